@@ -19,13 +19,20 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/api/middleware"
 	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/version"
+	"golang.org/x/time/rate"
 )
 
 // status of cdc server
@@ -37,15 +44,37 @@ type status struct {
 	IsOwner bool   `json:"is_owner"`
 }
 
+// sorterPositionRange is the position range of the events of a table buffered
+// in the sorter.
+type sorterPositionRange struct {
+	Namespace  string `json:"namespace"`
+	Changefeed string `json:"changefeed"`
+	TableID    int64  `json:"table_id"`
+	processor.SorterPositionRange
+}
+
 type statusAPI struct {
 	capture capture.Capture
+	// sorterLimiter limits the rate of scanning sorters, so that debugging
+	// does not impact the hot path.
+	sorterLimiter *rate.Limiter
+}
+
+// newSorterLimiter allows at most 1 request per second to scan sorters.
+func newSorterLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Every(time.Second), 1)
 }
 
 // RegisterStatusAPIRoutes registers routes for status.
 func RegisterStatusAPIRoutes(router *gin.Engine, capture capture.Capture) {
-	statusAPI := statusAPI{capture: capture}
+	statusAPI := statusAPI{
+		capture:       capture,
+		sorterLimiter: newSorterLimiter(),
+	}
 	router.GET("/status", gin.WrapF(statusAPI.handleStatus))
 	router.GET("/debug/info", middleware.AuthenticateMiddleware(capture), gin.WrapF(statusAPI.handleDebugInfo))
+	router.GET("/debug/sorter/position-range", middleware.AuthenticateMiddleware(capture),
+		gin.WrapF(statusAPI.handleSorterPositionRange))
 }
 
 func (h *statusAPI) writeEtcdInfo(ctx context.Context, cli etcd.CDCEtcdClient, w io.Writer) {
@@ -67,6 +96,52 @@ func (h *statusAPI) handleDebugInfo(w http.ResponseWriter, req *http.Request) {
 	h.writeEtcdInfo(ctx, h.capture.GetEtcdClient(), w)
 }
 
+// handleSorterPositionRange returns the position range of the events of a table
+// buffered in the sorter of a changefeed on this capture, e.g.
+// `/debug/sorter/position-range?namespace=default&changefeed=test&tableID=100`.
+func (h *statusAPI) handleSorterPositionRange(w http.ResponseWriter, req *http.Request) {
+	if !h.sorterLimiter.Allow() {
+		api.WriteError(w, http.StatusTooManyRequests,
+			fmt.Errorf("too many requests, at most 1 request per second is allowed"))
+		return
+	}
+
+	query := req.URL.Query()
+	namespace := query.Get("namespace")
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: query.Get("changefeed")}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		api.WriteError(w, http.StatusBadRequest,
+			cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed: %s", changefeedID.ID))
+		return
+	}
+	tableID, err := strconv.ParseInt(query.Get("tableID"), 10, 64)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest,
+			cerror.ErrAPIInvalidParam.GenWithStack("invalid tableID: %s", query.Get("tableID")))
+		return
+	}
+
+	positionRange, err := h.capture.GetSorterPositionRange(
+		req.Context(), changefeedID, spanz.TableIDToComparableSpan(tableID))
+	if err != nil {
+		if cerror.ErrChangeFeedNotExists.Equal(err) || cerror.ErrProcessorTableNotFound.Equal(err) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	api.WriteData(w, &sorterPositionRange{
+		Namespace:           changefeedID.Namespace,
+		Changefeed:          changefeedID.ID,
+		TableID:             tableID,
+		SorterPositionRange: positionRange,
+	})
+}
+
 func (h *statusAPI) handleStatus(w http.ResponseWriter, req *http.Request) {
 	st := status{
 		Version: version.ReleaseVersion,
@@ -0,0 +1,82 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSorterPositionRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cp := mock_capture.NewMockCapture(ctrl)
+	router := gin.New()
+	RegisterStatusAPIRoutes(router, cp)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/debug/sorter/position-range?"+query, nil)
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	expected := processor.SorterPositionRange{
+		Min:   sorter.Position{StartTs: 1, CommitTs: 2},
+		Max:   sorter.Position{StartTs: 3, CommitTs: 4},
+		Count: 10,
+	}
+	cp.EXPECT().GetSorterPositionRange(gomock.Any(),
+		model.DefaultChangeFeedID("test"), spanz.TableIDToComparableSpan(100)).
+		Return(expected, nil)
+	w := get("changefeed=test&tableID=100")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := &sorterPositionRange{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	require.Equal(t, "default", resp.Namespace)
+	require.Equal(t, int64(100), resp.TableID)
+	require.Equal(t, expected, resp.SorterPositionRange)
+
+	// At most 1 request is allowed per second.
+	w = get("changefeed=test&tableID=100")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	handler := &statusAPI{capture: cp}
+	serve := func(query string) int {
+		handler.sorterLimiter = newSorterLimiter()
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/debug/sorter/position-range?"+query, nil)
+		require.NoError(t, err)
+		handler.handleSorterPositionRange(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusBadRequest, serve("tableID=100"))
+	require.Equal(t, http.StatusBadRequest, serve("changefeed=test&tableID=abc"))
+
+	cp.EXPECT().GetSorterPositionRange(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(processor.SorterPositionRange{}, cerror.ErrProcessorTableNotFound.GenWithStackByArgs())
+	require.Equal(t, http.StatusNotFound, serve("namespace=ns&changefeed=test&tableID=1"))
+}
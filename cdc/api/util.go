@@ -39,6 +39,7 @@ var httpBadRequestError = []*errors.Error{
 	cerror.ErrChangeFeedNotExists, cerror.ErrTargetTsBeforeStartTs, cerror.ErrTableIneligible,
 	cerror.ErrFilterRuleInvalid, cerror.ErrChangefeedUpdateRefused, cerror.ErrMySQLConnectionError,
 	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist, cerror.ErrSchedulerRequestFailed,
+	cerror.ErrQuotaExceeded,
 }
 
 const (
@@ -63,6 +64,16 @@ const (
 	APIOpVarTiCDCUser = "user"
 	// APIOpVarTiCDCPassword is the key of ticdc password in HTTP API.
 	APIOpVarTiCDCPassword = "password"
+	// APIOpVarTableName is the key of full table name in HTTP API.
+	APIOpVarTableName = "table"
+	// APIOpVarStartTime is the key of the start of a time range in HTTP API.
+	APIOpVarStartTime = "start_time"
+	// APIOpVarEndTime is the key of the end of a time range in HTTP API.
+	APIOpVarEndTime = "end_time"
+	// APIOpVarWatch is the key of whether to wait for the changes in HTTP API.
+	APIOpVarWatch = "watch"
+	// APIOpVarTimeout is the key of the max time to wait in HTTP API.
+	APIOpVarTimeout = "timeout"
 
 	// forwardFromCapture is a header to be set when forwarding requests to owner
 	forwardFromCapture = "TiCDC-ForwardFromCapture"
@@ -14,11 +14,15 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/log"
@@ -36,6 +40,14 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// defaultWatchTimeout is the default max time to wait for the status
+	// changes of a changefeed.
+	defaultWatchTimeout = 30 * time.Second
+	// maxWatchTimeout must be shorter than the write timeout of the server.
+	maxWatchTimeout = 5 * time.Minute
+)
+
 // OpenAPI provides capture APIs.
 type OpenAPI struct {
 	capture capture.Capture
@@ -82,6 +94,7 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api OpenAPI) {
 	changefeedGroup := v1.Group("/changefeeds")
 	changefeedGroup.GET("", controllerMiddleware, api.ListChangefeed)
 	changefeedGroup.GET("/:changefeed_id", changefeedOwnerMiddleware, api.GetChangefeed)
+	changefeedGroup.GET("/:changefeed_id/status", changefeedOwnerMiddleware, api.GetChangefeedStatus)
 	changefeedGroup.POST("", controllerMiddleware, authenticateMiddleware, api.CreateChangefeed)
 	changefeedGroup.PUT("/:changefeed_id", changefeedOwnerMiddleware, authenticateMiddleware, api.UpdateChangefeed)
 	changefeedGroup.POST("/:changefeed_id/pause", changefeedOwnerMiddleware, authenticateMiddleware, api.PauseChangefeed)
@@ -258,6 +271,129 @@ func (h *OpenAPI) GetChangefeed(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, changefeedDetail)
 }
 
+// GetChangefeedStatus gets the status of a changefeed
+// @Summary Get changefeed status
+// @Description get the state, the error and the checkpoint of a changefeed,
+// @Description if watch is true, the request is blocked until the state or
+// @Description the error of the changefeed changes or the timeout elapses
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param watch query bool false "wait for the status to change"
+// @Param timeout query string false "max time to wait, 30s by default"
+// @Success 200 {object} model.ChangefeedCommonInfo
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/status [get]
+func (h *OpenAPI) GetChangefeedStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(api.APIOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	watch, timeout, err := getWatchParams(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var changed <-chan struct{}
+	if watch {
+		// Watch before getting the status, so that no change is missed.
+		changed, err = h.statusProvider().WatchChangeFeedStatus(ctx, changefeedID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	status, err := h.getChangefeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !watch {
+		c.IndentedJSON(http.StatusOK, status)
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = c.Error(ctx.Err())
+			return
+		case <-timer.C:
+			c.IndentedJSON(http.StatusOK, status)
+			return
+		case <-changed:
+		}
+		changed, err = h.statusProvider().WatchChangeFeedStatus(ctx, changefeedID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		latest, err := h.getChangefeedStatus(ctx, changefeedID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		if latest.FeedState != status.FeedState ||
+			!reflect.DeepEqual(latest.RunningError, status.RunningError) {
+			c.IndentedJSON(http.StatusOK, latest)
+			return
+		}
+		status = latest
+	}
+}
+
+// getWatchParams returns whether to wait for the status changes and the max
+// time to wait.
+func getWatchParams(c *gin.Context) (bool, time.Duration, error) {
+	watch := false
+	if s := c.Query(api.APIOpVarWatch); s != "" {
+		var err error
+		watch, err = strconv.ParseBool(s)
+		if err != nil {
+			return false, 0, cerror.ErrAPIInvalidParam.GenWithStack("invalid watch: %s", s)
+		}
+	}
+	timeout := defaultWatchTimeout
+	if s := c.Query(api.APIOpVarTimeout); s != "" {
+		var err error
+		timeout, err = time.ParseDuration(s)
+		if err != nil || timeout <= 0 || timeout > maxWatchTimeout {
+			return false, 0, cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid timeout: %s, it must be positive and no more than %s", s, maxWatchTimeout)
+		}
+	}
+	return watch, timeout, nil
+}
+
+func (h *OpenAPI) getChangefeedStatus(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+) (*model.ChangefeedCommonInfo, error) {
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ChangefeedCommonInfo{
+		UpstreamID:     info.UpstreamID,
+		Namespace:      changefeedID.Namespace,
+		ID:             changefeedID.ID,
+		FeedState:      info.State,
+		RunningError:   info.Error,
+		CheckpointTSO:  status.CheckpointTs,
+		CheckpointTime: model.JSONTime(oracle.GetTimeFromTS(status.CheckpointTs)),
+	}, nil
+}
+
 // CreateChangefeed creates a changefeed
 // @Summary Create changefeed
 // @Description create a new changefeed
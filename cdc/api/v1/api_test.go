@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -107,6 +108,20 @@ func (p *mockStatusProvider) GetChangeFeedSyncedStatus(ctx context.Context,
 	return args.Get(0).(*model.ChangeFeedSyncedStatusForAPI), args.Error(1)
 }
 
+func (p *mockStatusProvider) GetChangeFeedDDLHistory(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.DDLHistoryEntry, error) {
+	args := p.Called(ctx)
+	return args.Get(0).([]*model.DDLHistoryEntry), args.Error(1)
+}
+
+func (p *mockStatusProvider) WatchChangeFeedStatus(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) (<-chan struct{}, error) {
+	args := p.Called(ctx, changefeedID)
+	return args.Get(0).(<-chan struct{}), args.Error(1)
+}
+
 func (p *mockStatusProvider) IsHealthy(ctx context.Context) (bool, error) {
 	args := p.Called(ctx)
 	return args.Get(0).(bool), args.Error(1)
@@ -279,6 +294,74 @@ func TestGetChangefeed(t *testing.T) {
 	require.Contains(t, respErr.Error, "changefeed not exists")
 }
 
+func TestGetChangefeedStatus(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	capture := mock_capture.NewMockCapture(ctrl)
+	capture.EXPECT().IsReady().Return(true).AnyTimes()
+	capture.EXPECT().IsController().Return(true).AnyTimes()
+	statusProvider := &mockStatusProvider{}
+	capture.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	router := newRouter(capture, statusProvider)
+	statusProvider.On("IsChangefeedOwner", mock.Anything, mock.Anything).Return(true, nil)
+	statusProvider.On("GetChangeFeedStatus", mock.Anything, changeFeedID).
+		Return(&model.ChangeFeedStatusForAPI{CheckpointTs: 1}, nil)
+
+	getStatus := func(query string) (*httptest.ResponseRecorder, time.Duration) {
+		url := fmt.Sprintf("/api/v1/changefeeds/%s/status%s", changeFeedID.ID, query)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+		start := time.Now()
+		router.ServeHTTP(w, req)
+		return w, time.Since(start)
+	}
+	requireState := func(w *httptest.ResponseRecorder, state model.FeedState) {
+		require.Equal(t, 200, w.Code)
+		var resp model.ChangefeedCommonInfo
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, changeFeedID.ID, resp.ID)
+		require.Equal(t, state, resp.FeedState)
+		require.Equal(t, uint64(1), resp.CheckpointTSO)
+	}
+
+	// get the status without watching.
+	statusProvider.On("GetChangeFeedInfo", mock.Anything).
+		Return(&model.ChangeFeedInfo{State: model.StateNormal}, nil).Once()
+	w, _ := getStatus("")
+	requireState(w, model.StateNormal)
+
+	// return immediately when the status changes.
+	changed := make(chan struct{})
+	statusProvider.On("WatchChangeFeedStatus", mock.Anything, changeFeedID).
+		Return((<-chan struct{})(changed), nil).Once()
+	statusProvider.On("WatchChangeFeedStatus", mock.Anything, changeFeedID).
+		Return((<-chan struct{})(make(chan struct{})), nil).Once()
+	statusProvider.On("GetChangeFeedInfo", mock.Anything).
+		Return(&model.ChangeFeedInfo{State: model.StateNormal}, nil).Once()
+	statusProvider.On("GetChangeFeedInfo", mock.Anything).
+		Return(&model.ChangeFeedInfo{State: model.StateStopped}, nil).Once()
+	time.AfterFunc(100*time.Millisecond, func() { close(changed) })
+	w, elapsed := getStatus("?watch=true&timeout=10s")
+	requireState(w, model.StateStopped)
+	require.Less(t, elapsed, 10*time.Second)
+
+	// return the current status when the timeout elapses.
+	statusProvider.On("WatchChangeFeedStatus", mock.Anything, changeFeedID).
+		Return((<-chan struct{})(make(chan struct{})), nil).Once()
+	statusProvider.On("GetChangeFeedInfo", mock.Anything).
+		Return(&model.ChangeFeedInfo{State: model.StateStopped}, nil).Once()
+	w, elapsed = getStatus("?watch=true&timeout=200ms")
+	requireState(w, model.StateStopped)
+	require.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+
+	// invalid parameters.
+	for _, query := range []string{"?watch=yes", "?watch=true&timeout=1h", "?watch=true&timeout=-1s"} {
+		w, _ = getStatus(query)
+		require.Equal(t, 400, w.Code, query)
+	}
+	statusProvider.AssertExpectations(t)
+}
+
 func TestPauseChangefeed(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -25,16 +25,18 @@ import (
 type OpenAPIV2 struct {
 	capture capture.Capture
 	helpers APIV2Helpers
+
+	tableStatsCache *tableStatsCache
 }
 
 // NewOpenAPIV2 creates a new OpenAPIV2.
 func NewOpenAPIV2(c capture.Capture) OpenAPIV2 {
-	return OpenAPIV2{c, APIV2HelpersImpl{}}
+	return OpenAPIV2{c, APIV2HelpersImpl{}, newTableStatsCache()}
 }
 
 // NewOpenAPIV2ForTest creates a new OpenAPIV2.
 func NewOpenAPIV2ForTest(c capture.Capture, h APIV2Helpers) OpenAPIV2 {
-	return OpenAPIV2{c, h}
+	return OpenAPIV2{c, h, newTableStatsCache()}
 }
 
 // RegisterOpenAPIV2Routes registers routes for OpenAPI
@@ -64,8 +66,10 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	changefeedGroup.GET("/:changefeed_id/meta_info", changefeedOwnerMiddleware, api.getChangeFeedMetaInfo)
 	changefeedGroup.POST("/:changefeed_id/resume", changefeedOwnerMiddleware, authenticateMiddleware, api.resumeChangefeed)
 	changefeedGroup.POST("/:changefeed_id/pause", changefeedOwnerMiddleware, authenticateMiddleware, api.pauseChangefeed)
+	changefeedGroup.POST("/:changefeed_id/reset", changefeedOwnerMiddleware, authenticateMiddleware, api.resetChangefeed)
 	changefeedGroup.GET("/:changefeed_id/status", changefeedOwnerMiddleware, api.status)
 	changefeedGroup.GET("/:changefeed_id/synced", changefeedOwnerMiddleware, api.synced)
+	changefeedGroup.GET("/:changefeed_id/ddl_history", changefeedOwnerMiddleware, api.ddlHistory)
 
 	// capture apis
 	captureGroup := v2.Group("/captures")
@@ -77,6 +81,7 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	processorGroup := v2.Group("/processors")
 	processorGroup.GET("/:changefeed_id/:capture_id", changefeedOwnerMiddleware, api.getProcessor)
 	processorGroup.GET("", controllerMiddleware, api.listProcessors)
+	processorGroup.GET("/:changefeed_id/:capture_id/tables/stats", api.getTableStats)
 
 	verifyTableGroup := v2.Group("/verify_table")
 	verifyTableGroup.POST("", api.verifyTable)
@@ -217,7 +217,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
-	tableInfos, ineligibleTables, _, err := entry.VerifyTables(f, kvStorage, cfg.StartTs)
+	tableInfos, ineligibleTables, eligibleTables, err := entry.VerifyTables(f, kvStorage, cfg.StartTs)
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
@@ -225,6 +225,26 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
+	if cfg.ValidateDownstreamSchema && cfg.UpstreamDSN == "" {
+		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+			"upstream_dsn is required to validate the downstream schema")
+	}
+	if cfg.UpstreamDSN != "" {
+		downstreamURI := ""
+		if cfg.ValidateDownstreamSchema {
+			downstreamURI = cfg.SinkURI
+		}
+		err = validator.ValidateSchemas(ctx, f, cfg.UpstreamDSN, downstreamURI)
+		if err != nil {
+			return nil, err
+		}
+	}
+	quota := config.GetGlobalServerConfig().GetNamespaceQuota(cfg.Namespace)
+	if quota != nil && quota.MaxTablesPerChangefeed > 0 &&
+		len(eligibleTables) > quota.MaxTablesPerChangefeed {
+		return nil, cerror.ErrQuotaExceeded.GenWithStackByArgs(
+			cfg.Namespace, "max-tables-per-changefeed", quota.MaxTablesPerChangefeed)
+	}
 	if !replicaCfg.ForceReplicate && !cfg.ReplicaConfig.IgnoreIneligibleTable {
 		if err != nil {
 			return nil, err
@@ -567,6 +567,9 @@ func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
 	}
 	detail := toAPIModel(cfInfo, status.ResolvedTs,
 		status.CheckpointTs, taskStatus, true)
+	detail.ScanProgress = toAPIScanProgress(status.ScanProgress)
+	detail.ReadAmplification = toAPIReadAmplification(status.ReadAmplification)
+	detail.Queued = status.Queued
 	c.JSON(http.StatusOK, detail)
 }
 
@@ -804,6 +807,131 @@ func (h *OpenAPIV2) resumeChangefeed(c *gin.Context) {
 	c.JSON(http.StatusOK, &EmptyResponse{})
 }
 
+// resetChangefeed handles reset changefeed request.
+// ResetChangefeed resets the start-ts of a changefeed
+// @Summary Reset the start-ts of a changefeed
+// @Description Replay a changefeed from the given start-ts without recreating it.
+// @Description The changefeed is restarted from the start-ts with its
+// @Description checkpoint and table states cleared in a single owner job.
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "default"
+// @Param resetConfig body ResetChangefeedConfig true "reset config"
+// @Success 200 {object} ResetChangefeedResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router	/api/v2/changefeeds/{changefeed_id}/reset [post]
+func (h *OpenAPIV2) resetChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	cfg := new(ResetChangefeedConfig)
+	if err := c.BindJSON(&cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.StartTs == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("start_ts is required"))
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var pdClient pd.Client
+	// if PDAddrs is empty, use the default pdClient
+	if len(cfg.PDAddrs) == 0 {
+		up, err := getCaptureDefaultUpstream(h.capture)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		pdClient = up.PDClient
+	} else {
+		credential := cfg.PDConfig.toCredential()
+		timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		pdClient, err = h.helpers.getPDClient(timeoutCtx, cfg.PDAddrs, credential)
+		if err != nil {
+			_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+			return
+		}
+		defer pdClient.Close()
+	}
+
+	// The start-ts is verified before the changefeed is stopped, so that a
+	// running changefeed is left untouched if it can not be reset.
+	if err := h.helpers.verifyResumeChangefeedConfig(
+		ctx,
+		pdClient,
+		h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceResuming),
+		changefeedID,
+		cfg.StartTs); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	needRemoveGCSafePoint := false
+	defer func() {
+		if !needRemoveGCSafePoint {
+			return
+		}
+		err := gc.UndoEnsureChangefeedStartTsSafety(
+			ctx,
+			pdClient,
+			h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceResuming),
+			changefeedID,
+		)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}()
+
+	switch info.State {
+	case model.StateNormal, model.StateWarning, model.StatePending,
+		model.StateFailed, model.StateStopped, model.StateFinished:
+	default:
+		needRemoveGCSafePoint = true
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"can not reset changefeed in %s state", info.State))
+		return
+	}
+
+	// The reset job releases the changefeed, and then overwrites the start-ts,
+	// the checkpoint and the epoch in a single etcd transaction of the owner,
+	// so the changefeed is either reset or left untouched. The new epoch makes
+	// processors drop all the table states.
+	resetJob := model.AdminJob{
+		CfID:                  changefeedID,
+		Type:                  model.AdminReset,
+		OverwriteCheckpointTs: cfg.StartTs,
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, resetJob); err != nil {
+		needRemoveGCSafePoint = true
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &ResetChangefeedResponse{
+		PreviousCheckpointTs: status.CheckpointTs,
+		StartTs:              cfg.StartTs,
+	})
+}
+
 // pauseChangefeed handles pause changefeed request
 // PauseChangefeed pauses a changefeed
 // @Summary Pause a changefeed
@@ -889,12 +1017,28 @@ func (h *OpenAPIV2) status(c *gin.Context) {
 		}
 	}
 
+	var binlogPosition *BinlogPosition
+	if status.BinlogPosition != nil {
+		binlogPosition = &BinlogPosition{
+			CheckpointTs: status.BinlogPosition.CheckpointTs,
+			File:         status.BinlogPosition.File,
+			Pos:          status.BinlogPosition.Pos,
+			GTIDSet:      status.BinlogPosition.GTIDSet,
+		}
+	}
+
 	c.JSON(http.StatusOK, &ChangefeedStatus{
-		State:        string(info.State),
-		CheckpointTs: status.CheckpointTs,
-		ResolvedTs:   status.ResolvedTs,
-		LastError:    lastError,
-		LastWarning:  lastWarning,
+		State:          string(info.State),
+		CheckpointTs:   status.CheckpointTs,
+		ResolvedTs:     status.ResolvedTs,
+		LastError:      lastError,
+		LastWarning:    lastWarning,
+		GTIDExecuted:   status.GTIDExecuted,
+		BinlogPosition: binlogPosition,
+		ScanProgress:   toAPIScanProgress(status.ScanProgress),
+
+		ReadAmplification: toAPIReadAmplification(status.ReadAmplification),
+		Queued:            status.Queued,
 	})
 }
 
@@ -1034,6 +1178,95 @@ func (h *OpenAPIV2) synced(c *gin.Context) {
 	})
 }
 
+// ddlHistory get the recently executed DDLs of a changefeed
+// @Summary Get DDL history
+// @Description get the recently executed DDLs of a changefeed, the DDLs can be
+// @Description filtered by the affected table and the executed time range
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Param table query string false "full table name, e.g. test.t1"
+// @Param start_time query string false "RFC3339 time, e.g. 2024-01-01T00:00:00Z"
+// @Param end_time query string false "RFC3339 time, e.g. 2024-01-02T00:00:00Z"
+// @Success 200 {object} ListResponse[DDLHistoryEntry]
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/ddl_history [get]
+func (h *OpenAPIV2) ddlHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	table := c.Query(api.APIOpVarTableName)
+	startTime, err := parseTimeParam(c, api.APIOpVarStartTime)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	endTime, err := parseTimeParam(c, api.APIOpVarEndTime)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	history, err := h.capture.StatusProvider().GetChangeFeedDDLHistory(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	items := make([]DDLHistoryEntry, 0, len(history))
+	for _, entry := range history {
+		if !startTime.IsZero() && entry.ExecutedAt.Before(startTime) {
+			continue
+		}
+		if !endTime.IsZero() && entry.ExecutedAt.After(endTime) {
+			continue
+		}
+		if table != "" && !containsTable(entry.AffectedTables, table) {
+			continue
+		}
+		items = append(items, DDLHistoryEntry{
+			ExecutedAt:     entry.ExecutedAt,
+			CommitTs:       entry.CommitTs,
+			Query:          entry.Query,
+			AffectedTables: entry.AffectedTables,
+		})
+	}
+	c.JSON(http.StatusOK, &ListResponse[DDLHistoryEntry]{
+		Total: len(items),
+		Items: items,
+	})
+}
+
+func containsTable(tables []string, table string) bool {
+	for _, t := range tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeParam parses the RFC3339 time in the query parameter, it returns
+// a zero time if the parameter is not set.
+func parseTimeParam(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid %s: %s, it should be in RFC3339 format", key, value)
+	}
+	return t, nil
+}
+
 func toAPIModel(
 	info *model.ChangeFeedInfo,
 	resolvedTs uint64,
@@ -314,6 +314,16 @@ func TestGetChangeFeed(t *testing.T) {
 
 	// success
 	statusProvider.changefeedInfo = &model.ChangeFeedInfo{ID: validID}
+	statusProvider.changefeedStatus = &model.ChangeFeedStatusForAPI{
+		CheckpointTs: 1,
+		ScanProgress: &model.ScanProgress{
+			TotalRegions:    100,
+			ScannedRegions:  40,
+			CurrentScanRate: 2,
+		},
+		ReadAmplification: model.NewReadAmplification(300, 100),
+		Queued:            true,
+	}
 	w = httptest.NewRecorder()
 	req, _ = http.NewRequestWithContext(
 		context.Background(),
@@ -328,6 +338,17 @@ func TestGetChangeFeed(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, resp.ID, validID)
 	require.Nil(t, resp.Error)
+	require.Equal(t, &ScanProgress{
+		TotalRegions:    100,
+		ScannedRegions:  40,
+		CurrentScanRate: 2,
+	}, resp.ScanProgress)
+	require.Equal(t, &ReadAmplification{
+		ScannedBytes: 300,
+		WrittenBytes: 100,
+		Ratio:        3,
+	}, resp.ReadAmplification)
+	require.True(t, resp.Queued)
 }
 
 func TestUpdateChangefeed(t *testing.T) {
@@ -816,6 +837,103 @@ func TestResumeChangefeed(t *testing.T) {
 	require.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestResetChangefeed(t *testing.T) {
+	reset := testCase{url: "/api/v2/changefeeds/%s/reset?namespace=abc", method: "POST"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	pdClient := &mockPDClient{}
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	mockUpManager := upstream.NewManager4Test(pdClient)
+	statusProvider := &mockStatusProvider{
+		changefeedStatus: &model.ChangeFeedStatusForAPI{CheckpointTs: 200},
+	}
+
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().Return(mockUpManager, nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsController().Return(true).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	var jobs []model.AdminJob
+	owner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(adminJob model.AdminJob, done chan<- error) {
+			require.EqualValues(t, changeFeedID, adminJob.CfID)
+			jobs = append(jobs, adminJob)
+			close(done)
+		}).AnyTimes()
+
+	post := func(id string, cfg *ResetChangefeedConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), reset.method,
+			fmt.Sprintf(reset.url, id), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// case 1: invalid changefeed id
+	w := post("@^Invalid", &ResetChangefeedConfig{StartTs: 100})
+	respErr := model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: start_ts is not specified
+	validID := changeFeedID.ID
+	statusProvider.changefeedInfo = &model.ChangeFeedInfo{ID: validID, State: model.StateNormal}
+	w = post(validID, &ResetChangefeedConfig{})
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 3: failed to verify the start_ts, the changefeed is not stopped
+	helpers.EXPECT().
+		verifyResumeChangefeedConfig(gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), uint64(100)).Return(cerrors.ErrStartTsBeforeGC).Times(1)
+	w = post(validID, &ResetChangefeedConfig{StartTs: 100})
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrStartTsBeforeGC")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Empty(t, jobs)
+
+	// case 4: a running changefeed is reset from the start_ts in one job
+	helpers.EXPECT().
+		verifyResumeChangefeedConfig(gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), uint64(100)).Return(nil).Times(1)
+	w = post(validID, &ResetChangefeedConfig{StartTs: 100})
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := &ResetChangefeedResponse{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(resp))
+	require.Equal(t, &ResetChangefeedResponse{PreviousCheckpointTs: 200, StartTs: 100}, resp)
+	require.Len(t, jobs, 1)
+	require.Equal(t, model.AdminReset, jobs[0].Type)
+	require.Equal(t, uint64(100), jobs[0].OverwriteCheckpointTs)
+
+	// case 5: a stopped changefeed is reset from the start_ts
+	jobs = nil
+	statusProvider.changefeedInfo = &model.ChangeFeedInfo{ID: validID, State: model.StateStopped}
+	helpers.EXPECT().
+		verifyResumeChangefeedConfig(gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), uint64(150)).Return(nil).Times(1)
+	w = post(validID, &ResetChangefeedConfig{StartTs: 150})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, jobs, 1)
+	require.Equal(t, model.AdminReset, jobs[0].Type)
+	require.Equal(t, uint64(150), jobs[0].OverwriteCheckpointTs)
+}
+
 func TestDeleteChangefeed(t *testing.T) {
 	remove := testCase{url: "/api/v2/changefeeds/%s?namespace=abc", method: "DELETE"}
 	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
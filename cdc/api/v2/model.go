@@ -82,6 +82,18 @@ type ResumeChangefeedConfig struct {
 	OverwriteCheckpointTs uint64 `json:"overwrite_checkpoint_ts"`
 }
 
+// ResetChangefeedConfig is used by reset changefeed api
+type ResetChangefeedConfig struct {
+	PDConfig
+	StartTs uint64 `json:"start_ts"`
+}
+
+// ResetChangefeedResponse is the response of reset changefeed api
+type ResetChangefeedResponse struct {
+	PreviousCheckpointTs uint64 `json:"previous_checkpoint_ts"`
+	StartTs              uint64 `json:"start_ts"`
+}
+
 // PDConfig is a configuration used to connect to pd
 type PDConfig struct {
 	PDAddrs       []string `json:"pd_addrs,omitempty"`
@@ -111,6 +123,13 @@ type SyncedStatusConfig struct {
 	CheckpointInterval int64 `json:"checkpoint_interval"`
 }
 
+// SamplingModeConfig represents the event sampling config for a changefeed
+type SamplingModeConfig struct {
+	Enabled    bool    `json:"enabled"`
+	SampleRate float64 `json:"sample_rate"`
+	OutputFile string  `json:"output_file"`
+}
+
 // MarshalJSON marshal changefeed common info to json
 // we need to set feed state to normal if it is uninitialized and pending to warning
 // to hide the detail of uninitialized and pending state from user
@@ -140,6 +159,12 @@ type ChangefeedConfig struct {
 	TargetTs      uint64         `json:"target_ts"`
 	SinkURI       string         `json:"sink_uri"`
 	ReplicaConfig *ReplicaConfig `json:"replica_config"`
+	// UpstreamDSN is the DSN of the upstream TiDB, the tables matched by the
+	// filter rules are validated against it on creation if it's not empty.
+	UpstreamDSN string `json:"upstream_dsn,omitempty"`
+	// ValidateDownstreamSchema also validates the tables on the downstream,
+	// it requires UpstreamDSN and a MySQL compatible sink.
+	ValidateDownstreamSchema bool `json:"validate_downstream_schema,omitempty"`
 	PDConfig
 }
 
@@ -192,18 +217,24 @@ type ReplicaConfig struct {
 	EnableSyncPoint       *bool  `json:"enable_sync_point,omitempty"`
 	EnableTableMonitor    *bool  `json:"enable_table_monitor,omitempty"`
 	BDRMode               *bool  `json:"bdr_mode,omitempty"`
+	SchemaOnlyMode        *bool  `json:"schema_only_mode,omitempty"`
+
+	ValidateInitialConsistency   *bool    `json:"validate_initial_consistency,omitempty"`
+	InitialConsistencySampleRate *float64 `json:"initial_consistency_sample_rate,omitempty"`
 
 	SyncPointInterval  *JSONDuration `json:"sync_point_interval,omitempty" swaggertype:"string"`
 	SyncPointRetention *JSONDuration `json:"sync_point_retention,omitempty" swaggertype:"string"`
 
-	Filter                       *FilterConfig              `json:"filter"`
-	Mounter                      *MounterConfig             `json:"mounter"`
-	Sink                         *SinkConfig                `json:"sink"`
-	Consistent                   *ConsistentConfig          `json:"consistent,omitempty"`
-	Scheduler                    *ChangefeedSchedulerConfig `json:"scheduler"`
-	Integrity                    *IntegrityConfig           `json:"integrity"`
-	ChangefeedErrorStuckDuration *JSONDuration              `json:"changefeed_error_stuck_duration,omitempty"`
-	SyncedStatus                 *SyncedStatusConfig        `json:"synced_status,omitempty"`
+	Filter                        *FilterConfig              `json:"filter"`
+	Mounter                       *MounterConfig             `json:"mounter"`
+	Sink                          *SinkConfig                `json:"sink"`
+	Consistent                    *ConsistentConfig          `json:"consistent,omitempty"`
+	Scheduler                     *ChangefeedSchedulerConfig `json:"scheduler"`
+	Integrity                     *IntegrityConfig           `json:"integrity"`
+	ChangefeedErrorStuckDuration  *JSONDuration              `json:"changefeed_error_stuck_duration,omitempty"`
+	SyncedStatus                  *SyncedStatusConfig        `json:"synced_status,omitempty"`
+	SamplingMode                  *SamplingModeConfig        `json:"sampling_mode,omitempty"`
+	ResolvedTsBroadcastIntervalMs *uint64                    `json:"resolved_ts_broadcast_interval_ms,omitempty"`
 
 	// Deprecated: we don't use this field since v8.0.0.
 	SQLMode string `json:"sql_mode,omitempty"`
@@ -232,6 +263,9 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 		res.SyncPointRetention = &c.SyncPointRetention.duration
 	}
 	res.BDRMode = c.BDRMode
+	res.SchemaOnlyMode = c.SchemaOnlyMode
+	res.ValidateInitialConsistency = c.ValidateInitialConsistency
+	res.InitialConsistencySampleRate = c.InitialConsistencySampleRate
 
 	if c.Filter != nil {
 		var efs []*config.EventFilterRule
@@ -285,6 +319,22 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				Columns: selector.Columns,
 			})
 		}
+		var tableNameMappings []*config.TableNameMapping
+		for _, m := range c.Sink.TableNameMappings {
+			tableNameMappings = append(tableNameMappings, &config.TableNameMapping{
+				SourceSchema: m.SourceSchema,
+				SourceTable:  m.SourceTable,
+				TargetSchema: m.TargetSchema,
+				TargetTable:  m.TargetTable,
+			})
+		}
+		var icebergPartitionBy []*config.IcebergPartitionRule
+		for _, rule := range c.Sink.IcebergPartitionBy {
+			icebergPartitionBy = append(icebergPartitionBy, &config.IcebergPartitionRule{
+				Matcher: rule.Matcher,
+				Fields:  rule.Fields,
+			})
+		}
 		var csvConfig *config.CSVConfig
 		if c.Sink.CSVConfig != nil {
 			csvConfig = &config.CSVConfig{
@@ -340,6 +390,9 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 					AvroEnableWatermark:            oldConfig.AvroEnableWatermark,
 					AvroDecimalHandlingMode:        oldConfig.AvroDecimalHandlingMode,
 					AvroBigintUnsignedHandlingMode: oldConfig.AvroBigintUnsignedHandlingMode,
+					AvroEnumSetHandlingMode:        oldConfig.AvroEnumSetHandlingMode,
+					AvroSchemaNamespace:            oldConfig.AvroSchemaNamespace,
+					AvroSchemaCompatibility:        oldConfig.AvroSchemaCompatibility,
 					EncodingFormat:                 oldConfig.EncodingFormat,
 				}
 			}
@@ -351,6 +404,8 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 					LargeMessageHandleOption:      oldConfig.LargeMessageHandleOption,
 					LargeMessageHandleCompression: oldConfig.LargeMessageHandleCompression,
 					ClaimCheckStorageURI:          oldConfig.ClaimCheckStorageURI,
+					LargeValueExternalStorage:     oldConfig.LargeValueExternalStorage,
+					LargeValueThresholdBytes:      oldConfig.LargeValueThresholdBytes,
 				}
 			}
 
@@ -371,6 +426,7 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				KafkaVersion:                 c.Sink.KafkaConfig.KafkaVersion,
 				MaxMessageBytes:              c.Sink.KafkaConfig.MaxMessageBytes,
 				Compression:                  c.Sink.KafkaConfig.Compression,
+				CompressionLevel:             c.Sink.KafkaConfig.CompressionLevel,
 				KafkaClientID:                c.Sink.KafkaConfig.KafkaClientID,
 				AutoCreateTopic:              c.Sink.KafkaConfig.AutoCreateTopic,
 				DialTimeout:                  c.Sink.KafkaConfig.DialTimeout,
@@ -402,6 +458,7 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				CodecConfig:                  codeConfig,
 				LargeMessageHandle:           largeMessageHandle,
 				GlueSchemaRegistryConfig:     glueSchemaRegistryConfig,
+				KafkaStreamsChangelog:        c.Sink.KafkaConfig.KafkaStreamsChangelog,
 			}
 		}
 		var mysqlConfig *config.MySQLConfig
@@ -411,6 +468,7 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				MaxTxnRow:                    c.Sink.MySQLConfig.MaxTxnRow,
 				MaxMultiUpdateRowSize:        c.Sink.MySQLConfig.MaxMultiUpdateRowSize,
 				MaxMultiUpdateRowCount:       c.Sink.MySQLConfig.MaxMultiUpdateRowCount,
+				MaxBatchWaitMs:               c.Sink.MySQLConfig.MaxBatchWaitMs,
 				TiDBTxnMode:                  c.Sink.MySQLConfig.TiDBTxnMode,
 				SSLCa:                        c.Sink.MySQLConfig.SSLCa,
 				SSLCert:                      c.Sink.MySQLConfig.SSLCert,
@@ -422,6 +480,26 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				EnableBatchDML:               c.Sink.MySQLConfig.EnableBatchDML,
 				EnableMultiStatement:         c.Sink.MySQLConfig.EnableMultiStatement,
 				EnableCachePreparedStatement: c.Sink.MySQLConfig.EnableCachePreparedStatement,
+				SessionVariables:             c.Sink.MySQLConfig.SessionVariables,
+				VerifyOrdering:               c.Sink.MySQLConfig.VerifyOrdering,
+				DebugSQLPreview:              c.Sink.MySQLConfig.DebugSQLPreview,
+				SchemaCompatibilityCheck:     c.Sink.MySQLConfig.SchemaCompatibilityCheck,
+				TrackBinlogPosition:          c.Sink.MySQLConfig.TrackBinlogPosition,
+				PartitionDDLCompatibility:    c.Sink.MySQLConfig.PartitionDDLCompatibility,
+				ConnectionPoolPerTable:       c.Sink.MySQLConfig.ConnectionPoolPerTable,
+				DDLFallbackPolicy:            c.Sink.MySQLConfig.DDLFallbackPolicy,
+				MaxLockTimeoutRetries:        c.Sink.MySQLConfig.MaxLockTimeoutRetries,
+				DownstreamCharset:            c.Sink.MySQLConfig.DownstreamCharset,
+				DownstreamCollation:          c.Sink.MySQLConfig.DownstreamCollation,
+				AuditSideChannel:             c.Sink.MySQLConfig.AuditSideChannel,
+				AuditSideChannelCompression:  c.Sink.MySQLConfig.AuditSideChannelCompression,
+				PtOSCCompatibility:           c.Sink.MySQLConfig.PtOSCCompatibility,
+				PtOSCWaitTimeout:             c.Sink.MySQLConfig.PtOSCWaitTimeout,
+				BatchDDLMode:                 c.Sink.MySQLConfig.BatchDDLMode,
+				CreateTableIfNotExists:       c.Sink.MySQLConfig.CreateTableIfNotExists,
+				UpstreamTiDBDSN:              c.Sink.MySQLConfig.UpstreamTiDBDSN,
+				AutoIncrementConflictPolicy:  c.Sink.MySQLConfig.AutoIncrementConflictPolicy,
+				ConditionalDDL:               c.Sink.MySQLConfig.ConditionalDDL,
 			}
 		}
 		var cloudStorageConfig *config.CloudStorageConfig
@@ -437,12 +515,38 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			}
 		}
 
+		var pulsarProducerConfig *config.PulsarProducerConfig
+		if c.Sink.PulsarProducerConfig != nil {
+			pulsarProducerConfig = &config.PulsarProducerConfig{
+				CompressionType:           (*config.PulsarCompressionType)(c.Sink.PulsarProducerConfig.CompressionType),
+				BatchingEnabled:           c.Sink.PulsarProducerConfig.BatchingEnabled,
+				BatchingMaxMessages:       c.Sink.PulsarProducerConfig.BatchingMaxMessages,
+				BatchingMaxPublishDelayMs: c.Sink.PulsarProducerConfig.BatchingMaxPublishDelayMs,
+				MaxPendingMessages:        c.Sink.PulsarProducerConfig.MaxPendingMessages,
+			}
+		}
+		var elasticsearchConfig *config.ElasticsearchConfig
+		if c.Sink.ElasticsearchConfig != nil {
+			elasticsearchConfig = &config.ElasticsearchConfig{
+				IndexNameTemplate: c.Sink.ElasticsearchConfig.IndexNameTemplate,
+				BatchSize:         c.Sink.ElasticsearchConfig.BatchSize,
+				FlushInterval:     c.Sink.ElasticsearchConfig.FlushInterval,
+			}
+		}
+
 		res.Sink = &config.SinkConfig{
 			DispatchRules:                    dispatchRules,
 			Protocol:                         c.Sink.Protocol,
 			CSVConfig:                        csvConfig,
 			ColumnSelectors:                  columnSelectors,
 			SchemaRegistry:                   c.Sink.SchemaRegistry,
+			SchemaRegistryCA:                 c.Sink.SchemaRegistryCA,
+			SchemaRegistryCert:               c.Sink.SchemaRegistryCert,
+			SchemaRegistryKey:                c.Sink.SchemaRegistryKey,
+			SchemaRegistryUsername:           c.Sink.SchemaRegistryUsername,
+			SchemaRegistryPassword:           c.Sink.SchemaRegistryPassword,
+			SchemaRegistryAPIKey:             c.Sink.SchemaRegistryAPIKey,
+			SchemaRegistryType:               c.Sink.SchemaRegistryType,
 			EncoderConcurrency:               c.Sink.EncoderConcurrency,
 			Terminator:                       c.Sink.Terminator,
 			DateSeparator:                    c.Sink.DateSeparator,
@@ -452,10 +556,18 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			OnlyOutputUpdatedColumns:         c.Sink.OnlyOutputUpdatedColumns,
 			DeleteOnlyOutputHandleKeyColumns: c.Sink.DeleteOnlyOutputHandleKeyColumns,
 			ContentCompatible:                c.Sink.ContentCompatible,
+			JSONColumnAsObject:               c.Sink.JSONColumnAsObject,
+			SpatialEncoding:                  c.Sink.SpatialEncoding,
+			CharsetConversionMode:            c.Sink.CharsetConversionMode,
+			ExcludeInvisibleColumns:          c.Sink.ExcludeInvisibleColumns,
 			KafkaConfig:                      kafkaConfig,
 			MySQLConfig:                      mysqlConfig,
 			PulsarConfig:                     pulsarConfig,
 			CloudStorageConfig:               cloudStorageConfig,
+			PulsarProducerConfig:             pulsarProducerConfig,
+			ElasticsearchConfig:              elasticsearchConfig,
+			IcebergPartitionBy:               icebergPartitionBy,
+			TableNameMappings:                tableNameMappings,
 			SafeMode:                         c.Sink.SafeMode,
 		}
 
@@ -465,6 +577,15 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 		if c.Sink.AdvanceTimeoutInSec != nil {
 			res.Sink.AdvanceTimeoutInSec = util.AddressOf(*c.Sink.AdvanceTimeoutInSec)
 		}
+		if c.Sink.BackpressureThresholdMs != nil {
+			res.Sink.BackpressureThresholdMs = util.AddressOf(*c.Sink.BackpressureThresholdMs)
+		}
+		if c.Sink.HeartbeatIntervalMs != nil {
+			res.Sink.HeartbeatIntervalMs = util.AddressOf(*c.Sink.HeartbeatIntervalMs)
+		}
+		if c.Sink.SinkCreationTimeoutInSec != nil {
+			res.Sink.SinkCreationTimeoutInSec = util.AddressOf(*c.Sink.SinkCreationTimeoutInSec)
+		}
 		if c.Sink.DebeziumDisableSchema != nil {
 			res.Sink.DebeziumDisableSchema = util.AddressOf(*c.Sink.DebeziumDisableSchema)
 		}
@@ -508,6 +629,16 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			CheckpointInterval:  c.SyncedStatus.CheckpointInterval,
 		}
 	}
+	if c.SamplingMode != nil {
+		res.SamplingMode = &config.SamplingModeConfig{
+			Enabled:    c.SamplingMode.Enabled,
+			SampleRate: c.SamplingMode.SampleRate,
+			OutputFile: c.SamplingMode.OutputFile,
+		}
+	}
+	if c.ResolvedTsBroadcastIntervalMs != nil {
+		res.ResolvedTsBroadcastIntervalMs = util.AddressOf(*c.ResolvedTsBroadcastIntervalMs)
+	}
 	return res
 }
 
@@ -524,6 +655,10 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		EnableSyncPoint:       cloned.EnableSyncPoint,
 		EnableTableMonitor:    cloned.EnableTableMonitor,
 		BDRMode:               cloned.BDRMode,
+		SchemaOnlyMode:        cloned.SchemaOnlyMode,
+
+		ValidateInitialConsistency:   cloned.ValidateInitialConsistency,
+		InitialConsistencySampleRate: cloned.InitialConsistencySampleRate,
 	}
 
 	if cloned.SyncPointInterval != nil {
@@ -567,6 +702,22 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var tableNameMappings []*TableNameMapping
+		for _, m := range cloned.Sink.TableNameMappings {
+			tableNameMappings = append(tableNameMappings, &TableNameMapping{
+				SourceSchema: m.SourceSchema,
+				SourceTable:  m.SourceTable,
+				TargetSchema: m.TargetSchema,
+				TargetTable:  m.TargetTable,
+			})
+		}
+		var icebergPartitionBy []*IcebergPartitionRule
+		for _, rule := range cloned.Sink.IcebergPartitionBy {
+			icebergPartitionBy = append(icebergPartitionBy, &IcebergPartitionRule{
+				Matcher: rule.Matcher,
+				Fields:  rule.Fields,
+			})
+		}
 		var csvConfig *CSVConfig
 		if cloned.Sink.CSVConfig != nil {
 			csvConfig = &CSVConfig{
@@ -590,6 +741,9 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 					AvroEnableWatermark:            oldConfig.AvroEnableWatermark,
 					AvroDecimalHandlingMode:        oldConfig.AvroDecimalHandlingMode,
 					AvroBigintUnsignedHandlingMode: oldConfig.AvroBigintUnsignedHandlingMode,
+					AvroEnumSetHandlingMode:        oldConfig.AvroEnumSetHandlingMode,
+					AvroSchemaNamespace:            oldConfig.AvroSchemaNamespace,
+					AvroSchemaCompatibility:        oldConfig.AvroSchemaCompatibility,
 					EncodingFormat:                 oldConfig.EncodingFormat,
 				}
 			}
@@ -601,6 +755,8 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 					LargeMessageHandleOption:      oldConfig.LargeMessageHandleOption,
 					LargeMessageHandleCompression: oldConfig.LargeMessageHandleCompression,
 					ClaimCheckStorageURI:          oldConfig.ClaimCheckStorageURI,
+					LargeValueExternalStorage:     oldConfig.LargeValueExternalStorage,
+					LargeValueThresholdBytes:      oldConfig.LargeValueThresholdBytes,
 				}
 			}
 
@@ -621,6 +777,7 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				KafkaVersion:                 cloned.Sink.KafkaConfig.KafkaVersion,
 				MaxMessageBytes:              cloned.Sink.KafkaConfig.MaxMessageBytes,
 				Compression:                  cloned.Sink.KafkaConfig.Compression,
+				CompressionLevel:             cloned.Sink.KafkaConfig.CompressionLevel,
 				KafkaClientID:                cloned.Sink.KafkaConfig.KafkaClientID,
 				AutoCreateTopic:              cloned.Sink.KafkaConfig.AutoCreateTopic,
 				DialTimeout:                  cloned.Sink.KafkaConfig.DialTimeout,
@@ -652,6 +809,7 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				CodecConfig:                  codeConfig,
 				LargeMessageHandle:           largeMessageHandle,
 				GlueSchemaRegistryConfig:     glueSchemaRegistryConfig,
+				KafkaStreamsChangelog:        cloned.Sink.KafkaConfig.KafkaStreamsChangelog,
 			}
 		}
 		var mysqlConfig *MySQLConfig
@@ -661,6 +819,7 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				MaxTxnRow:                    cloned.Sink.MySQLConfig.MaxTxnRow,
 				MaxMultiUpdateRowSize:        cloned.Sink.MySQLConfig.MaxMultiUpdateRowSize,
 				MaxMultiUpdateRowCount:       cloned.Sink.MySQLConfig.MaxMultiUpdateRowCount,
+				MaxBatchWaitMs:               cloned.Sink.MySQLConfig.MaxBatchWaitMs,
 				TiDBTxnMode:                  cloned.Sink.MySQLConfig.TiDBTxnMode,
 				SSLCa:                        cloned.Sink.MySQLConfig.SSLCa,
 				SSLCert:                      cloned.Sink.MySQLConfig.SSLCert,
@@ -672,6 +831,26 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				EnableBatchDML:               cloned.Sink.MySQLConfig.EnableBatchDML,
 				EnableMultiStatement:         cloned.Sink.MySQLConfig.EnableMultiStatement,
 				EnableCachePreparedStatement: cloned.Sink.MySQLConfig.EnableCachePreparedStatement,
+				SessionVariables:             cloned.Sink.MySQLConfig.SessionVariables,
+				VerifyOrdering:               cloned.Sink.MySQLConfig.VerifyOrdering,
+				DebugSQLPreview:              cloned.Sink.MySQLConfig.DebugSQLPreview,
+				SchemaCompatibilityCheck:     cloned.Sink.MySQLConfig.SchemaCompatibilityCheck,
+				TrackBinlogPosition:          cloned.Sink.MySQLConfig.TrackBinlogPosition,
+				PartitionDDLCompatibility:    cloned.Sink.MySQLConfig.PartitionDDLCompatibility,
+				ConnectionPoolPerTable:       cloned.Sink.MySQLConfig.ConnectionPoolPerTable,
+				DDLFallbackPolicy:            cloned.Sink.MySQLConfig.DDLFallbackPolicy,
+				MaxLockTimeoutRetries:        cloned.Sink.MySQLConfig.MaxLockTimeoutRetries,
+				DownstreamCharset:            cloned.Sink.MySQLConfig.DownstreamCharset,
+				DownstreamCollation:          cloned.Sink.MySQLConfig.DownstreamCollation,
+				AuditSideChannel:             cloned.Sink.MySQLConfig.AuditSideChannel,
+				AuditSideChannelCompression:  cloned.Sink.MySQLConfig.AuditSideChannelCompression,
+				PtOSCCompatibility:           cloned.Sink.MySQLConfig.PtOSCCompatibility,
+				PtOSCWaitTimeout:             cloned.Sink.MySQLConfig.PtOSCWaitTimeout,
+				BatchDDLMode:                 cloned.Sink.MySQLConfig.BatchDDLMode,
+				CreateTableIfNotExists:       cloned.Sink.MySQLConfig.CreateTableIfNotExists,
+				UpstreamTiDBDSN:              cloned.Sink.MySQLConfig.UpstreamTiDBDSN,
+				AutoIncrementConflictPolicy:  cloned.Sink.MySQLConfig.AutoIncrementConflictPolicy,
+				ConditionalDDL:               cloned.Sink.MySQLConfig.ConditionalDDL,
 			}
 		}
 		var pulsarConfig *PulsarConfig
@@ -718,9 +897,35 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			}
 		}
 
+		var pulsarProducerConfig *PulsarProducerConfig
+		if cloned.Sink.PulsarProducerConfig != nil {
+			pulsarProducerConfig = &PulsarProducerConfig{
+				CompressionType:           (*string)(cloned.Sink.PulsarProducerConfig.CompressionType),
+				BatchingEnabled:           cloned.Sink.PulsarProducerConfig.BatchingEnabled,
+				BatchingMaxMessages:       cloned.Sink.PulsarProducerConfig.BatchingMaxMessages,
+				BatchingMaxPublishDelayMs: cloned.Sink.PulsarProducerConfig.BatchingMaxPublishDelayMs,
+				MaxPendingMessages:        cloned.Sink.PulsarProducerConfig.MaxPendingMessages,
+			}
+		}
+		var elasticsearchConfig *ElasticsearchConfig
+		if cloned.Sink.ElasticsearchConfig != nil {
+			elasticsearchConfig = &ElasticsearchConfig{
+				IndexNameTemplate: cloned.Sink.ElasticsearchConfig.IndexNameTemplate,
+				BatchSize:         cloned.Sink.ElasticsearchConfig.BatchSize,
+				FlushInterval:     cloned.Sink.ElasticsearchConfig.FlushInterval,
+			}
+		}
+
 		res.Sink = &SinkConfig{
 			Protocol:                         cloned.Sink.Protocol,
 			SchemaRegistry:                   cloned.Sink.SchemaRegistry,
+			SchemaRegistryCA:                 cloned.Sink.SchemaRegistryCA,
+			SchemaRegistryCert:               cloned.Sink.SchemaRegistryCert,
+			SchemaRegistryKey:                cloned.Sink.SchemaRegistryKey,
+			SchemaRegistryUsername:           cloned.Sink.SchemaRegistryUsername,
+			SchemaRegistryPassword:           cloned.Sink.SchemaRegistryPassword,
+			SchemaRegistryAPIKey:             cloned.Sink.SchemaRegistryAPIKey,
+			SchemaRegistryType:               cloned.Sink.SchemaRegistryType,
 			DispatchRules:                    dispatchRules,
 			CSVConfig:                        csvConfig,
 			ColumnSelectors:                  columnSelectors,
@@ -733,10 +938,18 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			OnlyOutputUpdatedColumns:         cloned.Sink.OnlyOutputUpdatedColumns,
 			DeleteOnlyOutputHandleKeyColumns: cloned.Sink.DeleteOnlyOutputHandleKeyColumns,
 			ContentCompatible:                cloned.Sink.ContentCompatible,
+			JSONColumnAsObject:               cloned.Sink.JSONColumnAsObject,
+			SpatialEncoding:                  cloned.Sink.SpatialEncoding,
+			CharsetConversionMode:            cloned.Sink.CharsetConversionMode,
+			ExcludeInvisibleColumns:          cloned.Sink.ExcludeInvisibleColumns,
 			KafkaConfig:                      kafkaConfig,
 			MySQLConfig:                      mysqlConfig,
 			PulsarConfig:                     pulsarConfig,
 			CloudStorageConfig:               cloudStorageConfig,
+			PulsarProducerConfig:             pulsarProducerConfig,
+			ElasticsearchConfig:              elasticsearchConfig,
+			IcebergPartitionBy:               icebergPartitionBy,
+			TableNameMappings:                tableNameMappings,
 			SafeMode:                         cloned.Sink.SafeMode,
 		}
 
@@ -746,6 +959,15 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		if cloned.Sink.AdvanceTimeoutInSec != nil {
 			res.Sink.AdvanceTimeoutInSec = util.AddressOf(*cloned.Sink.AdvanceTimeoutInSec)
 		}
+		if cloned.Sink.BackpressureThresholdMs != nil {
+			res.Sink.BackpressureThresholdMs = util.AddressOf(*cloned.Sink.BackpressureThresholdMs)
+		}
+		if cloned.Sink.HeartbeatIntervalMs != nil {
+			res.Sink.HeartbeatIntervalMs = util.AddressOf(*cloned.Sink.HeartbeatIntervalMs)
+		}
+		if cloned.Sink.SinkCreationTimeoutInSec != nil {
+			res.Sink.SinkCreationTimeoutInSec = util.AddressOf(*cloned.Sink.SinkCreationTimeoutInSec)
+		}
 
 		if cloned.Sink.SendBootstrapIntervalInSec != nil {
 			res.Sink.SendBootstrapIntervalInSec = util.AddressOf(*cloned.Sink.SendBootstrapIntervalInSec)
@@ -811,6 +1033,16 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			CheckpointInterval:  cloned.SyncedStatus.CheckpointInterval,
 		}
 	}
+	if cloned.SamplingMode != nil {
+		res.SamplingMode = &SamplingModeConfig{
+			Enabled:    cloned.SamplingMode.Enabled,
+			SampleRate: cloned.SamplingMode.SampleRate,
+			OutputFile: cloned.SamplingMode.OutputFile,
+		}
+	}
+	if cloned.ResolvedTsBroadcastIntervalMs != nil {
+		res.ResolvedTsBroadcastIntervalMs = util.AddressOf(*cloned.ResolvedTsBroadcastIntervalMs)
+	}
 	return res
 }
 
@@ -902,6 +1134,13 @@ type Table struct {
 type SinkConfig struct {
 	Protocol                         *string             `json:"protocol,omitempty"`
 	SchemaRegistry                   *string             `json:"schema_registry,omitempty"`
+	SchemaRegistryCA                 *string             `json:"schema_registry_ca,omitempty"`
+	SchemaRegistryCert               *string             `json:"schema_registry_cert,omitempty"`
+	SchemaRegistryKey                *string             `json:"schema_registry_key,omitempty"`
+	SchemaRegistryUsername           *string             `json:"schema_registry_username,omitempty"`
+	SchemaRegistryPassword           *string             `json:"schema_registry_password,omitempty"`
+	SchemaRegistryAPIKey             *string             `json:"schema_registry_api_key,omitempty"`
+	SchemaRegistryType               *string             `json:"schema_registry_type,omitempty"`
 	CSVConfig                        *CSVConfig          `json:"csv,omitempty"`
 	DispatchRules                    []*DispatchRule     `json:"dispatchers,omitempty"`
 	ColumnSelectors                  []*ColumnSelector   `json:"column_selectors,omitempty"`
@@ -915,16 +1154,29 @@ type SinkConfig struct {
 	OnlyOutputUpdatedColumns         *bool               `json:"only_output_updated_columns,omitempty"`
 	DeleteOnlyOutputHandleKeyColumns *bool               `json:"delete_only_output_handle_key_columns"`
 	ContentCompatible                *bool               `json:"content_compatible"`
+	JSONColumnAsObject               *bool               `json:"json_column_as_object,omitempty"`
+	SpatialEncoding                  *string             `json:"spatial_encoding,omitempty"`
+	CharsetConversionMode            *string             `json:"charset_conversion_mode,omitempty"`
+	ExcludeInvisibleColumns          *bool               `json:"exclude_invisible_columns,omitempty"`
 	SafeMode                         *bool               `json:"safe_mode,omitempty"`
 	KafkaConfig                      *KafkaConfig        `json:"kafka_config,omitempty"`
 	PulsarConfig                     *PulsarConfig       `json:"pulsar_config,omitempty"`
 	MySQLConfig                      *MySQLConfig        `json:"mysql_config,omitempty"`
 	CloudStorageConfig               *CloudStorageConfig `json:"cloud_storage_config,omitempty"`
 	AdvanceTimeoutInSec              *uint               `json:"advance_timeout,omitempty"`
+	BackpressureThresholdMs          *uint64             `json:"backpressure_threshold_ms,omitempty"`
+	HeartbeatIntervalMs              *uint64             `json:"heartbeat_interval_ms,omitempty"`
+	SinkCreationTimeoutInSec         *uint               `json:"sink_creation_timeout,omitempty"`
 	SendBootstrapIntervalInSec       *int64              `json:"send_bootstrap_interval_in_sec,omitempty"`
 	SendBootstrapInMsgCount          *int32              `json:"send_bootstrap_in_msg_count,omitempty"`
 	SendBootstrapToAllPartition      *bool               `json:"send_bootstrap_to_all_partition,omitempty"`
 	DebeziumDisableSchema            *bool               `json:"debezium_disable_schema,omitempty"`
+
+	PulsarProducerConfig *PulsarProducerConfig `json:"pulsar_producer_config,omitempty"`
+	ElasticsearchConfig  *ElasticsearchConfig  `json:"elasticsearch_config,omitempty"`
+
+	IcebergPartitionBy []*IcebergPartitionRule `json:"iceberg_partition_by,omitempty"`
+	TableNameMappings  []*TableNameMapping     `json:"table_name_mappings,omitempty"`
 }
 
 // CSVConfig denotes the csv config
@@ -945,6 +1197,8 @@ type LargeMessageHandleConfig struct {
 	LargeMessageHandleOption      string `json:"large_message_handle_option"`
 	LargeMessageHandleCompression string `json:"large_message_handle_compression"`
 	ClaimCheckStorageURI          string `json:"claim_check_storage_uri"`
+	LargeValueExternalStorage     string `json:"large_value_external_storage"`
+	LargeValueThresholdBytes      int    `json:"large_value_threshold_bytes"`
 }
 
 // DispatchRule represents partition rule for a table
@@ -964,6 +1218,22 @@ type ColumnSelector struct {
 	Columns []string `json:"columns,omitempty"`
 }
 
+// TableNameMapping maps a table of the upstream to a table of the downstream.
+// This is a duplicate of config.TableNameMapping
+type TableNameMapping struct {
+	SourceSchema string `json:"source_schema"`
+	SourceTable  string `json:"source_table"`
+	TargetSchema string `json:"target_schema"`
+	TargetTable  string `json:"target_table"`
+}
+
+// IcebergPartitionRule represents the Iceberg partition spec of tables.
+// This is a duplicate of config.IcebergPartitionRule
+type IcebergPartitionRule struct {
+	Matcher []string `json:"matcher,omitempty"`
+	Fields  []string `json:"fields,omitempty"`
+}
+
 // ConsistentConfig represents replication consistency config for a changefeed
 // This is a duplicate of config.ConsistentConfig
 type ConsistentConfig struct {
@@ -1040,6 +1310,11 @@ type ChangeFeedInfo struct {
 	CheckpointTs   uint64                    `json:"checkpoint_ts"`
 	CheckpointTime model.JSONTime            `json:"checkpoint_time"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+	ScanProgress   *ScanProgress             `json:"scan_progress,omitempty"`
+
+	ReadAmplification *ReadAmplification `json:"read_amplification,omitempty"`
+	// Queued is true if the changefeed is waiting for the owner to run it.
+	Queued bool `json:"queued,omitempty"`
 }
 
 // SyncedStatus describes the detail of a changefeed's synced status
@@ -1112,6 +1387,36 @@ type ProcessorDetail struct {
 	Tables []int64 `json:"table_ids"`
 }
 
+// GetTableStatsRequest is the request of getting the replication statistics
+// of the tables replicated by a processor.
+type GetTableStatsRequest struct {
+	// Page is the page number starting from 1.
+	Page int `form:"page" json:"page"`
+	// PageSize is the number of tables in a page, it's at most 1000.
+	PageSize int `form:"page_size" json:"page_size"`
+}
+
+// GetTableStatsResponse is the response of getting the replication statistics
+// of the tables replicated by a processor.
+type GetTableStatsResponse struct {
+	Total    int          `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Items    []TableStats `json:"items"`
+}
+
+// TableStats holds the replication statistics of a table
+type TableStats struct {
+	TableID      int64  `json:"table_id"`
+	RowsInserted uint64 `json:"rows_inserted"`
+	RowsUpdated  uint64 `json:"rows_updated"`
+	RowsDeleted  uint64 `json:"rows_deleted"`
+	BytesWritten uint64 `json:"bytes_written"`
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+	LagMs        int64  `json:"lag_ms"`
+}
+
 // Liveness is the liveness status of a capture.
 // Liveness can only be changed from alive to stopping, and no way back.
 type Liveness int32
@@ -1125,6 +1430,9 @@ type ServerStatus struct {
 	Pid       int      `json:"pid"`
 	IsOwner   bool     `json:"is_owner"`
 	Liveness  Liveness `json:"liveness"`
+	// Goroutines and HeapInUse are the runtime statistics of the server.
+	Goroutines int    `json:"goroutines,omitempty"`
+	HeapInUse  uint64 `json:"heap_inuse,omitempty"`
 }
 
 // Capture holds common information of a capture in cdc
@@ -1142,6 +1450,9 @@ type CodecConfig struct {
 	AvroEnableWatermark            *bool   `json:"avro_enable_watermark,omitempty"`
 	AvroDecimalHandlingMode        *string `json:"avro_decimal_handling_mode,omitempty"`
 	AvroBigintUnsignedHandlingMode *string `json:"avro_bigint_unsigned_handling_mode,omitempty"`
+	AvroEnumSetHandlingMode        *string `json:"avro_enum_set_handling_mode,omitempty"`
+	AvroSchemaNamespace            *string `json:"avro_schema_namespace,omitempty"`
+	AvroSchemaCompatibility        *string `json:"avro_schema_compatibility,omitempty"`
 	EncodingFormat                 *string `json:"encoding_format,omitempty"`
 }
 
@@ -1167,6 +1478,24 @@ type PulsarConfig struct {
 	OAuth2                  *PulsarOAuth2 `json:"oauth2,omitempty"`
 }
 
+// PulsarProducerConfig represents the configuration of pulsar producers.
+// This is a duplicate of config.PulsarProducerConfig
+type PulsarProducerConfig struct {
+	CompressionType           *string `json:"compression_type,omitempty"`
+	BatchingEnabled           *bool   `json:"batching_enabled,omitempty"`
+	BatchingMaxMessages       *int    `json:"batching_max_messages,omitempty"`
+	BatchingMaxPublishDelayMs *int    `json:"batching_max_publish_delay_ms,omitempty"`
+	MaxPendingMessages        *int    `json:"max_pending_messages,omitempty"`
+}
+
+// ElasticsearchConfig represents an Elasticsearch sink configuration
+// This is a duplicate of config.ElasticsearchConfig
+type ElasticsearchConfig struct {
+	IndexNameTemplate *string `json:"index_name_template,omitempty"`
+	BatchSize         *int    `json:"batch_size,omitempty"`
+	FlushInterval     *string `json:"flush_interval,omitempty"`
+}
+
 // PulsarOAuth2 is the configuration for OAuth2
 type PulsarOAuth2 struct {
 	OAuth2IssuerURL  string `json:"oauth2-issuer-url,omitempty"`
@@ -1183,6 +1512,7 @@ type KafkaConfig struct {
 	KafkaVersion                 *string                   `json:"kafka_version,omitempty"`
 	MaxMessageBytes              *int                      `json:"max_message_bytes,omitempty"`
 	Compression                  *string                   `json:"compression,omitempty"`
+	CompressionLevel             *int                      `json:"compression_level,omitempty"`
 	KafkaClientID                *string                   `json:"kafka_client_id,omitempty"`
 	AutoCreateTopic              *bool                     `json:"auto_create_topic,omitempty"`
 	DialTimeout                  *string                   `json:"dial_timeout,omitempty"`
@@ -1214,25 +1544,47 @@ type KafkaConfig struct {
 	CodecConfig                  *CodecConfig              `json:"codec_config,omitempty"`
 	LargeMessageHandle           *LargeMessageHandleConfig `json:"large_message_handle,omitempty"`
 	GlueSchemaRegistryConfig     *GlueSchemaRegistryConfig `json:"glue_schema_registry_config,omitempty"`
+	KafkaStreamsChangelog        *bool                     `json:"kafka_streams_changelog,omitempty"`
 }
 
 // MySQLConfig represents a MySQL sink configuration
 type MySQLConfig struct {
-	WorkerCount                  *int    `json:"worker_count,omitempty"`
-	MaxTxnRow                    *int    `json:"max_txn_row,omitempty"`
-	MaxMultiUpdateRowSize        *int    `json:"max_multi_update_row_size,omitempty"`
-	MaxMultiUpdateRowCount       *int    `json:"max_multi_update_row_count,omitempty"`
-	TiDBTxnMode                  *string `json:"tidb_txn_mode,omitempty"`
-	SSLCa                        *string `json:"ssl_ca,omitempty"`
-	SSLCert                      *string `json:"ssl_cert,omitempty"`
-	SSLKey                       *string `json:"ssl_key,omitempty"`
-	TimeZone                     *string `json:"time_zone,omitempty"`
-	WriteTimeout                 *string `json:"write_timeout,omitempty"`
-	ReadTimeout                  *string `json:"read_timeout,omitempty"`
-	Timeout                      *string `json:"timeout,omitempty"`
-	EnableBatchDML               *bool   `json:"enable_batch_dml,omitempty"`
-	EnableMultiStatement         *bool   `json:"enable_multi_statement,omitempty"`
-	EnableCachePreparedStatement *bool   `json:"enable_cache_prepared_statement,omitempty"`
+	WorkerCount                  *int              `json:"worker_count,omitempty"`
+	MaxTxnRow                    *int              `json:"max_txn_row,omitempty"`
+	MaxMultiUpdateRowSize        *int              `json:"max_multi_update_row_size,omitempty"`
+	MaxMultiUpdateRowCount       *int              `json:"max_multi_update_row_count,omitempty"`
+	MaxBatchWaitMs               *int              `json:"max_batch_wait_ms,omitempty"`
+	TiDBTxnMode                  *string           `json:"tidb_txn_mode,omitempty"`
+	SSLCa                        *string           `json:"ssl_ca,omitempty"`
+	SSLCert                      *string           `json:"ssl_cert,omitempty"`
+	SSLKey                       *string           `json:"ssl_key,omitempty"`
+	TimeZone                     *string           `json:"time_zone,omitempty"`
+	WriteTimeout                 *string           `json:"write_timeout,omitempty"`
+	ReadTimeout                  *string           `json:"read_timeout,omitempty"`
+	Timeout                      *string           `json:"timeout,omitempty"`
+	EnableBatchDML               *bool             `json:"enable_batch_dml,omitempty"`
+	EnableMultiStatement         *bool             `json:"enable_multi_statement,omitempty"`
+	EnableCachePreparedStatement *bool             `json:"enable_cache_prepared_statement,omitempty"`
+	SessionVariables             map[string]string `json:"session_variables,omitempty"`
+	VerifyOrdering               *bool             `json:"verify_ordering,omitempty"`
+	DebugSQLPreview              *bool             `json:"debug_sql_preview,omitempty"`
+	SchemaCompatibilityCheck     *bool             `json:"schema_compatibility_check,omitempty"`
+	TrackBinlogPosition          *bool             `json:"track_binlog_position,omitempty"`
+	PartitionDDLCompatibility    *bool             `json:"partition_ddl_compatibility,omitempty"`
+	ConnectionPoolPerTable       *bool             `json:"connection_pool_per_table,omitempty"`
+	DDLFallbackPolicy            *string           `json:"ddl_fallback_policy,omitempty"`
+	MaxLockTimeoutRetries        *int              `json:"max_lock_timeout_retries,omitempty"`
+	DownstreamCharset            *string           `json:"downstream_charset,omitempty"`
+	DownstreamCollation          *string           `json:"downstream_collation,omitempty"`
+	AuditSideChannel             *string           `json:"audit_side_channel,omitempty"`
+	AuditSideChannelCompression  *bool             `json:"audit_side_channel_compression,omitempty"`
+	PtOSCCompatibility           *bool             `json:"pt_osc_compatibility,omitempty"`
+	PtOSCWaitTimeout             *string           `json:"pt_osc_wait_timeout,omitempty"`
+	BatchDDLMode                 *bool             `json:"batch_ddl_mode,omitempty"`
+	CreateTableIfNotExists       *bool             `json:"create_table_if_not_exists,omitempty"`
+	UpstreamTiDBDSN              *string           `json:"upstream_tidb_dsn,omitempty"`
+	AutoIncrementConflictPolicy  *string           `json:"auto_increment_conflict_policy,omitempty"`
+	ConditionalDDL               *bool             `json:"conditional_ddl,omitempty"`
 }
 
 // CloudStorageConfig represents a cloud storage sink configuration
@@ -1253,6 +1605,72 @@ type ChangefeedStatus struct {
 	CheckpointTs uint64        `json:"checkpoint_ts"`
 	LastError    *RunningError `json:"last_error,omitempty"`
 	LastWarning  *RunningError `json:"last_warning,omitempty"`
+	GTIDExecuted string        `json:"gtid_executed,omitempty"`
+	// BinlogPosition is only set when track_binlog_position is enabled.
+	BinlogPosition *BinlogPosition `json:"binlog_position,omitempty"`
+	ScanProgress   *ScanProgress   `json:"scan_progress,omitempty"`
+
+	ReadAmplification *ReadAmplification `json:"read_amplification,omitempty"`
+	// Queued is true if the changefeed is waiting for the owner to run it.
+	Queued bool `json:"queued,omitempty"`
+}
+
+// ScanProgress is the progress of the incremental scan of the regions
+// captured by a changefeed.
+type ScanProgress struct {
+	TotalRegions            uint64     `json:"total_regions"`
+	ScannedRegions          uint64     `json:"scanned_regions"`
+	EstimatedCompletionTime *time.Time `json:"estimated_completion_time,omitempty"`
+	// CurrentScanRate is the number of regions scanned per second.
+	CurrentScanRate float64 `json:"current_scan_rate"`
+}
+
+func toAPIScanProgress(progress *model.ScanProgress) *ScanProgress {
+	if progress == nil {
+		return nil
+	}
+	return &ScanProgress{
+		TotalRegions:            progress.TotalRegions,
+		ScannedRegions:          progress.ScannedRegions,
+		EstimatedCompletionTime: progress.EstimatedCompletionTime,
+		CurrentScanRate:         progress.CurrentScanRate,
+	}
+}
+
+// ReadAmplification is the ratio of the bytes scanned from TiKV to the bytes
+// written to the downstream of a changefeed.
+type ReadAmplification struct {
+	ScannedBytes uint64  `json:"scanned_bytes"`
+	WrittenBytes uint64  `json:"written_bytes"`
+	Ratio        float64 `json:"ratio"`
+}
+
+func toAPIReadAmplification(ra *model.ReadAmplification) *ReadAmplification {
+	if ra == nil {
+		return nil
+	}
+	return &ReadAmplification{
+		ScannedBytes: ra.ScannedBytes,
+		WrittenBytes: ra.WrittenBytes,
+		Ratio:        ra.Ratio,
+	}
+}
+
+// DDLHistoryEntry is a DDL executed to downstream by a changefeed.
+type DDLHistoryEntry struct {
+	ExecutedAt     time.Time `json:"executed_at"`
+	CommitTs       uint64    `json:"commit_ts"`
+	Query          string    `json:"query"`
+	AffectedTables []string  `json:"affected_tables,omitempty"`
+}
+
+// BinlogPosition is the binlog position of a MySQL downstream
+// which corresponds to the checkpoint_ts.
+type BinlogPosition struct {
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	File         string `json:"file"`
+	Pos          uint64 `json:"pos"`
+	GTIDSet      string `json:"gtid_set,omitempty"`
 }
 
 // GlueSchemaRegistryConfig represents a glue schema registry configuration
@@ -33,10 +33,14 @@ var defaultAPIConfig = &ReplicaConfig{
 	CaseSensitive:      false,
 	CheckGCSafePoint:   true,
 	BDRMode:            util.AddressOf(false),
+	SchemaOnlyMode:     util.AddressOf(false),
 	EnableSyncPoint:    util.AddressOf(false),
 	EnableTableMonitor: util.AddressOf(false),
 	SyncPointInterval:  &JSONDuration{10 * time.Minute},
 	SyncPointRetention: &JSONDuration{24 * time.Hour},
+
+	ValidateInitialConsistency:   util.AddressOf(false),
+	InitialConsistencySampleRate: util.AddressOf(0.01),
 	Filter: &FilterConfig{
 		Rules: []string{"*.*"},
 	},
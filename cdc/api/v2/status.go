@@ -16,6 +16,7 @@ package v2
 import (
 	"net/http"
 	"os"
+	"runtime"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -40,6 +41,8 @@ func (h *OpenAPIV2) serverStatus(c *gin.Context) {
 		return
 	}
 	etcdClient := h.capture.GetEtcdClient()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
 	status := model.ServerStatus{
 		Version:   version.ReleaseVersion,
 		GitHash:   version.GitHash,
@@ -48,6 +51,9 @@ func (h *OpenAPIV2) serverStatus(c *gin.Context) {
 		ClusterID: etcdClient.GetClusterID(),
 		IsOwner:   h.capture.IsController(),
 		Liveness:  h.capture.Liveness(),
+
+		Goroutines: runtime.NumGoroutine(),
+		HeapInUse:  memStats.HeapInuse,
 	}
 	c.IndentedJSON(http.StatusOK, status)
 }
@@ -55,5 +55,7 @@ func TestGetStatus(t *testing.T) {
 	require.Equal(t, model.LivenessCaptureStopping, resp.Liveness)
 	require.True(t, resp.IsOwner)
 	require.Equal(t, "capture-id", resp.ID)
+	require.Positive(t, resp.Goroutines)
+	require.Positive(t, resp.HeapInUse)
 	require.Equal(t, http.StatusOK, w.Code)
 }
@@ -0,0 +1,233 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// defaultTableStatsPageSize is the default and the maximum number of
+	// tables in a page of the table stats response.
+	defaultTableStatsPageSize = 1000
+	// tableStatsCacheTTL is the duration that the collected table stats are
+	// cached, to reduce the contention on the processor.
+	tableStatsCacheTTL = time.Second
+)
+
+// getTableStats gets the replication statistics of the tables replicated by a processor
+// @Summary Get the replication statistics of tables
+// @Description get the replication statistics of the tables replicated by a processor
+// @Tags processor,v2
+// @Produce json
+// @Success 200 {object} GetTableStatsResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Param   changefeed_id   path    string  true  "changefeed ID"
+// @Param   namespace      query string false "default"
+// @Param   capture_id   path    string  true  "capture ID"
+// @Param   page      query int false "1"
+// @Param   page_size      query int false "1000"
+// @Router	/api/v2/processors/{changefeed_id}/{capture_id}/tables/stats [get]
+func (h *OpenAPIV2) getTableStats(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid changefeed_id: %s", changefeedID.ID))
+		return
+	}
+	captureID := c.Param(apiOpVarCaptureID)
+	if err := model.ValidateChangefeedID(captureID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid capture_id: %s", captureID))
+		return
+	}
+	req := &GetTableStatsRequest{}
+	if err := c.BindQuery(req); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+
+	self, err := h.capture.Info()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// The statistics are collected from the local processor,
+	// forward the request to the capture which runs the processor.
+	if captureID != self.ID {
+		_, captures, err := h.capture.GetEtcdClient().GetCaptures(ctx)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		for _, capture := range captures {
+			if capture.ID == captureID {
+				api.ForwardToCapture(c, self.ID, capture.AdvertiseAddr)
+				return
+			}
+		}
+		_ = c.Error(cerror.ErrCaptureNotExist.GenWithStackByArgs(captureID))
+		return
+	}
+
+	resp, err := h.getTableStatsResponse(ctx, changefeedID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getTableStatsResponse aggregates the statistics of table spans by table,
+// and returns the requested page.
+func (h *OpenAPIV2) getTableStatsResponse(
+	ctx context.Context, changefeedID model.ChangeFeedID, req *GetTableStatsRequest,
+) (*GetTableStatsResponse, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = defaultTableStatsPageSize
+	}
+	if req.Page < 0 || req.PageSize < 0 || req.PageSize > defaultTableStatsPageSize {
+		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid page: %d or page_size: %d, page_size should be in [1, %d]",
+			req.Page, req.PageSize, defaultTableStatsPageSize)
+	}
+
+	tables, err := h.tableStatsCache.get(ctx, changefeedID, h.capture.GetTableStats)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GetTableStatsResponse{
+		Total:    len(tables),
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Items:    []TableStats{},
+	}
+	start := (req.Page - 1) * req.PageSize
+	if start < len(tables) {
+		end := start + req.PageSize
+		if end > len(tables) {
+			end = len(tables)
+		}
+		resp.Items = tables[start:end]
+	}
+	return resp, nil
+}
+
+// aggregateTableStats aggregates the statistics of spans into tables,
+// the result is sorted by table ID.
+func aggregateTableStats(spans []sinkmanager.TableReplicationStats) []TableStats {
+	tables := make(map[model.TableID]*TableStats)
+	for _, span := range spans {
+		lagMs := span.Lag.Milliseconds()
+		table, ok := tables[span.Span.TableID]
+		if !ok {
+			tables[span.Span.TableID] = &TableStats{
+				TableID:      span.Span.TableID,
+				RowsInserted: span.RowsInserted,
+				RowsUpdated:  span.RowsUpdated,
+				RowsDeleted:  span.RowsDeleted,
+				BytesWritten: span.BytesWritten,
+				CheckpointTs: span.CheckpointTs,
+				ResolvedTs:   span.ResolvedTs,
+				LagMs:        lagMs,
+			}
+			continue
+		}
+		table.RowsInserted += span.RowsInserted
+		table.RowsUpdated += span.RowsUpdated
+		table.RowsDeleted += span.RowsDeleted
+		table.BytesWritten += span.BytesWritten
+		if span.CheckpointTs < table.CheckpointTs {
+			table.CheckpointTs = span.CheckpointTs
+		}
+		if span.ResolvedTs < table.ResolvedTs {
+			table.ResolvedTs = span.ResolvedTs
+		}
+		if lagMs > table.LagMs {
+			table.LagMs = lagMs
+		}
+	}
+
+	result := make([]TableStats, 0, len(tables))
+	for _, table := range tables {
+		result = append(result, *table)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TableID < result[j].TableID
+	})
+	return result
+}
+
+type tableStatsCacheEntry struct {
+	tables    []TableStats
+	updatedAt time.Time
+}
+
+// tableStatsCache caches the aggregated table stats of changefeeds.
+type tableStatsCache struct {
+	mu      sync.Mutex
+	entries map[model.ChangeFeedID]tableStatsCacheEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+func newTableStatsCache() *tableStatsCache {
+	return &tableStatsCache{
+		entries: make(map[model.ChangeFeedID]tableStatsCacheEntry),
+		ttl:     tableStatsCacheTTL,
+		now:     time.Now,
+	}
+}
+
+// get returns the cached table stats of the changefeed, the stats are
+// collected by the collect function if the cache is expired.
+func (c *tableStatsCache) get(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+	collect func(context.Context, model.ChangeFeedID) ([]sinkmanager.TableReplicationStats, error),
+) ([]TableStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for id, entry := range c.entries {
+		if now.Sub(entry.updatedAt) >= c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	if entry, ok := c.entries[changefeedID]; ok {
+		return entry.tables, nil
+	}
+
+	spans, err := collect(ctx, changefeedID)
+	if err != nil {
+		return nil, err
+	}
+	tables := aggregateTableStats(spans)
+	c.entries[changefeedID] = tableStatsCacheEntry{tables: tables, updatedAt: now}
+	return tables, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTableStats(t *testing.T) {
+	t.Parallel()
+
+	ctl := gomock.NewController(t)
+	cp := mock_capture.NewMockCapture(ctl)
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: captureID}, nil).AnyTimes()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	spans := make([]sinkmanager.TableReplicationStats, 0, 1501)
+	for i := 1500; i > 0; i-- {
+		spans = append(spans, sinkmanager.TableReplicationStats{
+			Span:         tablepb.Span{TableID: int64(i)},
+			RowsInserted: 1,
+			BytesWritten: 10,
+			CheckpointTs: 100,
+			ResolvedTs:   200,
+			Lag:          time.Second,
+		})
+	}
+	// The table 1 is split into two spans.
+	spans = append(spans, sinkmanager.TableReplicationStats{
+		Span:         tablepb.Span{TableID: 1, StartKey: []byte("b")},
+		RowsUpdated:  2,
+		RowsDeleted:  3,
+		BytesWritten: 20,
+		CheckpointTs: 90,
+		ResolvedTs:   300,
+		Lag:          2 * time.Second,
+	})
+	cp.EXPECT().GetTableStats(gomock.Any(), changefeedID).Return(spans, nil).Times(2)
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	now := time.Now()
+	apiV2.tableStatsCache.now = func() time.Time { return now }
+	router := newRouter(apiV2)
+
+	getTableStats := func(query string) (int, *GetTableStatsResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), "GET",
+			fmt.Sprintf("/api/v2/processors/%s/%s/tables/stats%s",
+				changefeedID.ID, captureID, query), nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		resp := &GetTableStatsResponse{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(resp))
+		return w.Code, resp
+	}
+
+	// The first page contains 1000 tables by default.
+	code, resp := getTableStats("")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1500, resp.Total)
+	require.Equal(t, 1, resp.Page)
+	require.Len(t, resp.Items, 1000)
+	require.Equal(t, TableStats{
+		TableID:      1,
+		RowsInserted: 1,
+		RowsUpdated:  2,
+		RowsDeleted:  3,
+		BytesWritten: 30,
+		CheckpointTs: 90,
+		ResolvedTs:   200,
+		LagMs:        2000,
+	}, resp.Items[0])
+	require.Equal(t, int64(1000), resp.Items[999].TableID)
+
+	// The stats are cached, the second page is served from the cache.
+	code, resp = getTableStats("?page=2")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Items, 500)
+	require.Equal(t, int64(1001), resp.Items[0].TableID)
+
+	code, resp = getTableStats("?page=3&page_size=1000")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Items, 0)
+
+	code, _ = getTableStats("?page_size=1001")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// The stats are collected again after the cache is expired.
+	now = now.Add(tableStatsCacheTTL)
+	code, resp = getTableStats("?page_size=10")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Items, 10)
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/processor"
+	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/factory"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -67,6 +69,14 @@ type Capture interface {
 	Info() (model.CaptureInfo, error)
 	StatusProvider() owner.StatusProvider
 	WriteDebugInfo(ctx context.Context, w io.Writer)
+	// GetTableStats returns the replication statistics of the tables
+	// replicated by the processor of the changefeed on this capture.
+	GetTableStats(ctx context.Context, id model.ChangeFeedID) ([]sinkmanager.TableReplicationStats, error)
+	// GetSorterPositionRange returns the position range of the events of
+	// the table span buffered in the sorter of the changefeed on this capture.
+	GetSorterPositionRange(
+		ctx context.Context, id model.ChangeFeedID, span tablepb.Span,
+	) (processor.SorterPositionRange, error)
 
 	GetUpstreamManager() (*upstream.Manager, error)
 	GetEtcdClient() etcd.CDCEtcdClient
@@ -237,6 +247,7 @@ func (c *captureImpl) reset(ctx context.Context) error {
 		GitHash:        version.GitHash,
 		DeployPath:     deployPath,
 		StartTimestamp: time.Now().Unix(),
+		Region:         c.config.Region,
 	}
 
 	if c.upstreamManager != nil {
@@ -267,11 +278,11 @@ func (c *captureImpl) reset(ctx context.Context) error {
 		c.MessageRouter.Close()
 		c.MessageRouter = nil
 	}
-	messageServerConfig := c.config.Debug.Messages.ToMessageServerConfig()
+	messageServerConfig := c.config.MessageServerConfig()
 	c.MessageServer = p2p.NewMessageServer(c.info.ID, messageServerConfig)
 	c.grpcService.Reset(c.MessageServer)
 
-	messageClientConfig := c.config.Debug.Messages.ToMessageClientConfig()
+	messageClientConfig := c.config.MessageClientConfig()
 
 	// Puts the advertise-addr of the local node to the client config.
 	// This is for metrics purpose only, so that the receiver knows which
@@ -768,6 +779,60 @@ func (c *captureImpl) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	wait(doneM)
 }
 
+// GetTableStats implements Capture interface.
+func (c *captureImpl) GetTableStats(
+	ctx context.Context, id model.ChangeFeedID,
+) ([]sinkmanager.TableReplicationStats, error) {
+	query := &processor.TableStatsQuery{ChangefeedID: id}
+	done := make(chan error, 1)
+	c.captureMu.Lock()
+	if c.processorManager == nil {
+		c.captureMu.Unlock()
+		return nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id.ID)
+	}
+	c.processorManager.QueryTableStats(ctx, query, done)
+	// NOTICE: we must release the lock before waiting the query to be done,
+	// see WriteDebugInfo for details.
+	c.captureMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return query.Stats, nil
+}
+
+// GetSorterPositionRange implements Capture interface.
+func (c *captureImpl) GetSorterPositionRange(
+	ctx context.Context, id model.ChangeFeedID, span tablepb.Span,
+) (processor.SorterPositionRange, error) {
+	query := &processor.SorterPositionRangeQuery{ChangefeedID: id, Span: span}
+	done := make(chan error, 1)
+	c.captureMu.Lock()
+	if c.processorManager == nil {
+		c.captureMu.Unlock()
+		return processor.SorterPositionRange{}, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id.ID)
+	}
+	c.processorManager.QuerySorterPositionRange(ctx, query, done)
+	// NOTICE: we must release the lock before waiting the query to be done,
+	// see WriteDebugInfo for details.
+	c.captureMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return processor.SorterPositionRange{}, errors.Trace(ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return processor.SorterPositionRange{}, errors.Trace(err)
+		}
+	}
+	return query.Range, nil
+}
+
 // IsController returns whether the capture is a controller
 func (c *captureImpl) IsController() bool {
 	c.ownerMu.Lock()
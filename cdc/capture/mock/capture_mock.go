@@ -13,6 +13,9 @@ import (
 	controller "github.com/pingcap/tiflow/cdc/controller"
 	model "github.com/pingcap/tiflow/cdc/model"
 	owner "github.com/pingcap/tiflow/cdc/owner"
+	processor "github.com/pingcap/tiflow/cdc/processor"
+	sinkmanager "github.com/pingcap/tiflow/cdc/processor/sinkmanager"
+	tablepb "github.com/pingcap/tiflow/cdc/processor/tablepb"
 	etcd "github.com/pingcap/tiflow/pkg/etcd"
 	upstream "github.com/pingcap/tiflow/pkg/upstream"
 )
@@ -240,6 +243,36 @@ func (mr *MockCaptureMockRecorder) StatusProvider() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusProvider", reflect.TypeOf((*MockCapture)(nil).StatusProvider))
 }
 
+// GetTableStats mocks base method.
+func (m *MockCapture) GetTableStats(ctx context.Context, id model.ChangeFeedID) ([]sinkmanager.TableReplicationStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTableStats", ctx, id)
+	ret0, _ := ret[0].([]sinkmanager.TableReplicationStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTableStats indicates an expected call of GetTableStats.
+func (mr *MockCaptureMockRecorder) GetTableStats(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTableStats", reflect.TypeOf((*MockCapture)(nil).GetTableStats), ctx, id)
+}
+
+// GetSorterPositionRange mocks base method.
+func (m *MockCapture) GetSorterPositionRange(ctx context.Context, id model.ChangeFeedID, span tablepb.Span) (processor.SorterPositionRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSorterPositionRange", ctx, id, span)
+	ret0, _ := ret[0].(processor.SorterPositionRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSorterPositionRange indicates an expected call of GetSorterPositionRange.
+func (mr *MockCaptureMockRecorder) GetSorterPositionRange(ctx, id, span interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSorterPositionRange", reflect.TypeOf((*MockCapture)(nil).GetSorterPositionRange), ctx, id, span)
+}
+
 // WriteDebugInfo mocks base method.
 func (m *MockCapture) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	m.ctrl.T.Helper()
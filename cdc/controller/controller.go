@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
@@ -153,6 +154,7 @@ func (o *controllerImpl) Tick(stdCtx context.Context, rawState orchestrator.Reac
 			delete(o.changefeeds, changefeedID)
 		}
 	}
+	o.updateNamespaceQuotaMetrics()
 
 	// if closed, exit the etcd worker loop
 	if atomic.LoadInt32(&o.closed) != 0 {
@@ -297,7 +299,26 @@ func (o *controllerImpl) CreateChangefeed(ctx context.Context,
 	upstreamInfo *model.UpstreamInfo,
 	cfInfo *model.ChangeFeedInfo,
 ) error {
-	return o.etcdClient.CreateChangefeedInfo(ctx, upstreamInfo, cfInfo)
+	maxChangefeeds := 0
+	if quota := config.GetGlobalServerConfig().GetNamespaceQuota(cfInfo.Namespace); quota != nil {
+		maxChangefeeds = quota.MaxChangefeeds
+	}
+	return o.etcdClient.CreateChangefeedInfo(ctx, upstreamInfo, cfInfo, maxChangefeeds)
+}
+
+func (o *controllerImpl) updateNamespaceQuotaMetrics() {
+	quotas := config.GetGlobalServerConfig().NamespaceQuota
+	if len(quotas) == 0 {
+		return
+	}
+	counts := make(map[string]int, len(quotas))
+	for id := range o.changefeeds {
+		counts[id.Namespace]++
+	}
+	for namespace := range quotas {
+		namespaceQuotaUsedGauge.WithLabelValues(namespace, quotaChangefeeds).
+			Set(float64(counts[namespace]))
+	}
 }
 
 // Export field names for pretty printing.
@@ -75,7 +75,11 @@ func RegisterRoutes(
 		router.Any("/debug/fail/*any", gin.WrapH(http.StripPrefix("/debug/fail", &failpoint.HttpHandler{})))
 	}
 
-	// Promtheus metrics API
+	// Promtheus metrics API, the OpenMetrics format is returned if it is
+	// requested by the Accept header, otherwise the Prometheus text format.
 	prometheus.DefaultGatherer = registry
-	router.Any("/metrics", gin.WrapH(promhttp.Handler()))
+	router.Any("/metrics", gin.WrapH(promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	)))
 }
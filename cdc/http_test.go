@@ -19,11 +19,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -91,3 +94,61 @@ func TestHandleFailpoint(t *testing.T) {
 	})
 	require.False(t, failpointHit)
 }
+
+func TestMetricsContentNegotiation(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "test",
+		Name:      "events_total",
+		Help:      "The number of events",
+	})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "ticdc",
+		Subsystem: "test",
+		Name:      "duration_seconds",
+		Help:      "The duration of events",
+	})
+	registry.MustRegister(counter, histogram)
+	counter.Add(3)
+	histogram.Observe(0.1)
+
+	router := gin.New()
+	RegisterRoutes(router, capture.NewCapture4Test(nil), registry)
+	request := func(accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	// Prometheus text format by default.
+	w := request("")
+	require.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+	families, err := new(expfmt.TextParser).TextToMetricFamilies(w.Body)
+	require.NoError(t, err)
+	require.Equal(t, float64(3), families["ticdc_test_events_total"].GetMetric()[0].GetCounter().GetValue())
+
+	// OpenMetrics format if it's accepted.
+	w = request("application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+	require.Equal(t, string(expfmt.FmtOpenMetrics_1_0_0), w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Equal(t, "# EOF", lines[len(lines)-1])
+	require.Contains(t, lines, "# TYPE ticdc_test_events counter")
+	require.Contains(t, lines, "ticdc_test_events_total 3.0")
+	require.Contains(t, lines, "# TYPE ticdc_test_duration_seconds histogram")
+	require.Contains(t, lines, "ticdc_test_duration_seconds_count 1")
+	for _, line := range lines[:len(lines)-1] {
+		if strings.HasPrefix(line, "#") {
+			require.Regexp(t, "^# (HELP|TYPE|UNIT) [a-zA-Z_:][a-zA-Z0-9_:]* ", line)
+			continue
+		}
+		// A metric point is `name{labels} value`.
+		require.Regexp(t, `^[a-zA-Z_:][a-zA-Z0-9_:]*(\{.*\})? \S+$`, line)
+	}
+}
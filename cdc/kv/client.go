@@ -172,6 +172,10 @@ type CDCKVClient interface {
 
 	// RegionCount returns the number of captured regions.
 	RegionCount() uint64
+
+	// InitializedRegionCount returns the number of captured regions which
+	// have finished the incremental scan.
+	InitializedRegionCount() uint64
 	// ResolvedTs returns the current ingress resolved ts.
 	ResolvedTs() model.Ts
 	// CommitTs returns the current ingress commit ts.
@@ -211,9 +215,10 @@ type CDCClient struct {
 }
 
 type tableStoreStat struct {
-	regionCount atomic.Uint64
-	resolvedTs  atomic.Uint64
-	commitTs    atomic.Uint64
+	regionCount            atomic.Uint64
+	initializedRegionCount atomic.Uint64
+	resolvedTs             atomic.Uint64
+	commitTs               atomic.Uint64
 }
 
 // NewCDCClient creates a CDCClient instance
@@ -333,6 +338,17 @@ func (c *CDCClient) RegionCount() (totalCount uint64) {
 	return totalCount
 }
 
+// InitializedRegionCount returns the number of captured regions which have
+// finished the incremental scan.
+func (c *CDCClient) InitializedRegionCount() (totalCount uint64) {
+	c.tableStoreStats.RLock()
+	defer c.tableStoreStats.RUnlock()
+	for _, v := range c.tableStoreStats.v {
+		totalCount += v.initializedRegionCount.Load()
+	}
+	return totalCount
+}
+
 // ResolvedTs returns the current ingress resolved ts.
 func (c *CDCClient) ResolvedTs() model.Ts {
 	c.tableStoreStats.RLock()
@@ -1227,6 +1243,7 @@ func (s *eventFeedSession) receiveFromStream(
 				// TiKV send resolved ts events every second by default.
 				// We check and update region count here to save CPU.
 				tsStat.regionCount.Store(uint64(worker.statesManager.regionCount()))
+				tsStat.initializedRegionCount.Store(uint64(worker.statesManager.initializedRegionCount()))
 				tsStat.resolvedTs.Store(cevent.ResolvedTs.Ts)
 				if maxCommitTs == 0 {
 					// In case, there is no write for the table,
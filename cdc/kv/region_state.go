@@ -16,6 +16,7 @@ package kv
 import (
 	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pingcap/tiflow/cdc/kv/regionlock"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -73,6 +74,10 @@ type regionFeedState struct {
 		// `err` is used to retrieve errors generated outside.
 		err error
 	}
+
+	// initializedCounted is true if the region is counted as initialized by
+	// regionStateManager. It's protected by the lock of the bucket.
+	initializedCounted bool
 }
 
 func newRegionFeedState(sri singleRegionInfo, requestID uint64) *regionFeedState {
@@ -216,10 +221,13 @@ func (m *syncRegionFeedStateMap) takeAll() map[uint64]*regionFeedState {
 	return state
 }
 
-func (m *syncRegionFeedStateMap) setByRegionID(regionID uint64, state *regionFeedState) {
+// setByRegionID returns true if an initialized state is replaced.
+func (m *syncRegionFeedStateMap) setByRegionID(regionID uint64, state *regionFeedState) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	old, ok := m.statesInternal[regionID]
 	m.statesInternal[regionID] = state
+	return ok && old != state && old.initializedCounted
 }
 
 func (m *syncRegionFeedStateMap) getByRegionID(regionID uint64) (*regionFeedState, bool) {
@@ -229,10 +237,25 @@ func (m *syncRegionFeedStateMap) getByRegionID(regionID uint64) (*regionFeedStat
 	return result, ok
 }
 
-func (m *syncRegionFeedStateMap) delByRegionID(regionID uint64) {
+// delByRegionID returns true if an initialized state is deleted.
+func (m *syncRegionFeedStateMap) delByRegionID(regionID uint64) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	old, ok := m.statesInternal[regionID]
 	delete(m.statesInternal, regionID)
+	return ok && old.initializedCounted
+}
+
+// markInitialized returns true if the state is stored and it's the first
+// time to be marked as initialized.
+func (m *syncRegionFeedStateMap) markInitialized(regionID uint64, state *regionFeedState) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.statesInternal[regionID] != state || state.initializedCounted {
+		return false
+	}
+	state.initializedCounted = true
+	return true
 }
 
 func (m *syncRegionFeedStateMap) len() int {
@@ -252,6 +275,8 @@ type regionStateManagerInterface interface {
 type regionStateManager struct {
 	bucket int
 	states []*syncRegionFeedStateMap
+	// initialized is the count of initialized regions in states.
+	initialized atomic.Int64
 }
 
 func newRegionStateManager(bucket int) *regionStateManager {
@@ -286,12 +311,24 @@ func (rsm *regionStateManager) getState(regionID uint64) (*regionFeedState, bool
 
 func (rsm *regionStateManager) setState(regionID uint64, state *regionFeedState) {
 	bucket := rsm.getBucket(regionID)
-	rsm.states[bucket].setByRegionID(regionID, state)
+	if rsm.states[bucket].setByRegionID(regionID, state) {
+		rsm.initialized.Add(-1)
+	}
 }
 
 func (rsm *regionStateManager) delState(regionID uint64) {
 	bucket := rsm.getBucket(regionID)
-	rsm.states[bucket].delByRegionID(regionID)
+	if rsm.states[bucket].delByRegionID(regionID) {
+		rsm.initialized.Add(-1)
+	}
+}
+
+// markInitialized counts the region as initialized if its state is stored.
+func (rsm *regionStateManager) markInitialized(regionID uint64, state *regionFeedState) {
+	bucket := rsm.getBucket(regionID)
+	if rsm.states[bucket].markInitialized(regionID, state) {
+		rsm.initialized.Add(1)
+	}
 }
 
 func (rsm *regionStateManager) regionCount() (count int64) {
@@ -300,3 +337,7 @@ func (rsm *regionStateManager) regionCount() (count int64) {
 	}
 	return
 }
+
+func (rsm *regionStateManager) initializedRegionCount() int64 {
+	return rsm.initialized.Load()
+}
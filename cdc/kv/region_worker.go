@@ -685,7 +685,12 @@ func (w *regionWorker) handleEventEntry(
 			return false
 		}
 	}
-	return handleEventEntry(x, w.session.startTs, state, w.metrics, emit, w.session.changefeed, w.session.tableID, w.session.client.logRegionDetails)
+	initialized := state.isInitialized()
+	err := handleEventEntry(x, w.session.startTs, state, w.metrics, emit, w.session.changefeed, w.session.tableID, w.session.client.logRegionDetails)
+	if !initialized && state.isInitialized() {
+		w.statesManager.markInitialized(state.getRegionID(), state)
+	}
+	return err
 }
 
 func handleEventEntry(
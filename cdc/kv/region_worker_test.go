@@ -43,6 +43,35 @@ func TestRegionStateManager(t *testing.T) {
 	require.Equal(t, uint64(2), state.requestID)
 }
 
+func TestRegionStateManagerInitializedRegionCount(t *testing.T) {
+	rsm := newRegionStateManager(4)
+	newState := func() *regionFeedState {
+		state := &regionFeedState{}
+		state.sri.lockedRange = &regionlock.LockedRange{}
+		return state
+	}
+
+	s1, s2 := newState(), newState()
+	rsm.setState(1, s1)
+	rsm.setState(2, s2)
+	require.Equal(t, int64(0), rsm.initializedRegionCount())
+
+	rsm.markInitialized(1, s1)
+	rsm.markInitialized(1, s1)
+	require.Equal(t, int64(1), rsm.initializedRegionCount())
+	// States not stored are not counted.
+	rsm.markInitialized(3, newState())
+	require.Equal(t, int64(1), rsm.initializedRegionCount())
+
+	rsm.markInitialized(2, s2)
+	require.Equal(t, int64(2), rsm.initializedRegionCount())
+	rsm.delState(2)
+	require.Equal(t, int64(1), rsm.initializedRegionCount())
+	// The region is re-subscribed with a new state.
+	rsm.setState(1, newState())
+	require.Equal(t, int64(0), rsm.initializedRegionCount())
+}
+
 func TestRegionStateManagerThreadSafe(t *testing.T) {
 	rsm := newRegionStateManager(4)
 	regionCount := 100
@@ -555,6 +555,20 @@ func (l *RegionRangeLock) LockedRanges() int {
 	return l.rangeLock.Len()
 }
 
+// InitializedRanges returns count of locked ranges which are initialized.
+func (l *RegionRangeLock) InitializedRanges() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	count := 0
+	l.rangeLock.Ascend(func(item *rangeLockEntry) bool {
+		if item.state.Initialzied.Load() {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 // RefCount returns how many ranges are locked.
 func (l *RegionRangeLock) RefCount() uint64 {
 	l.mu.RLock()
@@ -289,6 +289,24 @@ func TestRegionRangeLockCollect(t *testing.T) {
 	require.Equal(t, 1, len(attrs.Holes))
 }
 
+func TestRegionRangeLockInitializedRanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := NewRegionRangeLock(1, []byte("a"), []byte("z"), 100, "")
+	r1 := l.LockRange(ctx, []byte("a"), []byte("m"), 1, 1).LockedRange
+	r2 := l.LockRange(ctx, []byte("m"), []byte("z"), 2, 1).LockedRange
+	require.Equal(t, 0, l.InitializedRanges())
+
+	r1.Initialzied.Store(true)
+	require.Equal(t, 1, l.InitializedRanges())
+	r2.Initialzied.Store(true)
+	require.Equal(t, 2, l.InitializedRanges())
+
+	l.UnlockRange([]byte("a"), []byte("m"), 1, 1)
+	require.Equal(t, 1, l.InitializedRanges())
+}
+
 func TestCalculateMinResolvedTs(t *testing.T) {
 	l := NewRegionRangeLock(1, []byte("a"), []byte("z"), 100, "")
 
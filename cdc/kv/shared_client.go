@@ -240,6 +240,17 @@ func (s *SharedClient) RegionCount(subID SubscriptionID) uint64 {
 	return 0
 }
 
+// InitializedRegionCount returns the count of subscribed regions which have
+// finished the incremental scan for the span.
+func (s *SharedClient) InitializedRegionCount(subID SubscriptionID) uint64 {
+	s.totalSpans.RLock()
+	defer s.totalSpans.RUnlock()
+	if rt := s.totalSpans.v[subID]; rt != nil {
+		return uint64(rt.rangeLock.InitializedRanges())
+	}
+	return 0
+}
+
 // Run the client.
 func (s *SharedClient) Run(ctx context.Context) error {
 	s.clusterID = s.pd.GetClusterID(ctx)
@@ -32,6 +32,9 @@ type CaptureInfo struct {
 	GitHash        string `json:"git-hash"`
 	DeployPath     string `json:"deploy-path"`
 	StartTimestamp int64  `json:"start-timestamp"`
+
+	// Region is the region label of the capture, it's empty if not set.
+	Region string `json:"region,omitempty"`
 }
 
 // Marshal using json.Marshal.
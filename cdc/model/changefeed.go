@@ -14,6 +14,8 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"math"
 	"net/url"
@@ -304,6 +306,16 @@ func (info *ChangeFeedInfo) Clone() (*ChangeFeedInfo, error) {
 	return cloned, err
 }
 
+// ComputeConfigHash returns the SHA256 of the json encoded Config.
+func (info *ChangeFeedInfo) ComputeConfigHash() (string, error) {
+	data, err := json.Marshal(info.Config)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // VerifyAndComplete verifies changefeed info and may fill in some fields.
 // If a required field is not provided, return an error.
 // If some necessary filed is missing but can use a default value, fill in it.
@@ -349,7 +361,7 @@ func (info *ChangeFeedInfo) RmUnusedFields() {
 		log.Warn(
 			"failed to parse the sink uri",
 			zap.Error(err),
-			zap.Any("sinkUri", info.SinkURI),
+			zap.String("sinkURI", util.MaskSensitiveDataInURI(info.SinkURI)),
 		)
 		return
 	}
@@ -508,9 +520,8 @@ func (info *ChangeFeedInfo) fixMySQLSinkProtocol() {
 	query := uri.Query()
 	protocolStr := query.Get(config.ProtocolKey)
 	if protocolStr != "" || info.Config.Sink.Protocol != nil {
-		maskedSinkURI, _ := util.MaskSinkURI(info.SinkURI)
 		log.Warn("sink URI or sink config contains protocol, but scheme is not mq",
-			zap.String("sinkURI", maskedSinkURI),
+			zap.String("sinkURI", util.MustMaskSinkURI(info.SinkURI)),
 			zap.String("protocol", protocolStr),
 			zap.Any("sinkConfig", info.Config.Sink))
 		// always set protocol of mysql sink to ""
@@ -560,9 +571,8 @@ func (info *ChangeFeedInfo) fixMQSinkProtocol() {
 
 func (info *ChangeFeedInfo) updateSinkURIAndConfigProtocol(uri *url.URL, newProtocol string, newQuery url.Values) {
 	newRawQuery := newQuery.Encode()
-	maskedURI, _ := util.MaskSinkURI(uri.String())
 	log.Info("handle incompatible protocol from sink URI",
-		zap.String("oldURI", maskedURI),
+		zap.String("oldURI", util.MustMaskSinkURI(uri.String())),
 		zap.String("newProtocol", newProtocol))
 
 	uri.RawQuery = newRawQuery
@@ -583,6 +593,52 @@ func (info *ChangeFeedInfo) fixScheduler(inheritV66 bool) {
 type ChangeFeedStatusForAPI struct {
 	ResolvedTs   uint64 `json:"resolved-ts"`
 	CheckpointTs uint64 `json:"checkpoint-ts"`
+	// GTIDExecuted is the latest gtid_executed of a MySQL downstream.
+	GTIDExecuted string `json:"gtid-executed,omitempty"`
+	// BinlogPosition is the latest binlog position of a MySQL downstream.
+	BinlogPosition *BinlogPosition `json:"binlog-position,omitempty"`
+	// ScanProgress is the progress of the incremental scan of regions.
+	ScanProgress *ScanProgress `json:"scan-progress,omitempty"`
+	// ReadAmplification is the ratio of the bytes scanned from TiKV to the
+	// bytes written to the downstream.
+	ReadAmplification *ReadAmplification `json:"read-amplification,omitempty"`
+	// Queued is true if the changefeed is waiting for the owner to run it
+	// since the owner already runs max-concurrent-changefeeds changefeeds.
+	Queued bool `json:"queued,omitempty"`
+}
+
+// ReadAmplification is the ratio of the bytes scanned from TiKV to the bytes
+// written to the downstream of a changefeed. The events scanned from TiKV may
+// be dropped by filter rules or deduplicated before reaching the downstream,
+// a high ratio means TiKV is loaded by data that is never replicated.
+type ReadAmplification struct {
+	ScannedBytes uint64 `json:"scanned-bytes"`
+	WrittenBytes uint64 `json:"written-bytes"`
+	// Ratio is 0 if nothing is written to the downstream.
+	Ratio float64 `json:"ratio"`
+}
+
+// NewReadAmplification creates a ReadAmplification.
+func NewReadAmplification(scanned, written uint64) *ReadAmplification {
+	ra := &ReadAmplification{ScannedBytes: scanned, WrittenBytes: written}
+	if written > 0 {
+		ra.Ratio = float64(scanned) / float64(written)
+	}
+	return ra
+}
+
+// ScanProgress is the progress of the incremental scan of the regions captured
+// by a changefeed. TiKV scans the existing data of a region before sending its
+// changes, so the scan takes a while when tables start to be replicated, e.g.
+// in the initial sync phase of a changefeed.
+type ScanProgress struct {
+	TotalRegions   uint64 `json:"total-regions"`
+	ScannedRegions uint64 `json:"scanned-regions"`
+	// EstimatedCompletionTime is nil if the scan is finished or
+	// no region is scanned recently.
+	EstimatedCompletionTime *time.Time `json:"estimated-completion-time,omitempty"`
+	// CurrentScanRate is the number of regions scanned per second.
+	CurrentScanRate float64 `json:"current-scan-rate"`
 }
 
 // ChangeFeedSyncedStatusForAPI uses to transfer the synced status of changefeed for API.
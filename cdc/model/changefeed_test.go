@@ -207,7 +207,11 @@ func TestVerifyAndComplete(t *testing.T) {
 			SyncPointInterval:     util.AddressOf(time.Minute * 10),
 			SyncPointRetention:    util.AddressOf(time.Hour * 24),
 			BDRMode:               util.AddressOf(false),
+			SchemaOnlyMode:        util.AddressOf(false),
 			IgnoreIneligibleTable: false,
+
+			ValidateInitialConsistency:   util.AddressOf(false),
+			InitialConsistencySampleRate: util.AddressOf(0.01),
 		},
 	}
 
@@ -891,6 +895,29 @@ func TestChangeFeedInfoClone(t *testing.T) {
 	require.Equal(t, "blackhole://", info.SinkURI)
 }
 
+func TestChangeFeedInfoConfigHash(t *testing.T) {
+	t.Parallel()
+
+	info1 := &ChangeFeedInfo{
+		ID:     "test1",
+		Config: config.GetDefaultReplicaConfig(),
+	}
+	info2 := &ChangeFeedInfo{
+		ID:     "test2",
+		Config: config.GetDefaultReplicaConfig(),
+	}
+	hash1, err := info1.ComputeConfigHash()
+	require.NoError(t, err)
+	hash2, err := info2.ComputeConfigHash()
+	require.NoError(t, err)
+	require.Equal(t, hash1, hash2)
+
+	info2.Config.CaseSensitive = !info2.Config.CaseSensitive
+	hash2, err = info2.ComputeConfigHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash1, hash2)
+}
+
 func TestChangefeedInfoStringer(t *testing.T) {
 	t.Parallel()
 
@@ -1128,3 +1155,14 @@ func TestGetTs(t *testing.T) {
 	status := &ChangeFeedStatus{CheckpointTs: checkpointTs}
 	require.Equal(t, info.GetCheckpointTs(status), checkpointTs)
 }
+
+func TestNewReadAmplification(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, &ReadAmplification{ScannedBytes: 100}, NewReadAmplification(100, 0))
+	require.Equal(t, &ReadAmplification{
+		ScannedBytes: 300,
+		WrittenBytes: 100,
+		Ratio:        3,
+	}, NewReadAmplification(300, 100))
+}
@@ -102,6 +102,9 @@ type ServerStatus struct {
 	Pid       int      `json:"pid"`
 	IsOwner   bool     `json:"is_owner"`
 	Liveness  Liveness `json:"liveness"`
+	// Goroutines and HeapInUse are the runtime statistics of the server.
+	Goroutines int    `json:"goroutines,omitempty"`
+	HeapInUse  uint64 `json:"heap_inuse,omitempty"`
 }
 
 // ChangefeedCommonInfo holds some common usage information of a changefeed
@@ -16,6 +16,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/pingcap/errors"
 	timodel "github.com/pingcap/tidb/pkg/parser/model"
@@ -41,6 +42,9 @@ const (
 	AdminResume
 	AdminRemove
 	AdminFinish
+	// AdminReset restarts the changefeed from the overwritten checkpoint ts,
+	// it's never persisted in the changefeed info.
+	AdminReset
 )
 
 // String implements fmt.Stringer interface.
@@ -56,6 +60,8 @@ func (t AdminJobType) String() string {
 		return "remove changefeed"
 	case AdminFinish:
 		return "finish changefeed"
+	case AdminReset:
+		return "reset changefeed"
 	}
 	return "unknown"
 }
@@ -95,6 +101,20 @@ type TaskPosition struct {
 	Error *RunningError `json:"error"`
 	// Warning when module error happens
 	Warning *RunningError `json:"warning"`
+	// GTIDExecuted is the latest gtid_executed of a MySQL downstream.
+	GTIDExecuted string `json:"gtid-executed,omitempty"`
+	// BinlogPosition is the latest binlog position of a MySQL downstream,
+	// it's only set when track-binlog-position is enabled.
+	BinlogPosition *BinlogPosition `json:"binlog-position,omitempty"`
+}
+
+// BinlogPosition is the binlog position of a MySQL downstream after all
+// transactions with CommitTs <= CheckpointTs in a DML batch are written.
+type BinlogPosition struct {
+	CheckpointTs uint64 `json:"checkpoint-ts"`
+	File         string `json:"file"`
+	Pos          uint64 `json:"pos"`
+	GTIDSet      string `json:"gtid-set,omitempty"`
 }
 
 // Marshal returns the json marshal format of a TaskStatus
@@ -122,6 +142,11 @@ func (tp *TaskPosition) Clone() *TaskPosition {
 		CheckPointTs: tp.CheckPointTs,
 		ResolvedTs:   tp.ResolvedTs,
 		Count:        tp.Count,
+		GTIDExecuted: tp.GTIDExecuted,
+	}
+	if tp.BinlogPosition != nil {
+		pos := *tp.BinlogPosition
+		ret.BinlogPosition = &pos
 	}
 	if tp.Error != nil {
 		ret.Error = &RunningError{
@@ -278,6 +303,19 @@ type ChangeFeedStatus struct {
 	// TODO: remove this filed after we don't use ChangeFeedStatus to
 	// control processor. This is too ambiguous.
 	AdminJobType AdminJobType `json:"admin-job-type"`
+	// DDLHistory is the recently executed DDLs, which are persisted to
+	// recover the DDL history of the changefeed after the owner restarts.
+	DDLHistory []*DDLHistoryEntry `json:"ddl-history,omitempty"`
+}
+
+// DDLHistoryEntry is a DDL executed to downstream by a changefeed.
+type DDLHistoryEntry struct {
+	ExecutedAt time.Time `json:"executed-at"`
+	CommitTs   uint64    `json:"commit-ts"`
+	Query      string    `json:"query"`
+	// AffectedTables is the full names of tables changed by the DDL,
+	// it contains both the old and new names of a renamed table.
+	AffectedTables []string `json:"affected-tables,omitempty"`
 }
 
 // Marshal returns json encoded string of ChangeFeedStatus, only contains necessary fields stored in storage
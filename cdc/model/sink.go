@@ -48,6 +48,9 @@ const (
 	MessageTypeDDL
 	// MessageTypeResolved is resolved type of message key
 	MessageTypeResolved
+	// MessageTypeHeartbeat is heartbeat type of message key, it carries the
+	// resolved ts and is emitted periodically even if there are no events.
+	MessageTypeHeartbeat
 )
 
 const (
@@ -555,7 +558,9 @@ func (r *RowChangedEvent) ApproximateBytes() int {
 	size := 0
 	// Size of cols
 	for i := range r.Columns {
-		size += r.Columns[i].ApproximateBytes
+		if r.Columns[i] != nil {
+			size += r.Columns[i].ApproximateBytes
+		}
 	}
 	// Size of pre cols
 	for i := range r.PreColumns {
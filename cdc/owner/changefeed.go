@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -37,10 +38,14 @@ import (
 	pfilter "github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	redoCfg "github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/sink/observer"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/atomic"
@@ -106,6 +111,9 @@ type changefeed struct {
 	ddlManager  *ddlManager
 	redoDDLMgr  redo.DDLManager
 	redoMetaMgr redo.MetaManager
+	// ddlHistory is kept after the changefeed is released, so that the
+	// history is not lost when the changefeed restarts.
+	ddlHistory *ddlHistory
 
 	schema    entry.SchemaStorage
 	ddlSink   DDLSink
@@ -119,6 +127,9 @@ type changefeed struct {
 	// isRemoved is true if the changefeed is removed,
 	// which means it will be removed from memory forever
 	isRemoved bool
+	// queued is true if the changefeed is waiting for the owner to run it,
+	// it's not initialized until then, but its admin jobs are handled.
+	queued bool
 	// isReleased is true if the changefeed's resources were released,
 	// but it will still be kept in the memory, and it will be check
 	// in every tick. Such as the changefeed that is stopped or encountered an error.
@@ -146,6 +157,11 @@ type changefeed struct {
 	downstreamObserver observer.Observer
 	observerLastTick   *atomic.Time
 
+	// initialConsistencyValidated is set if the initial consistency of the
+	// downstream is being validated, and it becomes true once the
+	// validation is finished. Tables are not scheduled before that.
+	initialConsistencyValidated *atomic.Bool
+
 	newDDLPuller func(ctx context.Context,
 		up *upstream.Upstream,
 		startTs uint64,
@@ -175,8 +191,22 @@ type changefeed struct {
 	// The latest changefeed info and status from meta storage. they are updated in every Tick.
 	latestInfo   *model.ChangeFeedInfo
 	latestStatus *model.ChangeFeedStatus
+
+	// gtidExecuted is the latest gtid_executed of a MySQL downstream reported
+	// by processors, it is updated in every Tick and only used in API.
+	gtidExecuted string
+	// binlogPosition is the latest binlog position of a MySQL downstream
+	// reported by processors, it is updated in every Tick and only used in API.
+	binlogPosition *model.BinlogPosition
+	// scanProgress is the progress of the incremental scan of regions, it is
+	// updated every scanProgressUpdateInterval and only used in API.
+	scanProgress           *model.ScanProgress
+	lastScanProgressUpdate time.Time
 }
 
+// scanProgressUpdateInterval is the interval to update the scan progress.
+const scanProgressUpdateInterval = 5 * time.Second
+
 func (c *changefeed) GetScheduler() scheduler.Scheduler {
 	return c.scheduler
 }
@@ -197,6 +227,7 @@ func NewChangefeed(
 		// The scheduler will be created lazily.
 		scheduler:        nil,
 		barriers:         newBarriers(),
+		ddlHistory:       newDDLHistory(),
 		feedStateManager: feedStateManager,
 		upstream:         up,
 
@@ -343,6 +374,12 @@ func (c *changefeed) handleWarning(err error) {
 func (c *changefeed) checkStaleCheckpointTs(
 	ctx cdcContext.Context, checkpointTs uint64,
 ) error {
+	// A queued changefeed is waiting for the owner rather than lagging behind.
+	// Its checkpoint ts is still kept in the GC safepoint, so it's not checked
+	// until it runs, otherwise it fails once it's queued longer than the GC TTL.
+	if c.queued {
+		return nil
+	}
 	if c.latestInfo.NeedBlockGC() {
 		failpoint.Inject("InjectChangefeedFastFailError", func() error {
 			return cerror.ErrStartTsBeforeGC.FastGen("InjectChangefeedFastFailError")
@@ -377,6 +414,10 @@ func (c *changefeed) tick(ctx cdcContext.Context,
 		return 0, 0, nil
 	}
 
+	if c.queued {
+		return 0, 0, nil
+	}
+
 	if err := c.initialize(ctx); err != nil {
 		return 0, 0, errors.Trace(err)
 	}
@@ -393,6 +434,10 @@ func (c *changefeed) tick(ctx cdcContext.Context,
 		}
 	}
 
+	if c.initialConsistencyValidated != nil && !c.initialConsistencyValidated.Load() {
+		return 0, 0, nil
+	}
+
 	allPhysicalTables, barrier, err := c.ddlManager.tick(ctx, preCheckpointTs)
 	if err != nil {
 		return 0, 0, errors.Trace(err)
@@ -426,6 +471,8 @@ func (c *changefeed) tick(ctx cdcContext.Context,
 		return 0, 0, errors.Trace(err)
 	}
 
+	c.updateScanProgress(time.Now())
+
 	if watermark.LastSyncedTs != scheduler.CheckpointCannotProceed {
 		if c.lastSyncedTs < watermark.LastSyncedTs {
 			c.lastSyncedTs = watermark.LastSyncedTs
@@ -497,6 +544,34 @@ func (c *changefeed) tick(ctx cdcContext.Context,
 	return watermark.CheckpointTs, barrier.MinTableBarrierTs, nil
 }
 
+// checkUpstreamFeatures checks whether the features used by the changefeed
+// are supported by the upstream TiDB. It's skipped if the upstream version
+// is unknown.
+func (c *changefeed) checkUpstreamFeatures() error {
+	detector := c.upstream.VersionDetector
+	if detector == nil || c.latestInfo.Config.Sink == nil {
+		return nil
+	}
+	protocol, err := config.ParseSinkProtocolFromString(
+		util.GetOrZero(c.latestInfo.Config.Sink.Protocol))
+	if err != nil || protocol != config.ProtocolAvro {
+		return nil
+	}
+	sinkURI, err := url.Parse(c.latestInfo.SinkURI)
+	if err != nil {
+		return nil
+	}
+	// Invalid configurations are reported when the sink is created.
+	codecConfig := common.NewConfig(protocol)
+	if err := codecConfig.Apply(sinkURI, c.latestInfo.Config); err != nil {
+		return nil
+	}
+	if codecConfig.EnableTiDBExtension && codecConfig.AvroEnableWatermark {
+		return detector.CheckFeature(version.FeatureAvroWatermark)
+	}
+	return nil
+}
+
 func (c *changefeed) initialize(ctx cdcContext.Context) (err error) {
 	if c.initialized || c.latestStatus == nil {
 		// If `c.latestStatus` is nil it means the changefeed struct is just created, it needs to
@@ -533,6 +608,8 @@ LOOP2:
 	}
 
 	minTableBarrierTs := c.latestStatus.MinTableBarrierTs
+	// Recover the DDL history persisted by the previous owner.
+	c.ddlHistory.recover(c.latestStatus.DDLHistory)
 
 	failpoint.Inject("NewChangefeedNoRetryError", func() {
 		failpoint.Return(cerror.ErrStartTsBeforeGC.GenWithStackByArgs(checkpointTs-300, checkpointTs))
@@ -579,6 +656,10 @@ LOOP2:
 		}
 	}
 
+	if err = c.checkUpstreamFeatures(); err != nil {
+		return errors.Trace(err)
+	}
+
 	var ddlStartTs model.Ts
 	// This means there was a ddl job when the changefeed was paused.
 	// We don't know whether the ddl job is finished or not, so we need to
@@ -646,6 +727,14 @@ LOOP2:
 	}
 	c.observerLastTick = atomic.NewTime(time.Time{})
 
+	// Only the changefeed which has not replicated anything is validated.
+	if util.GetOrZero(c.latestInfo.Config.ValidateInitialConsistency) &&
+		checkpointTs == c.latestInfo.StartTs {
+		if err := c.startInitialConsistencyValidation(cancelCtx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	c.redoDDLMgr = redo.NewDDLManager(c.id, c.latestInfo.Config.Consistent, ddlStartTs)
 	if c.redoDDLMgr.Enabled() {
 		c.wg.Add(1)
@@ -677,11 +766,15 @@ LOOP2:
 		c.schema,
 		c.redoDDLMgr,
 		c.redoMetaMgr,
-		util.GetOrZero(c.latestInfo.Config.BDRMode))
+		c.ddlHistory,
+		util.GetOrZero(c.latestInfo.Config.BDRMode),
+		isBatchDDLMode(c.latestInfo))
 
 	// create scheduler
 	cfg := *c.cfg
 	cfg.ChangefeedSettings = c.latestInfo.Config.Scheduler
+	cfg.ResolvedTsBroadcastInterval = time.Duration(
+		util.GetOrZero(c.latestInfo.Config.ResolvedTsBroadcastIntervalMs)) * time.Millisecond
 	epoch := c.latestInfo.Epoch
 	c.scheduler, err = c.newScheduler(ctx, c.upstream, epoch, &cfg, c.redoMetaMgr)
 	if err != nil {
@@ -771,9 +864,11 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 		_ = c.downstreamObserver.Close()
 	}
 
+	c.initialConsistencyValidated = nil
 	c.schema = nil
 	c.barriers = nil
 	c.resolvedTs = 0
+	c.scanProgress = nil
 	c.initialized = false
 	c.isReleased = true
 
@@ -783,6 +878,70 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 		zap.Bool("isRemoved", c.isRemoved))
 }
 
+// startInitialConsistencyValidation validates the consistency of the
+// downstream at the start-ts in background. The tables are not scheduled
+// until the validation is finished, so that the downstream is not changed by
+// the replication during the validation. The mismatches and the failures of
+// the validation are reported as warnings, they don't stop the changefeed.
+func (c *changefeed) startInitialConsistencyValidation(ctx context.Context) error {
+	info, err := c.latestInfo.Clone()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	validated := atomic.NewBool(false)
+	c.initialConsistencyValidated = validated
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer validated.Store(true)
+
+		log.Info("start to validate the initial consistency of the downstream",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Uint64("startTs", info.StartTs),
+			zap.Float64("sampleRate", util.GetOrZero(info.Config.InitialConsistencySampleRate)))
+		err := func() error {
+			v, err := newInitialConsistencyValidator(
+				ctx, c.id, c.upstream.KVStorage, info, pmysql.CreateMySQLDBConn)
+			if err != nil || v == nil {
+				return err
+			}
+			defer v.Close()
+			return v.validate(ctx)
+		}()
+		if err == nil || errors.Cause(err) == context.Canceled {
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case c.warningCh <- err:
+		}
+	}()
+	return nil
+}
+
+// updateScanProgress updates the scan progress every scanProgressUpdateInterval,
+// the scan rate is calculated by the regions scanned since the last update.
+func (c *changefeed) updateScanProgress(now time.Time) {
+	if c.scanProgress != nil && now.Sub(c.lastScanProgressUpdate) < scanProgressUpdateInterval {
+		return
+	}
+	total, scanned := c.scheduler.RegionScanProgress()
+	progress := &model.ScanProgress{TotalRegions: total, ScannedRegions: scanned}
+	if c.scanProgress != nil && scanned > c.scanProgress.ScannedRegions {
+		elapsed := now.Sub(c.lastScanProgressUpdate).Seconds()
+		progress.CurrentScanRate = float64(scanned-c.scanProgress.ScannedRegions) / elapsed
+	}
+	if progress.CurrentScanRate > 0 && scanned < total {
+		remaining := float64(total-scanned) / progress.CurrentScanRate
+		completion := now.Add(time.Duration(remaining * float64(time.Second)))
+		progress.EstimatedCompletionTime = &completion
+	}
+	c.scanProgress = progress
+	c.lastScanProgressUpdate = now
+}
+
 func (c *changefeed) cleanupMetrics() {
 	changefeedCheckpointTsGauge.DeleteLabelValues(c.id.Namespace, c.id.ID)
 	changefeedCheckpointTsLagGauge.DeleteLabelValues(c.id.Namespace, c.id.ID)
@@ -1002,3 +1161,16 @@ func (c *changefeed) tickDownstreamObserver(ctx context.Context) {
 		}()
 	}
 }
+
+// isBatchDDLMode returns true if the DDLs are executed in batches, which is
+// only supported by the MySQL sink.
+func isBatchDDLMode(info *model.ChangeFeedInfo) bool {
+	sinkURI, err := url.Parse(info.SinkURI)
+	if err != nil || !sink.IsMySQLCompatibleScheme(sink.GetScheme(sinkURI)) {
+		return false
+	}
+	if info.Config.Sink == nil || info.Config.Sink.MySQLConfig == nil {
+		return false
+	}
+	return util.GetOrZero(info.Config.Sink.MySQLConfig.BatchDDLMode)
+}
@@ -124,6 +124,15 @@ func (m *mockDDLSink) emitDDLEvent(ctx context.Context, ddl *model.DDLEvent) (bo
 	return m.ddlDone, nil
 }
 
+func (m *mockDDLSink) emitDDLEvents(ctx context.Context, ddls []*model.DDLEvent) (bool, error) {
+	for _, ddl := range ddls {
+		if _, err := m.emitDDLEvent(ctx, ddl); err != nil {
+			return false, err
+		}
+	}
+	return m.ddlDone, nil
+}
+
 func (m *mockDDLSink) emitSyncPoint(ctx context.Context, checkpointTs uint64) error {
 	if checkpointTs == m.syncPoint {
 		return nil
@@ -156,7 +165,11 @@ func (m *mockDDLSink) Barrier(ctx context.Context) error {
 }
 
 type mockScheduler struct {
-	currentTables []model.TableID
+	currentTables  []model.TableID
+	totalRegions   uint64
+	scannedRegions uint64
+	scannedBytes   uint64
+	writtenBytes   uint64
 }
 
 func (m *mockScheduler) Tick(
@@ -186,6 +199,16 @@ func (m *mockScheduler) DrainCapture(target model.CaptureID) (int, error) {
 	return 0, nil
 }
 
+// RegionScanProgress implement scheduler interface
+func (m *mockScheduler) RegionScanProgress() (total, scanned uint64) {
+	return m.totalRegions, m.scannedRegions
+}
+
+// ReplicationBytes implement scheduler interface
+func (m *mockScheduler) ReplicationBytes() (scanned, written uint64) {
+	return m.scannedBytes, m.writtenBytes
+}
+
 // Close closes the scheduler and releases resources.
 func (m *mockScheduler) Close(ctx context.Context) {}
 
@@ -684,3 +707,38 @@ func TestBarrierAdvance(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateScanProgress(t *testing.T) {
+	t.Parallel()
+
+	sched := &mockScheduler{totalRegions: 100}
+	cf := &changefeed{scheduler: sched}
+	start := time.Now()
+	cf.updateScanProgress(start)
+	require.Equal(t, &model.ScanProgress{TotalRegions: 100}, cf.scanProgress)
+
+	// the progress is not updated within the interval.
+	sched.scannedRegions = 10
+	cf.updateScanProgress(start.Add(time.Second))
+	require.Equal(t, uint64(0), cf.scanProgress.ScannedRegions)
+
+	now := start.Add(scanProgressUpdateInterval)
+	cf.updateScanProgress(now)
+	require.Equal(t, uint64(10), cf.scanProgress.ScannedRegions)
+	require.Equal(t, float64(2), cf.scanProgress.CurrentScanRate)
+	require.Equal(t, now.Add(45*time.Second), *cf.scanProgress.EstimatedCompletionTime)
+
+	// the scan is finished.
+	sched.scannedRegions = 100
+	now = now.Add(scanProgressUpdateInterval)
+	cf.updateScanProgress(now)
+	require.Equal(t, uint64(100), cf.scanProgress.ScannedRegions)
+	require.Equal(t, float64(18), cf.scanProgress.CurrentScanRate)
+	require.Nil(t, cf.scanProgress.EstimatedCompletionTime)
+
+	// no region is scanned since the last update.
+	now = now.Add(scanProgressUpdateInterval)
+	cf.updateScanProgress(now)
+	require.Equal(t, float64(0), cf.scanProgress.CurrentScanRate)
+	require.Nil(t, cf.scanProgress.EstimatedCompletionTime)
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+const (
+	// maxDDLHistoryEntries is the max number of DDLs kept in memory.
+	maxDDLHistoryEntries = 100
+	// maxPersistedDDLHistoryEntries is the max number of DDLs persisted in
+	// the changefeed status, which are used to recover the history after
+	// the owner restarts.
+	maxPersistedDDLHistoryEntries = 20
+)
+
+// ddlHistory records the recently executed DDLs of a changefeed, it is only
+// used in API to help operators to investigate replication issues.
+type ddlHistory struct {
+	entries []*model.DDLHistoryEntry
+}
+
+func newDDLHistory() *ddlHistory {
+	return &ddlHistory{}
+}
+
+// add records a DDL executed at the given time.
+func (h *ddlHistory) add(ddl *model.DDLEvent, executedAt time.Time) {
+	entry := &model.DDLHistoryEntry{
+		ExecutedAt:     executedAt,
+		CommitTs:       ddl.CommitTs,
+		Query:          ddl.Query,
+		AffectedTables: affectedTables(ddl),
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxDDLHistoryEntries {
+		// Copy to a new slice to release the removed entries.
+		h.entries = append([]*model.DDLHistoryEntry(nil),
+			h.entries[len(h.entries)-maxDDLHistoryEntries:]...)
+	}
+}
+
+// recover restores the history from the persisted entries if nothing is
+// recorded yet, e.g. the changefeed is just created by a new owner.
+func (h *ddlHistory) recover(persisted []*model.DDLHistoryEntry) {
+	if len(h.entries) != 0 || len(persisted) == 0 {
+		return
+	}
+	h.entries = append([]*model.DDLHistoryEntry(nil), persisted...)
+}
+
+// persisted returns the last entries which should be persisted.
+func (h *ddlHistory) persisted() []*model.DDLHistoryEntry {
+	if len(h.entries) <= maxPersistedDDLHistoryEntries {
+		return h.entries
+	}
+	return h.entries[len(h.entries)-maxPersistedDDLHistoryEntries:]
+}
+
+// list returns a copy of all entries, ordered by the executed time.
+func (h *ddlHistory) list() []*model.DDLHistoryEntry {
+	return append([]*model.DDLHistoryEntry(nil), h.entries...)
+}
+
+// affectedTables returns the full names of the tables changed by the DDL.
+func affectedTables(ddl *model.DDLEvent) []string {
+	var tables []string
+	if ddl.PreTableInfo != nil && ddl.PreTableInfo.TableName.Table != "" {
+		tables = append(tables, ddl.PreTableInfo.TableName.String())
+	}
+	if ddl.TableInfo != nil && ddl.TableInfo.TableName.Table != "" {
+		name := ddl.TableInfo.TableName.String()
+		if len(tables) == 0 || tables[0] != name {
+			tables = append(tables, name)
+		}
+	}
+	return tables
+}
+
+// ddlHistoryEqual returns true if the two histories are the same.
+func ddlHistoryEqual(a, b []*model.DDLHistoryEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].CommitTs != b[i].CommitTs || a[i].Query != b[i].Query ||
+			!a[i].ExecutedAt.Equal(b[i].ExecutedAt) {
+			return false
+		}
+	}
+	return true
+}
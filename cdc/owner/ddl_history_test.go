@@ -0,0 +1,116 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLHistory(t *testing.T) {
+	dm := createDDLManagerForTest(t)
+	dm.ddlSink.(*mockDDLSink).ddlDone = true
+
+	// Replay 5 DDLs, the last one renames test_4 to test_5.
+	for i := 0; i < 5; i++ {
+		ddl := newFakeDDLEvent(int64(i), fmt.Sprintf("test_%d", i),
+			timodel.ActionAddColumn, uint64(i+1))
+		ddl.TableInfo.TableName.Schema = "test"
+		ddl.Query = fmt.Sprintf("ALTER TABLE test.test_%d ADD COLUMN c INT", i)
+		if i == 4 {
+			ddl.Type = timodel.ActionRenameTable
+			ddl.Query = "RENAME TABLE test.test_4 TO test.test_5"
+			ddl.PreTableInfo = ddl.TableInfo
+			ddl.TableInfo = &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: "test_5", TableID: 4},
+			}
+		}
+		dm.pendingDDLs[ddl.TableInfo.TableName] = []*model.DDLEvent{ddl}
+		dm.executingDDL = ddl
+		require.NoError(t, dm.executeDDL(context.Background()))
+		require.Nil(t, dm.executingDDL)
+	}
+
+	history := dm.ddlHistory.list()
+	require.Len(t, history, 5)
+	for i, entry := range history {
+		require.Equal(t, uint64(i+1), entry.CommitTs)
+		require.False(t, entry.ExecutedAt.IsZero())
+		if i > 0 {
+			require.False(t, entry.ExecutedAt.Before(history[i-1].ExecutedAt))
+		}
+	}
+	require.Equal(t, "ALTER TABLE test.test_0 ADD COLUMN c INT", history[0].Query)
+	require.Equal(t, []string{"test.test_0"}, history[0].AffectedTables)
+	require.Equal(t, "RENAME TABLE test.test_4 TO test.test_5", history[4].Query)
+	require.Equal(t, []string{"test.test_4", "test.test_5"}, history[4].AffectedTables)
+
+	// The history is capped.
+	h := newDDLHistory()
+	for i := 0; i < maxDDLHistoryEntries+10; i++ {
+		h.add(&model.DDLEvent{CommitTs: uint64(i)}, time.Now())
+	}
+	require.Len(t, h.list(), maxDDLHistoryEntries)
+	require.Equal(t, uint64(10), h.list()[0].CommitTs)
+	require.Len(t, h.persisted(), maxPersistedDDLHistoryEntries)
+	require.Equal(t, uint64(maxDDLHistoryEntries+9),
+		h.persisted()[maxPersistedDDLHistoryEntries-1].CommitTs)
+}
+
+func TestPersistDDLHistory(t *testing.T) {
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID("test"))
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		return &model.ChangeFeedStatus{CheckpointTs: 1, MinTableBarrierTs: 1}, true, nil
+	})
+	tester.MustApplyPatches()
+
+	// An empty history does not overwrite the persisted one.
+	h := newDDLHistory()
+	updateDDLHistory(state, h.persisted())
+	tester.MustApplyPatches()
+	require.Empty(t, state.Status.DDLHistory)
+
+	executedAt := time.Now()
+	for i := 0; i < 30; i++ {
+		h.add(&model.DDLEvent{
+			CommitTs: uint64(i + 1),
+			Query:    fmt.Sprintf("CREATE TABLE test.t%d (id INT PRIMARY KEY)", i),
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: fmt.Sprintf("t%d", i)},
+			},
+		}, executedAt.Add(time.Duration(i)*time.Second))
+	}
+	updateDDLHistory(state, h.persisted())
+	tester.MustApplyPatches()
+	require.Len(t, state.Status.DDLHistory, maxPersistedDDLHistoryEntries)
+	require.Equal(t, uint64(11), state.Status.DDLHistory[0].CommitTs)
+	require.Equal(t, uint64(1), state.Status.CheckpointTs)
+
+	// Recover the history after the owner restarts.
+	recovered := newDDLHistory()
+	recovered.recover(state.Status.DDLHistory)
+	require.True(t, ddlHistoryEqual(h.persisted(), recovered.list()))
+	require.Equal(t, []string{"test.t29"},
+		recovered.list()[maxPersistedDDLHistoryEntries-1].AffectedTables)
+}
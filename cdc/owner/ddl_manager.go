@@ -119,6 +119,9 @@ type ddlManager struct {
 	// executingDDL is the ddl that is currently being executed,
 	// it is nil if there is no ddl being executed.
 	executingDDL *model.DDLEvent
+	// executingBatch is the DDLs executed together with executingDDL in
+	// batch DDL mode, executingDDL is the first one.
+	executingBatch []*model.DDLEvent
 	// justSentDDL is the ddl that just be sent to the downstream in the current tick.
 	// we need it to prevent the checkpointTs from advancing in the same tick.
 	justSentDDL *model.DDLEvent
@@ -127,9 +130,13 @@ type ddlManager struct {
 	// The ones that have not been executed yet do not have.
 	tableInfoCache      []*model.TableInfo
 	physicalTablesCache []model.TableID
+	// ddlHistory records the DDLs executed to downstream successfully.
+	ddlHistory *ddlHistory
 
 	BDRMode       bool
 	ddlResolvedTs model.Ts
+	// batchDDLMode executes the consecutive `CREATE TABLE` DDLs in a batch.
+	batchDDLMode bool
 }
 
 func newDDLManager(
@@ -142,14 +149,17 @@ func newDDLManager(
 	schema entry.SchemaStorage,
 	redoManager redo.DDLManager,
 	redoMetaManager redo.MetaManager,
+	ddlHistory *ddlHistory,
 	bdrMode bool,
+	batchDDLMode bool,
 ) *ddlManager {
 	log.Info("owner create ddl manager",
 		zap.String("namespace", changefeedID.Namespace),
 		zap.String("changefeed", changefeedID.ID),
 		zap.Uint64("startTs", startTs),
 		zap.Uint64("checkpointTs", checkpointTs),
-		zap.Bool("bdrMode", bdrMode))
+		zap.Bool("bdrMode", bdrMode),
+		zap.Bool("batchDDLMode", batchDDLMode))
 
 	return &ddlManager{
 		changfeedID:     changefeedID,
@@ -159,10 +169,12 @@ func newDDLManager(
 		schema:          schema,
 		redoDDLManager:  redoManager,
 		redoMetaManager: redoMetaManager,
+		ddlHistory:      ddlHistory,
 		startTs:         startTs,
 		checkpointTs:    checkpointTs,
 		ddlResolvedTs:   startTs,
 		BDRMode:         bdrMode,
+		batchDDLMode:    batchDDLMode,
 		pendingDDLs:     make(map[model.TableName][]*model.DDLEvent),
 	}
 }
@@ -383,16 +395,70 @@ func (m *ddlManager) executeDDL(ctx context.Context) error {
 		time.Sleep(lag)
 	})
 
-	done, err := m.ddlSink.emitDDLEvent(ctx, m.executingDDL)
+	if m.batchDDLMode && m.executingBatch == nil && !m.executingDDL.Done.Load() {
+		m.executingBatch, err = m.getBatchDDLs()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	var done bool
+	if len(m.executingBatch) > 1 {
+		done, err = m.ddlSink.emitDDLEvents(ctx, m.executingBatch)
+	} else {
+		done, err = m.ddlSink.emitDDLEvent(ctx, m.executingDDL)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
 	if done {
+		m.ddlHistory.add(m.executingDDL, time.Now())
 		m.cleanCache("execute a ddl event successfully")
 	}
 	return nil
 }
 
+// getBatchDDLs returns the executing DDL and the consecutive `CREATE TABLE`
+// DDLs following it, which are executed together in batch DDL mode. It's safe
+// to create the tables in advance since no other DDLs are in between. The DDLs
+// executed in advance are kept in pendingDDLs until the checkpointTs reaches
+// them, so the barriers are not affected, and they are skipped by the ddlSink
+// as they are done already.
+func (m *ddlManager) getBatchDDLs() ([]*model.DDLEvent, error) {
+	batch := []*model.DDLEvent{m.executingDDL}
+	if m.executingDDL.Type != timodel.ActionCreateTable {
+		return batch, nil
+	}
+	pending := make([]*model.DDLEvent, 0)
+	for _, ddls := range m.pendingDDLs {
+		for _, ddl := range ddls {
+			if ddl != m.executingDDL {
+				pending = append(pending, ddl)
+			}
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CommitTs < pending[j].CommitTs
+	})
+	for _, ddl := range pending {
+		// The DDLs are not written to the redo log yet.
+		if ddl.CommitTs > m.ddlResolvedTs {
+			break
+		}
+		skip, _, err := m.shouldSkipDDL(ddl)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if skip || ddl.Done.Load() {
+			continue
+		}
+		if ddl.Type != timodel.ActionCreateTable {
+			break
+		}
+		batch = append(batch, ddl)
+	}
+	return batch, nil
+}
+
 // getNextDDL returns the next ddl event to execute.
 func (m *ddlManager) getNextDDL() *model.DDLEvent {
 	if m.executingDDL != nil {
@@ -584,6 +650,7 @@ func (m *ddlManager) cleanCache(msg string) {
 	m.schema.DoGC(m.executingDDL.CommitTs - 1)
 	m.justSentDDL = m.executingDDL
 	m.executingDDL = nil
+	m.executingBatch = nil
 
 	m.tableInfoCache = nil
 	m.physicalTablesCache = nil
@@ -50,6 +50,8 @@ func createDDLManagerForTest(t *testing.T) *ddlManager {
 		schema,
 		redo.NewDisabledDDLManager(),
 		redo.NewDisabledMetaManager(),
+		newDDLHistory(),
+		false,
 		false)
 	return res
 }
@@ -92,6 +94,46 @@ func TestGetNextDDL(t *testing.T) {
 	require.Equal(t, ddl1, dm.getNextDDL())
 }
 
+func TestGetBatchDDLs(t *testing.T) {
+	dm := createDDLManagerForTest(t)
+	dm.batchDDLMode = true
+	dm.ddlResolvedTs = 10
+
+	createT1 := newFakeDDLEvent(1, "t1", timodel.ActionCreateTable, 2)
+	createT2 := newFakeDDLEvent(2, "t2", timodel.ActionCreateTable, 3)
+	createT3 := newFakeDDLEvent(3, "t3", timodel.ActionCreateTable, 4)
+	addColumn := newFakeDDLEvent(1, "t1", timodel.ActionAddColumn, 5)
+	createT4 := newFakeDDLEvent(4, "t4", timodel.ActionCreateTable, 6)
+	for _, ddl := range []*model.DDLEvent{createT1, createT2, createT3, addColumn, createT4} {
+		tableName := ddl.TableInfo.TableName
+		dm.pendingDDLs[tableName] = append(dm.pendingDDLs[tableName], ddl)
+	}
+
+	// The DDLs after the ADD COLUMN are not executed in advance.
+	dm.executingDDL = createT1
+	batch, err := dm.getBatchDDLs()
+	require.NoError(t, err)
+	require.Equal(t, []*model.DDLEvent{createT1, createT2, createT3}, batch)
+
+	// The DDLs which are not written to the redo log are not executed in advance.
+	dm.ddlResolvedTs = 3
+	batch, err = dm.getBatchDDLs()
+	require.NoError(t, err)
+	require.Equal(t, []*model.DDLEvent{createT1, createT2}, batch)
+
+	// The DDLs which are done already are skipped.
+	dm.ddlResolvedTs = 10
+	createT2.Done.Store(true)
+	batch, err = dm.getBatchDDLs()
+	require.NoError(t, err)
+	require.Equal(t, []*model.DDLEvent{createT1, createT3}, batch)
+
+	dm.executingDDL = addColumn
+	batch, err = dm.getBatchDDLs()
+	require.NoError(t, err)
+	require.Equal(t, []*model.DDLEvent{addColumn}, batch)
+}
+
 func TestBarriers(t *testing.T) {
 	dm := createDDLManagerForTest(t)
 
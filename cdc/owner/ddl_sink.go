@@ -53,6 +53,9 @@ type DDLSink interface {
 	// the DDL event will be sent to another goroutine and execute to downstream
 	// the caller of this function can call again and again until a true returned
 	emitDDLEvent(ctx context.Context, ddl *model.DDLEvent) (bool, error)
+	// emitDDLEvents is like emitDDLEvent, but emits the DDL events committed
+	// at the same time in a batch, true is returned if all of them are executed.
+	emitDDLEvents(ctx context.Context, ddls []*model.DDLEvent) (bool, error)
 	emitSyncPoint(ctx context.Context, checkpointTs uint64) error
 	// close the ddlsink, cancel running goroutine.
 	close(ctx context.Context) error
@@ -72,7 +75,7 @@ type ddlSinkImpl struct {
 	// sent to `ddlCh` successfully.
 	ddlSentTsMap map[*model.DDLEvent]model.Ts
 
-	ddlCh chan *model.DDLEvent
+	ddlCh chan []*model.DDLEvent
 
 	sink ddlsink.Sink
 	// `sinkInitHandler` can be helpful in unit testing.
@@ -96,7 +99,7 @@ func newDDLSink(
 ) DDLSink {
 	res := &ddlSinkImpl{
 		ddlSentTsMap:    make(map[*model.DDLEvent]uint64),
-		ddlCh:           make(chan *model.DDLEvent, 1),
+		ddlCh:           make(chan []*model.DDLEvent, 1),
 		sinkInitHandler: ddlSinkInitializer,
 		cancel:          func() {},
 
@@ -235,6 +238,39 @@ func (s *ddlSinkImpl) writeCheckpointTs(ctx context.Context, lastCheckpointTs *m
 	return s.observedRetrySinkAction(ctx, "writeCheckpointTs", doWrite)
 }
 
+// writeHeartbeat writes a heartbeat event carrying the last emitted checkpoint ts,
+// so that the downstream can tell the changefeed is alive even if it's idle.
+func (s *ddlSinkImpl) writeHeartbeat(ctx context.Context, lastCheckpointTs model.Ts) error {
+	if lastCheckpointTs == 0 {
+		return nil
+	}
+	doWrite := func() (err error) {
+		s.mu.Lock()
+		tables := make([]*model.TableInfo, 0, len(s.mu.currentTables))
+		tables = append(tables, s.mu.currentTables...)
+		s.mu.Unlock()
+
+		if err = s.makeSinkReady(ctx); err != nil {
+			return
+		}
+		if heartbeatSink, ok := s.sink.(ddlsink.HeartbeatSink); ok {
+			err = heartbeatSink.WriteHeartbeat(ctx, lastCheckpointTs, tables)
+		}
+		return
+	}
+
+	return s.observedRetrySinkAction(ctx, "writeHeartbeat", doWrite)
+}
+
+// heartbeatInterval returns the interval to emit heartbeat events, 0 means
+// heartbeat is disabled.
+func (s *ddlSinkImpl) heartbeatInterval() time.Duration {
+	if s.info.Config == nil || s.info.Config.Sink == nil {
+		return 0
+	}
+	return time.Duration(util.GetOrZero(s.info.Config.Sink.HeartbeatIntervalMs)) * time.Millisecond
+}
+
 func (s *ddlSinkImpl) writeDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	log.Info("begin emit ddl event",
 		zap.String("namespace", s.changefeedID.Namespace),
@@ -267,6 +303,58 @@ func (s *ddlSinkImpl) writeDDLEvent(ctx context.Context, ddl *model.DDLEvent) er
 	return s.observedRetrySinkAction(ctx, "writeDDLEvent", doWrite)
 }
 
+// writeDDLEvents writes the DDL events in a batch if the sink supports it,
+// otherwise they are written one by one.
+func (s *ddlSinkImpl) writeDDLEvents(ctx context.Context, ddls []*model.DDLEvent) error {
+	if len(ddls) == 1 {
+		return s.writeDDLEvent(ctx, ddls[0])
+	}
+	log.Info("begin emit ddl events in a batch",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Int("count", len(ddls)),
+		zap.Uint64("commitTs", ddls[0].CommitTs))
+
+	doWrite := func() (err error) {
+		if err = s.makeSinkReady(ctx); err == nil {
+			if batchSink, ok := s.sink.(ddlsink.BatchDDLSink); ok {
+				err = batchSink.WriteDDLEvents(ctx, ddls)
+			} else {
+				// The DDLs written successfully are skipped when retrying.
+				for _, ddl := range ddls {
+					if ddl.Done.Load() {
+						continue
+					}
+					if err = s.sink.WriteDDLEvent(ctx, ddl); err != nil {
+						break
+					}
+					ddl.Done.Store(true)
+				}
+			}
+		}
+		if err != nil {
+			log.Error("Execute DDLs in a batch failed",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Int("count", len(ddls)),
+				zap.Uint64("commitTs", ddls[0].CommitTs),
+				zap.Error(err))
+		} else {
+			for _, ddl := range ddls {
+				ddl.Done.Store(true)
+			}
+			log.Info("Execute DDLs in a batch succeeded",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Int("count", len(ddls)),
+				zap.Uint64("commitTs", ddls[0].CommitTs))
+		}
+		return
+	}
+
+	return s.observedRetrySinkAction(ctx, "writeDDLEvents", doWrite)
+}
+
 func (s *ddlSinkImpl) run(ctx context.Context) {
 	ctx, s.cancel = context.WithCancel(ctx)
 
@@ -287,6 +375,12 @@ func (s *ddlSinkImpl) run(ctx context.Context) {
 		// TODO make the tick duration configurable
 		ticker := time.NewTicker(time.Second)
 		defer ticker.Stop()
+		var heartbeatCh <-chan time.Time
+		if interval := s.heartbeatInterval(); interval > 0 {
+			heartbeatTicker := time.NewTicker(interval)
+			defer heartbeatTicker.Stop()
+			heartbeatCh = heartbeatTicker.C
+		}
 		var lastCheckpointTs model.Ts
 		for {
 			// `ticker.C` and `ddlCh` may can be triggered at the same time, it
@@ -300,8 +394,12 @@ func (s *ddlSinkImpl) run(ctx context.Context) {
 				if err = s.writeCheckpointTs(ctx, &lastCheckpointTs); err != nil {
 					return
 				}
-			case ddl := <-s.ddlCh:
-				if err = s.writeDDLEvent(ctx, ddl); err != nil {
+			case <-heartbeatCh:
+				if err = s.writeHeartbeat(ctx, lastCheckpointTs); err != nil {
+					return
+				}
+			case ddls := <-s.ddlCh:
+				if err = s.writeDDLEvents(ctx, ddls); err != nil {
 					return
 				}
 				// Force emitting checkpoint ts when a ddl event is finished.
@@ -326,63 +424,85 @@ func (s *ddlSinkImpl) emitCheckpointTs(ts uint64, tables []*model.TableInfo) {
 // and CommitTs. So in emitDDLEvent, we get the DDL finished ts of an event
 // from a map in order to check whether that event is finished or not.
 func (s *ddlSinkImpl) emitDDLEvent(ctx context.Context, ddl *model.DDLEvent) (bool, error) {
+	return s.emitDDLEvents(ctx, []*model.DDLEvent{ddl})
+}
+
+// emitDDLEvents returns true if all the ddl events are already executed.
+// The ddl events are sent to `ddlCh` together and executed in a batch.
+func (s *ddlSinkImpl) emitDDLEvents(ctx context.Context, ddls []*model.DDLEvent) (bool, error) {
 	s.mu.Lock()
-	if ddl.Done.Load() {
-		// the DDL event is executed successfully, and done is true
-		log.Info("ddl already executed, skip it",
-			zap.String("namespace", s.changefeedID.Namespace),
-			zap.String("changefeed", s.changefeedID.ID),
-			zap.Any("DDL", ddl))
-		delete(s.ddlSentTsMap, ddl)
+	if allDDLsDone(ddls) {
+		// the DDL events are executed successfully, and done is true
+		for _, ddl := range ddls {
+			log.Info("ddl already executed, skip it",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Any("DDL", ddl))
+			delete(s.ddlSentTsMap, ddl)
+		}
 		s.mu.Unlock()
 		return true, nil
 	}
 
-	ddlSentTs := s.ddlSentTsMap[ddl]
-	if ddl.CommitTs <= ddlSentTs {
+	ddlSentTs := s.ddlSentTsMap[ddls[0]]
+	if ddls[0].CommitTs <= ddlSentTs {
 		log.Debug("ddl is not finished yet",
 			zap.String("namespace", s.changefeedID.Namespace),
 			zap.String("changefeed", s.changefeedID.ID),
-			zap.Uint64("ddlSentTs", ddlSentTs), zap.Any("DDL", ddl))
+			zap.Uint64("ddlSentTs", ddlSentTs), zap.Any("DDL", ddls[0]))
 		// the DDL event is executing and not finished yet, return false
 		s.mu.Unlock()
 		return false, nil
 	}
 
-	query, err := s.addSpecialComment(ddl)
-	if err != nil {
-		log.Error("Add special comment failed",
-			zap.String("namespace", s.changefeedID.Namespace),
-			zap.String("changefeed", s.changefeedID.ID),
-			zap.Error(err),
-			zap.Any("ddl", ddl))
-		s.mu.Unlock()
-		return false, errors.Trace(err)
+	for _, ddl := range ddls {
+		query, err := s.addSpecialComment(ddl)
+		if err != nil {
+			log.Error("Add special comment failed",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Error(err),
+				zap.Any("ddl", ddl))
+			s.mu.Unlock()
+			return false, errors.Trace(err)
+		}
+		ddl.Query = query
 	}
-	ddl.Query = query
 	s.mu.Unlock()
 
 	select {
 	case <-ctx.Done():
 		return false, errors.Trace(ctx.Err())
-	case s.ddlCh <- ddl:
-		s.ddlSentTsMap[ddl] = ddl.CommitTs
+	case s.ddlCh <- ddls:
+		for _, ddl := range ddls {
+			s.ddlSentTsMap[ddl] = ddl.CommitTs
+		}
 		log.Info("ddl is sent",
 			zap.String("namespace", s.changefeedID.Namespace),
 			zap.String("changefeed", s.changefeedID.ID),
-			zap.Uint64("ddlSentTs", ddl.CommitTs))
+			zap.Int("count", len(ddls)),
+			zap.Uint64("ddlSentTs", ddls[0].CommitTs))
 	default:
 		log.Warn("ddl chan full, send it the next round",
 			zap.String("namespace", s.changefeedID.Namespace),
 			zap.String("changefeed", s.changefeedID.ID),
 			zap.Uint64("ddlSentTs", ddlSentTs),
-			zap.Any("DDL", ddl))
+			zap.Any("DDL", ddls[0]))
 		// if this hit, we think that ddlCh is full,
 		// just return false and send the ddl in the next round.
 	}
 	return false, nil
 }
 
+func allDDLsDone(ddls []*model.DDLEvent) bool {
+	for _, ddl := range ddls {
+		if !ddl.Done.Load() {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *ddlSinkImpl) emitSyncPoint(ctx context.Context, checkpointTs uint64) (err error) {
 	if checkpointTs == s.lastSyncPoint {
 		return nil
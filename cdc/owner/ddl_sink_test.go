@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -100,6 +101,59 @@ func TestCheckpoint(t *testing.T) {
 	require.Nil(t, waitCheckpointGrowingUp(mSink, 10))
 }
 
+type mockHeartbeatSink struct {
+	*mockSink
+	heartbeatTs    model.Ts
+	heartbeatCount int64
+}
+
+func (m *mockHeartbeatSink) WriteHeartbeat(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	atomic.StoreUint64(&m.heartbeatTs, ts)
+	atomic.AddInt64(&m.heartbeatCount, 1)
+	return nil
+}
+
+func TestHeartbeat(t *testing.T) {
+	ddlSink, mSink := newDDLSink4Test(func(err error) {}, func(err error) {})
+	ddlSink.(*ddlSinkImpl).info.Config.Sink.HeartbeatIntervalMs = util.AddressOf(uint64(100))
+	hSink := &mockHeartbeatSink{mockSink: mSink}
+	ddlSink.(*ddlSinkImpl).sinkInitHandler = func(ctx context.Context, s *ddlSinkImpl) error {
+		s.sink = hSink
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx)
+
+	// No heartbeat is emitted before the first checkpoint.
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, int64(0), atomic.LoadInt64(&hSink.heartbeatCount))
+
+	ddlSink.emitCheckpointTs(1, nil)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint64(&mSink.checkpointTs) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// The changefeed is idle, heartbeats keep being emitted.
+	count := atomic.LoadInt64(&hSink.heartbeatCount)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&hSink.heartbeatCount) >= count+3
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, uint64(1), atomic.LoadUint64(&hSink.heartbeatTs))
+
+	// Heartbeats carry the latest checkpoint ts.
+	ddlSink.emitCheckpointTs(10, nil)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint64(&hSink.heartbeatTs) == 10
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
 func TestExecDDLEvents(t *testing.T) {
 	ddlSink, mSink := newDDLSink4Test(func(err error) {}, func(err error) {})
 
@@ -128,6 +182,61 @@ func TestExecDDLEvents(t *testing.T) {
 	}
 }
 
+type mockBatchSink struct {
+	*mockSink
+	batches [][]*model.DDLEvent
+}
+
+func (m *mockBatchSink) WriteDDLEvents(ctx context.Context, ddls []*model.DDLEvent) error {
+	m.ddlMu.Lock()
+	defer m.ddlMu.Unlock()
+	m.batches = append(m.batches, ddls)
+	return nil
+}
+
+func (m *mockBatchSink) GetBatches() [][]*model.DDLEvent {
+	m.ddlMu.Lock()
+	defer m.ddlMu.Unlock()
+	return m.batches
+}
+
+func TestExecDDLEventsInBatch(t *testing.T) {
+	ddlSink, mSink := newDDLSink4Test(func(err error) {}, func(err error) {})
+	bSink := &mockBatchSink{mockSink: mSink}
+	ddlSink.(*ddlSinkImpl).sinkInitHandler = func(ctx context.Context, s *ddlSinkImpl) error {
+		s.sink = bSink
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx)
+
+	ddlEvents := []*model.DDLEvent{
+		{CommitTs: 1, Query: "create table t1(id int)"},
+		{CommitTs: 2, Query: "create table t2(id int)"},
+		{CommitTs: 3, Query: "create table t3(id int)"},
+	}
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvents(ctx, ddlEvents)
+		require.Nil(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, [][]*model.DDLEvent{ddlEvents}, bSink.GetBatches())
+	require.Nil(t, mSink.GetDDL())
+
+	// The DDLs executed in the batch are not executed again.
+	for _, event := range ddlEvents[1:] {
+		done, err := ddlSink.emitDDLEvent(ctx, event)
+		require.Nil(t, err)
+		require.True(t, done)
+	}
+	require.Len(t, bSink.GetBatches(), 1)
+}
+
 func TestExecDDLError(t *testing.T) {
 	var (
 		resultErr   error
@@ -27,6 +27,8 @@ type ChangefeedState interface {
 	RemoveChangefeed()
 	// ResumeChnagefeed resumes the changefeed and set the checkpoint ts.
 	ResumeChnagefeed(uint64)
+	// ResetChangefeed restarts the changefeed from the start ts with the epoch.
+	ResetChangefeed(uint64, uint64)
 	// SetWarning sets the warning to changefeed
 	SetWarning(*model.RunningError)
 	// TakeProcessorWarnings reuturns the warning of the changefeed and clean the warning.
@@ -252,7 +252,7 @@ func (m *feedStateManager) MarkFinished() {
 
 func (m *feedStateManager) PushAdminJob(job *model.AdminJob) {
 	switch job.Type {
-	case model.AdminStop, model.AdminResume, model.AdminRemove:
+	case model.AdminStop, model.AdminResume, model.AdminRemove, model.AdminReset:
 	default:
 		log.Panic("Can not handle this job",
 			zap.String("namespace", m.state.GetID().Namespace),
@@ -314,6 +314,28 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.patchState(model.StateNormal)
 		m.state.ResumeChnagefeed(job.OverwriteCheckpointTs)
 
+	case model.AdminReset:
+		switch m.state.GetChangefeedInfo().State {
+		case model.StateNormal, model.StateWarning, model.StatePending,
+			model.StateFailed, model.StateStopped, model.StateFinished:
+		default:
+			log.Warn("can not reset the changefeed in the current state",
+				zap.String("namespace", m.state.GetID().Namespace),
+				zap.String("changefeed", m.state.GetID().ID),
+				zap.String("changefeedState", string(m.state.GetChangefeedInfo().State)), zap.Any("job", job))
+			return
+		}
+		// The running changefeed is released in this tick, and it's started
+		// from the start-ts in the next tick with a new epoch.
+		m.shouldBeRunning = false
+		m.resetErrRetry()
+		m.isRetrying = false
+		jobsPending = true
+		ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+		defer cancel()
+		m.state.ResetChangefeed(job.OverwriteCheckpointTs,
+			GenerateChangefeedEpoch(ctx, m.upstream.PDClient))
+
 	case model.AdminFinish:
 		switch m.state.GetChangefeedInfo().State {
 		case model.StateNormal, model.StateWarning:
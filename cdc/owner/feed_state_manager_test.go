@@ -228,6 +228,72 @@ func TestResumeChangefeedWithCheckpointTs(t *testing.T) {
 	require.False(t, manager.isRetrying)
 }
 
+func TestResetChangefeed(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", StartTs: 50, Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{CheckpointTs: 300}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.state = state
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	epoch := state.Info.Epoch
+
+	// reset a running changefeed, it's released and restarted from the
+	// start-ts without being stopped.
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:                  ctx.ChangefeedVars().ID,
+		Type:                  model.AdminReset,
+		OverwriteCheckpointTs: 100,
+	})
+	require.True(t, manager.Tick(0, state.Status, state.Info))
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, model.AdminNone, state.Info.AdminJobType)
+	require.Equal(t, uint64(100), state.Info.StartTs)
+	require.NotEqual(t, epoch, state.Info.Epoch)
+	require.Equal(t, uint64(100), state.Status.CheckpointTs)
+	require.Equal(t, uint64(100), state.Status.MinTableBarrierTs)
+
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+
+	// reset a failed changefeed, the error is cleared.
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		info.State = model.StateFailed
+		info.AdminJobType = model.AdminStop
+		info.Error = &model.RunningError{Code: "CDC:ErrStartTsBeforeGC"}
+		return info, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:                  ctx.ChangefeedVars().ID,
+		Type:                  model.AdminReset,
+		OverwriteCheckpointTs: 200,
+	})
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Nil(t, state.Info.Error)
+	require.Equal(t, uint64(200), state.Info.StartTs)
+	require.Equal(t, uint64(200), state.Status.CheckpointTs)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+}
+
 func TestMarkFinished(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
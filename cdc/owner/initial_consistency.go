@@ -0,0 +1,355 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	pfilter "github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxSampledRowsPerTable limits the rows sampled in a table, so that the
+	// validation of large tables is finished in a reasonable time.
+	maxSampledRowsPerTable = 1000
+	// maxLoggedMismatches limits the mismatches printed in logs.
+	maxLoggedMismatches = 100
+)
+
+// initialConsistencyValidator validates whether the downstream is consistent
+// with the upstream at the start-ts of a new changefeed, e.g. the downstream
+// is restored from a dump which is not taken at the start-ts. It samples the
+// rows of each replicated table from a snapshot of the upstream at the
+// start-ts, and compares them with the rows of the same handle keys in the
+// downstream.
+type initialConsistencyValidator struct {
+	changefeedID model.ChangeFeedID
+	startTs      model.Ts
+	sampleRate   float64
+
+	storage tidbkv.Storage
+	schema  entry.SchemaStorage
+	mounter entry.Mounter
+	db      *sql.DB
+}
+
+// newInitialConsistencyValidator creates a validator for the changefeed.
+// It returns nil if the downstream is not MySQL compatible.
+func newInitialConsistencyValidator(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	storage tidbkv.Storage,
+	info *model.ChangeFeedInfo,
+	dbConnFactory pmysql.Factory,
+) (*initialConsistencyValidator, error) {
+	sinkURI, err := url.Parse(info.SinkURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMySQLCompatibleScheme(sink.GetScheme(sinkURI)) {
+		log.Warn("initial consistency validation is only available for MySQL compatible sinks, skip it",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID))
+		return nil, nil
+	}
+
+	cfg := pmysql.NewConfig()
+	err = cfg.Apply(config.GetGlobalServerConfig().TZ, changefeedID, sinkURI, info.Config)
+	if err != nil {
+		return nil, err
+	}
+	dsnStr, err := pmysql.GenerateDSN(ctx, sinkURI, cfg, dbConnFactory)
+	if err != nil {
+		return nil, err
+	}
+	db, err := dbConnFactory(ctx, dsnStr)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(1)
+	db.SetMaxOpenConns(1)
+
+	v, err := newInitialConsistencyValidatorWithDB(changefeedID, storage, info, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+func newInitialConsistencyValidatorWithDB(
+	changefeedID model.ChangeFeedID,
+	storage tidbkv.Storage,
+	info *model.ChangeFeedInfo,
+	db *sql.DB,
+) (*initialConsistencyValidator, error) {
+	filter, err := pfilter.NewFilter(info.Config, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The rows committed at startTs are not replicated, so they are read
+	// from the snapshot at startTs with the schema at startTs-1, which is the
+	// same as the mounter decoding the rows committed at startTs.
+	schemaTs := info.StartTs - 1
+	schema, err := entry.NewSchemaStorage(storage, schemaTs,
+		info.Config.ForceReplicate, changefeedID, util.RoleOwner, filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tz, err := util.GetTimezone(config.GetGlobalServerConfig().TZ)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	sampleRate := util.GetOrZero(info.Config.InitialConsistencySampleRate)
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &initialConsistencyValidator{
+		changefeedID: changefeedID,
+		startTs:      info.StartTs,
+		sampleRate:   sampleRate,
+		storage:      storage,
+		schema:       schema,
+		mounter:      entry.NewMounter(schema, changefeedID, tz, filter, info.Config.Integrity),
+		db:           db,
+	}, nil
+}
+
+// validate samples the rows of all the replicated tables and compares them
+// with the downstream. It returns an ErrInitialConsistencyMismatch error if
+// any sampled row is missing or different in the downstream.
+func (v *initialConsistencyValidator) validate(ctx context.Context) error {
+	start := time.Now()
+	tables, err := v.schema.AllTables(ctx, v.startTs-1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	snap := v.storage.GetSnapshot(tidbkv.NewVersion(v.startTs))
+
+	var sampled int
+	var mismatches []string
+	for _, table := range tables {
+		n, ms, err := v.validateTable(ctx, snap, table)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sampled += n
+		mismatches = append(mismatches, ms...)
+	}
+
+	log.Info("initial consistency validation finished",
+		zap.String("namespace", v.changefeedID.Namespace),
+		zap.String("changefeed", v.changefeedID.ID),
+		zap.Uint64("startTs", v.startTs),
+		zap.Int("tables", len(tables)),
+		zap.Int("sampledRows", sampled),
+		zap.Int("mismatchedRows", len(mismatches)),
+		zap.Duration("duration", time.Since(start)))
+	if len(mismatches) == 0 {
+		return nil
+	}
+	for i, mismatch := range mismatches {
+		if i >= maxLoggedMismatches {
+			break
+		}
+		log.Warn("the sampled row is inconsistent with the downstream",
+			zap.String("namespace", v.changefeedID.Namespace),
+			zap.String("changefeed", v.changefeedID.ID),
+			zap.String("mismatch", mismatch))
+	}
+	return cerror.ErrInitialConsistencyMismatch.GenWithStackByArgs(
+		v.startTs, len(mismatches), sampled, mismatches[0])
+}
+
+// validateTable samples the rows of a table and returns the number of the
+// sampled rows and the descriptions of the mismatched ones. The tables
+// without handle keys are skipped, since their rows can't be identified.
+func (v *initialConsistencyValidator) validateTable(
+	ctx context.Context, snap tidbkv.Snapshot, table *model.TableInfo,
+) (int, []string, error) {
+	if table.IsView() {
+		return 0, nil, nil
+	}
+	if table.HandleIndexID == model.HandleIndexTableIneligible {
+		log.Info("skip validating the table without handle key",
+			zap.String("namespace", v.changefeedID.Namespace),
+			zap.String("changefeed", v.changefeedID.ID),
+			zap.String("table", table.TableName.String()))
+		return 0, nil, nil
+	}
+
+	physicalIDs := []model.TableID{table.ID}
+	if pi := table.GetPartitionInfo(); pi != nil {
+		physicalIDs = physicalIDs[:0]
+		for _, partition := range pi.Definitions {
+			physicalIDs = append(physicalIDs, partition.ID)
+		}
+	}
+
+	var sampled int
+	var mismatches []string
+	for _, id := range physicalIDs {
+		prefix := tablecodec.GenTableRecordPrefix(id)
+		iter, err := snap.Iter(prefix, prefix.PrefixNext())
+		if err != nil {
+			return 0, nil, errors.Trace(err)
+		}
+		for iter.Valid() && sampled < maxSampledRowsPerTable {
+			if rand.Float64() < v.sampleRate {
+				ok, mismatch, err := v.validateRow(ctx, iter.Key(), iter.Value())
+				if err != nil {
+					iter.Close()
+					return 0, nil, errors.Trace(err)
+				}
+				if ok {
+					sampled++
+				}
+				if mismatch != "" {
+					mismatches = append(mismatches, mismatch)
+				}
+			}
+			if err := iter.Next(); err != nil {
+				iter.Close()
+				return 0, nil, errors.Trace(err)
+			}
+		}
+		iter.Close()
+	}
+	return sampled, mismatches, nil
+}
+
+// validateRow decodes a row from the upstream and compares it with the
+// downstream, it returns false if the row is filtered by the event filter.
+func (v *initialConsistencyValidator) validateRow(
+	ctx context.Context, key, value []byte,
+) (bool, string, error) {
+	event := model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     key,
+		Value:   value,
+		StartTs: v.startTs,
+		CRTs:    v.startTs,
+	})
+	if err := v.mounter.DecodeEvent(ctx, event); err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if event.Row == nil {
+		return false, "", nil
+	}
+	mismatch, err := v.compareRow(ctx, event.Row)
+	return true, mismatch, err
+}
+
+// compareRow queries the row of the same handle key in the downstream, and
+// compares the columns by the null-safe equal operator, so that the values
+// are compared in the same way as they are written by the MySQL sink.
+// It returns the description of the mismatch, or an empty string if the
+// row is consistent.
+func (v *initialConsistencyValidator) compareRow(
+	ctx context.Context, row *model.RowChangedEvent,
+) (string, error) {
+	var (
+		names, exprs, keyNames []string
+		args, keyArgs          []interface{}
+	)
+	for _, col := range row.GetColumns() {
+		if col == nil || col.Flag.IsGeneratedColumn() {
+			continue
+		}
+		arg := col.Value
+		// Use the string representation of the non-binary strings, or the
+		// driver sends them with the `_binary` charset.
+		if b, ok := arg.([]byte); ok && col.Charset != "" && col.Charset != charset.CharsetBin {
+			arg = string(b)
+		}
+		expr := "?"
+		if col.Type == mysql.TypeJSON {
+			expr = "CAST(? AS JSON)"
+		}
+		names = append(names, col.Name)
+		exprs = append(exprs, quotes.QuoteName(col.Name)+" <=> "+expr)
+		args = append(args, arg)
+		if col.Flag.IsHandleKey() {
+			keyNames = append(keyNames, quotes.QuoteName(col.Name)+" = ?")
+			keyArgs = append(keyArgs, arg)
+		}
+	}
+
+	quoteTable := quotes.QuoteSchema(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName())
+	query := "SELECT " + strings.Join(exprs, ",") + " FROM " + quoteTable +
+		" WHERE " + strings.Join(keyNames, " AND ") + " LIMIT 1"
+	rows, err := v.db.QueryContext(ctx, query, append(args, keyArgs...)...)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+
+	key := fmt.Sprintf("%s%v", quoteTable, keyArgs)
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		return fmt.Sprintf("row %s is missing in the downstream", key), nil
+	}
+	equals := make([]sql.NullBool, len(names))
+	dest := make([]interface{}, len(names))
+	for i := range equals {
+		dest[i] = &equals[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	var different []string
+	for i, equal := range equals {
+		if !equal.Bool {
+			different = append(different, names[i])
+		}
+	}
+	if len(different) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("row %s has different columns %v in the downstream", key, different), nil
+}
+
+// Close closes the downstream connection.
+func (v *initialConsistencyValidator) Close() {
+	if err := v.db.Close(); err != nil {
+		log.Warn("close initial consistency validator db failed",
+			zap.String("namespace", v.changefeedID.Namespace),
+			zap.String("changefeed", v.changefeedID.ID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestInitialConsistencyValidator(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(32), j json)")
+	helper.Tk().MustExec(`insert into test.t values (1, 'a', '{"k": 1}'), (2, 'b', null), (3, 'c', null)`)
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.NoError(t, err)
+	// The rows committed after the start-ts are not validated.
+	helper.Tk().MustExec(`insert into test.t values (4, 'd', null)`)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	info := &model.ChangeFeedInfo{
+		SinkURI: "mysql://127.0.0.1:3306",
+		StartTs: ver.Ver,
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	info.Config.InitialConsistencySampleRate = util.AddressOf(1.0)
+	v, err := newInitialConsistencyValidatorWithDB(model.DefaultChangeFeedID("test"), helper.Storage(), info, db)
+	require.NoError(t, err)
+
+	query := "SELECT `id` <=> ?,`name` <=> ?,`j` <=> CAST(? AS JSON) FROM `test`.`t` WHERE `id` = ? LIMIT 1"
+	columns := []string{"id", "name", "j"}
+	mock.ExpectQuery(query).WithArgs(int64(1), "a", sqlmock.AnyArg(), int64(1)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 1))
+	mock.ExpectQuery(query).WithArgs(int64(2), "b", nil, int64(2)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 0, 1))
+	mock.ExpectQuery(query).WithArgs(int64(3), "c", nil, int64(3)).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectClose()
+
+	err = v.validate(context.Background())
+	require.True(t, cerror.ErrInitialConsistencyMismatch.Equal(err))
+	require.ErrorContains(t, err, "2 of 3 sampled rows mismatch")
+	require.ErrorContains(t, err, "row `test`.`t`[2] has different columns [name] in the downstream")
+	v.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInitialConsistencyValidatorUnsupportedSink(t *testing.T) {
+	t.Parallel()
+
+	info := &model.ChangeFeedInfo{
+		SinkURI: "kafka://127.0.0.1:9092/test?protocol=canal-json",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	v, err := newInitialConsistencyValidator(context.Background(), model.DefaultChangeFeedID("test"),
+		nil, info, pmysql.CreateMySQLDBConn)
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
@@ -65,6 +65,21 @@ func (mr *MockStatusProviderMockRecorder) GetCaptures(ctx interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCaptures", reflect.TypeOf((*MockStatusProvider)(nil).GetCaptures), ctx)
 }
 
+// GetChangeFeedDDLHistory mocks base method.
+func (m *MockStatusProvider) GetChangeFeedDDLHistory(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.DDLHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeFeedDDLHistory", ctx, changefeedID)
+	ret0, _ := ret[0].([]*model.DDLHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeFeedDDLHistory indicates an expected call of GetChangeFeedDDLHistory.
+func (mr *MockStatusProviderMockRecorder) GetChangeFeedDDLHistory(ctx, changefeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedDDLHistory", reflect.TypeOf((*MockStatusProvider)(nil).GetChangeFeedDDLHistory), ctx, changefeedID)
+}
+
 // GetChangeFeedInfo mocks base method.
 func (m *MockStatusProvider) GetChangeFeedInfo(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -154,3 +169,18 @@ func (mr *MockStatusProviderMockRecorder) IsHealthy(ctx interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsHealthy", reflect.TypeOf((*MockStatusProvider)(nil).IsHealthy), ctx)
 }
+
+// WatchChangeFeedStatus mocks base method.
+func (m *MockStatusProvider) WatchChangeFeedStatus(ctx context.Context, changefeedID model.ChangeFeedID) (<-chan struct{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchChangeFeedStatus", ctx, changefeedID)
+	ret0, _ := ret[0].(<-chan struct{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchChangeFeedStatus indicates an expected call of WatchChangeFeedStatus.
+func (mr *MockStatusProviderMockRecorder) WatchChangeFeedStatus(ctx, changefeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchChangeFeedStatus", reflect.TypeOf((*MockStatusProvider)(nil).WatchChangeFeedStatus), ctx, changefeedID)
+}
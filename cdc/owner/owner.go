@@ -16,6 +16,7 @@ package owner
 import (
 	"context"
 	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -119,6 +120,17 @@ type ownerImpl struct {
 
 	etcdClient etcd.CDCEtcdClient
 
+	// maxConcurrentChangefeeds limits the number of running changefeeds, 0
+	// means no limit. The others are queued in queuedChangefeeds and run in
+	// FIFO order.
+	maxConcurrentChangefeeds int
+	runningChangefeeds       map[model.ChangeFeedID]struct{}
+	queuedChangefeeds        []model.ChangeFeedID
+
+	// statusWatchers are used to notify the API watchers of the changefeeds
+	// when their status changes.
+	statusWatchers map[model.ChangeFeedID]*statusWatcher
+
 	newChangefeed func(
 		id model.ChangeFeedID,
 		cfInfo *model.ChangeFeedInfo,
@@ -149,6 +161,10 @@ func NewOwner(
 		logLimiter:      rate.NewLimiter(versionInconsistentLogRate, versionInconsistentLogRate),
 		cfg:             cfg,
 		etcdClient:      etcdClient,
+
+		maxConcurrentChangefeeds: config.GetGlobalServerConfig().MaxConcurrentChangefeeds,
+		runningChangefeeds:       make(map[model.ChangeFeedID]struct{}),
+		statusWatchers:           make(map[model.ChangeFeedID]*statusWatcher),
 	}
 }
 
@@ -168,6 +184,7 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	// the admin job may not be processed all the time. And http api relies on
 	// admin job, which will cause all http api unavailable.
 	o.handleJobs(stdCtx)
+	o.scheduleChangefeeds(state)
 
 	// Tick all changefeeds.
 	ctx := stdCtx.(cdcContext.Context)
@@ -195,6 +212,8 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 				up, o.cfg)
 			o.changefeeds[changefeedID] = cfReactor
 		}
+		_, running := o.runningChangefeeds[changefeedID]
+		cfReactor.queued = !running
 		ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
 			ID: changefeedID,
 		})
@@ -205,6 +224,9 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		}
 		checkpointTs, minTableBarrierTs := cfReactor.Tick(ctx, changefeedState.Info, changefeedState.Status, captures)
 		updateStatus(changefeedState, checkpointTs, minTableBarrierTs)
+		updateDDLHistory(changefeedState, cfReactor.ddlHistory.persisted())
+		cfReactor.gtidExecuted = getGTIDExecuted(changefeedState)
+		cfReactor.binlogPosition = getBinlogPosition(changefeedState)
 	}
 	o.changefeedTicked = true
 
@@ -219,11 +241,14 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		}
 	}
 
+	o.notifyStatusWatchers()
+
 	// Close and cleanup all changefeeds.
 	if atomic.LoadInt32(&o.closed) != 0 {
 		for _, reactor := range o.changefeeds {
 			reactor.Close(ctx)
 		}
+		o.closeStatusWatchers()
 		return state, cerror.ErrReactorFinished.GenWithStackByArgs()
 	}
 
@@ -233,6 +258,41 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	return state, nil
 }
 
+// getGTIDExecuted returns the gtid_executed reported by processors.
+// All processors query the same downstream, so the one reported by the
+// capture with the smallest ID is picked to keep the result stable.
+func getGTIDExecuted(changefeed *orchestrator.ChangefeedReactorState) string {
+	var (
+		gtidExecuted string
+		minCaptureID model.CaptureID
+	)
+	for captureID, position := range changefeed.TaskPositions {
+		if position == nil || position.GTIDExecuted == "" {
+			continue
+		}
+		if minCaptureID == "" || captureID < minCaptureID {
+			minCaptureID = captureID
+			gtidExecuted = position.GTIDExecuted
+		}
+	}
+	return gtidExecuted
+}
+
+// getBinlogPosition returns the binlog position with the largest checkpoint ts
+// reported by processors, which is the latest entry of the position table.
+func getBinlogPosition(changefeed *orchestrator.ChangefeedReactorState) *model.BinlogPosition {
+	var latest *model.BinlogPosition
+	for _, position := range changefeed.TaskPositions {
+		if position == nil || position.BinlogPosition == nil {
+			continue
+		}
+		if latest == nil || position.BinlogPosition.CheckpointTs > latest.CheckpointTs {
+			latest = position.BinlogPosition
+		}
+	}
+	return latest
+}
+
 // preflightCheck makes sure that the metadata in Etcd is complete enough to run the tick.
 // If the metadata is not complete, such as when the ChangeFeedStatus is nil,
 // this function will reconstruct the lost metadata and skip this tick.
@@ -316,6 +376,100 @@ func updateStatus(changefeed *orchestrator.ChangefeedReactorState,
 		})
 }
 
+// updateDDLHistory persists the recently executed DDLs to the changefeed status.
+func updateDDLHistory(changefeed *orchestrator.ChangefeedReactorState,
+	history []*model.DDLHistoryEntry,
+) {
+	// The history is empty before the changefeed is initialized, skip it to
+	// avoid overwriting the history persisted by the previous owner.
+	if len(history) == 0 {
+		return
+	}
+	changefeed.PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil || ddlHistoryEqual(status.DDLHistory, history) {
+				return status, false, nil
+			}
+			status.DDLHistory = append([]*model.DDLHistoryEntry(nil), history...)
+			return status, true, nil
+		})
+}
+
+// scheduleChangefeeds limits the number of running changefeeds to
+// maxConcurrentChangefeeds. The changefeeds beyond the limit are queued, and
+// activated in FIFO order once some running changefeeds are stopped, failed
+// or finished.
+func (o *ownerImpl) scheduleChangefeeds(state *orchestrator.GlobalReactorState) {
+	// needSlot returns true if the changefeed should be running.
+	needSlot := func(id model.ChangeFeedID) bool {
+		cfState, ok := state.Changefeeds[id]
+		if !ok || cfState.Info == nil || !o.shouldHandleChangefeed(cfState) {
+			return false
+		}
+		switch cfState.Info.State {
+		case model.StateNormal, model.StateWarning, model.StatePending,
+			model.StateUnInitialized:
+			return true
+		}
+		return false
+	}
+
+	for id := range o.runningChangefeeds {
+		if !needSlot(id) {
+			delete(o.runningChangefeeds, id)
+		}
+	}
+	queued := make([]model.ChangeFeedID, 0, len(o.queuedChangefeeds))
+	isQueued := make(map[model.ChangeFeedID]struct{}, len(o.queuedChangefeeds))
+	for _, id := range o.queuedChangefeeds {
+		if needSlot(id) {
+			queued = append(queued, id)
+			isQueued[id] = struct{}{}
+		}
+	}
+	// The new changefeeds are queued in the order of creation.
+	var created []model.ChangeFeedID
+	for id := range state.Changefeeds {
+		_, running := o.runningChangefeeds[id]
+		_, ok := isQueued[id]
+		if !running && !ok && needSlot(id) {
+			created = append(created, id)
+		}
+	}
+	sort.Slice(created, func(i, j int) bool {
+		ti := state.Changefeeds[created[i]].Info.CreateTime
+		tj := state.Changefeeds[created[j]].Info.CreateTime
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return created[i].String() < created[j].String()
+	})
+	queued = append(queued, created...)
+
+	i := 0
+	for ; i < len(queued); i++ {
+		if o.maxConcurrentChangefeeds > 0 && len(o.runningChangefeeds) >= o.maxConcurrentChangefeeds {
+			break
+		}
+		o.runningChangefeeds[queued[i]] = struct{}{}
+		if _, ok := isQueued[queued[i]]; ok {
+			log.Info("queued changefeed is activated",
+				zap.String("namespace", queued[i].Namespace),
+				zap.String("changefeed", queued[i].ID))
+		}
+	}
+	for _, id := range queued[i:] {
+		if _, ok := isQueued[id]; !ok {
+			log.Info("changefeed is queued since the owner runs too many changefeeds",
+				zap.String("namespace", id.Namespace),
+				zap.String("changefeed", id.ID),
+				zap.Int("maxConcurrentChangefeeds", o.maxConcurrentChangefeeds))
+		}
+	}
+	o.queuedChangefeeds = queued[i:]
+	queuedChangefeedsGauge.Set(float64(len(o.queuedChangefeeds)))
+}
+
 // shouldHandleChangefeed returns whether the owner should handle the changefeed.
 func (o *ownerImpl) shouldHandleChangefeed(_ *orchestrator.ChangefeedReactorState) bool {
 	return true
@@ -593,8 +747,33 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 		}
 		ret := &model.ChangeFeedStatusForAPI{}
 		ret.ResolvedTs = cfReactor.resolvedTs
-		ret.CheckpointTs = cfReactor.latestStatus.CheckpointTs
+		// The status of a queued changefeed may not be created yet.
+		if cfReactor.latestStatus != nil {
+			ret.CheckpointTs = cfReactor.latestStatus.CheckpointTs
+		}
+		ret.GTIDExecuted = cfReactor.gtidExecuted
+		ret.BinlogPosition = cfReactor.binlogPosition
+		ret.ScanProgress = cfReactor.scanProgress
+		ret.Queued = cfReactor.queued
+		// Scheduler is created lazily, it is nil before initialization.
+		if cfReactor.scheduler != nil {
+			ret.ReadAmplification = model.NewReadAmplification(
+				cfReactor.scheduler.ReplicationBytes())
+		}
 		query.Data = ret
+	case QueryChangeFeedDDLHistory:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok {
+			query.Data = nil
+			return nil
+		}
+		query.Data = cfReactor.ddlHistory.list()
+	case QueryChangeFeedStatusWatch:
+		query.Data = nil
+		// avoid storing a nil channel in the interface.
+		if changed := o.watchChangefeedStatus(query.ChangeFeedID); changed != nil {
+			query.Data = changed
+		}
 	case QueryChangeFeedSyncedStatus:
 		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
 		if !ok {
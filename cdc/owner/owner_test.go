@@ -16,6 +16,7 @@ package owner
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
@@ -54,6 +56,23 @@ func (m *mockManager) CheckStaleCheckpointTs(
 
 var _ gc.Manager = (*mockManager)(nil)
 
+// staleGCManager fails the GC TTL check of the stale changefeeds.
+type staleGCManager struct {
+	gc.Manager
+	stale []model.ChangeFeedID
+}
+
+func (m *staleGCManager) CheckStaleCheckpointTs(
+	ctx context.Context, changefeedID model.ChangeFeedID, checkpointTs model.Ts,
+) error {
+	for _, id := range m.stale {
+		if id == changefeedID {
+			return cerror.ErrGCTTLExceeded.GenWithStackByArgs(checkpointTs, changefeedID)
+		}
+	}
+	return m.Manager.CheckStaleCheckpointTs(ctx, changefeedID, checkpointTs)
+}
+
 // newOwner4Test creates a new Owner for test
 func newOwner4Test(
 	newDDLPuller func(ctx context.Context,
@@ -220,6 +239,107 @@ func TestCreateRemoveChangefeed(t *testing.T) {
 	require.NotContains(t, owner.changefeeds, changefeedID)
 }
 
+func TestMaxConcurrentChangefeeds(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(false)
+	ctx, cancel := cdcContext.WithCancel(ctx)
+	defer cancel()
+
+	owner, state, tester := createOwner4Test(ctx, t)
+	const maxConcurrentChangefeeds = 3
+	owner.maxConcurrentChangefeeds = maxConcurrentChangefeeds
+
+	// The mocked PD returns the start ts as the min service GC safepoint,
+	// which fails the initialization of the changefeeds if it's checked.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.CheckGCSafePoint = false
+	createTime := time.Now()
+	changefeedIDs := make([]model.ChangeFeedID, 0, maxConcurrentChangefeeds+5)
+	for i := 0; i < maxConcurrentChangefeeds+5; i++ {
+		changefeedID := model.DefaultChangeFeedID(fmt.Sprintf("test-changefeed-%d", i))
+		changefeedInfo := &model.ChangeFeedInfo{
+			StartTs:    oracle.GoTimeToTS(time.Now()),
+			State:      model.StateNormal,
+			CreateTime: createTime.Add(time.Duration(i) * time.Second),
+			Config:     replicaConfig,
+		}
+		changefeedStr, err := changefeedInfo.Marshal()
+		require.Nil(t, err)
+		cdcKey := etcd.CDCKey{
+			ClusterID:    state.ClusterID,
+			Tp:           etcd.CDCKeyTypeChangefeedInfo,
+			ChangefeedID: changefeedID,
+		}
+		tester.MustUpdate(cdcKey.String(), []byte(changefeedStr))
+		changefeedIDs = append(changefeedIDs, changefeedID)
+	}
+
+	tick := func() {
+		_, err := owner.Tick(ctx, state)
+		require.Nil(t, err)
+		tester.MustApplyPatches()
+	}
+	checkRunning := func(expected []model.ChangeFeedID) {
+		running := []model.ChangeFeedID{}
+		for _, id := range changefeedIDs {
+			cf, ok := owner.changefeeds[id]
+			if !ok || state.Changefeeds[id].Info.State != model.StateNormal {
+				continue
+			}
+			if cf.queued {
+				require.False(t, cf.initialized)
+				continue
+			}
+			running = append(running, id)
+		}
+		require.Equal(t, expected, running)
+		require.Equal(t, float64(len(owner.queuedChangefeeds)),
+			testutil.ToFloat64(queuedChangefeedsGauge))
+	}
+
+	tick()
+	checkRunning(changefeedIDs[:maxConcurrentChangefeeds])
+	require.Equal(t, changefeedIDs[maxConcurrentChangefeeds:], owner.queuedChangefeeds)
+
+	// The queued state is exposed by the changefeed status.
+	for i, id := range changefeedIDs {
+		query := &Query{Tp: QueryChangeFeedStatuses, ChangeFeedID: id}
+		require.NoError(t, owner.handleQueries(query))
+		status := query.Data.(*model.ChangeFeedStatusForAPI)
+		require.Equal(t, i >= maxConcurrentChangefeeds, status.Queued)
+	}
+
+	// The queued changefeeds are not failed by the GC TTL check.
+	up, _ := owner.upstreamManager.Get(0)
+	gcManager := up.GCManager
+	up.GCManager = &staleGCManager{Manager: gcManager, stale: changefeedIDs[maxConcurrentChangefeeds:]}
+	tick()
+	checkRunning(changefeedIDs[:maxConcurrentChangefeeds])
+	for _, id := range changefeedIDs[maxConcurrentChangefeeds:] {
+		require.Equal(t, model.StateNormal, state.Changefeeds[id].Info.State)
+	}
+	up.GCManager = gcManager
+
+	// The queued changefeeds are activated in FIFO order once the running
+	// changefeeds are paused.
+	for i := 0; i < len(changefeedIDs); i++ {
+		done := make(chan error, 1)
+		owner.EnqueueJob(model.AdminJob{
+			CfID: changefeedIDs[i],
+			Type: model.AdminStop,
+		}, done)
+		tick()
+		require.Nil(t, <-done)
+		tick()
+
+		end := i + 1 + maxConcurrentChangefeeds
+		if end > len(changefeedIDs) {
+			end = len(changefeedIDs)
+		}
+		checkRunning(changefeedIDs[i+1 : end])
+	}
+	require.Empty(t, owner.queuedChangefeeds)
+}
+
 func TestStopChangefeed(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(false)
 	owner, state, tester := createOwner4Test(ctx, t)
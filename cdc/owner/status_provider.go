@@ -30,6 +30,13 @@ type StatusProvider interface {
 	// GetChangeFeedSyncedStatus returns a changefeeds' synced status.
 	GetChangeFeedSyncedStatus(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedSyncedStatusForAPI, error)
 
+	// GetChangeFeedDDLHistory returns the recently executed DDLs of a changefeed.
+	GetChangeFeedDDLHistory(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.DDLHistoryEntry, error)
+
+	// WatchChangeFeedStatus returns a channel which is closed when the state
+	// or the error of the changefeed changes.
+	WatchChangeFeedStatus(ctx context.Context, changefeedID model.ChangeFeedID) (<-chan struct{}, error)
+
 	// GetChangeFeedInfo returns a changefeeds' info.
 	GetChangeFeedInfo(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedInfo, error)
 
@@ -68,6 +75,10 @@ const (
 	QueryChangeFeedStatuses
 	// QueryChangeFeedSyncedStatus is the type of query changefeed synced status
 	QueryChangeFeedSyncedStatus
+	// QueryChangeFeedDDLHistory is the type of query changefeed DDL history
+	QueryChangeFeedDDLHistory
+	// QueryChangeFeedStatusWatch is the type of watching changefeed status
+	QueryChangeFeedStatusWatch
 )
 
 // Query wraps query command and return results.
@@ -119,6 +130,38 @@ func (p *ownerStatusProvider) GetChangeFeedSyncedStatus(ctx context.Context,
 	return query.Data.(*model.ChangeFeedSyncedStatusForAPI), nil
 }
 
+func (p *ownerStatusProvider) GetChangeFeedDDLHistory(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.DDLHistoryEntry, error) {
+	query := &Query{
+		Tp:           QueryChangeFeedDDLHistory,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if query.Data == nil {
+		return nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(changefeedID)
+	}
+	return query.Data.([]*model.DDLHistoryEntry), nil
+}
+
+func (p *ownerStatusProvider) WatchChangeFeedStatus(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) (<-chan struct{}, error) {
+	query := &Query{
+		Tp:           QueryChangeFeedStatusWatch,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if query.Data == nil {
+		return nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(changefeedID)
+	}
+	return query.Data.(<-chan struct{}), nil
+}
+
 func (p *ownerStatusProvider) GetChangeFeedInfo(ctx context.Context,
 	changefeedID model.ChangeFeedID,
 ) (*model.ChangeFeedInfo, error) {
@@ -0,0 +1,80 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// statusWatcher notifies the API watchers of a changefeed by closing the
+// channel when the state or the error of the changefeed changes. It's only
+// accessed in the owner goroutine, so no lock is needed.
+type statusWatcher struct {
+	state   model.FeedState
+	err     *model.RunningError
+	changed chan struct{}
+}
+
+// watchChangefeedStatus returns a channel which is closed when the status of
+// the changefeed changes, the watchers of the same changefeed share the
+// channel. It returns nil if the changefeed doesn't exist.
+func (o *ownerImpl) watchChangefeedStatus(id model.ChangeFeedID) <-chan struct{} {
+	cfReactor, ok := o.changefeeds[id]
+	if !ok || cfReactor.isRemoved {
+		return nil
+	}
+	if w, ok := o.statusWatchers[id]; ok {
+		return w.changed
+	}
+	w := &statusWatcher{changed: make(chan struct{})}
+	if cfReactor.latestInfo != nil {
+		w.state, w.err = cfReactor.latestInfo.State, cfReactor.latestInfo.Error
+	}
+	o.statusWatchers[id] = w
+	return w.changed
+}
+
+// notifyStatusWatchers notifies the watchers of the changefeeds whose status
+// has changed, or which have been removed. The notified watchers are
+// removed, so the changefeeds nobody watches are not checked.
+func (o *ownerImpl) notifyStatusWatchers() {
+	for id, w := range o.statusWatchers {
+		cfReactor, ok := o.changefeeds[id]
+		if ok && !cfReactor.isRemoved && cfReactor.latestInfo != nil &&
+			cfReactor.latestInfo.State == w.state &&
+			sameRunningError(cfReactor.latestInfo.Error, w.err) {
+			continue
+		}
+		close(w.changed)
+		delete(o.statusWatchers, id)
+	}
+}
+
+// closeStatusWatchers notifies all watchers, it's called when the owner
+// is closed since the changefeeds may be taken over by another owner.
+func (o *ownerImpl) closeStatusWatchers() {
+	for id, w := range o.statusWatchers {
+		close(w.changed)
+		delete(o.statusWatchers, id)
+	}
+}
+
+// sameRunningError returns true if the errors are the same regardless of
+// the time they occurred.
+func sameRunningError(a, b *model.RunningError) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Code == b.Code && a.Message == b.Message
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func requireNotified(t *testing.T, changed <-chan struct{}, notified bool) {
+	select {
+	case <-changed:
+		require.True(t, notified, "unexpected notification")
+	default:
+		require.False(t, notified, "expected notification")
+	}
+}
+
+func TestStatusWatcher(t *testing.T) {
+	t.Parallel()
+
+	id := model.DefaultChangeFeedID("test")
+	cf := &changefeed{latestInfo: &model.ChangeFeedInfo{State: model.StateNormal}}
+	o := &ownerImpl{
+		changefeeds:    map[model.ChangeFeedID]*changefeed{id: cf},
+		statusWatchers: make(map[model.ChangeFeedID]*statusWatcher),
+	}
+	require.Nil(t, o.watchChangefeedStatus(model.DefaultChangeFeedID("not-exist")))
+
+	// The watchers of the same changefeed share the channel.
+	changed := o.watchChangefeedStatus(id)
+	require.NotNil(t, changed)
+	require.Equal(t, changed, o.watchChangefeedStatus(id))
+
+	// Nothing changes.
+	o.notifyStatusWatchers()
+	requireNotified(t, changed, false)
+
+	// An error occurs.
+	cf.latestInfo.Error = &model.RunningError{Code: "CDC:ErrTest", Message: "test"}
+	o.notifyStatusWatchers()
+	requireNotified(t, changed, true)
+	// The same error occurs again.
+	changed = o.watchChangefeedStatus(id)
+	cf.latestInfo.Error = &model.RunningError{Code: "CDC:ErrTest", Message: "test", Time: time.Now()}
+	o.notifyStatusWatchers()
+	requireNotified(t, changed, false)
+
+	// The state changes.
+	cf.latestInfo.State = model.StateWarning
+	o.notifyStatusWatchers()
+	requireNotified(t, changed, true)
+	require.Empty(t, o.statusWatchers)
+
+	// The changefeed is removed.
+	changed = o.watchChangefeedStatus(id)
+	cf.isRemoved = true
+	o.notifyStatusWatchers()
+	requireNotified(t, changed, true)
+	require.Nil(t, o.watchChangefeedStatus(id))
+
+	// The owner is closed.
+	cf.isRemoved = false
+	changed = o.watchChangefeedStatus(id)
+	o.closeStatusWatchers()
+	requireNotified(t, changed, true)
+	require.Empty(t, o.statusWatchers)
+}
@@ -23,6 +23,9 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -38,6 +41,8 @@ type commandTp int
 const (
 	commandTpUnknown commandTp = iota
 	commandTpWriteDebugInfo
+	commandTpQueryTableStats
+	commandTpQuerySorterPositionRange
 	processorLogsWarnDuration = 1 * time.Second
 )
 
@@ -56,6 +61,32 @@ type Manager interface {
 	Close()
 
 	WriteDebugInfo(ctx context.Context, w io.Writer, done chan<- error)
+	// QueryTableStats queries the replication statistics of the tables
+	// replicated by the processor of the changefeed.
+	QueryTableStats(ctx context.Context, query *TableStatsQuery, done chan<- error)
+	// QuerySorterPositionRange queries the position range of the events of
+	// a table span buffered in the sorter of the changefeed.
+	QuerySorterPositionRange(ctx context.Context, query *SorterPositionRangeQuery, done chan<- error)
+}
+
+// TableStatsQuery is the payload of querying table replication statistics.
+type TableStatsQuery struct {
+	ChangefeedID model.ChangeFeedID
+	Stats        []sinkmanager.TableReplicationStats
+}
+
+// SorterPositionRange is the position range of the events buffered in the sorter.
+type SorterPositionRange struct {
+	Min   sorter.Position `json:"min"`
+	Max   sorter.Position `json:"max"`
+	Count int             `json:"count"`
+}
+
+// SorterPositionRangeQuery is the payload of querying the sorter position range.
+type SorterPositionRangeQuery struct {
+	ChangefeedID model.ChangeFeedID
+	Span         tablepb.Span
+	Range        SorterPositionRange
 }
 
 // managerImpl is a manager of processor, which maintains the state and behavior of processors
@@ -165,7 +196,10 @@ func (m *managerImpl) Tick(stdCtx context.Context, state orchestrator.ReactorSta
 			// patchProcessorErr have already patched its error to tell the owner
 			// manager can just close the processor and continue to tick other processors
 			m.closeProcessor(changefeedID)
+			continue
 		}
+		patchProcessorGTIDExecuted(p.captureInfo, changefeedState, p.getGTIDExecuted())
+		patchProcessorBinlogPosition(p.captureInfo, changefeedState, p.getBinlogPosition())
 	}
 	// check if the processors in memory is leaked
 	if len(globalState.Changefeeds)-inactiveChangefeedCount != len(m.processors) {
@@ -277,6 +311,57 @@ func patchProcessorWarning(captureInfo *model.CaptureInfo,
 		})
 }
 
+// patchProcessorGTIDExecuted records the gtid_executed of the downstream in
+// the task position, it only patches etcd when the value changes.
+func patchProcessorGTIDExecuted(captureInfo *model.CaptureInfo,
+	changefeed *orchestrator.ChangefeedReactorState, gtidExecuted string,
+) {
+	if gtidExecuted == "" {
+		return
+	}
+	if position, ok := changefeed.TaskPositions[captureInfo.ID]; ok &&
+		position != nil && position.GTIDExecuted == gtidExecuted {
+		return
+	}
+	changefeed.PatchTaskPosition(captureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			if position == nil {
+				position = &model.TaskPosition{}
+			}
+			if position.GTIDExecuted == gtidExecuted {
+				return position, false, nil
+			}
+			position.GTIDExecuted = gtidExecuted
+			return position, true, nil
+		})
+}
+
+// patchProcessorBinlogPosition records the binlog position of the downstream
+// in the task position, it only patches etcd when the value changes.
+func patchProcessorBinlogPosition(captureInfo *model.CaptureInfo,
+	changefeed *orchestrator.ChangefeedReactorState, binlogPosition *model.BinlogPosition,
+) {
+	if binlogPosition == nil {
+		return
+	}
+	if position, ok := changefeed.TaskPositions[captureInfo.ID]; ok && position != nil &&
+		position.BinlogPosition != nil && *position.BinlogPosition == *binlogPosition {
+		return
+	}
+	changefeed.PatchTaskPosition(captureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			if position == nil {
+				position = &model.TaskPosition{}
+			}
+			if position.BinlogPosition != nil && *position.BinlogPosition == *binlogPosition {
+				return position, false, nil
+			}
+			pos := *binlogPosition
+			position.BinlogPosition = &pos
+			return position, true, nil
+		})
+}
+
 func (m *managerImpl) closeProcessor(changefeedID model.ChangeFeedID) {
 	processor, exist := m.processors[changefeedID]
 	if exist {
@@ -321,6 +406,26 @@ func (m *managerImpl) WriteDebugInfo(
 	}
 }
 
+// QueryTableStats implements Manager interface.
+func (m *managerImpl) QueryTableStats(
+	ctx context.Context, query *TableStatsQuery, done chan<- error,
+) {
+	err := m.sendCommand(ctx, commandTpQueryTableStats, query, done)
+	if err != nil {
+		log.Warn("send command commandTpQueryTableStats failed", zap.Error(err))
+	}
+}
+
+// QuerySorterPositionRange implements Manager interface.
+func (m *managerImpl) QuerySorterPositionRange(
+	ctx context.Context, query *SorterPositionRangeQuery, done chan<- error,
+) {
+	err := m.sendCommand(ctx, commandTpQuerySorterPositionRange, query, done)
+	if err != nil {
+		log.Warn("send command commandTpQuerySorterPositionRange failed", zap.Error(err))
+	}
+}
+
 // sendCommands sends command to manager.
 // `done` is closed upon command completion or sendCommand returns error.
 func (m *managerImpl) sendCommand(
@@ -352,6 +457,24 @@ func (m *managerImpl) handleCommand() {
 		if err != nil {
 			cmd.done <- err
 		}
+	case commandTpQueryTableStats:
+		query := cmd.payload.(*TableStatsQuery)
+		processor, ok := m.processors[query.ChangefeedID]
+		if !ok {
+			cmd.done <- cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangefeedID.ID)
+			return
+		}
+		query.Stats = processor.getTableReplicationStats()
+	case commandTpQuerySorterPositionRange:
+		query := cmd.payload.(*SorterPositionRangeQuery)
+		processor, ok := m.processors[query.ChangefeedID]
+		if !ok {
+			cmd.done <- cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangefeedID.ID)
+			return
+		}
+		if err := processor.getSorterPositionRange(query); err != nil {
+			cmd.done <- err
+		}
 	default:
 		log.Warn("Unknown command in processor manager", zap.Any("command", cmd))
 	}
@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	processor "github.com/pingcap/tiflow/cdc/processor"
 	orchestrator "github.com/pingcap/tiflow/pkg/orchestrator"
 )
 
@@ -63,6 +64,30 @@ func (mr *MockManagerMockRecorder) Tick(ctx, state interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockManager)(nil).Tick), ctx, state)
 }
 
+// QueryTableStats mocks base method.
+func (m *MockManager) QueryTableStats(ctx context.Context, query *processor.TableStatsQuery, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "QueryTableStats", ctx, query, done)
+}
+
+// QueryTableStats indicates an expected call of QueryTableStats.
+func (mr *MockManagerMockRecorder) QueryTableStats(ctx, query, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTableStats", reflect.TypeOf((*MockManager)(nil).QueryTableStats), ctx, query, done)
+}
+
+// QuerySorterPositionRange mocks base method.
+func (m *MockManager) QuerySorterPositionRange(ctx context.Context, query *processor.SorterPositionRangeQuery, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "QuerySorterPositionRange", ctx, query, done)
+}
+
+// QuerySorterPositionRange indicates an expected call of QuerySorterPositionRange.
+func (mr *MockManagerMockRecorder) QuerySorterPositionRange(ctx, query, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySorterPositionRange", reflect.TypeOf((*MockManager)(nil).QuerySorterPositionRange), ctx, query, done)
+}
+
 // WriteDebugInfo mocks base method.
 func (m *MockManager) WriteDebugInfo(ctx context.Context, w io.Writer, done chan<- error) {
 	m.ctrl.T.Helper()
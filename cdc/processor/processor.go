@@ -107,6 +107,8 @@ type processor struct {
 	// processor implements TableExecutor interface, so we need to add these two fields here to use them
 	// in `AddTableSpan` and `RemoveTableSpan`, otherwise we need to adjust the interface.
 	// we can refactor this step by step.
+	// They are snapshots of the reactor state kept up to date by the watch of
+	// EtcdWorker, so reading them in table scheduling does not read etcd.
 	latestInfo   *model.ChangeFeedInfo
 	latestStatus *model.ChangeFeedStatus
 
@@ -380,9 +382,12 @@ func (p *processor) getStatsFromSourceManagerAndSinkManager(
 	now := p.upstream.PDClock.CurrentTime()
 
 	stats := tablepb.Stats{
-		RegionCount: pullerStats.RegionCount,
-		CurrentTs:   oracle.ComposeTS(oracle.GetPhysical(now), 0),
-		BarrierTs:   sinkStats.BarrierTs,
+		RegionCount:            pullerStats.RegionCount,
+		InitializedRegionCount: pullerStats.InitializedRegionCount,
+		ScannedBytes:           pullerStats.ScannedBytes,
+		WrittenBytes:           sinkStats.BytesWritten,
+		CurrentTs:              oracle.ComposeTS(oracle.GetPhysical(now), 0),
+		BarrierTs:              sinkStats.BarrierTs,
 		StageCheckpoints: map[string]tablepb.Checkpoint{
 			"puller-ingress": {
 				CheckpointTs: pullerStats.CheckpointTsIngress,
@@ -505,6 +510,7 @@ func (p *processor) Tick(
 		log.Warn("upstream is not ready, skip",
 			zap.Uint64("id", p.upstream.ID),
 			zap.Strings("pd", p.upstream.PdEndpoints),
+			zap.Strings("healthyPD", p.upstream.HealthyPDEndpoints()),
 			zap.String("namespace", p.changefeedID.Namespace),
 			zap.String("changefeed", p.changefeedID.ID))
 		return nil, nil
@@ -641,6 +647,7 @@ func (p *processor) lazyInitImpl(etcdCtx cdcContext.Context) (err error) {
 
 	// Bind them so that sourceManager can notify sinkManager.r.
 	p.sourceManager.r.OnResolve(p.sinkManager.r.UpdateReceivedSorterResolvedTs)
+	p.sourceManager.r.OnBackpressure(p.sinkManager.r.WaitForBackpressure, p.sinkManager.r.CheckBackpressure)
 	p.agent, err = p.newAgent(prcCtx, p.liveness, p.changefeedEpoch, p.cfg, p.ownerCaptureInfoClient)
 	if err != nil {
 		return err
@@ -825,6 +832,22 @@ func (p *processor) refreshMetrics() {
 	p.metricSyncTableNumGauge.Set(float64(p.sinkManager.r.GetAllCurrentTableSpansCount()))
 }
 
+// getGTIDExecuted returns the latest gtid_executed of the downstream.
+func (p *processor) getGTIDExecuted() string {
+	if !p.initialized {
+		return ""
+	}
+	return p.sinkManager.r.GetGTIDExecuted()
+}
+
+// getBinlogPosition returns the latest binlog position of the downstream.
+func (p *processor) getBinlogPosition() *model.BinlogPosition {
+	if !p.initialized {
+		return nil
+	}
+	return p.sinkManager.r.GetBinlogPosition()
+}
+
 // Close closes the processor. It must be called explicitly to stop all sub-components.
 func (p *processor) Close() error {
 	log.Info("processor closing ...",
@@ -920,6 +943,31 @@ func (p *processor) WriteDebugInfo(w io.Writer) error {
 	return nil
 }
 
+// getTableReplicationStats returns the replication statistics of the tables,
+// nil is returned if the processor is not initialized.
+func (p *processor) getTableReplicationStats() []sinkmanager.TableReplicationStats {
+	if !p.initialized {
+		return nil
+	}
+	return p.sinkManager.r.GetTableReplicationStats()
+}
+
+// getSorterPositionRange fills the position range of the events of the table
+// span buffered in the sorter into the query.
+func (p *processor) getSorterPositionRange(query *SorterPositionRangeQuery) error {
+	if !p.initialized {
+		return cerror.ErrProcessorTableNotFound.GenWithStack(
+			"processor of changefeed %s is not initialized", query.ChangefeedID.ID)
+	}
+	if _, exist := p.sinkManager.r.GetTableState(query.Span); !exist {
+		return cerror.ErrProcessorTableNotFound.GenWithStack(
+			"table span %s not found in processor", &query.Span)
+	}
+	minPos, maxPos, count := p.sourceManager.r.GetTablePositionRange(query.Span)
+	query.Range = SorterPositionRange{Min: minPos, Max: maxPos, Count: count}
+	return nil
+}
+
 func (p *processor) calculateTableBarrierTs(
 	barrier *schedulepb.Barrier,
 ) map[model.TableID]model.Ts {
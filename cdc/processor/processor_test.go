@@ -18,8 +18,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -36,6 +40,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/pdutil"
 	redoPkg "github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -771,3 +776,77 @@ func TestProcessorDostNotStuckInInit(t *testing.T) {
 	require.Nil(t, p.Close())
 	tester.MustApplyPatches()
 }
+
+func TestProcessorWithUnhealthyPDEndpoint(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	liveness := model.LivenessCaptureAlive
+	p, tester, changefeed := initProcessor4Test(ctx, t, &liveness, false)
+
+	// One of the two PD endpoints is down.
+	healthy := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+	))
+	defer healthy.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	pc, pool, err := pdutil.NewPDAPIClientWithEndpointPool(
+		&sinkmanager.MockPD{}, nil, 1, []string{down.URL, healthy.URL})
+	require.NoError(t, err)
+	defer pc.Close()
+	p.upstream.PDAPIClient = pc
+	p.upstream.PDEndpointPool = pool
+
+	poolCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = pool.Run(poolCtx)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	require.Eventually(t, func() bool {
+		endpoints := p.upstream.HealthyPDEndpoints()
+		return len(endpoints) == 1 && endpoints[0] == healthy.URL
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The processor keeps replicating tables.
+	checkChangefeedNormal(changefeed)
+	createTaskPosition(changefeed, p.captureInfo)
+	tester.MustApplyPatches()
+	changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 20
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	err, _ = p.Tick(ctx, changefeed.Info, changefeed.Status)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+
+	span := spanz.TableIDToComparableSpan(1)
+	ok, err := p.AddTableSpan(ctx, span, tablepb.Checkpoint{CheckpointTs: 20}, true)
+	require.NoError(t, err)
+	require.True(t, ok)
+	p.sourceManager.r.Add(span, model.NewResolvedPolymorphicEvent(0, 101))
+	err, _ = p.Tick(ctx, changefeed.Info, changefeed.Status)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.True(t, p.IsAddTableSpanFinished(span, true))
+
+	ok, err = p.AddTableSpan(ctx, span, tablepb.Checkpoint{CheckpointTs: 30}, false)
+	require.NoError(t, err)
+	require.True(t, ok)
+	err, _ = p.Tick(ctx, changefeed.Info, changefeed.Status)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	state, ok := p.sinkManager.r.GetTableState(span)
+	require.True(t, ok)
+	require.Equal(t, tablepb.TableStateReplicating, state)
+
+	require.Nil(t, p.Close())
+	tester.MustApplyPatches()
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -52,6 +53,9 @@ const (
 	// sorter.CleanByTable can be expensive. So it's necessary to reduce useless calls.
 	cleanTableInterval  = 5 * time.Second
 	cleanTableMinEvents = 128
+	// backpressureCheckInterval is the interval to re-check the lag of a table
+	// whose puller is blocked by backpressure.
+	backpressureCheckInterval = 100 * time.Millisecond
 )
 
 // TableStats of a table sink.
@@ -60,6 +64,21 @@ type TableStats struct {
 	ResolvedTs   model.Ts
 	LastSyncedTs model.Ts
 	BarrierTs    model.Ts
+	// BytesWritten is the bytes of the events appended to the table sink.
+	BytesWritten uint64
+}
+
+// TableReplicationStats is the replication statistics of a table sink.
+type TableReplicationStats struct {
+	Span         tablepb.Span
+	RowsInserted uint64
+	RowsUpdated  uint64
+	RowsDeleted  uint64
+	BytesWritten uint64
+	CheckpointTs model.Ts
+	ResolvedTs   model.Ts
+	// Lag is the duration between the current PD time and the checkpoint ts.
+	Lag time.Duration
 }
 
 // SinkManager is the implementation of SinkManager.
@@ -106,6 +125,17 @@ type SinkManager struct {
 	// sinkMemQuota is used to control the total memory usage of the table sink.
 	sinkMemQuota *memquota.MemQuota
 	sinkRetry    *retry.ErrorRetry
+	// sinkRestartCh is used to send SafeRestart requests to the sink task generator.
+	sinkRestartCh chan chan error
+	// sinkRestarting is true when SafeRestart is in progress, all sink tasks
+	// are canceled in the meantime.
+	sinkRestarting atomic.Bool
+	// sinkTasksInflight is the number of sink tasks which are dispatched but
+	// not finished yet.
+	sinkTasksInflight atomic.Int64
+	// sinkWorkerQuota limits the sink tasks handled at the same time by all
+	// changefeeds of the namespace, it's nil if the namespace is not limited.
+	sinkWorkerQuota *workerQuota
 	// redoWorkers used to pull data from source manager.
 	redoWorkers []*redoWorker
 	// redoTaskChan is used to send tasks to redoWorkers.
@@ -126,10 +156,20 @@ type SinkManager struct {
 	// wg is used to wait for all workers to exit.
 	wg sync.WaitGroup
 
+	// backpressureThreshold is the max lag between the sorter resolved ts and the
+	// checkpoint ts of a table before its puller is blocked. 0 means disabled.
+	backpressureThreshold time.Duration
+
+	// sinkCreationTimeout is the max duration to create a table sink before
+	// it's reported as a timeout.
+	sinkCreationTimeout time.Duration
+
 	// Metric for table sink.
 	metricsTableSinkTotalRows prometheus.Counter
 
 	metricsTableSinkFlushLagDuration prometheus.Observer
+
+	metricsBackpressureActive prometheus.Gauge
 }
 
 // New creates a new sink manager.
@@ -153,12 +193,29 @@ func New(
 		sinkTaskChan:        make(chan *sinkTask),
 		sinkWorkerAvailable: make(chan struct{}, 1),
 		sinkRetry:           retry.NewInfiniteErrorRetry(),
+		sinkRestartCh:       make(chan chan error),
 
 		metricsTableSinkTotalRows: tablesinkmetrics.TotalRowsCountCounter.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 
 		metricsTableSinkFlushLagDuration: tablesinkmetrics.TableSinkFlushLagDuration.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+
+		metricsBackpressureActive: backpressureActive.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+
+	backpressureThresholdMs := util.GetOrZero(changefeedInfo.Config.Sink.BackpressureThresholdMs)
+	m.backpressureThreshold = time.Duration(backpressureThresholdMs) * time.Millisecond
+
+	sinkCreationTimeoutInSec := util.GetOrZero(changefeedInfo.Config.Sink.SinkCreationTimeoutInSec)
+	if sinkCreationTimeoutInSec == 0 {
+		sinkCreationTimeoutInSec = config.DefaultSinkCreationTimeoutInSec
+	}
+	m.sinkCreationTimeout = time.Duration(sinkCreationTimeoutInSec) * time.Second
+
+	if quota := config.GetGlobalServerConfig().GetNamespaceQuota(changefeedID.Namespace); quota != nil {
+		m.sinkWorkerQuota = getNamespaceWorkerQuota(changefeedID.Namespace, quota.MaxWorkers)
 	}
 
 	totalQuota := changefeedInfo.Config.MemoryQuota
@@ -202,16 +259,22 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 	}()
 
 	splitTxn := util.GetOrZero(m.changefeedInfo.Config.Sink.TxnAtomicity).ShouldSplitTxn()
+	excludeInvisibleColumns := util.GetOrZero(m.changefeedInfo.Config.Sink.ExcludeInvisibleColumns)
+	schemaOnly := util.GetOrZero(m.changefeedInfo.Config.SchemaOnlyMode)
 
 	gcErrors := make(chan error, 16)
 	sinkErrors := make(chan error, 16)
 	redoErrors := make(chan error, 16)
 
 	m.backgroundGC(gcErrors)
+	// restartCtx is canceled if the sink task generator exits, so that a
+	// restart of the sink factory doesn't wait for it forever.
+	restartCtx := m.managerCtx
 	if m.sinkEg == nil {
 		var sinkCtx context.Context
 		m.sinkEg, sinkCtx = errgroup.WithContext(m.managerCtx)
-		m.startSinkWorkers(sinkCtx, m.sinkEg, splitTxn)
+		restartCtx = sinkCtx
+		m.startSinkWorkers(sinkCtx, m.sinkEg, splitTxn, excludeInvisibleColumns, schemaOnly)
 		m.sinkEg.Go(func() error { return m.generateSinkTasks(sinkCtx) })
 		m.wg.Add(1)
 		go func() {
@@ -231,7 +294,7 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 	if m.redoDMLMgr != nil && m.redoEg == nil {
 		var redoCtx context.Context
 		m.redoEg, redoCtx = errgroup.WithContext(m.managerCtx)
-		m.startRedoWorkers(redoCtx, m.redoEg)
+		m.startRedoWorkers(redoCtx, m.redoEg, excludeInvisibleColumns, schemaOnly)
 		m.redoEg.Go(func() error { return m.generateRedoTasks(redoCtx) })
 		m.wg.Add(1)
 		go func() {
@@ -256,9 +319,9 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 		zap.Bool("withRedoEnabled", m.redoDMLMgr != nil))
 
 	// SinkManager will restart some internal modules if necessasry.
+	// The sink factory reports errors to the same channel after restarts.
+	sinkFactoryErrors, _ := m.initSinkFactory()
 	for {
-		sinkFactoryErrors, sinkFactoryVersion := m.initSinkFactory()
-
 		select {
 		case <-m.managerCtx.Done():
 			return m.managerCtx.Err()
@@ -272,24 +335,7 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 			log.Warn("Sink manager backend sink fails",
 				zap.String("namespace", m.changefeedID.Namespace),
 				zap.String("changefeed", m.changefeedID.ID),
-				zap.Uint64("factoryVersion", sinkFactoryVersion),
 				zap.Error(err))
-			m.clearSinkFactory()
-
-			// To release memory quota ASAP, close all table sinks manually.
-			start := time.Now()
-			log.Info("Sink manager is closing all table sinks",
-				zap.String("namespace", m.changefeedID.Namespace),
-				zap.String("changefeed", m.changefeedID.ID))
-			m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
-				value.(*tableSinkWrapper).closeTableSink()
-				m.sinkMemQuota.ClearTable(span)
-				return true
-			})
-			log.Info("Sink manager has closed all table sinks",
-				zap.String("namespace", m.changefeedID.Namespace),
-				zap.String("changefeed", m.changefeedID.ID),
-				zap.Duration("cost", time.Since(start)))
 		}
 
 		// If the error is retryable, we should retry to re-establish the internal resources.
@@ -306,10 +352,27 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 		if err != nil {
 			return errors.New(fmt.Sprintf("GetRetryBackoff: %s", err.Error()))
 		}
+		failpoint.Inject("SinkManagerRestartNoBackoff", func() {
+			backoff = 0
+		})
 
 		if err = util.Hang(m.managerCtx, backoff); err != nil {
 			return errors.Trace(err)
 		}
+
+		// All tables are continued from their checkpoints after the sink factory
+		// is re-created, so the events written into the failed one are not lost.
+		// If the sink factory can't be re-created, the error is reported to
+		// sinkFactoryErrors and it will be retried after another backoff.
+		if err = m.SafeRestart(restartCtx); err != nil {
+			if errors.Cause(err) == context.Canceled {
+				continue
+			}
+			log.Warn("Sink manager restarts sink factory failed",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Error(err))
+		}
 	}
 }
 
@@ -319,6 +382,28 @@ func (m *SinkManager) needsStuckCheck() bool {
 	return m.sinkFactory.f != nil && m.sinkFactory.f.Category() == factory.CategoryMQ
 }
 
+// GetGTIDExecuted returns the latest gtid_executed of the downstream.
+// It returns an empty string if the downstream doesn't support GTID.
+func (m *SinkManager) GetGTIDExecuted() string {
+	m.sinkFactory.Lock()
+	defer m.sinkFactory.Unlock()
+	if m.sinkFactory.f == nil {
+		return ""
+	}
+	return m.sinkFactory.f.GetGTIDExecuted()
+}
+
+// GetBinlogPosition returns the latest binlog position of the downstream.
+// It returns nil if track-binlog-position is not enabled.
+func (m *SinkManager) GetBinlogPosition() *model.BinlogPosition {
+	m.sinkFactory.Lock()
+	defer m.sinkFactory.Unlock()
+	if m.sinkFactory.f == nil {
+		return nil
+	}
+	return m.sinkFactory.f.GetBinlogPosition()
+}
+
 func (m *SinkManager) initSinkFactory() (chan error, uint64) {
 	m.sinkFactory.Lock()
 	defer m.sinkFactory.Unlock()
@@ -399,19 +484,24 @@ func (m *SinkManager) putSinkFactoryError(err error, version uint64) (success bo
 	return false
 }
 
-func (m *SinkManager) startSinkWorkers(ctx context.Context, eg *errgroup.Group, splitTxn bool) {
+func (m *SinkManager) startSinkWorkers(
+	ctx context.Context, eg *errgroup.Group,
+	splitTxn bool, excludeInvisibleColumns bool, schemaOnly bool,
+) {
 	for i := 0; i < sinkWorkerNum; i++ {
 		w := newSinkWorker(m.changefeedID, m.sourceManager,
-			m.sinkMemQuota, splitTxn)
+			m.sinkMemQuota, splitTxn, excludeInvisibleColumns, schemaOnly)
 		m.sinkWorkers = append(m.sinkWorkers, w)
 		eg.Go(func() error { return w.handleTasks(ctx, m.sinkTaskChan) })
 	}
 }
 
-func (m *SinkManager) startRedoWorkers(ctx context.Context, eg *errgroup.Group) {
+func (m *SinkManager) startRedoWorkers(
+	ctx context.Context, eg *errgroup.Group, excludeInvisibleColumns bool, schemaOnly bool,
+) {
 	for i := 0; i < redoWorkerNum; i++ {
 		w := newRedoWorker(m.changefeedID, m.sourceManager, m.redoMemQuota,
-			m.redoDMLMgr)
+			m.redoDMLMgr, excludeInvisibleColumns, schemaOnly)
 		m.redoWorkers = append(m.redoWorkers, w)
 		eg.Go(func() error { return w.handleTasks(ctx, m.redoTaskChan) })
 	}
@@ -586,8 +676,19 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 				continue
 			}
 
+			// No available worker in the namespace, skip this round directly.
+			if m.sinkWorkerQuota != nil && !m.sinkWorkerQuota.tryAcquire() {
+				break LOOP
+			}
+			releaseWorker := func() {
+				if m.sinkWorkerQuota != nil {
+					m.sinkWorkerQuota.release()
+				}
+			}
+
 			// No available memory, skip this round directly.
 			if !m.sinkMemQuota.TryAcquire(requestMemSize) {
+				releaseWorker()
 				break LOOP
 			}
 
@@ -609,17 +710,24 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 						version:           slowestTableProgress.version,
 					}
 					m.sinkProgressHeap.push(p)
+					m.sinkTasksInflight.Add(-1)
 					select {
 					case m.sinkWorkerAvailable <- struct{}{}:
 					default:
 					}
 				},
 				isCanceled: func() bool {
-					return tableSink.getState() != tablepb.TableStateReplicating
+					return tableSink.getState() != tablepb.TableStateReplicating ||
+						m.sinkRestarting.Load()
 				},
+				release: releaseWorker,
 			}
+			// The task can be finished before the select returns.
+			m.sinkTasksInflight.Add(1)
 			select {
 			case <-ctx.Done():
+				m.sinkTasksInflight.Add(-1)
+				releaseWorker()
 				return ctx.Err()
 			case m.sinkTaskChan <- t:
 				log.Debug("Generate sink task",
@@ -629,6 +737,8 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 					zap.Any("lowerBound", lowerBound),
 					zap.Any("currentUpperBound", upperBound))
 			default:
+				m.sinkTasksInflight.Add(-1)
+				releaseWorker()
 				m.sinkMemQuota.Refund(requestMemSize)
 				log.Debug("MemoryQuotaTracing: refund memory for table sink task",
 					zap.String("namespace", m.changefeedID.Namespace),
@@ -659,6 +769,13 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 			if err := dispatchTasks(); err != nil {
 				return errors.Trace(err)
 			}
+		case done := <-m.sinkRestartCh:
+			done <- m.safeRestart(ctx)
+			// safeRestart consumes the signals of available workers, so tasks
+			// are dispatched right away instead of waiting for the next tick.
+			if err := dispatchTasks(); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
@@ -811,6 +928,64 @@ func (m *SinkManager) UpdateReceivedSorterResolvedTs(span tablepb.Span, ts model
 	tableSink.(*tableSinkWrapper).updateReceivedSorterResolvedTs(ts)
 }
 
+// CheckBackpressure returns nil if the lag between the sorter resolved ts and
+// the checkpoint ts of the table is no more than the configured threshold.
+// Otherwise the table is under backpressure, and a channel is returned which
+// will be closed when the checkpoint ts of the table advances. It never blocks,
+// so it can be called by pullers which are shared by many tables.
+func (m *SinkManager) CheckBackpressure(span tablepb.Span) <-chan struct{} {
+	if m.backpressureThreshold == 0 {
+		return nil
+	}
+	value, ok := m.tableSinks.Load(span)
+	if !ok {
+		return nil
+	}
+	tableSink := value.(*tableSinkWrapper)
+
+	lag, checkpointAdvanced := tableSink.getBackpressureLag()
+	// The table sink won't advance any more if it's not replicating.
+	if lag <= m.backpressureThreshold || tableSink.getState() != tablepb.TableStateReplicating {
+		if tableSink.backpressured.CompareAndSwap(true, false) {
+			m.metricsBackpressureActive.Dec()
+		}
+		return nil
+	}
+	if tableSink.backpressured.CompareAndSwap(false, true) {
+		m.metricsBackpressureActive.Inc()
+		log.Debug("Table puller is under backpressure",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Duration("lag", lag),
+			zap.Duration("threshold", m.backpressureThreshold))
+	}
+	return checkpointAdvanced
+}
+
+// WaitForBackpressure blocks until the table isn't under backpressure, see
+// CheckBackpressure. It's called by pullers of single tables before pushing
+// events into the sorter, so that the sorter won't buffer unbounded data when
+// the downstream is slow.
+func (m *SinkManager) WaitForBackpressure(ctx context.Context, span tablepb.Span) error {
+	checkpointAdvanced := m.CheckBackpressure(span)
+	if checkpointAdvanced == nil {
+		return nil
+	}
+	ticker := time.NewTicker(backpressureCheckInterval)
+	defer ticker.Stop()
+	for checkpointAdvanced != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-checkpointAdvanced:
+		case <-ticker.C:
+		}
+		checkpointAdvanced = m.CheckBackpressure(span)
+	}
+	return nil
+}
+
 // UpdateBarrierTs update all tableSink's barrierTs in the SinkManager
 func (m *SinkManager) UpdateBarrierTs(globalBarrierTs model.Ts, tableBarrier map[model.TableID]model.Ts) {
 	m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
@@ -845,6 +1020,7 @@ func (m *SinkManager) AddTable(span tablepb.Span, startTs model.Ts, targetTs mod
 			return genReplicateTs(ctx, m.up.PDClient)
 		},
 	)
+	sinkWrapper.sinkCreationTimeout = m.sinkCreationTimeout
 
 	_, loaded := m.tableSinks.LoadOrStore(span, sinkWrapper)
 	if loaded {
@@ -935,6 +1111,9 @@ func (m *SinkManager) RemoveTable(span tablepb.Span) {
 			zap.String("changefeed", m.changefeedID.ID),
 			zap.Stringer("span", &span))
 	}
+	if value.(*tableSinkWrapper).backpressured.CompareAndSwap(true, false) {
+		m.metricsBackpressureActive.Dec()
+	}
 	checkpointTs := value.(*tableSinkWrapper).getCheckpointTs()
 	log.Info("Remove table sink successfully",
 		zap.String("namespace", m.changefeedID.Namespace),
@@ -1047,9 +1226,37 @@ func (m *SinkManager) GetTableStats(span tablepb.Span) TableStats {
 		ResolvedTs:   resolvedTs,
 		LastSyncedTs: lastSyncedTs,
 		BarrierTs:    tableSink.barrierTs.Load(),
+		BytesWritten: tableSink.bytesWritten.Load(),
 	}
 }
 
+// GetTableReplicationStats returns the replication statistics of all tables.
+// Unlike GetTableStats, it doesn't change the state of table sinks.
+func (m *SinkManager) GetTableReplicationStats() []TableReplicationStats {
+	now := m.up.PDClock.CurrentTime()
+	var stats []TableReplicationStats
+	m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
+		tableSink := value.(*tableSinkWrapper)
+		checkpointTs := tableSink.getCheckpointTs().ResolvedMark()
+		var lag time.Duration
+		if checkpointTs > 0 {
+			lag = now.Sub(oracle.GetTimeFromTS(checkpointTs))
+		}
+		stats = append(stats, TableReplicationStats{
+			Span:         span,
+			RowsInserted: tableSink.rowsInserted.Load(),
+			RowsUpdated:  tableSink.rowsUpdated.Load(),
+			RowsDeleted:  tableSink.rowsDeleted.Load(),
+			BytesWritten: tableSink.bytesWritten.Load(),
+			CheckpointTs: checkpointTs,
+			ResolvedTs:   tableSink.getReceivedSorterResolvedTs(),
+			Lag:          lag,
+		})
+		return true
+	})
+	return stats
+}
+
 // WaitForReady implements pkg/util.Runnable.
 func (m *SinkManager) WaitForReady(ctx context.Context) {
 	select {
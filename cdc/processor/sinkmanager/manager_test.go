@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -27,7 +28,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -434,3 +437,238 @@ func TestSinkManagerRestartTableSinks(t *testing.T) {
 		panic("should always get a sink task")
 	}
 }
+
+func TestSinkManagerSafeRestart(t *testing.T) {
+	failpoint.Enable("github.com/pingcap/tiflow/cdc/processor/sinkmanager/SinkWorkerTaskHandlePause", "return")
+	defer failpoint.Disable("github.com/pingcap/tiflow/cdc/processor/sinkmanager/SinkWorkerTaskHandlePause")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 16)
+	changefeedInfo := getChangefeedInfo()
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.ChangeFeedID{}, changefeedInfo, errCh)
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	span := tablepb.Span{TableID: 1}
+	manager.AddTable(span, 1, 100)
+	require.Nil(t, manager.StartTable(span, 2))
+	table, exists := manager.tableSinks.Load(span)
+	require.True(t, exists)
+
+	table.(*tableSinkWrapper).updateReceivedSorterResolvedTs(4)
+	table.(*tableSinkWrapper).updateBarrierTs(4)
+	var task *sinkTask
+	select {
+	case task = <-manager.sinkTaskChan:
+		require.Equal(t, sorter.Position{StartTs: 0, CommitTs: 3}, task.lowerBound)
+	case <-time.After(2 * time.Second):
+		panic("should always get a sink task")
+	}
+
+	// The task is in flight, SafeRestart must wait for it.
+	restartErr := make(chan error, 1)
+	go func() { restartErr <- manager.SafeRestart(ctx) }()
+	require.Eventually(t, task.isCanceled, 2*time.Second, 10*time.Millisecond)
+	select {
+	case <-restartErr:
+		panic("SafeRestart should wait for tasks in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+	// Events in (2, 4] are written into the old sink factory but not flushed.
+	task.callback(sorter.Position{StartTs: 3, CommitTs: 4})
+	require.NoError(t, <-restartErr)
+
+	// The table sink is re-created and continued from the checkpoint.
+	require.Eventually(t, table.(*tableSinkWrapper).initTableSink, time.Second, 10*time.Millisecond)
+	select {
+	case task = <-manager.sinkTaskChan:
+		require.Equal(t, sorter.Position{StartTs: 2, CommitTs: 2}, task.lowerBound)
+		require.False(t, task.isCanceled())
+		task.callback(sorter.Position{StartTs: 3, CommitTs: 4})
+	case <-time.After(2 * time.Second):
+		panic("should always get a sink task")
+	}
+}
+
+func TestSinkManagerRestartOnSinkFactoryError(t *testing.T) {
+	failpoint.Enable("github.com/pingcap/tiflow/cdc/processor/sinkmanager/SinkWorkerTaskHandlePause", "return")
+	defer failpoint.Disable("github.com/pingcap/tiflow/cdc/processor/sinkmanager/SinkWorkerTaskHandlePause")
+	failpoint.Enable("github.com/pingcap/tiflow/cdc/processor/sinkmanager/SinkManagerRestartNoBackoff", "return")
+	defer failpoint.Disable("github.com/pingcap/tiflow/cdc/processor/sinkmanager/SinkManagerRestartNoBackoff")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	manager, sourceManager, _ := NewManagerWithMemEngine(t, model.ChangeFeedID{}, changefeedInfo, nil)
+	warnings := make(chan error, 16)
+	go func() { _ = sourceManager.Run(ctx) }()
+	sourceManager.WaitForReady(ctx)
+	go func() { _ = manager.Run(ctx, warnings) }()
+	manager.WaitForReady(ctx)
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	span := tablepb.Span{TableID: 1}
+	manager.AddTable(span, 1, 100)
+	require.Nil(t, manager.StartTable(span, 2))
+	table, exists := manager.tableSinks.Load(span)
+	require.True(t, exists)
+
+	table.(*tableSinkWrapper).updateReceivedSorterResolvedTs(4)
+	table.(*tableSinkWrapper).updateBarrierTs(4)
+	var task *sinkTask
+	select {
+	case task = <-manager.sinkTaskChan:
+		require.Equal(t, sorter.Position{StartTs: 0, CommitTs: 3}, task.lowerBound)
+	case <-time.After(2 * time.Second):
+		panic("should always get a sink task")
+	}
+
+	// Inject a failure of the sink factory.
+	manager.sinkFactory.Lock()
+	version := manager.sinkFactory.version
+	manager.sinkFactory.Unlock()
+	require.True(t, manager.putSinkFactoryError(errors.New("injected sink factory error"), version))
+	select {
+	case err := <-warnings:
+		require.ErrorContains(t, err, "injected sink factory error")
+	case <-time.After(2 * time.Second):
+		panic("the sink factory error should be reported as a warning")
+	}
+
+	// The sink factory is restarted after the task in flight is finished.
+	require.Eventually(t, task.isCanceled, 2*time.Second, 10*time.Millisecond)
+	// Events in (2, 4] are written into the failed sink factory but not flushed.
+	task.callback(sorter.Position{StartTs: 3, CommitTs: 4})
+
+	// The table is continued from the checkpoint, so there is no data gap.
+	select {
+	case task = <-manager.sinkTaskChan:
+		require.Equal(t, sorter.Position{StartTs: 2, CommitTs: 2}, task.lowerBound)
+		require.False(t, task.isCanceled())
+		task.callback(sorter.Position{StartTs: 3, CommitTs: 4})
+	case <-time.After(2 * time.Second):
+		panic("should always get a sink task")
+	}
+	manager.sinkFactory.Lock()
+	require.Equal(t, version+1, manager.sinkFactory.version)
+	require.NotNil(t, manager.sinkFactory.f)
+	manager.sinkFactory.Unlock()
+	// The table sinks of the failed sink factory can't report errors anymore.
+	require.False(t, manager.putSinkFactoryError(errors.New("stale error"), version))
+}
+
+func TestWaitForBackpressure(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	changefeedInfo.Config.Sink.BackpressureThresholdMs = util.AddressOf(uint64(1000))
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	span := spanz.TableIDToComparableSpan(1)
+	// Tables not in the manager are never blocked.
+	require.Nil(t, manager.WaitForBackpressure(ctx, span))
+
+	// Use a table sink which never flushes events to simulate a slow downstream.
+	wrapper, _ := createTableSinkWrapper(manager.changefeedID, span)
+	startTs := oracle.GoTimeToTS(time.Now())
+	require.Nil(t, wrapper.start(ctx, startTs))
+	manager.tableSinks.Store(span, wrapper)
+
+	resolvedTs := oracle.GoTimeToTS(oracle.GetTimeFromTS(startTs).Add(5 * time.Second))
+	wrapper.updateReceivedSorterResolvedTs(resolvedTs)
+	wrapper.updateBarrierTs(resolvedTs)
+
+	// CheckBackpressure never blocks.
+	checkpointAdvanced := manager.CheckBackpressure(span)
+	require.NotNil(t, checkpointAdvanced)
+	done := make(chan error, 1)
+	go func() { done <- manager.WaitForBackpressure(ctx, span) }()
+	select {
+	case <-done:
+		require.FailNow(t, "puller should be blocked by backpressure")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// The puller is released after the table sink catches up.
+	require.Nil(t, wrapper.updateResolvedTs(model.NewResolvedTs(resolvedTs)))
+	select {
+	case err := <-done:
+		require.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "puller should be released")
+	}
+	select {
+	case <-checkpointAdvanced:
+	default:
+		require.FailNow(t, "checkpoint advancing should be notified")
+	}
+	require.Nil(t, manager.CheckBackpressure(span))
+	require.False(t, wrapper.backpressured.Load())
+}
+
+func TestBackpressureBoundsSorterMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	changefeedInfo.Config.Sink.BackpressureThresholdMs = util.AddressOf(uint64(1000))
+	manager, _, engine := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	// Use a table sink which never flushes events to simulate a slow downstream.
+	span := spanz.TableIDToComparableSpan(1)
+	wrapper, _ := createTableSinkWrapper(manager.changefeedID, span)
+	startTs := oracle.GoTimeToTS(time.Now())
+	require.Nil(t, wrapper.start(ctx, startTs))
+	manager.tableSinks.Store(span, wrapper)
+	engine.AddTable(span, startTs)
+
+	// Simulate a puller which pushes an event every 100ms in ts.
+	eventInterval := 100 * time.Millisecond
+	pullerCtx, pullerCancel := context.WithCancel(ctx)
+	pushed := make(chan int, 1)
+	go func() {
+		i := 0
+		for manager.WaitForBackpressure(pullerCtx, span) == nil {
+			i++
+			commitTs := oracle.GoTimeToTS(
+				oracle.GetTimeFromTS(startTs).Add(time.Duration(i) * eventInterval))
+			engine.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{
+				OpType: model.OpTypePut, Key: []byte("key"), StartTs: commitTs - 1, CRTs: commitTs,
+			}))
+			engine.Add(span, model.NewResolvedPolymorphicEvent(0, commitTs))
+			wrapper.updateReceivedSorterResolvedTs(commitTs)
+			wrapper.updateBarrierTs(commitTs)
+		}
+		pushed <- i
+	}()
+	time.Sleep(500 * time.Millisecond)
+	pullerCancel()
+
+	// The sorter buffers at most the events within the threshold, and the
+	// puller is blocked instead of pushing more.
+	maxEvents := int(time.Second/eventInterval) + 1
+	select {
+	case n := <-pushed:
+		require.Equal(t, maxEvents, n)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "puller should exit after it's canceled")
+	}
+	_, _, count := engine.GetPositionRange(span)
+	// Each event is followed by a resolved event.
+	require.LessOrEqual(t, count, 2*maxEvents)
+}
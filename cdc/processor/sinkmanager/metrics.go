@@ -46,6 +46,33 @@ var (
 		Name:      "output_event_count",
 		Help:      "The number of events output by the sorter",
 	}, []string{"namespace", "changefeed", "type"})
+
+	// backpressureActive is the metric that records how many tables are blocked
+	// from pushing events into the sorter because their sinks are too slow.
+	backpressureActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "backpressure_active",
+		Help:      "The number of tables blocked by backpressure from the sink",
+	}, []string{"namespace", "changefeed"})
+
+	// downstreamBytesWritten is the metric that counts the bytes of the row
+	// changed events appended to table sinks.
+	downstreamBytesWritten = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "processor",
+		Name:      "downstream_bytes_written_total",
+		Help:      "The total bytes of the events written to downstream",
+	}, []string{"namespace", "changefeed"})
+
+	// tableSinkCreationTimeout is the metric that counts how many times the
+	// creation of a table sink times out.
+	tableSinkCreationTimeout = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sink",
+		Name:      "table_creation_timeout_total",
+		Help:      "The total number of table sink creations which time out",
+	}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -53,4 +80,7 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(RedoEventCache)
 	registry.MustRegister(RedoEventCacheAccess)
 	registry.MustRegister(outputEventCount)
+	registry.MustRegister(backpressureActive)
+	registry.MustRegister(downstreamBytesWritten)
+	registry.MustRegister(tableSinkCreationTimeout)
 }
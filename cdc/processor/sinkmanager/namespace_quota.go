@@ -0,0 +1,66 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinkmanager
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// workerQuota limits the number of sink tasks handled at the same time by
+// the changefeeds of a namespace.
+type workerQuota struct {
+	capacity int64
+	used     atomic.Int64
+}
+
+func (q *workerQuota) tryAcquire() bool {
+	for {
+		used := q.used.Load()
+		if used >= q.capacity {
+			return false
+		}
+		if q.used.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
+
+func (q *workerQuota) release() {
+	q.used.Add(-1)
+}
+
+var namespaceWorkerQuotas struct {
+	sync.Mutex
+	quotas map[string]*workerQuota
+}
+
+// getNamespaceWorkerQuota returns the worker quota shared by all changefeeds
+// of the namespace in the capture, or nil if maxWorkers is 0.
+func getNamespaceWorkerQuota(namespace string, maxWorkers int) *workerQuota {
+	if maxWorkers <= 0 {
+		return nil
+	}
+	namespaceWorkerQuotas.Lock()
+	defer namespaceWorkerQuotas.Unlock()
+	if namespaceWorkerQuotas.quotas == nil {
+		namespaceWorkerQuotas.quotas = make(map[string]*workerQuota)
+	}
+	q, ok := namespaceWorkerQuotas.quotas[namespace]
+	if !ok {
+		q = &workerQuota{capacity: int64(maxWorkers)}
+		namespaceWorkerQuotas.quotas[namespace] = q
+	}
+	return q
+}
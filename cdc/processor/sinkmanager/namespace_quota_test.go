@@ -0,0 +1,37 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinkmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceWorkerQuota(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, getNamespaceWorkerQuota("test-worker-quota-unlimited", 0))
+
+	q := getNamespaceWorkerQuota("test-worker-quota", 2)
+	// Changefeeds of the same namespace share the quota.
+	require.Same(t, q, getNamespaceWorkerQuota("test-worker-quota", 2))
+	require.NotSame(t, q, getNamespaceWorkerQuota("test-worker-quota-other", 2))
+
+	require.True(t, q.tryAcquire())
+	require.True(t, q.tryAcquire())
+	require.False(t, q.tryAcquire())
+	q.release()
+	require.True(t, q.tryAcquire())
+}
@@ -32,6 +32,10 @@ type redoWorker struct {
 	sourceManager  *sourcemanager.SourceManager
 	memQuota       *memquota.MemQuota
 	redoDMLManager redo.DMLManager
+	// excludeInvisibleColumns indicates whether to strip invisible columns from rows.
+	excludeInvisibleColumns bool
+	// schemaOnly indicates whether to drop all rows, only DDLs are replicated.
+	schemaOnly bool
 }
 
 func newRedoWorker(
@@ -39,12 +43,16 @@ func newRedoWorker(
 	sourceManager *sourcemanager.SourceManager,
 	quota *memquota.MemQuota,
 	redoDMLMgr redo.DMLManager,
+	excludeInvisibleColumns bool,
+	schemaOnly bool,
 ) *redoWorker {
 	return &redoWorker{
-		changefeedID:   changefeedID,
-		sourceManager:  sourceManager,
-		memQuota:       quota,
-		redoDMLManager: redoDMLMgr,
+		changefeedID:            changefeedID,
+		sourceManager:           sourceManager,
+		memQuota:                quota,
+		redoDMLManager:          redoDMLMgr,
+		excludeInvisibleColumns: excludeInvisibleColumns,
+		schemaOnly:              schemaOnly,
 	}
 }
 
@@ -125,10 +133,10 @@ func (w *redoWorker) handleTask(ctx context.Context, task *redoTask) (finalErr e
 		var x []*model.RowChangedEvent
 		var size uint64
 		// NOTICE: The event can be filtered by the event filter.
-		if e.Row != nil {
+		if e.Row != nil && !w.schemaOnly {
 			// For all events, we add table replicate ts, so mysql sink can determine safe-mode.
 			e.Row.ReplicatingTs = task.tableSink.replicateTs
-			x, size = handleRowChangedEvents(w.changefeedID, task.span, e)
+			x, size = handleRowChangedEvents(w.changefeedID, task.span, w.excludeInvisibleColumns, e)
 			advancer.appendEvents(x, size)
 		}
 
@@ -74,7 +74,7 @@ func (suite *redoLogWorkerSuite) createWorker(
 	redoDMLManager := newMockRedoDMLManager()
 
 	return newRedoWorker(suite.testChangefeedID, sm, quota,
-		redoDMLManager), sortEngine, redoDMLManager
+		redoDMLManager, false, false), sortEngine, redoDMLManager
 }
 
 func (suite *redoLogWorkerSuite) addEventsToSortEngine(
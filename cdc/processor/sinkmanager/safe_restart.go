@@ -0,0 +1,169 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinkmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/factory"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+// SafeRestart closes the sink factory and all table sinks, then creates a new
+// sink factory and continues all tables from their checkpoints. Unlike a table
+// sink restart caused by errors, events in flight are not lost because all
+// sink tasks are finished and no new task is generated during the restart.
+func (m *SinkManager) SafeRestart(ctx context.Context) error {
+	done := make(chan error, 1)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-m.managerCtx.Done():
+		return errors.Trace(m.managerCtx.Err())
+	case m.sinkRestartCh <- done:
+	}
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-m.managerCtx.Done():
+		return errors.Trace(m.managerCtx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
+// safeRestart must be called in the goroutine generating sink tasks.
+func (m *SinkManager) safeRestart(ctx context.Context) error {
+	start := time.Now()
+	log.Info("Sink manager is restarting sink factory safely",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID))
+
+	// Cancel all sink tasks in flight and wait for their callbacks, so that
+	// all table progresses are in the heap.
+	m.sinkRestarting.Store(true)
+	defer m.sinkRestarting.Store(false)
+	ticker := time.NewTicker(defaultGenerateTaskInterval)
+	defer ticker.Stop()
+	for m.sinkTasksInflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		case <-m.sinkWorkerAvailable:
+		}
+	}
+
+	// Advance and save checkpoints of all tables. Events after the checkpoints
+	// can be dropped when table sinks are closed, so tables must be continued
+	// from the checkpoints.
+	checkpoints := spanz.NewHashMap[model.ResolvedTs]()
+	m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
+		checkpoints.ReplaceOrInsert(span, value.(*tableSinkWrapper).getCheckpointTs())
+		return true
+	})
+
+	m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
+		value.(*tableSinkWrapper).closeAndClearTableSink()
+		m.sinkMemQuota.ClearTable(span)
+		return true
+	})
+
+	// Continue all tables from the saved checkpoints. It must be done even if
+	// the new sink factory can't be created, because the table sinks have been
+	// closed and will be re-created by Run later.
+	var restartErr error
+	tableSinks := make([]*tableSinkWrapper, 0, m.sinkProgressHeap.len())
+	progs := make([]*progress, 0, m.sinkProgressHeap.len())
+	for m.sinkProgressHeap.len() > 0 {
+		progs = append(progs, m.sinkProgressHeap.pop())
+	}
+	for _, p := range progs {
+		if value, ok := m.tableSinks.Load(p.span); ok && value.(*tableSinkWrapper).version == p.version {
+			tableSink := value.(*tableSinkWrapper)
+			if err := tableSink.restart(ctx); err != nil && restartErr == nil {
+				restartErr = err
+			}
+			ckpt := checkpoints.GetV(p.span).ResolvedMark()
+			lastWrittenPos := sorter.Position{StartTs: ckpt - 1, CommitTs: ckpt}
+			p.nextLowerBoundPos = lastWrittenPos.Next()
+			tableSinks = append(tableSinks, tableSink)
+		}
+		m.sinkProgressHeap.push(p)
+	}
+	if restartErr != nil {
+		return errors.Trace(restartErr)
+	}
+
+	if err := m.recreateSinkFactory(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, tableSink := range tableSinks {
+		tableSink.initTableSink()
+	}
+
+	log.Info("Sink manager has restarted sink factory safely",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Int("tableCount", checkpoints.Len()),
+		zap.Duration("cost", time.Since(start)))
+	return nil
+}
+
+// recreateSinkFactory closes the current sink factory and creates a new one.
+// The new factory reports errors to the same channel, so Run keeps watching it.
+// The current sink factory can be nil if it failed to be created.
+func (m *SinkManager) recreateSinkFactory() error {
+	m.sinkFactory.Lock()
+	defer m.sinkFactory.Unlock()
+	if m.sinkFactory.errors == nil {
+		return errors.New("sink factory is not initialized")
+	}
+	if m.sinkFactory.f != nil {
+		m.sinkFactory.f.Close()
+		m.sinkFactory.f = nil
+	}
+	// Drop the errors of the closed sink factory, and bump the version so that
+	// the table sinks created by it can't report errors anymore. Otherwise they
+	// trigger another restart.
+DRAIN:
+	for {
+		select {
+		case <-m.sinkFactory.errors:
+		default:
+			break DRAIN
+		}
+	}
+	m.sinkFactory.version++
+
+	var err error
+	m.sinkFactory.f, err = factory.New(m.managerCtx, m.changefeedID,
+		m.changefeedInfo.SinkURI, m.changefeedInfo.Config, m.sinkFactory.errors, m.up.PDClock)
+	if err != nil {
+		// Run will re-create the sink factory after receiving the error.
+		select {
+		case m.sinkFactory.errors <- err:
+		default:
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -63,6 +63,10 @@ type sinkWorker struct {
 	sinkMemQuota  *memquota.MemQuota
 	// splitTxn indicates whether to split the transaction into multiple batches.
 	splitTxn bool
+	// excludeInvisibleColumns indicates whether to strip invisible columns from rows.
+	excludeInvisibleColumns bool
+	// schemaOnly indicates whether to drop all rows, only DDLs are replicated.
+	schemaOnly bool
 
 	// Metrics.
 	metricOutputEventCountKV prometheus.Counter
@@ -74,12 +78,16 @@ func newSinkWorker(
 	sourceManager *sourcemanager.SourceManager,
 	sinkQuota *memquota.MemQuota,
 	splitTxn bool,
+	excludeInvisibleColumns bool,
+	schemaOnly bool,
 ) *sinkWorker {
 	return &sinkWorker{
-		changefeedID:  changefeedID,
-		sourceManager: sourceManager,
-		sinkMemQuota:  sinkQuota,
-		splitTxn:      splitTxn,
+		changefeedID:            changefeedID,
+		sourceManager:           sourceManager,
+		sinkMemQuota:            sinkQuota,
+		splitTxn:                splitTxn,
+		excludeInvisibleColumns: excludeInvisibleColumns,
+		schemaOnly:              schemaOnly,
 
 		metricOutputEventCountKV: outputEventCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID, "kv"),
 	}
@@ -93,6 +101,9 @@ func (w *sinkWorker) handleTasks(ctx context.Context, taskChan <-chan *sinkTask)
 			return ctx.Err()
 		case task := <-taskChan:
 			err := w.handleTask(ctx, task)
+			if task.release != nil {
+				task.release()
+			}
 			failpoint.Inject("SinkWorkerTaskError", func() {
 				err = errors.New("SinkWorkerTaskError")
 			})
@@ -202,10 +213,11 @@ func (w *sinkWorker) handleTask(ctx context.Context, task *sinkTask) (finalErr e
 		advancer.tryMoveToNextTxn(e.CRTs)
 
 		// NOTICE: The event can be filtered by the event filter.
-		if e.Row != nil {
+		// In schema only mode, rows are dropped and only the position advances.
+		if e.Row != nil && !w.schemaOnly {
 			// For all rows, we add table replicate ts, so mysql sink can determine safe-mode.
 			e.Row.ReplicatingTs = task.tableSink.replicateTs
-			x, size := handleRowChangedEvents(w.changefeedID, task.span, e)
+			x, size := handleRowChangedEvents(w.changefeedID, task.span, w.excludeInvisibleColumns, e)
 			advancer.appendEvents(x, size)
 		}
 
@@ -140,7 +140,7 @@ func (suite *tableSinkWorkerSuite) createWorker(
 	quota.ForceAcquire(uint64(testEventSize))
 	quota.AddTable(suite.testSpan)
 
-	return newSinkWorker(suite.testChangefeedID, sm, quota, splitTxn), sortEngine
+	return newSinkWorker(suite.testChangefeedID, sm, quota, splitTxn, false, false), sortEngine
 }
 
 func (suite *tableSinkWorkerSuite) addEventsToSortEngine(
@@ -589,6 +589,50 @@ func (suite *tableSinkWorkerSuite) TestHandleTaskWithSplitTxnAndAdvanceTableIfNo
 	wg.Wait()
 }
 
+// Test Scenario:
+// worker will drop all rows and still advance the table sink in schema only mode.
+func (suite *tableSinkWorkerSuite) TestHandleTaskInSchemaOnlyMode() {
+	ctx, cancel := context.WithCancel(context.Background())
+	events := []*model.PolymorphicEvent{
+		genPolymorphicEvent(1, 2, suite.testSpan),
+		genPolymorphicEvent(1, 3, suite.testSpan),
+		genPolymorphicEvent(1, 4, suite.testSpan),
+		genPolymorphicResolvedEvent(4),
+	}
+	// Only for three events.
+	eventSize := uint64(testEventSize * 3)
+	w, e := suite.createWorker(ctx, eventSize, true)
+	w.schemaOnly = true
+	defer w.sinkMemQuota.Close()
+	suite.addEventsToSortEngine(events, e)
+
+	taskChan := make(chan *sinkTask)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := w.handleTasks(ctx, taskChan)
+		require.ErrorIs(suite.T(), err, context.Canceled)
+	}()
+
+	wrapper, sink := createTableSinkWrapper(suite.testChangefeedID, suite.testSpan)
+	taskChan <- &sinkTask{
+		span:          suite.testSpan,
+		lowerBound:    genLowerBound(),
+		getUpperBound: genUpperBoundGetter(4),
+		tableSink:     wrapper,
+		callback:      func(_ sorter.Position) {},
+		isCanceled:    func() bool { return false },
+	}
+	require.Eventually(suite.T(), func() bool {
+		checkpointTs := wrapper.getCheckpointTs()
+		return checkpointTs.ResolvedMark() == 4
+	}, 5*time.Second, 10*time.Millisecond, "Advance resolved mark to 4 without rows")
+	cancel()
+	wg.Wait()
+	require.Len(suite.T(), sink.GetEvents(), 0)
+}
+
 func (suite *tableSinkWorkerSuite) TestHandleTaskUseDifferentBatchIDEveryTime() {
 	ctx, cancel := context.WithCancel(context.Background())
 	events := []*model.PolymorphicEvent{
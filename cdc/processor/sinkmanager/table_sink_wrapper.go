@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -29,6 +30,7 @@ import (
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
@@ -48,18 +50,31 @@ type tableSinkWrapper struct {
 	span tablepb.Span
 
 	tableSinkCreator func() (tablesink.TableSink, uint64)
+	// sinkCreationTimeout is the max duration of a `tableSinkCreator` call
+	// before it's reported as a timeout. 0 means no timeout.
+	sinkCreationTimeout time.Duration
 
 	// tableSink is the underlying sink.
 	tableSink struct {
 		sync.RWMutex
 		s       tablesink.TableSink
 		version uint64 // it's generated by `tableSinkCreater`.
+		// creating is the result of the in-flight `tableSinkCreator` call,
+		// it's checked again in the next poll if it isn't finished.
+		creating chan tableSinkCreation
+		// creatingSince is when the in-flight creation started or its last
+		// timeout was reported.
+		creatingSince time.Time
 
 		innerMu      sync.Mutex
 		advanced     time.Time
 		resolvedTs   model.ResolvedTs
 		checkpointTs model.ResolvedTs
 		lastSyncedTs model.Ts
+
+		// checkpointAdvanced is closed and replaced when checkpointTs advances,
+		// so that pullers blocked by backpressure can be notified.
+		checkpointAdvanced chan struct{}
 	}
 
 	// state used to control the lifecycle of the table.
@@ -75,6 +90,18 @@ type tableSinkWrapper struct {
 	// receivedSorterResolvedTs is the resolved ts received from the sorter.
 	// We use this to advance the redo log.
 	receivedSorterResolvedTs atomic.Uint64
+	// backpressured is true if the puller of the table is under backpressure.
+	backpressured atomic.Bool
+
+	// rowsInserted, rowsUpdated, rowsDeleted and bytesWritten are the
+	// statistics of the events appended to the table sink.
+	rowsInserted atomic.Uint64
+	rowsUpdated  atomic.Uint64
+	rowsDeleted  atomic.Uint64
+	bytesWritten atomic.Uint64
+
+	metricBytesWritten        prometheus.Counter
+	metricSinkCreationTimeout prometheus.Counter
 
 	// replicateTs is the ts that the table sink has started to replicate.
 	replicateTs    model.Ts
@@ -90,6 +117,11 @@ type tableSinkWrapper struct {
 	rangeEventCountsMu sync.Mutex
 }
 
+type tableSinkCreation struct {
+	s       tablesink.TableSink
+	version uint64
+}
+
 type rangeEventCount struct {
 	// firstPos and lastPos are used to merge many rangeEventCount into one.
 	firstPos sorter.Position
@@ -123,12 +155,18 @@ func newTableSinkWrapper(
 		startTs:          startTs,
 		targetTs:         targetTs,
 		genReplicateTs:   genReplicateTs,
+
+		metricBytesWritten: downstreamBytesWritten.
+			WithLabelValues(changefeed.Namespace, changefeed.ID),
+		metricSinkCreationTimeout: tableSinkCreationTimeout.
+			WithLabelValues(changefeed.Namespace, changefeed.ID),
 	}
 
 	res.tableSink.version = 0
 	res.tableSink.checkpointTs = model.NewResolvedTs(startTs)
 	res.tableSink.resolvedTs = model.NewResolvedTs(startTs)
 	res.tableSink.advanced = time.Now()
+	res.tableSink.checkpointAdvanced = make(chan struct{})
 
 	res.receivedSorterResolvedTs.Store(startTs)
 	res.barrierTs.Store(startTs)
@@ -183,6 +221,24 @@ func (t *tableSinkWrapper) appendRowChangedEvents(events ...*model.RowChangedEve
 		return tablesink.NewSinkInternalError(errors.New("table sink cleared"))
 	}
 	t.tableSink.s.AppendRowChangedEvents(events...)
+
+	var inserted, updated, deleted, bytes uint64
+	for _, e := range events {
+		switch {
+		case e.IsInsert():
+			inserted++
+		case e.IsUpdate():
+			updated++
+		case e.IsDelete():
+			deleted++
+		}
+		bytes += uint64(e.ApproximateBytes())
+	}
+	t.rowsInserted.Add(inserted)
+	t.rowsUpdated.Add(updated)
+	t.rowsDeleted.Add(deleted)
+	t.bytesWritten.Add(bytes)
+	t.metricBytesWritten.Add(float64(bytes))
 	return nil
 }
 
@@ -231,6 +287,7 @@ func (t *tableSinkWrapper) getCheckpointTs() model.ResolvedTs {
 		if t.tableSink.checkpointTs.Less(checkpointTs) {
 			t.tableSink.checkpointTs = checkpointTs
 			t.tableSink.advanced = time.Now()
+			t.notifyCheckpointAdvanced()
 		} else if !checkpointTs.Less(t.tableSink.resolvedTs) {
 			t.tableSink.advanced = time.Now()
 		}
@@ -238,6 +295,28 @@ func (t *tableSinkWrapper) getCheckpointTs() model.ResolvedTs {
 	return t.tableSink.checkpointTs
 }
 
+// notifyCheckpointAdvanced must be called with `tableSink.innerMu` held.
+func (t *tableSinkWrapper) notifyCheckpointAdvanced() {
+	close(t.tableSink.checkpointAdvanced)
+	t.tableSink.checkpointAdvanced = make(chan struct{})
+}
+
+// getBackpressureLag returns the lag between the upper bound and the checkpoint
+// of the table sink, and a channel which will be closed when the checkpoint advances.
+func (t *tableSinkWrapper) getBackpressureLag() (time.Duration, <-chan struct{}) {
+	checkpointTs := t.getCheckpointTs().ResolvedMark()
+	t.tableSink.innerMu.Lock()
+	checkpointAdvanced := t.tableSink.checkpointAdvanced
+	t.tableSink.innerMu.Unlock()
+
+	upperBoundTs := t.getUpperBoundTs()
+	if upperBoundTs <= checkpointTs {
+		return 0, checkpointAdvanced
+	}
+	lag := oracle.GetTimeFromTS(upperBoundTs).Sub(oracle.GetTimeFromTS(checkpointTs))
+	return lag, checkpointAdvanced
+}
+
 func (t *tableSinkWrapper) getReceivedSorterResolvedTs() model.Ts {
 	return t.receivedSorterResolvedTs.Load()
 }
@@ -302,18 +381,52 @@ func (t *tableSinkWrapper) asyncStop() bool {
 }
 
 // Return true means the internal table sink has been initialized.
+// The table sink is created asynchronously so that the caller is never blocked.
+// If the creation isn't finished, it returns false and the creation will be
+// checked again in the next call. A creation which takes longer than
+// `sinkCreationTimeout` is reported as a timeout.
 func (t *tableSinkWrapper) initTableSink() bool {
 	t.tableSink.Lock()
 	defer t.tableSink.Unlock()
-	if t.tableSink.s == nil {
-		t.tableSink.s, t.tableSink.version = t.tableSinkCreator()
-		if t.tableSink.s != nil {
-			t.tableSink.advanced = time.Now()
-			return true
+	if t.tableSink.s != nil {
+		return true
+	}
+
+	if t.tableSink.creating == nil {
+		creating := make(chan tableSinkCreation, 1)
+		creator := t.tableSinkCreator
+		go func() {
+			s, version := creator()
+			creating <- tableSinkCreation{s: s, version: version}
+		}()
+		t.tableSink.creating = creating
+		t.tableSink.creatingSince = time.Now()
+	}
+
+	var res tableSinkCreation
+	select {
+	case res = <-t.tableSink.creating:
+		t.tableSink.creating = nil
+	default:
+		if t.sinkCreationTimeout > 0 && time.Since(t.tableSink.creatingSince) >= t.sinkCreationTimeout {
+			log.Warn("Table sink creation timeout, will check it again later",
+				zap.String("namespace", t.changefeed.Namespace),
+				zap.String("changefeed", t.changefeed.ID),
+				zap.Stringer("span", &t.span),
+				zap.Duration("timeout", t.sinkCreationTimeout))
+			t.metricSinkCreationTimeout.Inc()
+			// Report the timeout once per sinkCreationTimeout.
+			t.tableSink.creatingSince = time.Now()
 		}
 		return false
 	}
-	return true
+
+	t.tableSink.s, t.tableSink.version = res.s, res.version
+	if t.tableSink.s != nil {
+		t.tableSink.advanced = time.Now()
+		return true
+	}
+	return false
 }
 
 func (t *tableSinkWrapper) asyncCloseTableSink() bool {
@@ -350,6 +463,16 @@ func (t *tableSinkWrapper) closeAndClearTableSink() {
 func (t *tableSinkWrapper) doTableSinkClear() {
 	t.tableSink.Lock()
 	defer t.tableSink.Unlock()
+	if creating := t.tableSink.creating; creating != nil {
+		// The table sink may be created by a stale sink factory, close it
+		// once the creation finishes.
+		t.tableSink.creating = nil
+		go func() {
+			if res := <-creating; res.s != nil {
+				res.s.Close()
+			}
+		}()
+	}
 	if t.tableSink.s == nil {
 		return
 	}
@@ -357,6 +480,7 @@ func (t *tableSinkWrapper) doTableSinkClear() {
 	t.tableSink.innerMu.Lock()
 	if t.tableSink.checkpointTs.Less(checkpointTs) {
 		t.tableSink.checkpointTs = checkpointTs
+		t.notifyCheckpointAdvanced()
 	}
 	t.tableSink.resolvedTs = checkpointTs
 	t.tableSink.lastSyncedTs = t.tableSink.s.GetLastSyncedTs()
@@ -462,7 +586,7 @@ func (t *tableSinkWrapper) sinkMaybeStuck(stuckCheck time.Duration) (bool, uint6
 }
 
 func handleRowChangedEvents(
-	changefeed model.ChangeFeedID, span tablepb.Span,
+	changefeed model.ChangeFeedID, span tablepb.Span, excludeInvisibleColumns bool,
 	events ...*model.PolymorphicEvent,
 ) ([]*model.RowChangedEvent, uint64) {
 	size := 0
@@ -490,12 +614,38 @@ func handleRowChangedEvents(
 			continue
 		}
 
+		if excludeInvisibleColumns {
+			rowEvent.Columns = stripInvisibleColumns(rowEvent.TableInfo, rowEvent.Columns)
+			rowEvent.PreColumns = stripInvisibleColumns(rowEvent.TableInfo, rowEvent.PreColumns)
+		}
+
 		size += rowEvent.ApproximateBytes()
 		rowChangedEvents = append(rowChangedEvents, rowEvent)
 	}
 	return rowChangedEvents, uint64(size)
 }
 
+// stripInvisibleColumns removes invisible columns from the row, primary key
+// columns are kept even if they are invisible because they identify the row.
+func stripInvisibleColumns(
+	tableInfo *model.TableInfo, columns []*model.ColumnData,
+) []*model.ColumnData {
+	if tableInfo == nil || len(columns) == 0 {
+		return columns
+	}
+	result := make([]*model.ColumnData, 0, len(columns))
+	for _, col := range columns {
+		if col != nil {
+			colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+			if ok && colInfo.Hidden && !mysql.HasPriKeyFlag(colInfo.GetFlag()) {
+				continue
+			}
+		}
+		result = append(result, col)
+	}
+	return result
+}
+
 func genReplicateTs(ctx context.Context, pdClient pd.Client) (model.Ts, error) {
 	backoffBaseDelayInMs := int64(100)
 	totalRetryDuration := 10 * time.Second
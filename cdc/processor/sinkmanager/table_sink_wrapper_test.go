@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
 )
@@ -162,7 +163,7 @@ func TestHandleNilRowChangedEvents(t *testing.T) {
 	events := []*model.PolymorphicEvent{nil}
 	changefeedID := model.DefaultChangeFeedID("1")
 	span := spanz.TableIDToComparableSpan(1)
-	result, size := handleRowChangedEvents(changefeedID, span, events...)
+	result, size := handleRowChangedEvents(changefeedID, span, false, events...)
 	require.Equal(t, 0, len(result))
 	require.Equal(t, uint64(0), size)
 }
@@ -184,7 +185,7 @@ func TestHandleEmptyRowChangedEvents(t *testing.T) {
 	changefeedID := model.DefaultChangeFeedID("1")
 	span := spanz.TableIDToComparableSpan(1)
 
-	result, size := handleRowChangedEvents(changefeedID, span, events...)
+	result, size := handleRowChangedEvents(changefeedID, span, false, events...)
 	require.Equal(t, 0, len(result))
 	require.Equal(t, uint64(0), size)
 }
@@ -232,11 +233,56 @@ func TestHandleRowChangedEventNormalEvent(t *testing.T) {
 	}
 	changefeedID := model.DefaultChangeFeedID("1")
 	span := spanz.TableIDToComparableSpan(1)
-	result, size := handleRowChangedEvents(changefeedID, span, events...)
+	result, size := handleRowChangedEvents(changefeedID, span, false, events...)
 	require.Equal(t, 1, len(result))
 	require.Equal(t, uint64(testEventSize), size)
 }
 
+func TestHandleRowChangedEventsExcludeInvisibleColumns(t *testing.T) {
+	t.Parallel()
+
+	columns := []*model.Column{
+		{Name: "id", Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: 1},
+		{Name: "visible", Value: "visible-value"},
+		{Name: "invisible", Value: "invisible-value"},
+	}
+	tableInfo := model.BuildTableInfo("test", "test", columns, [][]int{{0}})
+	// the invisible primary key column should never be stripped.
+	tableInfo.Columns[0].Hidden = true
+	tableInfo.Columns[2].Hidden = true
+
+	newEvents := func() []*model.PolymorphicEvent {
+		return []*model.PolymorphicEvent{
+			{
+				CRTs:  1,
+				RawKV: &model.RawKVEntry{OpType: model.OpTypePut},
+				Row: &model.RowChangedEvent{
+					CommitTs:   1,
+					TableInfo:  tableInfo,
+					Columns:    model.Columns2ColumnDatas(columns, tableInfo),
+					PreColumns: model.Columns2ColumnDatas(columns, tableInfo),
+				},
+			},
+		}
+	}
+	changefeedID := model.DefaultChangeFeedID("1")
+	span := spanz.TableIDToComparableSpan(1)
+
+	result, _ := handleRowChangedEvents(changefeedID, span, false, newEvents()...)
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Columns, 3)
+	require.Len(t, result[0].PreColumns, 3)
+
+	result, size := handleRowChangedEvents(changefeedID, span, true, newEvents()...)
+	require.Len(t, result, 1)
+	require.Equal(t, uint64(result[0].ApproximateBytes()), size)
+	for _, cols := range [][]*model.Column{result[0].GetColumns(), result[0].GetPreColumns()} {
+		require.Len(t, cols, 2)
+		require.Equal(t, "id", cols[0].Name)
+		require.Equal(t, "visible", cols[1].Name)
+	}
+}
+
 func TestGetUpperBoundTs(t *testing.T) {
 	t.Parallel()
 	wrapper, _ := createTableSinkWrapper(
@@ -297,7 +343,7 @@ func TestTableSinkWrapperSinkVersion(t *testing.T) {
 		return innerTableSink, *version
 	}
 
-	require.True(t, wrapper.initTableSink())
+	require.Eventually(t, wrapper.initTableSink, time.Second, 10*time.Millisecond)
 	require.Equal(t, wrapper.tableSink.version, uint64(1))
 
 	require.True(t, wrapper.asyncCloseTableSink())
@@ -306,7 +352,7 @@ func TestTableSinkWrapperSinkVersion(t *testing.T) {
 	require.Nil(t, wrapper.tableSink.s)
 	require.Equal(t, wrapper.tableSink.version, uint64(0))
 
-	require.True(t, wrapper.initTableSink())
+	require.Eventually(t, wrapper.initTableSink, time.Second, 10*time.Millisecond)
 	require.Equal(t, wrapper.tableSink.version, uint64(2))
 
 	wrapper.closeTableSink()
@@ -316,6 +362,59 @@ func TestTableSinkWrapperSinkVersion(t *testing.T) {
 	require.Equal(t, wrapper.tableSink.version, uint64(0))
 }
 
+func TestTableSinkWrapperSinkCreationTimeout(t *testing.T) {
+	t.Parallel()
+
+	innerTableSink := tablesink.New[*model.RowChangedEvent](
+		model.ChangeFeedID{}, tablepb.Span{}, model.Ts(0),
+		newMockSink(), &dmlsink.RowChangeEventAppender{},
+		pdutil.NewClock4Test(),
+		prometheus.NewCounter(prometheus.CounterOpts{}),
+		prometheus.NewHistogram(prometheus.HistogramOpts{}),
+	)
+	created := make(chan struct{})
+	creations := 0
+
+	changefeedID := model.DefaultChangeFeedID("test-sink-creation-timeout")
+	wrapper := newTableSinkWrapper(
+		changefeedID,
+		spanz.TableIDToComparableSpan(1),
+		func() (tablesink.TableSink, uint64) {
+			// Simulate a slow sink factory.
+			creations++
+			<-created
+			return innerTableSink, 1
+		},
+		tablepb.TableStatePrepared,
+		model.Ts(10),
+		model.Ts(20),
+		func(_ context.Context) (model.Ts, error) { return math.MaxUint64, nil },
+	)
+	wrapper.sinkCreationTimeout = 100 * time.Millisecond
+
+	timeouts := tableSinkCreationTimeout.WithLabelValues(changefeedID.Namespace, changefeedID.ID)
+	// The creation never blocks the caller.
+	start := time.Now()
+	require.False(t, wrapper.initTableSink())
+	require.Less(t, time.Since(start), wrapper.sinkCreationTimeout)
+	require.Nil(t, wrapper.tableSink.s)
+	require.Equal(t, float64(0), testutil.ToFloat64(timeouts))
+
+	// The timeout is reported once the creation takes longer than sinkCreationTimeout.
+	require.Eventually(t, func() bool {
+		return !wrapper.initTableSink() && testutil.ToFloat64(timeouts) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// The in-flight creation is checked again instead of starting a new one.
+	require.False(t, wrapper.initTableSink())
+	close(created)
+	require.Eventually(t, wrapper.initTableSink, time.Second, 10*time.Millisecond)
+	require.Equal(t, innerTableSink, wrapper.tableSink.s)
+	require.Equal(t, uint64(1), wrapper.tableSink.version)
+	require.Equal(t, 1, creations)
+	require.Nil(t, wrapper.tableSink.creating)
+}
+
 func TestTableSinkWrapperSinkInner(t *testing.T) {
 	t.Parallel()
 
@@ -341,7 +440,7 @@ func TestTableSinkWrapperSinkInner(t *testing.T) {
 		func(_ context.Context) (model.Ts, error) { return math.MaxUint64, nil },
 	)
 
-	require.True(t, wrapper.initTableSink())
+	require.Eventually(t, wrapper.initTableSink, time.Second, 10*time.Millisecond)
 
 	wrapper.closeAndClearTableSink()
 
@@ -351,7 +450,7 @@ func TestTableSinkWrapperSinkInner(t *testing.T) {
 	require.False(t, isStuck)
 
 	// Shouldn't be stuck because tableSink.advanced is just updated.
-	require.True(t, wrapper.initTableSink())
+	require.Eventually(t, wrapper.initTableSink, time.Second, 10*time.Millisecond)
 	isStuck, _ = wrapper.sinkMaybeStuck(100 * time.Millisecond)
 	require.False(t, isStuck)
 
@@ -375,3 +474,62 @@ func TestTableSinkWrapperSinkInner(t *testing.T) {
 	isStuck, _ = wrapper.sinkMaybeStuck(100 * time.Millisecond)
 	require.True(t, isStuck)
 }
+
+func TestTableSinkWrapperBackpressureLag(t *testing.T) {
+	t.Parallel()
+
+	wrapper, _ := createTableSinkWrapper(
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1))
+	startTs := oracle.GoTimeToTS(time.Now())
+	require.Nil(t, wrapper.start(context.Background(), startTs))
+
+	// The upper bound equals to the checkpoint, so there is no lag.
+	lag, _ := wrapper.getBackpressureLag()
+	require.Equal(t, time.Duration(0), lag)
+
+	// The lag is limited by the barrier ts.
+	resolvedTs := oracle.GoTimeToTS(oracle.GetTimeFromTS(startTs).Add(5 * time.Second))
+	wrapper.updateReceivedSorterResolvedTs(resolvedTs)
+	lag, _ = wrapper.getBackpressureLag()
+	require.Equal(t, time.Duration(0), lag)
+
+	wrapper.updateBarrierTs(resolvedTs)
+	lag, checkpointAdvanced := wrapper.getBackpressureLag()
+	require.Equal(t, 5*time.Second, lag)
+
+	// The channel should be closed after the checkpoint advances.
+	require.Nil(t, wrapper.updateResolvedTs(model.NewResolvedTs(resolvedTs)))
+	lag, _ = wrapper.getBackpressureLag()
+	require.Equal(t, time.Duration(0), lag)
+	select {
+	case <-checkpointAdvanced:
+	default:
+		require.FailNow(t, "checkpoint advanced should be notified")
+	}
+}
+
+func TestTableSinkWrapperReplicationStats(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("replication-stats")
+	wrapper, _ := createTableSinkWrapper(changefeedID, spanz.TableIDToComparableSpan(1))
+
+	cols := []*model.ColumnData{{ColumnID: 1, Value: 1}}
+	events := []*model.RowChangedEvent{
+		{CommitTs: 1, Columns: cols},
+		{CommitTs: 1, Columns: cols},
+		{CommitTs: 2, Columns: cols, PreColumns: cols},
+		{CommitTs: 3, PreColumns: cols},
+	}
+	require.NoError(t, wrapper.appendRowChangedEvents(events...))
+	require.Equal(t, uint64(2), wrapper.rowsInserted.Load())
+	require.Equal(t, uint64(1), wrapper.rowsUpdated.Load())
+	require.Equal(t, uint64(1), wrapper.rowsDeleted.Load())
+	var bytes uint64
+	for _, e := range events {
+		bytes += uint64(e.ApproximateBytes())
+	}
+	require.Equal(t, bytes, wrapper.bytesWritten.Load())
+	require.Equal(t, float64(bytes), testutil.ToFloat64(downstreamBytesWritten.
+		WithLabelValues(changefeedID.Namespace, changefeedID.ID)))
+}
@@ -66,6 +66,8 @@ type sinkTask struct {
 	tableSink     *tableSinkWrapper
 	callback      writeSuccessCallback
 	isCanceled    isCanceled
+	// release is called after the task is handled, it can be nil.
+	release func()
 }
 
 // redoTask is a task for the redo log.
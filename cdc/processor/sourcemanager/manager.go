@@ -15,6 +15,7 @@ package sourcemanager
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/log"
@@ -71,6 +72,10 @@ type SourceManager struct {
 	engine sorter.SortEngine
 	// Used to indicate whether the changefeed is in BDR mode.
 	bdrMode bool
+	// backpressure and backpressureCheck are registered by OnBackpressure and
+	// called by pullers.
+	backpressure      atomic.Value
+	backpressureCheck atomic.Value
 
 	// if `multiplexing` is true (the default value) then `multiplexingPuller` will be used.
 	//  * tables in one changefeed will share grpc streams and region workers
@@ -143,7 +148,8 @@ func (m *SourceManager) AddTable(span tablepb.Span, tableName string, startTs mo
 	}
 
 	p := m.tablePullers.pullerWrapperCreator(m.changefeedID, span, tableName, startTs, m.bdrMode)
-	p.Start(m.tablePullers.ctx, m.up, m.engine, m.tablePullers.errChan, m.enableTableMonitor)
+	p.Start(m.tablePullers.ctx, m.up, m.engine, m.tablePullers.errChan, m.enableTableMonitor,
+		m.waitForBackpressure)
 	m.tablePullers.Store(span, p)
 }
 
@@ -166,6 +172,33 @@ func (m *SourceManager) OnResolve(action func(tablepb.Span, model.Ts)) {
 	m.engine.OnResolve(action)
 }
 
+// OnBackpressure registers actions which will be called by pullers before
+// pushing events of a table into the engine, so they can be used to slow down
+// the upstream. The puller of a single table is blocked until wait returns.
+// The multiplexing puller is shared by many tables so it's never blocked,
+// instead it holds back the resolved ts of a table if check reports the table
+// is under backpressure.
+func (m *SourceManager) OnBackpressure(
+	wait pullerwrapper.BackpressureFunc, check pullerwrapper.CheckBackpressureFunc,
+) {
+	m.backpressure.Store(wait)
+	m.backpressureCheck.Store(check)
+}
+
+func (m *SourceManager) waitForBackpressure(ctx context.Context, span tablepb.Span) error {
+	if action, ok := m.backpressure.Load().(pullerwrapper.BackpressureFunc); ok {
+		return action(ctx, span)
+	}
+	return nil
+}
+
+func (m *SourceManager) checkBackpressure(span tablepb.Span) <-chan struct{} {
+	if action, ok := m.backpressureCheck.Load().(pullerwrapper.CheckBackpressureFunc); ok {
+		return action(span)
+	}
+	return nil
+}
+
 // FetchByTable just wrap the engine's FetchByTable method.
 func (m *SourceManager) FetchByTable(
 	span tablepb.Span, lowerBound, upperBound sorter.Position,
@@ -201,6 +234,12 @@ func (m *SourceManager) GetTableSorterStats(span tablepb.Span) sorter.TableStats
 	return m.engine.GetStatsByTable(span)
 }
 
+// GetTablePositionRange returns the position range of the events of the table
+// buffered in the engine.
+func (m *SourceManager) GetTablePositionRange(span tablepb.Span) (minPos, maxPos sorter.Position, count int) {
+	return m.engine.GetPositionRange(span)
+}
+
 // Run implements util.Runnable.
 func (m *SourceManager) Run(ctx context.Context, _ ...chan<- error) error {
 	if m.multiplexing {
@@ -214,6 +253,7 @@ func (m *SourceManager) Run(ctx context.Context, _ ...chan<- error) error {
 		m.multiplexingPuller.puller = pullerwrapper.NewMultiplexingPullerWrapper(
 			m.changefeedID, client, m.engine,
 			int(serverConfig.KVClient.FrontierConcurrent),
+			m.checkBackpressure,
 		)
 
 		close(m.ready)
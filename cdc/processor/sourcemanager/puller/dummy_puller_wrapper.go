@@ -39,7 +39,9 @@ func NewPullerWrapperForTest(
 }
 
 func (d *dummyPullerWrapper) Start(ctx context.Context, up *upstream.Upstream,
-	eventSortEngine sorter.SortEngine, errCh chan<- error, enableTableMonitor bool) {
+	eventSortEngine sorter.SortEngine, errCh chan<- error, enableTableMonitor bool,
+	backpressure BackpressureFunc,
+) {
 }
 
 func (d *dummyPullerWrapper) GetStats() puller.Stats {
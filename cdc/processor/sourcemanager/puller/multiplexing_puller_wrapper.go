@@ -15,6 +15,7 @@ package puller
 
 import (
 	"context"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/kv"
@@ -22,13 +23,33 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
+// heldResolvedTsRecheckInterval is the interval to re-check the backpressure
+// of a table whose resolved ts is held back, even if its checkpoint doesn't
+// advance. For example, the table can be stopped.
+const heldResolvedTsRecheckInterval = 100 * time.Millisecond
+
 // MultiplexingWrapper wraps `puller.MultiplexingPuller`.
 type MultiplexingWrapper struct {
 	changefeed model.ChangeFeedID
 	*puller.MultiplexingPuller
+
+	checkBackpressure CheckBackpressureFunc
+	// held is the resolved ts of tables which are held back by backpressure,
+	// tablepb.Span -> *heldResolvedTs. A table is always consumed in one slot,
+	// so its heldResolvedTs is only accessed by one goroutine.
+	held spanz.SyncMap
+}
+
+// heldResolvedTs is the latest resolved ts of a table under backpressure.
+type heldResolvedTs struct {
+	ts model.Ts
+	// checkpointAdvanced is closed when the checkpoint of the table advances.
+	checkpointAdvanced <-chan struct{}
+	checkedAt          time.Time
 }
 
 // NewMultiplexingPullerWrapper creates a `MultiplexingWrapper`.
@@ -37,7 +58,12 @@ func NewMultiplexingPullerWrapper(
 	client *kv.SharedClient,
 	eventSortEngine sorter.SortEngine,
 	frontiers int,
+	checkBackpressure CheckBackpressureFunc,
 ) *MultiplexingWrapper {
+	w := &MultiplexingWrapper{
+		changefeed:        changefeed,
+		checkBackpressure: checkBackpressure,
+	}
 	consume := func(ctx context.Context, raw *model.RawKVEntry, spans []tablepb.Span) error {
 		if len(spans) > 1 {
 			log.Panic("DML puller subscribes multiple spans",
@@ -45,16 +71,74 @@ func NewMultiplexingPullerWrapper(
 				zap.String("changefeed", changefeed.ID))
 		}
 		if raw != nil {
-			pEvent := model.NewPolymorphicEvent(raw)
-			eventSortEngine.Add(spans[0], pEvent)
+			// Tables in one slot share the same goroutine, so it can't be
+			// blocked by one of them. The resolved ts of a table is held back
+			// instead, so that its sink can't fetch more events.
+			if resolvedTs := w.resolvedTsToAdd(spans[0], raw); resolvedTs > 0 {
+				eventSortEngine.Add(spans[0], model.NewResolvedPolymorphicEvent(0, resolvedTs))
+			}
+			if raw.OpType != model.OpTypeResolved {
+				eventSortEngine.Add(spans[0], model.NewPolymorphicEvent(raw))
+			}
 		}
 		return nil
 	}
 
 	slots, hasher := eventSortEngine.SlotsAndHasher()
-	mp := puller.NewMultiplexingPuller(changefeed, client, consume, slots, hasher, frontiers)
-	return &MultiplexingWrapper{
-		changefeed:         changefeed,
-		MultiplexingPuller: mp,
+	w.MultiplexingPuller = puller.NewMultiplexingPuller(changefeed, client, consume, slots, hasher, frontiers)
+	return w
+}
+
+// Unsubscribe some spans, the held resolved ts of them are dropped.
+func (w *MultiplexingWrapper) Unsubscribe(spans []tablepb.Span) {
+	w.MultiplexingPuller.Unsubscribe(spans)
+	for _, span := range spans {
+		w.held.Delete(span)
+	}
+}
+
+// resolvedTsToAdd returns the resolved ts which should be added into the sort
+// engine before the given event, 0 means nothing. The resolved ts of a table is
+// held back if the table is under backpressure, it's checked again in the next
+// event of the table after the checkpoint of the table advances.
+func (w *MultiplexingWrapper) resolvedTsToAdd(span tablepb.Span, raw *model.RawKVEntry) model.Ts {
+	var resolvedTs model.Ts
+	if raw.OpType == model.OpTypeResolved {
+		resolvedTs = raw.CRTs
+	}
+
+	value, ok := w.held.Load(span)
+	if !ok {
+		if resolvedTs == 0 {
+			return 0
+		}
+		if checkpointAdvanced := w.checkBackpressure(span); checkpointAdvanced != nil {
+			w.held.Store(span, &heldResolvedTs{
+				ts:                 resolvedTs,
+				checkpointAdvanced: checkpointAdvanced,
+				checkedAt:          time.Now(),
+			})
+			return 0
+		}
+		return resolvedTs
+	}
+
+	held := value.(*heldResolvedTs)
+	if resolvedTs > held.ts {
+		held.ts = resolvedTs
+	}
+	select {
+	case <-held.checkpointAdvanced:
+	default:
+		if time.Since(held.checkedAt) < heldResolvedTsRecheckInterval {
+			return 0
+		}
+	}
+	if checkpointAdvanced := w.checkBackpressure(span); checkpointAdvanced != nil {
+		held.checkpointAdvanced = checkpointAdvanced
+		held.checkedAt = time.Now()
+		return 0
 	}
+	w.held.Delete(span)
+	return held.ts
 }
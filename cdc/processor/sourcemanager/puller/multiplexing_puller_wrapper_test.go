@@ -0,0 +1,74 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiplexingWrapperHoldsResolvedTs(t *testing.T) {
+	t.Parallel()
+
+	slow := spanz.TableIDToComparableSpan(1)
+	fast := spanz.TableIDToComparableSpan(2)
+	checkpointAdvanced := make(chan struct{})
+	backpressured := true
+	w := &MultiplexingWrapper{
+		checkBackpressure: func(span tablepb.Span) <-chan struct{} {
+			if span.Eq(&slow) && backpressured {
+				return checkpointAdvanced
+			}
+			return nil
+		},
+	}
+	resolved := func(ts model.Ts) *model.RawKVEntry {
+		return &model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: ts}
+	}
+	put := func(ts model.Ts) *model.RawKVEntry {
+		return &model.RawKVEntry{OpType: model.OpTypePut, StartTs: ts - 1, CRTs: ts}
+	}
+
+	// Tables which aren't under backpressure are never affected.
+	require.Equal(t, model.Ts(10), w.resolvedTsToAdd(fast, resolved(10)))
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(fast, put(11)))
+
+	// The resolved ts of the slow table is held back without blocking.
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(slow, put(9)))
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(slow, resolved(10)))
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(slow, put(11)))
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(slow, resolved(12)))
+	require.Equal(t, model.Ts(20), w.resolvedTsToAdd(fast, resolved(20)))
+
+	// The held resolved ts is released after the checkpoint advances.
+	backpressured = false
+	close(checkpointAdvanced)
+	require.Equal(t, model.Ts(12), w.resolvedTsToAdd(slow, put(13)))
+	require.Equal(t, model.Ts(14), w.resolvedTsToAdd(slow, resolved(14)))
+
+	// The held resolved ts is re-checked periodically even if the checkpoint
+	// doesn't advance.
+	backpressured = true
+	checkpointAdvanced = make(chan struct{})
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(slow, resolved(15)))
+	backpressured = false
+	require.Equal(t, model.Ts(0), w.resolvedTsToAdd(slow, resolved(16)))
+	time.Sleep(heldResolvedTsRecheckInterval)
+	require.Equal(t, model.Ts(17), w.resolvedTsToAdd(slow, resolved(17)))
+}
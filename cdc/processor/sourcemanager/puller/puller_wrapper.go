@@ -27,6 +27,15 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// BackpressureFunc is called by pullers before pushing events of the given span
+// into the sort engine. The puller is blocked until it returns.
+type BackpressureFunc func(ctx context.Context, span tablepb.Span) error
+
+// CheckBackpressureFunc returns nil if the given span isn't under backpressure,
+// otherwise a channel which will be closed when the checkpoint of the span
+// advances. It never blocks.
+type CheckBackpressureFunc func(span tablepb.Span) <-chan struct{}
+
 // Wrapper is a wrapper of puller used by source manager.
 type Wrapper interface {
 	// Start the puller and send internal errors into `errChan`.
@@ -36,6 +45,7 @@ type Wrapper interface {
 		eventSortEngine sorter.SortEngine,
 		errChan chan<- error,
 		enableTableMonitor bool,
+		backpressure BackpressureFunc,
 	)
 	GetStats() puller.Stats
 	Close()
@@ -81,6 +91,7 @@ func (n *WrapperImpl) Start(
 	eventSortEngine sorter.SortEngine,
 	errChan chan<- error,
 	enableTableMonitor bool,
+	backpressure BackpressureFunc,
 ) {
 	ctx, n.cancel = context.WithCancel(ctx)
 	errorHandler := func(err error) {
@@ -129,6 +140,10 @@ func (n *WrapperImpl) Start(
 				if rawKV == nil {
 					continue
 				}
+				// It only fails if the context is canceled.
+				if err := backpressure(ctx, n.span); err != nil {
+					return nil
+				}
 				pEvent := model.NewPolymorphicEvent(rawKV)
 				eventSortEngine.Add(n.span, pEvent)
 			}
@@ -0,0 +1,19 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package adaptive is an EventSortEngine implementation which sorts events
+// in memory, and migrates to pebble once the available memory is low:
+//  1. after the migration starts, new events of all tables are written into pebble;
+//  2. events resolved in memory are still fetched from memory;
+//  3. after all events in memory are consumed, all events are fetched from pebble.
+package adaptive
@@ -0,0 +1,496 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adaptive
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/memory"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+const (
+	backendMemory = 0
+	backendPebble = 1
+
+	memoryCheckInterval = time.Second
+)
+
+var (
+	_ sorter.SortEngine    = (*EventSorter)(nil)
+	_ sorter.EventIterator = (*chainedIter)(nil)
+)
+
+// EventSorter is an adaptive sorter which sorts events in memory, and
+// migrates to pebble when the available memory is below the threshold.
+type EventSorter struct {
+	// Read-only fields.
+	changefeedID model.ChangeFeedID
+	slots        int
+	memoryLimit  uint64
+	threshold    uint64
+	heapInuse    func() uint64
+	createPebble func() (sorter.SortEngine, error)
+	memory       *memory.EventSorter
+	backendGauge prometheus.Gauge
+
+	// All following fields are protected by mu.
+	mu         sync.RWMutex
+	tables     *spanz.HashMap[*tableState]
+	onResolves []func(tablepb.Span, model.Ts)
+	// pebble is set once the migration starts, it's never reset.
+	pebble       sorter.SortEngine
+	memoryClosed bool
+
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+type tableState struct {
+	// inMemory indicates whether the table is added into the memory sorter.
+	inMemory bool
+	// migrated indicates whether new events of the table are written into pebble.
+	migrated bool
+	// boundary is the resolved ts of the table in memory when it's migrated.
+	// Events not greater than it are in memory, others are in pebble.
+	boundary model.Ts
+	// drained indicates whether all events in memory are consumed.
+	drained atomic.Bool
+
+	maxReceivedCommitTs   atomic.Uint64
+	maxReceivedResolvedTs atomic.Uint64
+}
+
+// New creates an EventSorter. The memory backend is used at the beginning,
+// and pebble created by createPebble is used once the available memory,
+// which is memoryLimit minus the heap in use, is less than threshold.
+func New(
+	ID model.ChangeFeedID, slots int, memoryLimit, threshold uint64,
+	createPebble func() (sorter.SortEngine, error),
+) *EventSorter {
+	s := newEventSorter(ID, slots, memoryLimit, threshold, createPebble)
+	s.start()
+	return s
+}
+
+func newEventSorter(
+	ID model.ChangeFeedID, slots int, memoryLimit, threshold uint64,
+	createPebble func() (sorter.SortEngine, error),
+) *EventSorter {
+	s := &EventSorter{
+		changefeedID: ID,
+		slots:        slots,
+		memoryLimit:  memoryLimit,
+		threshold:    threshold,
+		heapInuse:    getHeapInuse,
+		createPebble: createPebble,
+		memory:       memory.New(context.Background()),
+		backendGauge: sorter.BackendGauge().WithLabelValues(ID.Namespace, ID.ID),
+		tables:       spanz.NewHashMap[*tableState](),
+		closed:       make(chan struct{}),
+	}
+	s.backendGauge.Set(backendMemory)
+	return s
+}
+
+func (s *EventSorter) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run()
+	}()
+}
+
+func getHeapInuse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// IsTableBased implements sorter.SortEngine.
+func (s *EventSorter) IsTableBased() bool {
+	return true
+}
+
+// AddTable implements sorter.SortEngine.
+func (s *EventSorter) AddTable(span tablepb.Span, startTs model.Ts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.tables.Get(span); exists {
+		log.Warn("add an exist table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+		return
+	}
+
+	state := &tableState{}
+	state.maxReceivedResolvedTs.Store(startTs)
+	if s.pebble != nil {
+		// The migration has started, the table is added into pebble directly.
+		s.pebble.AddTable(span, startTs)
+		state.migrated = true
+		state.boundary = startTs
+		state.drained.Store(true)
+	} else {
+		s.memory.AddTable(span, startTs)
+		state.inMemory = true
+	}
+	s.tables.ReplaceOrInsert(span, state)
+}
+
+// RemoveTable implements sorter.SortEngine.
+func (s *EventSorter) RemoveTable(span tablepb.Span) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		log.Warn("remove an non-existent table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+		return
+	}
+	s.tables.Delete(span)
+	if state.inMemory && !s.memoryClosed {
+		s.memory.RemoveTable(span)
+	}
+	if state.migrated {
+		s.pebble.RemoveTable(span)
+	}
+}
+
+// Add implements sorter.SortEngine.
+func (s *EventSorter) Add(span tablepb.Span, events ...*model.PolymorphicEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		log.Panic("add events into an non-existent table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+	}
+
+	for _, event := range events {
+		if event.IsResolved() {
+			if event.CRTs > state.maxReceivedResolvedTs.Load() {
+				state.maxReceivedResolvedTs.Store(event.CRTs)
+			}
+		} else if event.CRTs > state.maxReceivedCommitTs.Load() {
+			state.maxReceivedCommitTs.Store(event.CRTs)
+		}
+	}
+	if state.migrated {
+		s.pebble.Add(span, events...)
+	} else {
+		s.memory.Add(span, events...)
+	}
+}
+
+// OnResolve implements sorter.SortEngine.
+func (s *EventSorter) OnResolve(action func(tablepb.Span, model.Ts)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onResolves = append(s.onResolves, action)
+	s.memory.OnResolve(action)
+	if s.pebble != nil {
+		s.pebble.OnResolve(action)
+	}
+}
+
+// FetchByTable implements sorter.SortEngine.
+func (s *EventSorter) FetchByTable(span tablepb.Span, lowerBound, upperBound sorter.Position) sorter.EventIterator {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		log.Panic("fetch events from an non-existent table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+	}
+
+	if !state.migrated {
+		return s.memory.FetchByTable(span, lowerBound, upperBound)
+	}
+	fence := sorter.GenCommitFence(state.boundary)
+	if state.drained.Load() || lowerBound.Compare(fence) > 0 {
+		return s.pebble.FetchByTable(span, lowerBound, upperBound)
+	}
+	if upperBound.Compare(fence) <= 0 {
+		return s.memory.FetchByTable(span, lowerBound, upperBound)
+	}
+	// Events in memory are fetched first, then events in pebble.
+	return &chainedIter{iters: []sorter.EventIterator{
+		s.memory.FetchByTable(span, lowerBound, fence),
+		s.pebble.FetchByTable(span, fence.Next(), upperBound),
+	}}
+}
+
+// FetchAllTables implements sorter.SortEngine.
+func (s *EventSorter) FetchAllTables(lowerBound sorter.Position) sorter.EventIterator {
+	log.Panic("FetchAllTables should never be called",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID))
+	return nil
+}
+
+// CleanByTable implements sorter.SortEngine.
+func (s *EventSorter) CleanByTable(span tablepb.Span, upperBound sorter.Position) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		log.Panic("clean an non-existent table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+	}
+
+	if !state.migrated {
+		return s.memory.CleanByTable(span, upperBound)
+	}
+	if !state.drained.Load() {
+		fence := sorter.GenCommitFence(state.boundary)
+		if upperBound.Compare(fence) < 0 {
+			return s.memory.CleanByTable(span, upperBound)
+		}
+		// All events in memory are consumed, fetch events from pebble only.
+		// The memory sorter is closed after all tables are drained.
+		if err := s.memory.CleanByTable(span, fence); err != nil {
+			return err
+		}
+		state.drained.Store(true)
+	}
+	return s.pebble.CleanByTable(span, upperBound)
+}
+
+// CleanAllTables implements sorter.SortEngine.
+func (s *EventSorter) CleanAllTables(upperBound sorter.Position) error {
+	log.Panic("CleanAllTables should never be called",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID))
+	return nil
+}
+
+// GetPositionRange implements sorter.SortEngine.
+func (s *EventSorter) GetPositionRange(span tablepb.Span) (minPos, maxPos sorter.Position, count int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		return
+	}
+
+	var engines []sorter.SortEngine
+	if state.inMemory && !s.memoryClosed {
+		engines = append(engines, s.memory)
+	}
+	if state.migrated {
+		engines = append(engines, s.pebble)
+	}
+	for _, engine := range engines {
+		lower, upper, n := engine.GetPositionRange(span)
+		if n == 0 {
+			continue
+		}
+		if count == 0 || lower.Compare(minPos) < 0 {
+			minPos = lower
+		}
+		if count == 0 || upper.Compare(maxPos) > 0 {
+			maxPos = upper
+		}
+		count += n
+	}
+	return
+}
+
+// GetStatsByTable implements sorter.SortEngine.
+func (s *EventSorter) GetStatsByTable(span tablepb.Span) sorter.TableStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		log.Panic("Get stats from an non-existent table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+	}
+	return sorter.TableStats{
+		ReceivedMaxCommitTs:   state.maxReceivedCommitTs.Load(),
+		ReceivedMaxResolvedTs: state.maxReceivedResolvedTs.Load(),
+	}
+}
+
+// Close implements sorter.SortEngine.
+func (s *EventSorter) Close() (err error) {
+	close(s.closed)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.memoryClosed {
+		err = multierr.Append(err, s.memory.Close())
+		s.memoryClosed = true
+	}
+	if s.pebble != nil {
+		err = multierr.Append(err, s.pebble.Close())
+	}
+	sorter.BackendGauge().DeleteLabelValues(s.changefeedID.Namespace, s.changefeedID.ID)
+	return
+}
+
+// SlotsAndHasher implements sorter.SortEngine.
+func (s *EventSorter) SlotsAndHasher() (slotCount int, hasher func(tablepb.Span, int) int) {
+	return s.slots, spanz.HashTableSpan
+}
+
+func (s *EventSorter) run() {
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.RLock()
+		migrating := s.pebble != nil
+		s.mu.RUnlock()
+		if !migrating {
+			if !s.isMemoryLow() {
+				continue
+			}
+			if err := s.migrate(); err != nil {
+				log.Warn("migrate sorter backend to pebble failed, retry later",
+					zap.String("namespace", s.changefeedID.Namespace),
+					zap.String("changefeed", s.changefeedID.ID),
+					zap.Error(err))
+			}
+			continue
+		}
+		if s.tryCloseMemory() {
+			return
+		}
+	}
+}
+
+func (s *EventSorter) isMemoryLow() bool {
+	heapInuse := s.heapInuse()
+	if heapInuse >= s.memoryLimit {
+		return true
+	}
+	return s.memoryLimit-heapInuse < s.threshold
+}
+
+// migrate starts the migration, new events of all tables are written
+// into pebble after it returns.
+func (s *EventSorter) migrate() error {
+	pebble, err := s.createPebble()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, action := range s.onResolves {
+		pebble.OnResolve(action)
+	}
+	s.tables.Range(func(span tablepb.Span, state *tableState) bool {
+		// Unresolved events in memory are moved into pebble, so that
+		// all events in memory are not greater than the boundary.
+		resolvedTs, events := s.memory.TakeUnresolved(span)
+		pebble.AddTable(span, resolvedTs)
+		if len(events) > 0 {
+			pebble.Add(span, events...)
+		}
+		state.migrated = true
+		state.boundary = resolvedTs
+		return true
+	})
+	s.pebble = pebble
+	log.Info("sorter backend starts to migrate from memory to pebble",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Int("tables", s.tables.Len()))
+	return nil
+}
+
+// tryCloseMemory closes the memory sorter if all events in it are consumed.
+// It returns true if the migration is finished.
+func (s *EventSorter) tryCloseMemory() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.memoryClosed {
+		return true
+	}
+	drained := true
+	s.tables.Range(func(_ tablepb.Span, state *tableState) bool {
+		drained = state.drained.Load()
+		return drained
+	})
+	if !drained {
+		return false
+	}
+
+	_ = s.memory.Close()
+	s.memoryClosed = true
+	s.backendGauge.Set(backendPebble)
+	log.Info("sorter backend is migrated from memory to pebble",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID))
+	return true
+}
+
+// chainedIter fetches events from iterators one by one.
+type chainedIter struct {
+	iters []sorter.EventIterator
+}
+
+// Next implements sorter.EventIterator.
+func (c *chainedIter) Next() (event *model.PolymorphicEvent, txnFinished sorter.Position, err error) {
+	for len(c.iters) > 0 {
+		event, txnFinished, err = c.iters[0].Next()
+		if event != nil || err != nil {
+			return
+		}
+		if err = c.iters[0].Close(); err != nil {
+			return
+		}
+		c.iters = c.iters[1:]
+	}
+	return
+}
+
+// Close implements sorter.EventIterator.
+func (c *chainedIter) Close() (err error) {
+	for _, iter := range c.iters {
+		err = multierr.Append(err, iter.Close())
+	}
+	c.iters = nil
+	return
+}
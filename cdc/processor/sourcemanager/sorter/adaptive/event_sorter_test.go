@@ -0,0 +1,136 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adaptive
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	epebble "github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/pebble"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(startTs, commitTs model.Ts) *model.PolymorphicEvent {
+	return model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte{1},
+		StartTs: startTs,
+		CRTs:    commitTs,
+	})
+}
+
+func fetchCommitTs(t *testing.T, iter sorter.EventIterator) []model.Ts {
+	defer func() { require.Nil(t, iter.Close()) }()
+	var result []model.Ts
+	for {
+		event, _, err := iter.Next()
+		require.Nil(t, err)
+		if event == nil {
+			return result
+		}
+		result = append(result, event.CRTs)
+	}
+}
+
+func TestMigrateToPebble(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := epebble.OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, nil)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := newEventSorter(cf, 1, 4<<30, 2<<30, func() (sorter.SortEngine, error) {
+		return epebble.New(cf, []*pebble.DB{db}), nil
+	})
+	defer func() { require.Nil(t, s.Close()) }()
+	heapInuse := uint64(1 << 30)
+	s.heapInuse = func() uint64 { return heapInuse }
+	backend := func() float64 {
+		return testutil.ToFloat64(sorter.BackendGauge().WithLabelValues(cf.Namespace, cf.ID))
+	}
+
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 1)
+	resolvedTs := make(chan model.Ts, 16)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs <- ts })
+	waitResolved := func(expected model.Ts) {
+		timer := time.NewTimer(5 * time.Second)
+		defer timer.Stop()
+		for {
+			select {
+			case ts := <-resolvedTs:
+				if ts == expected {
+					return
+				}
+			case <-timer.C:
+				require.FailNow(t, "must get a resolved timestamp instead of timeout")
+			}
+		}
+	}
+
+	s.Add(span, newTestEvent(1, 2), newTestEvent(2, 3))
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 3))
+	waitResolved(3)
+	// The event is not resolved before the migration.
+	s.Add(span, newTestEvent(4, 5))
+	require.False(t, s.isMemoryLow())
+	require.Equal(t, float64(backendMemory), backend())
+
+	// Start to migrate once the available memory is low.
+	heapInuse = 3 << 30
+	require.True(t, s.isMemoryLow())
+	require.Nil(t, s.migrate())
+	s.Add(span, newTestEvent(5, 6))
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 6))
+	waitResolved(6)
+	require.Equal(t, model.Ts(6), s.GetStatsByTable(span).ReceivedMaxCommitTs)
+	require.Equal(t, model.Ts(6), s.GetStatsByTable(span).ReceivedMaxResolvedTs)
+
+	// Events are fetched from memory first, then from pebble.
+	require.Equal(t, []model.Ts{2, 3, 5, 6},
+		fetchCommitTs(t, s.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(6))))
+	require.Equal(t, []model.Ts{2, 3},
+		fetchCommitTs(t, s.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(3))))
+	require.Equal(t, []model.Ts{5, 6},
+		fetchCommitTs(t, s.FetchByTable(span, sorter.GenCommitFence(4), sorter.GenCommitFence(6))))
+
+	// The memory sorter is kept until all events in it are consumed.
+	require.Nil(t, s.CleanByTable(span, sorter.GenCommitFence(2)))
+	require.False(t, s.tryCloseMemory())
+	require.Equal(t, []model.Ts{3, 5, 6},
+		fetchCommitTs(t, s.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(6))))
+	require.Nil(t, s.CleanByTable(span, sorter.GenCommitFence(3)))
+	require.True(t, s.tryCloseMemory())
+	require.Equal(t, float64(backendPebble), backend())
+	require.Equal(t, []model.Ts{5, 6},
+		fetchCommitTs(t, s.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(6))))
+
+	// Tables added after the migration are sorted by pebble.
+	span2 := spanz.TableIDToComparableSpan(2)
+	s.AddTable(span2, 6)
+	s.Add(span2, newTestEvent(6, 7), model.NewResolvedPolymorphicEvent(0, 7))
+	waitResolved(7)
+	require.Equal(t, []model.Ts{7},
+		fetchCommitTs(t, s.FetchByTable(span2, sorter.Position{}, sorter.GenCommitFence(7))))
+	s.RemoveTable(span)
+	s.RemoveTable(span2)
+}
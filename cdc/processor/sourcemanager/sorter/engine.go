@@ -71,6 +71,12 @@ type SortEngine interface {
 	// GetStatsByTable gets the statistics of the given table.
 	GetStatsByTable(span tablepb.Span) TableStats
 
+	// GetPositionRange returns the minimum and maximum positions of events of
+	// the given table buffered in the engine, and the count of the events.
+	// It scans the index of the engine without fetching events, so it should
+	// only be used for debugging. Zero values are returned for an unexist table.
+	GetPositionRange(span tablepb.Span) (minPos, maxPos Position, count int)
+
 	// Close closes the engine. All data written by this instance can be deleted.
 	//
 	// NOTE: it leads an undefined behavior to close an engine with active iterators.
@@ -24,8 +24,10 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/adaptive"
 	epebble "github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/pebble"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/atomic"
 	"go.uber.org/multierr"
 )
@@ -35,6 +37,8 @@ type sortEngineType int
 const (
 	// pebbleEngine details are in package document of pkg/sorter/pebble.
 	pebbleEngine sortEngineType = iota + 1
+	// adaptiveEngine details are in package document of pkg/sorter/adaptive.
+	adaptiveEngine
 
 	metricsCollectInterval = 15 * time.Second
 )
@@ -58,10 +62,15 @@ type SortEngineFactory struct {
 	wg     sync.WaitGroup
 	closed chan struct{}
 
-	// Following fields are valid if engineType is pebbleEngine.
+	// Following fields are valid if engineType is adaptiveEngine.
+	adaptiveThreshold uint64
+
+	// Following fields are valid if engineType is pebbleEngine or adaptiveEngine.
 	pebbleConfig *config.DBConfig
-	dbs          []*pebble.DB
-	writeStalls  []writeStall
+	// dbMu protects dbs and writeStalls, which are created lazily.
+	dbMu        sync.Mutex
+	dbs         []*pebble.DB
+	writeStalls []writeStall
 
 	// dbs is also readed in the background metrics collector.
 	dbInitialized *atomic.Bool
@@ -79,14 +88,29 @@ func (f *SortEngineFactory) Create(ID model.ChangeFeedID) (e sorter.SortEngine,
 		if e, exists = f.engines[ID]; exists {
 			return e, nil
 		}
-		if len(f.dbs) == 0 {
-			f.dbs, f.writeStalls, err = createPebbleDBs(f.dir, f.pebbleConfig, f.memQuotaInBytes)
-			if err != nil {
-				return
-			}
-			f.dbInitialized.Store(true)
+		var dbs []*pebble.DB
+		if dbs, err = f.getOrCreateDBs(); err != nil {
+			return
+		}
+		e = epebble.New(ID, dbs)
+		f.engines[ID] = e
+	case adaptiveEngine:
+		exists := false
+		if e, exists = f.engines[ID]; exists {
+			return e, nil
+		}
+		var memoryLimit uint64
+		if memoryLimit, err = util.GetMemoryLimit(); err != nil {
+			return
 		}
-		e = epebble.New(ID, f.dbs)
+		e = adaptive.New(ID, f.pebbleConfig.Count, memoryLimit, f.adaptiveThreshold,
+			func() (sorter.SortEngine, error) {
+				dbs, err := f.getOrCreateDBs()
+				if err != nil {
+					return nil, err
+				}
+				return epebble.New(ID, dbs), nil
+			})
 		f.engines[ID] = e
 	default:
 		log.Panic("not implemented")
@@ -94,6 +118,21 @@ func (f *SortEngineFactory) Create(ID model.ChangeFeedID) (e sorter.SortEngine,
 	return
 }
 
+// getOrCreateDBs returns the pebble instances, they are created at the first call.
+func (f *SortEngineFactory) getOrCreateDBs() ([]*pebble.DB, error) {
+	f.dbMu.Lock()
+	defer f.dbMu.Unlock()
+	if len(f.dbs) == 0 {
+		dbs, writeStalls, err := createPebbleDBs(f.dir, f.pebbleConfig, f.memQuotaInBytes)
+		if err != nil {
+			return nil, err
+		}
+		f.dbs, f.writeStalls = dbs, writeStalls
+		f.dbInitialized.Store(true)
+	}
+	return f.dbs, nil
+}
+
 // Drop cleans the given event sort engine.
 func (f *SortEngineFactory) Drop(ID model.ChangeFeedID) error {
 	f.mu.Lock()
@@ -122,6 +161,8 @@ func (f *SortEngineFactory) Close() (err error) {
 	for _, engine := range f.engines {
 		err = multierr.Append(err, engine.Close())
 	}
+	f.dbMu.Lock()
+	defer f.dbMu.Unlock()
 	for _, db := range f.dbs {
 		err = multierr.Append(err, db.Close())
 	}
@@ -147,6 +188,30 @@ func NewForPebble(dir string, memQuotaInBytes uint64, cfg *config.DBConfig) *Sor
 	return factory
 }
 
+// NewForAdaptive will create a SortEngineFactory for the adaptive implementation.
+// Engines sort events in memory, and migrate to pebble once the available
+// memory is less than adaptiveThreshold.
+func NewForAdaptive(
+	dir string, memQuotaInBytes uint64, cfg *config.DBConfig, adaptiveThreshold uint64,
+) *SortEngineFactory {
+	factoryMu.Lock()
+	defer factoryMu.Unlock()
+	if factory == nil {
+		factory = &SortEngineFactory{
+			engineType:        adaptiveEngine,
+			dir:               dir,
+			memQuotaInBytes:   memQuotaInBytes,
+			engines:           make(map[model.ChangeFeedID]sorter.SortEngine),
+			closed:            make(chan struct{}),
+			adaptiveThreshold: adaptiveThreshold,
+			pebbleConfig:      cfg,
+			dbInitialized:     atomic.NewBool(false),
+		}
+		factory.startMetricsCollector()
+	}
+	return factory
+}
+
 func (f *SortEngineFactory) startMetricsCollector() {
 	f.wg.Add(1)
 	ticker := time.NewTicker(metricsCollectInterval)
@@ -165,7 +230,8 @@ func (f *SortEngineFactory) startMetricsCollector() {
 }
 
 func (f *SortEngineFactory) collectMetrics() {
-	if f.engineType == pebbleEngine && f.dbInitialized.Load() {
+	// The pebble instances are never changed after they are initialized.
+	if f.dbInitialized.Load() {
 		for i, db := range f.dbs {
 			stats := db.Metrics()
 			id := strconv.Itoa(i + 1)
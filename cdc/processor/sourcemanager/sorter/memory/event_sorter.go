@@ -135,12 +135,32 @@ func (s *EventSorter) GetStatsByTable(span tablepb.Span) sorter.TableStats {
 	return sorter.TableStats{}
 }
 
+// GetPositionRange implements sorter.SortEngine.
+func (s *EventSorter) GetPositionRange(span tablepb.Span) (minPos, maxPos sorter.Position, count int) {
+	value, exists := s.tables.Load(span)
+	if !exists {
+		return
+	}
+	return value.(*tableSorter).positionRange()
+}
+
 // Close implements sorter.SortEngine.
 func (s *EventSorter) Close() error {
 	s.tables = spanz.SyncMap{}
 	return nil
 }
 
+// TakeUnresolved removes all unresolved events of the given table from the
+// sorter, and returns them together with the resolved ts of the table.
+// Events added later are still sorted as usual.
+func (s *EventSorter) TakeUnresolved(span tablepb.Span) (model.Ts, []*model.PolymorphicEvent) {
+	value, exists := s.tables.Load(span)
+	if !exists {
+		log.Panic("take unresolved events from an unexist table", zap.Stringer("span", &span))
+	}
+	return value.(*tableSorter).takeUnresolved()
+}
+
 // SlotsAndHasher implements sorter.SortEngine.
 func (s *EventSorter) SlotsAndHasher() (slotCount int, hasher func(tablepb.Span, int) int) {
 	return 1, func(_ tablepb.Span, _ int) int { return 0 }
@@ -213,6 +233,48 @@ func (s *tableSorter) add(events ...*model.PolymorphicEvent) (resolvedTs model.T
 	return
 }
 
+// positionRange returns the position range of both resolved and unresolved
+// events in the table.
+func (s *tableSorter) positionRange() (minPos, maxPos sorter.Position, count int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	update := func(event *model.PolymorphicEvent) {
+		pos := sorter.Position{StartTs: event.StartTs, CommitTs: event.CRTs}
+		if count == 0 || pos.Compare(minPos) < 0 {
+			minPos = pos
+		}
+		if count == 0 || pos.Compare(maxPos) > 0 {
+			maxPos = pos
+		}
+		count++
+	}
+	// Resolved events are sorted, so only the first and the last are checked.
+	if n := len(s.resolved); n > 0 {
+		update(s.resolved[0])
+		update(s.resolved[n-1])
+		// The count is increased twice above.
+		count += n - 2
+	}
+	for _, event := range s.unresolved {
+		update(event)
+	}
+	return
+}
+
+func (s *tableSorter) takeUnresolved() (resolvedTs model.Ts, events []*model.PolymorphicEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resolvedTs != nil {
+		resolvedTs = *s.resolvedTs
+	}
+	for s.unresolved.Len() > 0 {
+		events = append(events, heap.Pop(&s.unresolved).(*model.PolymorphicEvent))
+	}
+	return
+}
+
 func (s *tableSorter) fetch(
 	span tablepb.Span, lowerBound, upperBound sorter.Position,
 ) sorter.EventIterator {
@@ -208,3 +208,38 @@ func TestEventLess(t *testing.T) {
 		require.Equal(t, tc.expected, eventLess(tc.i, tc.j), "case %d", i)
 	}
 }
+
+func TestGetPositionRange(t *testing.T) {
+	t.Parallel()
+	s := New(context.Background())
+	span := spanz.TableIDToComparableSpan(1)
+
+	_, _, count := s.GetPositionRange(span)
+	require.Equal(t, 0, count)
+
+	s.AddTable(span, 1)
+	for _, entry := range []*model.RawKVEntry{
+		{StartTs: 2, CRTs: 3, OpType: model.OpTypePut},
+		{StartTs: 1, CRTs: 2, OpType: model.OpTypePut},
+		{StartTs: 4, CRTs: 6, OpType: model.OpTypePut},
+	} {
+		s.Add(span, model.NewPolymorphicEvent(entry))
+	}
+	minPos, maxPos, count := s.GetPositionRange(span)
+	require.Equal(t, sorter.Position{StartTs: 1, CommitTs: 2}, minPos)
+	require.Equal(t, sorter.Position{StartTs: 4, CommitTs: 6}, maxPos)
+	require.Equal(t, 3, count)
+
+	// Both resolved and unresolved events are counted.
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 3))
+	s.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{StartTs: 5, CRTs: 7, OpType: model.OpTypePut}))
+	minPos, maxPos, count = s.GetPositionRange(span)
+	require.Equal(t, sorter.Position{StartTs: 1, CommitTs: 2}, minPos)
+	require.Equal(t, sorter.Position{StartTs: 5, CommitTs: 7}, maxPos)
+	require.Equal(t, 4, count)
+
+	require.Nil(t, s.CleanByTable(span, sorter.Position{StartTs: 1, CommitTs: 2}))
+	minPos, _, count = s.GetPositionRange(span)
+	require.Equal(t, sorter.Position{StartTs: 2, CommitTs: 3}, minPos)
+	require.Equal(t, 3, count)
+}
@@ -74,6 +74,15 @@ var (
 		Help:      "The amount of pending data stored on-disk by the sorter",
 	}, []string{"id"})
 
+	// sorterBackendGauge is the metric that records the backend used by the
+	// adaptive sorter, 0 for memory and 1 for pebble.
+	sorterBackendGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "backend",
+		Help:      "The backend of the adaptive sorter, 0 for memory and 1 for pebble",
+	}, []string{"namespace", "changefeed"})
+
 	dbIteratorGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "ticdc",
 		Subsystem: "db",
@@ -142,6 +151,11 @@ func OnDiskDataSize() *prometheus.GaugeVec {
 	return onDiskDataSizeGauge
 }
 
+// BackendGauge returns sorterBackendGauge.
+func BackendGauge() *prometheus.GaugeVec {
+	return sorterBackendGauge
+}
+
 // IteratorGauge returns dbIteratorGauge.
 func IteratorGauge() *prometheus.GaugeVec {
 	return dbIteratorGauge
@@ -176,6 +190,7 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterIterReadDurationHistogram)
 	registry.MustRegister(inMemoryDataSizeGauge)
 	registry.MustRegister(onDiskDataSizeGauge)
+	registry.MustRegister(sorterBackendGauge)
 	registry.MustRegister(dbIteratorGauge)
 
 	// TODO: Seems these things belong to pebble instead of engine.
@@ -135,6 +135,22 @@ func (mr *MockSortEngineMockRecorder) FetchByTable(span, lowerBound, upperBound
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByTable", reflect.TypeOf((*MockSortEngine)(nil).FetchByTable), span, lowerBound, upperBound)
 }
 
+// GetPositionRange mocks base method.
+func (m *MockSortEngine) GetPositionRange(span tablepb.Span) (sorter.Position, sorter.Position, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPositionRange", span)
+	ret0, _ := ret[0].(sorter.Position)
+	ret1, _ := ret[1].(sorter.Position)
+	ret2, _ := ret[2].(int)
+	return ret0, ret1, ret2
+}
+
+// GetPositionRange indicates an expected call of GetPositionRange.
+func (mr *MockSortEngineMockRecorder) GetPositionRange(span interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPositionRange", reflect.TypeOf((*MockSortEngine)(nil).GetPositionRange), span)
+}
+
 // GetStatsByTable mocks base method.
 func (m *MockSortEngine) GetStatsByTable(span tablepb.Span) sorter.TableStats {
 	m.ctrl.T.Helper()
@@ -269,6 +269,40 @@ func (s *EventSorter) GetStatsByTable(span tablepb.Span) sorter.TableStats {
 	}
 }
 
+// GetPositionRange implements sorter.SortEngine.
+//
+// Only events committed into pebble are counted.
+func (s *EventSorter) GetPositionRange(span tablepb.Span) (minPos, maxPos sorter.Position, count int) {
+	s.mu.RLock()
+	state, exists := s.tables.Get(span)
+	s.mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	db := s.dbs[getDB(span, len(s.dbs))]
+	upperBound := sorter.Position{CommitTs: math.MaxUint64, StartTs: math.MaxUint64 - 1}
+	iter := iterTable(db, state.uniqueID, span.TableID, sorter.Position{}, upperBound)
+	defer func() {
+		if err := iter.Close(); err != nil {
+			log.Warn("close pebble iterator failed", zap.Error(err),
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Stringer("span", &span))
+		}
+	}()
+	// Keys are sorted by positions, so values are never decoded.
+	for ; iter.Valid(); iter.Next() {
+		_, _, startTs, commitTs := encoding.DecodeKey(iter.Key())
+		if count == 0 {
+			minPos = sorter.Position{StartTs: startTs, CommitTs: commitTs}
+		}
+		maxPos = sorter.Position{StartTs: startTs, CommitTs: commitTs}
+		count++
+	}
+	return
+}
+
 // Close implements sorter.SortEngine.
 func (s *EventSorter) Close() error {
 	s.mu.Lock()
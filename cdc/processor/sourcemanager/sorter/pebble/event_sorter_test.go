@@ -182,3 +182,45 @@ func TestCleanData(t *testing.T) {
 	require.NoError(t, s.CleanByTable(spanz.TableIDToComparableSpan(2), sorter.Position{}))
 	require.Nil(t, s.CleanByTable(span, sorter.Position{}))
 }
+
+func TestGetPositionRange(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, nil)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := New(cf, []*pebble.DB{db})
+	defer s.Close()
+
+	span := spanz.TableIDToComparableSpan(1)
+	_, _, count := s.GetPositionRange(span)
+	require.Equal(t, 0, count)
+
+	s.AddTable(span, 1)
+	resolvedTs := make(chan model.Ts, 1)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs <- ts })
+	for i, entry := range []*model.RawKVEntry{
+		{StartTs: 2, CRTs: 3, Key: []byte{1}},
+		{StartTs: 1, CRTs: 2, Key: []byte{2}},
+		{StartTs: 4, CRTs: 6, Key: []byte{3}},
+		{StartTs: 4, CRTs: 6, Key: []byte{4}},
+	} {
+		entry.OpType = model.OpTypePut
+		entry.Value = []byte{byte(i)}
+		s.Add(span, model.NewPolymorphicEvent(entry))
+	}
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 6))
+	require.Equal(t, model.Ts(6), <-resolvedTs)
+
+	minPos, maxPos, count := s.GetPositionRange(span)
+	require.Equal(t, sorter.Position{StartTs: 1, CommitTs: 2}, minPos)
+	require.Equal(t, sorter.Position{StartTs: 4, CommitTs: 6}, maxPos)
+	require.Equal(t, 4, count)
+
+	require.Nil(t, s.CleanByTable(span, sorter.Position{StartTs: 2, CommitTs: 3}))
+	minPos, maxPos, count = s.GetPositionRange(span)
+	require.Equal(t, sorter.Position{StartTs: 4, CommitTs: 6}, minPos)
+	require.Equal(t, sorter.Position{StartTs: 4, CommitTs: 6}, maxPos)
+	require.Equal(t, 2, count)
+}
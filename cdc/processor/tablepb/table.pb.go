@@ -184,6 +184,12 @@ type Stats struct {
 	StageCheckpoints map[string]Checkpoint `protobuf:"bytes,3,rep,name=stage_checkpoints,json=stageCheckpoints,proto3" json:"stage_checkpoints" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	// The barrier timestamp of the table.
 	BarrierTs Ts `protobuf:"varint,4,opt,name=barrier_ts,json=barrierTs,proto3,casttype=Ts" json:"barrier_ts,omitempty"`
+	// Number of captured regions which have finished the incremental scan.
+	InitializedRegionCount uint64 `protobuf:"varint,5,opt,name=initialized_region_count,json=initializedRegionCount,proto3" json:"initialized_region_count,omitempty"`
+	// Bytes of the events scanned from TiKV.
+	ScannedBytes uint64 `protobuf:"varint,6,opt,name=scanned_bytes,json=scannedBytes,proto3" json:"scanned_bytes,omitempty"`
+	// Bytes of the events written to the downstream.
+	WrittenBytes uint64 `protobuf:"varint,7,opt,name=written_bytes,json=writtenBytes,proto3" json:"written_bytes,omitempty"`
 }
 
 func (m *Stats) Reset()         { *m = Stats{} }
@@ -247,6 +253,27 @@ func (m *Stats) GetBarrierTs() Ts {
 	return 0
 }
 
+func (m *Stats) GetInitializedRegionCount() uint64 {
+	if m != nil {
+		return m.InitializedRegionCount
+	}
+	return 0
+}
+
+func (m *Stats) GetScannedBytes() uint64 {
+	if m != nil {
+		return m.ScannedBytes
+	}
+	return 0
+}
+
+func (m *Stats) GetWrittenBytes() uint64 {
+	if m != nil {
+		return m.WrittenBytes
+	}
+	return 0
+}
+
 // TableStatus is the running status of a table.
 // TODO rename to TableStatus.
 type TableStatus struct {
@@ -337,52 +364,55 @@ func init() {
 func init() { proto.RegisterFile("processor/tablepb/table.proto", fileDescriptor_ae83c9c6cf5ef75c) }
 
 var fileDescriptor_ae83c9c6cf5ef75c = []byte{
-	// 706 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xbf, 0x6f, 0xd3, 0x4e,
-	0x1c, 0xb5, 0x9d, 0x5f, 0xcd, 0x27, 0xf9, 0x56, 0xee, 0x7d, 0xdb, 0x52, 0x22, 0x91, 0x98, 0xa8,
-	0x40, 0xd5, 0x22, 0x07, 0xca, 0x82, 0xba, 0x35, 0x2d, 0xa0, 0xaa, 0x42, 0x42, 0x4e, 0x60, 0x60,
-	0x89, 0x1c, 0xfb, 0x70, 0xad, 0x86, 0xb3, 0xe5, 0xbb, 0xb4, 0xca, 0xc6, 0x88, 0xb2, 0xd0, 0x09,
-	0xb1, 0x44, 0xea, 0x9f, 0xd3, 0xb1, 0x23, 0x03, 0x8a, 0x20, 0x15, 0x33, 0x7b, 0x27, 0x74, 0x77,
-	0x6e, 0xdc, 0x04, 0x86, 0xd0, 0x25, 0x39, 0xdf, 0x7b, 0x9f, 0xa7, 0xf7, 0xde, 0x9d, 0x0e, 0xee,
-	0x84, 0x51, 0xe0, 0x60, 0x4a, 0x83, 0xa8, 0xc6, 0xec, 0x76, 0x07, 0x87, 0x6d, 0xf9, 0x6f, 0x86,
-	0x51, 0xc0, 0x02, 0xb4, 0x1a, 0xfa, 0xc4, 0x73, 0xec, 0xd0, 0x64, 0xfe, 0xbb, 0x4e, 0x70, 0x6c,
-	0x3a, 0xae, 0x63, 0x8e, 0x27, 0xcc, 0x78, 0xa2, 0xb4, 0xe8, 0x05, 0x5e, 0x20, 0x06, 0x6a, 0x7c,
-	0x25, 0x67, 0xab, 0x9f, 0x54, 0x48, 0x37, 0x42, 0x9b, 0xa0, 0xc7, 0x30, 0x27, 0x98, 0x2d, 0xdf,
-	0x5d, 0x51, 0x0d, 0x75, 0x2d, 0x55, 0x5f, 0x1e, 0x0d, 0x2b, 0xb9, 0x26, 0xdf, 0xdb, 0xdb, 0xbd,
-	0x4c, 0x96, 0x56, 0x4e, 0xf0, 0xf6, 0x5c, 0xb4, 0x0a, 0x79, 0xca, 0xec, 0x88, 0xb5, 0x0e, 0x71,
-	0x6f, 0x45, 0x33, 0xd4, 0xb5, 0x62, 0x3d, 0x77, 0x39, 0xac, 0xa4, 0xf6, 0x71, 0xcf, 0x9a, 0x13,
-	0xc8, 0x3e, 0xee, 0x21, 0x03, 0x72, 0x98, 0xb8, 0x82, 0x93, 0x9a, 0xe4, 0x64, 0x31, 0x71, 0xf7,
-	0x71, 0x6f, 0xab, 0xf8, 0xf1, 0xb4, 0xa2, 0x7c, 0x39, 0xad, 0x28, 0x1f, 0xbe, 0x19, 0x4a, 0xf5,
-	0x44, 0x05, 0xd8, 0x39, 0xc0, 0xce, 0x61, 0x18, 0xf8, 0x84, 0xa1, 0x0d, 0xf8, 0xcf, 0x19, 0x7f,
-	0xb5, 0x18, 0x15, 0xe6, 0xd2, 0xf5, 0xec, 0xe5, 0xb0, 0xa2, 0x35, 0xa9, 0x55, 0x4c, 0xc0, 0x26,
-	0x45, 0x0f, 0xa0, 0x10, 0x61, 0x1a, 0x74, 0x8e, 0xb0, 0xcb, 0xa9, 0xda, 0x04, 0x15, 0xae, 0xa0,
-	0x26, 0x45, 0x0f, 0x61, 0xbe, 0x63, 0x53, 0xd6, 0xa2, 0x3d, 0xe2, 0x48, 0x6e, 0x6a, 0x52, 0x96,
-	0xa3, 0x0d, 0x01, 0x36, 0x69, 0xf5, 0xa7, 0x06, 0x99, 0x06, 0xb3, 0x19, 0x45, 0x77, 0xa1, 0x18,
-	0x61, 0xcf, 0x0f, 0x48, 0xcb, 0x09, 0xba, 0x84, 0x49, 0x33, 0x56, 0x41, 0xee, 0xed, 0xf0, 0x2d,
-	0x74, 0x0f, 0xc0, 0xe9, 0x46, 0x11, 0x96, 0x6e, 0x27, 0x2d, 0xe4, 0x63, 0xa4, 0x49, 0x11, 0x83,
-	0x05, 0xca, 0x6c, 0x0f, 0xb7, 0x92, 0x00, 0xdc, 0x44, 0x6a, 0xad, 0xb0, 0xb9, 0x6d, 0xce, 0x72,
-	0xa0, 0xa6, 0x70, 0xc4, 0x7f, 0x3d, 0x9c, 0xf4, 0x45, 0x9f, 0x11, 0x16, 0xf5, 0xea, 0xe9, 0xb3,
-	0x61, 0x45, 0xb1, 0x74, 0x3a, 0x05, 0x72, 0x73, 0x6d, 0x3b, 0x8a, 0x7c, 0x1c, 0x71, 0x73, 0xe9,
-	0x49, 0x73, 0x31, 0xd2, 0xa4, 0xa5, 0x2e, 0x2c, 0xfd, 0x55, 0x17, 0xe9, 0x90, 0xe2, 0x07, 0xc9,
-	0x63, 0xe7, 0x2d, 0xbe, 0x44, 0xcf, 0x21, 0x73, 0x64, 0x77, 0xba, 0x58, 0x24, 0x2d, 0x6c, 0x3e,
-	0x9a, 0xcd, 0x7b, 0x22, 0x6c, 0xc9, 0xf1, 0x2d, 0xed, 0xa9, 0x5a, 0xfd, 0xa5, 0x41, 0x41, 0xdc,
-	0x32, 0x1e, 0xad, 0x4b, 0x6f, 0x72, 0x27, 0x77, 0x21, 0x4d, 0x43, 0x9b, 0xac, 0x64, 0x84, 0x9b,
-	0xf5, 0x19, 0x9b, 0x0c, 0x6d, 0x12, 0x57, 0x26, 0xa6, 0x79, 0x28, 0xca, 0x6c, 0x26, 0x43, 0xcd,
-	0xcf, 0x1a, 0x6a, 0x6c, 0x1d, 0x5b, 0x72, 0x1c, 0xbd, 0x01, 0x48, 0x8e, 0x57, 0x5c, 0xb1, 0x1b,
-	0x34, 0x14, 0x3b, 0xbb, 0xa6, 0x84, 0x5e, 0x48, 0x7f, 0xf2, 0x04, 0x0b, 0x9b, 0x1b, 0xff, 0x70,
-	0x61, 0x62, 0x35, 0x39, 0xbf, 0xfe, 0x59, 0x03, 0x48, 0x6c, 0xa3, 0x2a, 0xe4, 0x5e, 0x93, 0x43,
-	0x12, 0x1c, 0x13, 0x5d, 0x29, 0x2d, 0xf5, 0x07, 0xc6, 0x42, 0x02, 0xc6, 0x00, 0x32, 0x20, 0xbb,
-	0xdd, 0xa6, 0x98, 0x30, 0x5d, 0x2d, 0x2d, 0xf6, 0x07, 0x86, 0x9e, 0x50, 0xe4, 0x3e, 0xba, 0x0f,
-	0xf9, 0x57, 0x11, 0x0e, 0xed, 0xc8, 0x27, 0x9e, 0xae, 0x95, 0x6e, 0xf5, 0x07, 0xc6, 0xff, 0x09,
-	0x69, 0x0c, 0xa1, 0x55, 0x98, 0x93, 0x1f, 0xd8, 0xd5, 0x53, 0xa5, 0xe5, 0xfe, 0xc0, 0x40, 0xd3,
-	0x34, 0xec, 0xa2, 0x75, 0x28, 0x58, 0x38, 0xec, 0xf8, 0x8e, 0xcd, 0xb8, 0x5e, 0xba, 0x74, 0xbb,
-	0x3f, 0x30, 0x96, 0xae, 0x75, 0x9d, 0x80, 0x5c, 0xb1, 0xc1, 0x82, 0x90, 0xb7, 0xa1, 0x67, 0xa6,
-	0x15, 0xaf, 0x10, 0x9e, 0x52, 0xac, 0xb1, 0xab, 0x67, 0xa7, 0x53, 0xc6, 0x40, 0xfd, 0xe5, 0xf9,
-	0x8f, 0xb2, 0x72, 0x36, 0x2a, 0xab, 0xe7, 0xa3, 0xb2, 0xfa, 0x7d, 0x54, 0x56, 0x4f, 0x2e, 0xca,
-	0xca, 0xf9, 0x45, 0x59, 0xf9, 0x7a, 0x51, 0x56, 0xde, 0xd6, 0x3c, 0x9f, 0x1d, 0x74, 0xdb, 0xa6,
-	0x13, 0xbc, 0xaf, 0xc5, 0xd5, 0xd7, 0x64, 0xf5, 0x35, 0xc7, 0x75, 0x6a, 0x7f, 0x3c, 0xd7, 0xed,
-	0xac, 0x78, 0x6d, 0x9f, 0xfc, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xa3, 0xeb, 0x06, 0x9b, 0xca, 0x05,
-	0x00, 0x00,
+	// 763 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x3f, 0x6f, 0xfb, 0x44,
+	0x18, 0xb6, 0xe3, 0xfc, 0x69, 0x5e, 0xe7, 0x57, 0xb9, 0x47, 0x5b, 0x42, 0x24, 0x12, 0x13, 0x0a,
+	0x54, 0x2d, 0x72, 0x20, 0x2c, 0x55, 0xb7, 0xa6, 0x05, 0x54, 0x55, 0x48, 0xc8, 0x09, 0x0c, 0x2c,
+	0xd6, 0xc5, 0x3e, 0x5c, 0xab, 0xe1, 0x6c, 0xf9, 0x2e, 0xad, 0xc2, 0xc4, 0x88, 0xb2, 0xd0, 0x09,
+	0xb1, 0x44, 0xea, 0xc7, 0xe9, 0xd8, 0x91, 0x01, 0x45, 0x90, 0x4e, 0x4c, 0xec, 0x9d, 0xd0, 0x9d,
+	0xdd, 0x38, 0x49, 0x19, 0x42, 0x97, 0xe4, 0xfc, 0x3e, 0xcf, 0xfb, 0xea, 0x79, 0x9e, 0x7b, 0x13,
+	0xc3, 0xbb, 0x51, 0x1c, 0xba, 0x84, 0xb1, 0x30, 0x6e, 0x71, 0xdc, 0x1f, 0x90, 0xa8, 0x9f, 0x7c,
+	0x5b, 0x51, 0x1c, 0xf2, 0x10, 0xed, 0x45, 0x01, 0xf5, 0x5d, 0x1c, 0x59, 0x3c, 0xf8, 0x7e, 0x10,
+	0xde, 0x58, 0xae, 0xe7, 0x5a, 0xf3, 0x0e, 0x2b, 0xed, 0xa8, 0x6d, 0xfb, 0xa1, 0x1f, 0xca, 0x86,
+	0x96, 0x38, 0x25, 0xbd, 0xcd, 0x5f, 0x54, 0xc8, 0x77, 0x23, 0x4c, 0xd1, 0xa7, 0xb0, 0x21, 0x99,
+	0x4e, 0xe0, 0x55, 0x55, 0x53, 0xdd, 0xd7, 0x3a, 0xbb, 0xb3, 0x69, 0xa3, 0xd4, 0x13, 0xb5, 0xf3,
+	0xb3, 0xa7, 0xec, 0x68, 0x97, 0x24, 0xef, 0xdc, 0x43, 0x7b, 0x50, 0x66, 0x1c, 0xc7, 0xdc, 0xb9,
+	0x22, 0xa3, 0x6a, 0xce, 0x54, 0xf7, 0x2b, 0x9d, 0xd2, 0xd3, 0xb4, 0xa1, 0x5d, 0x90, 0x91, 0xbd,
+	0x21, 0x91, 0x0b, 0x32, 0x42, 0x26, 0x94, 0x08, 0xf5, 0x24, 0x47, 0x5b, 0xe6, 0x14, 0x09, 0xf5,
+	0x2e, 0xc8, 0xe8, 0xb8, 0xf2, 0xf3, 0x5d, 0x43, 0xf9, 0xed, 0xae, 0xa1, 0xfc, 0xf4, 0x87, 0xa9,
+	0x34, 0x6f, 0x55, 0x80, 0xd3, 0x4b, 0xe2, 0x5e, 0x45, 0x61, 0x40, 0x39, 0x3a, 0x84, 0x37, 0xee,
+	0xfc, 0xc9, 0xe1, 0x4c, 0x8a, 0xcb, 0x77, 0x8a, 0x4f, 0xd3, 0x46, 0xae, 0xc7, 0xec, 0x4a, 0x06,
+	0xf6, 0x18, 0xfa, 0x08, 0xf4, 0x98, 0xb0, 0x70, 0x70, 0x4d, 0x3c, 0x41, 0xcd, 0x2d, 0x51, 0xe1,
+	0x19, 0xea, 0x31, 0xf4, 0x31, 0x6c, 0x0e, 0x30, 0xe3, 0x0e, 0x1b, 0x51, 0x37, 0xe1, 0x6a, 0xcb,
+	0x63, 0x05, 0xda, 0x95, 0x60, 0x8f, 0x35, 0xff, 0xd6, 0xa0, 0xd0, 0xe5, 0x98, 0x33, 0xf4, 0x1e,
+	0x54, 0x62, 0xe2, 0x07, 0x21, 0x75, 0xdc, 0x70, 0x48, 0x79, 0x22, 0xc6, 0xd6, 0x93, 0xda, 0xa9,
+	0x28, 0xa1, 0x0f, 0x00, 0xdc, 0x61, 0x1c, 0x13, 0xca, 0x5f, 0x4a, 0x28, 0xa7, 0x48, 0x8f, 0x21,
+	0x0e, 0x5b, 0x8c, 0x63, 0x9f, 0x38, 0x99, 0x01, 0x21, 0x42, 0xdb, 0xd7, 0xdb, 0x27, 0xd6, 0x3a,
+	0x17, 0x6a, 0x49, 0x45, 0xe2, 0xd3, 0x27, 0x59, 0x5e, 0xec, 0x73, 0xca, 0xe3, 0x51, 0x27, 0x7f,
+	0x3f, 0x6d, 0x28, 0xb6, 0xc1, 0x56, 0x40, 0x21, 0xae, 0x8f, 0xe3, 0x38, 0x20, 0xb1, 0x10, 0x97,
+	0x5f, 0x16, 0x97, 0x22, 0x3d, 0x86, 0x8e, 0xa0, 0x1a, 0xd0, 0x80, 0x07, 0x78, 0x10, 0xfc, 0x48,
+	0x3c, 0x67, 0xc9, 0x72, 0x41, 0x5a, 0xde, 0x5d, 0xc0, 0xed, 0x05, 0xf7, 0xef, 0xc3, 0x1b, 0xe6,
+	0x62, 0x4a, 0x89, 0xe7, 0xf4, 0x47, 0x9c, 0xb0, 0x6a, 0x51, 0xd2, 0x2b, 0x69, 0xb1, 0x23, 0x6a,
+	0x82, 0x74, 0x13, 0x07, 0x9c, 0x13, 0x9a, 0x92, 0x4a, 0x09, 0x29, 0x2d, 0x4a, 0x52, 0x6d, 0x08,
+	0x3b, 0xff, 0xe9, 0x0d, 0x19, 0xa0, 0x89, 0x65, 0x12, 0xd1, 0x97, 0x6d, 0x71, 0x44, 0x5f, 0x40,
+	0xe1, 0x1a, 0x0f, 0x86, 0x44, 0xa6, 0xad, 0xb7, 0x3f, 0x59, 0x2f, 0xbf, 0x6c, 0xb0, 0x9d, 0xb4,
+	0x1f, 0xe7, 0x8e, 0xd4, 0xe6, 0x3f, 0x39, 0xd0, 0xe5, 0xa6, 0x8b, 0x78, 0x87, 0xec, 0x35, 0xbf,
+	0x8b, 0x33, 0xc8, 0xb3, 0x08, 0x53, 0x99, 0x94, 0xde, 0x3e, 0x58, 0xf3, 0x36, 0x23, 0x4c, 0xd3,
+	0x6b, 0x93, 0xdd, 0xc2, 0x14, 0xe3, 0x98, 0x27, 0xa6, 0x36, 0xd7, 0x35, 0x35, 0x97, 0x4e, 0xec,
+	0xa4, 0x1d, 0x7d, 0x0b, 0x90, 0xad, 0x58, 0x55, 0x7b, 0x5d, 0x42, 0xa9, 0xb2, 0x85, 0x49, 0xe8,
+	0xcb, 0x44, 0x5f, 0xb2, 0x45, 0x7a, 0xfb, 0xf0, 0x7f, 0x2c, 0x6d, 0x3a, 0x2d, 0xe9, 0x3f, 0xf8,
+	0x35, 0x07, 0x90, 0xc9, 0x46, 0x4d, 0x28, 0x7d, 0x43, 0xaf, 0x68, 0x78, 0x43, 0x0d, 0xa5, 0xb6,
+	0x33, 0x9e, 0x98, 0x5b, 0x19, 0x98, 0x02, 0xc8, 0x84, 0xe2, 0x49, 0x9f, 0x11, 0xca, 0x0d, 0xb5,
+	0xb6, 0x3d, 0x9e, 0x98, 0x46, 0x46, 0x49, 0xea, 0xe8, 0x43, 0x28, 0x7f, 0x1d, 0x93, 0x08, 0xc7,
+	0x01, 0xf5, 0x8d, 0x5c, 0xed, 0xed, 0xf1, 0xc4, 0x7c, 0x2b, 0x23, 0xcd, 0x21, 0xb4, 0x07, 0x1b,
+	0xc9, 0x03, 0xf1, 0x0c, 0xad, 0xb6, 0x3b, 0x9e, 0x98, 0x68, 0x95, 0x46, 0x3c, 0x74, 0x00, 0xba,
+	0x4d, 0xa2, 0x41, 0xe0, 0x62, 0x2e, 0xe6, 0xe5, 0x6b, 0xef, 0x8c, 0x27, 0xe6, 0xce, 0x42, 0xd6,
+	0x19, 0x28, 0x26, 0x76, 0x79, 0x18, 0x89, 0x34, 0x8c, 0xc2, 0xea, 0xc4, 0x67, 0x44, 0xb8, 0x94,
+	0x67, 0xe2, 0x19, 0xc5, 0x55, 0x97, 0x29, 0xd0, 0xf9, 0xea, 0xe1, 0xaf, 0xba, 0x72, 0x3f, 0xab,
+	0xab, 0x0f, 0xb3, 0xba, 0xfa, 0xe7, 0xac, 0xae, 0xde, 0x3e, 0xd6, 0x95, 0x87, 0xc7, 0xba, 0xf2,
+	0xfb, 0x63, 0x5d, 0xf9, 0xae, 0xe5, 0x07, 0xfc, 0x72, 0xd8, 0xb7, 0xdc, 0xf0, 0x87, 0x56, 0x1a,
+	0x7d, 0x2b, 0x89, 0xbe, 0xe5, 0x7a, 0x6e, 0xeb, 0xc5, 0x2b, 0xa3, 0x5f, 0x94, 0xff, 0xf8, 0x9f,
+	0xfd, 0x3b, 0x00, 0xd7, 0x24, 0xde, 0x61, 0x4e, 0x06, 0x00, 0x00,
 }
 
 func (m *Span) Marshal() (dAtA []byte, err error) {
@@ -485,6 +515,21 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.WrittenBytes != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.WrittenBytes))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.ScannedBytes != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.ScannedBytes))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.InitializedRegionCount != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.InitializedRegionCount))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.BarrierTs != 0 {
 		i = encodeVarintTable(dAtA, i, uint64(m.BarrierTs))
 		i--
@@ -663,6 +708,15 @@ func (m *Stats) Size() (n int) {
 	if m.BarrierTs != 0 {
 		n += 1 + sovTable(uint64(m.BarrierTs))
 	}
+	if m.InitializedRegionCount != 0 {
+		n += 1 + sovTable(uint64(m.InitializedRegionCount))
+	}
+	if m.ScannedBytes != 0 {
+		n += 1 + sovTable(uint64(m.ScannedBytes))
+	}
+	if m.WrittenBytes != 0 {
+		n += 1 + sovTable(uint64(m.WrittenBytes))
+	}
 	return n
 }
 
@@ -1152,6 +1206,63 @@ func (m *Stats) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InitializedRegionCount", wireType)
+			}
+			m.InitializedRegionCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InitializedRegionCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScannedBytes", wireType)
+			}
+			m.ScannedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ScannedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WrittenBytes", wireType)
+			}
+			m.WrittenBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WrittenBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTable(dAtA[iNdEx:])
@@ -27,6 +27,17 @@ var PullerEventCounter = prometheus.NewCounterVec(
 		Help:      "The number of events received by a puller",
 	}, []string{"namespace", "changefeed", "type"})
 
+// tikvBytesScannedCounter is the counter of the bytes of the kv events received
+// from TiKV. Compared with the bytes written to the downstream, it shows how
+// much data is read but filtered or deduplicated before reaching downstream.
+var tikvBytesScannedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "processor",
+		Name:      "tikv_bytes_scanned_total",
+		Help:      "The total bytes of the events scanned from TiKV",
+	}, []string{"namespace", "changefeed"})
+
 var pullerQueueDuration = prometheus.NewHistogramVec(
 	prometheus.HistogramOpts{
 		Namespace: "ticdc",
@@ -42,4 +53,5 @@ var pullerQueueDuration = prometheus.NewHistogramVec(
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(PullerEventCounter)
 	registry.MustRegister(pullerQueueDuration)
+	registry.MustRegister(tikvBytesScannedCounter)
 }
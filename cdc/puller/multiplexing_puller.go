@@ -53,6 +53,7 @@ type tableProgress struct {
 	resolvedTsUpdated    atomic.Int64
 	resolvedTs           atomic.Uint64
 	maxIngressResolvedTs atomic.Uint64
+	scannedBytes         atomic.Uint64
 
 	resolvedEventsCache chan kv.MultiplexingEvent
 	tsTracker           frontier.Frontier
@@ -94,6 +95,7 @@ type MultiplexingPuller struct {
 	CounterKv              prometheus.Counter
 	CounterResolved        prometheus.Counter
 	CounterResolvedDropped prometheus.Counter
+	counterScannedBytes    prometheus.Counter
 	queueKvDuration        prometheus.Observer
 	queueResolvedDuration  prometheus.Observer
 }
@@ -231,12 +233,14 @@ func (p *MultiplexingPuller) run(ctx context.Context, includeClient bool) error
 	p.CounterKv = PullerEventCounter.WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 	p.CounterResolved = PullerEventCounter.WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
 	p.CounterResolvedDropped = PullerEventCounter.WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved-dropped")
+	p.counterScannedBytes = tikvBytesScannedCounter.WithLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 	p.queueKvDuration = pullerQueueDuration.WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 	p.queueResolvedDuration = pullerQueueDuration.WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
 	defer func() {
 		PullerEventCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 		PullerEventCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
 		PullerEventCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved-dropped")
+		tikvBytesScannedCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 		pullerQueueDuration.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 		pullerQueueDuration.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
 		log.Info("MultiplexingPuller exits",
@@ -284,6 +288,9 @@ func (p *MultiplexingPuller) handleInputCh(ctx context.Context, inputCh <-chan k
 		if e.Val != nil {
 			p.queueKvDuration.Observe(float64(time.Since(e.Start).Milliseconds()))
 			p.CounterKv.Inc()
+			size := uint64(e.Val.ApproximateDataSize())
+			p.counterScannedBytes.Add(float64(size))
+			progress.scannedBytes.Add(size)
 			if err := progress.consume.f(ctx, e.Val, progress.spans); err != nil {
 				return errors.Trace(err)
 			}
@@ -447,10 +454,12 @@ func (p *MultiplexingPuller) Stats(span tablepb.Span) Stats {
 		return Stats{}
 	}
 	return Stats{
-		RegionCount:         p.client.RegionCount(progress.subID),
-		ResolvedTsIngress:   progress.maxIngressResolvedTs.Load(),
-		CheckpointTsIngress: progress.maxIngressResolvedTs.Load(),
-		ResolvedTsEgress:    progress.resolvedTs.Load(),
-		CheckpointTsEgress:  progress.resolvedTs.Load(),
+		RegionCount:            p.client.RegionCount(progress.subID),
+		InitializedRegionCount: p.client.InitializedRegionCount(progress.subID),
+		ScannedBytes:           progress.scannedBytes.Load(),
+		ResolvedTsIngress:      progress.maxIngressResolvedTs.Load(),
+		CheckpointTsIngress:    progress.maxIngressResolvedTs.Load(),
+		ResolvedTsEgress:       progress.resolvedTs.Load(),
+		CheckpointTsEgress:     progress.resolvedTs.Load(),
 	}
 }
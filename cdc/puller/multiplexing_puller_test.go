@@ -98,3 +98,37 @@ func TestMultiplexingPullerResolvedForward(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestMultiplexingPullerScannedBytes(t *testing.T) {
+	outputCh := make(chan *model.RawKVEntry, 16)
+	puller := newMultiplexingPullerForTest(outputCh)
+	defer puller.client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		puller.run(ctx, false)
+	}()
+
+	spans := []tablepb.Span{spanz.ToSpan([]byte("t_a"), []byte("t_e"))}
+	spans[0].TableID = 1
+	subID := puller.subscribe(spans, 996, "test")[0]
+	entry := &model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("t_b"), Value: []byte("value"), CRTs: 1000,
+	}
+	for i := 0; i < 2; i++ {
+		puller.inputChs[0] <- kv.MultiplexingEvent{
+			RegionFeedEvent: model.RegionFeedEvent{Val: entry}, SubscriptionID: subID,
+		}
+		select {
+		case <-outputCh:
+		case <-time.NewTimer(time.Second).C:
+			require.True(t, false, "must get an event")
+		}
+	}
+	require.Equal(t, uint64(2*entry.ApproximateDataSize()), puller.Stats(spans[0]).ScannedBytes)
+	cancel()
+	wg.Wait()
+}
@@ -43,11 +43,14 @@ const (
 
 // Stats of a puller.
 type Stats struct {
-	RegionCount         uint64
-	CheckpointTsIngress model.Ts
-	ResolvedTsIngress   model.Ts
-	CheckpointTsEgress  model.Ts
-	ResolvedTsEgress    model.Ts
+	RegionCount            uint64
+	InitializedRegionCount uint64
+	CheckpointTsIngress    model.Ts
+	ResolvedTsIngress      model.Ts
+	CheckpointTsEgress     model.Ts
+	ResolvedTsEgress       model.Ts
+	// ScannedBytes is the bytes of the kv events received from TiKV.
+	ScannedBytes uint64
 }
 
 // Puller pull data from tikv and push changes into a buffer.
@@ -68,6 +71,8 @@ type pullerImpl struct {
 	checkpointTs uint64
 	// The latest resolved ts that puller has sent.
 	resolvedTs uint64
+	// The bytes of the kv events that puller has received.
+	scannedBytes uint64
 
 	changefeed model.ChangeFeedID
 	tableID    model.TableID
@@ -149,6 +154,8 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 		WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 	metricPullerEventCounterResolved := PullerEventCounter.
 		WithLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
+	metricTiKVBytesScanned := tikvBytesScannedCounter.
+		WithLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 
 	lastResolvedTs := p.checkpointTs
 	lastAdvancedTime := time.Now()
@@ -204,6 +211,9 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 
 			if e.Val != nil {
 				metricPullerEventCounterKv.Inc()
+				size := e.Val.ApproximateDataSize()
+				metricTiKVBytesScanned.Add(float64(size))
+				atomic.AddUint64(&p.scannedBytes, uint64(size))
 				if err := output(e.Val); err != nil {
 					return errors.Trace(err)
 				}
@@ -346,10 +356,12 @@ func (p *pullerImpl) Output() <-chan *model.RawKVEntry {
 
 func (p *pullerImpl) Stats() Stats {
 	return Stats{
-		RegionCount:         p.kvCli.RegionCount(),
-		ResolvedTsIngress:   p.kvCli.ResolvedTs(),
-		CheckpointTsIngress: p.kvCli.CommitTs(),
-		ResolvedTsEgress:    atomic.LoadUint64(&p.resolvedTs),
-		CheckpointTsEgress:  atomic.LoadUint64(&p.checkpointTs),
+		RegionCount:            p.kvCli.RegionCount(),
+		InitializedRegionCount: p.kvCli.InitializedRegionCount(),
+		ScannedBytes:           atomic.LoadUint64(&p.scannedBytes),
+		ResolvedTsIngress:      p.kvCli.ResolvedTs(),
+		CheckpointTsIngress:    p.kvCli.CommitTs(),
+		ResolvedTsEgress:       atomic.LoadUint64(&p.resolvedTs),
+		CheckpointTsEgress:     atomic.LoadUint64(&p.checkpointTs),
 	}
 }
@@ -61,6 +61,16 @@ type Scheduler interface {
 	// It is thread-safe.
 	DrainCapture(target model.CaptureID) (int, error)
 
+	// RegionScanProgress returns the count of captured regions and the count
+	// of regions which have finished the incremental scan, of all tables.
+	// It is thread-safe.
+	RegionScanProgress() (total, scanned uint64)
+
+	// ReplicationBytes returns the bytes scanned from TiKV and the bytes
+	// written to the downstream, of all tables.
+	// It is thread-safe.
+	ReplicationBytes() (scanned, written uint64)
+
 	// Close scheduler and release resource.
 	// It is not thread-safe.
 	Close(ctx context.Context)
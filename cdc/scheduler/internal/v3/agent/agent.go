@@ -247,9 +247,13 @@ func (a *agent) handleMessage(msg []*schedulepb.Message) (result []*schedulepb.M
 
 		switch message.GetMsgType() {
 		case schedulepb.MsgHeartbeat:
-			var reMsg *schedulepb.Message
-			reMsg, barrier = a.handleMessageHeartbeat(message.GetHeartbeat())
+			reMsg, b := a.handleMessageHeartbeat(message.GetHeartbeat())
 			result = append(result, reMsg)
+			// The barrier may be omitted by heartbeats, see
+			// ResolvedTsBroadcastIntervalMs.
+			if b != nil {
+				barrier = b
+			}
 		case schedulepb.MsgDispatchTableRequest:
 			a.handleMessageDispatchTableRequest(message.DispatchTableRequest, processorEpoch)
 		default:
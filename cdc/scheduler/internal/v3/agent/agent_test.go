@@ -633,6 +633,37 @@ func TestAgentHandleMessage(t *testing.T) {
 	require.Len(t, response, 0)
 }
 
+func TestAgentHandleHeartbeatWithoutBarrier(t *testing.T) {
+	t.Parallel()
+
+	a := newAgent4Test()
+	a.tableM = newTableSpanManager(model.ChangeFeedID{}, newMockTableExecutor())
+	newHeartbeat := func(barrier *schedulepb.Barrier) *schedulepb.Message {
+		return &schedulepb.Message{
+			Header: &schedulepb.Message_Header{
+				Version:       a.ownerInfo.Version,
+				OwnerRevision: a.ownerInfo.Revision,
+			},
+			MsgType:   schedulepb.MsgHeartbeat,
+			From:      a.ownerInfo.ID,
+			Heartbeat: &schedulepb.Heartbeat{Barrier: barrier},
+		}
+	}
+
+	// The heartbeat without barrier is still responded, and it doesn't
+	// override the barrier of a former heartbeat.
+	barrier := &schedulepb.Barrier{GlobalBarrierTs: 10}
+	responses, b := a.handleMessage([]*schedulepb.Message{
+		newHeartbeat(barrier), newHeartbeat(nil),
+	})
+	require.Len(t, responses, 2)
+	require.Equal(t, barrier, b)
+
+	responses, b = a.handleMessage([]*schedulepb.Message{newHeartbeat(nil)})
+	require.Len(t, responses, 1)
+	require.Nil(t, b)
+}
+
 func TestAgentUpdateOwnerInfo(t *testing.T) {
 	t.Parallel()
 
@@ -66,6 +66,7 @@ type coordinator struct {
 	captureM        *member.CaptureManager
 	schedulerM      *scheduler.Manager
 	reconciler      *keyspan.Reconciler
+	regionLocator   *keyspan.RegionLocator
 	compat          *compat.Compat
 	pdClock         pdutil.Clock
 	tableRanges     replication.TableRanges
@@ -97,6 +98,10 @@ func NewCoordinator(
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	regionLocator, err := keyspan.NewRegionLocator(changefeedID, up)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	revision := schedulepb.OwnerRevision{Revision: ownerRevision}
 	return &coordinator{
 		version:         version.ReleaseSemver(),
@@ -107,8 +112,9 @@ func NewCoordinator(
 		replicationM: replication.NewReplicationManager(
 			cfg.MaxTaskConcurrency, changefeedID),
 		captureM:        member.NewCaptureManager(captureID, changefeedID, revision, cfg),
-		schedulerM:      scheduler.NewSchedulerManager(changefeedID, cfg),
+		schedulerM:      scheduler.NewSchedulerManager(changefeedID, cfg, regionLocator),
 		reconciler:      reconciler,
+		regionLocator:   regionLocator,
 		changefeedID:    changefeedID,
 		compat:          compat.New(cfg, map[model.CaptureID]*model.CaptureInfo{}),
 		pdClock:         up.PDClock,
@@ -249,6 +255,22 @@ func (c *coordinator) DrainCapture(target model.CaptureID) (int, error) {
 	return count, nil
 }
 
+// RegionScanProgress implement the scheduler interface
+func (c *coordinator) RegionScanProgress() (total, scanned uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.replicationM.RegionScanProgress()
+}
+
+// ReplicationBytes implement the scheduler interface
+func (c *coordinator) ReplicationBytes() (scanned, written uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.replicationM.ReplicationBytes()
+}
+
 func (c *coordinator) Close(ctx context.Context) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -348,6 +370,10 @@ func (c *coordinator) poll(
 	runningTasks := c.replicationM.RunningTasks()
 	currentSpans := c.reconciler.Reconcile(
 		ctx, &c.tableRanges, replications, c.captureM.Captures, c.compat)
+	// only nil in unit test
+	if c.regionLocator != nil {
+		c.regionLocator.Refresh(ctx, c.captureM.Captures)
+	}
 	allTasks := c.schedulerM.Schedule(
 		checkpointTs, currentSpans, c.captureM.Captures, replications, runningTasks)
 
@@ -218,7 +218,7 @@ func newCoordinatorForTest(
 		replicationM: replication.NewReplicationManager(
 			cfg.MaxTaskConcurrency, changefeedID),
 		captureM:        member.NewCaptureManager(captureID, changefeedID, revision, cfg),
-		schedulerM:      scheduler.NewSchedulerManager(changefeedID, cfg),
+		schedulerM:      scheduler.NewSchedulerManager(changefeedID, cfg, nil),
 		changefeedID:    changefeedID,
 		compat:          compat.New(cfg, map[model.CaptureID]*model.CaptureInfo{}),
 		redoMetaManager: redoMetaManager,
@@ -434,7 +434,7 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 	require.Equal(t, 1, count)
 
 	coord.schedulerM = scheduler.NewSchedulerManager(
-		model.ChangeFeedID{}, config.NewDefaultSchedulerConfig())
+		model.ChangeFeedID{}, config.NewDefaultSchedulerConfig(), nil)
 	count, err = coord.DrainCapture("b")
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
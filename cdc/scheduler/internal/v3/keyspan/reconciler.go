@@ -67,7 +67,7 @@ func NewReconciler(
 	up *upstream.Upstream,
 	config *config.ChangefeedSchedulerConfig,
 ) (*Reconciler, error) {
-	pdapi, err := pdutil.NewPDAPIClient(up.PDClient, up.SecurityConfig)
+	pdapi, err := newPDAPIClient(up)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -83,6 +83,15 @@ func NewReconciler(
 	}, nil
 }
 
+// newPDAPIClient returns the PD API client of the upstream, which selects the
+// healthy PD endpoints. A new client is created if the upstream has none.
+func newPDAPIClient(up *upstream.Upstream) (pdutil.PDAPIClient, error) {
+	if up.PDAPIClient != nil {
+		return up.PDAPIClient, nil
+	}
+	return pdutil.NewPDAPIClient(up.PDClient, up.SecurityConfig)
+}
+
 // Reconcile spans that need to be replicated based on current cluster status.
 // It handles following cases:
 // 1. Changefeed initialization
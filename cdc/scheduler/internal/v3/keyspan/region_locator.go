@@ -0,0 +1,174 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspan
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"go.uber.org/zap"
+)
+
+const (
+	// regionLabelKey is the store label key which indicates the region of
+	// a TiKV store in placement rules.
+	regionLabelKey = "region"
+	// placementRulesRefreshInterval is the interval to fetch placement rules.
+	placementRulesRefreshInterval = time.Minute
+)
+
+// regionRule is a placement rule which constrains a key range to be placed
+// in a region.
+type regionRule struct {
+	startKey []byte
+	// endKey is empty if the rule covers keys to the end.
+	endKey []byte
+	region string
+	role   string
+	index  int
+}
+
+// RegionLocator locates the region of a table's primary TiKV store, i.e.
+// the store holds the leaders, by checking the placement rules in PD.
+// Note that the region here is the location label of stores, not TiKV regions.
+type RegionLocator struct {
+	changefeedID model.ChangeFeedID
+	pdAPIClient  pdutil.PDAPIClient
+
+	rules       []regionRule
+	lastRefresh time.Time
+}
+
+// NewRegionLocator returns a RegionLocator.
+func NewRegionLocator(
+	changefeedID model.ChangeFeedID, up *upstream.Upstream,
+) (*RegionLocator, error) {
+	pdapi, err := newPDAPIClient(up)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &RegionLocator{changefeedID: changefeedID, pdAPIClient: pdapi}, nil
+}
+
+// Refresh fetches placement rules from PD if the last refresh is out of date.
+// It's a no-op if none of the captures has a region label, since tables can
+// not be scheduled by region in that case.
+func (l *RegionLocator) Refresh(
+	ctx context.Context, captures map[model.CaptureID]*member.CaptureStatus,
+) {
+	hasRegion := false
+	for _, capture := range captures {
+		if capture.Region != "" {
+			hasRegion = true
+			break
+		}
+	}
+	if !hasRegion || time.Since(l.lastRefresh) < placementRulesRefreshInterval {
+		return
+	}
+	// Do not retry immediately if it fails, the stale rules are still usable.
+	l.lastRefresh = time.Now()
+
+	rules, err := l.pdAPIClient.ListPlacementRules(ctx)
+	if err != nil {
+		log.Warn("schedulerv3: list placement rules failed",
+			zap.String("namespace", l.changefeedID.Namespace),
+			zap.String("changefeed", l.changefeedID.ID),
+			zap.Error(err))
+		return
+	}
+	l.rules = parseRegionRules(rules)
+	log.Debug("schedulerv3: placement rules refreshed",
+		zap.String("namespace", l.changefeedID.Namespace),
+		zap.String("changefeed", l.changefeedID.ID),
+		zap.Int("regionRules", len(l.rules)))
+}
+
+// LocateRegion returns the region of the span, or an empty string if the span
+// is not constrained to any region.
+func (l *RegionLocator) LocateRegion(span tablepb.Span) string {
+	var best *regionRule
+	for i := range l.rules {
+		rule := &l.rules[i]
+		if bytes.Compare(span.StartKey, rule.startKey) < 0 ||
+			(len(rule.endKey) != 0 && bytes.Compare(span.StartKey, rule.endKey) >= 0) {
+			continue
+		}
+		if best == nil || betterRegionRule(rule, best) {
+			best = rule
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.region
+}
+
+// betterRegionRule returns true if a is preferred over b. Leader rules are
+// preferred, since the leader store is where the data is read from.
+func betterRegionRule(a, b *regionRule) bool {
+	if a.role != b.role {
+		return a.role == "leader"
+	}
+	return a.index > b.index
+}
+
+func parseRegionRules(rules []*pdutil.PlacementRule) []regionRule {
+	result := make([]regionRule, 0)
+	for _, rule := range rules {
+		// Followers and learners are not the primary store of the data.
+		if rule.Role != "leader" && rule.Role != "voter" {
+			continue
+		}
+		region := ""
+		for _, c := range rule.LabelConstraints {
+			if c.Key == regionLabelKey && c.Op == "in" && len(c.Values) > 0 {
+				region = c.Values[0]
+				break
+			}
+		}
+		if region == "" {
+			continue
+		}
+		startKey, err := hex.DecodeString(rule.StartKeyHex)
+		if err != nil {
+			log.Warn("schedulerv3: invalid start key of placement rule",
+				zap.String("groupID", rule.GroupID), zap.String("ruleID", rule.ID))
+			continue
+		}
+		endKey, err := hex.DecodeString(rule.EndKeyHex)
+		if err != nil {
+			log.Warn("schedulerv3: invalid end key of placement rule",
+				zap.String("groupID", rule.GroupID), zap.String("ruleID", rule.ID))
+			continue
+		}
+		result = append(result, regionRule{
+			startKey: startKey,
+			endKey:   endKey,
+			region:   region,
+			role:     rule.Role,
+			index:    rule.Index,
+		})
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspan
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPlacementRulesClient struct {
+	pdutil.PDAPIClient
+	rules []*pdutil.PlacementRule
+	calls int
+}
+
+func (m *mockPlacementRulesClient) ListPlacementRules(
+	ctx context.Context,
+) ([]*pdutil.PlacementRule, error) {
+	m.calls++
+	return m.rules, nil
+}
+
+func newRegionPlacementRule(
+	tableID model.TableID, role string, index int, region string,
+) *pdutil.PlacementRule {
+	span := spanz.TableIDToComparableSpan(tableID)
+	return &pdutil.PlacementRule{
+		GroupID:     "TiDB_DDL",
+		ID:          "rule",
+		Index:       index,
+		StartKeyHex: hex.EncodeToString(span.StartKey),
+		EndKeyHex:   hex.EncodeToString(span.EndKey),
+		Role:        role,
+		Count:       1,
+		LabelConstraints: []pdutil.LabelConstraint{
+			{Key: regionLabelKey, Op: "in", Values: []string{region}},
+		},
+	}
+}
+
+func TestRegionLocator(t *testing.T) {
+	t.Parallel()
+
+	client := &mockPlacementRulesClient{rules: []*pdutil.PlacementRule{
+		// The default rule has no region constraint.
+		{GroupID: "pd", ID: "default", Role: "voter", Count: 3},
+		newRegionPlacementRule(1, "voter", 0, "west"),
+		newRegionPlacementRule(1, "leader", 0, "east"),
+		newRegionPlacementRule(2, "voter", 0, "west"),
+		newRegionPlacementRule(2, "voter", 1, "east"),
+		// Learners are not the primary store.
+		newRegionPlacementRule(3, "learner", 0, "west"),
+	}}
+	l := &RegionLocator{pdAPIClient: client}
+
+	// No capture has region label, the rules are not fetched.
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}}
+	l.Refresh(context.Background(), captures)
+	require.Equal(t, 0, client.calls)
+	require.Equal(t, "", l.LocateRegion(spanz.TableIDToComparableSpan(1)))
+
+	captures["a"].Region = "east"
+	l.Refresh(context.Background(), captures)
+	require.Equal(t, 1, client.calls)
+	// The leader rule is preferred.
+	require.Equal(t, "east", l.LocateRegion(spanz.TableIDToComparableSpan(1)))
+	// The rule with the higher index is preferred.
+	require.Equal(t, "east", l.LocateRegion(spanz.TableIDToComparableSpan(2)))
+	require.Equal(t, "", l.LocateRegion(spanz.TableIDToComparableSpan(3)))
+	require.Equal(t, "", l.LocateRegion(spanz.TableIDToComparableSpan(4)))
+
+	// Rules are not fetched again within the refresh interval.
+	l.Refresh(context.Background(), captures)
+	require.Equal(t, 1, client.calls)
+}
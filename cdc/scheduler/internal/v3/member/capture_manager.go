@@ -14,6 +14,8 @@
 package member
 
 import (
+	"time"
+
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -63,17 +65,19 @@ type CaptureStatus struct {
 	Tables   []tablepb.TableStatus
 	ID       model.CaptureID
 	Addr     string
+	Region   string
 	IsOwner  bool
 }
 
 func newCaptureStatus(
-	rev schedulepb.OwnerRevision, id model.CaptureID, addr string, isOwner bool,
+	rev schedulepb.OwnerRevision, id model.CaptureID, addr, region string, isOwner bool,
 ) *CaptureStatus {
 	return &CaptureStatus{
 		OwnerRev: rev,
 		State:    CaptureStateUninitialized,
 		ID:       id,
 		Addr:     addr,
+		Region:   region,
 		IsOwner:  isOwner,
 	}
 }
@@ -128,6 +132,13 @@ type CaptureManager struct {
 	collectStatsTick int
 	pendingCollect   bool
 
+	// barrierInterval is the min interval of broadcasting the barrier, i.e.
+	// the resolved ts of the changefeed, in heartbeats. Heartbeats are still
+	// sent every heartbeatTick, so that the checkpoints of tables keep being
+	// collected. 0 means the barrier is sent in every heartbeat.
+	barrierInterval time.Duration
+	lastBarrierTime time.Time
+
 	changefeedID model.ChangeFeedID
 	ownerID      model.CaptureID
 }
@@ -143,6 +154,8 @@ func NewCaptureManager(
 		heartbeatTick:    cfg.HeartbeatTick,
 		collectStatsTick: cfg.CollectStatsTick,
 
+		barrierInterval: cfg.ResolvedTsBroadcastInterval,
+
 		changefeedID: changefeedID,
 		ownerID:      ownerID,
 	}
@@ -179,6 +192,16 @@ func (c *CaptureManager) Tick(
 	if c.tickCounter%c.heartbeatTick != 0 {
 		return nil
 	}
+	// The barrier is still sent in every heartbeat when captures are being
+	// initialized or drained, so that scheduling isn't slowed down.
+	if c.barrierInterval > 0 && drainingCapture == "" && c.checkAllCaptureInitialized() {
+		now := time.Now()
+		if now.Sub(c.lastBarrierTime) < c.barrierInterval {
+			barrier = nil
+		} else {
+			c.lastBarrierTime = now
+		}
+	}
 	tables := make(map[model.CaptureID][]tablepb.Span)
 	reps.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
 		for captureID := range rep.Captures {
@@ -232,7 +255,7 @@ func (c *CaptureManager) HandleAliveCaptureUpdate(
 		if _, ok := c.Captures[id]; !ok {
 			// A new capture.
 			c.Captures[id] = newCaptureStatus(
-				c.OwnerRev, id, info.AdvertiseAddr, c.ownerID == id)
+				c.OwnerRev, id, info.AdvertiseAddr, info.Region, c.ownerID == id)
 			log.Info("schedulerv3: find a new capture",
 				zap.String("captureAddr", info.AdvertiseAddr),
 				zap.String("capture", id))
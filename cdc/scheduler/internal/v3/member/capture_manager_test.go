@@ -15,6 +15,7 @@ package member
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -34,7 +35,7 @@ func TestCaptureStatusHandleHeartbeatResponse(t *testing.T) {
 
 	rev := schedulepb.OwnerRevision{Revision: 1}
 	epoch := schedulepb.ProcessorEpoch{Epoch: "test"}
-	c := newCaptureStatus(rev, "", "", true)
+	c := newCaptureStatus(rev, "", "", "", true)
 	require.Equal(t, CaptureStateUninitialized, c.State)
 	require.True(t, c.IsOwner)
 
@@ -287,3 +288,98 @@ func TestCaptureManagerCollectStatsTick(t *testing.T) {
 		}
 	}
 }
+
+func TestCaptureManagerResolvedTsBroadcastInterval(t *testing.T) {
+	t.Parallel()
+
+	newCaptureManager := func(id string, interval time.Duration) *CaptureManager {
+		cfg := config.NewDefaultSchedulerConfig()
+		cfg.HeartbeatTick = 1
+		cfg.ResolvedTsBroadcastInterval = interval
+		cm := NewCaptureManager("", model.DefaultChangeFeedID(id), schedulepb.OwnerRevision{}, cfg)
+		cm.HandleAliveCaptureUpdate(map[model.CaptureID]*model.CaptureInfo{"1": {}})
+		return cm
+	}
+	cf1 := newCaptureManager("cf1", 100*time.Millisecond)
+	cf2 := newCaptureManager("cf2", time.Second)
+	barrier := &schedulepb.Barrier{GlobalBarrierTs: 1}
+	// tick returns the barrier of the heartbeat, every tick sends a heartbeat.
+	tick := func(cm *CaptureManager) *schedulepb.Barrier {
+		msgs := cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, barrier)
+		require.Len(t, msgs, 1)
+		return msgs[0].Heartbeat.Barrier
+	}
+	// elapse pretends the time has elapsed since the last broadcast.
+	elapse := func(cm *CaptureManager, d time.Duration) {
+		cm.lastBarrierTime = cm.lastBarrierTime.Add(-d)
+	}
+
+	// Broadcast every tick if the capture is uninitialized.
+	for _, cm := range []*CaptureManager{cf1, cf2} {
+		require.Equal(t, barrier, tick(cm))
+		require.Equal(t, barrier, tick(cm))
+		cm.Captures["1"].State = CaptureStateInitialized
+	}
+
+	// The first broadcast after captures are initialized is sent immediately.
+	for _, cm := range []*CaptureManager{cf1, cf2} {
+		require.Equal(t, barrier, tick(cm))
+		require.Nil(t, tick(cm))
+	}
+
+	// The changefeeds broadcast resolved ts independently.
+	broadcasts := map[*CaptureManager]int{}
+	for i := 0; i < 10; i++ {
+		for _, cm := range []*CaptureManager{cf1, cf2} {
+			elapse(cm, 200*time.Millisecond)
+			if tick(cm) != nil {
+				broadcasts[cm]++
+			}
+		}
+	}
+	require.Equal(t, 10, broadcasts[cf1])
+	require.Equal(t, 2, broadcasts[cf2])
+
+	// Broadcast every tick if a capture is being drained.
+	msgs := cf2.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), "1", barrier)
+	require.Len(t, msgs, 1)
+	require.True(t, msgs[0].Heartbeat.IsStopping)
+	require.Equal(t, barrier, msgs[0].Heartbeat.Barrier)
+}
+
+func TestCaptureManagerCollectCheckpointsBetweenBroadcasts(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewDefaultSchedulerConfig()
+	cfg.HeartbeatTick = 1
+	cfg.ResolvedTsBroadcastInterval = time.Minute
+	cm := NewCaptureManager("", model.DefaultChangeFeedID("cf"), schedulepb.OwnerRevision{}, cfg)
+	cm.HandleAliveCaptureUpdate(map[model.CaptureID]*model.CaptureInfo{"1": {}})
+	cm.Captures["1"].State = CaptureStateInitialized
+	barrier := &schedulepb.Barrier{GlobalBarrierTs: 1}
+	span := spanz.TableIDToComparableSpan(1)
+
+	for ts := uint64(1); ts <= 5; ts++ {
+		msgs := cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, barrier)
+		require.Len(t, msgs, 1)
+		if ts == 1 {
+			require.Equal(t, barrier, msgs[0].Heartbeat.Barrier)
+		} else {
+			require.Nil(t, msgs[0].Heartbeat.Barrier)
+		}
+		// The processor reports the checkpoint of the table in the response.
+		cm.HandleMessage([]*schedulepb.Message{{
+			Header:  &schedulepb.Message_Header{},
+			From:    "1",
+			MsgType: schedulepb.MsgHeartbeatResponse,
+			HeartbeatResponse: &schedulepb.HeartbeatResponse{
+				Tables: []tablepb.TableStatus{{
+					Span:       span,
+					State:      tablepb.TableStateReplicating,
+					Checkpoint: tablepb.Checkpoint{CheckpointTs: ts, ResolvedTs: ts},
+				}},
+			},
+		}})
+		require.Equal(t, ts, cm.Captures["1"].Tables[0].Checkpoint.CheckpointTs)
+	}
+}
@@ -140,6 +140,15 @@ var (
 			Name:      "slow_table_puller_resolved_ts_lag",
 			Help:      "Puller Slowest ResolvedTs lag",
 		}, []string{"namespace", "changefeed"})
+
+	schedulingLatencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "scheduler",
+			Name:      "scheduling_latency_seconds",
+			Help:      "Bucketed histogram of the time from a move table decision to the confirmed migration",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 18), // 10ms ~ 22min
+		}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics used in scheduler
@@ -162,4 +171,5 @@ func InitMetrics(registry *prometheus.Registry) {
 
 	registry.MustRegister(slowestTablePullerResolvedTs)
 	registry.MustRegister(slowestTablePullerResolvedTsLag)
+	registry.MustRegister(schedulingLatencyHistogram)
 }
@@ -145,6 +145,9 @@ type Manager struct { //nolint:revive
 
 	runningTasks       *spanz.BtreeMap[*ScheduleTask]
 	maxTaskConcurrency int
+	// moveTableStartTime records when move table tasks are accepted,
+	// it is used to measure the scheduling latency.
+	moveTableStartTime *spanz.HashMap[time.Time]
 
 	changefeedID           model.ChangeFeedID
 	slowestPuller          tablepb.Span
@@ -171,6 +174,7 @@ func NewReplicationManager(
 		spans:              spanz.NewBtreeMapWithDegree[*ReplicationSet](degreeReadHeavy),
 		runningTasks:       spanz.NewBtreeMap[*ScheduleTask](),
 		maxTaskConcurrency: maxTaskConcurrency,
+		moveTableStartTime: spanz.NewHashMap[time.Time](),
 		changefeedID:       changefeedID,
 	}
 }
@@ -227,6 +231,7 @@ func (r *Manager) HandleCaptureChanges(
 				if affected {
 					// Cleanup its running task.
 					r.runningTasks.Delete(table.Span)
+					r.moveTableStartTime.Delete(table.Span)
 				}
 			}
 			return true
@@ -359,6 +364,7 @@ func (r *Manager) HandleTasks(
 	})
 	for _, span := range toBeDeleted {
 		r.runningTasks.Delete(span)
+		r.observeMoveTableLatency(span)
 	}
 
 	sentMsgs := make([]*schedulepb.Message, 0)
@@ -470,7 +476,29 @@ func (r *Manager) handleMoveTableTask(
 ) ([]*schedulepb.Message, error) {
 	r.acceptMoveTableTask++
 	table, _ := r.spans.Get(task.Span)
-	return table.handleMoveTable(task.DestCapture)
+	msgs, err := table.handleMoveTable(task.DestCapture)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r.moveTableStartTime.ReplaceOrInsert(task.Span, time.Now())
+	return msgs, nil
+}
+
+// observeMoveTableLatency observes the scheduling latency of a finished
+// move table task. The latency is only observed if the table is migrated
+// and becomes replicating again.
+func (r *Manager) observeMoveTableLatency(span tablepb.Span) {
+	start, ok := r.moveTableStartTime.Get(span)
+	if !ok {
+		return
+	}
+	r.moveTableStartTime.Delete(span)
+	if table, ok := r.spans.Get(span); ok &&
+		table.State == ReplicationSetStateReplicating {
+		schedulingLatencyHistogram.
+			WithLabelValues(r.changefeedID.Namespace, r.changefeedID.ID).
+			Observe(time.Since(start).Seconds())
+	}
 }
 
 func (r *Manager) handleBurstBalanceTasks(
@@ -770,6 +798,31 @@ func (r *Manager) logSlowTableInfo(currentPDTime time.Time) {
 	}
 }
 
+// RegionScanProgress returns the count of captured regions and the count of
+// regions which have finished the incremental scan, of all spans. The counts
+// are collected from the latest stats reported by processors.
+func (r *Manager) RegionScanProgress() (total, scanned uint64) {
+	r.spans.Ascend(func(_ tablepb.Span, table *ReplicationSet) bool {
+		total += table.Stats.RegionCount
+		scanned += table.Stats.InitializedRegionCount
+		return true
+	})
+	return
+}
+
+// ReplicationBytes returns the bytes scanned from TiKV and the bytes written
+// to the downstream, of all spans. The bytes are collected from the latest
+// stats reported by processors, and are counted since the spans were added
+// to their current captures.
+func (r *Manager) ReplicationBytes() (scanned, written uint64) {
+	r.spans.Ascend(func(_ tablepb.Span, table *ReplicationSet) bool {
+		scanned += table.Stats.ScannedBytes
+		written += table.Stats.WrittenBytes
+		return true
+	})
+	return
+}
+
 // CollectMetrics collects metrics.
 func (r *Manager) CollectMetrics() {
 	cf := r.changefeedID
@@ -881,6 +934,7 @@ func (r *Manager) CleanMetrics() {
 	slowestTableCheckpointTsGauge.DeleteLabelValues(cf.Namespace, cf.ID)
 	slowestTableResolvedTsGauge.DeleteLabelValues(cf.Namespace, cf.ID)
 	runningScheduleTaskGauge.DeleteLabelValues(cf.Namespace, cf.ID)
+	schedulingLatencyHistogram.DeleteLabelValues(cf.Namespace, cf.ID)
 	metricAcceptScheduleTask := acceptScheduleTaskCounter.MustCurryWith(map[string]string{
 		"namespace": cf.Namespace, "changefeed": cf.ID,
 	})
@@ -277,6 +277,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 		},
 	}, msgs[0])
 	require.NotNil(t, r.runningTasks.Has(spanz.TableIDToComparableSpan(1)))
+	require.True(t, r.moveTableStartTime.Has(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-moveTableCh)
 
 	// Ignore if move table again.
@@ -370,6 +371,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
 	require.Nil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
+	require.False(t, r.moveTableStartTime.Has(spanz.TableIDToComparableSpan(1)))
 }
 
 func TestReplicationManagerBurstBalance(t *testing.T) {
@@ -1068,3 +1070,47 @@ func TestLogSlowTableInfo(t *testing.T) {
 	// make sure the slowTableHeap's capacity will not extend
 	require.Equal(t, cap(r.slowTableHeap), 8)
 }
+
+func TestReplicationManagerRegionScanProgress(t *testing.T) {
+	t.Parallel()
+	r := NewReplicationManager(1, model.ChangeFeedID{})
+	total, scanned := r.RegionScanProgress()
+	require.Equal(t, uint64(0), total)
+	require.Equal(t, uint64(0), scanned)
+
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), &ReplicationSet{
+		Span:  spanz.TableIDToComparableSpan(1),
+		State: ReplicationSetStateReplicating,
+		Stats: tablepb.Stats{RegionCount: 10, InitializedRegionCount: 10},
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(2), &ReplicationSet{
+		Span:  spanz.TableIDToComparableSpan(2),
+		State: ReplicationSetStateReplicating,
+		Stats: tablepb.Stats{RegionCount: 5, InitializedRegionCount: 2},
+	})
+	total, scanned = r.RegionScanProgress()
+	require.Equal(t, uint64(15), total)
+	require.Equal(t, uint64(12), scanned)
+}
+
+func TestReplicationManagerReplicationBytes(t *testing.T) {
+	t.Parallel()
+	r := NewReplicationManager(1, model.ChangeFeedID{})
+	scanned, written := r.ReplicationBytes()
+	require.Equal(t, uint64(0), scanned)
+	require.Equal(t, uint64(0), written)
+
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), &ReplicationSet{
+		Span:  spanz.TableIDToComparableSpan(1),
+		State: ReplicationSetStateReplicating,
+		Stats: tablepb.Stats{ScannedBytes: 1000, WrittenBytes: 100},
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(2), &ReplicationSet{
+		Span:  spanz.TableIDToComparableSpan(2),
+		State: ReplicationSetStateReplicating,
+		Stats: tablepb.Stats{ScannedBytes: 500, WrittenBytes: 400},
+	})
+	scanned, written = r.ReplicationBytes()
+	require.Equal(t, uint64(1500), scanned)
+	require.Equal(t, uint64(500), written)
+}
@@ -25,7 +25,35 @@ var scheduleTaskCounter = prometheus.NewCounterVec(
 		Help:      "The total number of scheduler tasks",
 	}, []string{"namespace", "changefeed", "scheduler", "task"})
 
+var tablesPerProcessorHistogram = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "ticdc",
+		Subsystem: "scheduler",
+		Name:      "tables_per_processor",
+		Help:      "Distribution of the number of tables replicated by each processor",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 16), // 1 ~ 32768
+	}, []string{"namespace", "changefeed"})
+
+var rebalanceCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "scheduler",
+		Name:      "rebalance_total",
+		Help:      "The total number of rebalances triggered by the balance scheduler",
+	}, []string{"namespace", "changefeed"})
+
+var crossRegionAssignmentsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "scheduler",
+		Name:      "cross_region_assignments_total",
+		Help:      "The total number of tables assigned to a capture outside the region of the table data",
+	}, []string{"namespace", "changefeed"})
+
 // InitMetrics registers all metrics used in scheduler
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(scheduleTaskCounter)
+	registry.MustRegister(tablesPerProcessorHistogram)
+	registry.MustRegister(rebalanceCounter)
+	registry.MustRegister(crossRegionAssignmentsCounter)
 }
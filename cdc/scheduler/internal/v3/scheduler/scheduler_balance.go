@@ -14,6 +14,7 @@
 package scheduler
 
 import (
+	"math"
 	"math/rand"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
 )
 
 var _ scheduler = &balanceScheduler{}
@@ -41,13 +43,23 @@ type balanceScheduler struct {
 	forceBalance bool
 
 	maxTaskConcurrency int
+	// rebalanceThreshold is the threshold of the coefficient of variation of
+	// table counts across captures, tables are moved only if it is exceeded.
+	rebalanceThreshold float64
+
+	changefeedID model.ChangeFeedID
 }
 
-func newBalanceScheduler(interval time.Duration, concurrency int) *balanceScheduler {
+func newBalanceScheduler(
+	interval time.Duration, concurrency int,
+	threshold float64, changefeed model.ChangeFeedID,
+) *balanceScheduler {
 	return &balanceScheduler{
 		random:               rand.New(rand.NewSource(time.Now().UnixNano())),
 		checkBalanceInterval: interval,
 		maxTaskConcurrency:   concurrency,
+		rebalanceThreshold:   threshold,
+		changefeedID:         changefeed,
 	}
 }
 
@@ -77,12 +89,77 @@ func (b *balanceScheduler) Schedule(
 		}
 	}
 
+	tablesPerCapture := countTablesPerCapture(captures, replications)
+	observer := tablesPerProcessorHistogram.
+		WithLabelValues(b.changefeedID.Namespace, b.changefeedID.ID)
+	for _, count := range tablesPerCapture {
+		observer.Observe(float64(count))
+	}
+	if cv := coefficientOfVariation(tablesPerCapture); cv <= b.rebalanceThreshold {
+		log.Debug("schedulerv3: tables are balanced enough, skip balance",
+			zap.String("namespace", b.changefeedID.Namespace),
+			zap.String("changefeed", b.changefeedID.ID),
+			zap.Float64("coefficientOfVariation", cv),
+			zap.Float64("threshold", b.rebalanceThreshold))
+		b.forceBalance = false
+		return nil
+	}
+
 	tasks := buildBalanceMoveTables(
 		b.random, captures, replications, b.maxTaskConcurrency)
+	// Only count the first round of a rebalance, the following rounds
+	// are forced to speed up the same rebalance.
+	if len(tasks) != 0 && !b.forceBalance {
+		rebalanceCounter.
+			WithLabelValues(b.changefeedID.Namespace, b.changefeedID.ID).Inc()
+	}
 	b.forceBalance = len(tasks) != 0
 	return tasks
 }
 
+// countTablesPerCapture returns the number of replicating tables of each capture.
+func countTablesPerCapture(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) map[model.CaptureID]int {
+	tablesPerCapture := make(map[model.CaptureID]int, len(captures))
+	for captureID := range captures {
+		tablesPerCapture[captureID] = 0
+	}
+	replications.Ascend(func(_ tablepb.Span, rep *replication.ReplicationSet) bool {
+		if rep.State == replication.ReplicationSetStateReplicating {
+			if _, ok := tablesPerCapture[rep.Primary]; ok {
+				tablesPerCapture[rep.Primary]++
+			}
+		}
+		return true
+	})
+	return tablesPerCapture
+}
+
+// coefficientOfVariation returns the ratio of the standard deviation to
+// the mean of table counts. It returns 0 if there is no table.
+func coefficientOfVariation(tablesPerCapture map[model.CaptureID]int) float64 {
+	if len(tablesPerCapture) == 0 {
+		return 0
+	}
+	total := 0
+	for _, count := range tablesPerCapture {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	mean := float64(total) / float64(len(tablesPerCapture))
+	variance := 0.0
+	for _, count := range tablesPerCapture {
+		diff := float64(count) - mean
+		variance += diff * diff
+	}
+	variance /= float64(len(tablesPerCapture))
+	return math.Sqrt(variance) / mean
+}
+
 func buildBalanceMoveTables(
 	random *rand.Rand,
 	captures map[model.CaptureID]*member.CaptureStatus,
@@ -27,7 +27,7 @@ import (
 func TestSchedulerBalanceCaptureOnline(t *testing.T) {
 	t.Parallel()
 
-	sched := newBalanceScheduler(time.Duration(0), 3)
+	sched := newBalanceScheduler(time.Duration(0), 3, 0, model.ChangeFeedID{})
 	sched.random = nil
 
 	// New capture "b" online
@@ -75,7 +75,7 @@ func TestSchedulerBalanceCaptureOnline(t *testing.T) {
 func TestSchedulerBalanceTaskLimit(t *testing.T) {
 	t.Parallel()
 
-	sched := newBalanceScheduler(time.Duration(0), 2)
+	sched := newBalanceScheduler(time.Duration(0), 2, 0, model.ChangeFeedID{})
 	sched.random = nil
 
 	// New capture "b" online
@@ -90,7 +90,47 @@ func TestSchedulerBalanceTaskLimit(t *testing.T) {
 	tasks := sched.Schedule(0, currentTables, captures, replications)
 	require.Len(t, tasks, 2)
 
-	sched = newBalanceScheduler(time.Duration(0), 1)
+	sched = newBalanceScheduler(time.Duration(0), 1, 0, model.ChangeFeedID{})
 	tasks = sched.Schedule(0, currentTables, captures, replications)
 	require.Len(t, tasks, 1)
 }
+
+func TestSchedulerBalanceThreshold(t *testing.T) {
+	t.Parallel()
+
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentTables := spanz.ArrayToSpan([]model.TableID{1, 2, 3, 4, 5, 6})
+	// a: 4 tables, b: 2 tables, the coefficient of variation is 1/3.
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		4: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		5: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		6: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	})
+
+	// The coefficient of variation does not exceed the threshold.
+	sched := newBalanceScheduler(time.Duration(0), 3, 0.4, model.ChangeFeedID{})
+	sched.random = nil
+	tasks := sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.False(t, sched.forceBalance)
+
+	// The coefficient of variation exceeds the threshold.
+	sched = newBalanceScheduler(time.Duration(0), 3, 0.3, model.ChangeFeedID{})
+	sched.random = nil
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, "b", tasks[0].MoveTable.DestCapture)
+}
+
+func TestCoefficientOfVariation(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0.0, coefficientOfVariation(nil))
+	require.Equal(t, 0.0, coefficientOfVariation(map[model.CaptureID]int{"a": 0, "b": 0}))
+	require.Equal(t, 0.0, coefficientOfVariation(map[model.CaptureID]int{"a": 3, "b": 3}))
+	require.InDelta(t, 1.0, coefficientOfVariation(map[model.CaptureID]int{"a": 2, "b": 0}), 1e-9)
+	require.InDelta(t, 1.0/3, coefficientOfVariation(map[model.CaptureID]int{"a": 4, "b": 2}), 1e-9)
+}
@@ -14,17 +14,26 @@
 package scheduler
 
 import (
+	"sort"
+
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
 var _ scheduler = &basicScheduler{}
 
+// RegionLocator locates the region where the data of a span is placed.
+type RegionLocator interface {
+	// LocateRegion returns an empty string if the region is unknown.
+	LocateRegion(span tablepb.Span) string
+}
+
 // The basic scheduler for adding and removing tables, it tries to keep
 // every table get replicated.
 //
@@ -35,12 +44,21 @@ var _ scheduler = &basicScheduler{}
 type basicScheduler struct {
 	batchSize    int
 	changefeedID model.ChangeFeedID
+
+	// regionLocator is nil if region-aware scheduling is disabled.
+	regionLocator      RegionLocator
+	crossRegionCounter prometheus.Counter
 }
 
-func newBasicScheduler(batchSize int, changefeed model.ChangeFeedID) *basicScheduler {
+func newBasicScheduler(
+	batchSize int, changefeed model.ChangeFeedID, regionLocator RegionLocator,
+) *basicScheduler {
 	return &basicScheduler{
-		batchSize:    batchSize,
-		changefeedID: changefeed,
+		batchSize:     batchSize,
+		changefeedID:  changefeed,
+		regionLocator: regionLocator,
+		crossRegionCounter: crossRegionAssignmentsCounter.
+			WithLabelValues(changefeed.Namespace, changefeed.ID),
 	}
 }
 
@@ -101,8 +119,9 @@ func (b *basicScheduler) Schedule(
 				zap.Any("allCaptureStatus", captures))
 			return tasks
 		}
+		targetCaptures := b.selectCaptures(newSpans, captureIDs, captures, replications)
 		tasks = append(
-			tasks, newBurstAddTables(b.changefeedID, checkpointTs, newSpans, captureIDs))
+			tasks, newBurstAddTables(b.changefeedID, checkpointTs, newSpans, targetCaptures))
 	}
 
 	// Build remove table tasks.
@@ -137,15 +156,88 @@ func (b *basicScheduler) Schedule(
 	return tasks
 }
 
-// newBurstAddTables add each new table to captures in a round-robin way.
+// selectCaptures selects a target capture for each new span.
+// If region-aware scheduling is enabled, a span is added to the least loaded
+// capture in the same region as its data, as long as the capture replicates
+// no more tables than the average after adding. Otherwise, the span falls
+// back to captures in a round-robin way.
+func (b *basicScheduler) selectCaptures(
+	newSpans []tablepb.Span,
+	captureIDs []model.CaptureID,
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) []model.CaptureID {
+	regionAware := false
+	if b.regionLocator != nil {
+		for _, captureID := range captureIDs {
+			if captures[captureID].Region != "" {
+				regionAware = true
+				break
+			}
+		}
+	}
+
+	targetCaptures := make([]model.CaptureID, 0, len(newSpans))
+	if !regionAware {
+		for i := range newSpans {
+			targetCaptures = append(targetCaptures, captureIDs[i%len(captureIDs)])
+		}
+		return targetCaptures
+	}
+
+	// Make the selection deterministic.
+	sort.Strings(captureIDs)
+	tablesPerCapture := countTablesPerCapture(captures, replications)
+	totalTables := len(newSpans)
+	for _, captureID := range captureIDs {
+		totalTables += tablesPerCapture[captureID]
+	}
+	capacity := (totalTables + len(captureIDs) - 1) / len(captureIDs)
+
+	idx := 0
+	for _, span := range newSpans {
+		region := b.regionLocator.LocateRegion(span)
+		target := ""
+		if region != "" {
+			for _, captureID := range captureIDs {
+				if captures[captureID].Region != region ||
+					tablesPerCapture[captureID] >= capacity {
+					continue
+				}
+				if target == "" || tablesPerCapture[captureID] < tablesPerCapture[target] {
+					target = captureID
+				}
+			}
+		}
+		if target == "" {
+			target = captureIDs[idx%len(captureIDs)]
+			idx++
+			if region != "" && captures[target].Region != region {
+				b.crossRegionCounter.Inc()
+				log.Info("schedulerv3: no capture has capacity in the region of the table, "+
+					"add the table to a capture in another region",
+					zap.String("namespace", b.changefeedID.Namespace),
+					zap.String("changefeed", b.changefeedID.ID),
+					zap.Int64("tableID", span.TableID),
+					zap.String("tableRegion", region),
+					zap.String("captureID", target),
+					zap.String("captureRegion", captures[target].Region))
+			}
+		}
+		tablesPerCapture[target]++
+		targetCaptures = append(targetCaptures, target)
+	}
+	return targetCaptures
+}
+
+// newBurstAddTables add each new table to the corresponding target capture.
 func newBurstAddTables(
 	changefeedID model.ChangeFeedID,
-	checkpointTs model.Ts, newSpans []tablepb.Span, captureIDs []model.CaptureID,
+	checkpointTs model.Ts, newSpans []tablepb.Span, targetCaptures []model.CaptureID,
 ) *replication.ScheduleTask {
-	idx := 0
 	tables := make([]replication.AddTable, 0, len(newSpans))
-	for _, span := range newSpans {
-		targetCapture := captureIDs[idx]
+	for i, span := range newSpans {
+		targetCapture := targetCaptures[i]
 		tables = append(tables, replication.AddTable{
 			Span:         span,
 			CaptureID:    targetCapture,
@@ -156,11 +248,6 @@ func newBurstAddTables(
 			zap.String("changefeed", changefeedID.ID),
 			zap.String("captureID", targetCapture),
 			zap.Any("tableID", span.TableID))
-
-		idx++
-		if idx >= len(captureIDs) {
-			idx = 0
-		}
 	}
 	return &replication.ScheduleTask{
 		BurstBalance: &replication.BurstBalance{
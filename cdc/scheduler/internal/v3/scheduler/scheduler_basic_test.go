@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -42,7 +43,7 @@ func TestSchedulerBasic(t *testing.T) {
 	// Initial table dispatch.
 	// AddTable only
 	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{})
-	b := newBasicScheduler(2, model.ChangeFeedID{})
+	b := newBasicScheduler(2, model.ChangeFeedID{}, nil)
 
 	// one capture stopping, another one is initialized
 	captures["a"].State = member.CaptureStateStopping
@@ -182,6 +183,74 @@ func TestSchedulerPriority(t *testing.T) {
 	require.Less(t, schedulerPriorityBasic, schedulerPriorityMax)
 }
 
+type mockRegionLocator map[model.TableID]string
+
+func (m mockRegionLocator) LocateRegion(span tablepb.Span) string {
+	return m[span.TableID]
+}
+
+func TestSchedulerBasicRegionAffinity(t *testing.T) {
+	t.Parallel()
+
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {Region: "east"}, "b": {Region: "east"}, "c": {Region: "west"},
+	}
+	locator := mockRegionLocator{1: "east", 2: "east", 3: "east", 4: "east", 5: "west"}
+	currentTables := spanz.ArrayToSpan([]model.TableID{1, 2, 3, 4, 5, 6})
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{})
+	b := newBasicScheduler(50, model.ChangeFeedID{}, locator)
+	tasks := b.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	targets := map[model.TableID]model.CaptureID{}
+	for _, add := range tasks[0].BurstBalance.AddTables {
+		targets[add.Span.TableID] = add.CaptureID
+	}
+	require.Equal(t, map[model.TableID]model.CaptureID{
+		1: "a", 2: "b", 3: "a", 4: "b", 5: "c",
+		// No region for table 6, fall back to round-robin.
+		6: "a",
+	}, targets)
+
+	// All tables are in the west region, but capture c can only take
+	// 1 table, the rest are assigned to captures in the east region.
+	locator = mockRegionLocator{1: "west", 2: "west", 3: "west"}
+	currentTables = spanz.ArrayToSpan([]model.TableID{1, 2, 3})
+	b = newBasicScheduler(50, model.ChangeFeedID{}, locator)
+	tasks = b.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	targets = map[model.TableID]model.CaptureID{}
+	for _, add := range tasks[0].BurstBalance.AddTables {
+		targets[add.Span.TableID] = add.CaptureID
+	}
+	require.Equal(t, map[model.TableID]model.CaptureID{1: "c", 2: "a", 3: "b"}, targets)
+	metric := &dto.Metric{}
+	require.NoError(t, b.crossRegionCounter.Write(metric))
+	require.Equal(t, float64(2), metric.GetCounter().GetValue())
+
+	// Existing tables are taken into account when checking capacity.
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		10: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		11: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+	})
+	locator = mockRegionLocator{1: "east"}
+	currentTables = spanz.ArrayToSpan([]model.TableID{1, 10, 11})
+	b = newBasicScheduler(50, model.ChangeFeedID{}, locator)
+	tasks = b.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.AddTables, 1)
+	require.Equal(t, "b", tasks[0].BurstBalance.AddTables[0].CaptureID)
+
+	// Captures without region label disable region-aware scheduling.
+	captures = map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentTables = spanz.ArrayToSpan([]model.TableID{1, 2})
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{})
+	tasks = b.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.AddTables, 2)
+	require.NotEqual(t, tasks[0].BurstBalance.AddTables[0].CaptureID,
+		tasks[0].BurstBalance.AddTables[1].CaptureID)
+}
+
 func benchmarkSchedulerBalance(
 	b *testing.B,
 	factory func(total int) (
@@ -224,7 +293,7 @@ func BenchmarkSchedulerBasicAddTables(b *testing.B) {
 		}
 		replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{})
 		name = fmt.Sprintf("AddTable %d", total)
-		sched = newBasicScheduler(50, model.ChangeFeedID{})
+		sched = newBasicScheduler(50, model.ChangeFeedID{}, nil)
 		return name, currentTables, captures, replications, sched
 	})
 }
@@ -251,7 +320,7 @@ func BenchmarkSchedulerBasicRemoveTables(b *testing.B) {
 				})
 		}
 		name = fmt.Sprintf("RemoveTable %d", total)
-		sched = newBasicScheduler(50, model.ChangeFeedID{})
+		sched = newBasicScheduler(50, model.ChangeFeedID{}, nil)
 		return name, currentTables, captures, replications, sched
 	})
 }
@@ -281,7 +350,7 @@ func BenchmarkSchedulerBasicAddRemoveTables(b *testing.B) {
 				})
 		}
 		name = fmt.Sprintf("AddRemoveTable %d", total)
-		sched = newBasicScheduler(50, model.ChangeFeedID{})
+		sched = newBasicScheduler(50, model.ChangeFeedID{}, nil)
 		return name, currentTables, captures, replications, sched
 	})
 }
@@ -37,8 +37,10 @@ type Manager struct { //nolint:revive
 }
 
 // NewSchedulerManager returns a new scheduler manager.
+// regionLocator can be nil, which disables region-aware table scheduling.
 func NewSchedulerManager(
 	changefeedID model.ChangeFeedID, cfg *config.SchedulerConfig,
+	regionLocator RegionLocator,
 ) *Manager {
 	sm := &Manager{
 		maxTaskConcurrency: cfg.MaxTaskConcurrency,
@@ -51,11 +53,12 @@ func NewSchedulerManager(
 	}
 
 	sm.schedulers[schedulerPriorityBasic] = newBasicScheduler(
-		cfg.AddTableBatchSize, changefeedID)
+		cfg.AddTableBatchSize, changefeedID, regionLocator)
 	sm.schedulers[schedulerPriorityDrainCapture] = newDrainCaptureScheduler(
 		cfg.MaxTaskConcurrency, changefeedID)
 	sm.schedulers[schedulerPriorityBalance] = newBalanceScheduler(
-		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency)
+		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency,
+		cfg.RebalanceThreshold, changefeedID)
 	sm.schedulers[schedulerPriorityMoveTable] = newMoveTableScheduler(changefeedID)
 	sm.schedulers[schedulerPriorityRebalance] = newRebalanceScheduler(changefeedID)
 
@@ -161,4 +164,7 @@ func (sm *Manager) CleanMetrics() {
 	for name := range sm.tasksCounter {
 		scheduleTaskCounter.DeleteLabelValues(cf.Namespace, cf.ID, name.scheduler, name.task)
 	}
+	tablesPerProcessorHistogram.DeleteLabelValues(cf.Namespace, cf.ID)
+	rebalanceCounter.DeleteLabelValues(cf.Namespace, cf.ID)
+	crossRegionAssignmentsCounter.DeleteLabelValues(cf.Namespace, cf.ID)
 }
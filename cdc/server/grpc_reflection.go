@@ -0,0 +1,123 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// registerReflection registers the gRPC reflection service, both v1 and
+// v1alpha, on the server. It must be called after all other services are
+// registered.
+//
+// The services of TiCDC are generated by gogoproto, whose descriptors are not
+// registered in the global registry of google.golang.org/protobuf, so they
+// are loaded from the gogoproto registry by the metadata of the services.
+func registerReflection(s *grpc.Server) {
+	files := new(protoregistry.Files)
+	for name, info := range s.GetServiceInfo() {
+		path, ok := info.Metadata.(string)
+		if !ok {
+			continue
+		}
+		if err := registerFileDescriptor(files, path); err != nil {
+			log.Warn("failed to load the descriptor of gRPC service for reflection",
+				zap.String("service", name), zap.String("file", path), zap.Error(err))
+		}
+	}
+	opts := reflection.ServerOptions{
+		Services:           s,
+		DescriptorResolver: &fallbackResolver{local: files},
+	}
+	reflectionv1.RegisterServerReflectionServer(s, reflection.NewServerV1(opts))
+	reflectionv1alpha.RegisterServerReflectionServer(s, reflection.NewServer(opts))
+}
+
+// registerFileDescriptor registers the descriptor of the given proto file and
+// its dependencies to files.
+func registerFileDescriptor(files *protoregistry.Files, path string) error {
+	if _, err := files.FindFileByPath(path); err == nil {
+		return nil
+	}
+	if fd, err := protoregistry.GlobalFiles.FindFileByPath(path); err == nil {
+		return errors.Trace(files.RegisterFile(fd))
+	}
+	gz := gogoproto.FileDescriptor(path)
+	if gz == nil {
+		return errors.Errorf("file descriptor %s not found", path)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fdProto := new(descriptorpb.FileDescriptorProto)
+	if err := proto.Unmarshal(raw, fdProto); err != nil {
+		return errors.Trace(err)
+	}
+	for _, dep := range fdProto.GetDependency() {
+		// A missing dependency becomes a placeholder, which only hides the
+		// types it defines from the reflection clients.
+		if err := registerFileDescriptor(files, dep); err != nil {
+			log.Debug("failed to load the dependency of proto file for reflection",
+				zap.String("file", path), zap.String("dependency", dep), zap.Error(err))
+		}
+	}
+	fd, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fdProto, files)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(files.RegisterFile(fd))
+}
+
+// fallbackResolver resolves descriptors from local first, and then from the
+// global registry, which contains the descriptors of the reflection service.
+type fallbackResolver struct {
+	local *protoregistry.Files
+}
+
+func (r *fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *fallbackResolver) FindDescriptorByName(
+	name protoreflect.FullName,
+) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
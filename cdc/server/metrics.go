@@ -14,6 +14,7 @@
 package server
 
 import (
+	"github.com/pingcap/tiflow/cdc/controller"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/owner"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/p2p"
+	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/sink/observer"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/prometheus/client_golang/prometheus"
@@ -48,6 +50,7 @@ func init() {
 	entry.InitMetrics(registry)
 	processor.InitMetrics(registry)
 	owner.InitMetrics(registry)
+	controller.InitMetrics(registry)
 	etcd.InitMetrics(registry)
 	orchestrator.InitMetrics(registry)
 	p2p.InitMetrics(registry)
@@ -57,6 +60,7 @@ func init() {
 	scheduler.InitMetrics(registry)
 	observer.InitMetrics(registry)
 	gc.InitMetrics(registry)
+	pdutil.InitMetrics(registry)
 	// TiKV client metrics, including metrics about resolved and region cache.
 	originalRegistry := prometheus.DefaultRegisterer
 	prometheus.DefaultRegisterer = registry
@@ -0,0 +1,115 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	clogutil "github.com/pingcap/tiflow/pkg/logutil"
+	"go.uber.org/zap"
+)
+
+// startPprofHTTP starts the pprof HTTP server, which is enabled by
+// `enable-pprof`. Unlike the pprof API of the status server, it listens on a
+// dedicated port, so that profiles are not exposed with the management API.
+// The following endpoints are served:
+//
+//   - /debug/pprof/heap: a sampling of memory allocations of live objects.
+//   - /debug/pprof/allocs: a sampling of all past memory allocations.
+//   - /debug/pprof/profile, /debug/pprof/trace: CPU profile and execution trace.
+//   - /debug/pprof/: the index of all profiles, e.g. goroutine and mutex.
+//
+// Delta profiles, e.g. `/debug/pprof/heap?seconds=30`, and CPU profiles last
+// at most `pprof-retention-secs`.
+func (s *server) startPprofHTTP(addr string, retentionSecs int) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrServeHTTP, err)
+	}
+
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(clogutil.InitGinLogWritter()))
+	registerPprofRoutes(router, retentionSecs)
+
+	// WriteTimeout must be longer than the longest profile.
+	s.pprofServer = &http.Server{
+		Handler:      router,
+		ReadTimeout:  httpConnectionTimeout,
+		WriteTimeout: httpConnectionTimeout,
+	}
+
+	go func() {
+		log.Info("pprof http server is running", zap.String("addr", addr),
+			zap.Int("retentionSecs", retentionSecs))
+		err := s.pprofServer.Serve(lis)
+		if err != nil && err != http.ErrServerClosed {
+			log.Error("pprof http server error", zap.Error(cerror.WrapError(cerror.ErrServeHTTP, err)))
+		}
+	}()
+	return nil
+}
+
+func registerPprofRoutes(router *gin.Engine, retentionSecs int) {
+	pprofGroup := router.Group("/debug/pprof/")
+	pprofGroup.Use(limitProfileDuration(retentionSecs))
+	pprofGroup.GET("", gin.WrapF(pprof.Index))
+	pprofGroup.GET("/:any", gin.WrapF(pprof.Index))
+	pprofGroup.GET("/heap", gin.WrapH(pprof.Handler("heap")))
+	pprofGroup.GET("/allocs", gin.WrapH(pprof.Handler("allocs")))
+	pprofGroup.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	pprofGroup.GET("/profile", gin.WrapF(pprof.Profile))
+	pprofGroup.GET("/symbol", gin.WrapF(pprof.Symbol))
+	pprofGroup.GET("/trace", gin.WrapF(pprof.Trace))
+}
+
+// limitProfileDuration rejects profiles that last longer than retentionSecs,
+// the base profile of a delta profile is kept in memory until it finishes.
+func limitProfileDuration(retentionSecs int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		seconds := c.Query("seconds")
+		if seconds == "" {
+			c.Next()
+			return
+		}
+		sec, err := strconv.ParseFloat(seconds, 64)
+		if err != nil || sec < 0 {
+			c.String(http.StatusBadRequest, "invalid seconds %q", seconds)
+			c.Abort()
+			return
+		}
+		if sec > float64(retentionSecs) {
+			c.String(http.StatusBadRequest,
+				"profile duration %ss exceeds pprof-retention-secs %d", seconds, retentionSecs)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (s *server) closePprofHTTP() {
+	if s.pprofServer == nil {
+		return
+	}
+	if err := s.pprofServer.Close(); err != nil {
+		log.Error("close pprof server", zap.Error(err))
+	}
+	s.pprofServer = nil
+}
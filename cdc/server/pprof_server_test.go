@@ -0,0 +1,71 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/utils/tempurl"
+)
+
+func TestPprofHTTP(t *testing.T) {
+	addr := tempurl.Alloc()[len("http://"):]
+	s := &server{}
+	require.NoError(t, s.startPprofHTTP(addr, 1))
+	defer s.closePprofHTTP()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cli := httputil.NewTestClient(nil)
+	get := func(path string) (*http.Response, error) {
+		var resp *http.Response
+		err := retry.Do(ctx, func() (err error) {
+			resp, err = cli.Get(ctx, fmt.Sprintf("http://%s%s", addr, path))
+			return err
+		}, retry.WithMaxTries(20), retry.WithBackoffBaseDelay(50))
+		return resp, err
+	}
+
+	for path, sampleType := range map[string]string{
+		"/debug/pprof/heap":   "inuse_space",
+		"/debug/pprof/allocs": "alloc_space",
+	} {
+		resp, err := get(path)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		p, err := profile.Parse(resp.Body)
+		require.NoError(t, resp.Body.Close())
+		require.NoError(t, err, path)
+		require.NoError(t, p.CheckValid())
+		sampleTypes := make([]string, 0, len(p.SampleType))
+		for _, st := range p.SampleType {
+			sampleTypes = append(sampleTypes, st.Type)
+		}
+		require.Contains(t, sampleTypes, sampleType, path)
+	}
+
+	// Delta profiles can not last longer than pprof-retention-secs.
+	resp, err := get("/debug/pprof/heap?seconds=2")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -83,6 +83,7 @@ type server struct {
 	grpcService        *p2p.ServerWrapper
 	diagnosticsService *sysutil.DiagnosticsServer
 	statusServer       *http.Server
+	pprofServer        *http.Server
 	etcdClient         etcd.CDCEtcdClient
 	// pdClient is the default upstream PD client.
 	// The PD acts as a metadata management service for TiCDC.
@@ -114,10 +115,9 @@ func New(pdEndpoints []string) (*server, error) {
 		return nil, errors.Trace(err)
 	}
 
-	debugConfig := config.GetGlobalServerConfig().Debug
 	s := &server{
 		pdEndpoints:        pdEndpoints,
-		grpcService:        p2p.NewServerWrapper(debugConfig.Messages.ToMessageServerConfig()),
+		grpcService:        p2p.NewServerWrapper(conf.MessageServerConfig()),
 		diagnosticsService: sysutil.NewDiagnosticsServer(conf.LogFile),
 		tcpServer:          tcpServer,
 	}
@@ -241,7 +241,16 @@ func (s *server) createSortEngineFactory() {
 	// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
 	sortDir := config.GetGlobalServerConfig().Sorter.SortDir
 	memInBytes := conf.Sorter.CacheSizeInMB * uint64(1<<20)
-	s.sortEngineFactory = factory.NewForPebble(sortDir, memInBytes, conf.Debug.DB)
+	if conf.Sorter.AdaptiveMemoryThresholdInMB > 0 {
+		threshold := conf.Sorter.AdaptiveMemoryThresholdInMB * uint64(1<<20)
+		s.sortEngineFactory = factory.NewForAdaptive(sortDir, memInBytes, conf.Debug.DB, threshold)
+		log.Info("adaptive sorter engine is enabled",
+			zap.Uint64("thresholdBytes", threshold),
+			zap.String("threshold", humanize.IBytes(threshold)),
+		)
+	} else {
+		s.sortEngineFactory = factory.NewForPebble(sortDir, memInBytes, conf.Debug.DB)
+	}
 	log.Info("sorter engine memory limit",
 		zap.Uint64("bytes", memInBytes),
 		zap.String("memory", humanize.IBytes(memInBytes)),
@@ -259,6 +268,14 @@ func (s *server) Run(serverCtx context.Context) error {
 		return err
 	}
 
+	conf := config.GetGlobalServerConfig()
+	if conf.EnablePprof {
+		err = s.startPprofHTTP(conf.PprofAddr, conf.PprofRetentionSecs)
+		if err != nil {
+			return err
+		}
+	}
+
 	return s.run(serverCtx)
 }
 
@@ -355,10 +372,7 @@ func (s *server) run(ctx context.Context) (err error) {
 		return s.tcpServer.Run(egCtx)
 	})
 
-	grpcServer := grpc.NewServer(s.grpcService.ServerOptions()...)
-	p2pProto.RegisterCDCPeerToPeerServer(grpcServer, s.grpcService)
-	diagnosticspb.RegisterDiagnosticsServer(grpcServer, s.diagnosticsService)
-
+	grpcServer := s.newGrpcServer()
 	eg.Go(func() error {
 		return grpcServer.Serve(s.tcpServer.GrpcListener())
 	})
@@ -375,6 +389,22 @@ func (s *server) run(ctx context.Context) (err error) {
 	return eg.Wait()
 }
 
+// newGrpcServer creates the gRPC server with the peer to peer messaging and
+// diagnostics services. The reflection service is registered as well, so that
+// generic gRPC tools can inspect the services without the proto files, e.g.
+//
+//	grpcurl -plaintext 127.0.0.1:8300 list
+//	grpcurl -plaintext 127.0.0.1:8300 describe p2p.CDCPeerToPeer
+//
+// Replace `-plaintext` with `-cacert`, `-cert` and `-key` if TLS is enabled.
+func (s *server) newGrpcServer() *grpc.Server {
+	grpcServer := grpc.NewServer(s.grpcService.ServerOptions()...)
+	p2pProto.RegisterCDCPeerToPeerServer(grpcServer, s.grpcService)
+	diagnosticspb.RegisterDiagnosticsServer(grpcServer, s.diagnosticsService)
+	registerReflection(grpcServer)
+	return grpcServer
+}
+
 // Drain removes tables in the current TiCDC instance.
 // It's part of graceful shutdown, should be called before Close.
 func (s *server) Drain() <-chan struct{} {
@@ -402,6 +432,7 @@ func (s *server) Close() {
 		}
 		s.statusServer = nil
 	}
+	s.closePprofHTTP()
 	if s.tcpServer != nil {
 		err := s.tcpServer.Close()
 		if err != nil {
@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -30,6 +31,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pingcap/sysutil"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -37,6 +39,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/etcd"
 	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
 	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -45,6 +48,11 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/embed"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 type testServer struct {
@@ -170,6 +178,75 @@ func TestCheckDir(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGrpcServerReflection(t *testing.T) {
+	t.Parallel()
+
+	s := &server{
+		grpcService: p2p.NewServerWrapper(
+			config.GetDefaultServerConfig().Debug.Messages.ToMessageServerConfig()),
+		diagnosticsService: sysutil.NewDiagnosticsServer(""),
+	}
+	grpcServer := s.newGrpcServer()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = grpcServer.Serve(lis)
+	}()
+	defer func() {
+		grpcServer.Stop()
+		wg.Wait()
+	}()
+
+	conn, err := grpc.Dial(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+
+	// List the services, like `grpcurl list`.
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	var services []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		services = append(services, svc.GetName())
+	}
+	require.Contains(t, services, "p2p.CDCPeerToPeer")
+	require.Contains(t, services, "diagnosticspb.Diagnostics")
+	require.Contains(t, services, "grpc.reflection.v1.ServerReflection")
+
+	// Describe the peer to peer service, like `grpcurl describe`.
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: "p2p.CDCPeerToPeer",
+		},
+	})
+	require.NoError(t, err)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Nil(t, resp.GetErrorResponse())
+	fds := resp.GetFileDescriptorResponse().GetFileDescriptorProto()
+	require.NotEmpty(t, fds)
+	fd := new(descriptorpb.FileDescriptorProto)
+	require.NoError(t, proto.Unmarshal(fds[0], fd))
+	require.Equal(t, "p2p", fd.GetPackage())
+	require.Len(t, fd.GetService(), 1)
+	require.Equal(t, "CDCPeerToPeer", fd.GetService()[0].GetName())
+}
+
 const retryTime = 20
 
 func TestServerTLSWithoutCommonName(t *testing.T) {
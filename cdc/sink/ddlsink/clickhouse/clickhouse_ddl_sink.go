@@ -0,0 +1,150 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+	"go.uber.org/zap"
+)
+
+const defaultDDLMaxRetry uint64 = 20
+
+// GetDBConnImpl is the implementation of pclickhouse.Factory.
+// Exported for testing.
+var GetDBConnImpl pclickhouse.Factory = pclickhouse.CreateClickHouseDBConn
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is a sink that writes DDL events to ClickHouse.
+// The DDLs are translated from the schema of the tables instead of the
+// queries, the DDLs which can't be translated are skipped.
+type DDLSink struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id model.ChangeFeedID
+	// db is the database connection.
+	db *sql.DB
+	// statistics is the statistics of this sink.
+	// We use it to record the DDL count.
+	statistics *metrics.Statistics
+}
+
+// NewDDLSink creates a new DDLSink.
+func NewDDLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+) (*DDLSink, error) {
+	cfg := pclickhouse.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	db, err := GetDBConnImpl(ctx, pclickhouse.GenerateDSN(sinkURI))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &DDLSink{
+		id:         changefeedID,
+		db:         db,
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+
+	log.Info("ClickHouse DDL sink is created",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return m, nil
+}
+
+// WriteDDLEvent writes a DDL event to the ClickHouse database.
+func (m *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	stmts := translateDDL(ddl)
+	if len(stmts) == 0 {
+		log.Warn("DDL is not supported by the ClickHouse sink, skip it",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.String("ddl", ddl.Query))
+		return nil
+	}
+	return retry.Do(ctx, func() error {
+		err := m.statistics.RecordDDLExecution(func() error {
+			return m.execDDL(ctx, ddl, stmts)
+		})
+		if err != nil {
+			log.Warn("Execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+// execDDL executes the statements of a DDL one by one. The DDLs of
+// ClickHouse are not transactional, all the statements are idempotent so
+// that they can be retried.
+func (m *DDLSink) execDDL(ctx context.Context, ddl *model.DDLEvent, stmts []string) error {
+	for _, stmt := range stmts {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return cerror.WrapError(cerror.ErrClickHouseTxnError, err)
+		}
+	}
+
+	log.Info("Exec DDL succeeded",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID),
+		zap.Uint64("startTs", ddl.StartTs),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.Strings("sqls", stmts))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Only for RowSink for now.
+	return nil
+}
+
+// Close closes the database connection.
+func (m *DDLSink) Close() {
+	if m.statistics != nil {
+		m.statistics.Close()
+	}
+	if m.db != nil {
+		if err := m.db.Close(); err != nil {
+			log.Warn("ClickHouse ddl sink close db with error",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+		}
+	}
+}
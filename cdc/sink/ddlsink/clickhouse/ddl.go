@@ -0,0 +1,111 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+)
+
+// translateDDL translates a DDL event to the statements of ClickHouse.
+// It returns nil if the DDL is not supported by the ClickHouse sink.
+func translateDDL(ddl *model.DDLEvent) []string {
+	tableInfo := ddl.TableInfo
+	schema := tableInfo.TableName.Schema
+	switch ddl.Type {
+	case timodel.ActionCreateSchema:
+		return []string{"CREATE DATABASE IF NOT EXISTS " + quotes.QuoteName(schema)}
+	case timodel.ActionDropSchema:
+		return []string{"DROP DATABASE IF EXISTS " + quotes.QuoteName(schema)}
+	case timodel.ActionCreateTable:
+		if tableInfo.TableInfo == nil || tableInfo.IsView() {
+			return nil
+		}
+		return []string{buildCreateTable(tableInfo)}
+	case timodel.ActionDropTable:
+		return []string{"DROP TABLE IF EXISTS " + quoteTable(tableInfo)}
+	case timodel.ActionTruncateTable:
+		return []string{"TRUNCATE TABLE IF EXISTS " + quoteTable(tableInfo)}
+	case timodel.ActionAddColumn:
+		if ddl.PreTableInfo == nil {
+			return nil
+		}
+		var stmts []string
+		for _, col := range tableInfo.Columns {
+			if col.IsGenerated() && !col.GeneratedStored {
+				continue
+			}
+			if _, ok := ddl.PreTableInfo.GetColumnInfo(col.ID); ok {
+				continue
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s",
+				quoteTable(tableInfo), quotes.QuoteName(col.Name.O), pclickhouse.ColumnType(&col.FieldType)))
+		}
+		return stmts
+	case timodel.ActionDropColumn:
+		if ddl.PreTableInfo == nil {
+			return nil
+		}
+		var stmts []string
+		for _, col := range ddl.PreTableInfo.Columns {
+			if _, ok := tableInfo.GetColumnInfo(col.ID); ok {
+				continue
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s",
+				quoteTable(tableInfo), quotes.QuoteName(col.Name.O)))
+		}
+		return stmts
+	default:
+		return nil
+	}
+}
+
+func quoteTable(tableInfo *model.TableInfo) string {
+	return quotes.QuoteSchema(tableInfo.TableName.Schema, tableInfo.TableName.Table)
+}
+
+// buildCreateTable creates a ReplacingMergeTree table sorted by the handle
+// key, the rows of a key are collapsed to the one with the largest commit ts,
+// and removed if it's deleted. Tables without handle key are created with
+// MergeTree, the deleted rows are kept with the deleted mark.
+func buildCreateTable(tableInfo *model.TableInfo) string {
+	definitions := make([]string, 0, len(tableInfo.Columns)+2)
+	keys := make([]string, 0)
+	for _, col := range tableInfo.Columns {
+		if col.IsGenerated() && !col.GeneratedStored {
+			continue
+		}
+		definitions = append(definitions,
+			quotes.QuoteName(col.Name.O)+" "+pclickhouse.ColumnType(&col.FieldType))
+		if tableInfo.ForceGetColumnFlagType(col.ID).IsHandleKey() {
+			keys = append(keys, quotes.QuoteName(col.Name.O))
+		}
+	}
+	commitTs := quotes.QuoteName(pclickhouse.CommitTsColumn)
+	isDeleted := quotes.QuoteName(pclickhouse.IsDeletedColumn)
+	definitions = append(definitions, commitTs+" UInt64", isDeleted+" UInt8")
+
+	engine := "MergeTree ORDER BY tuple()"
+	if len(keys) > 0 {
+		engine = fmt.Sprintf("ReplacingMergeTree(%s, %s) ORDER BY (%s)",
+			commitTs, isDeleted, strings.Join(keys, ", "))
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) ENGINE = %s",
+		quoteTable(tableInfo), strings.Join(definitions, ", "), engine)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateDDL(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event("create database test2")
+	require.Equal(t, []string{"CREATE DATABASE IF NOT EXISTS `test2`"}, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("create table test2.t(id bigint primary key, " +
+		"price decimal(10, 2), doc json, c datetime(3))")
+	require.Equal(t, []string{"CREATE TABLE IF NOT EXISTS `test2`.`t` (" +
+		"`id` Int64, `price` Nullable(Decimal128(2)), `doc` Nullable(String), " +
+		"`c` Nullable(DateTime64(3, 'UTC')), `_tidb_commit_ts` UInt64, `_tidb_is_deleted` UInt8) " +
+		"ENGINE = ReplacingMergeTree(`_tidb_commit_ts`, `_tidb_is_deleted`) ORDER BY (`id`)"},
+		translateDDL(ddl))
+
+	ddl = helper.DDL2Event("create table test2.t2(a int, b varchar(8))")
+	require.Equal(t, []string{"CREATE TABLE IF NOT EXISTS `test2`.`t2` (" +
+		"`a` Nullable(Int32), `b` Nullable(String), `_tidb_commit_ts` UInt64, `_tidb_is_deleted` UInt8) " +
+		"ENGINE = MergeTree ORDER BY tuple()"}, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("alter table test2.t add column d varchar(16) not null default 'd'")
+	require.Equal(t, []string{"ALTER TABLE `test2`.`t` ADD COLUMN IF NOT EXISTS `d` String"},
+		translateDDL(ddl))
+
+	ddl = helper.DDL2Event("alter table test2.t drop column c")
+	require.Equal(t, []string{"ALTER TABLE `test2`.`t` DROP COLUMN IF EXISTS `c`"}, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("truncate table test2.t")
+	require.Equal(t, []string{"TRUNCATE TABLE IF EXISTS `test2`.`t`"}, translateDDL(ddl))
+
+	// DDLs not supported by the ClickHouse sink.
+	ddl = helper.DDL2Event("alter table test2.t add index idx(d)")
+	require.Nil(t, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("drop database test2")
+	require.Equal(t, []string{"DROP DATABASE IF EXISTS `test2`"}, translateDDL(ddl))
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/lightning"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const defaultDDLMaxRetry uint64 = 20
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink writes the schema files of TiDB Lightning, i.e.
+// `<db>-schema-create.sql` and `<db>.<table>-schema.sql`, for the schemas and
+// tables created after the changefeed starts. The other DDLs are ignored,
+// the tables must be altered in the downstream manually.
+type DDLSink struct {
+	// id indicates which changefeed this sink belongs to.
+	id      model.ChangeFeedID
+	storage storage.ExternalStorage
+	// statistics is used to record the DDL metrics.
+	statistics *metrics.Statistics
+}
+
+// NewDDLSink creates a DDL sink for the CSV files.
+func NewDDLSink(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+) (*DDLSink, error) {
+	cfg := lightning.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	storage, err := putil.GetExternalStorageFromURI(ctx, cfg.StorageURI)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DDLSink{
+		id:         changefeedID,
+		storage:    storage,
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+	log.Info("CSV DDL sink is created",
+		zap.String("namespace", d.id.Namespace),
+		zap.String("changefeed", d.id.ID))
+	return d, nil
+}
+
+// WriteDDLEvent writes the schema files of the created schema or table.
+func (d *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	switch ddl.Type {
+	case timodel.ActionCreateSchema, timodel.ActionCreateTable:
+	default:
+		log.Info("DDL is ignored by the CSV sink",
+			zap.String("namespace", d.id.Namespace),
+			zap.String("changefeed", d.id.ID),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.String("ddl", ddl.Query))
+		return nil
+	}
+
+	return retry.Do(ctx, func() error {
+		err := d.statistics.RecordDDLExecution(func() error {
+			return d.execDDL(ctx, ddl)
+		})
+		if err != nil {
+			log.Warn("Execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", d.id.Namespace),
+				zap.String("changefeed", d.id.ID),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func (d *DDLSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
+	schemaName := ddl.TableInfo.TableName.Schema
+	// The schema file is also written for a table, since the schema may be
+	// created before the changefeed starts.
+	createSchema := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s;\n", quotes.QuoteName(schemaName))
+	if err := d.storage.WriteFile(ctx, lightning.SchemaCreateFileName(schemaName),
+		[]byte(createSchema)); err != nil {
+		return errors.Trace(err)
+	}
+	if ddl.Type == timodel.ActionCreateTable {
+		createTable := strings.TrimRight(strings.TrimSpace(ddl.Query), ";") + ";\n"
+		if err := d.storage.WriteFile(ctx,
+			lightning.TableSchemaFileName(schemaName, ddl.TableInfo.TableName.Table),
+			[]byte(createTable)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	log.Info("Exec DDL succeeded",
+		zap.String("namespace", d.id.Namespace),
+		zap.String("changefeed", d.id.ID),
+		zap.Uint64("startTs", ddl.StartTs),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("ddl", ddl.Query))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (d *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Only for RowSink for now.
+	return nil
+}
+
+// Close closes the sink.
+func (d *DDLSink) Close() {
+	if d.statistics != nil {
+		d.statistics.Close()
+	}
+}
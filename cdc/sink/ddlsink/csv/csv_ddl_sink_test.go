@@ -0,0 +1,56 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDDLEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("csv://%s?storage=file", dir))
+	require.NoError(t, err)
+	s, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+	defer s.Close()
+
+	readFile := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("create database test2")))
+	require.Equal(t, "CREATE DATABASE IF NOT EXISTS `test2`;\n", readFile("test2-schema-create.sql"))
+
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("create table test.t(id int primary key, v int)")))
+	require.Equal(t, "CREATE DATABASE IF NOT EXISTS `test`;\n", readFile("test-schema-create.sql"))
+	require.Equal(t, "create table test.t(id int primary key, v int);\n", readFile("test.t-schema.sql"))
+
+	// The other DDLs are ignored.
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("alter table test.t add column c int")))
+	require.Equal(t, "create table test.t(id int primary key, v int);\n", readFile("test.t-schema.sql"))
+}
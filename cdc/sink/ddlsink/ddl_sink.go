@@ -31,3 +31,20 @@ type Sink interface {
 	// Close closes the sink.
 	Close()
 }
+
+// HeartbeatSink is implemented by the sinks which support heartbeat events.
+// This only for MQSink for now.
+type HeartbeatSink interface {
+	// WriteHeartbeat writes a heartbeat event carrying the resolved ts to the sink.
+	// Note: This is a synchronous and thread-safe method.
+	WriteHeartbeat(ctx context.Context, ts uint64, tables []*model.TableInfo) error
+}
+
+// BatchDDLSink is implemented by the sinks which can execute several DDL
+// events at once. This only for MySQLSink for now.
+type BatchDDLSink interface {
+	// WriteDDLEvents writes the DDL events committed at the same time to the
+	// sink in order.
+	// Note: This is a synchronous and thread-safe method.
+	WriteDDLEvents(ctx context.Context, ddls []*model.DDLEvent) error
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pes "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"go.uber.org/zap"
+)
+
+const defaultDDLMaxRetry uint64 = 20
+
+// GetClientImpl is the implementation of pes.Factory.
+// Exported for testing.
+var GetClientImpl pes.Factory = pes.CreateClient
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is a sink that applies DDL events to Elasticsearch.
+// The indices are created with dynamic mappings when the documents are
+// written, so only the DDLs which remove all the rows of a table are
+// applied, by deleting the index of the table.
+type DDLSink struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id model.ChangeFeedID
+	// client is the Elasticsearch client.
+	client *elastic.Client
+	cfg    *pes.Config
+	// statistics is the statistics of this sink.
+	// We use it to record the DDL count.
+	statistics *metrics.Statistics
+}
+
+// NewDDLSink creates a new DDLSink.
+func NewDDLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*DDLSink, error) {
+	cfg := pes.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	client, err := GetClientImpl(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &DDLSink{
+		id:         changefeedID,
+		client:     client,
+		cfg:        cfg,
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+
+	log.Info("Elasticsearch DDL sink is created",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return m, nil
+}
+
+// WriteDDLEvent writes a DDL event to Elasticsearch.
+func (m *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionTruncateTable:
+	default:
+		log.Info("DDL is ignored by the Elasticsearch sink",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.String("ddl", ddl.Query))
+		return nil
+	}
+
+	index := m.cfg.IndexName(ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table)
+	return retry.Do(ctx, func() error {
+		err := m.statistics.RecordDDLExecution(func() error {
+			return m.deleteIndex(ctx, ddl, index)
+		})
+		if err != nil {
+			log.Warn("Execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+// deleteIndex deletes the index of a table, it's recreated when the
+// documents of the table are written again.
+func (m *DDLSink) deleteIndex(ctx context.Context, ddl *model.DDLEvent, index string) error {
+	_, err := m.client.DeleteIndex(index).Do(ctx)
+	if err != nil && !elastic.IsStatusCode(err, http.StatusNotFound) {
+		return cerror.WrapError(cerror.ErrElasticsearchRequestError, err)
+	}
+
+	log.Info("Exec DDL succeeded",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID),
+		zap.Uint64("startTs", ddl.StartTs),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("ddl", ddl.Query),
+		zap.String("deletedIndex", index))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Only for RowSink for now.
+	return nil
+}
+
+// Close closes the client.
+func (m *DDLSink) Close() {
+	if m.statistics != nil {
+		m.statistics.Close()
+	}
+	if m.client != nil {
+		m.client.Stop()
+	}
+}
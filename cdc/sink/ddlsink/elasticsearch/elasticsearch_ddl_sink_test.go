@@ -0,0 +1,80 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	pes "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDDLEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		deleted = append(deleted, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		// The index doesn't exist if no document has been written.
+		if r.URL.Path == "/test.t2" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"type":"index_not_found_exception"},"status":404}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer server.Close()
+
+	GetClientImpl = func(ctx context.Context, cfg *pes.Config) (*elastic.Client, error) {
+		return elastic.NewClient(elastic.SetURL(server.URL),
+			elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	}
+	defer func() { GetClientImpl = pes.CreateClient }()
+
+	ctx := context.Background()
+	sinkURI, err := url.Parse("es://127.0.0.1:9200")
+	require.NoError(t, err)
+	s, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	defer s.Close()
+
+	ddls := []string{
+		"create table test.t(id int primary key)",
+		"create table test.t2(id int primary key)",
+		"alter table test.t add column c int",
+		"truncate table test.t",
+		"drop table test.t2",
+	}
+	for _, query := range ddls {
+		require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event(query)))
+	}
+	require.Equal(t, []string{"DELETE /test.t", "DELETE /test.t2"}, deleted)
+}
@@ -20,10 +20,17 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/blackhole"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/clickhouse"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/cloudstorage"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/csv"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/elasticsearch"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/iceberg"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mongodb"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mysql"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/pg"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/redis"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -58,6 +65,20 @@ func New(
 		return blackhole.NewDDLSink(), nil
 	case sink.MySQLSSLScheme, sink.MySQLScheme, sink.TiDBScheme, sink.TiDBSSLScheme:
 		return mysql.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.PostgresScheme, sink.PostgreSQLScheme:
+		return pg.NewDDLSink(ctx, changefeedID, sinkURI)
+	case sink.ClickHouseScheme:
+		return clickhouse.NewDDLSink(ctx, changefeedID, sinkURI)
+	case sink.ElasticsearchScheme, sink.ESScheme:
+		return elasticsearch.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.RedisScheme:
+		return redis.NewDDLSink(ctx, changefeedID, sinkURI)
+	case sink.IcebergScheme:
+		return iceberg.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.CSVScheme:
+		return csv.NewDDLSink(ctx, changefeedID, sinkURI)
+	case sink.MongoDBScheme, sink.MongoDBSRVScheme:
+		return mongodb.NewDDLSink(ctx, changefeedID, sinkURI)
 	case sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme:
 		return cloudstorage.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
 	case sink.PulsarScheme, sink.PulsarSSLScheme:
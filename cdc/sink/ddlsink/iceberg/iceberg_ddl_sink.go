@@ -0,0 +1,160 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	piceberg "github.com/pingcap/tiflow/pkg/sink/iceberg"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const defaultDDLMaxRetry uint64 = 20
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is a sink that applies DDL events to the Iceberg tables.
+// The tables are created by CREATE TABLE, and truncated by TRUNCATE TABLE
+// with an empty snapshot. The schema changes are applied lazily when the
+// next snapshot of the table is committed by the DML sink. Dropped tables
+// are kept in the warehouse, so that the history can still be queried.
+type DDLSink struct {
+	// id indicates which changefeed this sink belongs to.
+	id      model.ChangeFeedID
+	catalog *piceberg.Catalog
+	cfg     *piceberg.Config
+	// statistics is used to record the DDL metrics.
+	statistics *metrics.Statistics
+}
+
+// NewDDLSink creates a DDL sink for Iceberg.
+func NewDDLSink(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*DDLSink, error) {
+	cfg := piceberg.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	storage, err := putil.GetExternalStorageFromURI(ctx, cfg.StorageURI)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := piceberg.NewCatalog(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DDLSink{
+		id:         changefeedID,
+		catalog:    catalog,
+		cfg:        cfg,
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+	log.Info("Iceberg DDL sink is created",
+		zap.String("namespace", d.id.Namespace),
+		zap.String("changefeed", d.id.ID))
+	return d, nil
+}
+
+// WriteDDLEvent applies the DDL event to the Iceberg tables.
+func (d *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	switch ddl.Type {
+	case timodel.ActionCreateTable, timodel.ActionTruncateTable:
+	case timodel.ActionDropTable, timodel.ActionDropSchema:
+		log.Warn("Iceberg sink doesn't drop the tables, the DDL is skipped",
+			zap.String("namespace", d.id.Namespace),
+			zap.String("changefeed", d.id.ID),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.String("ddl", ddl.Query))
+		return nil
+	default:
+		log.Info("DDL is ignored by the Iceberg sink",
+			zap.String("namespace", d.id.Namespace),
+			zap.String("changefeed", d.id.ID),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.String("ddl", ddl.Query))
+		return nil
+	}
+
+	return retry.Do(ctx, func() error {
+		err := d.statistics.RecordDDLExecution(func() error {
+			return d.execDDL(ctx, ddl)
+		})
+		if err != nil {
+			log.Warn("Execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", d.id.Namespace),
+				zap.String("changefeed", d.id.ID),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func (d *DDLSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
+	schemaName, tableName := ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table
+	table, err := d.catalog.CreateTable(ctx, schemaName, tableName,
+		piceberg.NewSchema(ddl.TableInfo), d.cfg.PartitionFields(schemaName, tableName))
+	if err != nil {
+		return err
+	}
+	if ddl.Type == timodel.ActionTruncateTable {
+		// Commit an empty snapshot which removes all the files, the previous
+		// snapshots are kept for the time travel queries.
+		if err := table.Commit(ctx, piceberg.NewSchema(ddl.TableInfo), nil, ddl.CommitTs, true); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Exec DDL succeeded",
+		zap.String("namespace", d.id.Namespace),
+		zap.String("changefeed", d.id.ID),
+		zap.Uint64("startTs", ddl.StartTs),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("ddl", ddl.Query))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (d *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Only for RowSink for now.
+	return nil
+}
+
+// Close closes the sink.
+func (d *DDLSink) Close() {
+	if d.statistics != nil {
+		d.statistics.Close()
+	}
+}
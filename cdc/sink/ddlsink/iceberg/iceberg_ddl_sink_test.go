@@ -0,0 +1,75 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	piceberg "github.com/pingcap/tiflow/pkg/sink/iceberg"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDDLEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("iceberg://%s?storage=file", dir))
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.IcebergPartitionBy = []*config.IcebergPartitionRule{
+		{Matcher: []string{"test.*"}, Fields: []string{"bucket(4, id)"}},
+	}
+	s, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	defer s.Close()
+
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+dir)
+	require.NoError(t, err)
+	catalog, err := piceberg.NewCatalog(storage)
+	require.NoError(t, err)
+
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("create table test.t(id int primary key, v int)")))
+	table, err := catalog.LoadTable(ctx, "test", "t")
+	require.NoError(t, err)
+	require.NotNil(t, table)
+	require.Len(t, table.Spec().Fields, 1)
+	require.Equal(t, "bucket[4]", table.Spec().Fields[0].Transform)
+	require.Equal(t, []int{1}, table.Metadata.CurrentSchema().IdentifierFieldIDs)
+	require.Nil(t, table.Metadata.CurrentSnapshot())
+
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("alter table test.t add column c int")))
+	truncate := helper.DDL2Event("truncate table test.t")
+	require.NoError(t, s.WriteDDLEvent(ctx, truncate))
+	table, err = catalog.LoadTable(ctx, "test", "t")
+	require.NoError(t, err)
+	require.Len(t, table.Metadata.Snapshots, 1)
+	require.Equal(t, truncate.CommitTs, table.Metadata.CurrentSnapshot().CommitTs())
+	require.Equal(t, "overwrite", table.Metadata.CurrentSnapshot().Summary["operation"])
+	require.Len(t, table.Metadata.CurrentSchema().Fields, 3)
+
+	// The dropped tables are kept.
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("drop table test.t")))
+	table, err = catalog.LoadTable(ctx, "test", "t")
+	require.NoError(t, err)
+	require.NotNil(t, table)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmongodb "github.com/pingcap/tiflow/pkg/sink/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const defaultDDLMaxRetry uint64 = 20
+
+// GetClientImpl is the implementation of pmongodb.Factory.
+// Exported for testing.
+var GetClientImpl pmongodb.Factory = pmongodb.CreateClient
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is a sink that applies DDL events to MongoDB.
+// The collections are created implicitly by the first writes, so only the
+// DDLs which remove the rows are applied: DROP TABLE drops the collection,
+// TRUNCATE TABLE deletes all the documents of the collection, and DROP
+// DATABASE drops the database.
+type DDLSink struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id model.ChangeFeedID
+	// client is the MongoDB client.
+	client *mongo.Client
+	// statistics is the statistics of this sink.
+	// We use it to record the DDL count.
+	statistics *metrics.Statistics
+}
+
+// NewDDLSink creates a new DDLSink.
+func NewDDLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+) (*DDLSink, error) {
+	cfg := pmongodb.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	client, err := GetClientImpl(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &DDLSink{
+		id:         changefeedID,
+		client:     client,
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+
+	log.Info("MongoDB DDL sink is created",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return m, nil
+}
+
+// WriteDDLEvent writes a DDL event to MongoDB.
+func (m *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionTruncateTable, timodel.ActionDropSchema:
+	default:
+		log.Info("DDL is ignored by the MongoDB sink",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.String("ddl", ddl.Query))
+		return nil
+	}
+
+	return retry.Do(ctx, func() error {
+		err := m.statistics.RecordDDLExecution(func() error {
+			return m.execDDL(ctx, ddl)
+		})
+		if err != nil {
+			log.Warn("Execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func (m *DDLSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
+	database := m.client.Database(ddl.TableInfo.TableName.Schema)
+	var err error
+	switch ddl.Type {
+	case timodel.ActionDropSchema:
+		err = database.Drop(ctx)
+	case timodel.ActionDropTable:
+		err = database.Collection(ddl.TableInfo.TableName.Table).Drop(ctx)
+	case timodel.ActionTruncateTable:
+		_, err = database.Collection(ddl.TableInfo.TableName.Table).DeleteMany(ctx, bson.D{})
+	}
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMongoDBRequestError, err)
+	}
+
+	log.Info("Exec DDL succeeded",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID),
+		zap.Uint64("startTs", ddl.StartTs),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("ddl", ddl.Query))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Only for RowSink for now.
+	return nil
+}
+
+// Close closes the client.
+func (m *DDLSink) Close() {
+	if m.statistics != nil {
+		m.statistics.Close()
+	}
+	if m.client != nil {
+		if err := m.client.Disconnect(context.Background()); err != nil {
+			log.Warn("MongoDB ddl sink close client with error",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	pmongodb "github.com/pingcap/tiflow/pkg/sink/mongodb"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWriteIgnoredDDLEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	GetClientImpl = func(ctx context.Context, cfg *pmongodb.Config) (*mongo.Client, error) {
+		require.Equal(t, "mongodb://127.0.0.1:27017", cfg.URI)
+		// The client connects lazily, so no server is needed if the DDLs
+		// are ignored.
+		return mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI))
+	}
+	defer func() { GetClientImpl = pmongodb.CreateClient }()
+
+	ctx := context.Background()
+	sinkURI, err := url.Parse("mongodb://127.0.0.1:27017")
+	require.NoError(t, err)
+	s, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("create table test.t(id int primary key)")))
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("alter table test.t add column c int")))
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("create index idx_c on test.t(c)")))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
 	"go.uber.org/zap"
 )
 
@@ -36,6 +37,7 @@ var _ DDLProducer = (*pulsarProducers)(nil)
 type pulsarProducers struct {
 	client           pulsar.Client
 	pConfig          *config.PulsarConfig
+	producerConfig   *config.PulsarProducerConfig
 	defaultTopicName string
 	// support multiple topics
 	producers      *lru.Cache
@@ -108,7 +110,8 @@ func NewPulsarProducer(
 		return nil, err
 	}
 
-	defaultProducer, err := newProducer(pConfig, client, topicName)
+	defaultProducer, err := newProducer(
+		pConfig, sinkConfig.PulsarProducerConfig, client, topicName)
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +136,7 @@ func NewPulsarProducer(
 	return &pulsarProducers{
 		client:           client,
 		pConfig:          pConfig,
+		producerConfig:   sinkConfig.PulsarProducerConfig,
 		producers:        producers,
 		defaultTopicName: topicName,
 		id:               changefeedID,
@@ -143,26 +147,11 @@ func NewPulsarProducer(
 // One topic is used by one producer
 func newProducer(
 	pConfig *config.PulsarConfig,
+	producerConfig *config.PulsarProducerConfig,
 	client pulsar.Client,
 	topicName string,
 ) (pulsar.Producer, error) {
-	po := pulsar.ProducerOptions{
-		Topic: topicName,
-	}
-	if pConfig.BatchingMaxMessages != nil {
-		po.BatchingMaxMessages = *pConfig.BatchingMaxMessages
-	}
-	if pConfig.BatchingMaxPublishDelay != nil {
-		po.BatchingMaxPublishDelay = pConfig.BatchingMaxPublishDelay.Duration()
-	}
-	if pConfig.CompressionType != nil {
-		po.CompressionType = pConfig.CompressionType.Value()
-		po.CompressionLevel = pulsar.Default
-	}
-	if pConfig.SendTimeout != nil {
-		po.SendTimeout = pConfig.SendTimeout.Duration()
-	}
-
+	po := pulsarConfig.NewProducerOptions(topicName, pConfig, producerConfig)
 	producer, err := client.CreateProducer(po)
 	if err != nil {
 		return nil, err
@@ -192,7 +181,7 @@ func (p *pulsarProducers) GetProducerByTopic(topicName string) (producer pulsar.
 	}
 
 	if !ok { // create a new producer for the topicName
-		producer, err = newProducer(p.pConfig, p.client, topicName)
+		producer, err = newProducer(p.pConfig, p.producerConfig, p.client, topicName)
 		if err != nil {
 			return nil, err
 		}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	"go.uber.org/zap"
 )
@@ -52,7 +53,10 @@ func getDDLDispatchRule(protocol config.Protocol) DDLDispatchRule {
 }
 
 // Assert Sink implementation
-var _ ddlsink.Sink = (*DDLSink)(nil)
+var (
+	_ ddlsink.Sink          = (*DDLSink)(nil)
+	_ ddlsink.HeartbeatSink = (*DDLSink)(nil)
+)
 
 // DDLSink is a sink that sends DDL events to the MQ system.
 type DDLSink struct {
@@ -153,6 +157,38 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 	if msg == nil {
 		return nil
 	}
+	return k.broadcastMessage(ctx, msg, tables)
+}
+
+// WriteHeartbeat sends the heartbeat event to the MQ system.
+// If the protocol can not distinguish heartbeat events from checkpoint events,
+// the resolved ts is sent as a checkpoint event.
+func (k *DDLSink) WriteHeartbeat(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	encoder := k.encoderBuilder.Build()
+	heartbeatEncoder, ok := encoder.(codec.HeartbeatEventEncoder)
+	if !ok {
+		return k.WriteCheckpointTs(ctx, ts, tables)
+	}
+	msg, err := heartbeatEncoder.EncodeHeartbeatEvent(ts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if msg == nil {
+		return nil
+	}
+	log.Debug("Emit heartbeat event",
+		zap.String("namespace", k.id.Namespace),
+		zap.String("changefeed", k.id.ID),
+		zap.Uint64("resolvedTs", ts))
+	return k.broadcastMessage(ctx, msg, tables)
+}
+
+// broadcastMessage sends the message to all partitions of the topics of tables.
+func (k *DDLSink) broadcastMessage(ctx context.Context,
+	msg *common.Message, tables []*model.TableInfo,
+) error {
 	// NOTICE: When there are no tables to replicate,
 	// we need to send checkpoint ts to the default topic.
 	// This will be compatible with the old behavior.
@@ -163,7 +199,7 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 			return errors.Trace(err)
 		}
 		log.Debug("Emit checkpointTs to default topic",
-			zap.String("topic", topic), zap.Uint64("checkpointTs", ts))
+			zap.String("topic", topic), zap.Uint64("checkpointTs", msg.Ts))
 		err = k.producer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
 		return errors.Trace(err)
 	}
@@ -0,0 +1,187 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxDDLBatchCount is the max number of DDLs executed in a batch.
+	maxDDLBatchCount = 100
+	// maxDDLBatchSize is the max size of the SQL executed in a batch.
+	maxDDLBatchSize = 1024 * 1024
+)
+
+// Assert BatchDDLSink implementation
+var _ ddlsink.BatchDDLSink = (*DDLSink)(nil)
+
+// WriteDDLEvents writes the DDL events in order. If BatchDDLMode is enabled,
+// the consecutive `CREATE TABLE` DDLs are executed in multi-statement batches
+// of at most maxDDLBatchCount DDLs or maxDDLBatchSize bytes.
+func (m *DDLSink) WriteDDLEvents(ctx context.Context, ddls []*model.DDLEvent) error {
+	for len(ddls) > 0 {
+		n := 0
+		if m.cfg.BatchDDLMode {
+			n = m.nextDDLBatch(ddls)
+		}
+		if n <= 1 {
+			if err := m.WriteDDLEvent(ctx, ddls[0]); err != nil {
+				return err
+			}
+			ddls = ddls[1:]
+			continue
+		}
+		if err := m.writeDDLBatch(ctx, ddls[:n]); err != nil {
+			return err
+		}
+		ddls = ddls[n:]
+	}
+	return nil
+}
+
+// nextDDLBatch returns the number of the leading DDLs which can be executed
+// in a batch.
+func (m *DDLSink) nextDDLBatch(ddls []*model.DDLEvent) int {
+	n, size := 0, 0
+	for _, ddl := range ddls {
+		if n >= maxDDLBatchCount || !m.canBatchDDL(ddl) {
+			break
+		}
+		size += len(ddl.Query)
+		if n > 0 && size > maxDDLBatchSize {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// canBatchDDL returns true if the DDL can be executed as is in a batch,
+// the DDLs which need to be rewritten or checked are executed one by one.
+func (m *DDLSink) canBatchDDL(ddl *model.DDLEvent) bool {
+	if ddl.Type != timodel.ActionCreateTable {
+		return false
+	}
+	return m.cfg.IsTiDB || (!hasMultiValuedIndex(ddl) && !isPartitionDDL(ddl))
+}
+
+// writeDDLBatch executes the DDLs in a batch. The DDLs are executed one by
+// one if the batch fails, so that the ignorable errors, e.g. the table
+// already exists, are handled as usual.
+func (m *DDLSink) writeDDLBatch(ctx context.Context, ddls []*model.DDLEvent) error {
+	err := m.statistics.RecordDDLExecution(func() error { return m.execDDLBatch(ctx, ddls) })
+	if err != nil {
+		log.Warn("Execute DDLs in a batch failed, execute them one by one",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.Int("count", len(ddls)),
+			zap.Uint64("commitTs", ddls[0].CommitTs),
+			zap.Error(err))
+		for _, ddl := range ddls {
+			if err := m.WriteDDLEvent(ctx, ddl); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if m.cfg.SchemaCompatibilityCheck {
+		for _, ddl := range ddls {
+			if needCheckSchemaCompatibility(ddl) {
+				m.checkSchemaCompatibility(ctx, ddl.TableInfo)
+			}
+		}
+	}
+	return nil
+}
+
+// buildDDLBatch joins the DDLs into a multi-statement SQL, `USE` statements
+// are inserted when the schema of the DDLs changes.
+func buildDDLBatch(ddls []*model.DDLEvent) string {
+	var builder strings.Builder
+	currentSchema := ""
+	for _, ddl := range ddls {
+		if needSwitchDB(ddl) && ddl.TableInfo.TableName.Schema != currentSchema {
+			currentSchema = ddl.TableInfo.TableName.Schema
+			builder.WriteString("USE ")
+			builder.WriteString(quotes.QuoteName(currentSchema))
+			builder.WriteString(";")
+		}
+		query := strings.TrimSpace(ddl.Query)
+		builder.WriteString(query)
+		if !strings.HasSuffix(query, ";") {
+			builder.WriteString(";")
+		}
+	}
+	return builder.String()
+}
+
+func (m *DDLSink) execDDLBatch(pctx context.Context, ddls []*model.DDLEvent) error {
+	writeTimeout, _ := time.ParseDuration(m.cfg.WriteTimeout)
+	writeTimeout += networkDriftDuration
+	ctx, cancelFunc := context.WithTimeout(pctx, writeTimeout)
+	defer cancelFunc()
+
+	query := buildDDLBatch(ddls)
+	start := time.Now()
+	log.Info("Start exec DDLs in a batch",
+		zap.Int("count", len(ddls)), zap.Int("size", len(query)),
+		zap.Uint64("commitTs", ddls[0].CommitTs),
+		zap.String("namespace", m.id.Namespace), zap.String("changefeed", m.id.ID))
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// we try to set cdc write source for the ddl
+	if err = pmysql.SetWriteSource(pctx, m.cfg, tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID), zap.Error(err))
+		}
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID), zap.Error(err))
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+
+	log.Info("Exec DDLs in a batch succeeded",
+		zap.Int("count", len(ddls)),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchDDLTestSink(t *testing.T) (*DDLSink, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID("test-batch-ddl")
+	return &DDLSink{
+		id: changefeedID,
+		db: db,
+		cfg: &pmysql.Config{
+			BatchDDLMode: true,
+			IsTiDB:       true,
+			WriteTimeout: "2m",
+		},
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}, mock
+}
+
+func newCreateTableDDL(schema, table string) *model.DDLEvent {
+	return &model.DDLEvent{
+		StartTs:   1000,
+		CommitTs:  1010,
+		Type:      timodel.ActionCreateTable,
+		Query:     fmt.Sprintf("CREATE TABLE `%s` (`id` INT PRIMARY KEY)", table),
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: schema, Table: table}},
+	}
+}
+
+func TestBuildDDLBatch(t *testing.T) {
+	t.Parallel()
+
+	ddls := []*model.DDLEvent{
+		newCreateTableDDL("test1", "t1"),
+		newCreateTableDDL("test1", "t2"),
+		newCreateTableDDL("test2", "t3"),
+	}
+	ddls[1].Query += ";"
+	require.Equal(t, "USE `test1`;CREATE TABLE `t1` (`id` INT PRIMARY KEY);"+
+		"CREATE TABLE `t2` (`id` INT PRIMARY KEY);"+
+		"USE `test2`;CREATE TABLE `t3` (`id` INT PRIMARY KEY);", buildDDLBatch(ddls))
+}
+
+func TestNextDDLBatch(t *testing.T) {
+	t.Parallel()
+
+	ddlSink, _ := newBatchDDLTestSink(t)
+	ddls := make([]*model.DDLEvent, 0, 150)
+	for i := 0; i < 150; i++ {
+		ddls = append(ddls, newCreateTableDDL("test", fmt.Sprintf("t%d", i)))
+	}
+	require.Equal(t, maxDDLBatchCount, ddlSink.nextDDLBatch(ddls))
+	require.Equal(t, 50, ddlSink.nextDDLBatch(ddls[100:]))
+
+	// The batch is cut by the size of the SQL.
+	ddls[1].Query = "CREATE TABLE `t1` (`id` INT PRIMARY KEY) COMMENT '" +
+		strings.Repeat("x", maxDDLBatchSize) + "'"
+	require.Equal(t, 1, ddlSink.nextDDLBatch(ddls))
+	require.Equal(t, 1, ddlSink.nextDDLBatch(ddls[1:]))
+	ddls[1] = newCreateTableDDL("test", "t1")
+
+	// The batch is cut by the DDLs which can't be batched.
+	ddls[3].Type = timodel.ActionAddColumn
+	require.Equal(t, 2, ddlSink.nextDDLBatch(ddls[1:]))
+	require.Equal(t, 0, ddlSink.nextDDLBatch(ddls[3:]))
+}
+
+func TestWriteDDLEventsInBatch(t *testing.T) {
+	ddlSink, mock := newBatchDDLTestSink(t)
+	ddls := make([]*model.DDLEvent, 0, 200)
+	for i := 0; i < 200; i++ {
+		ddls = append(ddls, newCreateTableDDL("test", fmt.Sprintf("t%d", i)))
+	}
+
+	// 200 tables are created by 2 round-trips.
+	mock.ExpectBegin()
+	mock.ExpectExec(buildDDLBatch(ddls[:100])).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(buildDDLBatch(ddls[100:])).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	require.NoError(t, ddlSink.WriteDDLEvents(context.Background(), ddls))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteDDLEventsFallback(t *testing.T) {
+	ddlSink, mock := newBatchDDLTestSink(t)
+	ddls := []*model.DDLEvent{
+		newCreateTableDDL("test", "t1"),
+		newCreateTableDDL("test", "t2"),
+	}
+
+	// The table t1 was created before the batch fails, the DDLs are executed
+	// one by one and the error is ignored.
+	tableExistsErr := &dmysql.MySQLError{Number: uint16(infoschema.ErrTableExists.Code())}
+	mock.ExpectBegin()
+	mock.ExpectExec(buildDDLBatch(ddls)).WillReturnError(tableExistsErr)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(ddls[0].Query).WillReturnError(tableExistsErr)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(ddls[1].Query).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	require.NoError(t, ddlSink.WriteDDLEvents(context.Background(), ddls))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteDDLEventsWithoutBatchDDLMode(t *testing.T) {
+	ddlSink, mock := newBatchDDLTestSink(t)
+	ddlSink.cfg.BatchDDLMode = false
+	ddls := []*model.DDLEvent{
+		newCreateTableDDL("test", "t1"),
+		newCreateTableDDL("test", "t2"),
+	}
+
+	for _, ddl := range ddls {
+		mock.ExpectBegin()
+		mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(ddl.Query).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+	mock.ExpectClose()
+
+	require.NoError(t, ddlSink.WriteDDLEvents(context.Background(), ddls))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
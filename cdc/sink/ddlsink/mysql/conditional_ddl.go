@@ -0,0 +1,246 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	tableExistsQuery = "SELECT 1 FROM information_schema.TABLES " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	columnExistsQuery = "SELECT 1 FROM information_schema.COLUMNS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?"
+	indexExistsQuery = "SELECT 1 FROM information_schema.STATISTICS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME = ? LIMIT 1"
+)
+
+// formatConditionalDDL makes the DDL idempotent, so that it can be executed
+// again after it has been applied to the downstream, e.g. by a changefeed
+// which restarts from an older checkpoint. `IF [NOT] EXISTS` is added to
+// `CREATE/DROP DATABASE` and `CREATE/DROP TABLE`. MySQL doesn't support it
+// for indexes and columns, so the `CREATE/DROP INDEX` DDLs and the
+// `ADD/DROP COLUMN` and `ADD/DROP INDEX` clauses of `ALTER TABLE` which have
+// been applied are removed by checking INFORMATION_SCHEMA, the same is done
+// for `RENAME TABLE`. The DDL is only checked by its name, e.g. an existing
+// column is regarded as added even if its definition is different.
+// It returns an empty query if the whole DDL has been applied.
+func (m *DDLSink) formatConditionalDDL(ctx context.Context, ddl *model.DDLEvent) (string, error) {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt(ddl.Query, "", "")
+	if err != nil {
+		log.Warn("Failed to parse the DDL, execute it without condition",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("ddl", ddl.Query), zap.Error(err))
+		return ddl.Query, nil
+	}
+	defaultSchema := ""
+	if ddl.TableInfo != nil {
+		defaultSchema = ddl.TableInfo.TableName.Schema
+	}
+
+	applied := false
+	switch s := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		s.IfNotExists = true
+	case *ast.DropDatabaseStmt:
+		s.IfExists = true
+	case *ast.CreateTableStmt:
+		s.IfNotExists = true
+	case *ast.DropTableStmt:
+		s.IfExists = true
+	case *ast.CreateIndexStmt:
+		if m.cfg.IsTiDB {
+			s.IfNotExists = true
+			break
+		}
+		applied, err = m.indexExists(ctx, tableSchema(s.Table, defaultSchema), s.Table.Name.O, s.IndexName)
+	case *ast.DropIndexStmt:
+		if m.cfg.IsTiDB {
+			s.IfExists = true
+			break
+		}
+		var exists bool
+		exists, err = m.indexExists(ctx, tableSchema(s.Table, defaultSchema), s.Table.Name.O, s.IndexName)
+		applied = !exists
+	case *ast.AlterTableStmt:
+		applied, err = m.formatConditionalAlterTable(ctx, s, defaultSchema)
+	case *ast.RenameTableStmt:
+		applied, err = m.formatConditionalRenameTable(ctx, s, defaultSchema)
+	default:
+		return ddl.Query, nil
+	}
+	if err != nil || applied {
+		return "", err
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes |
+		format.RestoreStringWithoutDefaultCharset
+	if err = stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
+
+// formatConditionalAlterTable adds `IF [NOT] EXISTS` to the clauses of the
+// `ALTER TABLE` DDL if the downstream is TiDB, otherwise it removes the
+// clauses which have been applied. It returns true if all clauses have been
+// applied.
+func (m *DDLSink) formatConditionalAlterTable(
+	ctx context.Context, stmt *ast.AlterTableStmt, defaultSchema string,
+) (bool, error) {
+	if m.cfg.IsTiDB {
+		for _, spec := range stmt.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddColumns, ast.AlterTableAddPartitions:
+				spec.IfNotExists = true
+			case ast.AlterTableDropColumn, ast.AlterTableDropIndex,
+				ast.AlterTableDropPartition:
+				spec.IfExists = true
+			case ast.AlterTableAddConstraint:
+				if spec.Constraint.Tp == ast.ConstraintIndex || spec.Constraint.Tp == ast.ConstraintKey {
+					spec.Constraint.IfNotExists = true
+				}
+			}
+		}
+		return false, nil
+	}
+
+	schema, table := tableSchema(stmt.Table, defaultSchema), stmt.Table.Name.O
+	specs := stmt.Specs[:0]
+	for _, spec := range stmt.Specs {
+		applied, err := m.alterTableSpecApplied(ctx, schema, table, spec)
+		if err != nil {
+			return false, err
+		}
+		if !applied {
+			specs = append(specs, spec)
+		}
+	}
+	stmt.Specs = specs
+	return len(specs) == 0, nil
+}
+
+// alterTableSpecApplied returns true if the `ADD/DROP COLUMN` or the
+// `ADD/DROP INDEX` clause has been applied to the table.
+func (m *DDLSink) alterTableSpecApplied(
+	ctx context.Context, schema, table string, spec *ast.AlterTableSpec,
+) (bool, error) {
+	switch spec.Tp {
+	case ast.AlterTableAddColumns:
+		if len(spec.NewColumns) == 0 || len(spec.NewConstraints) != 0 {
+			return false, nil
+		}
+		for _, col := range spec.NewColumns {
+			exists, err := m.columnExists(ctx, schema, table, col.Name.Name.O)
+			if err != nil || !exists {
+				return false, err
+			}
+		}
+		return true, nil
+	case ast.AlterTableDropColumn:
+		exists, err := m.columnExists(ctx, schema, table, spec.OldColumnName.Name.O)
+		return !exists, err
+	case ast.AlterTableAddConstraint:
+		switch spec.Constraint.Tp {
+		case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq,
+			ast.ConstraintUniqKey, ast.ConstraintUniqIndex, ast.ConstraintFulltext:
+		default:
+			return false, nil
+		}
+		// the name of an unnamed index is generated by the downstream.
+		if spec.Constraint.Name == "" {
+			return false, nil
+		}
+		return m.indexExists(ctx, schema, table, spec.Constraint.Name)
+	case ast.AlterTableDropIndex:
+		exists, err := m.indexExists(ctx, schema, table, spec.Name)
+		return !exists, err
+	}
+	return false, nil
+}
+
+// formatConditionalRenameTable removes the tables which have been renamed
+// from the `RENAME TABLE` DDL, a table is regarded as renamed if the old
+// table is gone and the new table exists. It returns true if all tables
+// have been renamed.
+func (m *DDLSink) formatConditionalRenameTable(
+	ctx context.Context, stmt *ast.RenameTableStmt, defaultSchema string,
+) (bool, error) {
+	tables := stmt.TableToTables[:0]
+	for _, t := range stmt.TableToTables {
+		oldExists, err := m.tableExists(ctx, tableSchema(t.OldTable, defaultSchema), t.OldTable.Name.O)
+		if err != nil {
+			return false, err
+		}
+		newExists, err := m.tableExists(ctx, tableSchema(t.NewTable, defaultSchema), t.NewTable.Name.O)
+		if err != nil {
+			return false, err
+		}
+		if oldExists || !newExists {
+			tables = append(tables, t)
+		}
+	}
+	stmt.TableToTables = tables
+	return len(tables) == 0, nil
+}
+
+func tableSchema(table *ast.TableName, defaultSchema string) string {
+	if table.Schema.O != "" {
+		return table.Schema.O
+	}
+	return defaultSchema
+}
+
+func (m *DDLSink) tableExists(ctx context.Context, schema, table string) (bool, error) {
+	return m.existsInInformationSchema(ctx, tableExistsQuery, schema, table)
+}
+
+func (m *DDLSink) columnExists(ctx context.Context, schema, table, column string) (bool, error) {
+	return m.existsInInformationSchema(ctx, columnExistsQuery, schema, table, column)
+}
+
+func (m *DDLSink) indexExists(ctx context.Context, schema, table, index string) (bool, error) {
+	return m.existsInInformationSchema(ctx, indexExistsQuery, schema, table, index)
+}
+
+func (m *DDLSink) existsInInformationSchema(
+	ctx context.Context, query string, args ...interface{},
+) (bool, error) {
+	var exists int
+	err := m.db.QueryRowContext(ctx, query, args...).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func newConditionalDDLTestSink(t *testing.T, isTiDB bool) (*DDLSink, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID("test-conditional-ddl")
+	return &DDLSink{
+		id: changefeedID,
+		db: db,
+		cfg: &pmysql.Config{
+			ConditionalDDL: true,
+			IsTiDB:         isTiDB,
+			WriteTimeout:   "2m",
+		},
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}, mock
+}
+
+func newConditionalDDLEvent(tp timodel.ActionType, query string) *model.DDLEvent {
+	return &model.DDLEvent{
+		StartTs: 1000, CommitTs: 1010, Type: tp, Query: query,
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t"}},
+	}
+}
+
+func expectExists(mock sqlmock.Sqlmock, query string, exists bool, args ...interface{}) {
+	rows := sqlmock.NewRows([]string{"1"})
+	if exists {
+		rows.AddRow(1)
+	}
+	driverArgs := make([]driver.Value, 0, len(args))
+	for _, arg := range args {
+		driverArgs = append(driverArgs, arg)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(driverArgs...).WillReturnRows(rows)
+}
+
+func TestFormatConditionalDDLForTiDB(t *testing.T) {
+	t.Parallel()
+
+	ddlSink, mock := newConditionalDDLTestSink(t, true)
+	cases := []struct {
+		tp       timodel.ActionType
+		query    string
+		expected string
+	}{
+		{
+			timodel.ActionCreateSchema, "CREATE DATABASE test",
+			"CREATE DATABASE IF NOT EXISTS `test`",
+		},
+		{
+			timodel.ActionDropSchema, "DROP DATABASE test",
+			"DROP DATABASE IF EXISTS `test`",
+		},
+		{
+			timodel.ActionCreateTable, "CREATE TABLE t (id INT PRIMARY KEY)",
+			"CREATE TABLE IF NOT EXISTS `t` (`id` INT PRIMARY KEY)",
+		},
+		{
+			timodel.ActionDropTable, "DROP TABLE t",
+			"DROP TABLE IF EXISTS `t`",
+		},
+		{
+			timodel.ActionDropView, "DROP VIEW v",
+			"DROP VIEW IF EXISTS `v`",
+		},
+		{
+			timodel.ActionAddIndex, "CREATE INDEX idx ON t (c)",
+			"CREATE INDEX IF NOT EXISTS `idx` ON `t` (`c`)",
+		},
+		{
+			timodel.ActionDropIndex, "DROP INDEX idx ON t",
+			"DROP INDEX /*T! IF EXISTS  */`idx` ON `t`",
+		},
+		{
+			timodel.ActionAddColumn, "ALTER TABLE t ADD COLUMN c INT",
+			"ALTER TABLE `t` ADD COLUMN IF NOT EXISTS `c` INT",
+		},
+		{
+			timodel.ActionDropColumn, "ALTER TABLE t DROP COLUMN c",
+			"ALTER TABLE `t` DROP COLUMN IF EXISTS `c`",
+		},
+		{
+			timodel.ActionAddIndex, "ALTER TABLE t ADD INDEX idx (c)",
+			"ALTER TABLE `t` ADD INDEX IF NOT EXISTS `idx`(`c`)",
+		},
+		{
+			timodel.ActionDropIndex, "ALTER TABLE t DROP INDEX idx",
+			"ALTER TABLE `t` DROP INDEX IF EXISTS `idx`",
+		},
+		{
+			timodel.ActionDropTablePartition, "ALTER TABLE t DROP PARTITION p0",
+			"ALTER TABLE `t` DROP PARTITION IF EXISTS `p0`",
+		},
+		// the DDLs which are idempotent or can't be checked are not changed.
+		{
+			timodel.ActionTruncateTable, "TRUNCATE TABLE t",
+			"TRUNCATE TABLE t",
+		},
+		{
+			timodel.ActionModifyColumn, "ALTER TABLE t MODIFY COLUMN c BIGINT",
+			"ALTER TABLE `t` MODIFY COLUMN `c` BIGINT",
+		},
+	}
+	for _, c := range cases {
+		query, err := ddlSink.formatConditionalDDL(context.Background(), newConditionalDDLEvent(c.tp, c.query))
+		require.NoError(t, err)
+		require.Equal(t, c.expected, query, c.query)
+	}
+	mock.ExpectClose()
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFormatConditionalDDLForMySQL(t *testing.T) {
+	t.Parallel()
+
+	ddlSink, mock := newConditionalDDLTestSink(t, false)
+	ctx := context.Background()
+	format := func(tp timodel.ActionType, query string) string {
+		result, err := ddlSink.formatConditionalDDL(ctx, newConditionalDDLEvent(tp, query))
+		require.NoError(t, err)
+		return result
+	}
+
+	// MySQL supports IF [NOT] EXISTS for databases and tables.
+	require.Equal(t, "CREATE DATABASE IF NOT EXISTS `test`",
+		format(timodel.ActionCreateSchema, "CREATE DATABASE test"))
+	require.Equal(t, "DROP TABLE IF EXISTS `test`.`t`",
+		format(timodel.ActionDropTable, "DROP TABLE test.t"))
+
+	// CREATE INDEX
+	expectExists(mock, indexExistsQuery, true, "test", "t", "idx")
+	require.Empty(t, format(timodel.ActionAddIndex, "CREATE INDEX idx ON t (c)"))
+	expectExists(mock, indexExistsQuery, false, "test", "t", "idx")
+	require.Equal(t, "CREATE INDEX `idx` ON `t` (`c`)",
+		format(timodel.ActionAddIndex, "CREATE INDEX idx ON t (c)"))
+
+	// DROP INDEX
+	expectExists(mock, indexExistsQuery, false, "test1", "t", "idx")
+	require.Empty(t, format(timodel.ActionDropIndex, "DROP INDEX idx ON test1.t"))
+
+	// ADD COLUMN
+	expectExists(mock, columnExistsQuery, true, "test", "t", "c1")
+	expectExists(mock, columnExistsQuery, true, "test", "t", "c2")
+	require.Empty(t, format(timodel.ActionAddColumns, "ALTER TABLE t ADD COLUMN (c1 INT, c2 INT)"))
+	expectExists(mock, columnExistsQuery, true, "test", "t", "c1")
+	expectExists(mock, columnExistsQuery, false, "test", "t", "c2")
+	require.Equal(t, "ALTER TABLE `t` ADD COLUMN (`c1` INT, `c2` INT)",
+		format(timodel.ActionAddColumns, "ALTER TABLE t ADD COLUMN (c1 INT, c2 INT)"))
+
+	// DROP COLUMN
+	expectExists(mock, columnExistsQuery, false, "test", "t", "c")
+	require.Empty(t, format(timodel.ActionDropColumn, "ALTER TABLE t DROP COLUMN c"))
+
+	// ADD INDEX, the clauses which have been applied are removed.
+	expectExists(mock, columnExistsQuery, true, "test", "t", "c")
+	expectExists(mock, indexExistsQuery, false, "test", "t", "idx")
+	require.Equal(t, "ALTER TABLE `t` ADD INDEX `idx`(`c`)",
+		format(timodel.ActionMultiSchemaChange, "ALTER TABLE t ADD COLUMN c INT, ADD INDEX idx (c)"))
+	// The name of an unnamed index is unknown.
+	require.Equal(t, "ALTER TABLE `t` ADD UNIQUE(`c`)",
+		format(timodel.ActionAddIndex, "ALTER TABLE t ADD UNIQUE (c)"))
+
+	// DROP INDEX
+	expectExists(mock, indexExistsQuery, true, "test", "t", "idx")
+	require.Equal(t, "ALTER TABLE `t` DROP INDEX `idx`",
+		format(timodel.ActionDropIndex, "ALTER TABLE t DROP INDEX idx"))
+
+	// RENAME TABLE
+	expectExists(mock, tableExistsQuery, false, "test", "t1")
+	expectExists(mock, tableExistsQuery, true, "test", "t2")
+	require.Empty(t, format(timodel.ActionRenameTable, "RENAME TABLE t1 TO t2"))
+	expectExists(mock, tableExistsQuery, false, "test", "t1")
+	expectExists(mock, tableExistsQuery, true, "test", "t2")
+	expectExists(mock, tableExistsQuery, true, "test", "t3")
+	expectExists(mock, tableExistsQuery, false, "test", "t4")
+	require.Equal(t, "RENAME TABLE `t3` TO `t4`",
+		format(timodel.ActionRenameTables, "RENAME TABLE t1 TO t2, t3 TO t4"))
+
+	mock.ExpectClose()
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteConditionalDDL(t *testing.T) {
+	t.Parallel()
+
+	ddlSink, mock := newConditionalDDLTestSink(t, false)
+	ctx := context.Background()
+
+	// The column has been added to the downstream, skip the DDL.
+	expectExists(mock, columnExistsQuery, true, "test", "t", "c")
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx,
+		newConditionalDDLEvent(timodel.ActionAddColumn, "ALTER TABLE t ADD COLUMN c INT")))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS `t` (`id` INT PRIMARY KEY)")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx,
+		newConditionalDDLEvent(timodel.ActionCreateTable, "CREATE TABLE t (id INT PRIMARY KEY)")))
+
+	mock.ExpectClose()
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+var createSkippedDDLTableSQL = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s
+(
+	changefeed_id varchar(255),
+	start_ts bigint unsigned,
+	commit_ts bigint unsigned,
+	ddl_type varchar(64),
+	ddl_query text,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX (created_at),
+	PRIMARY KEY (changefeed_id, start_ts)
+)`, filter.TiCDCSystemSchema, filter.SkippedDDLTable)
+
+var vectorIndexRegexp = regexp.MustCompile(`(?i)\bVECTOR\s+(INDEX|KEY)\b`)
+
+// unsupportedDDLType returns the type of the DDL if it can't be executed by a
+// MySQL downstream, such as `CREATE SEQUENCE` and `CREATE RESOURCE GROUP`.
+func unsupportedDDLType(ddl *model.DDLEvent) (string, bool) {
+	switch ddl.Type {
+	case timodel.ActionCreateSequence, timodel.ActionAlterSequence,
+		timodel.ActionDropSequence, timodel.ActionCreateResourceGroup,
+		timodel.ActionAlterResourceGroup, timodel.ActionDropResourceGroup:
+		return ddl.Type.String(), true
+	case timodel.ActionAddIndex:
+		if vectorIndexRegexp.MatchString(ddl.Query) {
+			return "add vector index", true
+		}
+	}
+	return "", false
+}
+
+// isDDLRejectedByDownstream returns true if the downstream fails to execute
+// the DDL since it doesn't support the syntax.
+func isDDLRejectedByDownstream(err error) bool {
+	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
+	if !ok {
+		return false
+	}
+	switch mysqlErr.Number {
+	case mysql.ErrParse, mysql.ErrSyntax, mysql.ErrNotSupportedYet:
+		return true
+	}
+	return false
+}
+
+// writeUnsupportedDDL handles the DDL not supported by the downstream by the
+// ddl-fallback-policy, false is returned if the DDL should be executed as usual.
+func (m *DDLSink) writeUnsupportedDDL(
+	ctx context.Context, ddl *model.DDLEvent, ddlType string,
+) (bool, error) {
+	switch m.cfg.DDLFallbackPolicy {
+	case pmysql.DDLFallbackPolicySkip:
+		return true, m.skipDDL(ctx, ddl, ddlType)
+	case pmysql.DDLFallbackPolicyEmulate:
+		// The DDL is executed as is, some MySQL compatible databases support
+		// part of the syntax, e.g. MariaDB supports sequences.
+		err := m.execDDLWithMaxRetries(ctx, ddl)
+		if err != nil && isDDLRejectedByDownstream(err) {
+			log.Warn("DDL is rejected by the downstream, skip it",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.String("ddl", ddl.Query), zap.Error(err))
+			return true, m.skipDDL(ctx, ddl, ddlType)
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// skipDDL skips the DDL and records it in the downstream for review.
+func (m *DDLSink) skipDDL(ctx context.Context, ddl *model.DDLEvent, ddlType string) error {
+	log.Warn("Skip the DDL since it's not supported by the downstream",
+		zap.Uint64("startTs", ddl.StartTs), zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("ddl", ddl.Query), zap.String("ddlType", ddlType),
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	if !m.skippedDDLTableCreated {
+		if _, err := m.db.ExecContext(ctx,
+			"CREATE DATABASE IF NOT EXISTS "+filter.TiCDCSystemSchema); err != nil {
+			return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+		}
+		if _, err := m.db.ExecContext(ctx, createSkippedDDLTableSQL); err != nil {
+			return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+		}
+		m.skippedDDLTableCreated = true
+	}
+	query := "REPLACE INTO " + filter.TiCDCSystemSchema + "." + filter.SkippedDDLTable +
+		"(changefeed_id, start_ts, commit_ts, ddl_type, ddl_query) VALUES (?,?,?,?,?)"
+	if _, err := m.db.ExecContext(ctx, query, m.id.String(),
+		ddl.StartTs, ddl.CommitTs, ddlType, ddl.Query); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	metrics.DDLSkippedCounter.WithLabelValues(m.id.Namespace, m.id.ID, ddlType).Inc()
+	return nil
+}
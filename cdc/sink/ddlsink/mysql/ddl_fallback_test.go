@@ -0,0 +1,159 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var unsupportedDDLs = []struct {
+	ddl     *model.DDLEvent
+	ddlType string
+}{
+	{
+		ddl: &model.DDLEvent{
+			StartTs: 1000, CommitTs: 1010, Type: timodel.ActionCreateSequence,
+			Query: "CREATE SEQUENCE `test`.`seq` START WITH 1 INCREMENT BY 1",
+		},
+		ddlType: "create sequence",
+	},
+	{
+		ddl: &model.DDLEvent{
+			StartTs: 1020, CommitTs: 1030, Type: timodel.ActionCreateResourceGroup,
+			Query: "CREATE RESOURCE GROUP `rg1` RU_PER_SEC = 1000",
+		},
+		ddlType: "create resource group",
+	},
+	{
+		ddl: &model.DDLEvent{
+			StartTs: 1040, CommitTs: 1050, Type: timodel.ActionAddIndex,
+			Query: "ALTER TABLE `test`.`t` ADD VECTOR INDEX `idx`((VEC_COSINE_DISTANCE(`v`))) USING HNSW",
+		},
+		ddlType: "add vector index",
+	},
+}
+
+func newFallbackTestDDLSink(
+	t *testing.T, changefeed string, policy string,
+) (*DDLSink, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID(changefeed)
+	return &DDLSink{
+		id:         changefeedID,
+		db:         db,
+		cfg:        &pmysql.Config{DDLFallbackPolicy: policy},
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}, mock
+}
+
+func withTableInfo(ddl *model.DDLEvent) *model.DDLEvent {
+	ddl.TableInfo = &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t"},
+	}
+	return ddl
+}
+
+func TestUnsupportedDDLType(t *testing.T) {
+	for _, c := range unsupportedDDLs {
+		ddlType, ok := unsupportedDDLType(c.ddl)
+		require.True(t, ok)
+		require.Equal(t, c.ddlType, ddlType)
+	}
+	_, ok := unsupportedDDLType(&model.DDLEvent{
+		Type:  timodel.ActionAddIndex,
+		Query: "ALTER TABLE `test`.`t` ADD INDEX `vector`(`vector`)",
+	})
+	require.False(t, ok)
+}
+
+func TestSkipUnsupportedDDL(t *testing.T) {
+	ddlSink, mock := newFallbackTestDDLSink(t, "test-skip-ddl", pmysql.DDLFallbackPolicySkip)
+	// The table recording the skipped DDLs is only created once.
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS tidb_cdc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS tidb_cdc._ticdc_skipped_ddls").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, c := range unsupportedDDLs {
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO tidb_cdc._ticdc_skipped_ddls")).
+			WithArgs("default/test-skip-ddl", c.ddl.StartTs, c.ddl.CommitTs, c.ddlType, c.ddl.Query).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectClose()
+
+	ctx := context.Background()
+	for _, c := range unsupportedDDLs {
+		require.NoError(t, ddlSink.WriteDDLEvent(ctx, withTableInfo(c.ddl)))
+		require.Equal(t, float64(1), testutil.ToFloat64(
+			metrics.DDLSkippedCounter.WithLabelValues("default", "test-skip-ddl", c.ddlType)))
+	}
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnsupportedDDLFallbackPolicy(t *testing.T) {
+	ctx := context.Background()
+	ddl := withTableInfo(unsupportedDDLs[0].ddl)
+	parseErr := &dmysql.MySQLError{Number: mysql.ErrParse, Message: "You have an error in your SQL syntax"}
+
+	// The DDL is executed and the error is reported with the error policy.
+	ddlSink, mock := newFallbackTestDDLSink(t, "test-error-ddl", pmysql.DDLFallbackPolicyError)
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(ddl.Query)).WillReturnError(parseErr)
+	mock.ExpectRollback()
+	mock.ExpectClose()
+	require.ErrorIs(t, ddlSink.WriteDDLEvent(ctx, ddl), parseErr)
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The DDL rejected by the downstream is skipped with the emulate policy.
+	ddlSink, mock = newFallbackTestDDLSink(t, "test-emulate-ddl", pmysql.DDLFallbackPolicyEmulate)
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(ddl.Query)).WillReturnError(parseErr)
+	mock.ExpectRollback()
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS tidb_cdc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS tidb_cdc._ticdc_skipped_ddls").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO tidb_cdc._ticdc_skipped_ddls")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The DDL accepted by the downstream is executed as is.
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(ddl.Query)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx, ddl))
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx, ddl))
+	require.Equal(t, float64(1), testutil.ToFloat64(
+		metrics.DDLSkippedCounter.WithLabelValues("default", "test-emulate-ddl", "create sequence")))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"go.uber.org/zap"
+)
+
+// needCheckDefaultValue returns true if the DDL may change the default value
+// of columns. Existing rows are backfilled by the DEFAULT clause of the
+// downstream, so the downstream default values must match the upstream.
+func needCheckDefaultValue(t timodel.ActionType) bool {
+	return t == timodel.ActionAddColumn ||
+		t == timodel.ActionAddColumns ||
+		t == timodel.ActionModifyColumn ||
+		t == timodel.ActionSetDefaultValue
+}
+
+// checkDefaultValues compares the column default values of the downstream
+// table with the upstream after the DDL is executed, a warning is logged for
+// each mismatched column. For `ALTER TABLE ALTER COLUMN SET DEFAULT`, the
+// upstream default value is explicitly applied to the mismatched columns.
+// It's best effort, errors are logged and do not fail the DDL.
+func (m *DDLSink) checkDefaultValues(ctx context.Context, ddl *model.DDLEvent) {
+	if ddl.TableInfo == nil || ddl.TableInfo.TableInfo == nil {
+		return
+	}
+	schema, table := ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table
+	downstream, err := queryDownstreamDefaultValues(ctx, m.db, schema, table)
+	if err != nil {
+		log.Warn("Failed to query downstream column default values",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("schema", schema), zap.String("table", table),
+			zap.Error(err))
+		return
+	}
+
+	var alteredColumns map[string]struct{}
+	if ddl.Type == timodel.ActionSetDefaultValue {
+		alteredColumns = getAlteredColumns(ddl.Query)
+	}
+	for _, col := range ddl.TableInfo.Columns {
+		if col.State != timodel.StatePublic || col.DefaultIsExpr ||
+			col.GetType() == mysql.TypeBit {
+			// Expression and bit default values are formatted differently,
+			// they can not be compared literally.
+			continue
+		}
+		downstreamDefault, ok := downstream[col.Name.L]
+		if !ok {
+			continue
+		}
+		upstreamDefault := formatDefaultValue(col.GetDefaultValue())
+		if equalDefaultValue(upstreamDefault, downstreamDefault) {
+			continue
+		}
+		log.Warn("Column default value of downstream differs from upstream",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("schema", schema), zap.String("table", table),
+			zap.String("column", col.Name.O),
+			zap.Stringp("upstreamDefault", upstreamDefault),
+			zap.Stringp("downstreamDefault", downstreamDefault),
+			zap.String("DDL", ddl.Query))
+		if _, ok := alteredColumns[col.Name.L]; !ok {
+			continue
+		}
+		setDefault := &model.DDLEvent{
+			StartTs:   ddl.StartTs,
+			CommitTs:  ddl.CommitTs,
+			Type:      timodel.ActionSetDefaultValue,
+			TableInfo: ddl.TableInfo,
+			Query:     genSetDefaultValueDDL(schema, table, col.Name.O, upstreamDefault),
+		}
+		if err := m.execDDLWithMaxRetries(ctx, setDefault); err != nil {
+			log.Warn("Failed to apply column default value to downstream",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.String("DDL", setDefault.Query),
+				zap.Error(err))
+		}
+	}
+}
+
+// queryDownstreamDefaultValues returns the default values of all columns of
+// the downstream table, keyed by the lower case column name. The value is nil
+// if the column has no default value.
+func queryDownstreamDefaultValues(
+	ctx context.Context, db *sql.DB, schema, table string,
+) (map[string]*string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT COLUMN_NAME, COLUMN_DEFAULT FROM information_schema.COLUMNS "+
+			"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", schema, table)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]*string)
+	for rows.Next() {
+		var (
+			name         string
+			defaultValue sql.NullString
+		)
+		if err := rows.Scan(&name, &defaultValue); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		if defaultValue.Valid {
+			result[strings.ToLower(name)] = &defaultValue.String
+		} else {
+			result[strings.ToLower(name)] = nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return result, nil
+}
+
+// getAlteredColumns returns the lower case names of columns altered by
+// `ALTER TABLE ALTER COLUMN` in the query.
+func getAlteredColumns(query string) map[string]struct{} {
+	result := make(map[string]struct{})
+	stmt, err := parser.New().ParseOneStmt(query, "", "")
+	if err != nil {
+		log.Warn("Failed to parse DDL", zap.String("DDL", query), zap.Error(err))
+		return result
+	}
+	alterStmt, ok := stmt.(*ast.AlterTableStmt)
+	if !ok {
+		return result
+	}
+	for _, spec := range alterStmt.Specs {
+		if spec.Tp != ast.AlterTableAlterColumn {
+			continue
+		}
+		for _, col := range spec.NewColumns {
+			result[col.Name.Name.L] = struct{}{}
+		}
+	}
+	return result
+}
+
+func formatDefaultValue(v interface{}) *string {
+	if v == nil {
+		return nil
+	}
+	s := fmt.Sprintf("%v", v)
+	return &s
+}
+
+func equalDefaultValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func genSetDefaultValueDDL(schema, table, column string, defaultValue *string) string {
+	ddl := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ",
+		quotes.QuoteSchema(schema, table), quotes.QuoteName(column))
+	if defaultValue == nil {
+		return ddl + "DROP DEFAULT"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(*defaultValue)
+	return ddl + "SET DEFAULT '" + escaped + "'"
+}
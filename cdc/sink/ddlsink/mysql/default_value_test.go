@@ -0,0 +1,153 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+const queryDefaultValuesSQL = "SELECT COLUMN_NAME, COLUMN_DEFAULT FROM information_schema.COLUMNS " +
+	"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+
+func newDefaultValueTestColumn(name string, tp byte, defaultValue interface{}) *timodel.ColumnInfo {
+	col := &timodel.ColumnInfo{
+		Name:      timodel.NewCIStr(name),
+		FieldType: *types.NewFieldType(tp),
+		State:     timodel.StatePublic,
+	}
+	_ = col.SetDefaultValue(defaultValue)
+	return col
+}
+
+func newDefaultValueTestDDL(
+	commitTs uint64, tp timodel.ActionType, query string, cols ...*timodel.ColumnInfo,
+) *model.DDLEvent {
+	return &model.DDLEvent{
+		StartTs:  commitTs - 1,
+		CommitTs: commitTs,
+		Type:     tp,
+		Query:    query,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t1"},
+			TableInfo: &timodel.TableInfo{Name: timodel.NewCIStr("t1"), Columns: cols},
+		},
+	}
+}
+
+func TestDefaultValueConsistency(t *testing.T) {
+	expectExecDDL := func(mock sqlmock.Sqlmock, query string) {
+		mock.ExpectBegin()
+		mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("SET SESSION tidb_cdc_write_source = 0").WillReturnResult(sqlmock.NewResult(1, 0))
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+	dbIndex := 0
+	var mock sqlmock.Sqlmock
+	GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() {
+			dbIndex++
+		}()
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+		// normal db
+		var (
+			db  *sql.DB
+			err error
+		)
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		mock.ExpectQuery("select tidb_version()").
+			WillReturnRows(sqlmock.NewRows([]string{"tidb_version()"}).AddRow("5.7.25-TiDB-v4.0.0-beta-191-ga1b3e3b"))
+		mock.ExpectQuery("select tidb_version()").
+			WillReturnRows(sqlmock.NewRows([]string{"tidb_version()"}).AddRow("5.7.25-TiDB-v4.0.0-beta-191-ga1b3e3b"))
+		mock.ExpectExec("SET SESSION tidb_cdc_write_source = 1").WillReturnResult(sqlmock.NewResult(1, 0))
+
+		// 1. ADD COLUMN with a default value, the downstream is consistent.
+		expectExecDDL(mock, "ALTER TABLE t1 ADD COLUMN c INT DEFAULT 100")
+		mock.ExpectQuery(queryDefaultValuesSQL).WithArgs("test", "t1").
+			WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_DEFAULT"}).
+				AddRow("a", nil).AddRow("c", "100"))
+
+		// 2. SET DEFAULT, the downstream default value differs from the upstream,
+		// the upstream default value is applied explicitly.
+		expectExecDDL(mock, "ALTER TABLE t1 ALTER COLUMN c SET DEFAULT 200")
+		mock.ExpectQuery(queryDefaultValuesSQL).WithArgs("test", "t1").
+			WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_DEFAULT"}).
+				AddRow("a", nil).AddRow("c", "100"))
+		expectExecDDL(mock, "ALTER TABLE `test`.`t1` ALTER COLUMN `c` SET DEFAULT '200'")
+
+		// 3. ADD COLUMN with a mismatched default value only logs a warning.
+		expectExecDDL(mock, "ALTER TABLE t1 ADD COLUMN d VARCHAR(10) DEFAULT 'x'")
+		mock.ExpectQuery(queryDefaultValuesSQL).WithArgs("test", "t1").
+			WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_DEFAULT"}).
+				AddRow("a", nil).AddRow("c", "200").AddRow("d", "y"))
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000")
+	require.Nil(t, err)
+	sink, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test-changefeed"),
+		sinkURI, config.GetDefaultReplicaConfig())
+	require.Nil(t, err)
+
+	colA := newDefaultValueTestColumn("a", mysql.TypeLong, nil)
+	err = sink.WriteDDLEvent(ctx, newDefaultValueTestDDL(
+		1010, timodel.ActionAddColumn, "ALTER TABLE t1 ADD COLUMN c INT DEFAULT 100",
+		colA, newDefaultValueTestColumn("c", mysql.TypeLong, "100")))
+	require.Nil(t, err)
+	err = sink.WriteDDLEvent(ctx, newDefaultValueTestDDL(
+		1020, timodel.ActionSetDefaultValue, "ALTER TABLE t1 ALTER COLUMN c SET DEFAULT 200",
+		colA, newDefaultValueTestColumn("c", mysql.TypeLong, "200")))
+	require.Nil(t, err)
+	err = sink.WriteDDLEvent(ctx, newDefaultValueTestDDL(
+		1030, timodel.ActionAddColumn, "ALTER TABLE t1 ADD COLUMN d VARCHAR(10) DEFAULT 'x'",
+		colA, newDefaultValueTestColumn("c", mysql.TypeLong, "200"),
+		newDefaultValueTestColumn("d", mysql.TypeVarchar, "x")))
+	require.Nil(t, err)
+
+	sink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGenSetDefaultValueDDL(t *testing.T) {
+	t.Parallel()
+
+	v := `it's \ok`
+	require.Equal(t, "ALTER TABLE `test`.`t1` ALTER COLUMN `c` SET DEFAULT 'it\\'s \\\\ok'",
+		genSetDefaultValueDDL("test", "t1", "c", &v))
+	require.Equal(t, "ALTER TABLE `test`.`t1` ALTER COLUMN `c` DROP DEFAULT",
+		genSetDefaultValueDDL("test", "t1", "c", nil))
+	require.Equal(t, map[string]struct{}{"c": {}, "d": {}},
+		getAlteredColumns("ALTER TABLE t1 ALTER COLUMN C SET DEFAULT 1, ALTER COLUMN d DROP DEFAULT"))
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// hasMultiValuedIndex returns true if the DDL creates a multi-valued index,
+// such as `CREATE INDEX idx ON t ((CAST(j->'$.tags' AS CHAR(10) ARRAY)))`.
+func hasMultiValuedIndex(ddl *model.DDLEvent) bool {
+	switch ddl.Type {
+	case timodel.ActionAddIndex, timodel.ActionCreateTable:
+	default:
+		return false
+	}
+	if ddl.TableInfo == nil || ddl.TableInfo.TableInfo == nil {
+		return false
+	}
+	for _, idx := range ddl.TableInfo.Indices {
+		if idx.MVIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// arrayCastVisitor removes the TiDB specific charset of the
+// `CAST(... AS ... ARRAY)` expressions in the key parts.
+type arrayCastVisitor struct{}
+
+func (v *arrayCastVisitor) Enter(n ast.Node) (ast.Node, bool) {
+	if cast, ok := n.(*ast.FuncCastExpr); ok && cast.Tp != nil && cast.Tp.IsArray() {
+		// MySQL only accepts the default collation `utf8mb4_0900_as_cs`
+		// for `CHAR(N) ARRAY`, so the charset must not be written explicitly.
+		cast.ExplicitCharSet = false
+	}
+	return n, false
+}
+
+func (v *arrayCastVisitor) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// formatMultiValuedIndexDDL translates a DDL with multi-valued index from
+// TiDB syntax to MySQL 8.0 syntax, such as
+// `CREATE INDEX idx ON t ((CAST(JSON_EXTRACT(j, '$.tags') AS CHAR(10) ARRAY)))`.
+// The TiDB specific features of the DDL are restored as special comments.
+func formatMultiValuedIndexDDL(query string) (string, error) {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt(query, "", "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	stmt.Accept(&arrayCastVisitor{})
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes |
+		format.RestoreStringWithoutDefaultCharset
+	if err = stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
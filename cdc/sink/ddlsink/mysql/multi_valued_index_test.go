@@ -0,0 +1,76 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasMultiValuedIndex(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{
+		Indices: []*timodel.IndexInfo{{Name: timodel.NewCIStr("idx"), MVIndex: true}},
+	}}
+	require.True(t, hasMultiValuedIndex(&model.DDLEvent{
+		Type: timodel.ActionAddIndex, TableInfo: tableInfo,
+	}))
+	require.True(t, hasMultiValuedIndex(&model.DDLEvent{
+		Type: timodel.ActionCreateTable, TableInfo: tableInfo,
+	}))
+	require.False(t, hasMultiValuedIndex(&model.DDLEvent{
+		Type: timodel.ActionDropIndex, TableInfo: tableInfo,
+	}))
+	require.False(t, hasMultiValuedIndex(&model.DDLEvent{
+		Type: timodel.ActionAddIndex,
+		TableInfo: &model.TableInfo{TableInfo: &timodel.TableInfo{
+			Indices: []*timodel.IndexInfo{{Name: timodel.NewCIStr("idx")}},
+		}},
+	}))
+	require.False(t, hasMultiValuedIndex(&model.DDLEvent{Type: timodel.ActionAddIndex}))
+}
+
+func TestFormatMultiValuedIndexDDL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "CREATE INDEX idx ON t ((CAST(j->'$.tags' AS CHAR(10) ARRAY)))",
+			expected: "CREATE INDEX `idx` ON `t` ((CAST(JSON_EXTRACT(`j`, '$.tags') AS CHAR(10) ARRAY)))",
+		},
+		{
+			query:    "alter table t add index idx((cast(j->'$.zips' as unsigned array)))",
+			expected: "ALTER TABLE `t` ADD INDEX `idx`((CAST(JSON_EXTRACT(`j`, '$.zips') AS UNSIGNED ARRAY)))",
+		},
+		{
+			query:    "CREATE TABLE t (id INT PRIMARY KEY CLUSTERED, j JSON, INDEX idx ((CAST(j->'$.tags' AS CHAR(10) CHARSET utf8mb4 ARRAY))))",
+			expected: "CREATE TABLE `t` (`id` INT PRIMARY KEY /*T![clustered_index] CLUSTERED */,`j` JSON,INDEX `idx`((CAST(JSON_EXTRACT(`j`, '$.tags') AS CHAR(10) ARRAY))))",
+		},
+	}
+	for _, tc := range testCases {
+		actual, err := formatMultiValuedIndexDDL(tc.query)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, actual, tc.query)
+	}
+
+	_, err := formatMultiValuedIndexDDL("CREATE INDEX idx ON")
+	require.Error(t, err)
+}
@@ -60,6 +60,16 @@ type DDLSink struct {
 	// statistics is the statistics of this sink.
 	// We use it to record the DDL count.
 	statistics *metrics.Statistics
+	// schemaChecked indicates whether the schema compatibility of all tables
+	// has been checked after the sink is started.
+	schemaChecked bool
+	// partitioningChecked indicates whether partitioningSupported has been
+	// fetched from the downstream.
+	partitioningChecked   bool
+	partitioningSupported bool
+	// skippedDDLTableCreated indicates whether the table recording the
+	// skipped DDLs has been created in the downstream.
+	skippedDDLTableCreated bool
 }
 
 // NewDDLSink creates a new DDLSink.
@@ -110,10 +120,97 @@ func NewDDLSink(
 
 // WriteDDLEvent writes a DDL event to the mysql database.
 func (m *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if isTiDBOnlyDDL(ddl.Type) && !m.cfg.IsTiDB {
+		// MySQL doesn't support these DDLs, skip them so that
+		// the checkpoint of the changefeed can still be advanced.
+		log.Warn("Skip TiDB specific DDL since the downstream is not TiDB",
+			zap.Uint64("startTs", ddl.StartTs), zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("ddl", ddl.Query),
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID))
+		return nil
+	}
+	if m.cfg.PtOSCCompatibility {
+		if err := m.checkPtOSCConflict(ctx, ddl); err != nil {
+			return err
+		}
+	}
+	if !m.cfg.IsTiDB {
+		if ddlType, ok := unsupportedDDLType(ddl); ok {
+			if handled, err := m.writeUnsupportedDDL(ctx, ddl, ddlType); handled {
+				return err
+			}
+		}
+	}
+	if !m.cfg.IsTiDB && hasMultiValuedIndex(ddl) {
+		query, err := formatMultiValuedIndexDDL(ddl.Query)
+		if err != nil {
+			return err
+		}
+		log.Info("Translate multi-valued index DDL for MySQL",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("ddl", ddl.Query), zap.String("result", query))
+		ddl = withQuery(ddl, query)
+	}
+	if !m.cfg.IsTiDB && isPartitionDDL(ddl) {
+		m.warnIfPartitioningNotSupported(ctx, ddl)
+		if m.cfg.PartitionDDLCompatibility {
+			if query, ok := formatPartitionDDL(ddl); ok {
+				log.Info("Translate partition DDL for MySQL",
+					zap.String("namespace", m.id.Namespace),
+					zap.String("changefeed", m.id.ID),
+					zap.String("ddl", ddl.Query), zap.String("result", query))
+				ddl = withQuery(ddl, query)
+			}
+		}
+	}
+	if m.cfg.ConditionalDDL {
+		query, err := m.formatConditionalDDL(ctx, ddl)
+		if err != nil {
+			return err
+		}
+		if query == "" {
+			log.Info("Skip DDL which has been applied to the downstream",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Uint64("startTs", ddl.StartTs), zap.Uint64("commitTs", ddl.CommitTs),
+				zap.String("ddl", ddl.Query))
+			return nil
+		}
+		ddl = withQuery(ddl, query)
+	}
 	if ddl.Type == timodel.ActionAddIndex && m.cfg.IsTiDB {
 		return m.asyncExecAddIndexDDLIfTimeout(ctx, ddl)
 	}
-	return m.execDDLWithMaxRetries(ctx, ddl)
+	if err := m.execDDLWithMaxRetries(ctx, ddl); err != nil {
+		return err
+	}
+	if needCheckDefaultValue(ddl.Type) {
+		m.checkDefaultValues(ctx, ddl)
+	}
+	if m.cfg.SchemaCompatibilityCheck && needCheckSchemaCompatibility(ddl) {
+		m.checkSchemaCompatibility(ctx, ddl.TableInfo)
+	}
+	return nil
+}
+
+// withQuery returns a copy of the DDL event with the given query, the
+// original event is not modified since it's still used by the owner.
+func withQuery(ddl *model.DDLEvent, query string) *model.DDLEvent {
+	return &model.DDLEvent{
+		StartTs:      ddl.StartTs,
+		CommitTs:     ddl.CommitTs,
+		Query:        query,
+		TableInfo:    ddl.TableInfo,
+		PreTableInfo: ddl.PreTableInfo,
+		Type:         ddl.Type,
+		Charset:      ddl.Charset,
+		Collate:      ddl.Collate,
+		IsBootstrap:  ddl.IsBootstrap,
+		BDRRole:      ddl.BDRRole,
+		SQLMode:      ddl.SQLMode,
+	}
 }
 
 func (m *DDLSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEvent) error {
@@ -144,6 +241,13 @@ func (m *DDLSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEvent
 		retry.WithIsRetryableErr(errorutil.IsRetryableDDLError))
 }
 
+// isTiDBOnlyDDL returns true if given ddl type can only be executed by TiDB,
+// such as `FLASHBACK TABLE`, `RECOVER TABLE` and `FLASHBACK CLUSTER`.
+func isTiDBOnlyDDL(t timodel.ActionType) bool {
+	return t == timodel.ActionRecoverTable ||
+		t == timodel.ActionFlashbackCluster
+}
+
 // isReorgOrPartitionDDL returns true if given ddl type is reorg ddl or
 // partition ddl.
 func isReorgOrPartitionDDL(t timodel.ActionType) bool {
@@ -253,9 +357,18 @@ func needSwitchDB(ddl *model.DDLEvent) bool {
 	return true
 }
 
-// WriteCheckpointTs does nothing.
-func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
-	// Only for RowSink for now.
+// WriteCheckpointTs does nothing except checking the schema compatibility of
+// all tables when it's called for the first time.
+func (m *DDLSink) WriteCheckpointTs(ctx context.Context, _ uint64, tables []*model.TableInfo) error {
+	if m.cfg.SchemaCompatibilityCheck && !m.schemaChecked {
+		for _, table := range tables {
+			if table.TableInfo == nil || table.IsView() {
+				continue
+			}
+			m.checkSchemaCompatibility(ctx, table)
+		}
+		m.schemaChecked = true
+	}
 	return nil
 }
 
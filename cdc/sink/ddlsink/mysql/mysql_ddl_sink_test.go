@@ -100,6 +100,78 @@ func TestWriteDDLEvent(t *testing.T) {
 	sink.Close()
 }
 
+func TestWriteTiDBOnlyDDLEvent(t *testing.T) {
+	ddl := &model.DDLEvent{
+		StartTs:  1000,
+		CommitTs: 1010,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{
+				Schema: "test",
+				Table:  "t1",
+			},
+		},
+		Type:  timodel.ActionRecoverTable,
+		Query: "FLASHBACK TABLE test.t1",
+	}
+
+	for _, isTiDB := range []bool{false, true} {
+		var mock sqlmock.Sqlmock
+		dbIndex := 0
+		GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			defer func() {
+				dbIndex++
+			}()
+			if dbIndex == 0 {
+				// test db
+				db, err := pmysql.MockTestDB()
+				require.Nil(t, err)
+				return db, nil
+			}
+			// normal db
+			var db *sql.DB
+			var err error
+			db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.Nil(t, err)
+			if !isTiDB {
+				for i := 0; i < 2; i++ {
+					mock.ExpectQuery("select tidb_version()").WillReturnError(&dmysql.MySQLError{
+						Number:  1305,
+						Message: "FUNCTION test.tidb_version does not exist",
+					})
+				}
+				// The DDL is skipped, nothing is executed in the downstream.
+				mock.ExpectClose()
+				return db, nil
+			}
+			mock.ExpectQuery("select tidb_version()").
+				WillReturnRows(sqlmock.NewRows([]string{"tidb_version()"}).AddRow("5.7.25-TiDB-v6.4.0"))
+			mock.ExpectQuery("select tidb_version()").
+				WillReturnRows(sqlmock.NewRows([]string{"tidb_version()"}).AddRow("5.7.25-TiDB-v6.4.0"))
+			mock.ExpectExec("SET SESSION tidb_cdc_write_source = 1").WillReturnResult(sqlmock.NewResult(1, 0))
+			mock.ExpectBegin()
+			mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("SET SESSION tidb_cdc_write_source = 0").WillReturnResult(sqlmock.NewResult(1, 0))
+			mock.ExpectExec("FLASHBACK TABLE test.t1").WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			mock.ExpectClose()
+			return db, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sinkURI, err := url.Parse("mysql://127.0.0.1:4000")
+		require.Nil(t, err)
+		rc := config.GetDefaultReplicaConfig()
+		sink, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI, rc)
+		require.Nil(t, err)
+		require.Equal(t, isTiDB, sink.cfg.IsTiDB)
+
+		require.Nil(t, sink.WriteDDLEvent(ctx, ddl))
+		sink.Close()
+		require.Nil(t, mock.ExpectationsWereMet())
+		cancel()
+	}
+}
+
 func TestNeedSwitchDB(t *testing.T) {
 	t.Parallel()
 
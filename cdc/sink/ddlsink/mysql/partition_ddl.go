@@ -0,0 +1,167 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+// isPartitionDDL returns true if the DDL creates a partitioned table or
+// changes the partitions of a table.
+func isPartitionDDL(ddl *model.DDLEvent) bool {
+	switch ddl.Type {
+	case timodel.ActionAddTablePartition, timodel.ActionDropTablePartition,
+		timodel.ActionTruncateTablePartition, timodel.ActionReorganizePartition,
+		timodel.ActionExchangeTablePartition, timodel.ActionAlterTablePartitioning,
+		timodel.ActionRemovePartitioning:
+		return true
+	case timodel.ActionCreateTable:
+		return ddl.TableInfo != nil && ddl.TableInfo.TableInfo != nil &&
+			ddl.TableInfo.GetPartitionInfo() != nil
+	}
+	return false
+}
+
+// warnIfPartitioningNotSupported logs a warning if the downstream doesn't
+// support partitioned tables, the DDL is still executed and the error is
+// reported by the downstream. It's best effort, errors are logged and ignored.
+func (m *DDLSink) warnIfPartitioningNotSupported(ctx context.Context, ddl *model.DDLEvent) {
+	if !m.partitioningChecked {
+		supported, err := pmysql.CheckPartitioningSupported(ctx, m.db)
+		if err != nil {
+			log.Warn("Failed to check whether the downstream supports partitioning",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+			return
+		}
+		m.partitioningChecked = true
+		m.partitioningSupported = supported
+	}
+	if !m.partitioningSupported {
+		log.Warn("Replicate partition DDL to a downstream which doesn't support partitioning",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.Uint64("startTs", ddl.StartTs), zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("ddl", ddl.Query))
+	}
+}
+
+// formatPartitionDDL rewrites the `ADD PARTITION` and `DROP PARTITION` DDLs
+// in MySQL syntax by comparing the partitions before and after the DDL, so
+// that TiDB specific syntax like `ALTER TABLE t FIRST PARTITION LESS THAN (...)`
+// can be replicated to MySQL. The partition options which are not supported
+// by MySQL, such as placement policies, are dropped. It returns false if the
+// DDL doesn't need to be rewritten.
+func formatPartitionDDL(ddl *model.DDLEvent) (string, bool) {
+	if ddl.TableInfo == nil || ddl.TableInfo.TableInfo == nil ||
+		ddl.PreTableInfo == nil || ddl.PreTableInfo.TableInfo == nil {
+		return "", false
+	}
+	post, pre := ddl.TableInfo.GetPartitionInfo(), ddl.PreTableInfo.GetPartitionInfo()
+	if post == nil || pre == nil {
+		return "", false
+	}
+
+	var sb strings.Builder
+	sb.WriteString("ALTER TABLE ")
+	sb.WriteString(quotes.QuoteSchema(ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table))
+	switch ddl.Type {
+	case timodel.ActionAddTablePartition:
+		if post.Type != timodel.PartitionTypeRange && post.Type != timodel.PartitionTypeList {
+			return "", false
+		}
+		added := diffPartitions(post.Definitions, pre.Definitions)
+		if len(added) == 0 {
+			return "", false
+		}
+		sb.WriteString(" ADD PARTITION (")
+		for i, def := range added {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writePartitionDefinition(&sb, post.Type, def)
+		}
+		sb.WriteString(")")
+	case timodel.ActionDropTablePartition:
+		dropped := diffPartitions(pre.Definitions, post.Definitions)
+		if len(dropped) == 0 {
+			return "", false
+		}
+		sb.WriteString(" DROP PARTITION ")
+		for i, def := range dropped {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(quotes.QuoteName(def.Name.O))
+		}
+	default:
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// diffPartitions returns the partitions in a but not in b.
+func diffPartitions(a, b []timodel.PartitionDefinition) []timodel.PartitionDefinition {
+	names := make(map[string]struct{}, len(b))
+	for _, def := range b {
+		names[def.Name.L] = struct{}{}
+	}
+	var result []timodel.PartitionDefinition
+	for _, def := range a {
+		if _, ok := names[def.Name.L]; !ok {
+			result = append(result, def)
+		}
+	}
+	return result
+}
+
+func writePartitionDefinition(
+	sb *strings.Builder, tp timodel.PartitionType, def timodel.PartitionDefinition,
+) {
+	sb.WriteString("PARTITION ")
+	sb.WriteString(quotes.QuoteName(def.Name.O))
+	if tp == timodel.PartitionTypeRange {
+		sb.WriteString(" VALUES LESS THAN (")
+		sb.WriteString(strings.Join(def.LessThan, ","))
+		sb.WriteString(")")
+	} else {
+		sb.WriteString(" VALUES IN (")
+		for i, values := range def.InValues {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			// the values of LIST COLUMNS partitioning with multiple columns are tuples.
+			if len(values) > 1 {
+				sb.WriteString("(" + strings.Join(values, ",") + ")")
+			} else {
+				sb.WriteString(strings.Join(values, ","))
+			}
+		}
+		sb.WriteString(")")
+	}
+	if def.Comment != "" {
+		sb.WriteString(" COMMENT = '")
+		sb.WriteString(strings.ReplaceAll(def.Comment, "'", "''"))
+		sb.WriteString("'")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func newPartitionedTableInfo(
+	tp timodel.PartitionType, defs ...timodel.PartitionDefinition,
+) *model.TableInfo {
+	return &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t"},
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("t"),
+			Partition: &timodel.PartitionInfo{
+				Type:        tp,
+				Enable:      true,
+				Definitions: defs,
+			},
+		},
+	}
+}
+
+func rangePartition(name string, lessThan ...string) timodel.PartitionDefinition {
+	return timodel.PartitionDefinition{Name: timodel.NewCIStr(name), LessThan: lessThan}
+}
+
+func TestIsPartitionDDL(t *testing.T) {
+	t.Parallel()
+
+	partitioned := newPartitionedTableInfo(timodel.PartitionTypeRange, rangePartition("p0", "10"))
+	require.True(t, isPartitionDDL(&model.DDLEvent{Type: timodel.ActionTruncateTablePartition}))
+	require.True(t, isPartitionDDL(&model.DDLEvent{Type: timodel.ActionReorganizePartition}))
+	require.True(t, isPartitionDDL(&model.DDLEvent{
+		Type: timodel.ActionCreateTable, TableInfo: partitioned,
+	}))
+	require.False(t, isPartitionDDL(&model.DDLEvent{
+		Type: timodel.ActionCreateTable,
+		TableInfo: &model.TableInfo{
+			TableInfo: &timodel.TableInfo{Name: timodel.NewCIStr("t")},
+		},
+	}))
+	require.False(t, isPartitionDDL(&model.DDLEvent{Type: timodel.ActionAddColumn}))
+}
+
+func TestFormatPartitionDDL(t *testing.T) {
+	t.Parallel()
+
+	p0, p1, p2 := rangePartition("p0", "10"), rangePartition("p1", "20"), rangePartition("p2", "MAXVALUE")
+	p2.Comment = "it's the last one"
+
+	// ALTER TABLE t LAST PARTITION LESS THAN (...)
+	query, ok := formatPartitionDDL(&model.DDLEvent{
+		Type:         timodel.ActionAddTablePartition,
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeRange, p0),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeRange, p0, p1, p2),
+	})
+	require.True(t, ok)
+	require.Equal(t, "ALTER TABLE `test`.`t` ADD PARTITION ("+
+		"PARTITION `p1` VALUES LESS THAN (20), "+
+		"PARTITION `p2` VALUES LESS THAN (MAXVALUE) COMMENT = 'it''s the last one')", query)
+
+	// ALTER TABLE t FIRST PARTITION LESS THAN (...)
+	query, ok = formatPartitionDDL(&model.DDLEvent{
+		Type:         timodel.ActionDropTablePartition,
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeRange, p0, p1, p2),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeRange, p2),
+	})
+	require.True(t, ok)
+	require.Equal(t, "ALTER TABLE `test`.`t` DROP PARTITION `p0`,`p1`", query)
+
+	l0 := timodel.PartitionDefinition{Name: timodel.NewCIStr("l0"), InValues: [][]string{{"1"}, {"2"}}}
+	l1 := timodel.PartitionDefinition{
+		Name:     timodel.NewCIStr("l1"),
+		InValues: [][]string{{"3", "'a'"}, {"4", "'b'"}},
+	}
+	query, ok = formatPartitionDDL(&model.DDLEvent{
+		Type:         timodel.ActionAddTablePartition,
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeList),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeList, l0, l1),
+	})
+	require.True(t, ok)
+	require.Equal(t, "ALTER TABLE `test`.`t` ADD PARTITION ("+
+		"PARTITION `l0` VALUES IN (1,2), "+
+		"PARTITION `l1` VALUES IN ((3,'a'),(4,'b')))", query)
+
+	// hash partitions are not rewritten.
+	_, ok = formatPartitionDDL(&model.DDLEvent{
+		Type:         timodel.ActionAddTablePartition,
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeHash, p0),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeHash, p0, p1),
+	})
+	require.False(t, ok)
+	// other partition DDLs are replicated verbatim.
+	_, ok = formatPartitionDDL(&model.DDLEvent{
+		Type:         timodel.ActionTruncateTablePartition,
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeRange, p0),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeRange, p0),
+	})
+	require.False(t, ok)
+	_, ok = formatPartitionDDL(&model.DDLEvent{
+		Type:      timodel.ActionAddTablePartition,
+		TableInfo: newPartitionedTableInfo(timodel.PartitionTypeRange, p0, p1),
+	})
+	require.False(t, ok)
+}
+
+func TestWritePartitionDDLToMySQL(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	mock.ExpectQuery("SHOW VARIABLES LIKE 'have_partitioning'").
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("have_partitioning", "YES"))
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("ALTER TABLE `test`.`t` DROP PARTITION `p0`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	// the support of partitioning is only checked once.
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("ALTER TABLE t TRUNCATE PARTITION p1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID("test-partition-ddl")
+	ddlSink := &DDLSink{
+		id:         changefeedID,
+		db:         db,
+		cfg:        &pmysql.Config{PartitionDDLCompatibility: true},
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+	p0, p1 := rangePartition("p0", "10"), rangePartition("p1", "20")
+	ddl := &model.DDLEvent{
+		StartTs:      1000,
+		CommitTs:     1010,
+		Type:         timodel.ActionDropTablePartition,
+		Query:        "ALTER TABLE t FIRST PARTITION LESS THAN (20)",
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeRange, p0, p1),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeRange, p1),
+	}
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx, ddl))
+	// the original event is not modified.
+	require.Equal(t, "ALTER TABLE t FIRST PARTITION LESS THAN (20)", ddl.Query)
+
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx, &model.DDLEvent{
+		StartTs:      1020,
+		CommitTs:     1030,
+		Type:         timodel.ActionTruncateTablePartition,
+		Query:        "ALTER TABLE t TRUNCATE PARTITION p1",
+		PreTableInfo: newPartitionedTableInfo(timodel.PartitionTypeRange, p1),
+		TableInfo:    newPartitionedTableInfo(timodel.PartitionTypeRange, p1),
+	}))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"go.uber.org/zap"
+)
+
+// ptOSCShadowTable returns the name of the shadow table created by
+// pt-online-schema-change for the table.
+func ptOSCShadowTable(table string) string {
+	return "_" + table + "_new"
+}
+
+// changedTable returns the existing table changed by the DDL, ok is false if
+// the DDL doesn't change an existing table.
+func changedTable(ddl *model.DDLEvent) (schema, table string, ok bool) {
+	switch ddl.Type {
+	case timodel.ActionCreateTable, timodel.ActionCreateTables, timodel.ActionCreateView:
+		return "", "", false
+	}
+	tableInfo := ddl.TableInfo
+	if (ddl.Type == timodel.ActionRenameTable || ddl.Type == timodel.ActionRenameTables) &&
+		ddl.PreTableInfo != nil {
+		tableInfo = ddl.PreTableInfo
+	}
+	if tableInfo == nil || tableInfo.TableName.Table == "" {
+		return "", "", false
+	}
+	return tableInfo.TableName.Schema, tableInfo.TableName.Table, true
+}
+
+// checkPtOSCConflict returns ErrPtOSCConflict if pt-online-schema-change is
+// running on the table changed by the DDL. pt-online-schema-change copies
+// the rows to the shadow table `_<table>_new` and swaps the tables at last,
+// so the DDLs executed on the table in the meantime are lost after the swap.
+// The error is retryable, it's reported as a warning of the changefeed and
+// the DDL is retried until the shadow table is gone. A shadow table older
+// than PtOSCWaitTimeout is regarded as left by an aborted migration, which
+// doesn't delay the DDLs any more.
+func (m *DDLSink) checkPtOSCConflict(ctx context.Context, ddl *model.DDLEvent) error {
+	schema, table, ok := changedTable(ddl)
+	if !ok {
+		return nil
+	}
+	shadowTable := ptOSCShadowTable(table)
+	var age sql.NullInt64
+	err := m.db.QueryRowContext(ctx,
+		"SELECT TIMESTAMPDIFF(SECOND, CREATE_TIME, NOW()) FROM information_schema.TABLES "+
+			"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", schema, shadowTable).Scan(&age)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+
+	if !age.Valid || time.Duration(age.Int64)*time.Second >= m.cfg.PtOSCWaitTimeout {
+		log.Warn("The shadow table of pt-online-schema-change exists for too long, "+
+			"execute the DDL anyway",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("shadowTable", quotes.QuoteSchema(schema, shadowTable)),
+			zap.Int64("ageInSeconds", age.Int64),
+			zap.Duration("waitTimeout", m.cfg.PtOSCWaitTimeout),
+			zap.String("DDL", ddl.Query))
+		return nil
+	}
+	log.Info("pt-online-schema-change is running in the downstream, delay the DDL",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID),
+		zap.String("shadowTable", quotes.QuoteSchema(schema, shadowTable)),
+		zap.Int64("ageInSeconds", age.Int64),
+		zap.String("DDL", ddl.Query))
+	return cerror.ErrPtOSCConflict.GenWithStackByArgs(quotes.QuoteSchema(schema, table))
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+const ptOSCCheckQuery = "SELECT TIMESTAMPDIFF(SECOND, CREATE_TIME, NOW()) FROM information_schema.TABLES " +
+	"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+
+func newPtOSCTestDDLSink(t *testing.T) (*DDLSink, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID("test-pt-osc")
+	return &DDLSink{
+		id: changefeedID,
+		db: db,
+		cfg: &pmysql.Config{
+			PtOSCCompatibility: true,
+			PtOSCWaitTimeout:   time.Hour,
+			WriteTimeout:       "2m",
+		},
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}, mock
+}
+
+func TestChangedTable(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t"}}
+	schema, table, ok := changedTable(&model.DDLEvent{
+		Type: timodel.ActionAddColumn, TableInfo: tableInfo,
+	})
+	require.True(t, ok)
+	require.Equal(t, "test", schema)
+	require.Equal(t, "t", table)
+	require.Equal(t, "_t_new", ptOSCShadowTable(table))
+
+	_, table, ok = changedTable(&model.DDLEvent{
+		Type:         timodel.ActionRenameTable,
+		TableInfo:    &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t1"}},
+		PreTableInfo: tableInfo,
+	})
+	require.True(t, ok)
+	require.Equal(t, "t", table)
+
+	_, _, ok = changedTable(&model.DDLEvent{Type: timodel.ActionCreateTable, TableInfo: tableInfo})
+	require.False(t, ok)
+	_, _, ok = changedTable(&model.DDLEvent{
+		Type:      timodel.ActionCreateSchema,
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test"}},
+	})
+	require.False(t, ok)
+}
+
+func TestPtOSCConflict(t *testing.T) {
+	ddlSink, mock := newPtOSCTestDDLSink(t)
+	ddl := &model.DDLEvent{
+		StartTs: 1000, CommitTs: 1010, Type: timodel.ActionAddIndex,
+		Query:     "ALTER TABLE `test`.`t` ADD INDEX `idx`(`c`)",
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t"}},
+	}
+
+	// pt-online-schema-change is copying the rows to the shadow table.
+	mock.ExpectQuery(regexp.QuoteMeta(ptOSCCheckQuery)).WithArgs("test", "_t_new").
+		WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(10))
+	// pt-online-schema-change has swapped the tables and dropped the old one.
+	mock.ExpectQuery(regexp.QuoteMeta(ptOSCCheckQuery)).WithArgs("test", "_t_new").
+		WillReturnRows(sqlmock.NewRows([]string{"age"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(ddl.Query)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	ctx := context.Background()
+	err := ddlSink.WriteDDLEvent(ctx, ddl)
+	require.True(t, cerror.ErrPtOSCConflict.Equal(err))
+	require.ErrorContains(t, err, "`test`.`t`")
+	// The error is reported as a warning, and the DDL is retried.
+	require.False(t, cerror.ShouldFailChangefeed(err))
+	require.NoError(t, ddlSink.WriteDDLEvent(ctx, ddl))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPtOSCWaitTimeout(t *testing.T) {
+	ddlSink, mock := newPtOSCTestDDLSink(t)
+	ddl := &model.DDLEvent{
+		StartTs: 1000, CommitTs: 1010, Type: timodel.ActionAddIndex,
+		Query:     "ALTER TABLE `test`.`t` ADD INDEX `idx`(`c`)",
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t"}},
+	}
+
+	// The shadow table is left by an aborted migration two hours ago.
+	mock.ExpectQuery(regexp.QuoteMeta(ptOSCCheckQuery)).WithArgs("test", "_t_new").
+		WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(7200))
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(ddl.Query)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	require.NoError(t, ddlSink.WriteDDLEvent(context.Background(), ddl))
+	ddlSink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
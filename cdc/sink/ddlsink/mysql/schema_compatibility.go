@@ -0,0 +1,348 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"go.uber.org/zap"
+)
+
+// schemaMismatch is the severity of a column type mismatch between
+// the upstream and downstream.
+type schemaMismatch int
+
+const (
+	// schemaMismatchNone means the downstream column can hold all upstream values.
+	schemaMismatchNone schemaMismatch = iota
+	// schemaMismatchMayLoseData means some upstream values may be truncated
+	// or rejected by the downstream column.
+	schemaMismatchMayLoseData
+	// schemaMismatchIncompatible means the replication will definitely fail.
+	schemaMismatchIncompatible
+)
+
+// needCheckSchemaCompatibility returns true if the table still exists in the
+// downstream after the DDL is executed.
+func needCheckSchemaCompatibility(ddl *model.DDLEvent) bool {
+	if ddl.TableInfo == nil || ddl.TableInfo.TableInfo == nil || ddl.TableInfo.IsView() {
+		return false
+	}
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionDropSchema, timodel.ActionDropView,
+		timodel.ActionCreateSchema, timodel.ActionModifySchemaCharsetAndCollate:
+		return false
+	}
+	return true
+}
+
+// checkSchemaCompatibility compares the column types and the charset of the
+// downstream table with the upstream. Mismatches that may cause data loss are
+// logged as warnings, and mismatches that will definitely fail the replication
+// are logged as errors. It's best effort, errors are logged and ignored.
+func (m *DDLSink) checkSchemaCompatibility(ctx context.Context, tableInfo *model.TableInfo) {
+	schema, table := tableInfo.TableName.Schema, tableInfo.TableName.Table
+	m.checkTableCharset(ctx, tableInfo)
+	downstream, err := queryDownstreamColumnTypes(ctx, m.db, schema, table)
+	if err != nil {
+		log.Warn("Failed to query downstream column types",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("schema", schema), zap.String("table", table),
+			zap.Error(err))
+		return
+	}
+
+	for _, col := range tableInfo.Columns {
+		if col.State != timodel.StatePublic || col.IsGenerated() {
+			continue
+		}
+		var (
+			mismatch       schemaMismatch
+			downstreamType string
+		)
+		ft, ok := downstream[col.Name.L]
+		if !ok {
+			mismatch = schemaMismatchIncompatible
+			downstreamType = "<missing>"
+		} else {
+			mismatch = compareColumnType(&col.FieldType, ft)
+			downstreamType = ft.String()
+		}
+		if mismatch == schemaMismatchNone {
+			continue
+		}
+		metrics.SchemaMismatchCounter.WithLabelValues(
+			m.id.Namespace, m.id.ID, quotes.QuoteSchema(schema, table), col.Name.O).Inc()
+		fields := []zap.Field{
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("schema", schema), zap.String("table", table),
+			zap.String("column", col.Name.O),
+			zap.String("upstreamType", col.FieldType.String()),
+			zap.String("downstreamType", downstreamType),
+		}
+		if mismatch == schemaMismatchIncompatible {
+			log.Error("Column type of downstream is incompatible with upstream, "+
+				"the replication will fail", fields...)
+		} else {
+			log.Warn("Column type of downstream is narrower than upstream, "+
+				"values may be truncated", fields...)
+		}
+	}
+}
+
+// checkTableCharset warns if the charset of the downstream table differs from
+// the upstream one, which usually happens when the table is created without
+// an explicit charset in a downstream whose default charset isn't utf8mb4.
+func (m *DDLSink) checkTableCharset(ctx context.Context, tableInfo *model.TableInfo) {
+	schema, table := tableInfo.TableName.Schema, tableInfo.TableName.Table
+	upstream := tableInfo.Charset
+	if upstream == "" {
+		upstream = mysql.DefaultCharset
+	}
+	var downstream string
+	err := m.db.QueryRowContext(ctx, queryTableCharsetSQL, schema, table).Scan(&downstream)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Warn("Failed to query downstream table charset",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.String("schema", schema), zap.String("table", table),
+				zap.Error(err))
+		}
+		return
+	}
+	if normalizeCharset(upstream) != normalizeCharset(downstream) {
+		log.Warn("Charset of downstream table is different from upstream, "+
+			"characters may be lost or garbled",
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID),
+			zap.String("schema", schema), zap.String("table", table),
+			zap.String("upstreamCharset", upstream),
+			zap.String("downstreamCharset", downstream))
+	}
+}
+
+const queryTableCharsetSQL = `SELECT CCSA.CHARACTER_SET_NAME
+FROM INFORMATION_SCHEMA.TABLES T
+JOIN INFORMATION_SCHEMA.COLLATION_CHARACTER_SET_APPLICABILITY CCSA
+ON CCSA.COLLATION_NAME = T.TABLE_COLLATION
+WHERE T.TABLE_SCHEMA = ? AND T.TABLE_NAME = ?`
+
+// normalizeCharset treats utf8 and utf8mb3 as the same charset.
+func normalizeCharset(cs string) string {
+	cs = strings.ToLower(cs)
+	if cs == charset.CharsetUTF8MB3 {
+		return charset.CharsetUTF8
+	}
+	return cs
+}
+
+// queryDownstreamColumnTypes returns the field types of all columns of the
+// downstream table, keyed by the lower case column name.
+func queryDownstreamColumnTypes(
+	ctx context.Context, db *sql.DB, schema, table string,
+) (map[string]*types.FieldType, error) {
+	rows, err := db.QueryContext(ctx, "DESCRIBE "+quotes.QuoteSchema(schema, table))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	result := make(map[string]*types.FieldType)
+	for rows.Next() {
+		// The first two columns are `Field` and `Type`.
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		ft, err := parseColumnType(values[1].String)
+		if err != nil {
+			return nil, err
+		}
+		result[strings.ToLower(values[0].String)] = ft
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return result, nil
+}
+
+// parseColumnType parses the column type returned by `DESCRIBE`,
+// such as `bigint(20) unsigned` and `varchar(100)`.
+func parseColumnType(tp string) (*types.FieldType, error) {
+	stmt, err := parser.New().ParseOneStmt("CREATE TABLE t (c "+tp+")", "", "")
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return stmt.(*ast.CreateTableStmt).Cols[0].Tp, nil
+}
+
+type typeCategory int
+
+const (
+	typeCategoryOther typeCategory = iota
+	typeCategoryInteger
+	typeCategoryFloat
+	typeCategoryDecimal
+	typeCategoryString
+	typeCategoryTime
+)
+
+func getTypeCategory(tp byte) typeCategory {
+	switch tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		return typeCategoryInteger
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return typeCategoryFloat
+	case mysql.TypeNewDecimal:
+		return typeCategoryDecimal
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		return typeCategoryString
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp,
+		mysql.TypeDuration, mysql.TypeYear:
+		return typeCategoryTime
+	}
+	return typeCategoryOther
+}
+
+// integerRank returns the storage size of integer types.
+var integerRank = map[byte]int{
+	mysql.TypeTiny:     1,
+	mysql.TypeShort:    2,
+	mysql.TypeInt24:    3,
+	mysql.TypeLong:     4,
+	mysql.TypeLonglong: 8,
+}
+
+// maxStringLength returns the max length of string types.
+func maxStringLength(ft *types.FieldType) int64 {
+	switch ft.GetType() {
+	case mysql.TypeTinyBlob:
+		return 1<<8 - 1
+	case mysql.TypeBlob:
+		return 1<<16 - 1
+	case mysql.TypeMediumBlob:
+		return 1<<24 - 1
+	case mysql.TypeLongBlob:
+		return 1<<32 - 1
+	}
+	flen, _ := getFlenAndDecimal(ft)
+	return int64(flen)
+}
+
+// getFlenAndDecimal returns the length and decimal of the field type,
+// the default values are used if they are not specified.
+func getFlenAndDecimal(ft *types.FieldType) (int, int) {
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.GetType())
+	flen, decimal := ft.GetFlen(), ft.GetDecimal()
+	if flen == types.UnspecifiedLength {
+		flen = defaultFlen
+	}
+	if decimal == types.UnspecifiedLength {
+		decimal = defaultDecimal
+	}
+	return flen, decimal
+}
+
+// compareColumnType checks whether the downstream column type
+// can hold all values of the upstream column type.
+func compareColumnType(upstream, downstream *types.FieldType) schemaMismatch {
+	category := getTypeCategory(upstream.GetType())
+	if category != getTypeCategory(downstream.GetType()) {
+		return schemaMismatchIncompatible
+	}
+
+	switch category {
+	case typeCategoryInteger:
+		upRank, downRank := integerRank[upstream.GetType()], integerRank[downstream.GetType()]
+		upUnsigned := mysql.HasUnsignedFlag(upstream.GetFlag())
+		downUnsigned := mysql.HasUnsignedFlag(downstream.GetFlag())
+		switch {
+		case downRank < upRank:
+			return schemaMismatchMayLoseData
+		case upUnsigned == downUnsigned:
+			return schemaMismatchNone
+		case upUnsigned && downRank > upRank:
+			// A wider signed type can hold all unsigned values.
+			return schemaMismatchNone
+		default:
+			return schemaMismatchMayLoseData
+		}
+	case typeCategoryFloat:
+		if upstream.GetType() == mysql.TypeDouble && downstream.GetType() == mysql.TypeFloat {
+			return schemaMismatchMayLoseData
+		}
+	case typeCategoryDecimal:
+		upFlen, upDecimal := getFlenAndDecimal(upstream)
+		downFlen, downDecimal := getFlenAndDecimal(downstream)
+		if downFlen-downDecimal < upFlen-upDecimal || downDecimal < upDecimal {
+			return schemaMismatchMayLoseData
+		}
+	case typeCategoryString:
+		if maxStringLength(downstream) < maxStringLength(upstream) {
+			return schemaMismatchMayLoseData
+		}
+	case typeCategoryTime:
+		_, upDecimal := getFlenAndDecimal(upstream)
+		_, downDecimal := getFlenAndDecimal(downstream)
+		if upstream.GetType() != downstream.GetType() || downDecimal < upDecimal {
+			return schemaMismatchMayLoseData
+		}
+	default:
+		if upstream.GetType() != downstream.GetType() {
+			return schemaMismatchIncompatible
+		}
+		switch upstream.GetType() {
+		case mysql.TypeBit:
+			if downstream.GetFlen() < upstream.GetFlen() {
+				return schemaMismatchMayLoseData
+			}
+		case mysql.TypeEnum, mysql.TypeSet:
+			elems := make(map[string]struct{}, len(downstream.GetElems()))
+			for _, e := range downstream.GetElems() {
+				elems[strings.ToLower(e)] = struct{}{}
+			}
+			for _, e := range upstream.GetElems() {
+				if _, ok := elems[strings.ToLower(e)]; !ok {
+					return schemaMismatchMayLoseData
+				}
+			}
+		}
+	}
+	return schemaMismatchNone
+}
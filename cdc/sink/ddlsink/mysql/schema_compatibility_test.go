@@ -0,0 +1,163 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCompareColumnType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		upstream   string
+		downstream string
+		expected   schemaMismatch
+	}{
+		{"bigint(20)", "bigint(20)", schemaMismatchNone},
+		{"int(11)", "bigint(20)", schemaMismatchNone},
+		{"bigint(20)", "int(11)", schemaMismatchMayLoseData},
+		{"int(10) unsigned", "bigint(20)", schemaMismatchNone},
+		{"int(10) unsigned", "int(11)", schemaMismatchMayLoseData},
+		{"varchar(100)", "varchar(255)", schemaMismatchNone},
+		{"varchar(255)", "varchar(100)", schemaMismatchMayLoseData},
+		{"varchar(255)", "text", schemaMismatchNone},
+		{"text", "varchar(100)", schemaMismatchMayLoseData},
+		{"decimal(10,2)", "decimal(12,2)", schemaMismatchNone},
+		{"decimal(10,2)", "decimal(10,1)", schemaMismatchMayLoseData},
+		{"double", "float", schemaMismatchMayLoseData},
+		{"datetime(6)", "datetime", schemaMismatchMayLoseData},
+		{"enum('a','b')", "enum('a','b','c')", schemaMismatchNone},
+		{"enum('a','b')", "enum('a')", schemaMismatchMayLoseData},
+		{"varchar(10)", "int(11)", schemaMismatchIncompatible},
+		{"json", "json", schemaMismatchNone},
+		{"json", "bit(1)", schemaMismatchIncompatible},
+	}
+	for _, tc := range testCases {
+		upstream, err := parseColumnType(tc.upstream)
+		require.NoError(t, err)
+		downstream, err := parseColumnType(tc.downstream)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, compareColumnType(upstream, downstream),
+			"upstream: %s, downstream: %s", tc.upstream, tc.downstream)
+	}
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	describeColumns := []string{"Field", "Type", "Null", "Key", "Default", "Extra"}
+	mock.ExpectQuery(queryTableCharsetSQL).WithArgs("test", "t1").
+		WillReturnRows(sqlmock.NewRows([]string{"CHARACTER_SET_NAME"}).AddRow("utf8mb4"))
+	mock.ExpectQuery("DESCRIBE `test`.`t1`").
+		WillReturnRows(sqlmock.NewRows(describeColumns).
+			AddRow("id", "int(11)", "NO", "PRI", nil, "").
+			AddRow("name", "varchar(100)", "YES", "", nil, "").
+			AddRow("age", "int(11)", "YES", "", nil, ""))
+	mock.ExpectClose()
+
+	newColumn := func(name, tp string) *timodel.ColumnInfo {
+		ft, err := parseColumnType(tp)
+		require.NoError(t, err)
+		return &timodel.ColumnInfo{
+			Name:      timodel.NewCIStr(name),
+			FieldType: *ft,
+			State:     timodel.StatePublic,
+		}
+	}
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t1"},
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("t1"),
+			Columns: []*timodel.ColumnInfo{
+				newColumn("id", "bigint(20)"),
+				newColumn("name", "varchar(255)"),
+				newColumn("age", "int(11)"),
+			},
+		},
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test-schema-compatibility")
+	sink := &DDLSink{
+		id:  changefeedID,
+		db:  db,
+		cfg: &pmysql.Config{SchemaCompatibilityCheck: true},
+	}
+	require.NoError(t, sink.WriteCheckpointTs(context.Background(), 1, []*model.TableInfo{tableInfo}))
+	// The schema compatibility is only checked once after the sink is started.
+	require.NoError(t, sink.WriteCheckpointTs(context.Background(), 2, []*model.TableInfo{tableInfo}))
+	sink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	counter := func(column string) float64 {
+		return testutil.ToFloat64(metrics.SchemaMismatchCounter.WithLabelValues(
+			changefeedID.Namespace, changefeedID.ID, "`test`.`t1`", column))
+	}
+	require.Equal(t, float64(1), counter("id"))
+	require.Equal(t, float64(1), counter("name"))
+	require.Equal(t, float64(0), counter("age"))
+}
+
+func TestCheckTableCharset(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	// The downstream table is created in a MySQL whose default charset is latin1.
+	mock.ExpectQuery(queryTableCharsetSQL).WithArgs("test", "t1").
+		WillReturnRows(sqlmock.NewRows([]string{"CHARACTER_SET_NAME"}).AddRow("latin1"))
+	mock.ExpectQuery(queryTableCharsetSQL).WithArgs("test", "t2").
+		WillReturnRows(sqlmock.NewRows([]string{"CHARACTER_SET_NAME"}).AddRow("utf8mb3"))
+	mock.ExpectClose()
+
+	zapcore, logs := observer.New(zap.WarnLevel)
+	conf := &log.Config{Level: "warn", File: log.FileLogConfig{}}
+	_, r, _ := log.InitLogger(conf)
+	restoreFn := log.ReplaceGlobals(zap.New(zapcore), r)
+	defer restoreFn()
+
+	sink := &DDLSink{
+		id:  model.DefaultChangeFeedID("test-table-charset"),
+		db:  db,
+		cfg: &pmysql.Config{SchemaCompatibilityCheck: true},
+	}
+	newTableInfo := func(table, charset string) *model.TableInfo {
+		return &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: table},
+			TableInfo: &timodel.TableInfo{Name: timodel.NewCIStr(table), Charset: charset},
+		}
+	}
+	ctx := context.Background()
+	sink.checkTableCharset(ctx, newTableInfo("t1", ""))
+	sink.checkTableCharset(ctx, newTableInfo("t2", "utf8"))
+	sink.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	mismatches := logs.FilterMessage("Charset of downstream table is different from upstream, " +
+		"characters may be lost or garbled").All()
+	require.Len(t, mismatches, 1)
+	require.Equal(t, "t1", mismatches[0].ContextMap()["table"])
+	require.Equal(t, "utf8mb4", mismatches[0].ContextMap()["upstreamCharset"])
+	require.Equal(t, "latin1", mismatches[0].ContextMap()["downstreamCharset"])
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	ppg "github.com/pingcap/tiflow/pkg/sink/pg"
+)
+
+// translateDDL translates a DDL event to the statements of PostgreSQL.
+// It returns nil if the DDL is not supported by the PostgreSQL sink.
+func translateDDL(ddl *model.DDLEvent) []string {
+	tableInfo := ddl.TableInfo
+	schema := tableInfo.TableName.Schema
+	switch ddl.Type {
+	case timodel.ActionCreateSchema:
+		return []string{"CREATE SCHEMA IF NOT EXISTS " + ppg.QuoteName(schema)}
+	case timodel.ActionDropSchema:
+		return []string{"DROP SCHEMA IF EXISTS " + ppg.QuoteName(schema) + " CASCADE"}
+	case timodel.ActionCreateTable:
+		if tableInfo.TableInfo == nil || tableInfo.IsView() {
+			return nil
+		}
+		return []string{buildCreateTable(tableInfo)}
+	case timodel.ActionDropTable:
+		return []string{"DROP TABLE IF EXISTS " + quoteTable(tableInfo)}
+	case timodel.ActionTruncateTable:
+		return []string{"TRUNCATE TABLE " + quoteTable(tableInfo)}
+	case timodel.ActionAddColumn:
+		if ddl.PreTableInfo == nil {
+			return nil
+		}
+		var stmts []string
+		for _, col := range tableInfo.Columns {
+			if !isReplicatedColumn(col) {
+				continue
+			}
+			if _, ok := ddl.PreTableInfo.GetColumnInfo(col.ID); ok {
+				continue
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s",
+				quoteTable(tableInfo), buildColumnDefinition(col)))
+		}
+		return stmts
+	case timodel.ActionDropColumn:
+		if ddl.PreTableInfo == nil {
+			return nil
+		}
+		var stmts []string
+		for _, col := range ddl.PreTableInfo.Columns {
+			if _, ok := tableInfo.GetColumnInfo(col.ID); ok {
+				continue
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s",
+				quoteTable(tableInfo), ppg.QuoteName(col.Name.O)))
+		}
+		return stmts
+	default:
+		return nil
+	}
+}
+
+func quoteTable(tableInfo *model.TableInfo) string {
+	return ppg.QuoteSchema(tableInfo.TableName.Schema, tableInfo.TableName.Table)
+}
+
+// isReplicatedColumn returns false for the virtual generated columns, whose
+// values are not carried by the row changed events.
+func isReplicatedColumn(col *timodel.ColumnInfo) bool {
+	return !col.IsGenerated() || col.GeneratedStored
+}
+
+func buildCreateTable(tableInfo *model.TableInfo) string {
+	definitions := make([]string, 0, len(tableInfo.Columns)+1)
+	for _, col := range tableInfo.Columns {
+		if !isReplicatedColumn(col) {
+			continue
+		}
+		definitions = append(definitions, buildColumnDefinition(col))
+	}
+	if pk := tableInfo.GetPrimaryKeyColumnNames(); len(pk) > 0 {
+		definitions = append(definitions, "PRIMARY KEY ("+quoteNames(pk)+")")
+	}
+	for _, index := range tableInfo.Indices {
+		if !index.Unique || index.Primary {
+			continue
+		}
+		names := make([]string, 0, len(index.Columns))
+		for _, col := range index.Columns {
+			names = append(names, col.Name.O)
+		}
+		definitions = append(definitions, "UNIQUE ("+quoteNames(names)+")")
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)",
+		quoteTable(tableInfo), strings.Join(definitions, ", "))
+}
+
+func buildColumnDefinition(col *timodel.ColumnInfo) string {
+	definition := ppg.QuoteName(col.Name.O) + " " + ppg.ColumnType(&col.FieldType)
+	defaultValue := model.GetColumnDefaultValue(col)
+	if defaultValue != nil && !col.DefaultIsExpr {
+		if value, err := ppg.ConvertValue(&col.FieldType, defaultValue); err == nil && value != nil {
+			definition += " DEFAULT " + quoteLiteral(fmt.Sprintf("%v", value))
+		}
+	}
+	if mysql.HasNotNullFlag(col.GetFlag()) {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, ppg.QuoteName(name))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
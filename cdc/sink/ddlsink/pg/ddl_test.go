@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateDDL(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event("create database test2")
+	require.Equal(t, []string{`CREATE SCHEMA IF NOT EXISTS "test2"`}, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("create table test2.t(id bigint unsigned primary key, " +
+		"name varchar(32) not null default 'a', c datetime, unique key uk(name))")
+	require.Equal(t, []string{`CREATE TABLE IF NOT EXISTS "test2"."t" (` +
+		`"id" NUMERIC(20,0) NOT NULL, "name" VARCHAR(32) DEFAULT 'a' NOT NULL, ` +
+		`"c" TIMESTAMP WITHOUT TIME ZONE, PRIMARY KEY ("id"), UNIQUE ("name"))`}, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("alter table test2.t add column d text")
+	require.Equal(t, []string{`ALTER TABLE "test2"."t" ADD COLUMN IF NOT EXISTS "d" TEXT`},
+		translateDDL(ddl))
+
+	ddl = helper.DDL2Event("alter table test2.t drop column c")
+	require.Equal(t, []string{`ALTER TABLE "test2"."t" DROP COLUMN IF EXISTS "c"`},
+		translateDDL(ddl))
+
+	ddl = helper.DDL2Event("truncate table test2.t")
+	require.Equal(t, []string{`TRUNCATE TABLE "test2"."t"`}, translateDDL(ddl))
+
+	// DDLs not supported by the PostgreSQL sink.
+	ddl = helper.DDL2Event("alter table test2.t add index idx(d(10))")
+	require.Nil(t, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("drop table test2.t")
+	require.Equal(t, []string{`DROP TABLE IF EXISTS "test2"."t"`}, translateDDL(ddl))
+
+	ddl = helper.DDL2Event("drop database test2")
+	require.Equal(t, []string{`DROP SCHEMA IF EXISTS "test2" CASCADE`}, translateDDL(ddl))
+}
+
+func TestWriteDDLEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "test"."t" ("id" INTEGER NOT NULL, PRIMARY KEY ("id"))`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("postgres://127.0.0.1:5432/test")
+	require.NoError(t, err)
+	sink, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+
+	ddl := helper.DDL2Event("create table test.t(id int primary key)")
+	require.NoError(t, sink.WriteDDLEvent(ctx, ddl))
+	// the unsupported DDLs are skipped.
+	ddl = helper.DDL2Event("create view test.v as select * from test.t")
+	require.NoError(t, sink.WriteDDLEvent(ctx, ddl))
+	sink.Close()
+}
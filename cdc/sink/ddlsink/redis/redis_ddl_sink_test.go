@@ -0,0 +1,61 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDDLEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	server := miniredis.RunT(t)
+	for _, key := range []string{"tidb:test.t:1", "tidb:test.t:2", "tidb:test.t2:1", "tidb:test.t*:1"} {
+		require.NoError(t, server.Set(key, "{}"))
+	}
+	GetClientImpl = func(ctx context.Context, cfg *predis.Config) (redis.UniversalClient, error) {
+		return redis.NewClient(&redis.Options{Addr: server.Addr()}), nil
+	}
+	defer func() { GetClientImpl = predis.CreateClient }()
+
+	ctx := context.Background()
+	sinkURI, err := url.Parse("redis://127.0.0.1:6379")
+	require.NoError(t, err)
+	s, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("create table test.t(id int primary key)")))
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("alter table test.t add column c int")))
+	require.Len(t, server.Keys(), 4)
+
+	require.NoError(t, s.WriteDDLEvent(ctx, helper.DDL2Event("truncate table test.t")))
+	require.Equal(t, []string{"tidb:test.t*:1", "tidb:test.t2:1"}, server.Keys())
+}
+
+func TestEscapePattern(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `tidb:test.t\*\?\[\]\\:`, escapePattern(`tidb:test.t*?[]\:`))
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/stretchr/testify/require"
+)
+
+func newTxns(
+	cnt *uint64, rows ...*model.RowChangedEvent,
+) []*dmlsink.TxnCallbackableEvent {
+	tableStatus := state.TableSinkSinking
+	var txns []*dmlsink.TxnCallbackableEvent
+	for _, row := range rows {
+		if len(txns) == 0 || txns[len(txns)-1].Event.CommitTs != row.CommitTs {
+			txns = append(txns, &dmlsink.TxnCallbackableEvent{
+				Event: &model.SingleTableTxn{
+					CommitTs:         row.CommitTs,
+					TableInfo:        row.TableInfo,
+					TableInfoVersion: row.TableInfo.Version,
+				},
+				Callback:  func() { atomic.AddUint64(cnt, 1) },
+				SinkState: &tableStatus,
+			})
+		}
+		txn := txns[len(txns)-1].Event
+		txn.Rows = append(txn.Rows, row)
+	}
+	return txns
+}
+
+func TestWriteCSVFiles(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	tableInfo := helper.DDL2Event("create table test.t(id int primary key, v varchar(16))").TableInfo
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("csv://%s?storage=file&worker-count=2", dir))
+	require.NoError(t, err)
+	errCh := make(chan error, 1)
+	s, err := NewDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, errCh)
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, "csv", s.Scheme())
+
+	var cnt, expected uint64
+	write := func(rows ...*model.RowChangedEvent) {
+		txns := newTxns(&cnt, rows...)
+		expected += uint64(len(txns))
+		require.NoError(t, s.WriteEvents(txns...))
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint64(&cnt) == expected
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+	write(
+		newRow(tableInfo, 100, nil, []interface{}{int64(1), "a"}),
+		newRow(tableInfo, 100, nil, []interface{}{int64(2), "b"}),
+		newRow(tableInfo, 110, []interface{}{int64(1), "a"}, []interface{}{int64(1), "c"}),
+	)
+	// The split transaction is appended to the same file.
+	write(newRow(tableInfo, 110, []interface{}{int64(2), "b"}, nil))
+
+	readFile := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.Equal(t, "\"id\",\"v\"\n1,\"a\"\n2,\"b\"\n", readFile("test.t.100.csv"))
+	require.Equal(t, "\"id\",\"v\"\n1,\\N\n1,\"c\"\n2,\\N\n", readFile("test.t.110.csv"))
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestWriteEventsOfStoppingTable(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(fmt.Sprintf("csv://%s?storage=file", t.TempDir()))
+	require.NoError(t, err)
+	s, err := NewDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, make(chan error, 1))
+	require.NoError(t, err)
+	defer s.Close()
+
+	var cnt uint64
+	tableStatus := state.TableSinkStopping
+	require.NoError(t, s.WriteEvents(&dmlsink.TxnCallbackableEvent{
+		Event:     &model.SingleTableTxn{},
+		Callback:  func() { atomic.AddUint64(&cnt, 1) },
+		SinkState: &tableStatus,
+	}))
+	require.Equal(t, uint64(1), atomic.LoadUint64(&cnt))
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/chann"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/lightning"
+	"go.uber.org/zap"
+)
+
+const defaultMaxRetry uint64 = 20
+
+// epoch is the transactions of a table written by a WriteEvents call, which
+// are all the transactions of a table span up to a resolved ts.
+type epoch struct {
+	txns []*dmlsink.TxnCallbackableEvent
+}
+
+// dmlWorker writes the epochs of the tables to the CSV files. The epochs of
+// a table are always written by the same worker, so that the files of a
+// table are never written concurrently.
+type dmlWorker struct {
+	id           int
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+	inputCh      *chann.DrainableChann[*epoch]
+	statistics   *metrics.Statistics
+}
+
+func newDMLWorker(
+	id int,
+	changefeedID model.ChangeFeedID,
+	storage storage.ExternalStorage,
+	inputCh *chann.DrainableChann[*epoch],
+	statistics *metrics.Statistics,
+) *dmlWorker {
+	return &dmlWorker{
+		id:           id,
+		changefeedID: changefeedID,
+		storage:      storage,
+		inputCh:      inputCh,
+		statistics:   statistics,
+	}
+}
+
+func (w *dmlWorker) run(ctx context.Context) error {
+	log.Info("csv dml worker started",
+		zap.Int("workerID", w.id),
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID))
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case e, ok := <-w.inputCh.Out():
+			if !ok {
+				return nil
+			}
+			if err := w.writeEpoch(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeEpoch writes a file for the transactions of each commit ts in the
+// epoch, and then calls the callbacks of the transactions.
+func (w *dmlWorker) writeEpoch(ctx context.Context, e *epoch) error {
+	for start := 0; start < len(e.txns); {
+		end := start + 1
+		commitTs := e.txns[start].Event.CommitTs
+		for end < len(e.txns) && e.txns[end].Event.CommitTs == commitTs {
+			end++
+		}
+		if err := w.write(ctx, e.txns[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	for _, txn := range e.txns {
+		txn.Callback()
+	}
+	return nil
+}
+
+// write writes the transactions with the same commit ts to a file.
+func (w *dmlWorker) write(ctx context.Context, txns []*dmlsink.TxnCallbackableEvent) error {
+	tableInfo := txns[0].Event.TableInfo
+	schemaName, tableName := tableInfo.GetSchemaName(), tableInfo.GetTableName()
+	return retry.Do(ctx, func() error {
+		err := w.statistics.RecordBatchExecution(func() (int, int64, error) {
+			return w.doWrite(ctx, schemaName, tableName, txns)
+		})
+		if err != nil {
+			log.Warn("write csv file failed, retry later",
+				zap.String("namespace", w.changefeedID.Namespace),
+				zap.String("changefeed", w.changefeedID.ID),
+				zap.String("schema", schemaName),
+				zap.String("table", tableName),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func (w *dmlWorker) doWrite(
+	ctx context.Context, schemaName, tableName string, txns []*dmlsink.TxnCallbackableEvent,
+) (int, int64, error) {
+	commitTs := txns[0].Event.CommitTs
+	name := lightning.DataFileName(schemaName, tableName, commitTs)
+
+	enc := &encoder{}
+	// The file exists if a large transaction is split into several epochs,
+	// or the partitions of a table are changed by the same transaction, the
+	// rows are appended to it.
+	exists, err := w.storage.FileExists(ctx, name)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if exists {
+		data, err := w.storage.ReadFile(ctx, name)
+		if err != nil {
+			return 0, 0, errors.Trace(err)
+		}
+		enc.buf.Write(data)
+	} else {
+		enc.encodeHeader(txns[0].Event.TableInfo)
+	}
+
+	rowCount := 0
+	for _, txn := range txns {
+		for _, row := range txn.Event.Rows {
+			if err := enc.encodeRow(row); err != nil {
+				return 0, 0, err
+			}
+			rowCount++
+		}
+	}
+	if rowCount == 0 {
+		return 0, 0, nil
+	}
+
+	data := enc.bytes()
+	if err := w.storage.WriteFile(ctx, name, data); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	log.Debug("csv file written",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("file", name),
+		zap.Int("rows", rowCount),
+		zap.Bool("appended", exists))
+	return rowCount, int64(len(data)), nil
+}
+
+func (w *dmlWorker) close() {
+	w.inputCh.CloseAndDrain()
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The files are encoded in the default CSV format of TiDB Lightning, i.e.
+// `separator = ','`, `delimiter = '"'`, `null = '\N'`, `header = true` and
+// `backslash-escape = true`.
+const (
+	separator  = ','
+	delimiter  = '"'
+	terminator = '\n'
+	nullString = `\N`
+)
+
+// escaper escapes the characters in a quoted field.
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\x00", `\0`)
+
+// encoder encodes the rows of a table to a CSV file, the first record is the
+// header with the column names, and the columns are in the order of them in
+// the table info.
+type encoder struct {
+	buf bytes.Buffer
+}
+
+// encodeHeader encodes the names of the columns of the table.
+func (e *encoder) encodeHeader(tableInfo *model.TableInfo) {
+	for i, colInfo := range tableInfo.GetColInfosForRowChangedEvent() {
+		if i > 0 {
+			e.buf.WriteByte(separator)
+		}
+		e.writeQuoted(tableInfo.ForceGetColumnName(colInfo.ID))
+	}
+	e.buf.WriteByte(terminator)
+}
+
+// encodeRow encodes a row changed event. An INSERT is encoded as a plain
+// record and an UPDATE is encoded as a DELETE record followed by an INSERT
+// record. A DELETE record has the values of the handle key columns, and all
+// the other values are NULL, which is the convention of the deletions
+// imported by Lightning. The values of all the columns are kept if the
+// table has no handle key, since the row can't be located otherwise.
+func (e *encoder) encodeRow(row *model.RowChangedEvent) error {
+	if row.IsDelete() || row.IsUpdate() {
+		if err := e.encodeRecord(row.TableInfo, row.GetPreColumns(), true); err != nil {
+			return err
+		}
+	}
+	if row.IsDelete() {
+		return nil
+	}
+	return e.encodeRecord(row.TableInfo, row.GetColumns(), false)
+}
+
+func (e *encoder) encodeRecord(tableInfo *model.TableInfo, cols []*model.Column, isDelete bool) error {
+	hasHandleKey := false
+	if isDelete {
+		for _, col := range cols {
+			if col != nil && col.Flag.IsHandleKey() {
+				hasHandleKey = true
+				break
+			}
+		}
+	}
+
+	colInfos := tableInfo.GetColInfosForRowChangedEvent()
+	for i, col := range cols {
+		if i > 0 {
+			e.buf.WriteByte(separator)
+		}
+		if col == nil || (hasHandleKey && !col.Flag.IsHandleKey()) {
+			e.buf.WriteString(nullString)
+			continue
+		}
+		if err := e.encodeValue(col, colInfos[i].Ft); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteByte(terminator)
+	return nil
+}
+
+func (e *encoder) encodeValue(col *model.Column, ft *types.FieldType) error {
+	if col.Value == nil {
+		e.buf.WriteString(nullString)
+		return nil
+	}
+	switch v := col.Value.(type) {
+	case []byte:
+		// The binary values are written as is, they are escaped in the
+		// quoted field.
+		e.writeQuoted(string(v))
+		return nil
+	case string:
+		e.writeQuoted(v)
+		return nil
+	}
+
+	switch col.Type {
+	case mysql.TypeEnum:
+		enum, err := types.ParseEnumValue(ft.GetElems(), col.Value.(uint64))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrCSVEncodeFailed, err)
+		}
+		e.writeQuoted(enum.Name)
+	case mysql.TypeSet:
+		set, err := types.ParseSetValue(ft.GetElems(), col.Value.(uint64))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrCSVEncodeFailed, err)
+		}
+		e.writeQuoted(set.Name)
+	default:
+		e.buf.WriteString(fmt.Sprintf("%v", col.Value))
+	}
+	return nil
+}
+
+func (e *encoder) writeQuoted(value string) {
+	e.buf.WriteByte(delimiter)
+	e.buf.WriteString(escaper.Replace(value))
+	e.buf.WriteByte(delimiter)
+}
+
+// bytes returns the encoded content.
+func (e *encoder) bytes() []byte {
+	return e.buf.Bytes()
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+// newRow creates a row changed event, the values are in the order of the
+// columns, nil pre or post means an insert or a delete.
+func newRow(tableInfo *model.TableInfo, commitTs uint64, pre, post []interface{}) *model.RowChangedEvent {
+	columns := func(values []interface{}) []*model.ColumnData {
+		if values == nil {
+			return nil
+		}
+		data := make([]*model.ColumnData, 0, len(values))
+		for i, col := range tableInfo.Columns {
+			data = append(data, &model.ColumnData{ColumnID: col.ID, Value: values[i]})
+		}
+		return data
+	}
+	return &model.RowChangedEvent{
+		CommitTs:   commitTs,
+		TableInfo:  tableInfo,
+		PreColumns: columns(pre),
+		Columns:    columns(post),
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	tableInfo := helper.DDL2Event(
+		"create table test.t(id int primary key, v varchar(16), e enum('x', 'y'), b blob)").TableInfo
+	noKeyTableInfo := helper.DDL2Event("create table test.t2(id int, v varchar(16))").TableInfo
+
+	enc := &encoder{}
+	enc.encodeHeader(tableInfo)
+	for _, row := range []*model.RowChangedEvent{
+		newRow(tableInfo, 100, nil, []interface{}{int64(1), "a", uint64(1), []byte("a\x00b")}),
+		newRow(tableInfo, 100, nil, []interface{}{int64(2), `a"b\c`, nil, nil}),
+		newRow(tableInfo, 110,
+			[]interface{}{int64(1), "a", uint64(1), []byte("a\x00b")},
+			[]interface{}{int64(1), "b", uint64(2), nil}),
+		newRow(tableInfo, 120, []interface{}{int64(2), `a"b\c`, nil, nil}, nil),
+	} {
+		require.NoError(t, enc.encodeRow(row))
+	}
+	require.Equal(t, `"id","v","e","b"
+1,"a","x","a\0b"
+2,"a\"b\\c",\N,\N
+1,\N,\N,\N
+1,"b","y",\N
+2,\N,\N,\N
+`, string(enc.bytes()))
+
+	// The values of all the columns are kept in the deletions of the table
+	// without handle key.
+	enc = &encoder{}
+	require.NoError(t, enc.encodeRow(newRow(noKeyTableInfo, 100, []interface{}{int64(1), "a"}, nil)))
+	require.Equal(t, "1,\"a\"\n", string(enc.bytes()))
+}
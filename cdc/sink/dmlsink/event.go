@@ -16,6 +16,7 @@ package dmlsink
 import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
 // TableEvent is the interface for events which can be written to sink by TableSink.
@@ -47,3 +48,12 @@ type RowChangeCallbackableEvent = CallbackableEvent[*model.RowChangedEvent]
 
 // TxnCallbackableEvent is the txn event which can be callbacked.
 type TxnCallbackableEvent = CallbackableEvent[*model.SingleTableTxn]
+
+// CheckRow returns an error if the row can't be written by the sinks which
+// build the writes from the table info of the row.
+func CheckRow(row *model.RowChangedEvent) error {
+	if row == nil || row.TableInfo == nil {
+		return cerror.ErrUnexpected.GenWithStackByArgs("row changed event without table info")
+	}
+	return nil
+}
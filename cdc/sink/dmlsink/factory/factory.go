@@ -23,9 +23,12 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/blackhole"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/cloudstorage"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/csv"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/iceberg"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/sampling"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -51,6 +54,10 @@ const (
 	CategoryCloudStorage = 3
 	// CategoryBlackhole is for Blackhole sink.
 	CategoryBlackhole = 4
+	// CategoryIceberg is for Iceberg sink.
+	CategoryIceberg = 5
+	// CategoryCSV is for CSV sink.
+	CategoryCSV = 6
 )
 
 // SinkFactory is the factory of sink.
@@ -62,6 +69,9 @@ type SinkFactory struct {
 	rowSink  dmlsink.EventSink[*model.RowChangedEvent]
 	txnSink  dmlsink.EventSink[*model.SingleTableTxn]
 	category Category
+	// sampler samples the events written to the table sinks, it's nil if
+	// the sampling mode is disabled.
+	sampler *sampling.Sampler
 }
 
 // New creates a new SinkFactory by schema.
@@ -89,6 +99,46 @@ func New(
 		}
 		s.txnSink = txnSink
 		s.category = CategoryTxn
+	case sink.PostgresScheme, sink.PostgreSQLScheme:
+		txnSink, err := txn.NewPGSink(ctx, changefeedID, sinkURI, errCh,
+			txn.DefaultConflictDetectorSlots)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+		s.category = CategoryTxn
+	case sink.ClickHouseScheme:
+		txnSink, err := txn.NewClickHouseSink(ctx, changefeedID, sinkURI, errCh,
+			txn.DefaultConflictDetectorSlots)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+		s.category = CategoryTxn
+	case sink.ElasticsearchScheme, sink.ESScheme:
+		txnSink, err := txn.NewElasticsearchSink(ctx, changefeedID, sinkURI, cfg, errCh,
+			txn.DefaultConflictDetectorSlots)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+		s.category = CategoryTxn
+	case sink.RedisScheme:
+		txnSink, err := txn.NewRedisSink(ctx, changefeedID, sinkURI, errCh,
+			txn.DefaultConflictDetectorSlots)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+		s.category = CategoryTxn
+	case sink.MongoDBScheme, sink.MongoDBSRVScheme:
+		txnSink, err := txn.NewMongoDBSink(ctx, changefeedID, sinkURI, errCh,
+			txn.DefaultConflictDetectorSlots)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+		s.category = CategoryTxn
 	case sink.KafkaScheme, sink.KafkaSSLScheme:
 		factoryCreator := kafka.NewSaramaFactory
 		if util.GetOrZero(cfg.Sink.EnableKafkaSinkV2) {
@@ -108,6 +158,20 @@ func New(
 		}
 		s.txnSink = storageSink
 		s.category = CategoryCloudStorage
+	case sink.IcebergScheme:
+		icebergSink, err := iceberg.NewDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = icebergSink
+		s.category = CategoryIceberg
+	case sink.CSVScheme:
+		csvSink, err := csv.NewDMLSink(ctx, changefeedID, sinkURI, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = csvSink
+		s.category = CategoryCSV
 	case sink.BlackHoleScheme:
 		bs := blackhole.NewDMLSink()
 		s.rowSink = bs
@@ -126,6 +190,13 @@ func New(
 			cerror.ErrSinkURIInvalid.GenWithStack("the sink scheme (%s) is not supported", schema)
 	}
 
+	if cfg.SamplingMode != nil && cfg.SamplingMode.Enabled {
+		s.sampler, err = sampling.NewSampler(ctx, changefeedID, cfg.SamplingMode)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
 	return s, nil
 }
 
@@ -139,11 +210,19 @@ func (s *SinkFactory) CreateTableSink(
 	flushLagDuration prometheus.Observer,
 ) tablesink.TableSink {
 	if s.txnSink != nil {
-		return tablesink.New(changefeedID, span, startTs, s.txnSink,
+		var txnSink dmlsink.EventSink[*model.SingleTableTxn] = s.txnSink
+		if s.sampler != nil {
+			txnSink = sampling.NewDMLSink(txnSink, s.sampler)
+		}
+		return tablesink.New(changefeedID, span, startTs, txnSink,
 			&dmlsink.TxnEventAppender{TableSinkStartTs: startTs}, PDClock, totalRowsCounter, flushLagDuration)
 	}
 
-	return tablesink.New(changefeedID, span, startTs, s.rowSink,
+	var rowSink dmlsink.EventSink[*model.RowChangedEvent] = s.rowSink
+	if s.sampler != nil {
+		rowSink = sampling.NewDMLSink(rowSink, s.sampler)
+	}
+	return tablesink.New(changefeedID, span, startTs, rowSink,
 		&dmlsink.RowChangeEventAppender{}, PDClock, totalRowsCounter, flushLagDuration)
 }
 
@@ -182,6 +261,29 @@ func (s *SinkFactory) Close() {
 	if s.txnSink != nil {
 		s.txnSink.Close()
 	}
+	if s.sampler != nil {
+		s.sampler.Close()
+	}
+}
+
+// GetGTIDExecuted returns the latest gtid_executed of the downstream.
+// It returns an empty string if the sink is not a MySQL sink.
+func (s *SinkFactory) GetGTIDExecuted() string {
+	if g, ok := s.txnSink.(interface{ GetGTIDExecuted() string }); ok {
+		return g.GetGTIDExecuted()
+	}
+	return ""
+}
+
+// GetBinlogPosition returns the latest binlog position of the downstream.
+// It returns nil if the sink is not a MySQL sink.
+func (s *SinkFactory) GetBinlogPosition() *model.BinlogPosition {
+	if g, ok := s.txnSink.(interface {
+		GetBinlogPosition() *model.BinlogPosition
+	}); ok {
+		return g.GetBinlogPosition()
+	}
+	return nil
 }
 
 // Category returns category of s.
@@ -0,0 +1,244 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+	piceberg "github.com/pingcap/tiflow/pkg/sink/iceberg"
+)
+
+// partitionRows are the rows of a data file or a delete file.
+type partitionRows struct {
+	partition []interface{}
+	rows      [][]interface{}
+	// keys are the keys of the rows, which are used to remove the rows.
+	keys []string
+}
+
+func (p *partitionRows) append(key string, row []interface{}) {
+	p.rows = append(p.rows, row)
+	p.keys = append(p.keys, key)
+}
+
+// remove removes the first row with the key, it returns false if there is
+// no such row.
+func (p *partitionRows) remove(key string) bool {
+	for i, k := range p.keys {
+		if k == key {
+			p.rows = append(p.rows[:i], p.rows[i+1:]...)
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// changeSet is the changes of a table in an epoch, the rows are grouped
+// by the partitions.
+type changeSet struct {
+	table  *piceberg.Table
+	schema *piceberg.Schema
+	// keyIDs are the field IDs of the equality fields, which are the handle
+	// key columns, or all the columns if the table has no handle key.
+	keyIDs     []int
+	keyIndexes []int
+
+	inserts map[string]*partitionRows
+	deletes map[string]*partitionRows
+	// the partition of the inserted row of each key.
+	insertedKeys map[string]string
+	deletedKeys  map[string]struct{}
+	maxCommitTs  uint64
+}
+
+func newChangeSet(table *piceberg.Table, schema *piceberg.Schema) *changeSet {
+	c := &changeSet{
+		table:        table,
+		schema:       schema,
+		keyIDs:       schema.IdentifierFieldIDs,
+		inserts:      make(map[string]*partitionRows),
+		deletes:      make(map[string]*partitionRows),
+		insertedKeys: make(map[string]string),
+		deletedKeys:  make(map[string]struct{}),
+	}
+	if len(c.keyIDs) == 0 {
+		for _, field := range schema.Fields {
+			c.keyIDs = append(c.keyIDs, field.ID)
+		}
+	}
+	for _, id := range c.keyIDs {
+		for i, field := range schema.Fields {
+			if field.ID == id {
+				c.keyIndexes = append(c.keyIndexes, i)
+			}
+		}
+	}
+	return c
+}
+
+func (c *changeSet) hasHandleKey() bool {
+	return len(c.schema.IdentifierFieldIDs) > 0
+}
+
+// keyFields returns the fields of the equality delete files.
+func (c *changeSet) keyFields() []*piceberg.Field {
+	fields := make([]*piceberg.Field, 0, len(c.keyIndexes))
+	for _, i := range c.keyIndexes {
+		fields = append(fields, c.schema.Fields[i])
+	}
+	return fields
+}
+
+// addRow collapses a row change to the change set.
+//
+// For the tables with handle key, the rows are upserted by the keys: the key
+// of every changed row is written to the equality delete files, and only the
+// last version of each key is written to the data files. Since the delete
+// files only apply to the data files committed before, replaying the events
+// is idempotent.
+//
+// For the tables without handle key, all the columns are the equality
+// fields, the deleted rows remove the identical rows inserted in the same
+// epoch first, otherwise they are written to the equality delete files,
+// which remove all the identical rows in the table.
+func (c *changeSet) addRow(row *model.RowChangedEvent) error {
+	if row.CommitTs > c.maxCommitTs {
+		c.maxCommitTs = row.CommitTs
+	}
+	if len(row.PreColumns) > 0 {
+		values, err := c.convertRow(row.TableInfo, row.PreColumns)
+		if err != nil {
+			return err
+		}
+		key := c.key(values)
+		if c.hasHandleKey() {
+			if partition, ok := c.insertedKeys[key]; ok {
+				c.inserts[partition].remove(key)
+				delete(c.insertedKeys, key)
+			}
+			if err := c.addDelete(key, values); err != nil {
+				return err
+			}
+		} else if !c.removeInsert(key) {
+			if err := c.addDelete(key, values); err != nil {
+				return err
+			}
+		}
+	}
+	if len(row.Columns) > 0 {
+		values, err := c.convertRow(row.TableInfo, row.Columns)
+		if err != nil {
+			return err
+		}
+		key := c.key(values)
+		if c.hasHandleKey() {
+			if partition, ok := c.insertedKeys[key]; ok {
+				c.inserts[partition].remove(key)
+			}
+			if err := c.addDelete(key, values); err != nil {
+				return err
+			}
+		}
+		partition, err := c.table.PartitionValues(c.schema, values)
+		if err != nil {
+			return err
+		}
+		partitionKey := piceberg.Key(partition)
+		rows, ok := c.inserts[partitionKey]
+		if !ok {
+			rows = &partitionRows{partition: partition}
+			c.inserts[partitionKey] = rows
+		}
+		rows.append(key, values)
+		if c.hasHandleKey() {
+			c.insertedKeys[key] = partitionKey
+		}
+	}
+	return nil
+}
+
+// removeInsert removes a row inserted in the epoch of a table without
+// handle key.
+func (c *changeSet) removeInsert(key string) bool {
+	for _, rows := range c.inserts {
+		if rows.remove(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// addDelete adds the key of a row to the delete file of its partition,
+// since the equality delete files only apply to the data files in the
+// same partition.
+func (c *changeSet) addDelete(key string, values []interface{}) error {
+	partition, err := c.table.PartitionValues(c.schema, values)
+	if err != nil {
+		return err
+	}
+	partitionKey := piceberg.Key(partition)
+	deleteKey := partitionKey + key
+	if _, ok := c.deletedKeys[deleteKey]; ok {
+		return nil
+	}
+	c.deletedKeys[deleteKey] = struct{}{}
+	rows, ok := c.deletes[partitionKey]
+	if !ok {
+		rows = &partitionRows{partition: partition}
+		c.deletes[partitionKey] = rows
+	}
+	keyValues := make([]interface{}, 0, len(c.keyIndexes))
+	for _, i := range c.keyIndexes {
+		keyValues = append(keyValues, values[i])
+	}
+	rows.append(key, keyValues)
+	return nil
+}
+
+func (c *changeSet) key(values []interface{}) string {
+	keyValues := make([]interface{}, 0, len(c.keyIndexes))
+	for _, i := range c.keyIndexes {
+		keyValues = append(keyValues, values[i])
+	}
+	return piceberg.Key(keyValues)
+}
+
+// convertRow converts the columns of a row to the values of the fields of
+// the schema, the missing columns are null.
+func (c *changeSet) convertRow(tableInfo *model.TableInfo, data []*model.ColumnData) ([]interface{}, error) {
+	values := make([]interface{}, len(c.schema.Fields))
+	indexes := make(map[int]int, len(c.schema.Fields))
+	for i, field := range c.schema.Fields {
+		indexes[field.ID] = i
+	}
+	for _, col := range data {
+		if col == nil {
+			continue
+		}
+		i, ok := indexes[int(col.ColumnID)]
+		if !ok {
+			continue
+		}
+		colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+		if !ok {
+			continue
+		}
+		value, err := piceberg.ConvertValue(&colInfo.FieldType, col.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
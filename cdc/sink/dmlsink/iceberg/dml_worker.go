@@ -0,0 +1,204 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/chann"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	piceberg "github.com/pingcap/tiflow/pkg/sink/iceberg"
+	"go.uber.org/zap"
+)
+
+const defaultMaxRetry uint64 = 20
+
+// epoch is the transactions of a table written by a WriteEvents call, which
+// are all the transactions of a table span up to a resolved ts.
+type epoch struct {
+	txns []*dmlsink.TxnCallbackableEvent
+}
+
+// dmlWorker commits the epochs of the tables to Iceberg. The epochs of a
+// table are always committed by the same worker, so that the snapshots of
+// a table are committed one by one.
+type dmlWorker struct {
+	id           int
+	changefeedID model.ChangeFeedID
+	catalog      *piceberg.Catalog
+	config       *piceberg.Config
+	inputCh      *chann.DrainableChann[*epoch]
+	statistics   *metrics.Statistics
+}
+
+func newDMLWorker(
+	id int,
+	changefeedID model.ChangeFeedID,
+	catalog *piceberg.Catalog,
+	config *piceberg.Config,
+	inputCh *chann.DrainableChann[*epoch],
+	statistics *metrics.Statistics,
+) *dmlWorker {
+	return &dmlWorker{
+		id:           id,
+		changefeedID: changefeedID,
+		catalog:      catalog,
+		config:       config,
+		inputCh:      inputCh,
+		statistics:   statistics,
+	}
+}
+
+func (w *dmlWorker) run(ctx context.Context) error {
+	log.Info("iceberg dml worker started",
+		zap.Int("workerID", w.id),
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID))
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case e, ok := <-w.inputCh.Out():
+			if !ok {
+				return nil
+			}
+			if err := w.writeEpoch(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeEpoch commits a snapshot for the transactions of each table info
+// version in the epoch, and then calls the callbacks of the transactions.
+func (w *dmlWorker) writeEpoch(ctx context.Context, e *epoch) error {
+	for start := 0; start < len(e.txns); {
+		end := start + 1
+		version := e.txns[start].Event.TableInfo.Version
+		for end < len(e.txns) && e.txns[end].Event.TableInfo.Version == version {
+			end++
+		}
+		if err := w.commit(ctx, e.txns[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	for _, txn := range e.txns {
+		txn.Callback()
+	}
+	return nil
+}
+
+// commit commits the transactions with the same table info as a snapshot.
+func (w *dmlWorker) commit(ctx context.Context, txns []*dmlsink.TxnCallbackableEvent) error {
+	tableInfo := txns[0].Event.TableInfo
+	schemaName, tableName := tableInfo.GetSchemaName(), tableInfo.GetTableName()
+	return retry.Do(ctx, func() error {
+		err := w.statistics.RecordBatchExecution(func() (int, int64, error) {
+			return w.doCommit(ctx, schemaName, tableName, txns)
+		})
+		if err != nil {
+			log.Warn("iceberg commit failed, retry later",
+				zap.String("namespace", w.changefeedID.Namespace),
+				zap.String("changefeed", w.changefeedID.ID),
+				zap.String("schema", schemaName),
+				zap.String("table", tableName),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func (w *dmlWorker) doCommit(
+	ctx context.Context, schemaName, tableName string, txns []*dmlsink.TxnCallbackableEvent,
+) (int, int64, error) {
+	tableInfo := txns[0].Event.TableInfo
+	// The table is always loaded again, since it may be committed by the
+	// DDL sink, e.g. truncated.
+	table, err := w.catalog.LoadTable(ctx, schemaName, tableName)
+	if err != nil {
+		return 0, 0, err
+	}
+	if table == nil {
+		table, err = w.catalog.CreateTable(ctx, schemaName, tableName,
+			piceberg.NewSchema(tableInfo), w.config.PartitionFields(schemaName, tableName))
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	changes := newChangeSet(table, piceberg.NewSchema(tableInfo))
+	rowCount := 0
+	for _, txn := range txns {
+		for _, row := range txn.Event.Rows {
+			if err := changes.addRow(row); err != nil {
+				return 0, 0, err
+			}
+			rowCount++
+		}
+	}
+	if rowCount == 0 {
+		return 0, 0, nil
+	}
+
+	files := make([]*piceberg.DataFile, 0, len(changes.inserts)+len(changes.deletes))
+	var writeBytes int64
+	for _, rows := range changes.deletes {
+		file, err := table.WriteDataFile(ctx, changes.keyFields(), rows.rows,
+			rows.partition, piceberg.ContentEqualityDeletes, changes.keyIDs)
+		if err != nil {
+			return 0, 0, err
+		}
+		files = append(files, file)
+		writeBytes += file.FileSize
+	}
+	for _, rows := range changes.inserts {
+		if len(rows.rows) == 0 {
+			continue
+		}
+		file, err := table.WriteDataFile(ctx, changes.schema.Fields, rows.rows,
+			rows.partition, piceberg.ContentData, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		files = append(files, file)
+		writeBytes += file.FileSize
+	}
+	if err := table.Commit(ctx, changes.schema, files, changes.maxCommitTs, false); err != nil {
+		return 0, 0, err
+	}
+	log.Debug("iceberg snapshot committed",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("schema", schemaName),
+		zap.String("table", tableName),
+		zap.Uint64("commitTs", changes.maxCommitTs),
+		zap.Int("files", len(files)))
+	return rowCount, writeBytes, nil
+}
+
+func (w *dmlWorker) close() {
+	w.inputCh.CloseAndDrain()
+}
@@ -0,0 +1,203 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/hash"
+	"github.com/pingcap/tiflow/pkg/sink"
+	piceberg "github.com/pingcap/tiflow/pkg/sink/iceberg"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Assert EventSink[E event.TableEvent] implementation
+var _ dmlsink.EventSink[*model.SingleTableTxn] = (*DMLSink)(nil)
+
+// DMLSink is the Iceberg sink.
+// The events of a WriteEvents call, which are all the events of a table span
+// up to a resolved ts, are committed as a snapshot of the Iceberg table, so
+// that the snapshots can be queried by time travel as of the commit ts of
+// the upstream. The tables are dispatched to the workers by the names, and
+// the workers commit the tables in parallel.
+type DMLSink struct {
+	changefeedID model.ChangeFeedID
+	scheme       string
+	workers      []*dmlWorker
+
+	alive struct {
+		sync.RWMutex
+		isDead bool
+	}
+
+	statistics *metrics.Statistics
+
+	cancel func()
+	wg     sync.WaitGroup
+	dead   chan struct{}
+}
+
+// NewDMLSink creates an Iceberg sink.
+func NewDMLSink(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+) (*DMLSink, error) {
+	cfg := piceberg.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	storage, err := putil.GetExternalStorageFromURI(ctx, cfg.StorageURI)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := piceberg.NewCatalog(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	wgCtx, wgCancel := context.WithCancel(ctx)
+	s := &DMLSink{
+		changefeedID: changefeedID,
+		scheme:       strings.ToLower(sinkURI.Scheme),
+		workers:      make([]*dmlWorker, cfg.WorkerCount),
+		statistics:   metrics.NewStatistics(wgCtx, changefeedID, sink.TxnSink),
+		cancel:       wgCancel,
+		dead:         make(chan struct{}),
+	}
+	for i := 0; i < cfg.WorkerCount; i++ {
+		s.workers[i] = newDMLWorker(i, changefeedID, catalog, cfg,
+			chann.NewAutoDrainChann[*epoch](), s.statistics)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.run(wgCtx)
+
+		s.alive.Lock()
+		s.alive.isDead = true
+		s.alive.Unlock()
+		close(s.dead)
+
+		if err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case <-wgCtx.Done():
+			case errCh <- err:
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+func (s *DMLSink) run(ctx context.Context) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < len(s.workers); i++ {
+		worker := s.workers[i]
+		eg.Go(func() error {
+			return worker.run(ctx)
+		})
+	}
+
+	log.Info("iceberg dml sink started", zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Int("workerCount", len(s.workers)))
+
+	return eg.Wait()
+}
+
+// WriteEvents writes events to the Iceberg sink.
+func (s *DMLSink) WriteEvents(txns ...*dmlsink.CallbackableEvent[*model.SingleTableTxn]) error {
+	s.alive.RLock()
+	defer s.alive.RUnlock()
+	if s.alive.isDead {
+		return errors.Trace(errors.New("dead dmlSink"))
+	}
+
+	epochs := make(map[model.TableName]*epoch)
+	var tables []model.TableName
+	for _, txn := range txns {
+		if txn.GetTableSinkState() != state.TableSinkSinking {
+			// The table where the event comes from is in stopping, so it's safe
+			// to drop the event directly.
+			txn.Callback()
+			continue
+		}
+		tbl := model.TableName{
+			Schema: txn.Event.TableInfo.GetSchemaName(),
+			Table:  txn.Event.TableInfo.GetTableName(),
+		}
+		e, ok := epochs[tbl]
+		if !ok {
+			e = &epoch{}
+			epochs[tbl] = e
+			tables = append(tables, tbl)
+		}
+		s.statistics.ObserveRows(txn.Event.Rows...)
+		e.txns = append(e.txns, txn)
+	}
+
+	hasher := hash.NewPositionInertia()
+	for _, tbl := range tables {
+		// The partitions of a partitioned table are dispatched to the same
+		// worker, since they are committed to the same Iceberg table.
+		hasher.Reset()
+		hasher.Write([]byte(tbl.Schema), []byte(tbl.Table))
+		workerID := hasher.Sum32() % uint32(len(s.workers))
+		s.workers[workerID].inputCh.In() <- epochs[tbl]
+	}
+	return nil
+}
+
+// Close closes the Iceberg sink.
+func (s *DMLSink) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	for _, worker := range s.workers {
+		worker.close()
+	}
+
+	if s.statistics != nil {
+		s.statistics.Close()
+	}
+}
+
+// Dead checks whether it's dead or not.
+func (s *DMLSink) Dead() <-chan struct{} {
+	return s.dead
+}
+
+// Scheme returns the sink scheme.
+func (s *DMLSink) Scheme() string {
+	return s.scheme
+}
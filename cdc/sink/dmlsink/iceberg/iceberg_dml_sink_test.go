@@ -0,0 +1,212 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	piceberg "github.com/pingcap/tiflow/pkg/sink/iceberg"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newRow creates a row changed event, the values are in the order of the
+// columns, nil pre or post means an insert or a delete.
+func newRow(tableInfo *model.TableInfo, commitTs uint64, pre, post []interface{}) *model.RowChangedEvent {
+	columns := func(values []interface{}) []*model.ColumnData {
+		if values == nil {
+			return nil
+		}
+		data := make([]*model.ColumnData, 0, len(values))
+		for i, col := range tableInfo.Columns {
+			data = append(data, &model.ColumnData{ColumnID: col.ID, Value: values[i]})
+		}
+		return data
+	}
+	return &model.RowChangedEvent{
+		CommitTs:   commitTs,
+		TableInfo:  tableInfo,
+		PreColumns: columns(pre),
+		Columns:    columns(post),
+	}
+}
+
+func newTxns(
+	cnt *uint64, rows ...*model.RowChangedEvent,
+) []*dmlsink.TxnCallbackableEvent {
+	tableStatus := state.TableSinkSinking
+	var txns []*dmlsink.TxnCallbackableEvent
+	for _, row := range rows {
+		if len(txns) == 0 || txns[len(txns)-1].Event.CommitTs != row.CommitTs {
+			txns = append(txns, &dmlsink.TxnCallbackableEvent{
+				Event: &model.SingleTableTxn{
+					CommitTs:         row.CommitTs,
+					TableInfo:        row.TableInfo,
+					TableInfoVersion: row.TableInfo.Version,
+				},
+				Callback:  func() { atomic.AddUint64(cnt, 1) },
+				SinkState: &tableStatus,
+			})
+		}
+		txn := txns[len(txns)-1].Event
+		txn.Rows = append(txn.Rows, row)
+	}
+	return txns
+}
+
+func scan(
+	ctx context.Context, t *testing.T, catalog *piceberg.Catalog, table string, commitTs uint64,
+) []map[string]interface{} {
+	tbl, err := catalog.LoadTable(ctx, "test", table)
+	require.NoError(t, err)
+	require.NotNil(t, tbl)
+	rows, err := tbl.Scan(ctx, commitTs)
+	require.NoError(t, err)
+	return rows
+}
+
+// TestTimeTravel checks that the snapshot as of the commit ts of every
+// epoch contains the same rows as the upstream at the commit ts.
+func TestTimeTravel(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	tableInfo := helper.DDL2Event("create table test.t(id int primary key, v varchar(16))").TableInfo
+	noKeyTableInfo := helper.DDL2Event("create table test.t2(id int, v varchar(16))").TableInfo
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("iceberg://%s?storage=file&worker-count=2", dir))
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	// The rows are moved between the partitions when v is updated.
+	replicaConfig.Sink.IcebergPartitionBy = []*config.IcebergPartitionRule{
+		{Matcher: []string{"test.t"}, Fields: []string{"v"}},
+	}
+	errCh := make(chan error, 1)
+	s, err := NewDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, replicaConfig, errCh)
+	require.NoError(t, err)
+	defer s.Close()
+
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+dir)
+	require.NoError(t, err)
+	catalog, err := piceberg.NewCatalog(storage)
+	require.NoError(t, err)
+
+	var cnt, expected uint64
+	write := func(rows ...*model.RowChangedEvent) {
+		txns := newTxns(&cnt, rows...)
+		expected += uint64(len(txns))
+		require.NoError(t, s.WriteEvents(txns...))
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint64(&cnt) == expected
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	epochs := [][]*model.RowChangedEvent{
+		{
+			newRow(tableInfo, 100, nil, []interface{}{int64(1), "a"}),
+			newRow(tableInfo, 100, nil, []interface{}{int64(2), "b"}),
+			newRow(tableInfo, 110, nil, []interface{}{int64(3), "a"}),
+		},
+		{
+			newRow(tableInfo, 200, []interface{}{int64(1), "a"}, []interface{}{int64(1), "b"}),
+			newRow(tableInfo, 200, []interface{}{int64(2), "b"}, nil),
+			newRow(tableInfo, 200, nil, []interface{}{int64(4), "c"}),
+			newRow(tableInfo, 210, []interface{}{int64(4), "c"}, []interface{}{int64(4), "d"}),
+		},
+		{
+			newRow(tableInfo, 300, []interface{}{int64(1), "b"}, nil),
+			newRow(tableInfo, 310, nil, []interface{}{int64(1), "a"}),
+		},
+	}
+	for _, rows := range epochs {
+		write(rows...)
+	}
+	// Replaying an epoch doesn't change the table.
+	write(epochs[2]...)
+
+	require.Empty(t, scan(ctx, t, catalog, "t", 105))
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int32(1), "v": "a"},
+		{"id": int32(2), "v": "b"},
+		{"id": int32(3), "v": "a"},
+	}, scan(ctx, t, catalog, "t", 110))
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int32(1), "v": "b"},
+		{"id": int32(3), "v": "a"},
+		{"id": int32(4), "v": "d"},
+	}, scan(ctx, t, catalog, "t", 299))
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int32(1), "v": "a"},
+		{"id": int32(3), "v": "a"},
+		{"id": int32(4), "v": "d"},
+	}, scan(ctx, t, catalog, "t", 310))
+
+	// The table without handle key.
+	write(
+		newRow(noKeyTableInfo, 100, nil, []interface{}{int64(1), "a"}),
+		newRow(noKeyTableInfo, 100, nil, []interface{}{int64(1), "a"}),
+		newRow(noKeyTableInfo, 100, nil, []interface{}{int64(2), "b"}),
+		newRow(noKeyTableInfo, 110, []interface{}{int64(1), "a"}, nil),
+	)
+	write(newRow(noKeyTableInfo, 200, []interface{}{int64(2), "b"}, []interface{}{int64(2), "c"}))
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int32(1), "v": "a"},
+		{"id": int32(2), "v": "b"},
+	}, scan(ctx, t, catalog, "t2", 110))
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int32(1), "v": "a"},
+		{"id": int32(2), "v": "c"},
+	}, scan(ctx, t, catalog, "t2", 200))
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestWriteEventsOfStoppingTable(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(fmt.Sprintf("iceberg://%s?storage=file", t.TempDir()))
+	require.NoError(t, err)
+	s, err := NewDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		config.GetDefaultReplicaConfig(), make(chan error, 1))
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, "iceberg", s.Scheme())
+
+	var cnt uint64
+	tableStatus := state.TableSinkStopping
+	require.NoError(t, s.WriteEvents(&dmlsink.TxnCallbackableEvent{
+		Event:     &model.SingleTableTxn{},
+		Callback:  func() { atomic.AddUint64(&cnt, 1) },
+		SinkState: &tableStatus,
+	}))
+	require.Equal(t, uint64(1), atomic.LoadUint64(&cnt))
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec/kafkastreams"
+	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
 
@@ -52,6 +54,15 @@ func NewEventRouter(
 		TopicRule:     "",
 	})
 
+	// Kafka Streams changelog topics must follow the naming convention,
+	// so the topic rules are overridden.
+	changelog := cfg.Sink.KafkaConfig != nil &&
+		util.GetOrZero(cfg.Sink.KafkaConfig.KafkaStreamsChangelog)
+	if changelog && protocol != config.ProtocolKafkaStreams {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"kafka-streams-changelog requires the kafka-streams protocol, but got %s", protocol)
+	}
+
 	rules := make([]struct {
 		partitionDispatcher partition.Dispatcher
 		topicDispatcher     topic.Dispatcher
@@ -70,7 +81,11 @@ func NewEventRouter(
 		d := getPartitionDispatcher(
 			ruleConfig.PartitionRule, scheme, ruleConfig.IndexName, ruleConfig.Columns,
 		)
-		t, err := getTopicDispatcher(ruleConfig.TopicRule, defaultTopic, protocol, scheme)
+		topicRule := ruleConfig.TopicRule
+		if changelog {
+			topicRule = kafkastreams.TopicExpression(defaultTopic)
+		}
+		t, err := getTopicDispatcher(topicRule, defaultTopic, protocol, scheme)
 		if err != nil {
 			return nil, err
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher/topic"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -195,6 +196,31 @@ func TestGetTopicForRowChange(t *testing.T) {
 	require.Equal(t, "a_table", topicName)
 }
 
+func TestGetTopicForKafkaStreamsChangelog(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := newReplicaConfig4DispatcherTest()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		KafkaStreamsChangelog: util.AddressOf(true),
+	}
+	_, err := NewEventRouter(replicaConfig, config.ProtocolCanalJSON, "app", "kafka")
+	require.ErrorContains(t, err, "kafka-streams-changelog requires the kafka-streams protocol")
+
+	d, err := NewEventRouter(replicaConfig, config.ProtocolKafkaStreams, "app", "kafka")
+	require.NoError(t, err)
+	// The topic rules are overridden by the changelog topic convention.
+	for _, schema := range []string{"test_default1", "test_table", "hard_code_schema", "a"} {
+		topicName := d.GetTopicForRowChange(&model.RowChangedEvent{
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: schema, Table: "t1"},
+			},
+		})
+		require.Equal(t, "app-"+schema+".t1-changelog", topicName)
+	}
+	// The partition rules are kept.
+	require.IsType(t, &partition.TableDispatcher{}, d.GetPartitionDispatcher("test_table", "t1"))
+}
+
 func TestGetPartitionForRowChange(t *testing.T) {
 	t.Parallel()
 
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
 	"go.uber.org/zap"
 )
 
@@ -60,7 +61,8 @@ type pulsarDMLProducer struct {
 	// closeCh is send error
 	errChan chan error
 
-	pConfig *config.PulsarConfig
+	pConfig        *config.PulsarConfig
+	producerConfig *config.PulsarProducerConfig
 }
 
 // NewPulsarDMLProducer creates a new pulsar producer.
@@ -86,7 +88,8 @@ func NewPulsarDMLProducer(
 
 	pulsarConfig = sinkConfig.PulsarConfig
 	defaultTopicName := pulsarConfig.GetDefaultTopicName()
-	defaultProducer, err := newProducer(pulsarConfig, client, defaultTopicName)
+	defaultProducer, err := newProducer(
+		pulsarConfig, sinkConfig.PulsarProducerConfig, client, defaultTopicName)
 	if err != nil {
 		go client.Close()
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
@@ -111,13 +114,14 @@ func NewPulsarDMLProducer(
 	producers.Add(defaultTopicName, defaultProducer)
 
 	p := &pulsarDMLProducer{
-		id:          changefeedID,
-		client:      client,
-		producers:   producers,
-		pConfig:     pulsarConfig,
-		closed:      false,
-		failpointCh: failpointCh,
-		errChan:     errCh,
+		id:             changefeedID,
+		client:         client,
+		producers:      producers,
+		pConfig:        pulsarConfig,
+		producerConfig: sinkConfig.PulsarProducerConfig,
+		closed:         false,
+		failpointCh:    failpointCh,
+		errChan:        errCh,
 	}
 	log.Info("Pulsar DML producer created", zap.Stringer("changefeed", p.id),
 		zap.Duration("duration", time.Since(start)))
@@ -226,26 +230,11 @@ func (p *pulsarDMLProducer) Close() {
 // One topic is used by one producer
 func newProducer(
 	pConfig *config.PulsarConfig,
+	producerConfig *config.PulsarProducerConfig,
 	client pulsar.Client,
 	topicName string,
 ) (pulsar.Producer, error) {
-	po := pulsar.ProducerOptions{
-		Topic: topicName,
-	}
-	if pConfig.BatchingMaxMessages != nil {
-		po.BatchingMaxMessages = *pConfig.BatchingMaxMessages
-	}
-	if pConfig.BatchingMaxPublishDelay != nil {
-		po.BatchingMaxPublishDelay = pConfig.BatchingMaxPublishDelay.Duration()
-	}
-	if pConfig.CompressionType != nil {
-		po.CompressionType = pConfig.CompressionType.Value()
-		po.CompressionLevel = pulsar.Default
-	}
-	if pConfig.SendTimeout != nil {
-		po.SendTimeout = pConfig.SendTimeout.Duration()
-	}
-
+	po := pulsarConfig.NewProducerOptions(topicName, pConfig, producerConfig)
 	producer, err := client.CreateProducer(po)
 	if err != nil {
 		return nil, err
@@ -277,7 +266,7 @@ func (p *pulsarDMLProducer) GetProducerByTopic(topicName string) (producer pulsa
 	}
 
 	if !ok { // create a new producer for the topicName
-		producer, err = newProducer(p.pConfig, p.client, topicName)
+		producer, err = newProducer(p.pConfig, p.producerConfig, p.client, topicName)
 		if err != nil {
 			return nil, err
 		}
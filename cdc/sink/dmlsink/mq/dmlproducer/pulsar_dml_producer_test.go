@@ -15,15 +15,21 @@ package dmlproducer
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/url"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 // newPulsarConfig set config
@@ -101,3 +107,56 @@ func Test_pulsarDMLProducer_AsyncSendMessage(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+// BenchmarkPulsarProducerBatching compares the throughput of pulsar producers
+// with and without batching at 10K events/s.
+// It requires a pulsar broker listening on 127.0.0.1:6650.
+func BenchmarkPulsarProducerBatching(b *testing.B) {
+	const (
+		brokerAddr   = "127.0.0.1:6650"
+		eventsPerSec = 10000
+	)
+	conn, err := net.DialTimeout("tcp", brokerAddr, time.Second)
+	if err != nil {
+		b.Skipf("pulsar broker is not available: %s", err)
+	}
+	_ = conn.Close()
+
+	for _, batching := range []bool{true, false} {
+		b.Run(fmt.Sprintf("batching=%t", batching), func(b *testing.B) {
+			client, err := pulsar.NewClient(pulsar.ClientOptions{URL: "pulsar://" + brokerAddr})
+			require.NoError(b, err)
+			defer client.Close()
+
+			po := pulsarConfig.NewProducerOptions(
+				"persistent://public/default/ticdc-producer-bench",
+				&config.PulsarConfig{},
+				&config.PulsarProducerConfig{BatchingEnabled: aws.Bool(batching)})
+			producer, err := client.CreateProducer(po)
+			require.NoError(b, err)
+			defer producer.Close()
+
+			ctx := context.Background()
+			limiter := rate.NewLimiter(rate.Limit(eventsPerSec), eventsPerSec/100)
+			payload := make([]byte, 256)
+			var wg sync.WaitGroup
+
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, limiter.Wait(ctx))
+				wg.Add(1)
+				producer.SendAsync(ctx, &pulsar.ProducerMessage{Payload: payload},
+					func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+						if err != nil {
+							b.Error(err)
+						}
+						wg.Done()
+					})
+			}
+			require.NoError(b, producer.Flush())
+			wg.Wait()
+			b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "events/s")
+		})
+	}
+}
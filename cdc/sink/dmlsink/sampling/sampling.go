@@ -0,0 +1,240 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+)
+
+// sample is a line written to the output of the sampler.
+type sample struct {
+	Namespace  string          `json:"namespace"`
+	Changefeed string          `json:"changefeed"`
+	CommitTs   uint64          `json:"commitTs"`
+	SampledAt  time.Time       `json:"sampledAt"`
+	Event      json.RawMessage `json:"event"`
+}
+
+// Sampler writes a sampled fraction of the row changed events in canal-json
+// format to a file. The rows are sampled by the hash of their handle keys, so
+// the changes of the same row are always sampled together.
+type Sampler struct {
+	changefeedID model.ChangeFeedID
+	rate         float64
+
+	mu      sync.Mutex
+	encoder codec.RowEventEncoder
+	out     io.Writer
+	// file is nil if the samples are written to stdout.
+	file *os.File
+}
+
+// NewSampler creates a Sampler by the sampling mode config.
+func NewSampler(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	cfg *config.SamplingModeConfig,
+) (*Sampler, error) {
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON).WithChangefeedID(changefeedID)
+	codecConfig.EnableTiDBExtension = true
+	builder, err := canal.NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s := &Sampler{
+		changefeedID: changefeedID,
+		rate:         cfg.SampleRate,
+		encoder:      builder.Build(),
+		out:          os.Stdout,
+	}
+	if cfg.OutputFile != "" {
+		s.file, err = os.OpenFile(cfg.OutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s.out = s.file
+	}
+	log.Info("Event sampling is enabled",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Float64("sampleRate", cfg.SampleRate),
+		zap.String("outputFile", cfg.OutputFile))
+	return s, nil
+}
+
+// ShouldSample returns true if the row is sampled.
+func (s *Sampler) ShouldSample(row *model.RowChangedEvent) bool {
+	if s.rate <= 0 {
+		return false
+	}
+	if s.rate >= 1 {
+		return true
+	}
+	return float64(rowHash(row)) < s.rate*math.MaxUint64
+}
+
+// rowHash hashes the table and the handle key of the row. All the column
+// values are hashed if the table has no handle key.
+func rowHash(row *model.RowChangedEvent) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(row.TableInfo.GetSchemaName()))
+	h.Write([]byte{0})
+	h.Write([]byte(row.TableInfo.GetTableName()))
+	values := row.GetHandleKeyColumnValues()
+	if len(values) == 0 {
+		cols := row.Columns
+		if row.IsDelete() {
+			cols = row.PreColumns
+		}
+		for _, col := range cols {
+			if col != nil {
+				values = append(values, model.ColumnValueString(col.Value))
+			}
+		}
+	}
+	for _, value := range values {
+		h.Write([]byte{0})
+		h.Write([]byte(value))
+	}
+	return fmix64(h.Sum64())
+}
+
+// fmix64 is the finalizer of MurmurHash3. The high bits of fnv hashes of
+// similar keys are poorly distributed, so they are mixed before the hash is
+// compared against the sample rate.
+func fmix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// Sample writes the row to the output if it's sampled.
+func (s *Sampler) Sample(ctx context.Context, row *model.RowChangedEvent) error {
+	if !s.ShouldSample(row) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.encoder.AppendRowChangedEvent(ctx, "", row, nil); err != nil {
+		return errors.Trace(err)
+	}
+	for _, msg := range s.encoder.Build() {
+		line, err := json.Marshal(&sample{
+			Namespace:  s.changefeedID.Namespace,
+			Changefeed: s.changefeedID.ID,
+			CommitTs:   row.CommitTs,
+			SampledAt:  time.Now(),
+			Event:      msg.Value,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := s.out.Write(append(line, '\n')); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Close closes the output file.
+func (s *Sampler) Close() {
+	if s.file == nil {
+		return
+	}
+	if err := s.file.Close(); err != nil {
+		log.Warn("Close the output file of event sampling failed",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Error(err))
+	}
+}
+
+// Assert EventSink[E event.TableEvent] implementation
+var (
+	_ dmlsink.EventSink[*model.RowChangedEvent] = (*DMLSink[*model.RowChangedEvent])(nil)
+	_ dmlsink.EventSink[*model.SingleTableTxn]  = (*DMLSink[*model.SingleTableTxn])(nil)
+)
+
+// DMLSink wraps a DML sink of a table sink, and samples the events before
+// they are written to the wrapped sink.
+type DMLSink[E dmlsink.TableEvent] struct {
+	sink    dmlsink.EventSink[E]
+	sampler *Sampler
+}
+
+// NewDMLSink creates a DMLSink which samples the events written to sink.
+func NewDMLSink[E dmlsink.TableEvent](sink dmlsink.EventSink[E], sampler *Sampler) *DMLSink[E] {
+	return &DMLSink[E]{sink: sink, sampler: sampler}
+}
+
+// WriteEvents samples the events and writes them to the wrapped sink.
+// Sampling is best effort, the errors are logged and don't fail the sink.
+func (s *DMLSink[E]) WriteEvents(events ...*dmlsink.CallbackableEvent[E]) error {
+	for _, event := range events {
+		for _, row := range rowsOf(event.Event) {
+			if err := s.sampler.Sample(context.Background(), row); err != nil {
+				log.Warn("Sample the row changed event failed",
+					zap.String("namespace", s.sampler.changefeedID.Namespace),
+					zap.String("changefeed", s.sampler.changefeedID.ID),
+					zap.Error(err))
+			}
+		}
+	}
+	return s.sink.WriteEvents(events...)
+}
+
+func rowsOf(event dmlsink.TableEvent) []*model.RowChangedEvent {
+	switch e := event.(type) {
+	case *model.RowChangedEvent:
+		return []*model.RowChangedEvent{e}
+	case *model.SingleTableTxn:
+		return e.Rows
+	}
+	return nil
+}
+
+// Scheme returns the scheme of the wrapped sink.
+func (s *DMLSink[E]) Scheme() string {
+	return s.sink.Scheme()
+}
+
+// Close does nothing, the wrapped sink and the sampler are shared by all the
+// table sinks, they are closed by their owner.
+func (s *DMLSink[E]) Close() {}
+
+// Dead returns the checker of the wrapped sink.
+func (s *DMLSink[E]) Dead() <-chan struct{} {
+	return s.sink.Dead()
+}
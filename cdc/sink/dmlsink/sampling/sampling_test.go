@@ -0,0 +1,156 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/blackhole"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+var testTableInfo = model.BuildTableInfo("test", "t", []*model.Column{{
+	Name: "id",
+	Type: mysql.TypeLong,
+	Flag: model.PrimaryKeyFlag | model.HandleKeyFlag,
+}, {
+	Name: "name",
+	Type: mysql.TypeVarchar,
+}}, [][]int{{0}})
+
+func newTestRow(id int64, name string) []*model.ColumnData {
+	return model.Columns2ColumnDatas([]*model.Column{
+		{Name: "id", Value: id},
+		{Name: "name", Value: name},
+	}, testTableInfo)
+}
+
+func newSampler(t *testing.T, rate float64) (*Sampler, string) {
+	outputFile := filepath.Join(t.TempDir(), "samples.log")
+	s, err := NewSampler(context.Background(), model.DefaultChangeFeedID("test"),
+		&config.SamplingModeConfig{Enabled: true, SampleRate: rate, OutputFile: outputFile})
+	require.NoError(t, err)
+	return s, outputFile
+}
+
+func readSamples(t *testing.T, outputFile string) []map[string]interface{} {
+	f, err := os.Open(outputFile)
+	require.NoError(t, err)
+	defer f.Close()
+	var samples []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var sample map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &sample))
+		samples = append(samples, sample)
+	}
+	require.NoError(t, scanner.Err())
+	return samples
+}
+
+func TestShouldSampleStablePerKey(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newSampler(t, 0.3)
+	defer s.Close()
+
+	sampled := 0
+	for id := int64(0); id < 1000; id++ {
+		insert := &model.RowChangedEvent{
+			CommitTs: 1, TableInfo: testTableInfo, Columns: newTestRow(id, "a"),
+		}
+		update := &model.RowChangedEvent{
+			CommitTs: 2, TableInfo: testTableInfo,
+			PreColumns: newTestRow(id, "a"), Columns: newTestRow(id, "b"),
+		}
+		del := &model.RowChangedEvent{
+			CommitTs: 3, TableInfo: testTableInfo, PreColumns: newTestRow(id, "b"),
+		}
+		// The changes of the same row are always sampled together.
+		ok := s.ShouldSample(insert)
+		require.Equal(t, ok, s.ShouldSample(update))
+		require.Equal(t, ok, s.ShouldSample(del))
+		if ok {
+			sampled++
+		}
+	}
+	require.InDelta(t, 300, sampled, 60)
+
+	s.rate = 0
+	require.False(t, s.ShouldSample(&model.RowChangedEvent{
+		TableInfo: testTableInfo, Columns: newTestRow(1, "a"),
+	}))
+	s.rate = 1
+	require.True(t, s.ShouldSample(&model.RowChangedEvent{
+		TableInfo: testTableInfo, Columns: newTestRow(1, "a"),
+	}))
+}
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+
+	s, outputFile := newSampler(t, 1)
+	row := &model.RowChangedEvent{
+		CommitTs: 100, TableInfo: testTableInfo, Columns: newTestRow(1, "a"),
+	}
+	require.NoError(t, s.Sample(context.Background(), row))
+	s.Close()
+
+	samples := readSamples(t, outputFile)
+	require.Len(t, samples, 1)
+	require.Equal(t, "default", samples[0]["namespace"])
+	require.Equal(t, "test", samples[0]["changefeed"])
+	require.Equal(t, float64(100), samples[0]["commitTs"])
+	require.NotEmpty(t, samples[0]["sampledAt"])
+	event := samples[0]["event"].(map[string]interface{})
+	require.Equal(t, "test", event["database"])
+	require.Equal(t, "t", event["table"])
+	require.Equal(t, "INSERT", event["type"])
+	require.Equal(t, []interface{}{map[string]interface{}{"id": "1", "name": "a"}}, event["data"])
+}
+
+func TestDMLSinkWriteEvents(t *testing.T) {
+	t.Parallel()
+
+	s, outputFile := newSampler(t, 1)
+	sink := NewDMLSink[*model.RowChangedEvent](blackhole.NewDMLSink(), s)
+	tableStatus := state.TableSinkSinking
+	count := 0
+	events := make([]*dmlsink.RowChangeCallbackableEvent, 0, 2)
+	for id := int64(1); id <= 2; id++ {
+		events = append(events, &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: 100, TableInfo: testTableInfo, Columns: newTestRow(id, "a"),
+			},
+			Callback:  func() { count++ },
+			SinkState: &tableStatus,
+		})
+	}
+	// The events are still written to the wrapped sink.
+	require.NoError(t, sink.WriteEvents(events...))
+	require.Equal(t, 2, count)
+	sink.Close()
+	s.Close()
+	require.Len(t, readSamples(t, outputFile), 2)
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+	"go.uber.org/zap"
+)
+
+const defaultDMLMaxRetry uint64 = 8
+
+type clickHouseBackend struct {
+	workerID    int
+	changefeed  string
+	db          *sql.DB
+	cfg         *pclickhouse.Config
+	dmlMaxRetry uint64
+
+	events []*dmlsink.TxnCallbackableEvent
+	rows   int
+
+	statistics *metrics.Statistics
+}
+
+// NewClickHouseBackends creates the ClickHouse backends of a txn sink, all
+// the backends share one connection pool.
+func NewClickHouseBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	dbConnFactory pclickhouse.Factory,
+	statistics *metrics.Statistics,
+) ([]*clickHouseBackend, error) {
+	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
+
+	cfg := pclickhouse.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	db, err := dbConnFactory(ctx, pclickhouse.GenerateDSN(sinkURI))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(cfg.WorkerCount)
+	db.SetMaxOpenConns(cfg.WorkerCount)
+
+	backends := make([]*clickHouseBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &clickHouseBackend{
+			workerID:    i,
+			changefeed:  changefeed,
+			db:          db,
+			cfg:         cfg,
+			dmlMaxRetry: defaultDMLMaxRetry,
+			statistics:  statistics,
+		})
+	}
+
+	log.Info("ClickHouse backends is created",
+		zap.String("changefeed", changefeed),
+		zap.Int("workerCount", cfg.WorkerCount))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It adds the event to the buffer, and return true if it needs flush immediately.
+func (s *clickHouseBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.MaxTxnRow
+}
+
+// Flush implements interface backend.
+func (s *clickHouseBackend) Flush(ctx context.Context) error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	batches, err := prepareBatches(s.events)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, event := range s.events {
+		s.statistics.ObserveRows(event.Event.Rows...)
+	}
+	for _, batch := range batches {
+		if err := s.execBatchWithRetries(ctx, batch); err != nil {
+			if errors.Cause(err) != context.Canceled {
+				log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
+			}
+			return errors.Trace(err)
+		}
+	}
+	for _, event := range s.events {
+		event.Callback()
+	}
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return nil
+}
+
+// execBatchWithRetries sends the rows of a batch in one block with the
+// native protocol. The rows are versioned by the commit ts, so it's safe to
+// insert a batch again after a failure.
+func (s *clickHouseBackend) execBatchWithRetries(ctx context.Context, batch *insertBatch) error {
+	start := time.Now()
+	return retry.Do(ctx, func() error {
+		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
+			tx, err := s.db.BeginTx(ctx, nil)
+			if err != nil {
+				return 0, 0, cerror.WrapError(cerror.ErrClickHouseTxnError, err)
+			}
+			stmt, err := tx.PrepareContext(ctx, batch.query)
+			if err != nil {
+				_ = tx.Rollback()
+				return 0, 0, cerror.WrapError(cerror.ErrClickHouseTxnError, err)
+			}
+			for _, args := range batch.values {
+				if _, err := stmt.ExecContext(ctx, args...); err != nil {
+					_ = tx.Rollback()
+					return 0, 0, cerror.WrapError(cerror.ErrClickHouseTxnError, err)
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				return 0, 0, cerror.WrapError(cerror.ErrClickHouseTxnError, err)
+			}
+			return len(batch.values), 0, nil
+		})
+		if err != nil {
+			log.Warn("execute DMLs with error, retry later",
+				zap.Error(err), zap.Duration("duration", time.Since(start)),
+				zap.String("query", batch.query), zap.Int("count", len(batch.values)),
+				zap.String("changefeed", s.changefeed))
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfRows", len(batch.values)))
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(s.dmlMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *clickHouseBackend) MaxFlushInterval() time.Duration {
+	return s.cfg.MaxBatchWait
+}
+
+// Close implements interface backend.
+func (s *clickHouseBackend) Close() (err error) {
+	if s.db != nil {
+		err = s.db.Close()
+		s.db = nil
+	}
+	return
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(32))")
+	insert := helper.DML2Event("insert into test.t values (1, 'x')", "test", "t")
+	remove := *insert
+	remove.PreColumns, remove.Columns = insert.Columns, nil
+	remove.CommitTs = insert.CommitTs + 1
+	helper.DDL2Event("create table test.t2(a int primary key)")
+	other := helper.DML2Event("insert into test.t2 values (2)", "test", "t2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("clickhouse://127.0.0.1:9000/test?worker-count=1&max-txn-row=2")
+	require.NoError(t, err)
+	backends, err := NewClickHouseBackends(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			require.Equal(t, "clickhouse://127.0.0.1:9000/test", dsnStr)
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			// the rows of a table are inserted in one batch.
+			mock.ExpectBegin()
+			prepare := mock.ExpectPrepare("INSERT INTO `test`.`t` (`id`,`name`,`_tidb_commit_ts`,`_tidb_is_deleted`)")
+			prepare.ExpectExec().WithArgs(int32(1), "x", insert.CommitTs, uint8(0)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			prepare.ExpectExec().WithArgs(int32(1), "x", insert.CommitTs+1, uint8(1)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			mock.ExpectBegin()
+			mock.ExpectPrepare("INSERT INTO `test`.`t2` (`a`,`_tidb_commit_ts`,`_tidb_is_deleted`)").
+				ExpectExec().WithArgs(int32(2), other.CommitTs, uint8(0)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			mock.ExpectClose()
+			return db, nil
+		}, metrics.NewStatistics(ctx, model.DefaultChangeFeedID("test"), sink.TxnSink))
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	backend := backends[0]
+
+	flushed := 0
+	needFlush := backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{insert, &remove}},
+		Callback: func() { flushed++ },
+	})
+	require.True(t, needFlush)
+	backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{other}},
+		Callback: func() { flushed++ },
+	})
+	require.NoError(t, backend.Flush(ctx))
+	require.Equal(t, 2, flushed)
+	require.NoError(t, backend.Close())
+}
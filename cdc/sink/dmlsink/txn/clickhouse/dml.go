@@ -0,0 +1,115 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"strings"
+
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+)
+
+// insertBatch is the rows inserted into one table by one statement.
+type insertBatch struct {
+	query  string
+	values [][]interface{}
+}
+
+// prepareBatches converts the events to inserts, every row change is an
+// insert of the ReplacingMergeTree table versioned by its commit ts, and a
+// delete is an insert with the deleted mark. Consecutive rows of the same
+// table schema are put in one batch.
+func prepareBatches(events []*dmlsink.TxnCallbackableEvent) ([]*insertBatch, error) {
+	var (
+		batches   []*insertBatch
+		lastTable *model.TableInfo
+		columns   []*timodel.ColumnInfo
+	)
+	for _, event := range events {
+		for _, row := range event.Event.Rows {
+			if err := dmlsink.CheckRow(row); err != nil {
+				return nil, err
+			}
+			if row.TableInfo != lastTable {
+				lastTable = row.TableInfo
+				columns = replicatedColumns(row.TableInfo)
+				batches = append(batches, &insertBatch{query: buildInsert(row.TableInfo, columns)})
+			}
+			values, err := rowValues(row, columns)
+			if err != nil {
+				return nil, err
+			}
+			batch := batches[len(batches)-1]
+			batch.values = append(batch.values, values)
+		}
+	}
+	return batches, nil
+}
+
+// replicatedColumns returns the columns of a table except the virtual
+// generated columns, whose values are not carried by the row changed events.
+func replicatedColumns(tableInfo *model.TableInfo) []*timodel.ColumnInfo {
+	columns := make([]*timodel.ColumnInfo, 0, len(tableInfo.Columns))
+	for _, col := range tableInfo.Columns {
+		if col.IsGenerated() && !col.GeneratedStored {
+			continue
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+func buildInsert(tableInfo *model.TableInfo, columns []*timodel.ColumnInfo) string {
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(quotes.QuoteSchema(tableInfo.GetSchemaName(), tableInfo.GetTableName()))
+	builder.WriteString(" (")
+	for _, col := range columns {
+		builder.WriteString(quotes.QuoteName(col.Name.O))
+		builder.WriteString(",")
+	}
+	builder.WriteString(quotes.QuoteName(pclickhouse.CommitTsColumn))
+	builder.WriteString(",")
+	builder.WriteString(quotes.QuoteName(pclickhouse.IsDeletedColumn))
+	builder.WriteString(")")
+	return builder.String()
+}
+
+func rowValues(row *model.RowChangedEvent, columns []*timodel.ColumnInfo) ([]interface{}, error) {
+	data := row.Columns
+	isDeleted := uint8(0)
+	if row.IsDelete() {
+		data = row.PreColumns
+		isDeleted = 1
+	}
+	byID := make(map[int64]interface{}, len(data))
+	for _, col := range data {
+		if col != nil {
+			byID[col.ColumnID] = col.Value
+		}
+	}
+
+	values := make([]interface{}, 0, len(columns)+2)
+	for _, col := range columns {
+		value, err := pclickhouse.ConvertValue(&col.FieldType, byID[col.ID])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return append(values, row.CommitTs, isDeleted), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"github.com/olivere/elastic/v7"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	pes "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+)
+
+// prepareRequests converts the row changes to bulk requests. An insert
+// becomes an index request, an update becomes an update request which
+// upserts the whole document, and a delete becomes a delete request.
+func prepareRequests(
+	events []*dmlsink.TxnCallbackableEvent, cfg *pes.Config,
+) ([]elastic.BulkableRequest, error) {
+	requests := make([]elastic.BulkableRequest, 0)
+	for _, event := range events {
+		for _, row := range event.Event.Rows {
+			if err := dmlsink.CheckRow(row); err != nil {
+				return nil, err
+			}
+			index := cfg.IndexName(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName())
+			switch {
+			case row.IsInsert():
+				id, doc, err := buildDocument(row.TableInfo, row.Columns)
+				if err != nil {
+					return nil, err
+				}
+				requests = append(requests, elastic.NewBulkIndexRequest().
+					Index(index).Id(id).Doc(doc))
+			case row.IsDelete():
+				id, _, err := buildDocument(row.TableInfo, row.PreColumns)
+				if err != nil {
+					return nil, err
+				}
+				requests = append(requests, elastic.NewBulkDeleteRequest().
+					Index(index).Id(id))
+			default:
+				preID, _, err := buildDocument(row.TableInfo, row.PreColumns)
+				if err != nil {
+					return nil, err
+				}
+				id, doc, err := buildDocument(row.TableInfo, row.Columns)
+				if err != nil {
+					return nil, err
+				}
+				// The document ID of a table without handle key is
+				// changed with the values.
+				if preID != id {
+					requests = append(requests,
+						elastic.NewBulkDeleteRequest().Index(index).Id(preID),
+						elastic.NewBulkIndexRequest().Index(index).Id(id).Doc(doc))
+					continue
+				}
+				requests = append(requests, elastic.NewBulkUpdateRequest().
+					Index(index).Id(id).Doc(doc).DocAsUpsert(true))
+			}
+		}
+	}
+	return requests, nil
+}
+
+// buildDocument returns the ID and the source of the document of a row.
+// The ID is the hash of the handle key values, or the hash of all the
+// values if the table has no handle key.
+func buildDocument(
+	tableInfo *model.TableInfo, data []*model.ColumnData,
+) (string, map[string]interface{}, error) {
+	doc := make(map[string]interface{}, len(data))
+	var keyValues, allValues []string
+	for _, col := range data {
+		if col == nil {
+			continue
+		}
+		colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+		if !ok {
+			continue
+		}
+		value, err := pes.ConvertValue(&colInfo.FieldType, col.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		doc[colInfo.Name.O] = value
+
+		valueStr := model.ColumnValueString(col.Value)
+		allValues = append(allValues, valueStr)
+		if tableInfo.ForceGetColumnFlagType(col.ColumnID).IsHandleKey() {
+			keyValues = append(keyValues, valueStr)
+		}
+	}
+	if len(keyValues) == 0 {
+		keyValues = allValues
+	}
+	return pes.DocumentID(keyValues), doc, nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pes "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"go.uber.org/zap"
+)
+
+const defaultDMLMaxRetry uint64 = 8
+
+type esBackend struct {
+	workerID    int
+	changefeed  string
+	client      *elastic.Client
+	cfg         *pes.Config
+	dmlMaxRetry uint64
+
+	events []*dmlsink.TxnCallbackableEvent
+	rows   int
+
+	statistics *metrics.Statistics
+}
+
+// NewElasticsearchBackends creates the Elasticsearch backends of a txn sink,
+// all the backends share one client.
+func NewElasticsearchBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	clientFactory pes.Factory,
+	statistics *metrics.Statistics,
+) ([]*esBackend, error) {
+	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
+
+	cfg := pes.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	client, err := clientFactory(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]*esBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &esBackend{
+			workerID:    i,
+			changefeed:  changefeed,
+			client:      client,
+			cfg:         cfg,
+			dmlMaxRetry: defaultDMLMaxRetry,
+			statistics:  statistics,
+		})
+	}
+
+	log.Info("Elasticsearch backends is created",
+		zap.String("changefeed", changefeed),
+		zap.Int("workerCount", cfg.WorkerCount),
+		zap.Int("batchSize", cfg.BatchSize),
+		zap.Duration("flushInterval", cfg.FlushInterval))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It adds the event to the buffer, and return true if it needs flush immediately.
+func (s *esBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.BatchSize
+}
+
+// Flush implements interface backend.
+func (s *esBackend) Flush(ctx context.Context) error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	requests, err := prepareRequests(s.events, s.cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, event := range s.events {
+		s.statistics.ObserveRows(event.Event.Rows...)
+	}
+	for start := 0; start < len(requests); start += s.cfg.BatchSize {
+		end := start + s.cfg.BatchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		if err := s.execBulkWithRetries(ctx, requests[start:end]); err != nil {
+			if errors.Cause(err) != context.Canceled {
+				log.Error("execute bulk requests failed",
+					zap.String("changefeed", s.changefeed), zap.Error(err))
+			}
+			return errors.Trace(err)
+		}
+	}
+	for _, event := range s.events {
+		event.Callback()
+	}
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return nil
+}
+
+// execBulkWithRetries sends the requests in one bulk request. All the
+// requests are idempotent, so the whole bulk is sent again if any of them
+// is rejected by a retryable error.
+func (s *esBackend) execBulkWithRetries(
+	ctx context.Context, requests []elastic.BulkableRequest,
+) error {
+	start := time.Now()
+	return retry.Do(ctx, func() error {
+		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
+			resp, err := s.client.Bulk().Add(requests...).Do(ctx)
+			if err != nil {
+				return 0, 0, wrapError(err)
+			}
+			if err := checkBulkResponse(resp); err != nil {
+				return 0, 0, err
+			}
+			return len(requests), 0, nil
+		})
+		if err != nil {
+			log.Warn("execute bulk requests with error, retry later",
+				zap.Error(err), zap.Duration("duration", time.Since(start)),
+				zap.Int("count", len(requests)),
+				zap.String("changefeed", s.changefeed))
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfRequests", len(requests)))
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(s.dmlMaxRetry),
+		retry.WithIsRetryableErr(isRetryableError))
+}
+
+// checkBulkResponse returns the first error of the items in a bulk response.
+// Deleting a missing document is not an error, since the document may be
+// deleted by a previous attempt.
+func checkBulkResponse(resp *elastic.BulkResponse) error {
+	if !resp.Errors {
+		return nil
+	}
+	for _, item := range resp.Items {
+		for op, result := range item {
+			if result.Status >= http.StatusOK && result.Status < http.StatusMultipleChoices {
+				continue
+			}
+			if op == "delete" && result.Status == http.StatusNotFound {
+				continue
+			}
+			reason := ""
+			if result.Error != nil {
+				reason = result.Error.Reason
+			}
+			return cerror.ErrElasticsearchRequestError.Wrap(&elastic.Error{
+				Status:  result.Status,
+				Details: result.Error,
+			}).GenWithStack("%s document %s of index %s failed: %s",
+				op, result.Id, result.Index, reason)
+		}
+	}
+	return nil
+}
+
+func wrapError(err error) error {
+	if errors.Cause(err) == context.Canceled {
+		return err
+	}
+	return cerror.WrapError(cerror.ErrElasticsearchRequestError, err)
+}
+
+// isRetryableError returns true if the requests are rejected for too many
+// requests, or failed by the server side or the network.
+func isRetryableError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+	var esErr *elastic.Error
+	if cerror.As(err, &esErr) {
+		return esErr.Status == http.StatusTooManyRequests ||
+			esErr.Status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *esBackend) MaxFlushInterval() time.Duration {
+	return s.cfg.FlushInterval
+}
+
+// Close implements interface backend.
+func (s *esBackend) Close() error {
+	if s.client != nil {
+		s.client.Stop()
+		s.client = nil
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pes "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	// force-replicate is required to replicate the table without handle key.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.ForceReplicate = true
+	helper := entry.NewSchemaTestHelperWithReplicaConfig(t, replicaConfig)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(32))")
+	insert := helper.DML2Event("insert into test.t values (1, 'x')", "test", "t")
+	update := *insert
+	update.PreColumns = insert.Columns
+	update.Columns = []*model.ColumnData{
+		insert.Columns[0], {ColumnID: insert.Columns[1].ColumnID, Value: []byte("y")},
+	}
+	remove := *insert
+	remove.PreColumns, remove.Columns = insert.Columns, nil
+	helper.DDL2Event("create table test.t2(a int)")
+	noKey := helper.DML2Event("insert into test.t2 values (1)", "test", "t2")
+	noKeyUpdate := *noKey
+	noKeyUpdate.PreColumns = noKey.Columns
+	noKeyUpdate.Columns = []*model.ColumnData{{ColumnID: noKey.Columns[0].ColumnID, Value: int64(2)}}
+
+	var (
+		requests int32
+		actions  []string
+		sources  []map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first bulk request is rejected for too many requests.
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			line := make(map[string]map[string]interface{})
+			_ = json.Unmarshal(scanner.Bytes(), &line)
+			for action := range line {
+				actions = append(actions, action)
+				if action != "delete" {
+					scanner.Scan()
+					source := make(map[string]interface{})
+					_ = json.Unmarshal(scanner.Bytes(), &source)
+					sources = append(sources, source)
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// The document is deleted by the previous attempt.
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"delete":{"status":404}}]}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("es://127.0.0.1:9200/?worker-count=1&batch-size=2")
+	require.NoError(t, err)
+	backends, err := NewElasticsearchBackends(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		config.GetDefaultReplicaConfig(),
+		func(ctx context.Context, cfg *pes.Config) (*elastic.Client, error) {
+			require.Equal(t, []string{"http://127.0.0.1:9200"}, cfg.Endpoints)
+			return elastic.NewClient(elastic.SetURL(server.URL),
+				elastic.SetSniff(false), elastic.SetHealthcheck(false))
+		}, metrics.NewStatistics(ctx, model.DefaultChangeFeedID("test"), sink.TxnSink))
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	backend := backends[0]
+
+	flushed := 0
+	needFlush := backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{insert, &update, &remove}},
+		Callback: func() { flushed++ },
+	})
+	require.True(t, needFlush)
+	backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{&noKeyUpdate}},
+		Callback: func() { flushed++ },
+	})
+	require.NoError(t, backend.Flush(ctx))
+	require.Equal(t, 2, flushed)
+	// The 5 requests are sent in 3 bulks of 2 requests at most, and the
+	// first one is retried.
+	require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	require.Equal(t, []string{"index", "update", "delete", "delete", "index"}, actions)
+	require.Equal(t, []map[string]interface{}{
+		{"id": float64(1), "name": "x"},
+		{"doc": map[string]interface{}{"id": float64(1), "name": "y"}, "doc_as_upsert": true},
+		{"a": float64(2)},
+	}, sources)
+	require.NoError(t, backend.Close())
+}
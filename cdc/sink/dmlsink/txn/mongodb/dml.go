@@ -0,0 +1,170 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	pmongodb "github.com/pingcap/tiflow/pkg/sink/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	opInsert = iota
+	opReplace
+	opDelete
+)
+
+// collection is a MongoDB collection, which is named by the table in the
+// database named by the schema.
+type collection struct {
+	database string
+	name     string
+}
+
+// operation is a write of a document identified by the handle key.
+type operation struct {
+	kind int
+	id   interface{}
+	doc  bson.D
+}
+
+// writeModel returns the write model of the operation, the inserts are
+// written as upserts if upsert is true.
+func (o *operation) writeModel(upsert bool) mongo.WriteModel {
+	switch {
+	case o.kind == opDelete:
+		return mongo.NewDeleteOneModel().SetFilter(bson.D{{Key: "_id", Value: o.id}})
+	case o.kind == opInsert && !upsert:
+		return mongo.NewInsertOneModel().SetDocument(o.doc)
+	default:
+		return mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: o.id}}).
+			SetReplacement(o.doc).SetUpsert(true)
+	}
+}
+
+// prepareOperations converts the row changes to the operations of the
+// collections, the operations of a collection are in the order of the rows.
+// The inserts are written as upserts in safe mode, or if the rows may have
+// been written before the changefeed is started.
+// Rows of tables without handle key are skipped, since they can't be
+// identified by `_id`, the IDs of the skipped tables are returned.
+func prepareOperations(
+	rows []*model.RowChangedEvent, safeMode bool,
+) ([]collection, map[collection][]*operation, []int64, error) {
+	var collections []collection
+	operations := make(map[collection][]*operation)
+	var skipped []int64
+	for _, row := range rows {
+		if err := dmlsink.CheckRow(row); err != nil {
+			return nil, nil, nil, err
+		}
+		var pre, post *operation
+		var preKey, postKey string
+		var err error
+		if row.IsDelete() || row.IsUpdate() {
+			pre, preKey, err = encodeRow(row.TableInfo, row.PreColumns)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if !row.IsDelete() {
+			post, postKey, err = encodeRow(row.TableInfo, row.Columns)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if (pre != nil && pre.id == nil) || (post != nil && post.id == nil) {
+			skipped = append(skipped, row.TableInfo.ID)
+			continue
+		}
+
+		coll := collection{
+			database: row.TableInfo.GetSchemaName(),
+			name:     row.TableInfo.GetTableName(),
+		}
+		ops, ok := operations[coll]
+		if !ok {
+			collections = append(collections, coll)
+		}
+		switch {
+		case row.IsDelete():
+			pre.kind = opDelete
+			ops = append(ops, pre)
+		case row.IsInsert():
+			post.kind = opInsert
+			if safeMode || row.CommitTs <= row.ReplicatingTs {
+				post.kind = opReplace
+			}
+			ops = append(ops, post)
+		default:
+			// The document of the old handle key is removed if the handle key
+			// is updated.
+			if preKey != postKey {
+				pre.kind = opDelete
+				ops = append(ops, pre)
+			}
+			post.kind = opReplace
+			ops = append(ops, post)
+		}
+		operations[coll] = ops
+	}
+	return collections, operations, skipped, nil
+}
+
+// encodeRow returns the operation with the document of a row, whose `_id`
+// is the value of the handle key, or the sub-document of the values if
+// the handle key has multiple columns. The id of the operation is nil if
+// the table has no handle key. The string of the handle key is returned
+// to compare the handle keys.
+func encodeRow(tableInfo *model.TableInfo, data []*model.ColumnData) (*operation, string, error) {
+	doc := make(bson.D, 1, len(data)+1)
+	var keys bson.D
+	var keyValues []string
+	for _, col := range data {
+		if col == nil {
+			continue
+		}
+		colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+		if !ok {
+			continue
+		}
+		value, err := pmongodb.ConvertValue(&colInfo.FieldType, col.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		doc = append(doc, bson.E{Key: colInfo.Name.O, Value: value})
+		if tableInfo.ForceGetColumnFlagType(col.ColumnID).IsHandleKey() {
+			keys = append(keys, bson.E{Key: colInfo.Name.O, Value: value})
+			keyValues = append(keyValues, strconv.Quote(model.ColumnValueString(col.Value)))
+		}
+	}
+
+	op := &operation{}
+	switch len(keys) {
+	case 0:
+		return op, "", nil
+	case 1:
+		op.id = keys[0].Value
+	default:
+		op.id = keys
+	}
+	doc[0] = bson.E{Key: "_id", Value: op.id}
+	op.doc = doc
+	return op, strings.Join(keyValues, ","), nil
+}
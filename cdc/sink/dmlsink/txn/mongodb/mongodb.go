@@ -0,0 +1,274 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmongodb "github.com/pingcap/tiflow/pkg/sink/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDMLMaxRetry uint64 = 8
+	maxFlushInterval          = 10 * time.Millisecond
+)
+
+type mongoDBBackend struct {
+	workerID    int
+	changefeed  string
+	client      *mongo.Client
+	cfg         *pmongodb.Config
+	dmlMaxRetry uint64
+
+	events []*dmlsink.TxnCallbackableEvent
+	rows   int
+	// skippedTables are the tables without handle key, which have been
+	// warned about.
+	skippedTables map[int64]struct{}
+
+	statistics *metrics.Statistics
+}
+
+// NewMongoDBBackends creates the MongoDB backends of a txn sink, all the
+// backends share one client.
+func NewMongoDBBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	clientFactory pmongodb.Factory,
+	statistics *metrics.Statistics,
+) ([]*mongoDBBackend, error) {
+	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
+
+	cfg := pmongodb.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	client, err := clientFactory(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]*mongoDBBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &mongoDBBackend{
+			workerID:      i,
+			changefeed:    changefeed,
+			client:        client,
+			cfg:           cfg,
+			dmlMaxRetry:   defaultDMLMaxRetry,
+			skippedTables: make(map[int64]struct{}),
+			statistics:    statistics,
+		})
+	}
+
+	log.Info("MongoDB backends is created",
+		zap.String("changefeed", changefeed),
+		zap.Int("workerCount", cfg.WorkerCount),
+		zap.Int("batchSize", cfg.BatchSize),
+		zap.Bool("safeMode", cfg.SafeMode))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It adds the event to the buffer, and return true if it needs flush immediately.
+func (s *mongoDBBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.BatchSize
+}
+
+// Flush implements interface backend.
+func (s *mongoDBBackend) Flush(ctx context.Context) error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	rows := make([]*model.RowChangedEvent, 0, s.rows)
+	for _, event := range s.events {
+		rows = append(rows, event.Event.Rows...)
+	}
+
+	collections, operations, skipped, err := prepareOperations(rows, s.cfg.SafeMode)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.statistics.ObserveRows(rows...)
+	for _, tableID := range skipped {
+		if _, ok := s.skippedTables[tableID]; !ok {
+			s.skippedTables[tableID] = struct{}{}
+			log.Warn("rows of the table without handle key are skipped by the MongoDB sink",
+				zap.String("changefeed", s.changefeed), zap.Int64("tableID", tableID))
+		}
+	}
+	// The operations of different collections are on different documents,
+	// so they are written one collection after another.
+	for _, coll := range collections {
+		ops := operations[coll]
+		for start := 0; start < len(ops); start += s.cfg.BatchSize {
+			end := start + s.cfg.BatchSize
+			if end > len(ops) {
+				end = len(ops)
+			}
+			if err := s.bulkWriteWithRetries(ctx, coll, ops[start:end]); err != nil {
+				if errors.Cause(err) != context.Canceled {
+					log.Error("execute bulk write failed",
+						zap.String("changefeed", s.changefeed),
+						zap.String("database", coll.database),
+						zap.String("collection", coll.name),
+						zap.Error(err))
+				}
+				return errors.Trace(err)
+			}
+		}
+	}
+	for _, event := range s.events {
+		event.Callback()
+	}
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return nil
+}
+
+// bulkWriteWithRetries writes the operations of a collection in an ordered
+// bulk write. A part of the operations may have been applied when the bulk
+// write fails, so the inserts are retried as upserts.
+func (s *mongoDBBackend) bulkWriteWithRetries(
+	ctx context.Context, coll collection, ops []*operation,
+) error {
+	start := time.Now()
+	upsert := false
+	return retry.Do(ctx, func() error {
+		models := make([]mongo.WriteModel, 0, len(ops))
+		for _, op := range ops {
+			models = append(models, op.writeModel(upsert))
+		}
+		upsert = true
+		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
+			_, err := s.client.Database(coll.database).Collection(coll.name).
+				BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true))
+			if err != nil {
+				return 0, 0, wrapError(err)
+			}
+			return len(models), 0, nil
+		})
+		if err != nil {
+			log.Warn("execute bulk write with error, retry later",
+				zap.Error(err), zap.Duration("duration", time.Since(start)),
+				zap.Int("count", len(models)),
+				zap.String("changefeed", s.changefeed))
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfOperations", len(models)))
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(s.dmlMaxRetry),
+		retry.WithIsRetryableErr(isRetryableError))
+}
+
+func wrapError(err error) error {
+	if errors.Cause(err) == context.Canceled {
+		return err
+	}
+	return cerror.WrapError(cerror.ErrMongoDBRequestError, err)
+}
+
+// retryableErrorCodes are the codes of the server errors which are caused
+// by the temporary unavailability of the servers, e.g. a primary election.
+var retryableErrorCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	262,   // ExceededTimeLimit
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// isRetryableError returns true if the error is caused by the connection
+// or a temporary unavailability of the servers, the other server errors,
+// such as duplicate keys, are not retryable.
+func isRetryableError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+	cause := errors.Cause(err)
+	if mongo.IsNetworkError(cause) || mongo.IsTimeout(cause) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	if cerror.As(err, &serverErr) {
+		if serverErr.HasErrorLabel("RetryableWriteError") {
+			return true
+		}
+		for _, code := range retryableErrorCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *mongoDBBackend) MaxFlushInterval() time.Duration {
+	return maxFlushInterval
+}
+
+// Close implements interface backend.
+func (s *mongoDBBackend) Close() (err error) {
+	if s.client != nil {
+		// The client is shared by all the backends, it may have been
+		// closed by another one.
+		err = s.client.Disconnect(context.Background())
+		if err == mongo.ErrClientDisconnected {
+			err = nil
+		}
+		s.client = nil
+	}
+	return
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPrepareOperations(t *testing.T) {
+	// test.t3 has no handle key, it needs force-replicate to be replicated.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.ForceReplicate = true
+	helper := entry.NewSchemaTestHelperWithReplicaConfig(t, replicaConfig)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(32))")
+	insert := helper.DML2Event("insert into test.t values (1, 'x')", "test", "t")
+	insert.CommitTs, insert.ReplicatingTs = 100, 10
+	update := *insert
+	update.PreColumns = insert.Columns
+	update.Columns = []*model.ColumnData{
+		insert.Columns[0],
+		{ColumnID: insert.Columns[1].ColumnID, Value: []byte("y")},
+	}
+	// The handle key is updated.
+	updateKey := *insert
+	updateKey.PreColumns = update.Columns
+	updateKey.Columns = []*model.ColumnData{
+		{ColumnID: insert.Columns[0].ColumnID, Value: int64(2)},
+		update.Columns[1],
+	}
+	remove := *insert
+	remove.PreColumns, remove.Columns = updateKey.Columns, nil
+	helper.DDL2Event("create table test.t2(a int, b int, primary key(a, b))")
+	insert2 := helper.DML2Event("insert into test.t2 values (1, 2)", "test", "t2")
+	insert2.CommitTs, insert2.ReplicatingTs = 100, 100
+	helper.DDL2Event("create table test.t3(a int)")
+	noKey := helper.DML2Event("insert into test.t3 values (1)", "test", "t3")
+
+	rows := []*model.RowChangedEvent{insert, insert2, &update, noKey, &updateKey, &remove}
+	collections, operations, skipped, err := prepareOperations(rows, false)
+	require.NoError(t, err)
+	require.Equal(t, []collection{{database: "test", name: "t"}, {database: "test", name: "t2"}}, collections)
+	require.Equal(t, []int64{noKey.TableInfo.ID}, skipped)
+
+	ops := operations[collections[0]]
+	kinds := make([]int, 0, len(ops))
+	for _, op := range ops {
+		kinds = append(kinds, op.kind)
+	}
+	require.Equal(t, []int{opInsert, opReplace, opDelete, opReplace, opDelete}, kinds)
+	require.Equal(t, bson.D{{Key: "_id", Value: int64(1)}, {Key: "id", Value: int64(1)}, {Key: "name", Value: "x"}},
+		ops[0].doc)
+	require.Equal(t, int64(1), ops[2].id)
+	require.Equal(t, int64(2), ops[3].id)
+	require.Equal(t, int64(2), ops[4].id)
+
+	// The rows replicated before the changefeed is started are upserted,
+	// and the composite handle key is a sub-document.
+	ops = operations[collections[1]]
+	require.Len(t, ops, 1)
+	require.Equal(t, opReplace, ops[0].kind)
+	require.Equal(t, bson.D{{Key: "a", Value: int64(1)}, {Key: "b", Value: int64(2)}}, ops[0].id)
+
+	_, operations, _, err = prepareOperations([]*model.RowChangedEvent{insert}, true)
+	require.NoError(t, err)
+	require.Equal(t, opReplace, operations[collections[0]][0].kind)
+
+	_, _, _, err = prepareOperations([]*model.RowChangedEvent{nil}, false)
+	require.Error(t, err)
+}
+
+func TestWriteModel(t *testing.T) {
+	t.Parallel()
+
+	doc := bson.D{{Key: "_id", Value: 1}, {Key: "id", Value: 1}}
+	op := &operation{kind: opInsert, id: 1, doc: doc}
+	insert, ok := op.writeModel(false).(*mongo.InsertOneModel)
+	require.True(t, ok)
+	require.Equal(t, doc, insert.Document)
+
+	// The inserts are upserted when they are retried.
+	replace, ok := op.writeModel(true).(*mongo.ReplaceOneModel)
+	require.True(t, ok)
+	require.Equal(t, bson.D{{Key: "_id", Value: 1}}, replace.Filter)
+	require.Equal(t, doc, replace.Replacement)
+	require.True(t, *replace.Upsert)
+
+	op = &operation{kind: opDelete, id: 1}
+	remove, ok := op.writeModel(true).(*mongo.DeleteOneModel)
+	require.True(t, ok)
+	require.Equal(t, bson.D{{Key: "_id", Value: 1}}, remove.Filter)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isRetryableError(wrapError(io.EOF)))
+	require.True(t, isRetryableError(wrapError(mongo.CommandError{Code: 189, Name: "PrimarySteppedDown"})))
+	require.True(t, isRetryableError(wrapError(mongo.CommandError{Labels: []string{"RetryableWriteError"}})))
+	require.False(t, isRetryableError(wrapError(mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}},
+	})))
+	require.False(t, isRetryableError(wrapError(context.Canceled)))
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"go.uber.org/zap"
+)
+
+const createAuditTableSQL = `CREATE TABLE IF NOT EXISTS %s
+(
+	id bigint NOT NULL AUTO_INCREMENT,
+	commit_ts bigint unsigned NOT NULL,
+	table_name varchar(255) NOT NULL,
+	operation varchar(16) NOT NULL,
+	row_id text,
+	raw_event_json longblob,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (id),
+	INDEX (commit_ts)
+)`
+
+// auditSideChannel inserts a row into the audit table for each row change,
+// the rows are written in the same transaction as the DMLs so the audit
+// table is always consistent with the replicated data.
+type auditSideChannel struct {
+	// quotedTable is the quoted full name of the audit table.
+	quotedTable string
+	compression bool
+}
+
+func newAuditSideChannel(
+	ctx context.Context, db *sql.DB, table string, compression bool,
+) (*auditSideChannel, error) {
+	schema := filter.TiCDCSystemSchema
+	if i := strings.IndexByte(table, '.'); i >= 0 {
+		schema, table = table[:i], table[i+1:]
+	}
+	if _, err := db.ExecContext(ctx,
+		"CREATE DATABASE IF NOT EXISTS "+quotes.QuoteName(schema)); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	quotedTable := quotes.QuoteSchema(schema, table)
+	if _, err := db.ExecContext(ctx,
+		fmt.Sprintf(createAuditTableSQL, quotedTable)); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	return &auditSideChannel{quotedTable: quotedTable, compression: compression}, nil
+}
+
+// auditEvent is the JSON payload of an audit row.
+type auditEvent struct {
+	StartTs    uint64                 `json:"start_ts"`
+	CommitTs   uint64                 `json:"commit_ts"`
+	Schema     string                 `json:"schema"`
+	Table      string                 `json:"table"`
+	Operation  string                 `json:"operation"`
+	Columns    map[string]interface{} `json:"columns,omitempty"`
+	PreColumns map[string]interface{} `json:"pre_columns,omitempty"`
+}
+
+// prepareInsert returns an INSERT statement which records all rows of the txn.
+// The size of the statement and its arguments is returned as well.
+func (a *auditSideChannel) prepareInsert(
+	txn *model.SingleTableTxn,
+) (string, []interface{}, int64) {
+	placeholder := "(?,?,?,?,?)"
+	if a.compression {
+		placeholder = "(?,?,?,?,COMPRESS(?))"
+	}
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO " + a.quotedTable +
+		" (commit_ts, table_name, operation, row_id, raw_event_json) VALUES ")
+	args := make([]interface{}, 0, len(txn.Rows)*5)
+	size := int64(0)
+	for i, row := range txn.Rows {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(placeholder)
+
+		// Column values are decoded from TiKV, so they can always be marshaled.
+		operation := auditOperation(row)
+		rowID, err := json.Marshal(row.GetHandleKeyColumnValues())
+		if err != nil {
+			log.Panic("failed to marshal the row id of an audit row", zap.Error(err))
+		}
+		payload, err := json.Marshal(&auditEvent{
+			StartTs:    row.StartTs,
+			CommitTs:   row.CommitTs,
+			Schema:     row.TableInfo.GetSchemaName(),
+			Table:      row.TableInfo.GetTableName(),
+			Operation:  operation,
+			Columns:    auditColumns(row.GetColumns()),
+			PreColumns: auditColumns(row.GetPreColumns()),
+		})
+		if err != nil {
+			log.Panic("failed to marshal an audit row", zap.Error(err))
+		}
+		tableName := row.TableInfo.TableName.String()
+		args = append(args, row.CommitTs, tableName, operation, string(rowID), payload)
+		size += int64(len(tableName) + len(rowID) + len(payload))
+	}
+	query := builder.String()
+	return query, args, size + int64(len(query))
+}
+
+func auditOperation(row *model.RowChangedEvent) string {
+	if row.IsDelete() {
+		return "DELETE"
+	}
+	if row.IsUpdate() {
+		return "UPDATE"
+	}
+	return "INSERT"
+}
+
+// auditColumns converts the columns to a map keyed by the column names.
+// Values of non-binary string columns are converted to strings, otherwise
+// they are encoded in base64 by JSON.
+func auditColumns(cols []*model.Column) map[string]interface{} {
+	if len(cols) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		value := col.Value
+		if b, ok := value.([]byte); ok && col.Charset != "" && col.Charset != charset.CharsetBin {
+			value = string(b)
+		}
+		result[col.Name] = value
+	}
+	return result
+}
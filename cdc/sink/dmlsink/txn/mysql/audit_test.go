@@ -0,0 +1,163 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuditTestTableInfo() *model.TableInfo {
+	return model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+		{
+			Name:    "b",
+			Type:    mysql.TypeVarchar,
+			Charset: "utf8mb4",
+		},
+	}, [][]int{{0}})
+}
+
+func TestMySQLBackendAuditSideChannel(t *testing.T) {
+	auditTable := "`tidb_cdc`.`_ticdc_audit_log`"
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectExec("CREATE DATABASE IF NOT EXISTS `tidb_cdc`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf(createAuditTableSQL, auditTable)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		// The audit row is inserted in the same transaction as the DML.
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)").
+			WithArgs(1, "x").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO "+auditTable+
+			" (commit_ts, table_name, operation, row_id, raw_event_json) VALUES (?,?,?,?,?)").
+			WithArgs(uint64(2), "s1.t1", "INSERT", `["1"]`,
+				[]byte(`{"start_ts":1,"commit_ts":2,"schema":"s1","table":"t1",`+
+					`"operation":"INSERT","columns":{"a":1,"b":"x"}}`)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false&multi-stmt-enable=false")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		AuditSideChannel: util.AddressOf("_ticdc_audit_log"),
+	}
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+
+	tableInfo := newAuditTestTableInfo()
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:         1,
+			CommitTs:        2,
+			TableInfo:       tableInfo,
+			PhysicalTableID: 1,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "a", Value: 1},
+				{Name: "b", Value: "x"},
+			}, tableInfo),
+		},
+	}
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{CommitTs: 2, Rows: rows},
+		Callback: func() {},
+	})
+	require.Nil(t, sink.Flush(context.Background()))
+	require.Nil(t, sink.Close())
+}
+
+func TestAuditSideChannelPrepareInsert(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := newAuditTestTableInfo()
+	txn := &model.SingleTableTxn{
+		CommitTs: 4,
+		Rows: []*model.RowChangedEvent{
+			{
+				StartTs:   3,
+				CommitTs:  4,
+				TableInfo: tableInfo,
+				PreColumns: model.Columns2ColumnDatas([]*model.Column{
+					{Name: "a", Value: 1},
+					{Name: "b", Value: []byte("x")},
+				}, tableInfo),
+				Columns: model.Columns2ColumnDatas([]*model.Column{
+					{Name: "a", Value: 1},
+					{Name: "b", Value: []byte("y")},
+				}, tableInfo),
+			},
+			{
+				StartTs:   3,
+				CommitTs:  4,
+				TableInfo: tableInfo,
+				PreColumns: model.Columns2ColumnDatas([]*model.Column{
+					{Name: "a", Value: 2},
+					{Name: "b", Value: nil},
+				}, tableInfo),
+			},
+		},
+	}
+
+	audit := &auditSideChannel{quotedTable: "`audit`.`log`", compression: true}
+	query, args, size := audit.prepareInsert(txn)
+	require.Equal(t, "INSERT INTO `audit`.`log` "+
+		"(commit_ts, table_name, operation, row_id, raw_event_json) "+
+		"VALUES (?,?,?,?,COMPRESS(?)),(?,?,?,?,COMPRESS(?))", query)
+	require.Equal(t, []interface{}{
+		uint64(4), "s1.t1", "UPDATE", `["1"]`,
+		[]byte(`{"start_ts":3,"commit_ts":4,"schema":"s1","table":"t1","operation":"UPDATE",` +
+			`"columns":{"a":1,"b":"y"},"pre_columns":{"a":1,"b":"x"}}`),
+		uint64(4), "s1.t1", "DELETE", `["2"]`,
+		[]byte(`{"start_ts":3,"commit_ts":4,"schema":"s1","table":"t1","operation":"DELETE",` +
+			`"pre_columns":{"a":2,"b":null}}`),
+	}, args)
+	require.Greater(t, size, int64(len(query)))
+}
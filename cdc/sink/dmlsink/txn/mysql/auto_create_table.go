@@ -0,0 +1,249 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/executor"
+	"github.com/pingcap/tidb/pkg/meta/autoid"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/util/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const tableExistsQuery = "SELECT COUNT(*) FROM information_schema.tables " +
+	"WHERE table_schema = ? AND table_name = ?"
+
+// isTableNotExistError returns true if the DMLs failed since the downstream
+// table doesn't exist.
+func isTableNotExistError(err error) bool {
+	errCode, ok := getSQLErrCode(err)
+	return ok && errCode == mysql.ErrNoSuchTable
+}
+
+// tableCreator creates the missing downstream tables of the failed DMLs. The
+// `CREATE TABLE` statements are built from the table info of the rows, or
+// fetched from the upstream TiDB if it fails. It is shared by all backends
+// of a sink.
+type tableCreator struct {
+	changefeed    string
+	cfg           *pmysql.Config
+	dbConnFactory pmysql.Factory
+
+	metricAutoCreateTable prometheus.Counter
+
+	mu sync.Mutex
+	// upstream is opened when it's used for the first time.
+	upstream *sql.DB
+}
+
+func newTableCreator(
+	changefeedID model.ChangeFeedID, changefeed string,
+	cfg *pmysql.Config, dbConnFactory pmysql.Factory,
+) *tableCreator {
+	return &tableCreator{
+		changefeed:    changefeed,
+		cfg:           cfg,
+		dbConnFactory: dbConnFactory,
+		metricAutoCreateTable: txn.AutoCreateTableCounter.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+}
+
+// createMissingTables creates the downstream tables of the events which
+// don't exist. It returns true if any table is created, so that the events
+// can be retried.
+func (c *tableCreator) createMissingTables(
+	ctx context.Context, db *sql.DB, events []*dmlsink.TxnCallbackableEvent,
+) bool {
+	created := false
+	seen := make(map[model.TableName]struct{})
+	for _, event := range events {
+		tableInfo := event.Event.TableInfo
+		if tableInfo == nil && len(event.Event.Rows) > 0 {
+			tableInfo = event.Event.Rows[0].TableInfo
+		}
+		if tableInfo == nil {
+			continue
+		}
+		if _, ok := seen[tableInfo.TableName]; ok {
+			continue
+		}
+		seen[tableInfo.TableName] = struct{}{}
+
+		target := c.cfg.TargetTableName(&tableInfo.TableName)
+		ok, err := c.createTableIfNotExists(ctx, db, tableInfo, target)
+		if err != nil {
+			log.Warn("create the missing downstream table failed",
+				zap.String("changefeed", c.changefeed),
+				zap.Stringer("table", target),
+				zap.Error(err))
+			continue
+		}
+		created = created || ok
+	}
+	return created
+}
+
+func (c *tableCreator) createTableIfNotExists(
+	ctx context.Context, db *sql.DB, tableInfo *model.TableInfo, target *model.TableName,
+) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, tableExistsQuery, target.Schema, target.Table).Scan(&count)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	query, err := c.createTableSQL(ctx, tableInfo, target)
+	if err != nil {
+		return false, err
+	}
+	if _, err = db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+quotes.QuoteName(target.Schema)); err != nil {
+		return false, errors.Trace(err)
+	}
+	if _, err = db.ExecContext(ctx, query); err != nil {
+		return false, errors.Trace(err)
+	}
+	c.metricAutoCreateTable.Inc()
+	log.Info("the missing downstream table is created",
+		zap.String("changefeed", c.changefeed),
+		zap.Stringer("table", target),
+		zap.String("query", query))
+	return true, nil
+}
+
+// createTableSQL returns the `CREATE TABLE IF NOT EXISTS` statement of the
+// target table with the schema of the upstream table.
+func (c *tableCreator) createTableSQL(
+	ctx context.Context, tableInfo *model.TableInfo, target *model.TableName,
+) (string, error) {
+	query, err := showCreateTable(tableInfo)
+	if err != nil {
+		if c.cfg.UpstreamTiDBDSN == "" {
+			return "", err
+		}
+		log.Warn("build the create table statement from the table info failed, "+
+			"fetch it from the upstream TiDB",
+			zap.String("changefeed", c.changefeed),
+			zap.Stringer("table", &tableInfo.TableName),
+			zap.Error(err))
+		query, err = c.showCreateTableFromUpstream(ctx, &tableInfo.TableName)
+		if err != nil {
+			return "", err
+		}
+	}
+	return rewriteCreateTable(query, target)
+}
+
+func showCreateTable(tableInfo *model.TableInfo) (string, error) {
+	if tableInfo == nil || tableInfo.TableInfo == nil {
+		return "", errors.New("the table info is unavailable")
+	}
+	result := bytes.NewBuffer(make([]byte, 0, 512))
+	err := executor.ConstructResultOfShowCreateTable(
+		mock.NewContext(), tableInfo.TableInfo, autoid.Allocators{}, result)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.String(), nil
+}
+
+func (c *tableCreator) showCreateTableFromUpstream(
+	ctx context.Context, table *model.TableName,
+) (string, error) {
+	upstream, err := c.getUpstream(ctx)
+	if err != nil {
+		return "", err
+	}
+	var name, query string
+	err = upstream.QueryRowContext(ctx, "SHOW CREATE TABLE "+table.QuoteString()).Scan(&name, &query)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return query, nil
+}
+
+func (c *tableCreator) getUpstream(ctx context.Context) (*sql.DB, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.upstream == nil {
+		db, err := c.dbConnFactory(ctx, c.cfg.UpstreamTiDBDSN)
+		if err != nil {
+			return nil, err
+		}
+		c.upstream = db
+	}
+	return c.upstream, nil
+}
+
+func (c *tableCreator) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.upstream != nil {
+		if err := c.upstream.Close(); err != nil {
+			log.Warn("close the upstream TiDB connection failed",
+				zap.String("changefeed", c.changefeed), zap.Error(err))
+		}
+		c.upstream = nil
+	}
+}
+
+// rewriteCreateTable renames the table of the `CREATE TABLE` statement to
+// the target table, and adds `IF NOT EXISTS` to it. The TiDB specific
+// features are restored as special comments.
+func rewriteCreateTable(query string, target *model.TableName) (string, error) {
+	stmt, err := parser.New().ParseOneStmt(query, "", "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	createStmt, ok := stmt.(*ast.CreateTableStmt)
+	if !ok {
+		return "", errors.Errorf("unexpected create table statement %s", query)
+	}
+	createStmt.IfNotExists = true
+	createStmt.Table.Schema = timodel.NewCIStr(target.Schema)
+	createStmt.Table.Name = timodel.NewCIStr(target.Table)
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes |
+		format.SkipPlacementRuleForRestore |
+		format.RestoreWithTTLEnableOff
+	if err = createStmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
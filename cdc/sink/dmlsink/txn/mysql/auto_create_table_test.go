@@ -0,0 +1,156 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteCreateTable(t *testing.T) {
+	t.Parallel()
+
+	query, err := rewriteCreateTable("CREATE TABLE `t1` (`a` INT PRIMARY KEY,`b` VARCHAR(10))",
+		&model.TableName{Schema: "s2", Table: "t2"})
+	require.NoError(t, err)
+	require.Equal(t,
+		"CREATE TABLE IF NOT EXISTS `s2`.`t2` (`a` INT PRIMARY KEY,`b` VARCHAR(10))", query)
+
+	_, err = rewriteCreateTable("DROP TABLE `t1`", &model.TableName{Schema: "s2", Table: "t2"})
+	require.Error(t, err)
+}
+
+func TestCreateTableSQLFromUpstream(t *testing.T) {
+	t.Parallel()
+
+	upstream, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	mock.ExpectQuery("SHOW CREATE TABLE `s1`.`t1`").
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+			AddRow("t1", "CREATE TABLE `t1` (`a` INT PRIMARY KEY)"))
+	mock.ExpectClose()
+
+	cfg := pmysql.NewConfig()
+	cfg.UpstreamTiDBDSN = "root@tcp(127.0.0.1:4000)/"
+	creator := newTableCreator(model.DefaultChangeFeedID("test"), "default.test", cfg,
+		func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			require.Equal(t, cfg.UpstreamTiDBDSN, dsnStr)
+			return upstream, nil
+		})
+
+	// The table info isn't available, the schema is fetched from the upstream.
+	tableInfo := &model.TableInfo{TableName: model.TableName{Schema: "s1", Table: "t1"}}
+	query, err := creator.createTableSQL(context.Background(), tableInfo,
+		&model.TableName{Schema: "s2", Table: "t2"})
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE IF NOT EXISTS `s2`.`t2` (`a` INT PRIMARY KEY)", query)
+
+	creator.close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateMissingTable(t *testing.T) {
+	// The table t1 was created upstream before the changefeed started, so it
+	// doesn't exist in the downstream.
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+	}, [][]int{{0}})
+	rows := []*model.RowChangedEvent{
+		{
+			TableInfo:       tableInfo,
+			PhysicalTableID: 1,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "a", Value: 1},
+			}, tableInfo),
+		},
+	}
+	createTable, err := showCreateTable(tableInfo)
+	require.NoError(t, err)
+	createTable, err = rewriteCreateTable(createTable, &tableInfo.TableName)
+	require.NoError(t, err)
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1).
+			WillReturnError(&dmysql.MySQLError{Number: mysql.ErrNoSuchTable})
+		mock.ExpectRollback()
+		mock.ExpectQuery(tableExistsQuery).
+			WithArgs("s1", "t1").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+		mock.ExpectExec("CREATE DATABASE IF NOT EXISTS `s1`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(createTable).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeed := "test-create-missing-table"
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		CreateTableIfNotExists: util.AddressOf(true),
+	}
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(changefeed), sinkURI,
+		replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+	counter := txn.AutoCreateTableCounter.WithLabelValues("default", changefeed)
+
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{TableInfo: tableInfo, Rows: rows},
+	})
+	require.Nil(t, sink.Flush(context.Background()))
+	require.Equal(t, float64(1), testutil.ToFloat64(counter))
+	require.Nil(t, sink.Close())
+}
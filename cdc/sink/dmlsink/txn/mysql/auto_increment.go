@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+)
+
+const insertPrefix = "INSERT INTO "
+
+// hasAutoIncrementColumn returns whether the table has an AUTO_INCREMENT
+// column, whose IDs generated upstream may collide with the IDs generated
+// by the writes to the downstream.
+func hasAutoIncrementColumn(tableInfo *model.TableInfo) bool {
+	if tableInfo == nil || tableInfo.TableInfo == nil {
+		return false
+	}
+	for _, col := range tableInfo.Columns {
+		if mysql.HasAutoIncrementFlag(col.GetFlag()) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteAutoIncrementInsert rewrites the INSERT statement of a table with an
+// AUTO_INCREMENT column by the policy. The conflicting rows are overwritten
+// by `REPLACE INTO` in the reassign mode, and are kept by `INSERT IGNORE` in
+// the skip mode.
+func rewriteAutoIncrementInsert(query string, policy string) string {
+	if !strings.HasPrefix(query, insertPrefix) {
+		return query
+	}
+	switch policy {
+	case pmysql.AutoIncrementConflictPolicyReassign:
+		return "REPLACE INTO " + query[len(insertPrefix):]
+	case pmysql.AutoIncrementConflictPolicySkip:
+		return "INSERT IGNORE INTO " + query[len(insertPrefix):]
+	}
+	return query
+}
+
+// autoIncrementConflicts returns the number of the conflicting rows of an
+// INSERT statement rewritten by rewriteAutoIncrementInsert. A row replaced
+// by `REPLACE INTO` is affected twice, and a row ignored by `INSERT IGNORE`
+// isn't affected.
+func autoIncrementConflicts(policy string, rows int, affected int64) int {
+	var conflicts int64
+	switch policy {
+	case pmysql.AutoIncrementConflictPolicyReassign:
+		conflicts = affected - int64(rows)
+	case pmysql.AutoIncrementConflictPolicySkip:
+		conflicts = int64(rows) - affected
+	}
+	if conflicts < 0 {
+		return 0
+	}
+	return int(conflicts)
+}
+
+func isDupEntryError(err error) bool {
+	errCode, ok := getSQLErrCode(err)
+	return ok && errCode == mysql.ErrDupEntry
+}
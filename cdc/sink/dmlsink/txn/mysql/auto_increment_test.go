@@ -0,0 +1,217 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newAutoIncrementTableInfo() *model.TableInfo {
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "id",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+		{
+			Name: "name",
+			Type: mysql.TypeVarchar,
+		},
+	}, [][]int{{0}})
+	tableInfo.Columns[0].AddFlag(mysql.AutoIncrementFlag)
+	return tableInfo
+}
+
+func newAutoIncrementInsert(tableInfo *model.TableInfo) *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		StartTs:         1,
+		CommitTs:        2,
+		ReplicatingTs:   1,
+		TableInfo:       tableInfo,
+		PhysicalTableID: 1,
+		Columns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: 1},
+			{Name: "name", Value: "a"},
+		}, tableInfo),
+	}
+}
+
+func TestHasAutoIncrementColumn(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, hasAutoIncrementColumn(newAutoIncrementTableInfo()))
+	require.False(t, hasAutoIncrementColumn(model.BuildTableInfo("s1", "t1", []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+	}, [][]int{{0}})))
+	require.False(t, hasAutoIncrementColumn(nil))
+}
+
+func TestPrepareAutoIncrementDMLs(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := newAutoIncrementTableInfo()
+	testCases := []struct {
+		policy   string
+		expected string
+	}{
+		{
+			policy:   pmysql.AutoIncrementConflictPolicyError,
+			expected: "INSERT INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)",
+		},
+		{
+			policy:   pmysql.AutoIncrementConflictPolicyReassign,
+			expected: "REPLACE INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)",
+		},
+		{
+			policy:   pmysql.AutoIncrementConflictPolicySkip,
+			expected: "INSERT IGNORE INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)",
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tc := range testCases {
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.cfg.AutoIncrementConflictPolicy = tc.policy
+		dmls := ms.prepareDMLsOf([]*dmlsink.TxnCallbackableEvent{{
+			Event: &model.SingleTableTxn{
+				Rows: []*model.RowChangedEvent{newAutoIncrementInsert(tableInfo)},
+			},
+		}})
+		require.Equal(t, []string{tc.expected}, dmls.sqls, tc.policy)
+		require.Equal(t, map[int]int{0: 1}, dmls.autoIncrementRows, tc.policy)
+	}
+
+	// The rows which may have been replicated are written by REPLACE INTO
+	// regardless of the policy.
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.AutoIncrementConflictPolicy = pmysql.AutoIncrementConflictPolicySkip
+	row := newAutoIncrementInsert(tableInfo)
+	row.ReplicatingTs = row.CommitTs
+	dmls := ms.prepareDMLsOf([]*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{row}},
+	}})
+	require.Equal(t, []string{"REPLACE INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)"}, dmls.sqls)
+	require.Empty(t, dmls.autoIncrementRows)
+}
+
+func TestAutoIncrementConflictPolicy(t *testing.T) {
+	testCases := []struct {
+		policy string
+		expect func(mock sqlmock.Sqlmock)
+		hasErr bool
+	}{
+		{
+			policy: pmysql.AutoIncrementConflictPolicyError,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)").
+					WithArgs(1, "a").
+					WillReturnError(&dmysql.MySQLError{Number: mysql.ErrDupEntry})
+				mock.ExpectRollback()
+			},
+			hasErr: true,
+		},
+		{
+			policy: pmysql.AutoIncrementConflictPolicyReassign,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				// The existing row is deleted and the row is inserted.
+				mock.ExpectExec("REPLACE INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)").
+					WithArgs(1, "a").
+					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			policy: pmysql.AutoIncrementConflictPolicySkip,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT IGNORE INTO `s1`.`t1` (`id`,`name`) VALUES (?,?)").
+					WithArgs(1, "a").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	tableInfo := newAutoIncrementTableInfo()
+	for _, tc := range testCases {
+		var mock sqlmock.Sqlmock
+		dbIndex := 0
+		mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			defer func() { dbIndex++ }()
+
+			if dbIndex == 0 {
+				// test db
+				db, err := pmysql.MockTestDB()
+				require.Nil(t, err)
+				return db, nil
+			}
+
+			// normal db
+			var db *sql.DB
+			db, mock = newTestMockDB(t)
+			tc.expect(mock)
+			mock.ExpectClose()
+			return db, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		changefeed := "test-auto-increment-conflict-" + tc.policy
+		sinkURI, err := url.Parse(
+			"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+		require.Nil(t, err)
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+			AutoIncrementConflictPolicy: util.AddressOf(tc.policy),
+		}
+		sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(changefeed), sinkURI,
+			replicaConfig, mockGetDBConn)
+		require.Nil(t, err)
+		sink.setDMLMaxRetry(1)
+		counter := txn.AutoIncrementConflictCounter.WithLabelValues("default", changefeed, tc.policy)
+
+		_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{
+				Rows: []*model.RowChangedEvent{newAutoIncrementInsert(tableInfo)},
+			},
+		})
+		err = sink.Flush(context.Background())
+		if tc.hasErr {
+			require.Error(t, err, tc.policy)
+		} else {
+			require.Nil(t, err, tc.policy)
+		}
+		require.Equal(t, float64(1), testutil.ToFloat64(counter), tc.policy)
+		require.Nil(t, sink.Close())
+		require.NoError(t, mock.ExpectationsWereMet(), tc.policy)
+		cancel()
+	}
+}
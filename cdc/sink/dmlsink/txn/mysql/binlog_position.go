@@ -0,0 +1,103 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+)
+
+var createBinlogPositionTableSQL = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s
+(
+	changefeed_id varchar(255),
+	checkpoint_ts bigint unsigned,
+	binlog_file varchar(255),
+	binlog_pos bigint unsigned,
+	gtid_set text,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX (created_at),
+	PRIMARY KEY (changefeed_id, checkpoint_ts)
+)`, filter.TiCDCSystemSchema, filter.BinlogPositionTable)
+
+// binlogPositionTracker records the binlog position of a MySQL downstream
+// after each DML batch, so that users can find the binlog position which
+// corresponds to a checkpoint ts for point-in-time recovery.
+// It is shared by all backends created by NewMySQLBackends.
+type binlogPositionTracker struct {
+	changefeed string
+
+	// mu makes sure positions are queried and recorded in order,
+	// so positions in the table increase with the insertion order.
+	mu     sync.Mutex
+	latest *model.BinlogPosition
+}
+
+func newBinlogPositionTracker(
+	ctx context.Context, db *sql.DB, changefeed string,
+) (*binlogPositionTracker, error) {
+	if _, err := db.ExecContext(ctx,
+		"CREATE DATABASE IF NOT EXISTS "+filter.TiCDCSystemSchema); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	if _, err := db.ExecContext(ctx, createBinlogPositionTableSQL); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	return &binlogPositionTracker{changefeed: changefeed}, nil
+}
+
+// record queries the current binlog position of the downstream and writes it
+// to the position table. All transactions with CommitTs <= checkpointTs in
+// the batch have been written before the position.
+func (t *binlogPositionTracker) record(
+	ctx context.Context, db *sql.DB, checkpointTs model.Ts,
+) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, pos, gtidSet, err := pmysql.QueryBinlogPosition(ctx, db)
+	if err != nil {
+		return err
+	}
+	// Batches written by different workers may have the same commit ts,
+	// the later one has a larger position and replaces the former.
+	query := "REPLACE INTO " + filter.TiCDCSystemSchema + "." + filter.BinlogPositionTable +
+		"(changefeed_id, checkpoint_ts, binlog_file, binlog_pos, gtid_set) VALUES (?,?,?,?,?)"
+	if _, err = db.ExecContext(ctx, query, t.changefeed, checkpointTs, file, pos, gtidSet); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	t.latest = &model.BinlogPosition{
+		CheckpointTs: checkpointTs,
+		File:         file,
+		Pos:          pos,
+		GTIDSet:      gtidSet,
+	}
+	return nil
+}
+
+func (t *binlogPositionTracker) get() *model.BinlogPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.latest == nil {
+		return nil
+	}
+	pos := *t.latest
+	return &pos
+}
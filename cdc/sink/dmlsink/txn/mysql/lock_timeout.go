@@ -0,0 +1,73 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	// lockTimeoutBackoffBaseDelay is the base delay of retrying a DML batch
+	// failed by lock wait timeout.
+	lockTimeoutBackoffBaseDelay = 50 * time.Millisecond
+	// lockTimeoutBackoffMaxDelay is the max delay of retrying a DML batch
+	// failed by lock wait timeout.
+	lockTimeoutBackoffMaxDelay = 30 * time.Second
+)
+
+// isLockTimeoutError returns true if the error is caused by the lock wait
+// timeout of a pessimistic transaction in the downstream.
+func isLockTimeoutError(err error) bool {
+	errCode, ok := getSQLErrCode(err)
+	return ok && errCode == mysql.ErrLockWaitTimeout
+}
+
+// execDMLWithMaxRetries executes the DMLs and retries the lock wait timeout
+// errors with exponential backoff, since the lock contention in the downstream
+// usually lasts much longer than other retryable errors. The changefeed fails
+// with ErrMySQLLockContention once the retries are exhausted.
+func (s *mysqlBackend) execDMLWithMaxRetries(
+	ctx context.Context, db *sql.DB, dmls *preparedDMLs,
+) error {
+	maxRetries := s.cfg.MaxLockTimeoutRetries
+	tries := 0
+	err := retry.Do(ctx, func() error {
+		if tries > 0 {
+			s.metricLockTimeoutRetry.Inc()
+			log.Warn("retry DMLs failed by lock wait timeout",
+				zap.String("changefeed", s.changefeed),
+				zap.Int("workerID", s.workerID),
+				zap.Int("retry", tries),
+				zap.Int("maxRetries", maxRetries))
+		}
+		tries++
+		return s.execDMLWithRetries(ctx, db, dmls)
+	}, retry.WithBackoffBaseDelay(lockTimeoutBackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(lockTimeoutBackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(uint64(maxRetries)+1),
+		retry.WithIsRetryableErr(isLockTimeoutError))
+	if err != nil && isLockTimeoutError(err) {
+		return cerror.WrapError(cerror.ErrMySQLLockContention, errors.Cause(err), maxRetries)
+	}
+	return err
+}
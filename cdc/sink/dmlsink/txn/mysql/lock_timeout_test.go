@@ -0,0 +1,128 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecDMLLockTimeoutRetry(t *testing.T) {
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+	}, [][]int{{0}})
+	rows := []*model.RowChangedEvent{
+		{
+			TableInfo:       tableInfo,
+			PhysicalTableID: 1,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "a", Value: 1},
+			}, tableInfo),
+		},
+	}
+	errLockTimeout := &dmysql.MySQLError{
+		Number:  mysql.ErrLockWaitTimeout,
+		Message: "Lock wait timeout exceeded; try restarting transaction",
+	}
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		// The first batch succeeds after 2 retries.
+		for i := 0; i < 2; i++ {
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(1).
+				WillReturnError(errLockTimeout)
+			mock.ExpectRollback()
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		// The second batch fails after the retries are exhausted.
+		for i := 0; i < 3; i++ {
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(1).
+				WillReturnError(errLockTimeout)
+			mock.ExpectRollback()
+		}
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeed := "test-lock-timeout"
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		MaxLockTimeoutRetries: util.AddressOf(2),
+	}
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(changefeed), sinkURI,
+		replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+	counter := txn.LockTimeoutRetryCounter.WithLabelValues("default", changefeed)
+
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{Rows: rows},
+	})
+	require.Nil(t, sink.Flush(context.Background()))
+	require.Equal(t, float64(2), testutil.ToFloat64(counter))
+
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{Rows: rows},
+	})
+	err = sink.Flush(context.Background())
+	require.Regexp(t, "CDC:ErrMySQLLockContention.*after 2 retries", err)
+	require.True(t, cerror.ShouldFailChangefeed(err))
+	require.Equal(t, errLockTimeout, errors.Cause(err))
+	require.Equal(t, float64(4), testutil.ToFloat64(counter))
+
+	require.Nil(t, sink.Close())
+}
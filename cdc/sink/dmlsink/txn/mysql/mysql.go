@@ -21,6 +21,7 @@ import (
 	"math"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	dmysql "github.com/go-sql-driver/mysql"
@@ -46,9 +47,6 @@ import (
 )
 
 const (
-	// Max interval for flushing transactions to the downstream.
-	maxFlushInterval = 10 * time.Millisecond
-
 	// networkDriftDuration is used to construct a context timeout for database operations.
 	networkDriftDuration = 5 * time.Second
 
@@ -56,8 +54,42 @@ const (
 
 	// To limit memory usage for prepared statements.
 	prepStmtCacheSize int = 16 * 1024
+
+	// gtidQueryInterval limits how often gtid_executed is fetched from the downstream.
+	gtidQueryInterval = time.Second
 )
 
+// gtidTracker records the latest gtid_executed of a MySQL downstream.
+// It is shared by all backends created by NewMySQLBackends.
+type gtidTracker struct {
+	mu           sync.Mutex
+	lastQueryAt  time.Time
+	gtidExecuted string
+}
+
+// shouldQuery returns true if it's time to fetch gtid_executed again.
+func (t *gtidTracker) shouldQuery(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now.Sub(t.lastQueryAt) < gtidQueryInterval {
+		return false
+	}
+	t.lastQueryAt = now
+	return true
+}
+
+func (t *gtidTracker) set(gtidExecuted string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gtidExecuted = gtidExecuted
+}
+
+func (t *gtidTracker) get() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.gtidExecuted
+}
+
 type mysqlBackend struct {
 	workerID    int
 	changefeed  string
@@ -72,12 +104,30 @@ type mysqlBackend struct {
 	metricTxnSinkDMLBatchCommit     prometheus.Observer
 	metricTxnSinkDMLBatchCallback   prometheus.Observer
 	metricTxnPrepareStatementErrors prometheus.Counter
+	metricLockTimeoutRetry          prometheus.Counter
+	metricAutoIncrementConflict     prometheus.Counter
 
 	// implement stmtCache to improve performance, especially when the downstream is TiDB
 	stmtCache *lru.Cache
 	// Indicate if the CachePrepStmts should be enabled or not
 	cachePrepStmts   bool
 	maxAllowedPacket int64
+
+	// gtid is nil if the downstream is TiDB, which doesn't support GTID.
+	gtid *gtidTracker
+	// binlogPosition is nil unless track-binlog-position is enabled
+	// and the downstream is MySQL.
+	binlogPosition *binlogPositionTracker
+	// ordering is nil unless verify-ordering is enabled.
+	ordering *orderingVerifier
+	// tablePools is nil unless connection-pool-per-table is enabled.
+	tablePools *tablePools
+	// audit is nil unless audit-side-channel is set.
+	audit *auditSideChannel
+	// writeAmplification is shared by all backends of the sink.
+	writeAmplification *writeAmplification
+	// tableCreator is nil unless create-table-if-not-exists is enabled.
+	tableCreator *tableCreator
 }
 
 // NewMySQLBackends creates a new MySQL sink using schema storage
@@ -111,6 +161,12 @@ func NewMySQLBackends(
 	if err != nil {
 		return nil, err
 	}
+	if cfg.IsTiDB {
+		err = checkDownstreamVersion(ctx, sinkURI.Host, db, replicaConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	cfg.IsWriteSourceExisted, err = pmysql.CheckIfBDRModeIsSupported(ctx, db)
 	if err != nil {
@@ -135,6 +191,13 @@ func NewMySQLBackends(
 
 	// Inherit the default value of the prepared statement cache from the SinkURI Options
 	cachePrepStmts := cfg.CachePrepStmts
+	// Prepared statements belong to a connection pool, they can't be shared
+	// by the pools of tables.
+	if cachePrepStmts && cfg.ConnectionPoolPerTable {
+		log.Info("cache-prep-stmts is disabled since connection-pool-per-table is enabled",
+			zap.String("changefeed", changefeed))
+		cachePrepStmts = false
+	}
 	if cachePrepStmts {
 		// query the size of the prepared statement cache on serverside
 		maxPreparedStmtCount, err := pmysql.QueryMaxPreparedStmtCount(ctx, db)
@@ -155,6 +218,11 @@ func NewMySQLBackends(
 		}
 	}
 
+	var pools *tablePools
+	if cfg.ConnectionPoolPerTable {
+		pools = newTablePools(changefeed, dsnStr, dbConnFactory, cfg.WorkerCount+1)
+	}
+
 	var stmtCache *lru.Cache
 	if cachePrepStmts {
 		stmtCache, err = lru.NewWithEvict(prepStmtCacheSize, func(key, value interface{}) {
@@ -175,6 +243,43 @@ func NewMySQLBackends(
 		maxAllowedPacket = int64(variable.DefMaxAllowedPacket)
 	}
 
+	var gtid *gtidTracker
+	if !cfg.IsTiDB {
+		gtid = &gtidTracker{}
+	}
+	var binlogPosition *binlogPositionTracker
+	if cfg.TrackBinlogPosition {
+		if cfg.IsTiDB {
+			log.Warn("track-binlog-position is ignored since the downstream is TiDB",
+				zap.String("changefeed", changefeed))
+		} else {
+			binlogPosition, err = newBinlogPositionTracker(ctx, db, changefeed)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	var audit *auditSideChannel
+	if cfg.AuditSideChannel != "" {
+		audit, err = newAuditSideChannel(ctx, db,
+			cfg.AuditSideChannel, cfg.AuditSideChannelCompression)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var ordering *orderingVerifier
+	if cfg.VerifyOrdering {
+		log.Warn("verify-ordering is enabled, it should only be used in tests",
+			zap.String("changefeed", changefeed))
+		ordering = newOrderingVerifier()
+	}
+
+	var creator *tableCreator
+	if cfg.CreateTableIfNotExists {
+		creator = newTableCreator(changefeedID, changefeed, cfg, dbConnFactory)
+	}
+
+	amplification := newWriteAmplification(changefeedID)
 	backends := make([]*mysqlBackend, 0, cfg.WorkerCount)
 	for i := 0; i < cfg.WorkerCount; i++ {
 		backends = append(backends, &mysqlBackend{
@@ -188,9 +293,18 @@ func NewMySQLBackends(
 			metricTxnSinkDMLBatchCommit:     txn.SinkDMLBatchCommit.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 			metricTxnSinkDMLBatchCallback:   txn.SinkDMLBatchCallback.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 			metricTxnPrepareStatementErrors: txn.PrepareStatementErrors.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+			metricLockTimeoutRetry:          txn.LockTimeoutRetryCounter.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+			metricAutoIncrementConflict:     txn.AutoIncrementConflictCounter.WithLabelValues(changefeedID.Namespace, changefeedID.ID, cfg.AutoIncrementConflictPolicy),
 			stmtCache:                       stmtCache,
 			cachePrepStmts:                  cachePrepStmts,
 			maxAllowedPacket:                maxAllowedPacket,
+			gtid:                            gtid,
+			binlogPosition:                  binlogPosition,
+			ordering:                        ordering,
+			tablePools:                      pools,
+			audit:                           audit,
+			writeAmplification:              amplification,
+			tableCreator:                    creator,
 		})
 	}
 
@@ -222,20 +336,77 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 		failpoint.Return(errors.Trace(dmysql.ErrInvalidConn))
 	})
 
+	if s.ordering != nil {
+		if err := s.ordering.check(s.changefeed, s.events); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	for _, event := range s.events {
 		s.statistics.ObserveRows(event.Event.Rows...)
 	}
 
-	dmls := s.prepareDMLs()
-	log.Debug("prepare DMLs", zap.String("changefeed", s.changefeed), zap.Any("rows", s.rows),
+	if s.tablePools == nil {
+		err = s.flushEvents(ctx, s.db, s.events)
+	} else {
+		err = s.flushEventsByTable(ctx)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.updateGTIDExecuted(ctx)
+	s.recordBinlogPosition(ctx)
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return
+}
+
+// flushEvents writes the events to the downstream in one transaction.
+func (s *mysqlBackend) flushEvents(
+	ctx context.Context, db *sql.DB, events []*dmlsink.TxnCallbackableEvent,
+) error {
+	dmls := s.prepareDMLsOf(events)
+	log.Debug("prepare DMLs", zap.String("changefeed", s.changefeed), zap.Any("rows", dmls.rowCount),
 		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+	if s.cfg.DebugSQLPreview {
+		s.previewSQL(ctx, dmls)
+	}
 
 	start := time.Now()
-	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
+	err := s.execDMLWithMaxRetries(ctx, db, dmls)
+	if err != nil && s.tableCreator != nil && isTableNotExistError(err) &&
+		s.tableCreator.createMissingTables(ctx, db, events) {
+		err = s.execDMLWithMaxRetries(ctx, db, dmls)
+	}
+	if err != nil {
+		if len(dmls.autoIncrementRows) > 0 && isDupEntryError(err) {
+			s.metricAutoIncrementConflict.Inc()
+		}
 		if errors.Cause(err) != context.Canceled {
 			log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
 		}
-		return errors.Trace(err)
+		return err
+	}
+	if dmls.autoIncrementConflicts > 0 {
+		log.Warn("AUTO_INCREMENT IDs collide with the rows in the downstream",
+			zap.String("changefeed", s.changefeed),
+			zap.String("policy", s.cfg.AutoIncrementConflictPolicy),
+			zap.Int("conflicts", dmls.autoIncrementConflicts))
+		s.metricAutoIncrementConflict.Add(float64(dmls.autoIncrementConflicts))
+	}
+	if s.ordering != nil {
+		s.ordering.apply(events)
+	}
+	if s.writeAmplification != nil {
+		s.writeAmplification.observe(dmls.tableWrites)
 	}
 	startCallback := time.Now()
 	for _, callback := range dmls.callbacks {
@@ -243,17 +414,25 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 	}
 	s.metricTxnSinkDMLBatchCommit.Observe(startCallback.Sub(start).Seconds())
 	s.metricTxnSinkDMLBatchCallback.Observe(time.Since(startCallback).Seconds())
+	return nil
+}
 
-	// Be friently to GC.
-	for i := 0; i < len(s.events); i++ {
-		s.events[i] = nil
-	}
-	if cap(s.events) > 1024 {
-		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+// flushEventsByTable writes the events of each table in a separate
+// transaction with the connection pool of the table.
+func (s *mysqlBackend) flushEventsByTable(ctx context.Context) error {
+	tableIDs, groups := groupEventsByTable(s.events)
+	for _, tableID := range tableIDs {
+		db, err := s.tablePools.acquire(ctx, tableID)
+		if err != nil {
+			return err
+		}
+		err = s.flushEvents(ctx, db, groups[tableID])
+		s.tablePools.release(tableID)
+		if err != nil {
+			return err
+		}
 	}
-	s.events = s.events[:0]
-	s.rows = 0
-	return
+	return nil
 }
 
 // Close implements interface backend.
@@ -261,6 +440,15 @@ func (s *mysqlBackend) Close() (err error) {
 	if s.stmtCache != nil {
 		s.stmtCache.Purge()
 	}
+	if s.tablePools != nil {
+		s.tablePools.close()
+	}
+	if s.writeAmplification != nil {
+		s.writeAmplification.cleanup()
+	}
+	if s.tableCreator != nil {
+		s.tableCreator.close()
+	}
 	if s.db != nil {
 		err = s.db.Close()
 		s.db = nil
@@ -268,9 +456,66 @@ func (s *mysqlBackend) Close() (err error) {
 	return
 }
 
+// GetGTIDExecuted returns the latest gtid_executed fetched from the downstream.
+// An empty string is returned if the downstream is TiDB or it hasn't been fetched yet.
+func (s *mysqlBackend) GetGTIDExecuted() string {
+	if s.gtid == nil {
+		return ""
+	}
+	return s.gtid.get()
+}
+
+// updateGTIDExecuted fetches gtid_executed from the downstream after a batch
+// is written successfully. Failures are only logged because the GTID position
+// is informative and should never block the replication.
+func (s *mysqlBackend) updateGTIDExecuted(ctx context.Context) {
+	if s.gtid == nil || !s.gtid.shouldQuery(time.Now()) {
+		return
+	}
+	gtidExecuted, err := pmysql.QueryGTIDExecuted(ctx, s.db)
+	if err != nil {
+		log.Warn("failed to query gtid_executed from downstream",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Error(err))
+		return
+	}
+	s.gtid.set(gtidExecuted)
+}
+
+// GetBinlogPosition returns the latest binlog position recorded in the
+// downstream. nil is returned if track-binlog-position is not enabled.
+func (s *mysqlBackend) GetBinlogPosition() *model.BinlogPosition {
+	if s.binlogPosition == nil {
+		return nil
+	}
+	return s.binlogPosition.get()
+}
+
+// recordBinlogPosition records the binlog position of the downstream after
+// a batch is written successfully. Like gtid_executed, failures are only logged.
+func (s *mysqlBackend) recordBinlogPosition(ctx context.Context) {
+	if s.binlogPosition == nil {
+		return
+	}
+	var checkpointTs model.Ts
+	for _, event := range s.events {
+		if event.Event.GetCommitTs() > checkpointTs {
+			checkpointTs = event.Event.GetCommitTs()
+		}
+	}
+	if err := s.binlogPosition.record(ctx, s.db, checkpointTs); err != nil {
+		log.Warn("failed to record binlog position of downstream",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Uint64("checkpointTs", checkpointTs),
+			zap.Error(err))
+	}
+}
+
 // MaxFlushInterval implements interface backend.
 func (s *mysqlBackend) MaxFlushInterval() time.Duration {
-	return maxFlushInterval
+	return s.cfg.MaxBatchWait
 }
 
 type preparedDMLs struct {
@@ -280,14 +525,23 @@ type preparedDMLs struct {
 	callbacks       []dmlsink.CallbackFunc
 	rowCount        int
 	approximateSize int64
+	// tableWrites is the bytes written to downstream of each event.
+	tableWrites []tableWrite
+	// autoIncrementRows maps the indexes of the INSERT statements of the
+	// tables with an AUTO_INCREMENT column to the number of inserted rows.
+	autoIncrementRows map[int]int
+	// autoIncrementConflicts is the number of the inserted rows collided
+	// with the downstream rows, it's set after the DMLs are executed.
+	autoIncrementConflicts int
 }
 
 // convert2RowChanges is a helper function that convert the row change representation
-// of CDC into a general one.
+// of CDC into a general one, the SQLs of the row change are on the targetTable.
 func convert2RowChanges(
 	row *model.RowChangedEvent,
 	tableInfo *model.TableInfo,
 	changeType sqlmodel.RowChangeType,
+	targetTable *model.TableName,
 ) *sqlmodel.RowChange {
 	tidbTableInfo := tableInfo.TableInfo
 	// RowChangedEvent doesn't contain data for virtual columns,
@@ -312,7 +566,7 @@ func convert2RowChanges(
 	case sqlmodel.RowChangeInsert:
 		res = sqlmodel.NewRowChange(
 			&row.TableInfo.TableName,
-			nil,
+			targetTable,
 			nil,
 			postValues,
 			tidbTableInfo,
@@ -320,7 +574,7 @@ func convert2RowChanges(
 	case sqlmodel.RowChangeUpdate:
 		res = sqlmodel.NewRowChange(
 			&row.TableInfo.TableName,
-			nil,
+			targetTable,
 			preValues,
 			postValues,
 			tidbTableInfo,
@@ -328,7 +582,7 @@ func convert2RowChanges(
 	case sqlmodel.RowChangeDelete:
 		res = sqlmodel.NewRowChange(
 			&row.TableInfo.TableName,
-			nil,
+			targetTable,
 			preValues,
 			nil,
 			tidbTableInfo,
@@ -363,6 +617,7 @@ func (s *mysqlBackend) groupRowsByType(
 		preAllocateSize = s.cfg.MaxTxnRow
 	}
 
+	targetTable := s.cfg.TargetTableName(&tableInfo.TableName)
 	insertRow := make([]*sqlmodel.RowChange, 0, preAllocateSize)
 	updateRow := make([]*sqlmodel.RowChange, 0, preAllocateSize)
 	deleteRow := make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -374,7 +629,7 @@ func (s *mysqlBackend) groupRowsByType(
 		if row.IsInsert() {
 			insertRow = append(
 				insertRow,
-				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeInsert))
+				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeInsert, targetTable))
 			if len(insertRow) >= s.cfg.MaxTxnRow {
 				insertRows = append(insertRows, insertRow)
 				insertRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -384,7 +639,7 @@ func (s *mysqlBackend) groupRowsByType(
 		if row.IsDelete() {
 			deleteRow = append(
 				deleteRow,
-				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete))
+				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete, targetTable))
 			if len(deleteRow) >= s.cfg.MaxTxnRow {
 				deleteRows = append(deleteRows, deleteRow)
 				deleteRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -395,14 +650,14 @@ func (s *mysqlBackend) groupRowsByType(
 			if spiltUpdate {
 				deleteRow = append(
 					deleteRow,
-					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete))
+					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete, targetTable))
 				if len(deleteRow) >= s.cfg.MaxTxnRow {
 					deleteRows = append(deleteRows, deleteRow)
 					deleteRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
 				}
 				insertRow = append(
 					insertRow,
-					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeInsert))
+					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeInsert, targetTable))
 				if len(insertRow) >= s.cfg.MaxTxnRow {
 					insertRows = append(insertRows, insertRow)
 					insertRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -410,7 +665,7 @@ func (s *mysqlBackend) groupRowsByType(
 			} else {
 				updateRow = append(
 					updateRow,
-					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeUpdate))
+					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeUpdate, targetTable))
 				if len(updateRow) >= s.cfg.MaxMultiUpdateRowCount {
 					updateRows = append(updateRows, updateRow)
 					updateRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -436,7 +691,7 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 	event *dmlsink.TxnCallbackableEvent,
 	tableInfo *model.TableInfo,
 	translateToInsert bool,
-) (sqls []string, values [][]interface{}) {
+) (sqls []string, values [][]interface{}, autoIncrementRows map[int]int) {
 	insertRows, updateRows, deleteRows := s.groupRowsByType(event, tableInfo, !translateToInsert)
 
 	// handle delete
@@ -475,6 +730,13 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 		for _, rows := range insertRows {
 			if translateToInsert {
 				sql, value := sqlmodel.GenInsertSQL(sqlmodel.DMLInsert, rows...)
+				if hasAutoIncrementColumn(tableInfo) {
+					if autoIncrementRows == nil {
+						autoIncrementRows = make(map[int]int)
+					}
+					autoIncrementRows[len(sqls)] = len(rows)
+					sql = rewriteAutoIncrementInsert(sql, s.cfg.AutoIncrementConflictPolicy)
+				}
 				sqls = append(sqls, sql)
 				values = append(values, value)
 			} else {
@@ -523,22 +785,40 @@ func hasHandleKey(cols []*model.ColumnData, tableInfo *model.TableInfo) bool {
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+	return s.prepareDMLsOf(s.events)
+}
+
+// prepareDMLsOf is like prepareDMLs, but only converts the given events.
+func (s *mysqlBackend) prepareDMLsOf(events []*dmlsink.TxnCallbackableEvent) *preparedDMLs {
+	rows := 0
+	for _, event := range events {
+		rows += len(event.Event.Rows)
+	}
 	// TODO: use a sync.Pool to reduce allocations.
-	startTs := make([]uint64, 0, s.rows)
-	sqls := make([]string, 0, s.rows)
-	values := make([][]interface{}, 0, s.rows)
-	callbacks := make([]dmlsink.CallbackFunc, 0, len(s.events))
+	startTs := make([]uint64, 0, rows)
+	sqls := make([]string, 0, rows)
+	values := make([][]interface{}, 0, rows)
+	callbacks := make([]dmlsink.CallbackFunc, 0, len(events))
 
 	// translateToInsert control the update and insert behavior.
 	translateToInsert := !s.cfg.SafeMode
 
 	rowCount := 0
 	approximateSize := int64(0)
-	for _, event := range s.events {
+	tableWrites := make([]tableWrite, 0, len(events))
+	var autoIncrementRows map[int]int
+	addAutoIncrementRows := func(index, rows int) {
+		if autoIncrementRows == nil {
+			autoIncrementRows = make(map[int]int)
+		}
+		autoIncrementRows[index] = rows
+	}
+	for _, event := range events {
 		if len(event.Event.Rows) == 0 {
 			continue
 		}
 		rowCount += len(event.Event.Rows)
+		sqlStart := len(sqls)
 
 		firstRow := event.Event.Rows[0]
 		if len(startTs) == 0 || startTs[len(startTs)-1] != firstRow.StartTs {
@@ -568,7 +848,10 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			}
 			// only use batch dml when the table has a handle key
 			if hasHandleKey(tableColumns, firstRow.TableInfo) {
-				sql, value := s.batchSingleTxnDmls(event, firstRow.TableInfo, translateToInsert)
+				sql, value, inserts := s.batchSingleTxnDmls(event, firstRow.TableInfo, translateToInsert)
+				for index, rows := range inserts {
+					addAutoIncrementRows(sqlStart+index, rows)
+				}
 				sqls = append(sqls, sql...)
 				values = append(values, value...)
 
@@ -578,11 +861,14 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 				for _, row := range event.Event.Rows {
 					approximateSize += row.ApproximateDataSize
 				}
+				tableWrites = append(tableWrites,
+					newTableWrite(event.Event, sqls[sqlStart:], values[sqlStart:]))
 				continue
 			}
 		}
 
-		quoteTable := firstRow.TableInfo.TableName.QuoteString()
+		quoteTable := s.cfg.TargetTableName(&firstRow.TableInfo.TableName).QuoteString()
+		autoIncrement := translateToInsert && hasAutoIncrementColumn(firstRow.TableInfo)
 		for _, row := range event.Event.Rows {
 			var query string
 			var args []interface{}
@@ -630,6 +916,10 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 					row.GetColumns(),
 					true, /* appendPlaceHolder */
 					translateToInsert)
+				if query != "" && autoIncrement && len(row.PreColumns) == 0 {
+					addAutoIncrementRows(len(sqls), 1)
+					query = rewriteAutoIncrementInsert(query, s.cfg.AutoIncrementConflictPolicy)
+				}
 				if query != "" {
 					sqls = append(sqls, query)
 					values = append(values, args)
@@ -638,6 +928,22 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 
 			approximateSize += int64(len(query)) + row.ApproximateDataSize
 		}
+		tableWrites = append(tableWrites,
+			newTableWrite(event.Event, sqls[sqlStart:], values[sqlStart:]))
+	}
+
+	// Audit rows are appended after all DMLs, so they aren't counted as
+	// writes of the tables.
+	if s.audit != nil {
+		for _, event := range events {
+			if len(event.Event.Rows) == 0 {
+				continue
+			}
+			query, args, size := s.audit.prepareInsert(event.Event)
+			sqls = append(sqls, query)
+			values = append(values, args)
+			approximateSize += size
+		}
 	}
 
 	if len(callbacks) == 0 {
@@ -651,6 +957,9 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 		callbacks:       callbacks,
 		rowCount:        rowCount,
 		approximateSize: approximateSize,
+		tableWrites:     tableWrites,
+
+		autoIncrementRows: autoIncrementRows,
 	}
 }
 
@@ -689,6 +998,7 @@ func (s *mysqlBackend) sequenceExecute(
 	ctx context.Context, dmls *preparedDMLs, tx *sql.Tx, writeTimeout time.Duration,
 ) error {
 	start := time.Now()
+	conflicts := 0
 	for i, query := range dmls.sqls {
 		args := dmls.values[i]
 		log.Debug("exec row", zap.String("changefeed", s.changefeed), zap.Int("workerID", s.workerID),
@@ -710,12 +1020,18 @@ func (s *mysqlBackend) sequenceExecute(
 			}
 		}
 
+		var res sql.Result
 		var execError error
 		if prepStmt == nil {
-			_, execError = tx.ExecContext(ctx, query, args...)
+			res, execError = tx.ExecContext(ctx, query, args...)
 		} else {
 			//nolint:sqlclosecheck
-			_, execError = tx.Stmt(prepStmt).ExecContext(ctx, args...)
+			res, execError = tx.Stmt(prepStmt).ExecContext(ctx, args...)
+		}
+		if rows, ok := dmls.autoIncrementRows[i]; ok && execError == nil {
+			var affected int64
+			affected, execError = res.RowsAffected()
+			conflicts += autoIncrementConflicts(s.cfg.AutoIncrementConflictPolicy, rows, affected)
 		}
 		if execError != nil {
 			err := logDMLTxnErr(
@@ -731,10 +1047,13 @@ func (s *mysqlBackend) sequenceExecute(
 		}
 		cancelFunc()
 	}
+	dmls.autoIncrementConflicts = conflicts
 	return nil
 }
 
-func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *preparedDMLs) error {
+func (s *mysqlBackend) execDMLWithRetries(
+	pctx context.Context, db *sql.DB, dmls *preparedDMLs,
+) error {
 	if len(dmls.sqls) != len(dmls.values) {
 		log.Error("unexpected number of sqls and values",
 			zap.String("changefeed", s.changefeed),
@@ -747,6 +1066,12 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 	// approximateSize is multiplied by 2 because in extreme circustumas, every
 	// byte in dmls can be escaped and adds one byte.
 	fallbackToSeqWay := dmls.approximateSize*2 > s.maxAllowedPacket
+	// The conflicts of the rewritten INSERTs are counted by the affected rows
+	// of each statement, which are only available in the sequence way.
+	if len(dmls.autoIncrementRows) > 0 &&
+		s.cfg.AutoIncrementConflictPolicy != pmysql.AutoIncrementConflictPolicyError {
+		fallbackToSeqWay = true
+	}
 	return retry.Do(pctx, func() error {
 		writeTimeout, _ := time.ParseDuration(s.cfg.WriteTimeout)
 		writeTimeout += networkDriftDuration
@@ -759,7 +1084,7 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 		failpoint.Inject("MySQLSinkHangLongTime", func() { _ = util.Hang(pctx, time.Hour) })
 
 		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
-			tx, err := s.db.BeginTx(pctx, nil)
+			tx, err := db.BeginTx(pctx, nil)
 			if err != nil {
 				return 0, 0, logDMLTxnErr(
 					cerror.WrapError(cerror.ErrMySQLTxnError, err),
@@ -830,7 +1155,7 @@ func logDMLTxnErr(
 	if len(query) > 1024 {
 		query = query[:1024]
 	}
-	if isRetryableDMLError(err) {
+	if isRetryableDMLError(err) || isLockTimeoutError(err) {
 		log.Warn("execute DMLs with error, retry later",
 			zap.Error(err), zap.Duration("duration", time.Since(start)),
 			zap.String("query", query), zap.Int("count", count),
@@ -856,7 +1181,8 @@ func isRetryableDMLError(err error) bool {
 	}
 
 	switch errCode {
-	case mysql.ErrNoSuchTable, mysql.ErrBadDB:
+	// Lock wait timeout is retried by execDMLWithMaxRetries with a longer backoff.
+	case mysql.ErrNoSuchTable, mysql.ErrBadDB, mysql.ErrLockWaitTimeout:
 		return false
 	}
 	return true
@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
@@ -196,6 +197,8 @@ func TestPrepareDML(t *testing.T) {
 		}
 		ms.rows = len(tc.input)
 		dmls := ms.prepareDMLs()
+		// tableWrites is covered by TestWriteAmplification.
+		dmls.tableWrites = nil
 		require.Equal(t, tc.expected, dmls)
 	}
 }
@@ -397,6 +400,77 @@ func TestNewMySQLBackendExecDML(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+func TestMySQLBackendGTIDExecuted(t *testing.T) {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery("select @@global.gtid_executed;").
+			WillReturnRows(sqlmock.NewRows([]string{"@@global.gtid_executed"}).
+				AddRow("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"))
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn)
+	require.Nil(t, err)
+	require.Equal(t, "", sink.GetGTIDExecuted())
+
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+	}, [][]int{{0}})
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:         1,
+			CommitTs:        2,
+			TableInfo:       tableInfo,
+			PhysicalTableID: 1,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{
+					Name:  "a",
+					Value: 1,
+				},
+			}, tableInfo),
+		},
+	}
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: rows},
+		Callback: func() {},
+	})
+
+	require.Nil(t, sink.Flush(context.Background()))
+	require.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5", sink.GetGTIDExecuted())
+
+	// gtid_executed is not queried again within gtidQueryInterval.
+	require.False(t, sink.gtid.shouldQuery(time.Now()))
+
+	require.Nil(t, sink.Close())
+}
+
 func TestExecDMLRollbackErrDatabaseNotExists(t *testing.T) {
 	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
 		{
@@ -1195,6 +1269,8 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 		}
 		ms.rows = len(tc.input)
 		dmls := ms.prepareDMLs()
+		// tableWrites is covered by TestWriteAmplification.
+		dmls.tableWrites = nil
 		require.Equal(t, tc.expected, dmls, tc.name)
 	}
 }
@@ -1540,10 +1616,78 @@ func TestPrepareBatchDMLs(t *testing.T) {
 		}
 		ms.rows = len(tc.input)
 		dmls := ms.prepareDMLs()
+		// tableWrites is covered by TestWriteAmplification.
+		dmls.tableWrites = nil
 		require.Equal(t, tc.expected, dmls)
 	}
 }
 
+func TestPrepareDMLsWithTableNameMappings(t *testing.T) {
+	t.Parallel()
+	tableInfo := model.BuildTableInfo("prod", "users", []*model.Column{{
+		Name: "id",
+		Type: mysql.TypeLong,
+		Flag: model.BinaryFlag | model.PrimaryKeyFlag | model.HandleKeyFlag,
+	}, {
+		Name: "name",
+		Type: mysql.TypeVarchar,
+	}}, [][]int{{0}})
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:   418658114257813514,
+			CommitTs:  418658114257813515,
+			TableInfo: tableInfo,
+			Columns: model.Columns2ColumnDatas([]*model.Column{{
+				Name:  "id",
+				Value: 1,
+			}, {
+				Name:  "name",
+				Value: "alice",
+			}}, tableInfo),
+		},
+		{
+			StartTs:   418658114257813514,
+			CommitTs:  418658114257813515,
+			TableInfo: tableInfo,
+			PreColumns: model.Columns2ColumnDatas([]*model.Column{{
+				Name:  "id",
+				Value: 2,
+			}, {
+				Name:  "name",
+				Value: "bob",
+			}}, tableInfo),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.TableNameMappings = []*config.TableNameMapping{{
+		SourceSchema: "prod",
+		SourceTable:  "users",
+		TargetSchema: "staging",
+		TargetTable:  "users_v2",
+	}}
+	for _, batchDMLEnable := range []bool{false, true} {
+		ms := newMySQLBackendWithoutDB(ctx)
+		sinkURI, err := url.Parse("mysql://127.0.0.1:3306/")
+		require.NoError(t, err)
+		require.NoError(t, ms.cfg.Apply("UTC", model.DefaultChangeFeedID("test"), sinkURI, replicaConfig))
+		ms.cfg.BatchDMLEnable = batchDMLEnable
+		ms.cfg.SafeMode = false
+		ms.events = []*dmlsink.TxnCallbackableEvent{{
+			Event: &model.SingleTableTxn{Rows: rows},
+		}}
+		ms.rows = len(rows)
+		dmls := ms.prepareDMLs()
+		require.Len(t, dmls.sqls, 2)
+		for _, query := range dmls.sqls {
+			require.Contains(t, query, "`staging`.`users_v2`")
+			require.NotContains(t, query, "`prod`")
+		}
+	}
+}
+
 func TestGroupRowsByType(t *testing.T) {
 	ctx := context.Background()
 	ms := newMySQLBackendWithoutDB(ctx)
@@ -1774,3 +1918,108 @@ func TestBackendGenUpdateSQL(t *testing.T) {
 		require.Equal(t, tc.expectedValues, values)
 	}
 }
+
+func TestMySQLBackendTrackBinlogPosition(t *testing.T) {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectExec("CREATE DATABASE IF NOT EXISTS tidb_cdc").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(createBinlogPositionTableSQL).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		for i, pos := range []uint64{1024, 2048} {
+			value := i + 1
+			gtidSet := fmt.Sprintf("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-%d", value)
+			mock.ExpectBegin()
+			mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(value).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			if i == 0 {
+				mock.ExpectQuery("select @@global.gtid_executed;").
+					WillReturnRows(sqlmock.NewRows([]string{"@@global.gtid_executed"}).
+						AddRow(gtidSet))
+			}
+			mock.ExpectQuery("SHOW MASTER STATUS").
+				WillReturnRows(sqlmock.NewRows([]string{
+					"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set",
+				}).AddRow("mysql-bin.000001", pos, "", "", gtidSet))
+			mock.ExpectExec("REPLACE INTO tidb_cdc._ticdc_binlog_positions"+
+				"(changefeed_id, checkpoint_ts, binlog_file, binlog_pos, gtid_set) VALUES (?,?,?,?,?)").
+				WithArgs("default.test-changefeed", uint64(value+1), "mysql-bin.000001", pos, gtidSet).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		TrackBinlogPosition: util.AddressOf(true),
+	}
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+	require.Nil(t, sink.GetBinlogPosition())
+
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+	}, [][]int{{0}})
+	var positions []*model.BinlogPosition
+	for i := 1; i <= 2; i++ {
+		rows := []*model.RowChangedEvent{
+			{
+				StartTs:         uint64(i),
+				CommitTs:        uint64(i + 1),
+				TableInfo:       tableInfo,
+				PhysicalTableID: 1,
+				Columns: model.Columns2ColumnDatas([]*model.Column{
+					{
+						Name:  "a",
+						Value: i,
+					},
+				}, tableInfo),
+			},
+		}
+		_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+			Event:    &model.SingleTableTxn{CommitTs: uint64(i + 1), Rows: rows},
+			Callback: func() {},
+		})
+		require.Nil(t, sink.Flush(context.Background()))
+		positions = append(positions, sink.GetBinlogPosition())
+	}
+
+	require.Equal(t, &model.BinlogPosition{
+		CheckpointTs: 2,
+		File:         "mysql-bin.000001",
+		Pos:          1024,
+		GTIDSet:      "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-1",
+	}, positions[0])
+	require.Equal(t, &model.BinlogPosition{
+		CheckpointTs: 3,
+		File:         "mysql-bin.000001",
+		Pos:          2048,
+		GTIDSet:      "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-2",
+	}, positions[1])
+
+	require.Nil(t, sink.Close())
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// orderingVerifier verifies that txns of the same table are written to the
+// downstream in CommitTs order. It is shared by all backends created by
+// NewMySQLBackends, and only used if verify-ordering is enabled, which
+// requires only one worker, because txns which don't conflict with each
+// other can be written out of order by different workers.
+type orderingVerifier struct {
+	mu sync.Mutex
+	// tables records the last applied txn of each physical table.
+	tables map[int64]tableOrdering
+}
+
+// tableOrdering is the last applied txn of a table. When the table sink is
+// restarted, txns are replayed from the checkpoint with a greater
+// ReplicatingTs, so the CommitTs is compared only if the ReplicatingTs is
+// the same.
+type tableOrdering struct {
+	replicatingTs uint64
+	commitTs      uint64
+}
+
+func newOrderingVerifier() *orderingVerifier {
+	return &orderingVerifier{tables: make(map[int64]tableOrdering)}
+}
+
+func txnReplicatingTs(txn *model.SingleTableTxn) uint64 {
+	if len(txn.Rows) == 0 {
+		return 0
+	}
+	return txn.Rows[0].ReplicatingTs
+}
+
+// check returns ErrOutOfOrderEvent if the CommitTs of any txn in the batch is
+// less than the last applied CommitTs of its table, or of a previous txn of the
+// same table in the batch.
+func (v *orderingVerifier) check(changefeed string, events []*dmlsink.TxnCallbackableEvent) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	batch := make(map[int64]tableOrdering)
+	for _, event := range events {
+		txn := event.Event
+		last, ok := batch[txn.PhysicalTableID]
+		if !ok {
+			last = v.tables[txn.PhysicalTableID]
+		}
+		current := tableOrdering{replicatingTs: txnReplicatingTs(txn), commitTs: txn.CommitTs}
+		if current.replicatingTs < last.replicatingTs {
+			// The txn is sent by a table sink which has been restarted.
+			continue
+		}
+		if current.replicatingTs == last.replicatingTs && current.commitTs < last.commitTs {
+			log.Error("out of order txn detected",
+				zap.String("changefeed", changefeed),
+				zap.Int64("tableID", txn.PhysicalTableID),
+				zap.Stringer("table", txn.TableInfo.TableName),
+				zap.Uint64("startTs", txn.StartTs),
+				zap.Uint64("commitTs", txn.CommitTs),
+				zap.Uint64("lastCommitTs", last.commitTs),
+				zap.Any("rows", txn.Rows))
+			return cerror.ErrOutOfOrderEvent.GenWithStackByArgs(
+				txn.PhysicalTableID, txn.CommitTs, last.commitTs)
+		}
+		batch[txn.PhysicalTableID] = current
+	}
+	return nil
+}
+
+// apply records the CommitTs of txns which have been written to the downstream.
+func (v *orderingVerifier) apply(events []*dmlsink.TxnCallbackableEvent) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, event := range events {
+		txn := event.Event
+		last := v.tables[txn.PhysicalTableID]
+		current := tableOrdering{replicatingTs: txnReplicatingTs(txn), commitTs: txn.CommitTs}
+		if current.replicatingTs > last.replicatingTs ||
+			(current.replicatingTs == last.replicatingTs && current.commitTs > last.commitTs) {
+			v.tables[txn.PhysicalTableID] = current
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrderingTestEvent(tableID int64, commitTs uint64) *dmlsink.TxnCallbackableEvent {
+	return newOrderingTestEventWithReplicatingTs(tableID, commitTs, 1)
+}
+
+func newOrderingTestEventWithReplicatingTs(
+	tableID int64, commitTs, replicatingTs uint64,
+) *dmlsink.TxnCallbackableEvent {
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t", TableID: tableID},
+	}
+	return &dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{
+			PhysicalTableID: tableID,
+			TableInfo:       tableInfo,
+			StartTs:         commitTs - 1,
+			CommitTs:        commitTs,
+			Rows: []*model.RowChangedEvent{{
+				PhysicalTableID: tableID,
+				TableInfo:       tableInfo,
+				StartTs:         commitTs - 1,
+				CommitTs:        commitTs,
+				ReplicatingTs:   replicatingTs,
+			}},
+		},
+		Callback: func() {},
+	}
+}
+
+func TestOrderingVerifier(t *testing.T) {
+	t.Parallel()
+
+	v := newOrderingVerifier()
+	events := []*dmlsink.TxnCallbackableEvent{
+		newOrderingTestEvent(1, 10),
+		newOrderingTestEvent(2, 5),
+		newOrderingTestEvent(1, 10),
+		newOrderingTestEvent(1, 12),
+	}
+	require.NoError(t, v.check("test", events))
+	v.apply(events)
+	require.Equal(t, map[int64]tableOrdering{
+		1: {replicatingTs: 1, commitTs: 12},
+		2: {replicatingTs: 1, commitTs: 5},
+	}, v.tables)
+
+	// out of order against the last applied CommitTs.
+	err := v.check("test", []*dmlsink.TxnCallbackableEvent{newOrderingTestEvent(1, 11)})
+	require.True(t, cerror.ErrOutOfOrderEvent.Equal(err))
+	// out of order inside the batch.
+	err = v.check("test", []*dmlsink.TxnCallbackableEvent{
+		newOrderingTestEvent(2, 8),
+		newOrderingTestEvent(2, 7),
+	})
+	require.True(t, cerror.ErrOutOfOrderEvent.Equal(err))
+	// a failed check doesn't change the last applied CommitTs.
+	require.Equal(t, map[int64]tableOrdering{
+		1: {replicatingTs: 1, commitTs: 12},
+		2: {replicatingTs: 1, commitTs: 5},
+	}, v.tables)
+	// other tables are not affected.
+	require.NoError(t, v.check("test", []*dmlsink.TxnCallbackableEvent{newOrderingTestEvent(3, 1)}))
+
+	// txns are replayed from the checkpoint after the table sink is restarted.
+	events = []*dmlsink.TxnCallbackableEvent{newOrderingTestEventWithReplicatingTs(1, 11, 2)}
+	require.NoError(t, v.check("test", events))
+	v.apply(events)
+	// the txns sent before the restart are ignored.
+	require.NoError(t, v.check("test", []*dmlsink.TxnCallbackableEvent{newOrderingTestEvent(1, 10)}))
+	err = v.check("test", []*dmlsink.TxnCallbackableEvent{newOrderingTestEventWithReplicatingTs(1, 10, 2)})
+	require.True(t, cerror.ErrOutOfOrderEvent.Equal(err))
+}
+
+func TestMySQLBackendFlushOutOfOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	backend := newMySQLBackendWithoutDB(ctx)
+	backend.ordering = newOrderingVerifier()
+	backend.ordering.apply([]*dmlsink.TxnCallbackableEvent{newOrderingTestEvent(1, 100)})
+
+	// The batch is rejected before it is written to the downstream,
+	// the backend has no db so any write would panic.
+	backend.OnTxnEvent(newOrderingTestEvent(1, 99))
+	err := backend.Flush(ctx)
+	require.True(t, cerror.ErrOutOfOrderEvent.Equal(err))
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// operatorIDSuffixRegexp matches the ID suffix of TiDB plan operators,
+// e.g. "_5" in "TableFullScan_5".
+var operatorIDSuffixRegexp = regexp.MustCompile(`_\d+$`)
+
+// explainResult is the parsed output of EXPLAIN.
+type explainResult struct {
+	// estRows is the estimated rows of the root operator.
+	estRows string
+	// planType is the access type of the statement, it's the `type` column
+	// in MySQL, e.g. "ALL", "ref", or the scan operator in TiDB,
+	// e.g. "TableFullScan", "IndexRangeScan", "Point_Get".
+	planType string
+	// plan contains the raw EXPLAIN rows.
+	plan []string
+}
+
+// previewSQL logs the EXPLAIN result of the first statement in the batch.
+// It's only used for debugging, errors are logged and ignored.
+func (s *mysqlBackend) previewSQL(ctx context.Context, dmls *preparedDMLs) {
+	if len(dmls.sqls) == 0 || log.GetLevel() > zapcore.DebugLevel {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, networkDriftDuration)
+	defer cancel()
+	result, err := explainSQL(ctx, s.db, dmls.sqls[0], dmls.values[0])
+	if err != nil {
+		log.Debug("explain DML failed",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.String("sql", dmls.sqls[0]),
+			zap.Error(err))
+		return
+	}
+	log.Debug("DML batch SQL preview",
+		zap.String("changefeed", s.changefeed),
+		zap.Int("workerID", s.workerID),
+		zap.Int("batchSize", len(dmls.sqls)),
+		zap.Int("rows", dmls.rowCount),
+		zap.String("sql", dmls.sqls[0]),
+		zap.String("estRows", result.estRows),
+		zap.String("planType", result.planType),
+		zap.Strings("plan", result.plan))
+}
+
+func explainSQL(
+	ctx context.Context, db *sql.DB, query string, args []interface{},
+) (*explainResult, error) {
+	rows, err := db.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	var values [][]sql.NullString
+	for rows.Next() {
+		row := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return parseExplainResult(columns, values), nil
+}
+
+// parseExplainResult parses the output of EXPLAIN in both TiDB and MySQL formats.
+func parseExplainResult(columns []string, rows [][]sql.NullString) *explainResult {
+	result := &explainResult{plan: make([]string, 0, len(rows))}
+	index := make(map[string]int, len(columns))
+	for i, column := range columns {
+		index[strings.ToLower(column)] = i
+	}
+	get := func(row []sql.NullString, column string) string {
+		if i, ok := index[column]; ok && row[i].Valid {
+			return row[i].String
+		}
+		return ""
+	}
+
+	for i, row := range rows {
+		fields := make([]string, 0, len(row))
+		for _, v := range row {
+			if v.Valid {
+				fields = append(fields, v.String)
+			}
+		}
+		result.plan = append(result.plan, strings.Join(fields, "\t"))
+
+		if _, ok := index["estrows"]; ok {
+			// TiDB format, the first row is the root operator and the
+			// access type is the leaf scan operator.
+			if i == 0 {
+				result.estRows = get(row, "estrows")
+			}
+			op := operatorIDSuffixRegexp.ReplaceAllString(
+				strings.TrimLeft(get(row, "id"), "└├│─ "), "")
+			if i == 0 || strings.Contains(op, "Scan") || strings.Contains(op, "Point_Get") {
+				result.planType = op
+			}
+			continue
+		}
+		// MySQL format.
+		if i == 0 {
+			result.estRows = get(row, "rows")
+			result.planType = get(row, "type")
+		}
+	}
+	return result
+}
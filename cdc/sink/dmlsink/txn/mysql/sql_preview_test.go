@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/log"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func toNullStrings(values ...interface{}) []sql.NullString {
+	result := make([]sql.NullString, 0, len(values))
+	for _, v := range values {
+		if v == nil {
+			result = append(result, sql.NullString{})
+			continue
+		}
+		result = append(result, sql.NullString{String: v.(string), Valid: true})
+	}
+	return result
+}
+
+func TestParseExplainResult(t *testing.T) {
+	t.Parallel()
+
+	// TiDB format.
+	columns := []string{"id", "estRows", "task", "access object", "operator info"}
+	result := parseExplainResult(columns, [][]sql.NullString{
+		toNullStrings("Update_4", "N/A", "root", "", "N/A"),
+		toNullStrings("└─TableReader_8", "10.00", "root", "", "data:Selection_7"),
+		toNullStrings("  └─Selection_7", "10.00", "cop[tikv]", "", "eq(test.t.b, 1)"),
+		toNullStrings("    └─TableFullScan_6", "10000.00", "cop[tikv]", "table:t", "keep order:false"),
+	})
+	require.Equal(t, "N/A", result.estRows)
+	require.Equal(t, "TableFullScan", result.planType)
+	require.Len(t, result.plan, 4)
+	require.Equal(t, "└─TableReader_8\t10.00\troot\t\tdata:Selection_7", result.plan[1])
+
+	result = parseExplainResult(columns, [][]sql.NullString{
+		toNullStrings("Point_Get_1", "1.00", "root", "table:t", "handle:1"),
+	})
+	require.Equal(t, "1.00", result.estRows)
+	require.Equal(t, "Point_Get", result.planType)
+
+	// MySQL format.
+	columns = []string{
+		"id", "select_type", "table", "partitions", "type", "possible_keys",
+		"key", "key_len", "ref", "rows", "filtered", "Extra",
+	}
+	result = parseExplainResult(columns, [][]sql.NullString{
+		toNullStrings("1", "DELETE", "t", nil, "ALL", nil, nil, nil, nil, "100", "100.00", "Using where"),
+	})
+	require.Equal(t, "100", result.estRows)
+	require.Equal(t, "ALL", result.planType)
+	require.Equal(t, []string{"1\tDELETE\tt\tALL\t100\t100.00\tUsing where"}, result.plan)
+}
+
+func TestPreviewSQL(t *testing.T) {
+	zapcore, logs := observer.New(zap.DebugLevel)
+	conf := &log.Config{Level: "debug", File: log.FileLogConfig{}}
+	_, r, _ := log.InitLogger(conf)
+	logger := zap.New(zapcore)
+	restoreFn := log.ReplaceGlobals(logger, r)
+	defer restoreFn()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	mock.ExpectQuery("EXPLAIN DELETE FROM `test`.`t` WHERE `b` = ? LIMIT 1").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(
+			[]string{"id", "estRows", "task", "access object", "operator info"}).
+			AddRow("Delete_4", "N/A", "root", "", "N/A").
+			AddRow("└─TableReader_8", "10.00", "root", "", "data:Selection_7").
+			AddRow("  └─Selection_7", "10.00", "cop[tikv]", "", "eq(test.t.b, 1)").
+			AddRow("    └─TableFullScan_6", "10000.00", "cop[tikv]", "table:t", "keep order:false"))
+	mock.ExpectClose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	backend := newMySQLBackendWithoutDB(ctx)
+	backend.db = db
+	backend.previewSQL(ctx, &preparedDMLs{
+		sqls: []string{
+			"DELETE FROM `test`.`t` WHERE `b` = ? LIMIT 1",
+			"DELETE FROM `test`.`t` WHERE `b` = ? LIMIT 1",
+		},
+		values:   [][]interface{}{{1}, {2}},
+		rowCount: 2,
+	})
+	require.NoError(t, db.Close())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	entries := logs.FilterMessage("DML batch SQL preview").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	require.Equal(t, int64(2), fields["batchSize"])
+	require.Equal(t, "N/A", fields["estRows"])
+	require.Equal(t, "TableFullScan", fields["planType"])
+	require.Len(t, fields["plan"], 4)
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+// tablePoolMaxIdleTime is the duration after which the connection pool of
+// an inactive table is closed.
+const tablePoolMaxIdleTime = 10 * time.Minute
+
+type tablePool struct {
+	db       *sql.DB
+	inUse    int
+	lastUsed time.Time
+}
+
+// tablePools maintains a connection pool for each table, so that slow writes
+// of a table can't exhaust the connections used by other tables. The
+// connections are shared evenly by the active tables, each table has at
+// least one connection. It is shared by all backends created by NewMySQLBackends.
+type tablePools struct {
+	changefeed    string
+	dsnStr        string
+	dbConnFactory pmysql.Factory
+	poolSize      int
+	maxIdleTime   time.Duration
+
+	mu    sync.Mutex
+	pools map[int64]*tablePool
+}
+
+func newTablePools(
+	changefeed string, dsnStr string, dbConnFactory pmysql.Factory, poolSize int,
+) *tablePools {
+	return &tablePools{
+		changefeed:    changefeed,
+		dsnStr:        dsnStr,
+		dbConnFactory: dbConnFactory,
+		poolSize:      poolSize,
+		maxIdleTime:   tablePoolMaxIdleTime,
+		pools:         make(map[int64]*tablePool),
+	}
+}
+
+// acquire returns the connection pool of the table, release must be called
+// after the pool is not used anymore.
+func (p *tablePools) acquire(ctx context.Context, tableID int64) (*sql.DB, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.evictIdle(now)
+	pool, ok := p.pools[tableID]
+	if !ok {
+		db, err := p.dbConnFactory(ctx, p.dsnStr)
+		if err != nil {
+			return nil, err
+		}
+		pool = &tablePool{db: db}
+		p.pools[tableID] = pool
+		p.resize()
+		log.Info("connection pool of table is created",
+			zap.String("changefeed", p.changefeed),
+			zap.Int64("tableID", tableID),
+			zap.Int("tableCount", len(p.pools)))
+	}
+	pool.inUse++
+	pool.lastUsed = now
+	return pool.db, nil
+}
+
+func (p *tablePools) release(tableID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pool, ok := p.pools[tableID]; ok {
+		pool.inUse--
+		pool.lastUsed = time.Now()
+	}
+}
+
+// evictIdle closes the pools of tables which are inactive for maxIdleTime.
+func (p *tablePools) evictIdle(now time.Time) {
+	evicted := false
+	for tableID, pool := range p.pools {
+		if pool.inUse > 0 || now.Sub(pool.lastUsed) < p.maxIdleTime {
+			continue
+		}
+		if err := pool.db.Close(); err != nil {
+			log.Warn("failed to close connection pool of table",
+				zap.String("changefeed", p.changefeed),
+				zap.Int64("tableID", tableID), zap.Error(err))
+		}
+		delete(p.pools, tableID)
+		evicted = true
+		log.Info("connection pool of inactive table is closed",
+			zap.String("changefeed", p.changefeed),
+			zap.Int64("tableID", tableID))
+	}
+	if evicted {
+		p.resize()
+	}
+}
+
+// resize shares the connections evenly by the tables.
+func (p *tablePools) resize() {
+	if len(p.pools) == 0 {
+		return
+	}
+	size := p.poolSize / len(p.pools)
+	if size < 1 {
+		size = 1
+	}
+	for _, pool := range p.pools {
+		pool.db.SetMaxIdleConns(size)
+		pool.db.SetMaxOpenConns(size)
+	}
+}
+
+func (p *tablePools) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for tableID, pool := range p.pools {
+		if err := pool.db.Close(); err != nil {
+			log.Warn("failed to close connection pool of table",
+				zap.String("changefeed", p.changefeed),
+				zap.Int64("tableID", tableID), zap.Error(err))
+		}
+	}
+	p.pools = make(map[int64]*tablePool)
+}
+
+// groupEventsByTable splits the events by their tables, the order of events
+// of each table is kept.
+func groupEventsByTable(
+	events []*dmlsink.TxnCallbackableEvent,
+) ([]int64, map[int64][]*dmlsink.TxnCallbackableEvent) {
+	var tableIDs []int64
+	groups := make(map[int64][]*dmlsink.TxnCallbackableEvent)
+	for _, event := range events {
+		tableID := event.Event.GetPhysicalTableID()
+		if _, ok := groups[tableID]; !ok {
+			tableIDs = append(tableIDs, tableID)
+		}
+		groups[tableID] = append(groups[tableID], event)
+	}
+	return tableIDs, groups
+}
@@ -0,0 +1,276 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablePools(t *testing.T) {
+	t.Parallel()
+
+	var mocks []sqlmock.Sqlmock
+	factory := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectClose()
+		mocks = append(mocks, mock)
+		return db, nil
+	}
+	ctx := context.Background()
+	pools := newTablePools("test", "", factory, 5)
+
+	db1, err := pools.acquire(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 5, db1.Stats().MaxOpenConnections)
+	db2, err := pools.acquire(ctx, 2)
+	require.NoError(t, err)
+	require.NotSame(t, db1, db2)
+	require.Equal(t, 2, db1.Stats().MaxOpenConnections)
+	require.Equal(t, 2, db2.Stats().MaxOpenConnections)
+	// the pool of a table is reused.
+	db, err := pools.acquire(ctx, 1)
+	require.NoError(t, err)
+	require.Same(t, db1, db)
+	pools.release(1)
+	pools.release(1)
+
+	// each table has at least one connection.
+	for tableID := int64(3); tableID <= 6; tableID++ {
+		_, err = pools.acquire(ctx, tableID)
+		require.NoError(t, err)
+		pools.release(tableID)
+	}
+	require.Equal(t, 1, db1.Stats().MaxOpenConnections)
+
+	// the pools of inactive tables are evicted, except the ones in use.
+	pools.mu.Lock()
+	pools.maxIdleTime = 0
+	pools.mu.Unlock()
+	db7, err := pools.acquire(ctx, 7)
+	require.NoError(t, err)
+	require.Len(t, pools.pools, 2)
+	require.Contains(t, pools.pools, int64(2))
+	require.Equal(t, 2, db2.Stats().MaxOpenConnections)
+	require.Equal(t, 2, db7.Stats().MaxOpenConnections)
+	for _, mock := range mocks[2:6] {
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+
+	pools.release(2)
+	pools.release(7)
+	pools.close()
+	for _, mock := range mocks {
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestMySQLBackendConnectionPoolPerTable(t *testing.T) {
+	t.Parallel()
+
+	dbIndex := 0
+	var mocks []sqlmock.Sqlmock
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+		switch dbIndex {
+		case 0:
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		case 1:
+			// normal db
+			db, mock := newTestMockDB(t)
+			mock.ExpectClose()
+			return db, nil
+		}
+		// db of tables
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		mocks = append(mocks, mock)
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		ConnectionPoolPerTable: util.AddressOf(true),
+	}
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+	require.False(t, sink.cachePrepStmts)
+
+	newTxn := func(table string, tableID int64, value int) *dmlsink.TxnCallbackableEvent {
+		tableInfo := model.BuildTableInfo("s1", table, []*model.Column{
+			{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		}, [][]int{{0}})
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{
+				PhysicalTableID: tableID,
+				CommitTs:        2,
+				Rows: []*model.RowChangedEvent{{
+					StartTs:         1,
+					CommitTs:        2,
+					TableInfo:       tableInfo,
+					PhysicalTableID: tableID,
+					Columns: model.Columns2ColumnDatas([]*model.Column{
+						{Name: "a", Value: value},
+					}, tableInfo),
+				}},
+			},
+			Callback: func() {},
+		}
+	}
+	_ = sink.OnTxnEvent(newTxn("t1", 1, 1))
+	_ = sink.OnTxnEvent(newTxn("t2", 2, 2))
+	_ = sink.OnTxnEvent(newTxn("t1", 1, 3))
+	require.Len(t, mocks, 0)
+
+	// the pools are created lazily, set the expectations after they are created.
+	for tableID := int64(1); tableID <= 2; tableID++ {
+		db, err := sink.tablePools.acquire(ctx, tableID)
+		require.NoError(t, err)
+		require.NotNil(t, db)
+		sink.tablePools.release(tableID)
+	}
+	require.Len(t, mocks, 2)
+	mocks[0].ExpectBegin()
+	mocks[0].ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?);INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mocks[0].ExpectCommit()
+	mocks[0].ExpectClose()
+	mocks[1].ExpectBegin()
+	mocks[1].ExpectExec("INSERT INTO `s1`.`t2` (`a`) VALUES (?)").
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mocks[1].ExpectCommit()
+	mocks[1].ExpectClose()
+
+	require.Nil(t, sink.Flush(ctx))
+	require.Nil(t, sink.Close())
+	for _, mock := range mocks {
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+// slowDriver is a fake driver whose statements containing "slow" take
+// slowDriverDelay to execute, and others return immediately.
+type slowDriver struct{}
+
+const slowDriverDelay = 5 * time.Millisecond
+
+func (slowDriver) Open(string) (driver.Conn, error) { return slowConn{}, nil }
+
+type slowConn struct{}
+
+func (slowConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (slowConn) Close() error                        { return nil }
+func (slowConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (slowConn) ExecContext(
+	ctx context.Context, query string, _ []driver.NamedValue,
+) (driver.Result, error) {
+	if strings.Contains(query, "slow") {
+		select {
+		case <-time.After(slowDriverDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+var registerSlowDriver sync.Once
+
+func openSlowDB(_ context.Context, _ string) (*sql.DB, error) {
+	registerSlowDriver.Do(func() { sql.Register("tablepool-slow", slowDriver{}) })
+	return sql.Open("tablepool-slow", "")
+}
+
+// BenchmarkTablePoolsMixedWorkload measures the throughput of a fast table
+// while some workers keep writing a slow table, with a shared connection
+// pool and with a connection pool per table.
+func BenchmarkTablePoolsMixedWorkload(b *testing.B) {
+	const poolSize = 4
+	const slowTableID, fastTableID = 1, 2
+
+	run := func(b *testing.B, acquire func(tableID int64) (*sql.DB, func())) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		// the slow table occupies as many workers as the connections.
+		for i := 0; i < poolSize; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ctx.Err() == nil {
+					db, release := acquire(slowTableID)
+					_, _ = db.ExecContext(ctx, "INSERT INTO slow VALUES (1)")
+					release()
+				}
+			}()
+		}
+		// wait for the slow writers to start.
+		time.Sleep(10 * time.Millisecond)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			db, release := acquire(fastTableID)
+			_, err := db.ExecContext(ctx, "INSERT INTO fast VALUES (1)")
+			release()
+			require.NoError(b, err)
+		}
+		b.StopTimer()
+		cancel()
+		wg.Wait()
+	}
+
+	b.Run("shared", func(b *testing.B) {
+		db, err := openSlowDB(context.Background(), "")
+		require.NoError(b, err)
+		defer db.Close()
+		db.SetMaxOpenConns(poolSize)
+		run(b, func(int64) (*sql.DB, func()) { return db, func() {} })
+	})
+
+	b.Run("per-table", func(b *testing.B) {
+		pools := newTablePools("bench", "", openSlowDB, poolSize)
+		defer pools.close()
+		run(b, func(tableID int64) (*sql.DB, func()) {
+			db, err := pools.acquire(context.Background(), tableID)
+			require.NoError(b, err)
+			return db, func() { pools.release(tableID) }
+		})
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/pingcap/tiflow/pkg/version"
+	"go.uber.org/zap"
+)
+
+// downstreamVersionCache caches the downstream TiDB version keyed by the sink
+// URI host, so that the version is not queried every time the sink is recreated.
+var downstreamVersionCache sync.Map
+
+// checkDownstreamVersion checks whether the downstream TiDB version is
+// compatible with the features used by the changefeed.
+// An error is returned if a required feature is not supported by the downstream,
+// and a warning is logged if the downstream is more than one major version
+// behind the upstream. The downstream version is cached by host.
+func checkDownstreamVersion(
+	ctx context.Context, host string, db *sql.DB, cfg *config.ReplicaConfig,
+) error {
+	if v, ok := downstreamVersionCache.Load(host); ok {
+		return checkDownstreamVersionCompatible(v.(*semver.Version), cfg)
+	}
+	downstreamVersion, err := queryDownstreamVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	downstreamVersionCache.Store(host, downstreamVersion)
+	return checkDownstreamVersionCompatible(downstreamVersion, cfg)
+}
+
+func checkDownstreamVersionCompatible(
+	downstreamVersion *semver.Version, cfg *config.ReplicaConfig,
+) error {
+	if util.GetOrZero(cfg.BDRMode) {
+		if err := version.CheckFeature(version.FeatureBDRMode, downstreamVersion); err != nil {
+			return err
+		}
+	}
+
+	// TiCDC is released together with TiDB, so its release version is used
+	// as the upstream version. It is empty in tests and development builds.
+	upstream := version.ReleaseSemver()
+	if upstream == "" {
+		return nil
+	}
+	upstreamVersion := semver.New(upstream)
+	if upstreamVersion.Major-downstreamVersion.Major > 1 {
+		log.Warn("downstream TiDB is more than one major version behind the upstream, "+
+			"some features may not work as expected",
+			zap.Stringer("upstreamVersion", upstreamVersion),
+			zap.Stringer("downstreamVersion", downstreamVersion))
+	}
+	return nil
+}
+
+// queryDownstreamVersion gets the TiDB version of the downstream.
+func queryDownstreamVersion(ctx context.Context, db *sql.DB) (*semver.Version, error) {
+	var versionStr string
+	if err := db.QueryRowContext(ctx, "select version()").Scan(&versionStr); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return parseTiDBVersion(versionStr)
+}
+
+// parseTiDBVersion extracts the TiDB version from the result of `select version()`.
+// The format is like "8.0.11-TiDB-v7.5.0" or "5.7.25-TiDB-v4.0.0-beta-191-ga1b3e3b".
+func parseTiDBVersion(versionStr string) (*semver.Version, error) {
+	const sep = "-TiDB-"
+	idx := strings.Index(versionStr, sep)
+	if idx < 0 {
+		return nil, cerror.WrapError(cerror.ErrNewSemVersion,
+			fmt.Errorf("not a valid TiDB version: %s", versionStr))
+	}
+	v, err := semver.NewVersion(version.SanitizeVersion(versionStr[idx+len(sep):]))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrNewSemVersion, err)
+	}
+	return v, nil
+}
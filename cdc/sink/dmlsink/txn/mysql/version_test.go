@@ -0,0 +1,61 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTiDBVersion(t *testing.T) {
+	t.Parallel()
+
+	v, err := parseTiDBVersion("8.0.11-TiDB-v7.5.0")
+	require.NoError(t, err)
+	require.Equal(t, "7.5.0", v.String())
+
+	v, err = parseTiDBVersion("5.7.25-TiDB-v4.0.0-beta-191-ga1b3e3b")
+	require.NoError(t, err)
+	require.Equal(t, "4.0.0-beta", v.String())
+
+	_, err = parseTiDBVersion("8.0.32")
+	require.Error(t, err)
+}
+
+func TestCheckDownstreamVersion(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("select version()").
+		WillReturnRows(sqlmock.NewRows([]string{"version()"}).AddRow("5.7.25-TiDB-v6.1.0"))
+
+	ctx := context.Background()
+	host := "check-downstream-version:4000"
+	cfg := config.GetDefaultReplicaConfig()
+	require.NoError(t, checkDownstreamVersion(ctx, host, db, cfg))
+
+	// The cached version is used, and BDR mode is not supported by v6.1.0.
+	cfg.BDRMode = util.AddressOf(true)
+	err = checkDownstreamVersion(ctx, host, db, cfg)
+	require.True(t, cerror.ErrVersionIncompatible.Equal(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+)
+
+// tableWrite is the bytes written to downstream for the events of a table.
+type tableWrite struct {
+	table string
+	// sqlBytes is the bytes of SQL statements and their arguments.
+	sqlBytes int64
+	// sourceBytes is the approximate bytes of the source events.
+	sourceBytes int64
+}
+
+func newTableWrite(
+	event *model.SingleTableTxn, sqls []string, values [][]interface{},
+) tableWrite {
+	w := tableWrite{table: event.Rows[0].TableInfo.TableName.String()}
+	for i, sql := range sqls {
+		w.sqlBytes += int64(len(sql))
+		for _, arg := range values[i] {
+			w.sqlBytes += argBytes(arg)
+		}
+	}
+	for _, row := range event.Rows {
+		w.sourceBytes += int64(row.ApproximateBytes())
+	}
+	return w
+}
+
+// argBytes returns the approximate bytes of a SQL argument written to
+// downstream, numeric arguments are counted as 8 bytes.
+func argBytes(arg interface{}) int64 {
+	switch v := arg.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	default:
+		return 8
+	}
+}
+
+// writeAmplification tracks the write amplification of each table, which is
+// the bytes of SQL statements written per byte of source events. It is shared
+// by all backends of a sink.
+type writeAmplification struct {
+	namespace  string
+	changefeed string
+
+	mu     sync.Mutex
+	tables map[string]*tableWrite
+}
+
+func newWriteAmplification(changefeedID model.ChangeFeedID) *writeAmplification {
+	return &writeAmplification{
+		namespace:  changefeedID.Namespace,
+		changefeed: changefeedID.ID,
+		tables:     make(map[string]*tableWrite),
+	}
+}
+
+// observe records the bytes written to downstream successfully.
+func (w *writeAmplification) observe(writes []tableWrite) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, write := range writes {
+		total, ok := w.tables[write.table]
+		if !ok {
+			total = &tableWrite{table: write.table}
+			w.tables[write.table] = total
+		}
+		total.sqlBytes += write.sqlBytes
+		total.sourceBytes += write.sourceBytes
+
+		txn.MySQLSQLBytesWritten.
+			WithLabelValues(w.namespace, w.changefeed, write.table).
+			Add(float64(write.sqlBytes))
+		txn.MySQLSourceEventBytes.
+			WithLabelValues(w.namespace, w.changefeed, write.table).
+			Add(float64(write.sourceBytes))
+		if total.sourceBytes > 0 {
+			txn.MySQLWriteAmplificationRatio.
+				WithLabelValues(w.namespace, w.changefeed, write.table).
+				Set(float64(total.sqlBytes) / float64(total.sourceBytes))
+		}
+	}
+}
+
+// cleanup removes the metrics of all tables.
+func (w *writeAmplification) cleanup() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for table := range w.tables {
+		txn.MySQLSQLBytesWritten.DeleteLabelValues(w.namespace, w.changefeed, table)
+		txn.MySQLSourceEventBytes.DeleteLabelValues(w.namespace, w.changefeed, table)
+		txn.MySQLWriteAmplificationRatio.DeleteLabelValues(w.namespace, w.changefeed, table)
+	}
+	w.tables = make(map[string]*tableWrite)
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAmplification(t *testing.T) {
+	insertSQL := "INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)"
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(insertSQL).
+			WithArgs(1, "aaaaaaaaaa", 2, "bbbbbbbbbb").
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeedID := model.DefaultChangeFeedID("test-write-amplification")
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	sink, err := newMySQLBackend(ctx, changefeedID, sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn)
+	require.Nil(t, err)
+
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "b", Type: mysql.TypeVarchar},
+	}, [][]int{{0}})
+	rows := make([]*model.RowChangedEvent, 0, 2)
+	for i, value := range []string{"aaaaaaaaaa", "bbbbbbbbbb"} {
+		columns := model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: i + 1},
+			{Name: "b", Value: value},
+		}, tableInfo)
+		// The source row takes 1000 bytes, e.g. the row has many secondary
+		// indexes in upstream.
+		columns[0].ApproximateBytes = 500
+		columns[1].ApproximateBytes = 500
+		rows = append(rows, &model.RowChangedEvent{
+			StartTs:         1,
+			CommitTs:        2,
+			TableInfo:       tableInfo,
+			PhysicalTableID: 1,
+			Columns:         columns,
+		})
+	}
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{Rows: rows},
+	})
+	require.Nil(t, sink.Flush(context.Background()))
+
+	// Each numeric argument is counted as 8 bytes.
+	expectedSQLBytes := float64(len(insertSQL) + 8 + 10 + 8 + 10)
+	expectedSourceBytes := float64(rows[0].ApproximateBytes() + rows[1].ApproximateBytes())
+	labels := []string{changefeedID.Namespace, changefeedID.ID, "s1.t1"}
+	require.Equal(t, expectedSQLBytes,
+		testutil.ToFloat64(txn.MySQLSQLBytesWritten.WithLabelValues(labels...)))
+	require.Equal(t, expectedSourceBytes,
+		testutil.ToFloat64(txn.MySQLSourceEventBytes.WithLabelValues(labels...)))
+	require.InDelta(t, expectedSQLBytes/expectedSourceBytes,
+		testutil.ToFloat64(txn.MySQLWriteAmplificationRatio.WithLabelValues(labels...)), 1e-9)
+
+	require.Nil(t, sink.Close())
+	require.Equal(t, 0, testutil.CollectAndCount(txn.MySQLSQLBytesWritten))
+}
+
+func TestWriteAmplificationPerTable(t *testing.T) {
+	changefeedID := model.DefaultChangeFeedID("test-write-amplification-per-table")
+	w := newWriteAmplification(changefeedID)
+	w.observe([]tableWrite{
+		{table: "test.t1", sqlBytes: 100, sourceBytes: 100},
+		{table: "test.t2", sqlBytes: 300, sourceBytes: 100},
+	})
+	w.observe([]tableWrite{{table: "test.t1", sqlBytes: 300, sourceBytes: 100}})
+
+	ratio := func(table string) float64 {
+		return testutil.ToFloat64(txn.MySQLWriteAmplificationRatio.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID, table))
+	}
+	require.Equal(t, float64(2), ratio("test.t1"))
+	require.Equal(t, float64(3), ratio("test.t2"))
+	require.Equal(t, float64(400), testutil.ToFloat64(txn.MySQLSQLBytesWritten.
+		WithLabelValues(changefeedID.Namespace, changefeedID.ID, "test.t1")))
+
+	w.cleanup()
+	require.Equal(t, 0, testutil.CollectAndCount(txn.MySQLWriteAmplificationRatio))
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	ppg "github.com/pingcap/tiflow/pkg/sink/pg"
+)
+
+// prepareUpsert generates an `INSERT ... ON CONFLICT DO UPDATE` statement
+// of a row. It degrades to a plain `INSERT` if the table has no handle key.
+func prepareUpsert(row *model.RowChangedEvent) (string, []interface{}, error) {
+	if err := dmlsink.CheckRow(row); err != nil {
+		return "", nil, err
+	}
+	tableInfo := row.TableInfo
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(ppg.QuoteSchema(tableInfo.GetSchemaName(), tableInfo.GetTableName()))
+	builder.WriteString(" (")
+
+	columnNames := make([]string, 0, len(row.Columns))
+	keyNames := make([]string, 0)
+	args := make([]interface{}, 0, len(row.Columns))
+	for _, col := range row.Columns {
+		if col == nil {
+			continue
+		}
+		colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+		if !ok {
+			continue
+		}
+		value, err := ppg.ConvertValue(&colInfo.FieldType, col.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		name := ppg.QuoteName(colInfo.Name.O)
+		if len(columnNames) > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(name)
+		columnNames = append(columnNames, name)
+		if tableInfo.ForceGetColumnFlagType(col.ColumnID).IsHandleKey() {
+			keyNames = append(keyNames, name)
+		}
+		args = append(args, value)
+	}
+
+	builder.WriteString(") VALUES (")
+	for i := range args {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("$" + strconv.Itoa(i+1))
+	}
+	builder.WriteString(")")
+
+	if len(keyNames) == 0 {
+		return builder.String(), args, nil
+	}
+	builder.WriteString(" ON CONFLICT (")
+	builder.WriteString(strings.Join(keyNames, ","))
+	builder.WriteString(") DO ")
+	updated := 0
+	for _, name := range columnNames {
+		if isKey(name, keyNames) {
+			continue
+		}
+		if updated == 0 {
+			builder.WriteString("UPDATE SET ")
+		} else {
+			builder.WriteString(",")
+		}
+		builder.WriteString(name + " = EXCLUDED." + name)
+		updated++
+	}
+	if updated == 0 {
+		builder.WriteString("NOTHING")
+	}
+	return builder.String(), args, nil
+}
+
+// prepareDelete generates a `DELETE` statement of a row. The row is located
+// by the handle key, or by all the columns if the table has no handle key.
+func prepareDelete(row *model.RowChangedEvent) (string, []interface{}, error) {
+	if err := dmlsink.CheckRow(row); err != nil {
+		return "", nil, err
+	}
+	tableInfo := row.TableInfo
+	hasHandleKey := false
+	for _, col := range row.PreColumns {
+		if col != nil && tableInfo.ForceGetColumnFlagType(col.ColumnID).IsHandleKey() {
+			hasHandleKey = true
+			break
+		}
+	}
+
+	var where strings.Builder
+	args := make([]interface{}, 0)
+	for _, col := range row.PreColumns {
+		if col == nil {
+			continue
+		}
+		if hasHandleKey && !tableInfo.ForceGetColumnFlagType(col.ColumnID).IsHandleKey() {
+			continue
+		}
+		colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+		if !ok {
+			continue
+		}
+		value, err := ppg.ConvertValue(&colInfo.FieldType, col.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		if where.Len() > 0 {
+			where.WriteString(" AND ")
+		}
+		where.WriteString(ppg.QuoteName(colInfo.Name.O))
+		if value == nil {
+			where.WriteString(" IS NULL")
+		} else {
+			args = append(args, value)
+			where.WriteString(" = $" + strconv.Itoa(len(args)))
+		}
+	}
+
+	table := ppg.QuoteSchema(tableInfo.GetSchemaName(), tableInfo.GetTableName())
+	if hasHandleKey {
+		return "DELETE FROM " + table + " WHERE " + where.String(), args, nil
+	}
+	// Delete only one of the duplicated rows, like the MySQL sink does.
+	return "DELETE FROM " + table + " WHERE ctid = (SELECT ctid FROM " + table +
+		" WHERE " + where.String() + " LIMIT 1)", args, nil
+}
+
+func isKey(name string, keyNames []string) bool {
+	for _, key := range keyNames {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	ppg "github.com/pingcap/tiflow/pkg/sink/pg"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDMLMaxRetry uint64 = 8
+
+	// maxFlushInterval is the max duration to wait for more transactions
+	// before flushing a partial batch.
+	maxFlushInterval = 10 * time.Millisecond
+)
+
+type pgBackend struct {
+	workerID    int
+	changefeed  string
+	db          *sql.DB
+	cfg         *ppg.Config
+	dmlMaxRetry uint64
+
+	events []*dmlsink.TxnCallbackableEvent
+	rows   int
+
+	statistics *metrics.Statistics
+}
+
+// NewPGBackends creates the PostgreSQL backends of a txn sink, all the
+// backends share one connection pool.
+func NewPGBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	dbConnFactory ppg.Factory,
+	statistics *metrics.Statistics,
+) ([]*pgBackend, error) {
+	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
+
+	cfg := ppg.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	db, err := dbConnFactory(ctx, ppg.GenerateDSN(sinkURI))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(cfg.WorkerCount)
+	db.SetMaxOpenConns(cfg.WorkerCount)
+
+	backends := make([]*pgBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &pgBackend{
+			workerID:    i,
+			changefeed:  changefeed,
+			db:          db,
+			cfg:         cfg,
+			dmlMaxRetry: defaultDMLMaxRetry,
+			statistics:  statistics,
+		})
+	}
+
+	log.Info("PostgreSQL backends is created",
+		zap.String("changefeed", changefeed),
+		zap.Int("workerCount", cfg.WorkerCount))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It adds the event to the buffer, and return true if it needs flush immediately.
+func (s *pgBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.MaxTxnRow
+}
+
+// Flush implements interface backend.
+func (s *pgBackend) Flush(ctx context.Context) error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	sqls, values, err := s.prepareDMLs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, event := range s.events {
+		s.statistics.ObserveRows(event.Event.Rows...)
+	}
+	if err := s.execDMLWithRetries(ctx, sqls, values); err != nil {
+		if errors.Cause(err) != context.Canceled {
+			log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
+		}
+		return errors.Trace(err)
+	}
+	for _, event := range s.events {
+		event.Callback()
+	}
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return nil
+}
+
+// prepareDMLs generates the statements of all the buffered events. Updates
+// changing the handle key are already split into deletes and inserts by the
+// txn sink, so an update is always written as an upsert.
+func (s *pgBackend) prepareDMLs() ([]string, [][]interface{}, error) {
+	sqls := make([]string, 0, s.rows)
+	values := make([][]interface{}, 0, s.rows)
+	for _, event := range s.events {
+		for _, row := range event.Event.Rows {
+			var (
+				query string
+				args  []interface{}
+				err   error
+			)
+			if row.IsDelete() {
+				query, args, err = prepareDelete(row)
+			} else {
+				query, args, err = prepareUpsert(row)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			sqls = append(sqls, query)
+			values = append(values, args)
+		}
+	}
+	return sqls, values, nil
+}
+
+func (s *pgBackend) execDMLWithRetries(
+	pctx context.Context, sqls []string, values [][]interface{},
+) error {
+	start := time.Now()
+	return retry.Do(pctx, func() error {
+		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
+			tx, err := s.db.BeginTx(pctx, nil)
+			if err != nil {
+				return 0, 0, logDMLTxnErr(
+					cerror.WrapError(cerror.ErrPostgresTxnError, err),
+					start, s.changefeed, "BEGIN", len(sqls))
+			}
+
+			ctx, cancel := context.WithTimeout(pctx, s.cfg.WriteTimeout)
+			defer cancel()
+			for i, query := range sqls {
+				if _, err := tx.ExecContext(ctx, query, values[i]...); err != nil {
+					if rbErr := tx.Rollback(); rbErr != nil {
+						if errors.Cause(rbErr) != context.Canceled {
+							log.Warn("failed to rollback txn",
+								zap.String("changefeed", s.changefeed), zap.Error(rbErr))
+						}
+					}
+					return 0, 0, logDMLTxnErr(
+						cerror.WrapError(cerror.ErrPostgresTxnError, err),
+						start, s.changefeed, query, len(sqls))
+				}
+			}
+
+			if err = tx.Commit(); err != nil {
+				return 0, 0, logDMLTxnErr(
+					cerror.WrapError(cerror.ErrPostgresTxnError, err),
+					start, s.changefeed, "COMMIT", len(sqls))
+			}
+			return len(sqls), 0, nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfRows", len(sqls)))
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(s.dmlMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func logDMLTxnErr(
+	err error, start time.Time, changefeed string, query string, count int,
+) error {
+	if len(query) > 1024 {
+		query = query[:1024]
+	}
+	log.Warn("execute DMLs with error",
+		zap.Error(err), zap.Duration("duration", time.Since(start)),
+		zap.String("query", query), zap.Int("count", count),
+		zap.String("changefeed", changefeed))
+	return err
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *pgBackend) MaxFlushInterval() time.Duration {
+	return maxFlushInterval
+}
+
+// Close implements interface backend.
+func (s *pgBackend) Close() (err error) {
+	if s.db != nil {
+		err = s.db.Close()
+		s.db = nil
+	}
+	return
+}
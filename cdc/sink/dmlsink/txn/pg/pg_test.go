@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareDMLs(t *testing.T) {
+	// Tables without handle key are replicated only if force-replicate is on.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.ForceReplicate = true
+	helper := entry.NewSchemaTestHelperWithReplicaConfig(t, replicaConfig)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id bigint unsigned primary key, " +
+		"name varchar(32), e enum('a','b'))")
+	insert := helper.DML2Event("insert into test.t values (18446744073709551615, 'x', 'b')",
+		"test", "t")
+	query, args, err := prepareUpsert(insert)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "test"."t" ("id","name","e") VALUES ($1,$2,$3) `+
+		`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name","e" = EXCLUDED."e"`, query)
+	require.Equal(t, []interface{}{"18446744073709551615", "x", "b"}, args)
+
+	remove := *insert
+	remove.PreColumns, remove.Columns = insert.Columns, nil
+	query, args, err = prepareDelete(&remove)
+	require.NoError(t, err)
+	require.Equal(t, `DELETE FROM "test"."t" WHERE "id" = $1`, query)
+	require.Equal(t, []interface{}{"18446744073709551615"}, args)
+
+	// tables without handle key.
+	helper.DDL2Event("create table test.t2(a int, b varchar(32))")
+	insert = helper.DML2Event("insert into test.t2 values (1, null)", "test", "t2")
+	query, args, err = prepareUpsert(insert)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "test"."t2" ("a","b") VALUES ($1,$2)`, query)
+	require.Equal(t, []interface{}{int64(1), nil}, args)
+
+	remove = *insert
+	remove.PreColumns, remove.Columns = insert.Columns, nil
+	query, args, err = prepareDelete(&remove)
+	require.NoError(t, err)
+	require.Equal(t, `DELETE FROM "test"."t2" WHERE ctid = (SELECT ctid FROM "test"."t2" `+
+		`WHERE "a" = $1 AND "b" IS NULL LIMIT 1)`, query)
+	require.Equal(t, []interface{}{int64(1)}, args)
+
+	// the rows without table info are rejected.
+	_, _, err = prepareUpsert(&model.RowChangedEvent{})
+	require.Error(t, err)
+	_, _, err = prepareDelete(nil)
+	require.Error(t, err)
+}
+
+func TestFlush(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(32))")
+	row := helper.DML2Event("insert into test.t values (1, 'x')", "test", "t")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("postgres://127.0.0.1:5432/test?worker-count=1")
+	require.NoError(t, err)
+	backends, err := NewPGBackends(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			mock.ExpectBegin()
+			mock.ExpectExec(`INSERT INTO "test"."t" ("id","name") VALUES ($1,$2) `+
+				`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`).
+				WithArgs(int64(1), "x").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			mock.ExpectClose()
+			return db, nil
+		}, metrics.NewStatistics(ctx, model.DefaultChangeFeedID("test"), sink.TxnSink))
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	backend := backends[0]
+
+	flushed := false
+	needFlush := backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{row}},
+		Callback: func() { flushed = true },
+	})
+	require.False(t, needFlush)
+	require.NoError(t, backend.Flush(ctx))
+	require.True(t, flushed)
+	require.Zero(t, backend.rows)
+	require.NoError(t, backend.Close())
+}
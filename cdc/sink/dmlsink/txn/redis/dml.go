@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+)
+
+// command is a SET of the key of a row, or a DEL if the value is nil.
+type command struct {
+	key   string
+	value []byte
+}
+
+// prepareCommands converts the row changes to commands. The value of a key
+// is the row encoded in JSON, and the key of a deleted row is removed.
+// Rows of tables without handle key are skipped, since they can't be
+// identified by keys, the IDs of the skipped tables are returned.
+func prepareCommands(rows []*model.RowChangedEvent, cfg *predis.Config) ([]command, []int64, error) {
+	commands := make([]command, 0, len(rows))
+	var skipped []int64
+	for _, row := range rows {
+		if err := dmlsink.CheckRow(row); err != nil {
+			return nil, nil, err
+		}
+		data := row.Columns
+		if row.IsDelete() {
+			data = row.PreColumns
+		}
+		keyValues, doc, err := encodeRow(row.TableInfo, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(keyValues) == 0 {
+			skipped = append(skipped, row.TableInfo.ID)
+			continue
+		}
+		key := cfg.Key(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName(), keyValues)
+		if row.IsDelete() {
+			commands = append(commands, command{key: key})
+			continue
+		}
+		value, err := json.Marshal(doc)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		commands = append(commands, command{key: key, value: value})
+	}
+	return commands, skipped, nil
+}
+
+// encodeRow returns the handle key values and the JSON object of a row.
+func encodeRow(
+	tableInfo *model.TableInfo, data []*model.ColumnData,
+) ([]string, map[string]interface{}, error) {
+	doc := make(map[string]interface{}, len(data))
+	var keyValues []string
+	for _, col := range data {
+		if col == nil {
+			continue
+		}
+		colInfo, ok := tableInfo.GetColumnInfo(col.ColumnID)
+		if !ok {
+			continue
+		}
+		value, err := predis.ConvertValue(&colInfo.FieldType, col.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc[colInfo.Name.O] = value
+		if tableInfo.ForceGetColumnFlagType(col.ColumnID).IsHandleKey() {
+			keyValues = append(keyValues, model.ColumnValueString(col.Value))
+		}
+	}
+	return keyValues, doc, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDMLMaxRetry uint64 = 8
+	// Redis handles small pipelines quickly, so don't wait too long for
+	// more transactions.
+	maxFlushInterval = 10 * time.Millisecond
+)
+
+type redisBackend struct {
+	workerID    int
+	changefeed  string
+	client      redis.UniversalClient
+	cfg         *predis.Config
+	dmlMaxRetry uint64
+
+	events []*dmlsink.TxnCallbackableEvent
+	rows   int
+	// skippedTables are the tables without handle key, which have been
+	// warned about.
+	skippedTables map[int64]struct{}
+
+	statistics *metrics.Statistics
+}
+
+// NewRedisBackends creates the Redis backends of a txn sink, all the
+// backends share one client.
+func NewRedisBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	clientFactory predis.Factory,
+	statistics *metrics.Statistics,
+) ([]*redisBackend, error) {
+	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
+
+	cfg := predis.NewConfig()
+	if err := cfg.Apply(sinkURI); err != nil {
+		return nil, err
+	}
+
+	client, err := clientFactory(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]*redisBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &redisBackend{
+			workerID:      i,
+			changefeed:    changefeed,
+			client:        client,
+			cfg:           cfg,
+			dmlMaxRetry:   defaultDMLMaxRetry,
+			skippedTables: make(map[int64]struct{}),
+			statistics:    statistics,
+		})
+	}
+
+	log.Info("Redis backends is created",
+		zap.String("changefeed", changefeed),
+		zap.Int("workerCount", cfg.WorkerCount),
+		zap.Bool("cluster", cfg.Cluster),
+		zap.Duration("keyTTL", cfg.KeyTTL))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It adds the event to the buffer, and return true if it needs flush immediately.
+func (s *redisBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.PipelineSize
+}
+
+// Flush implements interface backend.
+func (s *redisBackend) Flush(ctx context.Context) error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	rows := make([]*model.RowChangedEvent, 0, s.rows)
+	for _, event := range s.events {
+		rows = append(rows, event.Event.Rows...)
+	}
+
+	commands, skipped, err := prepareCommands(rows, s.cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.statistics.ObserveRows(rows...)
+	for _, tableID := range skipped {
+		if _, ok := s.skippedTables[tableID]; !ok {
+			s.skippedTables[tableID] = struct{}{}
+			log.Warn("rows of the table without handle key are skipped by the Redis sink",
+				zap.String("changefeed", s.changefeed), zap.Int64("tableID", tableID))
+		}
+	}
+	for start := 0; start < len(commands); start += s.cfg.PipelineSize {
+		end := start + s.cfg.PipelineSize
+		if end > len(commands) {
+			end = len(commands)
+		}
+		if err := s.execPipelineWithRetries(ctx, commands[start:end]); err != nil {
+			if errors.Cause(err) != context.Canceled {
+				log.Error("execute commands failed",
+					zap.String("changefeed", s.changefeed), zap.Error(err))
+			}
+			return errors.Trace(err)
+		}
+	}
+	for _, event := range s.events {
+		event.Callback()
+	}
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return nil
+}
+
+// execPipelineWithRetries sends the commands in one pipeline. The commands
+// are idempotent, so the whole pipeline is sent again after a failure.
+func (s *redisBackend) execPipelineWithRetries(ctx context.Context, commands []command) error {
+	start := time.Now()
+	return retry.Do(ctx, func() error {
+		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
+			pipe := s.client.Pipeline()
+			for _, cmd := range commands {
+				if cmd.value == nil {
+					pipe.Del(ctx, cmd.key)
+				} else {
+					pipe.Set(ctx, cmd.key, cmd.value, s.cfg.KeyTTL)
+				}
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return 0, 0, wrapError(err)
+			}
+			return len(commands), 0, nil
+		})
+		if err != nil {
+			log.Warn("execute commands with error, retry later",
+				zap.Error(err), zap.Duration("duration", time.Since(start)),
+				zap.Int("count", len(commands)),
+				zap.String("changefeed", s.changefeed))
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfCommands", len(commands)))
+		return nil
+	}, retry.WithBackoffBaseDelay(sink.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(sink.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(s.dmlMaxRetry),
+		retry.WithIsRetryableErr(isRetryableError))
+}
+
+func wrapError(err error) error {
+	if errors.Cause(err) == context.Canceled {
+		return err
+	}
+	return cerror.WrapError(cerror.ErrRedisRequestError, err)
+}
+
+// retryableReplyPrefixes are the prefixes of the error replies of Redis
+// which are caused by the temporary unavailability of the servers.
+var retryableReplyPrefixes = []string{"LOADING", "BUSY", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN"}
+
+// isRetryableError returns true if the error is caused by the connection
+// or a temporary unavailability of the servers, the other error replies,
+// such as WRONGTYPE, are not retryable.
+func isRetryableError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+	var replyErr redis.Error
+	if cerror.As(err, &replyErr) {
+		msg := replyErr.Error()
+		for _, prefix := range retryableReplyPrefixes {
+			if strings.HasPrefix(msg, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *redisBackend) MaxFlushInterval() time.Duration {
+	return maxFlushInterval
+}
+
+// Close implements interface backend.
+func (s *redisBackend) Close() (err error) {
+	if s.client != nil {
+		// The client is shared by all the backends, it may have been
+		// closed by another one.
+		if err = s.client.Close(); err == redis.ErrClosed {
+			err = nil
+		}
+		s.client = nil
+	}
+	return
+}
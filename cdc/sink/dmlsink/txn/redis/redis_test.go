@@ -0,0 +1,119 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	// Enable force-replicate, otherwise the rows of test.t2 are not mounted.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.ForceReplicate = true
+	helper := entry.NewSchemaTestHelperWithReplicaConfig(t, replicaConfig)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(32), price decimal(5, 2))")
+	insert1 := helper.DML2Event("insert into test.t values (1, 'x', 1.50)", "test", "t")
+	insert2 := helper.DML2Event("insert into test.t values (2, 'y', null)", "test", "t")
+	update := *insert1
+	update.PreColumns = insert1.Columns
+	update.Columns = []*model.ColumnData{
+		insert1.Columns[0],
+		{ColumnID: insert1.Columns[1].ColumnID, Value: []byte("z")},
+		insert1.Columns[2],
+	}
+	remove := *insert2
+	remove.PreColumns, remove.Columns = insert2.Columns, nil
+	helper.DDL2Event("create table test.t2(a int)")
+	noKey := helper.DML2Event("insert into test.t2 values (1)", "test", "t2")
+
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("redis://127.0.0.1:6379?worker-count=1&pipeline-size=2&key-ttl=1h")
+	require.NoError(t, err)
+	backends, err := NewRedisBackends(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		func(ctx context.Context, cfg *predis.Config) (redis.UniversalClient, error) {
+			require.Equal(t, []string{"127.0.0.1:6379"}, cfg.Addrs)
+			return redis.NewClient(&redis.Options{Addr: server.Addr()}), nil
+		}, metrics.NewStatistics(ctx, model.DefaultChangeFeedID("test"), sink.TxnSink))
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	backend := backends[0]
+
+	flushed := 0
+	needFlush := backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{insert1, insert2}},
+		Callback: func() { flushed++ },
+	})
+	require.True(t, needFlush)
+	require.NoError(t, backend.Flush(ctx))
+	require.Equal(t, 1, flushed)
+	value, err := server.Get("tidb:test.t:1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": 1, "name": "x", "price": 1.50}`, value)
+	require.True(t, server.Exists("tidb:test.t:2"))
+	require.Equal(t, time.Hour, server.TTL("tidb:test.t:1"))
+
+	backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{&update, &remove}},
+		Callback: func() { flushed++ },
+	})
+	backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: []*model.RowChangedEvent{noKey}},
+		Callback: func() { flushed++ },
+	})
+	require.NoError(t, backend.Flush(ctx))
+	require.Equal(t, 3, flushed)
+	value, err = server.Get("tidb:test.t:1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": 1, "name": "z", "price": 1.50}`, value)
+	require.False(t, server.Exists("tidb:test.t:2"))
+	// The rows of tables without handle key are skipped.
+	require.Equal(t, []string{"tidb:test.t:1"}, server.Keys())
+
+	require.NoError(t, backend.Close())
+}
+
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+
+func (replyError) RedisError() {}
+
+func TestIsRetryableError(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isRetryableError(wrapError(io.EOF)))
+	require.True(t, isRetryableError(wrapError(replyError("LOADING Redis is loading the dataset in memory"))))
+	require.False(t, isRetryableError(wrapError(
+		replyError("WRONGTYPE Operation against a key holding the wrong kind of value"))))
+	require.False(t, isRetryableError(wrapError(context.Canceled)))
+}
@@ -21,13 +21,23 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/clickhouse"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/elasticsearch"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/mongodb"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/mysql"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/pg"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/redis"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/causality"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+	pes "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	pmongodb "github.com/pingcap/tiflow/pkg/sink/mongodb"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	ppg "github.com/pingcap/tiflow/pkg/sink/pg"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -56,6 +66,11 @@ type dmlSink struct {
 	statistics *metrics.Statistics
 
 	scheme string
+
+	// getGTIDExecuted is only set for MySQL compatible downstreams.
+	getGTIDExecuted func() string
+	// getBinlogPosition is only set for MySQL compatible downstreams.
+	getBinlogPosition func() *model.BinlogPosition
 }
 
 // GetDBConnImpl is the implementation of pmysql.Factory.
@@ -72,29 +87,170 @@ func NewMySQLSink(
 	replicaConfig *config.ReplicaConfig,
 	errCh chan<- error,
 	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	var (
+		getGTIDExecuted   func() string
+		getBinlogPosition func() *model.BinlogPosition
+	)
+	s, err := newSinkWithBackends(ctx, changefeedID, sinkURI, errCh, conflictDetectorSlots,
+		func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error) {
+			backendImpls, err := mysql.NewMySQLBackends(ctx, changefeedID, sinkURI,
+				replicaConfig, GetDBConnImpl, statistics)
+			if err != nil {
+				return nil, err
+			}
+			if len(backendImpls) > 0 {
+				// All backends share the same GTID and binlog position trackers.
+				getGTIDExecuted = backendImpls[0].GetGTIDExecuted
+				getBinlogPosition = backendImpls[0].GetBinlogPosition
+			}
+			return toBackends(backendImpls, nil)
+		})
+	if err != nil {
+		return nil, err
+	}
+	s.getGTIDExecuted = getGTIDExecuted
+	s.getBinlogPosition = getBinlogPosition
+	return s, nil
+}
+
+// GetPGDBConnImpl is the implementation of ppg.Factory.
+// Exported for testing.
+var GetPGDBConnImpl ppg.Factory = ppg.CreatePGDBConn
+
+// NewPGSink creates a PostgreSQL dmlSink with given parameters.
+func NewPGSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	return newSinkWithBackends(ctx, changefeedID, sinkURI, errCh, conflictDetectorSlots,
+		func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error) {
+			return toBackends(pg.NewPGBackends(ctx, changefeedID, sinkURI,
+				GetPGDBConnImpl, statistics))
+		})
+}
+
+// GetClickHouseDBConnImpl is the implementation of pclickhouse.Factory.
+// Exported for testing.
+var GetClickHouseDBConnImpl pclickhouse.Factory = pclickhouse.CreateClickHouseDBConn
+
+// NewClickHouseSink creates a ClickHouse dmlSink with given parameters.
+func NewClickHouseSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	return newSinkWithBackends(ctx, changefeedID, sinkURI, errCh, conflictDetectorSlots,
+		func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error) {
+			return toBackends(clickhouse.NewClickHouseBackends(ctx, changefeedID, sinkURI,
+				GetClickHouseDBConnImpl, statistics))
+		})
+}
+
+// GetElasticsearchClientImpl is the implementation of pes.Factory.
+// Exported for testing.
+var GetElasticsearchClientImpl pes.Factory = pes.CreateClient
+
+// NewElasticsearchSink creates an Elasticsearch dmlSink with given parameters.
+func NewElasticsearchSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	return newSinkWithBackends(ctx, changefeedID, sinkURI, errCh, conflictDetectorSlots,
+		func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error) {
+			return toBackends(elasticsearch.NewElasticsearchBackends(ctx, changefeedID, sinkURI,
+				replicaConfig, GetElasticsearchClientImpl, statistics))
+		})
+}
+
+// GetRedisClientImpl is the implementation of predis.Factory.
+// Exported for testing.
+var GetRedisClientImpl predis.Factory = predis.CreateClient
+
+// NewRedisSink creates a Redis dmlSink with given parameters.
+func NewRedisSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	return newSinkWithBackends(ctx, changefeedID, sinkURI, errCh, conflictDetectorSlots,
+		func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error) {
+			return toBackends(redis.NewRedisBackends(ctx, changefeedID, sinkURI,
+				GetRedisClientImpl, statistics))
+		})
+}
+
+// GetMongoDBClientImpl is the implementation of pmongodb.Factory.
+// Exported for testing.
+var GetMongoDBClientImpl pmongodb.Factory = pmongodb.CreateClient
+
+// NewMongoDBSink creates a MongoDB dmlSink with given parameters.
+func NewMongoDBSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	return newSinkWithBackends(ctx, changefeedID, sinkURI, errCh, conflictDetectorSlots,
+		func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error) {
+			return toBackends(mongodb.NewMongoDBBackends(ctx, changefeedID, sinkURI,
+				GetMongoDBClientImpl, statistics))
+		})
+}
+
+// backendFactory creates the backends of a dmlSink, the backends report
+// their metrics to the statistics of the dmlSink.
+type backendFactory func(ctx context.Context, statistics *metrics.Statistics) ([]backend, error)
+
+// newSinkWithBackends creates a dmlSink whose backends are created by newBackends.
+func newSinkWithBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+	newBackends backendFactory,
 ) (*dmlSink, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	statistics := metrics.NewStatistics(ctx, changefeedID, sink.TxnSink)
 
-	backendImpls, err := mysql.NewMySQLBackends(ctx, changefeedID, sinkURI, replicaConfig, GetDBConnImpl, statistics)
+	backends, err := newBackends(ctx, statistics)
 	if err != nil {
 		cancel()
 		return nil, err
 	}
 
-	backends := make([]backend, 0, len(backendImpls))
-	for _, impl := range backendImpls {
-		backends = append(backends, impl)
-	}
-
 	s := newSink(ctx, changefeedID, backends, errCh, conflictDetectorSlots)
 	s.statistics = statistics
 	s.cancel = cancel
 	s.scheme = sink.GetScheme(sinkURI)
-
 	return s, nil
 }
 
+// toBackends converts the backend implementations to backends.
+func toBackends[B backend](backendImpls []B, err error) ([]backend, error) {
+	if err != nil {
+		return nil, err
+	}
+	backends := make([]backend, 0, len(backendImpls))
+	for _, impl := range backendImpls {
+		backends = append(backends, impl)
+	}
+	return backends, nil
+}
+
 func newSink(ctx context.Context,
 	changefeedID model.ChangeFeedID,
 	backends []backend,
@@ -181,3 +337,21 @@ func (s *dmlSink) Dead() <-chan struct{} {
 func (s *dmlSink) Scheme() string {
 	return s.scheme
 }
+
+// GetGTIDExecuted returns the latest gtid_executed of the downstream.
+// It returns an empty string if the downstream doesn't support GTID.
+func (s *dmlSink) GetGTIDExecuted() string {
+	if s.getGTIDExecuted == nil {
+		return ""
+	}
+	return s.getGTIDExecuted()
+}
+
+// GetBinlogPosition returns the latest binlog position of the downstream.
+// It returns nil if track-binlog-position is not enabled.
+func (s *dmlSink) GetBinlogPosition() *model.BinlogPosition {
+	if s.getBinlogPosition == nil {
+		return nil
+	}
+	return s.getBinlogPosition()
+}
@@ -22,7 +22,9 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -108,3 +110,77 @@ func TestTxnSinkNolocking(t *testing.T) {
 	require.Equal(t, uint32(100), atomic.LoadUint32(&handled))
 	sink.Close()
 }
+
+// lowRateBackend never reaches the max batch size, so batches are
+// only flushed when the max batch wait elapses.
+type lowRateBackend struct {
+	events []*dmlsink.TxnCallbackableEvent
+}
+
+func (b *lowRateBackend) OnTxnEvent(e *dmlsink.TxnCallbackableEvent) bool {
+	b.events = append(b.events, e)
+	return false
+}
+
+func (b *lowRateBackend) Flush(ctx context.Context) error {
+	for _, e := range b.events {
+		e.Callback()
+	}
+	b.events = b.events[:0]
+	return nil
+}
+
+func (b *lowRateBackend) MaxFlushInterval() time.Duration {
+	return 100 * time.Millisecond
+}
+
+func (b *lowRateBackend) Close() error {
+	return nil
+}
+
+// TestTxnSinkLowRateLatency checks the latency of a low-rate table is
+// bounded by the max batch wait even if the batch is never full.
+func TestTxnSinkLowRateLatency(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test-low-rate")
+	errCh := make(chan error, 1)
+	sink := newSink(context.Background(), changefeedID,
+		[]backend{&lowRateBackend{}}, errCh, DefaultConflictDetectorSlots)
+	defer sink.Close()
+
+	tableInfo := model.BuildTableInfo("test", "t1", []*model.Column{
+		{Name: "a", Type: mysql.TypeLong},
+	}, nil)
+	for i := 0; i < 3; i++ {
+		sinkState := new(state.TableSinkState)
+		*sinkState = state.TableSinkSinking
+		done := make(chan struct{})
+		start := time.Now()
+		sink.WriteEvents(&dmlsink.CallbackableEvent[*model.SingleTableTxn]{
+			Event: &model.SingleTableTxn{
+				Rows: []*model.RowChangedEvent{{
+					TableInfo: tableInfo,
+					Columns: model.Columns2ColumnDatas([]*model.Column{
+						{Name: "a", Value: i},
+					}, tableInfo),
+				}},
+			},
+			Callback:  func() { close(done) },
+			SinkState: sinkState,
+		})
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "the partial batch is not flushed")
+		}
+		latency := time.Since(start)
+		require.GreaterOrEqual(t, latency, 100*time.Millisecond)
+		require.Less(t, latency, time.Second)
+	}
+
+	require.Equal(t, float64(3), testutil.ToFloat64(txn.BatchFlushReason.WithLabelValues(
+		changefeedID.Namespace, changefeedID.ID, "timeout")))
+	require.Equal(t, float64(0), testutil.ToFloat64(txn.BatchFlushReason.WithLabelValues(
+		changefeedID.Namespace, changefeedID.ID, "full_batch")))
+}
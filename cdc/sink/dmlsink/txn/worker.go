@@ -47,6 +47,8 @@ type worker struct {
 	metricTxnWorkerFlushDuration prometheus.Observer
 	metricTxnWorkerTotalDuration prometheus.Observer
 	metricTxnWorkerHandledRows   prometheus.Counter
+	metricFlushFullBatch         prometheus.Counter
+	metricFlushTimeout           prometheus.Counter
 
 	// Fields only used in the background loop.
 	flushInterval            time.Duration
@@ -72,6 +74,8 @@ func newWorker(ctx context.Context, changefeedID model.ChangeFeedID,
 		metricTxnWorkerFlushDuration: txn.WorkerFlushDuration.WithLabelValues(changefeedID.Namespace, changefeedID.ID, wid),
 		metricTxnWorkerTotalDuration: txn.WorkerTotalDuration.WithLabelValues(changefeedID.Namespace, changefeedID.ID, wid),
 		metricTxnWorkerHandledRows:   txn.WorkerHandledRows.WithLabelValues(changefeedID.Namespace, changefeedID.ID, wid),
+		metricFlushFullBatch:         txn.BatchFlushReason.WithLabelValues(changefeedID.Namespace, changefeedID.ID, "full_batch"),
+		metricFlushTimeout:           txn.BatchFlushReason.WithLabelValues(changefeedID.Namespace, changefeedID.ID, "timeout"),
 
 		flushInterval:            backend.MaxFlushInterval(),
 		hasPending:               false,
@@ -117,19 +121,26 @@ func (w *worker) run() error {
 		case txn := <-w.txnCh.Out():
 			// we get the data from txnCh.out until no more data here or reach the state that can be flushed.
 			// If no more data in txnCh.out, and also not reach the state that can be flushed,
-			// we will wait for 10ms and then do flush to avoid too much flush with small amount of txns.
+			// we will wait for flushInterval and then do flush to avoid too much flush with
+			// small amount of txns, so the latency is bounded by flushInterval.
 			if txn.txnEvent != nil {
 				needFlush := w.onEvent(txn)
 				if !needFlush {
 					delay := time.NewTimer(w.flushInterval)
+					timeout := false
 					for !needFlush {
 						select {
 						case txn := <-w.txnCh.Out():
 							needFlush = w.onEvent(txn)
 						case <-delay.C:
-							needFlush = true
+							needFlush, timeout = true, true
 						}
 					}
+					if timeout {
+						w.metricFlushTimeout.Inc()
+					} else {
+						w.metricFlushFullBatch.Inc()
+					}
 					// Release resources promptly
 					if !delay.Stop() {
 						select {
@@ -137,6 +148,8 @@ func (w *worker) run() error {
 						default:
 						}
 					}
+				} else {
+					w.metricFlushFullBatch.Inc()
 				}
 				// needFlush must be true here, so we can do flush.
 				if err := w.doFlush(); err != nil {
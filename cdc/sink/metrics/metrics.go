@@ -73,6 +73,25 @@ var (
 			Name:      "execution_error",
 			Help:      "Total count of execution errors.",
 		}, []string{"namespace", "changefeed", "type"}) // type is for `sinkType`
+
+	// SchemaMismatchCounter is the counter of column type mismatches between
+	// the upstream and downstream tables.
+	SchemaMismatchCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "schema_mismatch_total",
+			Help:      "Total count of column type mismatches between upstream and downstream.",
+		}, []string{"namespace", "changefeed", "table", "column"})
+
+	// DDLSkippedCounter is the counter of DDLs skipped since they are not
+	// supported by the downstream.
+	DDLSkippedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Name:      "ddl_skipped_total",
+			Help:      "Total count of DDLs skipped since they are not supported by the downstream.",
+		}, []string{"namespace", "changefeed", "ddl_type"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -82,6 +101,8 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(ExecDDLHistogram)
 	registry.MustRegister(LargeRowSizeHistogram)
 	registry.MustRegister(ExecutionErrorCounter)
+	registry.MustRegister(SchemaMismatchCounter)
+	registry.MustRegister(DDLSkippedCounter)
 
 	tablesink.InitMetrics(registry)
 	txn.InitMetrics(registry)
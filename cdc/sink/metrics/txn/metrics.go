@@ -81,6 +81,16 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 18), // 10ms~1300s
 		}, []string{"namespace", "changefeed"})
 
+	// BatchFlushReason records why the txn workers flush a batch, the reason
+	// is "full_batch" or "timeout".
+	BatchFlushReason = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "batch_flush_reason",
+			Help:      "The number of batches flushed by txn workers for each reason",
+		}, []string{"namespace", "changefeed", "reason"})
+
 	PrepareStatementErrors = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "ticdc",
@@ -88,6 +98,66 @@ var (
 			Name:      "txn_prepare_statement_errors",
 			Help:      "Prepare statement errors",
 		}, []string{"namespace", "changefeed"})
+
+	// MySQLSQLBytesWritten records the bytes of SQL statements and their
+	// arguments written to the MySQL compatible downstream for each table.
+	MySQLSQLBytesWritten = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_sql_bytes_written_total",
+			Help:      "The total bytes of SQL statements written to downstream",
+		}, []string{"namespace", "changefeed", "table"})
+
+	// MySQLSourceEventBytes records the approximate bytes of the source row
+	// changed events written to the MySQL compatible downstream for each table.
+	MySQLSourceEventBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_source_event_bytes_total",
+			Help:      "The total approximate bytes of source events written to downstream",
+		}, []string{"namespace", "changefeed", "table"})
+
+	// MySQLWriteAmplificationRatio is the ratio of MySQLSQLBytesWritten to
+	// MySQLSourceEventBytes for each table.
+	MySQLWriteAmplificationRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_write_amplification_ratio",
+			Help:      "The bytes of SQL statements written per byte of source events",
+		}, []string{"namespace", "changefeed", "table"})
+
+	// LockTimeoutRetryCounter records the retries of the DML batches failed by
+	// lock wait timeout.
+	LockTimeoutRetryCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "lock_timeout_retry_total",
+			Help:      "The number of retries of DML batches failed by lock wait timeout",
+		}, []string{"namespace", "changefeed"})
+
+	// AutoCreateTableCounter records the missing downstream tables created
+	// by the MySQL sink.
+	AutoCreateTableCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "auto_create_table_total",
+			Help:      "The number of missing downstream tables created automatically",
+		}, []string{"namespace", "changefeed"})
+
+	// AutoIncrementConflictCounter records the inserted rows of the tables
+	// with an AUTO_INCREMENT column which collide with the downstream rows.
+	AutoIncrementConflictCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "auto_increment_conflict_total",
+			Help:      "The number of inserted rows whose AUTO_INCREMENT IDs collide with the downstream rows",
+		}, []string{"namespace", "changefeed", "policy"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -100,4 +170,11 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(SinkDMLBatchCommit)
 	registry.MustRegister(SinkDMLBatchCallback)
 	registry.MustRegister(PrepareStatementErrors)
+	registry.MustRegister(BatchFlushReason)
+	registry.MustRegister(MySQLSQLBytesWritten)
+	registry.MustRegister(MySQLSourceEventBytes)
+	registry.MustRegister(MySQLWriteAmplificationRatio)
+	registry.MustRegister(LockTimeoutRetryCounter)
+	registry.MustRegister(AutoCreateTableCounter)
+	registry.MustRegister(AutoIncrementConflictCounter)
 }
@@ -0,0 +1,207 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+)
+
+// createDBConn is used to connect to the upstream and the downstream
+// databases, it's replaced in tests.
+var createDBConn pmysql.Factory = pmysql.CreateMySQLDBConn
+
+const queryColumnCounts = "SELECT TABLE_SCHEMA, TABLE_NAME, COUNT(*) " +
+	"FROM information_schema.COLUMNS GROUP BY TABLE_SCHEMA, TABLE_NAME"
+
+// SchemaValidationError describes a table whose schema doesn't match
+// between the upstream and the downstream.
+type SchemaValidationError struct {
+	Schema string
+	Table  string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e SchemaValidationError) Error() string {
+	if e.Table == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", quotes.QuoteSchema(e.Schema, e.Table), e.Reason)
+}
+
+// ValidateSchemas checks the tables matched by the filter on the upstream
+// and, if downstreamURI is not empty, on the downstream. All the mismatches
+// are reported in one error so that they can be fixed at once.
+func ValidateSchemas(
+	ctx context.Context, f filter.Filter, upstreamDSN, downstreamURI string,
+) error {
+	errs := validateSchemas(ctx, f, upstreamDSN, downstreamURI)
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return cerror.ErrSchemaValidationFailed.GenWithStackByArgs(
+		fmt.Sprintf("%d problem(s) found:\n%s", len(errs), strings.Join(msgs, "\n")))
+}
+
+// validateSchemas returns all the mismatches of the tables matched by the
+// filter:
+//   - no table on the upstream matches the filter;
+//   - a table exists on one side but not the other;
+//   - a table has different column counts on the two sides.
+//
+// The downstream is only checked if downstreamURI is not empty, and it must
+// be a MySQL compatible sink.
+func validateSchemas(
+	ctx context.Context, f filter.Filter, upstreamDSN, downstreamURI string,
+) []SchemaValidationError {
+	upstream, err := queryTables(ctx, f, func() (*sql.DB, error) {
+		return createDBConn(ctx, upstreamDSN)
+	})
+	if err != nil {
+		return []SchemaValidationError{{
+			Reason: fmt.Sprintf("failed to query the upstream tables: %s", err),
+		}}
+	}
+	if len(upstream) == 0 {
+		return []SchemaValidationError{{
+			Reason: "no upstream table matches the filter rules",
+		}}
+	}
+	if downstreamURI == "" {
+		return nil
+	}
+
+	downstream, err := queryTables(ctx, f, func() (*sql.DB, error) {
+		return openDownstream(ctx, downstreamURI)
+	})
+	if err != nil {
+		return []SchemaValidationError{{
+			Reason: fmt.Sprintf("failed to query the downstream tables: %s", err),
+		}}
+	}
+	return compareTables(upstream, downstream)
+}
+
+// queryTables returns the column counts of the tables matched by the filter.
+func queryTables(
+	ctx context.Context, f filter.Filter, openDB func() (*sql.DB, error),
+) (map[model.TableName]int, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, queryColumnCounts)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+	tables := make(map[model.TableName]int)
+	for rows.Next() {
+		var (
+			name    model.TableName
+			columns int
+		)
+		if err := rows.Scan(&name.Schema, &name.Table, &columns); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		if f.ShouldIgnoreTable(name.Schema, name.Table) {
+			continue
+		}
+		tables[name] = columns
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return tables, nil
+}
+
+func openDownstream(ctx context.Context, downstreamURI string) (*sql.DB, error) {
+	uri, err := url.Parse(downstreamURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMySQLCompatibleScheme(uri.Scheme) {
+		return nil, cerror.ErrSinkURIInvalid.GenWithStack(
+			"downstream schema validation requires a MySQL compatible sink, got %s", uri.Scheme)
+	}
+	cfg := pmysql.NewConfig()
+	id := model.ChangeFeedID{Namespace: "default", ID: "schema-verify"}
+	err = cfg.Apply(config.GetGlobalServerConfig().TZ, id, uri, config.GetDefaultReplicaConfig())
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := pmysql.GenBasicDSN(uri, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return pmysql.GetTestDB(ctx, dsn, createDBConn)
+}
+
+func compareTables(upstream, downstream map[model.TableName]int) []SchemaValidationError {
+	var errs []SchemaValidationError
+	for name, columns := range upstream {
+		downstreamColumns, ok := downstream[name]
+		if !ok {
+			errs = append(errs, SchemaValidationError{
+				Schema: name.Schema,
+				Table:  name.Table,
+				Reason: "table doesn't exist on the downstream",
+			})
+			continue
+		}
+		if columns != downstreamColumns {
+			errs = append(errs, SchemaValidationError{
+				Schema: name.Schema,
+				Table:  name.Table,
+				Reason: fmt.Sprintf("column count mismatch, "+
+					"upstream has %d columns but downstream has %d", columns, downstreamColumns),
+			})
+		}
+	}
+	for name := range downstream {
+		if _, ok := upstream[name]; !ok {
+			errs = append(errs, SchemaValidationError{
+				Schema: name.Schema,
+				Table:  name.Table,
+				Reason: "table doesn't exist on the upstream",
+			})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Schema != errs[j].Schema {
+			return errs[i].Schema < errs[j].Schema
+		}
+		return errs[i].Table < errs[j].Table
+	})
+	return errs
+}
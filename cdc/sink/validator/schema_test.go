@@ -0,0 +1,121 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/stretchr/testify/require"
+)
+
+type tableColumns struct {
+	schema  string
+	table   string
+	columns int
+}
+
+// mockSchemaDBs makes createDBConn return the mocked databases, the
+// databases are told apart by the host in the DSN.
+func mockSchemaDBs(t *testing.T, upstream, downstream []tableColumns) {
+	newDB := func(tables []tableColumns) *sql.DB {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		rows := sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME", "COUNT(*)"})
+		for _, tbl := range tables {
+			rows.AddRow(tbl.schema, tbl.table, tbl.columns)
+		}
+		mock.ExpectQuery("SELECT TABLE_SCHEMA, TABLE_NAME, COUNT.*").WillReturnRows(rows)
+		mock.ExpectClose()
+		return db
+	}
+	backup := createDBConn
+	t.Cleanup(func() { createDBConn = backup })
+	createDBConn = func(ctx context.Context, dsn string) (*sql.DB, error) {
+		if strings.Contains(dsn, "upstream") {
+			return newDB(upstream), nil
+		}
+		return newDB(downstream), nil
+	}
+}
+
+func newTestFilter(t *testing.T, rules ...string) filter.Filter {
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = rules
+	f, err := filter.NewFilter(cfg, "")
+	require.NoError(t, err)
+	return f
+}
+
+func TestValidateSchemas(t *testing.T) {
+	const (
+		upstreamDSN   = "root@tcp(upstream:4000)/"
+		downstreamURI = "mysql://root@downstream:3306/"
+	)
+	ctx := context.Background()
+	f := newTestFilter(t, "test.*")
+
+	// The tables out of the filter rules are not checked.
+	mockSchemaDBs(t,
+		[]tableColumns{{"test", "t1", 3}, {"mysql", "user", 10}, {"other", "t1", 2}},
+		[]tableColumns{{"test", "t1", 3}, {"other", "t2", 2}})
+	require.Empty(t, validateSchemas(ctx, f, upstreamDSN, downstreamURI))
+	require.NoError(t, ValidateSchemas(ctx, f, upstreamDSN, downstreamURI))
+
+	// No table matches the filter rules on the upstream.
+	mockSchemaDBs(t, []tableColumns{{"other", "t1", 2}}, nil)
+	require.Equal(t, []SchemaValidationError{{
+		Reason: "no upstream table matches the filter rules",
+	}}, validateSchemas(ctx, f, upstreamDSN, ""))
+
+	// The downstream is not checked if downstreamURI is empty.
+	mockSchemaDBs(t, []tableColumns{{"test", "t1", 3}}, nil)
+	require.Empty(t, validateSchemas(ctx, f, upstreamDSN, ""))
+
+	// Missing tables and column count mismatches are all reported.
+	mockSchemaDBs(t,
+		[]tableColumns{{"test", "t1", 3}, {"test", "t2", 4}, {"test", "t3", 5}},
+		[]tableColumns{{"test", "t1", 3}, {"test", "t3", 6}, {"test", "t4", 1}})
+	errs := validateSchemas(ctx, f, upstreamDSN, downstreamURI)
+	require.Equal(t, []SchemaValidationError{
+		{Schema: "test", Table: "t2", Reason: "table doesn't exist on the downstream"},
+		{
+			Schema: "test", Table: "t3",
+			Reason: "column count mismatch, upstream has 5 columns but downstream has 6",
+		},
+		{Schema: "test", Table: "t4", Reason: "table doesn't exist on the upstream"},
+	}, errs)
+
+	mockSchemaDBs(t,
+		[]tableColumns{{"test", "t1", 3}, {"test", "t2", 4}},
+		[]tableColumns{{"test", "t1", 2}})
+	err := ValidateSchemas(ctx, f, upstreamDSN, downstreamURI)
+	require.ErrorContains(t, err, "2 problem(s) found")
+	require.ErrorContains(t, err, "`test`.`t1`: column count mismatch")
+	require.ErrorContains(t, err, "`test`.`t2`: table doesn't exist on the downstream")
+}
+
+func TestValidateSchemasDownstreamNotMySQL(t *testing.T) {
+	ctx := context.Background()
+	f := newTestFilter(t, "test.*")
+	mockSchemaDBs(t, []tableColumns{{"test", "t1", 3}}, nil)
+	errs := validateSchemas(ctx, f, "root@tcp(upstream:4000)/", "kafka://127.0.0.1:9092/topic")
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Reason, "requires a MySQL compatible sink")
+}
@@ -74,7 +74,7 @@ func checkSyncPointSchemeCompatibility(
 		return cerror.ErrSinkURIInvalid.
 			GenWithStack(
 				"sink uri scheme is not supported with syncpoint enabled"+
-					"sink uri: %s", uri,
+					"sink uri: %s", util.MustMaskSinkURI(uri.String()),
 			)
 	}
 	return nil
@@ -107,18 +107,14 @@ func preCheckSinkURI(sinkURIStr string) (*url.URL, error) {
 }
 
 func checkBDRMode(ctx context.Context, sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
-	maskSinkURI, err := util.MaskSinkURI(sinkURI.String())
-	if err != nil {
-		return err
-	}
-
+	maskSinkURI := util.MustMaskSinkURI(sinkURI.String())
 	if !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
 		return cerror.ErrSinkURIInvalid.
 			GenWithStack("sink uri scheme is not supported in BDR mode, sink uri: %s", maskSinkURI)
 	}
 	cfg := pmysql.NewConfig()
 	id := model.ChangeFeedID{Namespace: "default", ID: "sink-verify"}
-	err = cfg.Apply(config.GetGlobalServerConfig().TZ, id, sinkURI, replicaConfig)
+	err := cfg.Apply(config.GetGlobalServerConfig().TZ, id, sinkURI, replicaConfig)
 	if err != nil {
 		return err
 	}
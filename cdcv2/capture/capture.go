@@ -27,7 +27,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/controller"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
+	"github.com/pingcap/tiflow/cdc/processor"
+	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/factory"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	controllerv2 "github.com/pingcap/tiflow/cdcv2/controller"
 	"github.com/pingcap/tiflow/cdcv2/metadata"
 	msql "github.com/pingcap/tiflow/cdcv2/metadata/sql"
@@ -357,6 +360,18 @@ func (c *captureImpl) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	wait(doneM)
 }
 
+func (c *captureImpl) GetTableStats(
+	ctx context.Context, id model.ChangeFeedID,
+) ([]sinkmanager.TableReplicationStats, error) {
+	panic("implement me")
+}
+
+func (c *captureImpl) GetSorterPositionRange(
+	ctx context.Context, id model.ChangeFeedID, span tablepb.Span,
+) (processor.SorterPositionRange, error) {
+	panic("implement me")
+}
+
 func (c *captureImpl) GetUpstreamManager() (*upstream.Manager, error) {
 	if c.upstreamManager == nil {
 		return nil, cerror.ErrUpstreamManagerNotReady
@@ -0,0 +1,77 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// lastHeartbeatReceivedGauge records the unix timestamp in seconds when the
+// last heartbeat event is received from each partition.
+var lastHeartbeatReceivedGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Name:      "last_heartbeat_received_timestamp",
+		Help:      "The unix timestamp in seconds when the last heartbeat event is received",
+	}, []string{"partition"})
+
+func init() {
+	prometheus.MustRegister(lastHeartbeatReceivedGauge)
+}
+
+// minCommitTs returns the minimum commit ts of the buffered events.
+func (g *eventsGroup) minCommitTs() (uint64, bool) {
+	if len(g.events) == 0 && !g.hasSpilled {
+		return 0, false
+	}
+	result := uint64(math.MaxUint64)
+	// The spilled events are sorted by the commit ts.
+	if g.hasSpilled {
+		result = g.spill.minCommitTs
+	}
+	for _, e := range g.events {
+		if e.CommitTs < result {
+			result = e.CommitTs
+		}
+	}
+	return result, true
+}
+
+// onHeartbeat advances the partition resolved ts by the heartbeat event, so
+// that the global resolved ts keeps advancing when the changefeed is idle.
+// Heartbeats do not flush the buffered events, so the resolved ts never exceeds
+// the commit ts of them, they are flushed by the next resolved event.
+// It returns true if the partition resolved ts is advanced.
+func onHeartbeat(
+	sink *partitionSinks, partition int32, ts uint64, eventGroups map[int64]*eventsGroup,
+) bool {
+	lastHeartbeatReceivedGauge.WithLabelValues(strconv.Itoa(int(partition))).
+		Set(float64(time.Now().Unix()))
+
+	for _, group := range eventGroups {
+		if commitTs, ok := group.minCommitTs(); ok && commitTs <= ts {
+			ts = commitTs - 1
+		}
+	}
+	if ts <= atomic.LoadUint64(&sink.resolvedTs) {
+		return false
+	}
+	atomic.StoreUint64(&sink.resolvedTs, ts)
+	return true
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatAdvanceResolvedTs(t *testing.T) {
+	sink := &partitionSinks{}
+	eventGroups := make(map[int64]*eventsGroup)
+
+	// Heartbeats keep advancing the resolved ts during idle periods.
+	for _, ts := range []uint64{100, 200, 300} {
+		require.True(t, onHeartbeat(sink, 0, ts, eventGroups))
+		require.Equal(t, ts, atomic.LoadUint64(&sink.resolvedTs))
+	}
+	require.Greater(t, testutil.ToFloat64(lastHeartbeatReceivedGauge.WithLabelValues("0")), float64(0))
+
+	// Stale heartbeats are ignored.
+	require.False(t, onHeartbeat(sink, 0, 250, eventGroups))
+	require.Equal(t, uint64(300), atomic.LoadUint64(&sink.resolvedTs))
+
+	// Buffered events are not flushed by heartbeats, the resolved ts
+	// stops before them.
+	group := newEventsGroup(0)
+	group.Append(&model.RowChangedEvent{CommitTs: 450})
+	group.Append(&model.RowChangedEvent{CommitTs: 400})
+	eventGroups[1] = group
+	require.True(t, onHeartbeat(sink, 0, 500, eventGroups))
+	require.Equal(t, uint64(399), atomic.LoadUint64(&sink.resolvedTs))
+	require.Len(t, group.events, 2)
+
+	// Once the events are resolved, heartbeats advance the resolved ts again.
+	require.Len(t, group.Resolve(500), 2)
+	require.True(t, onHeartbeat(sink, 0, 600, eventGroups))
+	require.Equal(t, uint64(600), atomic.LoadUint64(&sink.resolvedTs))
+}
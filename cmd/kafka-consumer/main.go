@@ -35,9 +35,11 @@ import (
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	cerror "github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
 	ddlsinkfactory "github.com/pingcap/tiflow/cdc/sink/ddlsink/factory"
@@ -54,11 +56,16 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/avro"
 	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/kafkastreams"
+	"github.com/pingcap/tiflow/pkg/sink/codec/ocdf"
 	"github.com/pingcap/tiflow/pkg/sink/codec/open"
 	"github.com/pingcap/tiflow/pkg/sink/codec/simple"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -68,6 +75,9 @@ func newConsumerOption() *consumerOption {
 
 		maxMessageBytes: math.MaxInt64,
 		maxBatchSize:    math.MaxInt64,
+
+		offsetCommitStrategy: offsetCommitPerMessage,
+		offsetCommitInterval: defaultOffsetCommitInterval,
 	}
 }
 
@@ -101,6 +111,27 @@ type consumerOption struct {
 	upstreamTiDBDSN string
 
 	enableProfiling bool
+
+	// offsetCommitStrategy determines when the consumed offset is committed,
+	// offsetCommitInterval only takes effect for the periodic strategy.
+	offsetCommitStrategy offsetCommitStrategy
+	offsetCommitInterval time.Duration
+
+	// ddlExecutionTimeout caps how long the consumer waits for a DDL to be
+	// executed in the downstream, 0 means waiting until the DDL finishes.
+	ddlExecutionTimeout time.Duration
+
+	// minGlobalResolvedTsAdvance is the min lag between the min partition
+	// resolved ts and the global resolved ts to advance the global resolved
+	// ts and flush DMLs, 0 means advancing it as soon as possible. A larger
+	// value batches more events in one downstream transaction at the cost of
+	// latency.
+	minGlobalResolvedTsAdvance time.Duration
+
+	// eventsGroupMaxMemoryBytes is the max bytes of the buffered events of a
+	// table kept in memory, the overflow events are spilled to disk, 0 means
+	// no limit.
+	eventsGroupMaxMemoryBytes uint64
 }
 
 // Adjust the consumer option by the upstream uri passed in parameters.
@@ -114,6 +145,16 @@ func (o *consumerOption) Adjust(upstreamURI *url.URL, configFile string) error {
 	})
 	o.address = strings.Split(upstreamURI.Host, ",")
 
+	strategy, err := parseOffsetCommitStrategy(string(o.offsetCommitStrategy))
+	if err != nil {
+		return cerror.Trace(err)
+	}
+	o.offsetCommitStrategy = strategy
+	if o.offsetCommitInterval <= 0 {
+		return cerror.Errorf("invalid offset commit interval %s, should be positive",
+			o.offsetCommitInterval)
+	}
+
 	s = upstreamURI.Query().Get("partition-num")
 	if s == "" {
 		partition, err := getPartitionNum(o.address, o.topic)
@@ -187,7 +228,9 @@ func (o *consumerOption) Adjust(upstreamURI *url.URL, configFile string) error {
 		zap.String("groupID", o.groupID),
 		zap.Int("maxMessageBytes", o.maxMessageBytes),
 		zap.Int("maxBatchSize", o.maxBatchSize),
-		zap.String("upstreamURI", upstreamURI.String()))
+		zap.String("offsetCommitStrategy", string(o.offsetCommitStrategy)),
+		zap.Duration("offsetCommitInterval", o.offsetCommitInterval),
+		zap.String("upstreamURI", util.MustMaskSinkURI(upstreamURI.String())))
 	return nil
 }
 
@@ -199,6 +242,7 @@ func main() {
 	var (
 		upstreamURIStr string
 		configFile     string
+		commitStrategy string
 	)
 
 	groupID := fmt.Sprintf("ticdc_kafka_consumer_%s", uuid.New().String())
@@ -216,8 +260,19 @@ func main() {
 	flag.StringVar(&consumerOption.ca, "ca", "", "CA certificate path for Kafka SSL connection")
 	flag.StringVar(&consumerOption.cert, "cert", "", "Certificate path for Kafka SSL connection")
 	flag.StringVar(&consumerOption.key, "key", "", "Private key path for Kafka SSL connection")
-	flag.BoolVar(&consumerOption.enableProfiling, "enable-profiling", false, "enable pprof profiling")
+	flag.BoolVar(&consumerOption.enableProfiling, "enable-profiling", false, "enable pprof profiling and metrics")
+	flag.StringVar(&commitStrategy, "offset-commit-strategy", string(offsetCommitPerMessage),
+		"when to commit the consumed offset, one of perMessage, perResolvedTs and periodic")
+	flag.DurationVar(&consumerOption.offsetCommitInterval, "offset-commit-interval", defaultOffsetCommitInterval,
+		"interval to commit the consumed offset, only used by the periodic offset commit strategy")
+	flag.DurationVar(&consumerOption.ddlExecutionTimeout, "ddl-execution-timeout", 0,
+		"max time to wait for a DDL to be executed in the downstream before proceeding, 0 means no limit")
+	flag.DurationVar(&consumerOption.minGlobalResolvedTsAdvance, "min-global-resolved-ts-advance", 0,
+		"min lag of the resolved ts to advance the global resolved ts and flush DMLs, 0 means no limit")
+	flag.Uint64Var(&consumerOption.eventsGroupMaxMemoryBytes, "events-group-max-memory-bytes", 0,
+		"max bytes of the buffered events of a table kept in memory before spilling them to disk, 0 means no limit")
 	flag.Parse()
+	consumerOption.offsetCommitStrategy = offsetCommitStrategy(commitStrategy)
 
 	err := logutil.InitLogger(&logutil.Config{
 		Level: consumerOption.logLevel,
@@ -240,7 +295,7 @@ func main() {
 	scheme := strings.ToLower(upstreamURI.Scheme)
 	if scheme != "kafka" {
 		log.Panic("invalid upstream-uri scheme, the scheme of upstream-uri must be `kafka`",
-			zap.String("upstreamURI", upstreamURIStr))
+			zap.String("upstreamURI", util.MustMaskSinkURI(upstreamURIStr)))
 	}
 
 	err = consumerOption.Adjust(upstreamURI, configFile)
@@ -277,6 +332,7 @@ func main() {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			http.Handle("/metrics", promhttp.Handler())
 			if err := http.ListenAndServe(":6060", nil); err != nil {
 				log.Panic("Error starting pprof", zap.Error(err))
 			}
@@ -387,6 +443,8 @@ func newSaramaConfig(o *consumerOption) (*sarama.Config, error) {
 	config.Metadata.Retry.Backoff = 500 * time.Millisecond
 	config.Consumer.Retry.Backoff = 500 * time.Millisecond
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	// offsets are committed manually according to the offset commit strategy.
+	config.Consumer.Offsets.AutoCommit.Enable = false
 
 	if len(o.ca) != 0 {
 		config.Net.TLS.Enable = true
@@ -409,13 +467,21 @@ type partitionSinks struct {
 	tableSinksMap     sync.Map
 	// resolvedTs record the maximum timestamp of the received event
 	resolvedTs uint64
+	// catchingUp is true if the partition is assigned by a rebalance and its
+	// resolvedTs hasn't caught up with the global resolved ts.
+	catchingUp atomic.Bool
+	// committer commits the consumed offsets of the partition, it's nil if
+	// the partition is not being consumed.
+	committer atomic.Pointer[offsetCommitter]
 }
 
 // Consumer represents a Sarama consumer group consumer
 type Consumer struct {
 	ready chan bool
 
-	ddlList              []*model.DDLEvent
+	// ddlList holds the DDLs wait to be handled, DDLs with the same CommitTs
+	// are grouped together, e.g. the DDLs split from a rename tables DDL job.
+	ddlList              [][]*ddlEntry
 	ddlListMu            sync.Mutex
 	ddlWithMaxCommitTs   *model.DDLEvent
 	ddlSink              ddlsink.Sink
@@ -466,14 +532,11 @@ func NewConsumer(ctx context.Context, o *consumerOption) (*Consumer, error) {
 	}
 	c.eventRouter = eventRouter
 
+	// The sinks of the partitions are initialized when they are assigned.
 	c.sinks = make([]*partitionSinks, o.partitionNum)
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error, 1)
 
-	for i := 0; i < int(o.partitionNum); i++ {
-		c.sinks[i] = &partitionSinks{}
-	}
-
 	changefeedID := model.DefaultChangeFeedID("kafka-consumer")
 	f, err := eventsinkfactory.New(ctx, changefeedID, o.downstreamURI, config.GetDefaultReplicaConfig(), errChan, nil)
 	if err != nil {
@@ -503,33 +566,56 @@ func NewConsumer(ctx context.Context, o *consumerOption) (*Consumer, error) {
 }
 
 // Setup is run at the beginning of a new session, before ConsumeClaim
-func (c *Consumer) Setup(sarama.ConsumerGroupSession) error {
+func (c *Consumer) Setup(session sarama.ConsumerGroupSession) error {
+	c.onPartitionAssigned(claimedPartitions(session))
 	// Mark the c as ready
 	close(c.ready)
 	return nil
 }
 
 // Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited
-func (c *Consumer) Cleanup(sarama.ConsumerGroupSession) error {
+func (c *Consumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	// All the claims of the session are revoked, some of them may be
+	// assigned to the consumer again by the next session.
+	if err := c.onPartitionRevoked(session.Context(), claimedPartitions(session)); err != nil {
+		return cerror.Trace(err)
+	}
+	// commit the marked but not committed offsets before the session ends.
+	session.Commit()
 	return nil
 }
 
 type eventsGroup struct {
 	events []*model.RowChangedEvent
+	// memoryBytes is the approximate bytes of the events in memory.
+	memoryBytes uint64
+	// maxMemoryBytes is the max bytes of the events kept in memory, the
+	// overflow events are spilled to a temp file, 0 means no limit.
+	maxMemoryBytes uint64
+
+	hasSpilled bool
+	spill      *spillFile
 }
 
-func newEventsGroup() *eventsGroup {
+func newEventsGroup(maxMemoryBytes uint64) *eventsGroup {
 	return &eventsGroup{
-		events: make([]*model.RowChangedEvent, 0),
+		events:         make([]*model.RowChangedEvent, 0),
+		maxMemoryBytes: maxMemoryBytes,
 	}
 }
 
 func (g *eventsGroup) Append(e *model.RowChangedEvent) {
 	g.events = append(g.events, e)
+	g.memoryBytes += uint64(e.ApproximateBytes())
+	if g.maxMemoryBytes > 0 && g.memoryBytes > g.maxMemoryBytes {
+		g.spillEvents()
+	}
 }
 
 func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
-	sort.Slice(g.events, func(i, j int) bool {
+	// The events are sorted stably, since they are merged with the spilled
+	// ones which keep the order of arrival.
+	sort.SliceStable(g.events, func(i, j int) bool {
 		return g.events[i].CommitTs < g.events[j].CommitTs
 	})
 
@@ -538,10 +624,67 @@ func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
 	})
 	result := g.events[:i]
 	g.events = g.events[i:]
+	for _, e := range result {
+		g.memoryBytes -= uint64(e.ApproximateBytes())
+	}
 
+	if !g.hasSpilled || g.spill.minCommitTs > resolveTs {
+		return result
+	}
+	result, spill, err := g.spill.merge(result, resolveTs)
+	if err != nil {
+		log.Panic("read the spilled events failed", zap.Error(err))
+	}
+	g.spill = spill
+	if spill.count == 0 {
+		spill.close()
+		g.spill = nil
+		g.hasSpilled = false
+	}
 	return result
 }
 
+// spillEvents writes the events in memory to the spill file. The spill file
+// is rewritten by merging if the events are not after the spilled ones.
+func (g *eventsGroup) spillEvents() {
+	sort.SliceStable(g.events, func(i, j int) bool {
+		return g.events[i].CommitTs < g.events[j].CommitTs
+	})
+	var err error
+	switch {
+	case g.spill == nil:
+		g.spill, err = newSpillFile(nil)
+		if err == nil {
+			err = g.spill.writeAll(g.events)
+		}
+	case g.events[0].CommitTs >= g.spill.maxCommitTs:
+		err = g.spill.writeAll(g.events)
+	default:
+		_, g.spill, err = g.spill.merge(g.events, 0)
+	}
+	if err != nil {
+		log.Panic("spill the events failed", zap.Error(err))
+	}
+	eventsGroupSpillCounter.Inc()
+	log.Info("the events of the table exceed the memory limit, spill them to disk",
+		zap.Int("events", len(g.events)),
+		zap.Uint64("memoryBytes", g.memoryBytes),
+		zap.Uint64("maxMemoryBytes", g.maxMemoryBytes),
+		zap.Int("spilledEvents", g.spill.count))
+	g.events = make([]*model.RowChangedEvent, 0)
+	g.memoryBytes = 0
+	g.hasSpilled = true
+}
+
+// close removes the spill file of the group.
+func (g *eventsGroup) close() {
+	if g.spill != nil {
+		g.spill.close()
+		g.spill = nil
+		g.hasSpilled = false
+	}
+}
+
 // ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages().
 func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	partition := claim.Partition()
@@ -574,6 +717,14 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 		decoder = avro.NewDecoder(c.option.codecConfig, schemaM, c.option.topic)
 	case config.ProtocolSimple:
 		decoder, err = simple.NewDecoder(ctx, c.option.codecConfig, c.upstreamTiDB)
+	case config.ProtocolKafkaStreams:
+		schema, table, err := kafkastreams.ParseTopic(claim.Topic())
+		if err != nil {
+			return cerror.Trace(err)
+		}
+		decoder = kafkastreams.NewDecoder(c.option.codecConfig, schema, table)
+	case config.ProtocolOCDF:
+		decoder = ocdf.NewDecoder()
 	default:
 		log.Panic("Protocol not supported", zap.Any("Protocol", c.option.protocol))
 	}
@@ -585,7 +736,16 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 		zap.String("topic", claim.Topic()), zap.Int32("partition", partition),
 		zap.Int64("initialOffset", claim.InitialOffset()), zap.Int64("highWaterMarkOffset", claim.HighWaterMarkOffset()))
 
+	committer := newOffsetCommitter(session,
+		c.option.offsetCommitStrategy, c.option.offsetCommitInterval, clock.New())
+	sink.committer.Store(committer)
 	eventGroups := make(map[int64]*eventsGroup)
+	defer func() {
+		sink.committer.CompareAndSwap(committer, nil)
+		for _, group := range eventGroups {
+			group.close()
+		}
+	}()
 	for message := range claim.Messages() {
 		if err = decoder.AddKeyValue(message.Key, message.Value); err != nil {
 			log.Error("add key value to the decoder failed", zap.Error(err))
@@ -593,6 +753,7 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 		}
 
 		counter := 0
+		hasResolved := false
 		for {
 			tp, hasNext, err := decoder.HasNext()
 			if err != nil {
@@ -638,7 +799,7 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 
 						group, ok := eventGroups[tableID]
 						if !ok {
-							group = newEventsGroup()
+							group = newEventsGroup(c.option.eventsGroupMaxMemoryBytes)
 							eventGroups[tableID] = group
 						}
 						group.Append(row)
@@ -649,8 +810,6 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 				if partition == 0 && ddl.Query != "" {
 					c.appendDDL(ddl)
 				}
-				// todo: mark the offset after the DDL is fully synced to the downstream mysql.
-				session.MarkMessage(message, "")
 			case model.MessageTypeRow:
 				row, err := decoder.NextRowChangedEvent()
 				if err != nil {
@@ -676,7 +835,7 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 					)
 				}
 
-				globalResolvedTs := atomic.LoadUint64(&c.globalResolvedTs)
+				globalResolvedTs := c.fallbackResolvedTs(sink)
 				partitionResolvedTs := atomic.LoadUint64(&sink.resolvedTs)
 				if row.CommitTs <= globalResolvedTs || row.CommitTs <= partitionResolvedTs {
 					log.Warn("RowChangedEvent fallback row, ignore it",
@@ -685,8 +844,6 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 						zap.Uint64("partitionResolvedTs", partitionResolvedTs),
 						zap.Int32("partition", partition),
 						zap.Any("row", row))
-					// todo: mark the offset after the DDL is fully synced to the downstream mysql.
-					session.MarkMessage(message, "")
 					continue
 				}
 				var partitionID int64
@@ -699,14 +856,13 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 
 				group, ok := eventGroups[tableID]
 				if !ok {
-					group = newEventsGroup()
+					group = newEventsGroup(c.option.eventsGroupMaxMemoryBytes)
 					eventGroups[tableID] = group
 				}
 
 				group.Append(row)
-				// todo: mark the offset after the DDL is fully synced to the downstream mysql.
-				session.MarkMessage(message, "")
 			case model.MessageTypeResolved:
+				hasResolved = true
 				ts, err := decoder.NextResolvedEvent()
 				if err != nil {
 					log.Panic("decode message value failed",
@@ -714,7 +870,7 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 						zap.Error(err))
 				}
 
-				globalResolvedTs := atomic.LoadUint64(&c.globalResolvedTs)
+				globalResolvedTs := c.fallbackResolvedTs(sink)
 				partitionResolvedTs := atomic.LoadUint64(&sink.resolvedTs)
 				if ts < globalResolvedTs || ts < partitionResolvedTs {
 					log.Warn("partition resolved ts fallback, skip it",
@@ -722,37 +878,31 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 						zap.Uint64("partitionResolvedTs", partitionResolvedTs),
 						zap.Uint64("globalResolvedTs", globalResolvedTs),
 						zap.Int32("partition", partition))
-					session.MarkMessage(message, "")
 					continue
 				}
 
-				for tableID, group := range eventGroups {
-					events := group.Resolve(ts)
-					if len(events) == 0 {
-						continue
-					}
-					if _, ok := sink.tableSinksMap.Load(tableID); !ok {
-						sink.tableSinksMap.Store(tableID, c.sinkFactory.CreateTableSinkForConsumer(
-							model.DefaultChangeFeedID("kafka-consumer"),
-							spanz.TableIDToComparableSpan(tableID),
-							events[0].CommitTs,
-						))
-					}
-					s, _ := sink.tableSinksMap.Load(tableID)
-					s.(tablesink.TableSink).AppendRowChangedEvents(events...)
-					commitTs := events[len(events)-1].CommitTs
-					lastCommitTs, ok := sink.tablesCommitTsMap.Load(tableID)
-					if !ok || lastCommitTs.(uint64) < commitTs {
-						sink.tablesCommitTsMap.Store(tableID, commitTs)
-					}
-				}
+				c.appendResolvedEvents(sink, eventGroups, ts)
 				atomic.StoreUint64(&sink.resolvedTs, ts)
-				// todo: mark the offset after the DDL is fully synced to the downstream mysql.
-				session.MarkMessage(message, "")
-
+			case model.MessageTypeHeartbeat:
+				ts, err := decoder.NextResolvedEvent()
+				if err != nil {
+					log.Panic("decode message value failed",
+						zap.ByteString("value", message.Value),
+						zap.Error(err))
+				}
+				if !onHeartbeat(sink, partition, ts, eventGroups) {
+					log.Debug("heartbeat does not advance the partition resolved ts",
+						zap.Uint64("ts", ts),
+						zap.Uint64("partitionResolvedTs", atomic.LoadUint64(&sink.resolvedTs)),
+						zap.Int32("partition", partition))
+				}
 			}
-
 		}
+		var resolvedTs uint64
+		if hasResolved {
+			resolvedTs = atomic.LoadUint64(&sink.resolvedTs)
+		}
+		committer.onMessageConsumed(message, resolvedTs)
 
 		if counter > c.option.maxBatchSize {
 			log.Panic("Open Protocol max-batch-size exceeded", zap.Int("max-batch-size", c.option.maxBatchSize),
@@ -760,9 +910,50 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 		}
 	}
 
+	// The claim ends since the partition is revoked. The unresolved events
+	// are not consumed again if their offsets have been committed, so they
+	// are appended to the table sinks to be flushed by onPartitionRevoked.
+	if c.option.offsetCommitStrategy != offsetCommitPerResolvedTs {
+		c.appendResolvedEvents(sink, eventGroups, math.MaxUint64)
+	}
 	return nil
 }
 
+// appendResolvedEvents appends the events with commitTs <= ts to the table
+// sinks of the partition.
+func (c *Consumer) appendResolvedEvents(
+	sink *partitionSinks, eventGroups map[int64]*eventsGroup, ts uint64,
+) {
+	for tableID, group := range eventGroups {
+		events := group.Resolve(ts)
+		if len(events) == 0 {
+			continue
+		}
+		if _, ok := sink.tableSinksMap.Load(tableID); !ok {
+			sink.tableSinksMap.Store(tableID, c.sinkFactory.CreateTableSinkForConsumer(
+				model.DefaultChangeFeedID("kafka-consumer"),
+				spanz.TableIDToComparableSpan(tableID),
+				events[0].CommitTs,
+			))
+		}
+		s, _ := sink.tableSinksMap.Load(tableID)
+		s.(tablesink.TableSink).AppendRowChangedEvents(events...)
+		commitTs := events[len(events)-1].CommitTs
+		lastCommitTs, ok := sink.tablesCommitTsMap.Load(tableID)
+		if !ok || lastCommitTs.(uint64) < commitTs {
+			sink.tablesCommitTsMap.Store(tableID, commitTs)
+		}
+	}
+}
+
+// ddlEntry is a DDL wait to be handled.
+type ddlEntry struct {
+	ddl *model.DDLEvent
+	// attemptedAt is the time when the DDL is written to the downstream,
+	// it's zero if the DDL has not been attempted yet.
+	attemptedAt time.Time
+}
+
 // append DDL wait to be handled, only consider the constraint among DDLs.
 // for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
 func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
@@ -779,19 +970,32 @@ func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
 
 	// A rename tables DDL job contains multiple DDL events with same CommitTs.
 	// So to tell if a DDL is redundant or not, we must check the equivalence of
-	// the current DDL and the DDL with max CommitTs.
-	if ddl == c.ddlWithMaxCommitTs {
-		log.Info("ignore redundant DDL, the DDL is equal to ddlWithMaxCommitTs",
-			zap.Uint64("commitTs", ddl.CommitTs), zap.String("DDL", ddl.Query))
-		return
+	// the current DDL and the DDLs with max CommitTs.
+	if c.ddlWithMaxCommitTs != nil && ddl.CommitTs == c.ddlWithMaxCommitTs.CommitTs {
+		// The DDLs with max CommitTs have been popped, which means all of them
+		// are handled, so the DDL must be a redelivered one.
+		if len(c.ddlList) == 0 {
+			log.Info("ignore redundant DDL, the DDLs with the same CommitTs are handled",
+				zap.Uint64("commitTs", ddl.CommitTs), zap.String("DDL", ddl.Query))
+			return
+		}
+		last := len(c.ddlList) - 1
+		for _, entry := range c.ddlList[last] {
+			if entry.ddl == ddl || entry.ddl.Query == ddl.Query {
+				log.Info("ignore redundant DDL, the DDL is equal to a DDL with max CommitTs",
+					zap.Uint64("commitTs", ddl.CommitTs), zap.String("DDL", ddl.Query))
+				return
+			}
+		}
+		c.ddlList[last] = append(c.ddlList[last], &ddlEntry{ddl: ddl})
+	} else {
+		c.ddlList = append(c.ddlList, []*ddlEntry{{ddl: ddl}})
 	}
-
-	c.ddlList = append(c.ddlList, ddl)
 	log.Info("DDL event received", zap.Uint64("commitTs", ddl.CommitTs), zap.String("DDL", ddl.Query))
 	c.ddlWithMaxCommitTs = ddl
 }
 
-func (c *Consumer) getFrontDDL() *model.DDLEvent {
+func (c *Consumer) getFrontDDLs() []*ddlEntry {
 	c.ddlListMu.Lock()
 	defer c.ddlListMu.Unlock()
 	if len(c.ddlList) > 0 {
@@ -800,21 +1004,102 @@ func (c *Consumer) getFrontDDL() *model.DDLEvent {
 	return nil
 }
 
-func (c *Consumer) popDDL() *model.DDLEvent {
+func (c *Consumer) popDDLs() []*ddlEntry {
 	c.ddlListMu.Lock()
 	defer c.ddlListMu.Unlock()
 	if len(c.ddlList) > 0 {
-		ddl := c.ddlList[0]
+		ddls := c.ddlList[0]
 		c.ddlList = c.ddlList[1:]
-		return ddl
+		return ddls
+	}
+	return nil
+}
+
+// writeDDLs writes the DDLs with the same CommitTs to the downstream. DDLs
+// that have been attempted are skipped, since they may be still running in the
+// downstream after the consumer stops waiting for them.
+func (c *Consumer) writeDDLs(ctx context.Context, entries []*ddlEntry) error {
+	ddls := make([]*model.DDLEvent, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.attemptedAt.IsZero() {
+			log.Warn("skip DDL which has been attempted",
+				zap.Uint64("commitTs", entry.ddl.CommitTs),
+				zap.String("DDL", entry.ddl.Query),
+				zap.Time("attemptedAt", entry.attemptedAt))
+			continue
+		}
+		entry.attemptedAt = time.Now()
+		ddls = append(ddls, entry.ddl)
+	}
+	for _, ddl := range mergeRenameTableDDLs(ddls) {
+		if err := c.writeDDL(ctx, ddl); err != nil {
+			return cerror.Trace(err)
+		}
 	}
 	return nil
 }
 
+// writeDDL writes the DDL to the downstream, it waits at most
+// ddlExecutionTimeout and then proceeds even if the DDL is not finished.
+func (c *Consumer) writeDDL(ctx context.Context, ddl *model.DDLEvent) error {
+	if c.option == nil || c.option.ddlExecutionTimeout <= 0 {
+		return c.ddlSink.WriteDDLEvent(ctx, ddl)
+	}
+	ddlCtx, cancel := context.WithTimeout(ctx, c.option.ddlExecutionTimeout)
+	defer cancel()
+	err := c.ddlSink.WriteDDLEvent(ddlCtx, ddl)
+	if err != nil && ctx.Err() == nil && errors.Is(ddlCtx.Err(), context.DeadlineExceeded) {
+		log.Warn("DDL execution timeout, proceed without waiting for it",
+			zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("DDL", ddl.Query),
+			zap.Duration("timeout", c.option.ddlExecutionTimeout),
+			zap.Error(err))
+		return nil
+	}
+	return err
+}
+
+// mergeRenameTableDDLs merges the DDLs split from a rename tables DDL job
+// into one `RENAME TABLE` statement, so that all tables are renamed
+// atomically on the downstream. DDL statements cause an implicit commit,
+// it's not enough to wrap multiple DDLs in one transaction.
+// It returns the DDLs as is if they can not be merged.
+func mergeRenameTableDDLs(ddls []*model.DDLEvent) []*model.DDLEvent {
+	if len(ddls) <= 1 {
+		return ddls
+	}
+	const renameTablePrefix = "RENAME TABLE "
+	renames := make([]string, 0, len(ddls))
+	for _, ddl := range ddls {
+		if ddl.Type != timodel.ActionRenameTable ||
+			!strings.HasPrefix(strings.ToUpper(ddl.Query), renameTablePrefix) {
+			return ddls
+		}
+		renames = append(renames, strings.TrimSpace(ddl.Query[len(renameTablePrefix):]))
+	}
+	first := ddls[0]
+	merged := &model.DDLEvent{
+		StartTs:   first.StartTs,
+		CommitTs:  first.CommitTs,
+		Query:     renameTablePrefix + strings.Join(renames, ", "),
+		TableInfo: first.TableInfo,
+		Type:      timodel.ActionRenameTables,
+		Charset:   first.Charset,
+		Collate:   first.Collate,
+		BDRRole:   first.BDRRole,
+		SQLMode:   first.SQLMode,
+	}
+	return []*model.DDLEvent{merged}
+}
+
 func (c *Consumer) forEachSink(fn func(sink *partitionSinks) error) error {
 	c.sinksMu.Lock()
 	defer c.sinksMu.Unlock()
 	for _, sink := range c.sinks {
+		// The partition is not assigned to the consumer.
+		if sink == nil {
+			continue
+		}
 		if err := fn(sink); err != nil {
 			return cerror.Trace(err)
 		}
@@ -826,11 +1111,24 @@ func (c *Consumer) getMinPartitionResolvedTs() (result uint64, err error) {
 	result = uint64(math.MaxUint64)
 	err = c.forEachSink(func(sink *partitionSinks) error {
 		a := atomic.LoadUint64(&sink.resolvedTs)
+		// The partition assigned by a rebalance holds the global resolved ts
+		// until it catches up.
+		if sink.catchingUp.Load() {
+			if a < c.globalResolvedTs {
+				a = c.globalResolvedTs
+			} else {
+				sink.catchingUp.Store(false)
+			}
+		}
 		if a < result {
 			result = a
 		}
 		return nil
 	})
+	// No partition is assigned to the consumer.
+	if result == math.MaxUint64 {
+		result = c.globalResolvedTs
+	}
 	return result, err
 }
 
@@ -851,20 +1149,22 @@ func (c *Consumer) Run(ctx context.Context) error {
 		}
 
 		// handle DDL
-		todoDDL := c.getFrontDDL()
-		if todoDDL != nil && todoDDL.CommitTs <= minPartitionResolvedTs {
+		ddlExecuted := false
+		todoDDLs := c.getFrontDDLs()
+		if len(todoDDLs) != 0 && todoDDLs[0].ddl.CommitTs <= minPartitionResolvedTs {
+			todoDDL := todoDDLs[0].ddl
 			// flush DMLs
 			if err := c.forEachSink(func(sink *partitionSinks) error {
-				return syncFlushRowChangedEvents(ctx, sink, todoDDL.CommitTs)
+				return flushWithRetry(ctx, sink, todoDDL.CommitTs)
 			}); err != nil {
 				return cerror.Trace(err)
 			}
 
-			// DDL can be executed, do it first.
-			if err := c.ddlSink.WriteDDLEvent(ctx, todoDDL); err != nil {
+			// DDLs can be executed, do it first.
+			if err := c.writeDDLs(ctx, todoDDLs); err != nil {
 				return cerror.Trace(err)
 			}
-			c.popDDL()
+			c.popDDLs()
 
 			if todoDDL.CommitTs < minPartitionResolvedTs {
 				log.Info("update minPartitionResolvedTs by DDL",
@@ -872,6 +1172,7 @@ func (c *Consumer) Run(ctx context.Context) error {
 					zap.String("DDL", todoDDL.Query))
 			}
 			minPartitionResolvedTs = todoDDL.CommitTs
+			ddlExecuted = true
 		}
 
 		// update global resolved ts
@@ -882,17 +1183,86 @@ func (c *Consumer) Run(ctx context.Context) error {
 		}
 
 		if c.globalResolvedTs < minPartitionResolvedTs {
+			// DMLs before the DDL have been flushed, so the global resolved ts
+			// always catches up with the DDL.
+			if !ddlExecuted && !c.shouldAdvanceGlobalResolvedTs(minPartitionResolvedTs) {
+				continue
+			}
 			c.globalResolvedTs = minPartitionResolvedTs
 		}
 
 		if err := c.forEachSink(func(sink *partitionSinks) error {
-			return syncFlushRowChangedEvents(ctx, sink, c.globalResolvedTs)
+			if err := flushWithRetry(ctx, sink, c.globalResolvedTs); err != nil {
+				return err
+			}
+			// The events before the global resolved ts are in the downstream,
+			// so the offsets of the resolved ts events can be committed.
+			if committer := sink.committer.Load(); committer != nil {
+				committer.onResolvedTsFlushed(c.globalResolvedTs)
+			}
+			return nil
 		}); err != nil {
 			return cerror.Trace(err)
 		}
 	}
 }
 
+// shouldAdvanceGlobalResolvedTs returns true if the global resolved ts lags
+// behind the resolvedTs more than minGlobalResolvedTsAdvance.
+func (c *Consumer) shouldAdvanceGlobalResolvedTs(resolvedTs uint64) bool {
+	interval := c.option.minGlobalResolvedTsAdvance
+	if interval <= 0 || c.globalResolvedTs == 0 {
+		return true
+	}
+	lag := oracle.GetTimeFromTS(resolvedTs).Sub(oracle.GetTimeFromTS(c.globalResolvedTs))
+	return lag > interval
+}
+
+// errTableSinkNotFound means the commit ts of a table is recorded but its
+// table sink is not created yet, it's usually transient.
+var errTableSinkNotFound = cerror.New("table sink not found")
+
+// missingTableSinkCounter counts how many times a table sink is not found
+// when flushing the events of a partition.
+var missingTableSinkCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "consumer",
+		Name:      "missing_table_sink_total",
+		Help:      "The total number of times a table sink is not found when flushing",
+	})
+
+func init() {
+	prometheus.MustRegister(missingTableSinkCounter)
+}
+
+var (
+	// sinkRetryTimeout is the max duration to retry the flush if a table sink
+	// is not found.
+	sinkRetryTimeout = time.Minute
+	// sinkRetryInterval is the interval between two retries of the flush.
+	sinkRetryInterval = 100 * time.Millisecond
+)
+
+// flushWithRetry flushes the events of the partition up to resolvedTs, it
+// retries if a table sink is not found until sinkRetryTimeout elapses.
+func flushWithRetry(ctx context.Context, sink *partitionSinks, resolvedTs uint64) error {
+	deadline := time.Now().Add(sinkRetryTimeout)
+	for {
+		err := syncFlushRowChangedEvents(ctx, sink, resolvedTs)
+		if cerror.Cause(err) != errTableSinkNotFound || time.Now().After(deadline) {
+			return err
+		}
+		log.Warn("table sink not found, retry the flush later",
+			zap.Uint64("resolvedTs", resolvedTs), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sinkRetryInterval):
+		}
+	}
+}
+
 func syncFlushRowChangedEvents(ctx context.Context, sink *partitionSinks, resolvedTs uint64) error {
 	for {
 		select {
@@ -900,13 +1270,16 @@ func syncFlushRowChangedEvents(ctx context.Context, sink *partitionSinks, resolv
 			return ctx.Err()
 		default:
 		}
+		var err error
 		flushedResolvedTs := true
 		sink.tablesCommitTsMap.Range(func(key, value interface{}) bool {
 			tableID := key.(int64)
 			resolvedTs := model.NewResolvedTs(resolvedTs)
 			tableSink, ok := sink.tableSinksMap.Load(tableID)
 			if !ok {
-				log.Panic("Table sink not found", zap.Int64("tableID", tableID))
+				missingTableSinkCounter.Inc()
+				err = cerror.Annotatef(errTableSinkNotFound, "tableID: %d", tableID)
+				return false
 			}
 			if err := tableSink.(tablesink.TableSink).UpdateResolvedTs(resolvedTs); err != nil {
 				log.Error("Failed to update resolved ts", zap.Error(err))
@@ -918,6 +1291,9 @@ func syncFlushRowChangedEvents(ctx context.Context, sink *partitionSinks, resolv
 			}
 			return true
 		})
+		if err != nil {
+			return err
+		}
 		if flushedResolvedTs {
 			return nil
 		}
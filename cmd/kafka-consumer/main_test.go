@@ -0,0 +1,343 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cerror "github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type mockDDLSink struct {
+	mu   sync.Mutex
+	ddls []*model.DDLEvent
+	// delay simulates a slow DDL execution.
+	delay time.Duration
+}
+
+func (s *mockDDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	s.mu.Lock()
+	s.ddls = append(s.ddls, ddl)
+	s.mu.Unlock()
+	if s.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.delay):
+		}
+	}
+	return nil
+}
+
+func (s *mockDDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	return nil
+}
+
+func (s *mockDDLSink) Close() {}
+
+func (s *mockDDLSink) getDDLs() []*model.DDLEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ddls
+}
+
+func newRenameTableDDL(commitTs uint64, oldName, newName string) *model.DDLEvent {
+	return &model.DDLEvent{
+		CommitTs: commitTs,
+		Query:    fmt.Sprintf("RENAME TABLE `test`.`%s` TO `test`.`%s`", oldName, newName),
+		Type:     timodel.ActionRenameTable,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: newName},
+		},
+	}
+}
+
+func TestAppendDDLGroupByCommitTs(t *testing.T) {
+	t.Parallel()
+
+	c := &Consumer{}
+	c.appendDDL(&model.DDLEvent{CommitTs: 1, Query: "create table t1 (a int primary key)"})
+	c.appendDDL(newRenameTableDDL(2, "t1", "t2"))
+	c.appendDDL(newRenameTableDDL(2, "t3", "t4"))
+	c.appendDDL(&model.DDLEvent{CommitTs: 3, Query: "drop table t2"})
+	require.Len(t, c.ddlList, 3)
+	require.Len(t, c.ddlList[0], 1)
+	require.Len(t, c.ddlList[1], 2)
+	require.Len(t, c.ddlList[2], 1)
+
+	require.Len(t, c.popDDLs(), 1)
+	ddls := c.getFrontDDLs()
+	require.Len(t, ddls, 2)
+	require.Equal(t, uint64(2), ddls[0].ddl.CommitTs)
+
+	// Redelivered DDLs are ignored.
+	c.appendDDL(newRenameTableDDL(2, "t1", "t2"))
+	require.Len(t, c.getFrontDDLs(), 2)
+	c.appendDDL(&model.DDLEvent{CommitTs: 3, Query: "drop table t2"})
+	require.Len(t, c.ddlList, 2)
+	c.popDDLs()
+	c.popDDLs()
+	c.appendDDL(&model.DDLEvent{CommitTs: 3, Query: "drop table t2"})
+	require.Nil(t, c.getFrontDDLs())
+}
+
+func TestMergeRenameTableDDLs(t *testing.T) {
+	t.Parallel()
+
+	// Not rename table DDLs, do not merge.
+	ddls := []*model.DDLEvent{
+		{CommitTs: 1, Query: "create table t1 (a int primary key)", Type: timodel.ActionCreateTable},
+		{CommitTs: 1, Query: "create table t2 (a int primary key)", Type: timodel.ActionCreateTable},
+	}
+	require.Equal(t, ddls, mergeRenameTableDDLs(ddls))
+
+	ddls = []*model.DDLEvent{newRenameTableDDL(1, "t1", "t2")}
+	require.Equal(t, ddls, mergeRenameTableDDLs(ddls))
+
+	ddls = []*model.DDLEvent{
+		newRenameTableDDL(1, "t1", "t2"),
+		newRenameTableDDL(1, "t3", "t4"),
+	}
+	merged := mergeRenameTableDDLs(ddls)
+	require.Len(t, merged, 1)
+	require.Equal(t, timodel.ActionRenameTables, merged[0].Type)
+	require.Equal(t, uint64(1), merged[0].CommitTs)
+	require.Equal(t,
+		"RENAME TABLE `test`.`t1` TO `test`.`t2`, `test`.`t3` TO `test`.`t4`",
+		merged[0].Query)
+}
+
+func TestRenameTablesAtomically(t *testing.T) {
+	t.Parallel()
+
+	ddlSink := &mockDDLSink{}
+	c := &Consumer{
+		ddlSink: ddlSink,
+		sinks:   []*partitionSinks{{resolvedTs: 100}},
+	}
+	const tableCount = 10
+	for i := 0; i < tableCount; i++ {
+		c.appendDDL(newRenameTableDDL(
+			50, fmt.Sprintf("t%d", i), fmt.Sprintf("t%d_new", i)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return len(ddlSink.getDDLs()) != 0 && c.getFrontDDLs() == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+
+	// All tables are renamed by one statement on the downstream,
+	// so that no partial rename is visible.
+	ddls := ddlSink.getDDLs()
+	require.Len(t, ddls, 1)
+	stmts, _, err := parser.New().Parse(ddls[0].Query, "", "")
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	renameStmt, ok := stmts[0].(*ast.RenameTableStmt)
+	require.True(t, ok)
+	require.Len(t, renameStmt.TableToTables, tableCount)
+	for i, tt := range renameStmt.TableToTables {
+		require.Equal(t, fmt.Sprintf("t%d", i), tt.OldTable.Name.O)
+		require.Equal(t, fmt.Sprintf("t%d_new", i), tt.NewTable.Name.O)
+	}
+}
+
+func TestSlowDDLExecution(t *testing.T) {
+	t.Parallel()
+
+	ddlSink := &mockDDLSink{delay: time.Minute}
+	sink := &partitionSinks{resolvedTs: 100}
+	c := &Consumer{
+		ddlSink: ddlSink,
+		sinks:   []*partitionSinks{sink},
+		option:  &consumerOption{ddlExecutionTimeout: 100 * time.Millisecond},
+	}
+	ddl := &model.DDLEvent{CommitTs: 50, Query: "alter table t1 add column b int"}
+	c.appendDDL(ddl)
+
+	// The DDL is attempted only once.
+	entries := c.getFrontDDLs()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, c.writeDDLs(ctx, entries))
+	require.False(t, entries[0].attemptedAt.IsZero())
+	require.NoError(t, c.writeDDLs(ctx, entries))
+	require.Len(t, ddlSink.getDDLs(), 1)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return c.getFrontDDLs() == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The same DDL is redelivered while the DDL is still running.
+	c.appendDDL(&model.DDLEvent{CommitTs: 50, Query: "alter table t1 add column b int"})
+	require.Nil(t, c.getFrontDDLs())
+	atomic.StoreUint64(&sink.resolvedTs, 200)
+	require.Never(t, func() bool {
+		return len(ddlSink.getDDLs()) > 1
+	}, 500*time.Millisecond, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.Len(t, ddlSink.getDDLs(), 1)
+}
+
+// mockTableSink flushes the events up to the resolved ts immediately.
+type mockTableSink struct {
+	mu         sync.Mutex
+	resolvedTs model.ResolvedTs
+}
+
+func (s *mockTableSink) AppendRowChangedEvents(_ ...*model.RowChangedEvent) {}
+
+func (s *mockTableSink) UpdateResolvedTs(resolvedTs model.ResolvedTs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolvedTs = resolvedTs
+	return nil
+}
+
+func (s *mockTableSink) GetCheckpointTs() model.ResolvedTs {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolvedTs
+}
+
+func (s *mockTableSink) GetLastSyncedTs() model.Ts { return 0 }
+func (s *mockTableSink) Close()                    {}
+func (s *mockTableSink) AsyncClose() bool          { return true }
+func (s *mockTableSink) CheckHealth() error        { return nil }
+
+func TestFlushWithMissingTableSink(t *testing.T) {
+	oldTimeout, oldInterval := sinkRetryTimeout, sinkRetryInterval
+	defer func() {
+		sinkRetryTimeout, sinkRetryInterval = oldTimeout, oldInterval
+	}()
+	sinkRetryTimeout = 5 * time.Second
+	sinkRetryInterval = 10 * time.Millisecond
+
+	sink := &partitionSinks{}
+	tableSink := &mockTableSink{}
+	sink.tableSinksMap.Store(int64(1), tableSink)
+	sink.tablesCommitTsMap.Store(int64(1), uint64(100))
+	ctx := context.Background()
+	require.NoError(t, flushWithRetry(ctx, sink, 100))
+
+	// The table sink is removed during the flush, it's retried until the
+	// table sink comes back.
+	before := testutil.ToFloat64(missingTableSinkCounter)
+	sink.tableSinksMap.Delete(int64(1))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		sink.tableSinksMap.Store(int64(1), tableSink)
+	}()
+	require.NoError(t, flushWithRetry(ctx, sink, 200))
+	require.Greater(t, testutil.ToFloat64(missingTableSinkCounter), before)
+	require.Equal(t, uint64(200), tableSink.GetCheckpointTs().Ts)
+
+	// The error is returned after the timeout.
+	sinkRetryTimeout = 100 * time.Millisecond
+	sink.tableSinksMap.Delete(int64(1))
+	err := flushWithRetry(ctx, sink, 300)
+	require.Equal(t, errTableSinkNotFound, cerror.Cause(err))
+}
+
+func TestShouldAdvanceGlobalResolvedTs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	c := &Consumer{option: &consumerOption{}}
+	c.globalResolvedTs = oracle.GoTimeToTS(now)
+	// The global resolved ts is advanced as soon as possible by default.
+	require.True(t, c.shouldAdvanceGlobalResolvedTs(oracle.GoTimeToTS(now.Add(time.Millisecond))))
+
+	c.option.minGlobalResolvedTsAdvance = 500 * time.Millisecond
+	require.False(t, c.shouldAdvanceGlobalResolvedTs(oracle.GoTimeToTS(now.Add(100*time.Millisecond))))
+	require.False(t, c.shouldAdvanceGlobalResolvedTs(oracle.GoTimeToTS(now.Add(500*time.Millisecond))))
+	require.True(t, c.shouldAdvanceGlobalResolvedTs(oracle.GoTimeToTS(now.Add(501*time.Millisecond))))
+
+	// The first resolved ts is always accepted.
+	c.globalResolvedTs = 0
+	require.True(t, c.shouldAdvanceGlobalResolvedTs(oracle.GoTimeToTS(now)))
+}
+
+// BenchmarkGlobalResolvedTsAdvance simulates consuming 1K events/s for 10s,
+// and reports the count of downstream transactions and the average latency
+// of events with different min global resolved ts advance intervals.
+func BenchmarkGlobalResolvedTsAdvance(b *testing.B) {
+	const (
+		eventsPerSecond = 1000
+		duration        = 10 * time.Second
+		tick            = 100 * time.Millisecond
+	)
+	eventsPerTick := int(eventsPerSecond * tick / time.Second)
+	start := time.Now()
+	for _, interval := range []time.Duration{
+		0, 100 * time.Millisecond, 500 * time.Millisecond, 1000 * time.Millisecond,
+	} {
+		interval := interval
+		b.Run(interval.String(), func(b *testing.B) {
+			var (
+				txns, events int
+				latency      time.Duration
+			)
+			for i := 0; i < b.N; i++ {
+				c := &Consumer{option: &consumerOption{minGlobalResolvedTsAdvance: interval}}
+				txns, events, latency = 0, 0, 0
+				pending := make([]time.Time, 0, eventsPerSecond)
+				for now := start.Add(tick); now.Sub(start) <= duration; now = now.Add(tick) {
+					// The events committed during the last tick.
+					for j := 0; j < eventsPerTick; j++ {
+						pending = append(pending, now.Add(-tick+time.Duration(j)*time.Second/eventsPerSecond))
+					}
+					resolvedTs := oracle.GoTimeToTS(now)
+					if !c.shouldAdvanceGlobalResolvedTs(resolvedTs) {
+						continue
+					}
+					c.globalResolvedTs = resolvedTs
+					// All pending events are flushed in one transaction.
+					txns++
+					events += len(pending)
+					for _, commitAt := range pending {
+						latency += now.Sub(commitAt)
+					}
+					pending = pending[:0]
+				}
+			}
+			b.ReportMetric(float64(txns), "txns")
+			b.ReportMetric(float64(latency)/float64(time.Millisecond)/float64(events), "latency-ms/event")
+		})
+	}
+}
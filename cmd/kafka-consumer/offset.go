@@ -0,0 +1,140 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/benbjohnson/clock"
+	cerror "github.com/pingcap/errors"
+)
+
+type offsetCommitStrategy string
+
+const (
+	// offsetCommitPerMessage commits the offset after each message is consumed.
+	offsetCommitPerMessage offsetCommitStrategy = "perMessage"
+	// offsetCommitPerResolvedTs commits the offset of a message which contains
+	// a resolved ts event only after the events before the resolved ts are
+	// flushed to the downstream, so that the committed offset always matches
+	// the changefeed progress. Messages after the last committed offset are
+	// re-delivered if the consumer crashes.
+	offsetCommitPerResolvedTs offsetCommitStrategy = "perResolvedTs"
+	// offsetCommitPeriodic marks each consumed message and commits the marked
+	// offset at most once per interval.
+	offsetCommitPeriodic offsetCommitStrategy = "periodic"
+
+	defaultOffsetCommitInterval = 5 * time.Second
+)
+
+func parseOffsetCommitStrategy(s string) (offsetCommitStrategy, error) {
+	switch strategy := offsetCommitStrategy(s); strategy {
+	case offsetCommitPerMessage, offsetCommitPerResolvedTs, offsetCommitPeriodic:
+		return strategy, nil
+	default:
+		return "", cerror.Errorf("invalid offset commit strategy %s, "+
+			"should be one of perMessage, perResolvedTs and periodic", s)
+	}
+}
+
+// pendingOffset is a consumed message which contains a resolved ts event,
+// its offset is committed after the resolved ts is flushed.
+type pendingOffset struct {
+	message    *sarama.ConsumerMessage
+	resolvedTs uint64
+}
+
+// offsetCommitter marks and commits the offset of consumed messages of a
+// partition to the kafka consumer group according to the offset commit
+// strategy. The auto commit of sarama is disabled, offsets are committed
+// only by it.
+type offsetCommitter struct {
+	strategy offsetCommitStrategy
+	interval time.Duration
+	clock    clock.Clock
+	session  sarama.ConsumerGroupSession
+
+	mu         sync.Mutex
+	lastCommit time.Time
+	// pending is ordered by the offset, and so is the resolved ts.
+	pending []pendingOffset
+}
+
+func newOffsetCommitter(
+	session sarama.ConsumerGroupSession,
+	strategy offsetCommitStrategy,
+	interval time.Duration,
+	clock clock.Clock,
+) *offsetCommitter {
+	return &offsetCommitter{
+		strategy:   strategy,
+		interval:   interval,
+		clock:      clock,
+		session:    session,
+		lastCommit: clock.Now(),
+	}
+}
+
+// onMessageConsumed is called after all events in the message are consumed,
+// resolvedTs is the partition resolved ts if the message contains any
+// resolved ts event, otherwise it's 0.
+func (c *offsetCommitter) onMessageConsumed(message *sarama.ConsumerMessage, resolvedTs uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.strategy {
+	case offsetCommitPerResolvedTs:
+		if resolvedTs == 0 {
+			return
+		}
+		c.pending = append(c.pending, pendingOffset{message: message, resolvedTs: resolvedTs})
+	case offsetCommitPeriodic:
+		c.session.MarkMessage(message, "")
+		if c.clock.Since(c.lastCommit) >= c.interval {
+			c.commit()
+		}
+	default:
+		c.session.MarkMessage(message, "")
+		c.commit()
+	}
+}
+
+// onResolvedTsFlushed is called after the events of the partition with
+// commitTs <= resolvedTs are flushed to the downstream. It commits the
+// offsets of the pending messages covered by the flush.
+func (c *offsetCommitter) onResolvedTsFlushed(resolvedTs uint64) {
+	if c.strategy != offsetCommitPerResolvedTs {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := 0
+	for i < len(c.pending) && c.pending[i].resolvedTs <= resolvedTs {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+	c.session.MarkMessage(c.pending[i-1].message, "")
+	c.commit()
+	c.pending = c.pending[i:]
+}
+
+func (c *offsetCommitter) commit() {
+	c.session.Commit()
+	c.lastCommit = c.clock.Now()
+}
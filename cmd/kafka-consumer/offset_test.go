@@ -0,0 +1,164 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSession simulates the offset management of a consumer group session,
+// the marked offset is lost if it's not committed before the crash.
+type mockSession struct {
+	marked    int64
+	committed int64
+	commits   int
+}
+
+func newMockSession() *mockSession {
+	return &mockSession{marked: sarama.OffsetOldest, committed: sarama.OffsetOldest}
+}
+
+func (s *mockSession) Claims() map[string][]int32 { return nil }
+
+func (s *mockSession) MemberID() string { return "" }
+
+func (s *mockSession) GenerationID() int32 { return 0 }
+
+func (s *mockSession) MarkOffset(_ string, _ int32, offset int64, _ string) {
+	if offset > s.marked {
+		s.marked = offset
+	}
+}
+
+func (s *mockSession) Commit() {
+	s.committed = s.marked
+	s.commits++
+}
+
+func (s *mockSession) ResetOffset(_ string, _ int32, offset int64, _ string) {
+	s.marked = offset
+}
+
+func (s *mockSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	// the next offset to consume is marked, same as sarama.
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (s *mockSession) Context() context.Context { return context.Background() }
+
+func TestParseOffsetCommitStrategy(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"perMessage", "perResolvedTs", "periodic"} {
+		strategy, err := parseOffsetCommitStrategy(s)
+		require.NoError(t, err)
+		require.Equal(t, offsetCommitStrategy(s), strategy)
+	}
+	_, err := parseOffsetCommitStrategy("unknown")
+	require.Error(t, err)
+}
+
+func TestOffsetCommitRedeliveryAfterCrash(t *testing.T) {
+	t.Parallel()
+
+	// The partition contains rows at offset 0, 1, a resolved ts 10 at offset 2,
+	// rows at offset 3, 4 and a resolved ts 20 at offset 5. The resolved ts 10
+	// is flushed after offset 3 is consumed, and the consumer crashes after
+	// consuming offset 4, before the second resolved ts arrives.
+	resolved := map[int64]uint64{2: 10, 5: 20}
+	const (
+		flushAfter = 3
+		crashAfter = 4
+	)
+
+	cases := []struct {
+		strategy offsetCommitStrategy
+		// advance the clock before consuming the message at the offset.
+		tickAt int64
+		// the offset to resume consuming from after the crash.
+		expectedResume  int64
+		expectedCommits int
+	}{
+		{strategy: offsetCommitPerMessage, tickAt: -1, expectedResume: 5, expectedCommits: 5},
+		// rows at offset 3 and 4 are not resolved yet, they are re-delivered.
+		{strategy: offsetCommitPerResolvedTs, tickAt: -1, expectedResume: 3, expectedCommits: 1},
+		// only the offset marked when the interval elapsed is committed.
+		{strategy: offsetCommitPeriodic, tickAt: 1, expectedResume: 2, expectedCommits: 1},
+	}
+	for _, tc := range cases {
+		session := newMockSession()
+		mockClock := clock.NewMock()
+		committer := newOffsetCommitter(session, tc.strategy, time.Second, mockClock)
+		for offset := int64(0); offset <= crashAfter; offset++ {
+			if offset == tc.tickAt {
+				mockClock.Add(time.Second)
+			}
+			committer.onMessageConsumed(&sarama.ConsumerMessage{Offset: offset}, resolved[offset])
+			if offset == flushAfter {
+				committer.onResolvedTsFlushed(10)
+			}
+		}
+		// crash here, all marked but not committed offsets are lost.
+		require.Equal(t, tc.expectedResume, session.committed, tc.strategy)
+		require.Equal(t, tc.expectedCommits, session.commits, tc.strategy)
+
+		// resume from the committed offset, the re-delivered messages are
+		// consumed again until the next resolved ts is flushed.
+		session.marked = session.committed
+		committer = newOffsetCommitter(session, tc.strategy, time.Second, mockClock)
+		for offset := session.committed; offset <= 5; offset++ {
+			committer.onMessageConsumed(&sarama.ConsumerMessage{Offset: offset}, resolved[offset])
+		}
+		committer.onResolvedTsFlushed(20)
+		if tc.strategy != offsetCommitPeriodic {
+			require.Equal(t, int64(6), session.committed, tc.strategy)
+		}
+	}
+}
+
+func TestOffsetCommitPerResolvedTs(t *testing.T) {
+	t.Parallel()
+
+	// The partition contains rows at offset 0, 1, a resolved ts 10 at offset 2,
+	// rows at offset 3, 4 and a resolved ts 20 at offset 5.
+	resolved := map[int64]uint64{2: 10, 5: 20}
+	session := newMockSession()
+	committer := newOffsetCommitter(session, offsetCommitPerResolvedTs, time.Second, clock.NewMock())
+	for offset := int64(0); offset <= 5; offset++ {
+		committer.onMessageConsumed(&sarama.ConsumerMessage{Offset: offset}, resolved[offset])
+	}
+	// Nothing is committed before the resolved ts is flushed.
+	require.Equal(t, sarama.OffsetOldest, session.committed)
+	committer.onResolvedTsFlushed(5)
+	require.Equal(t, sarama.OffsetOldest, session.committed)
+	require.Equal(t, 0, session.commits)
+
+	committer.onResolvedTsFlushed(15)
+	require.Equal(t, int64(3), session.committed)
+	require.Equal(t, 1, session.commits)
+
+	committer.onResolvedTsFlushed(20)
+	require.Equal(t, int64(6), session.committed)
+	require.Equal(t, 2, session.commits)
+
+	// Flushing the same resolved ts again commits nothing.
+	committer.onResolvedTsFlushed(20)
+	require.Equal(t, 2, session.commits)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+
+	"github.com/IBM/sarama"
+	cerror "github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink"
+	"go.uber.org/zap"
+)
+
+// claimedPartitions returns the partitions claimed by the session of all
+// the topics in ascending order.
+func claimedPartitions(session sarama.ConsumerGroupSession) []int32 {
+	var partitions []int32
+	for _, claimed := range session.Claims() {
+		partitions = append(partitions, claimed...)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	return partitions
+}
+
+// onPartitionAssigned initializes fresh partitionSinks for the partitions
+// assigned to the consumer by a rebalance. The messages of them are consumed
+// from the committed offsets, which may be behind the global resolved ts, so
+// the partitions hold the global resolved ts until they catch up with it.
+// The fakeTableIDGenerator is kept, so the tables get the same IDs as before.
+func (c *Consumer) onPartitionAssigned(partitions []int32) {
+	c.sinksMu.Lock()
+	defer c.sinksMu.Unlock()
+	for _, partition := range partitions {
+		sink := &partitionSinks{}
+		sink.catchingUp.Store(true)
+		c.sinks[partition] = sink
+	}
+	log.Info("partitions assigned", zap.Int32s("partitions", partitions))
+}
+
+// fallbackResolvedTs returns the global resolved ts, the events of the
+// partition committed before it are regarded as fallback. It's 0 if the
+// partition is catching up, since its events before the global resolved ts
+// may not have been flushed by the consumer which owned it.
+func (c *Consumer) fallbackResolvedTs(sink *partitionSinks) uint64 {
+	if sink.catchingUp.Load() {
+		return 0
+	}
+	return atomic.LoadUint64(&c.globalResolvedTs)
+}
+
+// onPartitionRevoked flushes all the pending events of the partitions
+// revoked by a rebalance to the downstream, and releases their
+// partitionSinks. It must be called after all the ConsumeClaim of the
+// partitions have exited and before the offsets are committed.
+func (c *Consumer) onPartitionRevoked(ctx context.Context, partitions []int32) error {
+	// Detach the sinks first, so that Run doesn't flush them concurrently.
+	c.sinksMu.Lock()
+	revoked := make([]*partitionSinks, 0, len(partitions))
+	for _, partition := range partitions {
+		if sink := c.sinks[partition]; sink != nil {
+			revoked = append(revoked, sink)
+			c.sinks[partition] = nil
+		}
+	}
+	c.sinksMu.Unlock()
+
+	for _, sink := range revoked {
+		if err := syncFlushRowChangedEvents(ctx, sink, sink.maxPendingCommitTs()); err != nil {
+			return cerror.Trace(err)
+		}
+		sink.tableSinksMap.Range(func(_, value interface{}) bool {
+			value.(tablesink.TableSink).Close()
+			return true
+		})
+	}
+	log.Info("partitions revoked", zap.Int32s("partitions", partitions))
+	return nil
+}
+
+// maxPendingCommitTs returns the max commit ts of the events appended to the
+// table sinks of the partition, which are all flushed if the table sinks are
+// resolved by it.
+func (s *partitionSinks) maxPendingCommitTs() uint64 {
+	result := atomic.LoadUint64(&s.resolvedTs)
+	s.tablesCommitTsMap.Range(func(_, value interface{}) bool {
+		if commitTs := value.(uint64); commitTs > result {
+			result = commitTs
+		}
+		return true
+	})
+	return result
+}
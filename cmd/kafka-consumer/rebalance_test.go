@@ -0,0 +1,71 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionReassignment(t *testing.T) {
+	t.Parallel()
+
+	c := &Consumer{
+		sinks:            make([]*partitionSinks, 3),
+		globalResolvedTs: 100,
+	}
+	c.onPartitionAssigned([]int32{0, 1})
+	require.Nil(t, c.sinks[2])
+	c.sinks[0].resolvedTs = 120
+	c.sinks[1].resolvedTs = 50
+
+	// The partition 1 is catching up, it holds the global resolved ts.
+	require.Equal(t, uint64(0), c.fallbackResolvedTs(c.sinks[1]))
+	ts, err := c.getMinPartitionResolvedTs()
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), ts)
+	require.True(t, c.sinks[1].catchingUp.Load())
+	require.False(t, c.sinks[0].catchingUp.Load())
+	require.Equal(t, uint64(100), c.fallbackResolvedTs(c.sinks[0]))
+
+	c.sinks[1].resolvedTs = 110
+	ts, err = c.getMinPartitionResolvedTs()
+	require.NoError(t, err)
+	require.Equal(t, uint64(110), ts)
+	require.False(t, c.sinks[1].catchingUp.Load())
+
+	// The revoked partitions are skipped.
+	require.NoError(t, c.onPartitionRevoked(context.Background(), []int32{1}))
+	require.Nil(t, c.sinks[1])
+	ts, err = c.getMinPartitionResolvedTs()
+	require.NoError(t, err)
+	require.Equal(t, uint64(120), ts)
+
+	require.NoError(t, c.onPartitionRevoked(context.Background(), []int32{0}))
+	ts, err = c.getMinPartitionResolvedTs()
+	require.NoError(t, err)
+	require.Equal(t, c.globalResolvedTs, ts)
+}
+
+func TestMaxPendingCommitTs(t *testing.T) {
+	t.Parallel()
+
+	sink := &partitionSinks{resolvedTs: 100}
+	require.Equal(t, uint64(100), sink.maxPendingCommitTs())
+	sink.tablesCommitTsMap.Store(int64(1), uint64(150))
+	sink.tablesCommitTsMap.Store(int64(2), uint64(120))
+	require.Equal(t, uint64(150), sink.maxPendingCommitTs())
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"os"
+
+	cerror "github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/integrity"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// eventsGroupSpillCounter counts how many times the events of a table are
+// spilled to disk since they exceed the memory limit of the eventsGroup.
+var eventsGroupSpillCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "consumer",
+		Name:      "events_group_spill_total",
+		Help:      "The total number of times the buffered events of a table are spilled to disk",
+	})
+
+func init() {
+	prometheus.MustRegister(eventsGroupSpillCounter)
+}
+
+// spilledColumn is a ColumnData in the spill file, the nil columns are invalid.
+type spilledColumn struct {
+	Valid            bool
+	ColumnID         int64
+	Value            interface{}
+	ApproximateBytes int
+}
+
+// spilledEvent is a RowChangedEvent in the spill file. The table info is not
+// spilled, it is referenced by the index in the tableInfos of the spill file.
+type spilledEvent struct {
+	StartTs             uint64
+	CommitTs            uint64
+	RowID               int64
+	PhysicalTableID     int64
+	TableInfo           int
+	Columns             []spilledColumn
+	PreColumns          []spilledColumn
+	Checksum            *integrity.Checksum
+	ApproximateDataSize int64
+	SplitTxn            bool
+	ReplicatingTs       uint64
+	// HandleKey is the encoded common handle, IntHandle is the value of the
+	// int handle. gob omits the zero values, so whether the handle is an int
+	// handle is flagged by IsIntHandle, otherwise IntHandle(0) is lost.
+	HandleKey   []byte
+	IntHandle   int64
+	IsIntHandle bool
+}
+
+func toSpilledColumns(cols []*model.ColumnData) []spilledColumn {
+	if cols == nil {
+		return nil
+	}
+	result := make([]spilledColumn, len(cols))
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		result[i] = spilledColumn{
+			Valid:            true,
+			ColumnID:         col.ColumnID,
+			Value:            col.Value,
+			ApproximateBytes: col.ApproximateBytes,
+		}
+	}
+	return result
+}
+
+func fromSpilledColumns(cols []spilledColumn) []*model.ColumnData {
+	if cols == nil {
+		return nil
+	}
+	result := make([]*model.ColumnData, len(cols))
+	for i, col := range cols {
+		if !col.Valid {
+			continue
+		}
+		result[i] = &model.ColumnData{
+			ColumnID:         col.ColumnID,
+			Value:            col.Value,
+			ApproximateBytes: col.ApproximateBytes,
+		}
+	}
+	return result
+}
+
+// spillFile is a temp file holding the spilled events of an eventsGroup,
+// which are sorted by the commit ts.
+type spillFile struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *gob.Encoder
+
+	// tableInfos are the table infos of the spilled events, they are shared
+	// by many events so they are kept in memory.
+	tableInfos     []*model.TableInfo
+	tableInfoIndex map[*model.TableInfo]int
+
+	count       int
+	minCommitTs uint64
+	maxCommitTs uint64
+}
+
+func newSpillFile(tableInfos []*model.TableInfo) (*spillFile, error) {
+	file, err := os.CreateTemp("", "ticdc-kafka-consumer-*.spill")
+	if err != nil {
+		return nil, cerror.Trace(err)
+	}
+	writer := bufio.NewWriter(file)
+	f := &spillFile{
+		file:           file,
+		writer:         writer,
+		encoder:        gob.NewEncoder(writer),
+		tableInfos:     tableInfos,
+		tableInfoIndex: make(map[*model.TableInfo]int, len(tableInfos)),
+	}
+	for i, tableInfo := range tableInfos {
+		f.tableInfoIndex[tableInfo] = i
+	}
+	return f, nil
+}
+
+// write appends the event to the spill file, its commit ts must not be less
+// than the spilled events.
+func (f *spillFile) write(e *model.RowChangedEvent) error {
+	tableInfo := -1
+	if e.TableInfo != nil {
+		index, ok := f.tableInfoIndex[e.TableInfo]
+		if !ok {
+			index = len(f.tableInfos)
+			f.tableInfos = append(f.tableInfos, e.TableInfo)
+			f.tableInfoIndex[e.TableInfo] = index
+		}
+		tableInfo = index
+	}
+	spilled := &spilledEvent{
+		StartTs:             e.StartTs,
+		CommitTs:            e.CommitTs,
+		RowID:               e.RowID,
+		PhysicalTableID:     e.PhysicalTableID,
+		TableInfo:           tableInfo,
+		Columns:             toSpilledColumns(e.Columns),
+		PreColumns:          toSpilledColumns(e.PreColumns),
+		Checksum:            e.Checksum,
+		ApproximateDataSize: e.ApproximateDataSize,
+		SplitTxn:            e.SplitTxn,
+		ReplicatingTs:       e.ReplicatingTs,
+	}
+	if e.HandleKey != nil {
+		if e.HandleKey.IsInt() {
+			spilled.IntHandle = e.HandleKey.IntValue()
+			spilled.IsIntHandle = true
+		} else {
+			spilled.HandleKey = e.HandleKey.Encoded()
+		}
+	}
+	if err := f.encoder.Encode(spilled); err != nil {
+		return cerror.Annotatef(err, "spill the event failed, commitTs: %d", e.CommitTs)
+	}
+	if f.count == 0 {
+		f.minCommitTs = e.CommitTs
+	}
+	f.maxCommitTs = e.CommitTs
+	f.count++
+	return nil
+}
+
+func (f *spillFile) writeAll(events []*model.RowChangedEvent) error {
+	for _, e := range events {
+		if err := f.write(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *spillFile) read(decoder *gob.Decoder) (*model.RowChangedEvent, error) {
+	var spilled spilledEvent
+	if err := decoder.Decode(&spilled); err != nil {
+		return nil, cerror.Trace(err)
+	}
+	e := &model.RowChangedEvent{
+		StartTs:             spilled.StartTs,
+		CommitTs:            spilled.CommitTs,
+		RowID:               spilled.RowID,
+		PhysicalTableID:     spilled.PhysicalTableID,
+		Columns:             fromSpilledColumns(spilled.Columns),
+		PreColumns:          fromSpilledColumns(spilled.PreColumns),
+		Checksum:            spilled.Checksum,
+		ApproximateDataSize: spilled.ApproximateDataSize,
+		SplitTxn:            spilled.SplitTxn,
+		ReplicatingTs:       spilled.ReplicatingTs,
+	}
+	if spilled.TableInfo >= 0 {
+		e.TableInfo = f.tableInfos[spilled.TableInfo]
+	}
+	if spilled.IsIntHandle {
+		e.HandleKey = kv.IntHandle(spilled.IntHandle)
+	} else if spilled.HandleKey != nil {
+		handle, err := kv.NewCommonHandle(spilled.HandleKey)
+		if err != nil {
+			return nil, cerror.Trace(err)
+		}
+		e.HandleKey = handle
+	}
+	return e, nil
+}
+
+// merge merges the spilled events and the events sorted by the commit ts by
+// two pointers. The merged events with commit ts <= resolveTs are returned,
+// and the others are spilled to a new spill file, which replaces f.
+func (f *spillFile) merge(
+	events []*model.RowChangedEvent, resolveTs uint64,
+) ([]*model.RowChangedEvent, *spillFile, error) {
+	if err := f.writer.Flush(); err != nil {
+		return nil, nil, cerror.Trace(err)
+	}
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, cerror.Trace(err)
+	}
+	decoder := gob.NewDecoder(bufio.NewReader(f.file))
+	next, err := newSpillFile(f.tableInfos)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result []*model.RowChangedEvent
+	emit := func(e *model.RowChangedEvent) error {
+		if e.CommitTs <= resolveTs {
+			result = append(result, e)
+			return nil
+		}
+		return next.write(e)
+	}
+
+	var spilled *model.RowChangedEvent
+	read := 0
+	readNext := func() (err error) {
+		spilled = nil
+		if read < f.count {
+			spilled, err = f.read(decoder)
+			read++
+		}
+		return err
+	}
+	if err = readNext(); err != nil {
+		next.close()
+		return nil, nil, err
+	}
+	i := 0
+	for spilled != nil || i < len(events) {
+		// The spilled events are appended earlier, so they go first if the
+		// commit ts are the same.
+		if spilled != nil && (i == len(events) || spilled.CommitTs <= events[i].CommitTs) {
+			err = emit(spilled)
+			if err == nil {
+				err = readNext()
+			}
+		} else {
+			err = emit(events[i])
+			i++
+		}
+		if err != nil {
+			next.close()
+			return nil, nil, err
+		}
+	}
+	f.close()
+	return result, next, nil
+}
+
+// close closes and removes the spill file.
+func (f *spillFile) close() {
+	name := f.file.Name()
+	if err := f.file.Close(); err != nil {
+		log.Warn("close the spill file failed", zap.String("file", name), zap.Error(err))
+	}
+	if err := os.Remove(name); err != nil {
+		log.Warn("remove the spill file failed", zap.String("file", name), zap.Error(err))
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsGroupSpillLargeTxn(t *testing.T) {
+	const maxMemoryBytes = 8 * 1024 * 1024
+	tableInfo := &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t"}}
+	value := strings.Repeat("a", 1024)
+	spilledBefore := testutil.ToFloat64(eventsGroupSpillCounter)
+
+	// A 100MB transaction committed at 100.
+	group := newEventsGroup(maxMemoryBytes)
+	rows, totalBytes := 0, 0
+	for totalBytes < 100*1024*1024 {
+		row := &model.RowChangedEvent{
+			CommitTs:  100,
+			TableInfo: tableInfo,
+			HandleKey: kv.IntHandle(rows),
+			Columns: []*model.ColumnData{
+				{ColumnID: 1, Value: int64(rows)},
+				nil,
+				{ColumnID: 3, Value: value, ApproximateBytes: len(value)},
+			},
+		}
+		totalBytes += row.ApproximateBytes()
+		group.Append(row)
+		rows++
+	}
+	// The events of the next transactions arrive out of order.
+	group.Append(&model.RowChangedEvent{CommitTs: 300, TableInfo: tableInfo})
+	group.Append(&model.RowChangedEvent{CommitTs: 200, TableInfo: tableInfo})
+	group.Append(&model.RowChangedEvent{CommitTs: 50, TableInfo: tableInfo})
+
+	require.True(t, group.hasSpilled)
+	require.LessOrEqual(t, group.memoryBytes, uint64(maxMemoryBytes))
+	require.GreaterOrEqual(t, testutil.ToFloat64(eventsGroupSpillCounter)-spilledBefore, float64(12))
+	minCommitTs, ok := group.minCommitTs()
+	require.True(t, ok)
+	require.Equal(t, uint64(50), minCommitTs)
+
+	// The events are merged in the order of the commit ts and arrival.
+	events := group.Resolve(150)
+	require.Len(t, events, rows+1)
+	require.Equal(t, uint64(50), events[0].CommitTs)
+	for i, e := range events[1:] {
+		require.Equal(t, uint64(100), e.CommitTs)
+		require.Same(t, tableInfo, e.TableInfo)
+		require.Equal(t, kv.IntHandle(i), e.HandleKey)
+		require.Equal(t, int64(i), e.Columns[0].Value)
+		require.Nil(t, e.Columns[1])
+		require.Equal(t, value, e.Columns[2].Value)
+	}
+
+	events = group.Resolve(300)
+	require.Len(t, events, 2)
+	require.Equal(t, uint64(200), events[0].CommitTs)
+	require.Equal(t, uint64(300), events[1].CommitTs)
+	require.False(t, group.hasSpilled)
+	require.Nil(t, group.spill)
+	_, ok = group.minCommitTs()
+	require.False(t, ok)
+}
+
+func TestEventsGroupSpillMerge(t *testing.T) {
+	// Every event is spilled once it's appended.
+	group := newEventsGroup(1)
+	for _, commitTs := range []uint64{5, 3, 4, 3, 9, 1} {
+		group.Append(&model.RowChangedEvent{CommitTs: commitTs})
+	}
+	require.Empty(t, group.events)
+	require.Equal(t, 6, group.spill.count)
+
+	events := group.Resolve(4)
+	require.Len(t, events, 4)
+	for i, commitTs := range []uint64{1, 3, 3, 4} {
+		require.Equal(t, commitTs, events[i].CommitTs)
+	}
+	require.Equal(t, 2, group.spill.count)
+	require.Equal(t, uint64(5), group.spill.minCommitTs)
+
+	// The spill file is removed when the group is closed.
+	name := group.spill.file.Name()
+	group.close()
+	require.NoFileExists(t, name)
+}
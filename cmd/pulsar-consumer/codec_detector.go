@@ -0,0 +1,84 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// codecAutoDetectedCounter counts the messages whose codec is auto detected.
+var codecAutoDetectedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "consumer",
+		Name:      "codec_auto_detected_total",
+		Help:      "The number of messages whose codec is auto detected",
+	}, []string{"codec"})
+
+func init() {
+	prometheus.MustRegister(codecAutoDetectedCounter)
+}
+
+// codecPattern matches the key and the payload of the messages encoded by
+// the protocol.
+type codecPattern struct {
+	protocol config.Protocol
+	match    func(key, payload []byte) bool
+}
+
+// CodecAutoDetector detects the codec of a message by the magic bytes of it,
+// it's used when the messages encoded by different codecs are sent to the
+// same topic.
+type CodecAutoDetector struct {
+	patterns []codecPattern
+}
+
+// NewCodecAutoDetector creates a CodecAutoDetector with the patterns of all
+// the codecs supported by the consumer.
+func NewCodecAutoDetector() *CodecAutoDetector {
+	d := &CodecAutoDetector{}
+	// The key of an open protocol message starts with the 8 bytes batch version.
+	d.Register(config.ProtocolOpen, func(key, _ []byte) bool {
+		return len(key) >= 8 && binary.BigEndian.Uint64(key[:8]) == codec.BatchVersion1
+	})
+	// A canal-json message is a JSON object.
+	d.Register(config.ProtocolCanalJSON, func(_, payload []byte) bool {
+		payload = bytes.TrimLeft(payload, " \t\r\n")
+		return len(payload) > 0 && payload[0] == '{'
+	})
+	return d
+}
+
+// Register registers the pattern of the protocol, the patterns are matched in
+// the order of registration.
+func (d *CodecAutoDetector) Register(protocol config.Protocol, match func(key, payload []byte) bool) {
+	d.patterns = append(d.patterns, codecPattern{protocol: protocol, match: match})
+}
+
+// Detect returns the protocol of the first pattern matching the message,
+// false is returned if no pattern matches.
+func (d *CodecAutoDetector) Detect(key, payload []byte) (config.Protocol, bool) {
+	for _, p := range d.patterns {
+		if p.match(key, payload) {
+			codecAutoDetectedCounter.WithLabelValues(p.protocol.String()).Inc()
+			return p.protocol, true
+		}
+	}
+	return config.ProtocolDefault, false
+}
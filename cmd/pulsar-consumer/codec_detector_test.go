@@ -0,0 +1,121 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/open"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMessage is a pulsar message with only the topic, key and payload.
+type mockMessage struct {
+	pulsar.Message
+	topic   string
+	key     string
+	payload []byte
+}
+
+func (m *mockMessage) Topic() string   { return m.topic }
+func (m *mockMessage) Key() string     { return m.key }
+func (m *mockMessage) Payload() []byte { return m.payload }
+
+func TestCodecAutoDetector(t *testing.T) {
+	ctx := context.Background()
+	d := NewCodecAutoDetector()
+
+	builder, err := open.NewBatchEncoderBuilder(ctx, common.NewConfig(config.ProtocolOpen))
+	require.NoError(t, err)
+	watermark, err := builder.Build().EncodeCheckpointEvent(100)
+	require.NoError(t, err)
+	protocol, ok := d.Detect(watermark.Key, watermark.Value)
+	require.True(t, ok)
+	require.Equal(t, config.ProtocolOpen, protocol)
+
+	protocol, ok = d.Detect(nil, []byte(` {"id":0,"database":"test"}`))
+	require.True(t, ok)
+	require.Equal(t, config.ProtocolCanalJSON, protocol)
+
+	_, ok = d.Detect([]byte("key"), []byte{0x0, 0x1})
+	require.False(t, ok)
+	_, ok = d.Detect(nil, nil)
+	require.False(t, ok)
+}
+
+func TestHandleMixedCodecMessages(t *testing.T) {
+	ctx := context.Background()
+	// The commit ts of the canal-json rows is carried by the TiDB extension.
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	c := &Consumer{
+		option:               &ConsumerOption{topics: []string{"t1"}},
+		sinks:                []*partitionSinks{newPartitionSinks()},
+		topicPartitions:      assignVirtualPartitions([]string{"t1"}),
+		unknownTopics:        make(map[string]struct{}),
+		codecConfig:          codecConfig,
+		codecDetector:        NewCodecAutoDetector(),
+		fakeTableIDGenerator: &fakeTableIDGenerator{tableIDs: make(map[string]int64)},
+	}
+	tableSink := &mockTableSink{}
+	c.sinks[0].tableSinksMap.Store(int64(1), tableSink)
+
+	// The rows are encoded in canal-json.
+	columns := []*model.Column{{
+		Name: "id", Type: mysql.TypeLong,
+		Flag: model.PrimaryKeyFlag | model.HandleKeyFlag, Value: 1,
+	}}
+	tableInfo := model.BuildTableInfo("test", "t", columns, [][]int{{0}})
+	rowBuilder, err := canal.NewJSONRowEventEncoderBuilder(ctx, c.codecConfig)
+	require.NoError(t, err)
+	encoder := rowBuilder.Build()
+	for _, commitTs := range []uint64{10, 20} {
+		require.NoError(t, encoder.AppendRowChangedEvent(ctx, "t1", &model.RowChangedEvent{
+			CommitTs:  commitTs,
+			TableInfo: tableInfo,
+			Columns:   model.Columns2ColumnDatas(columns, tableInfo),
+		}, nil))
+	}
+	rows := encoder.Build()
+	require.Len(t, rows, 2)
+
+	// The watermarks are encoded in the open protocol.
+	watermarkBuilder, err := open.NewBatchEncoderBuilder(ctx, common.NewConfig(config.ProtocolOpen))
+	require.NoError(t, err)
+	watermark, err := watermarkBuilder.Build().EncodeCheckpointEvent(15)
+	require.NoError(t, err)
+
+	before := testutil.ToFloat64(codecAutoDetectedCounter.WithLabelValues(config.ProtocolOpen.String()))
+	for _, m := range []*common.Message{rows[0], rows[1], watermark} {
+		require.NoError(t, c.HandleMsg(&mockMessage{
+			topic: "t1", key: string(m.Key), payload: m.Value,
+		}))
+	}
+	require.Equal(t, before+1,
+		testutil.ToFloat64(codecAutoDetectedCounter.WithLabelValues(config.ProtocolOpen.String())))
+
+	// Only the row before the watermark is flushed to the table sink.
+	require.Equal(t, uint64(15), c.sinks[0].resolvedTs)
+	require.Len(t, tableSink.buffered, 1)
+	require.Equal(t, uint64(10), tableSink.buffered[0].CommitTs)
+	require.Len(t, c.sinks[0].eventGroups[1].events, 1)
+}
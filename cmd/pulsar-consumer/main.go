@@ -15,7 +15,9 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"net/url"
 	"os"
@@ -30,6 +32,7 @@ import (
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/apache/pulsar-client-go/pulsar/auth"
+	_ "github.com/go-sql-driver/mysql" // mysql driver
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -45,18 +48,24 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/ocdf"
+	"github.com/pingcap/tiflow/pkg/sink/codec/open"
 	tpulsar "github.com/pingcap/tiflow/pkg/sink/pulsar"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
 // ConsumerOption represents the options of the pulsar consumer
 type ConsumerOption struct {
 	address []string
-	topic   string
+	// topics are subscribed by the consumer, each topic is consumed by
+	// a virtual partition.
+	topics []string
 
 	protocol            config.Protocol
 	enableTiDBExtension bool
@@ -77,6 +86,31 @@ type ConsumerOption struct {
 
 	downstreamURI string
 	partitionNum  int
+
+	// replayFromTs is the TSO from which the events are replayed, all
+	// partitions are sought to the physical time of it.
+	replayFromTs uint64
+
+	// maxDMLBatchWaitMs is the maximum time in milliseconds the DMLs of a
+	// table are buffered without a resolved ts event, 0 means no limit.
+	maxDMLBatchWaitMs int
+
+	// upstreamTiDBDSN is the dsn of the upstream TiDB cluster, it's used to
+	// fetch the schema of the tables if the received events lack it.
+	upstreamTiDBDSN string
+
+	// codecAutoDetect enables detecting the codec of each message, the
+	// protocol is used if the codec of a message can not be detected.
+	codecAutoDetect bool
+
+	// verifyTables are the tables in the form of `schema.table`, which are
+	// verified against the stale read of the upstream TiDB.
+	verifyTables []string
+
+	// topicRule is the topic expression of the changefeed, such as
+	// `{schema}_{table}`, it's used to check the events are received from
+	// the topics they are dispatched to.
+	topicRule string
 }
 
 func newConsumerOption() *ConsumerOption {
@@ -87,12 +121,26 @@ func newConsumerOption() *ConsumerOption {
 
 // Adjust the consumer option by the upstream uri passed in parameters.
 func (o *ConsumerOption) Adjust(upstreamURI *url.URL, configFile string) {
-	// the default value of partitionNum is 1
-	o.partitionNum = 1
-
-	o.topic = strings.TrimFunc(upstreamURI.Path, func(r rune) bool {
-		return r == '/'
-	})
+	// topics passed by `--topics` take precedence over the topic in the upstream uri.
+	if len(o.topics) == 0 {
+		o.topics = []string{strings.TrimFunc(upstreamURI.Path, func(r rune) bool {
+			return r == '/'
+		})}
+	}
+	// Each topic must be consumed by exactly one virtual partition, otherwise
+	// the resolved ts of an idle partition blocks the global resolved ts.
+	topics := make([]string, 0, len(o.topics))
+	seen := make(map[string]struct{}, len(o.topics))
+	for _, topic := range o.topics {
+		if _, ok := seen[topicKey(topic)]; ok {
+			log.Warn("duplicated topic is ignored", zap.String("topic", topic))
+			continue
+		}
+		seen[topicKey(topic)] = struct{}{}
+		topics = append(topics, topic)
+	}
+	o.topics = topics
+	o.partitionNum = len(o.topics)
 
 	o.address = strings.Split(upstreamURI.Host, ",")
 
@@ -123,14 +171,47 @@ func (o *ConsumerOption) Adjust(upstreamURI *url.URL, configFile string) {
 		o.enableTiDBExtension = enableTiDBExtension
 	}
 
+	if o.replayFromTs != 0 {
+		currentTs := oracle.GoTimeToTS(time.Now())
+		if o.replayFromTs >= currentTs {
+			log.Panic("replay-from-ts must be less than the current TSO",
+				zap.Uint64("replayFromTs", o.replayFromTs),
+				zap.Uint64("currentTs", currentTs))
+		}
+		log.Warn("events between replay-from-ts and the current checkpoint "+
+			"will be re-delivered to the downstream",
+			zap.Uint64("replayFromTs", o.replayFromTs))
+	}
+
 	log.Info("consumer option adjusted",
 		zap.String("configFile", configFile),
 		zap.String("address", strings.Join(o.address, ",")),
-		zap.String("topic", o.topic),
+		zap.Strings("topics", o.topics),
 		zap.Any("protocol", o.protocol),
-		zap.Bool("enableTiDBExtension", o.enableTiDBExtension))
+		zap.Bool("enableTiDBExtension", o.enableTiDBExtension),
+		zap.Bool("codecAutoDetect", o.codecAutoDetect),
+		zap.Int("maxDMLBatchWaitMs", o.maxDMLBatchWaitMs),
+		zap.String("topicRule", o.topicRule))
 }
 
+// dmlBatchForcedFlushCounter counts the DML batches flushed by
+// `max-dml-batch-wait-ms` without a resolved ts event.
+var dmlBatchForcedFlushCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "consumer",
+		Name:      "dml_batch_forced_flush_total",
+		Help:      "The number of DML batches flushed without a resolved ts event",
+	})
+
+func init() {
+	prometheus.MustRegister(dmlBatchForcedFlushCounter)
+}
+
+// ErrSinkClosed is returned if the messages are handled after the consumer
+// is closed.
+var ErrSinkClosed = errors.New("the sinks of the pulsar consumer are closed")
+
 var (
 	upstreamURIStr string
 	configFile     string
@@ -145,6 +226,7 @@ func main() {
 	// Flags for the root command
 	cmd.Flags().StringVar(&configFile, "config", "", "config file for changefeed")
 	cmd.Flags().StringVar(&upstreamURIStr, "upstream-uri", "", "pulsar uri")
+	cmd.Flags().StringSliceVar(&consumerOption.topics, "topics", nil, "comma-separated topics to subscribe, the topic in upstream-uri is used if not set")
 	cmd.Flags().StringVar(&consumerOption.downstreamURI, "downstream-uri", "", "downstream sink uri")
 	cmd.Flags().StringVar(&consumerOption.timezone, "tz", "System", "Specify time zone of pulsar consumer")
 	cmd.Flags().StringVar(&consumerOption.ca, "ca", "", "CA certificate path for pulsar SSL connection")
@@ -159,6 +241,12 @@ func main() {
 	cmd.Flags().StringVar(&consumerOption.oauth2Audience, "oauth2-audience", "", "oauth2 audience")
 	cmd.Flags().StringVar(&consumerOption.mtlsAuthTLSCertificatePath, "auth-tls-certificate-path", "", "mtls certificate path")
 	cmd.Flags().StringVar(&consumerOption.mtlsAuthTLSPrivateKeyPath, "auth-tls-private-key-path", "", "mtls private key path")
+	cmd.Flags().Uint64Var(&consumerOption.replayFromTs, "replay-from-ts", 0, "replay events from the given TSO, events after it are re-delivered to the downstream")
+	cmd.Flags().StringVar(&consumerOption.upstreamTiDBDSN, "upstream-tidb-dsn", "", "upstream TiDB DSN, used to fetch the schema of the tables if the received events lack it")
+	cmd.Flags().BoolVar(&consumerOption.codecAutoDetect, "codec-auto-detect", false, "detect the codec of each message by its magic bytes, the protocol in upstream-uri is used if the detection fails")
+	cmd.Flags().IntVar(&consumerOption.maxDMLBatchWaitMs, "max-dml-batch-wait-ms", 0, "flush the DMLs of a table if they are buffered longer than it without a resolved ts event, 0 means no limit")
+	cmd.Flags().StringSliceVar(&consumerOption.verifyTables, "verify-table", nil, "comma-separated tables in the form of schema.table, which are periodically verified against the stale read of the upstream TiDB at the global resolved ts, upstream-tidb-dsn is required")
+	cmd.Flags().StringVar(&consumerOption.topicRule, "topic-rule", "", "topic expression of the changefeed such as {schema}_{table}, the events received from other topics are reported")
 	if err := cmd.Execute(); err != nil {
 		fmt.Println(err)
 	}
@@ -187,7 +275,7 @@ func run(cmd *cobra.Command, args []string) {
 	if !sink.IsPulsarScheme(scheme) {
 		log.Panic("invalid upstream-uri scheme, the scheme of upstream-uri must be pulsar schema",
 			zap.String("schema", scheme),
-			zap.String("upstreamURI", upstreamURIStr))
+			zap.String("upstreamURI", util.MustMaskSinkURI(upstreamURIStr)))
 	}
 
 	consumerOption.Adjust(upstreamURI, configFile)
@@ -197,6 +285,11 @@ func run(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Panic("Error creating pulsar consumer", zap.Error(err))
 	}
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			log.Warn("close the consumer failed", zap.Error(err))
+		}
+	}()
 
 	pulsarConsumer, client := NewPulsarConsumer(consumerOption)
 	defer client.Close()
@@ -259,7 +352,6 @@ func NewPulsarConsumer(option *ConsumerOption) (pulsar.Consumer, pulsar.Client)
 	} else {
 		pulsarURL = "pulsar" + "://" + option.address[0]
 	}
-	topicName := option.topic
 	subscriptionName := "pulsar-test-subscription"
 
 	clientOption := pulsar.ClientOptions{
@@ -300,7 +392,7 @@ func NewPulsarConsumer(option *ConsumerOption) (pulsar.Consumer, pulsar.Client)
 	}
 
 	consumerConfig := pulsar.ConsumerOptions{
-		Topic:                       topicName,
+		Topics:                      option.topics,
 		SubscriptionName:            subscriptionName,
 		Type:                        pulsar.Exclusive,
 		SubscriptionInitialPosition: pulsar.SubscriptionPositionEarliest,
@@ -310,6 +402,17 @@ func NewPulsarConsumer(option *ConsumerOption) (pulsar.Consumer, pulsar.Client)
 	if err != nil {
 		log.Fatal("can't create pulsar consumer", zap.Error(err))
 	}
+
+	if option.replayFromTs != 0 {
+		replayFrom := time.Unix(0, oracle.ExtractPhysical(option.replayFromTs)*int64(time.Millisecond))
+		if err := consumer.SeekByTime(replayFrom); err != nil {
+			log.Fatal("can't seek pulsar consumer to replay-from-ts",
+				zap.Uint64("replayFromTs", option.replayFromTs), zap.Error(err))
+		}
+		log.Info("pulsar consumer seeks to replay-from-ts",
+			zap.Uint64("replayFromTs", option.replayFromTs),
+			zap.Time("replayFrom", replayFrom))
+	}
 	return consumer, client
 }
 
@@ -319,11 +422,61 @@ type partitionSinks struct {
 	tableSinksMap     sync.Map
 	// resolvedTs record the maximum timestamp of the received event
 	resolvedTs uint64
+	// eventGroups buffers the unresolved events of each table received from
+	// the partition, they are resolved by the resolved ts of the partition.
+	eventGroups map[int64]*eventsGroup
+}
+
+func newPartitionSinks() *partitionSinks {
+	return &partitionSinks{eventGroups: make(map[int64]*eventsGroup)}
+}
+
+// topicKey returns the key of the pulsar topic used to locate its virtual
+// partition, the tenant, namespace and partition suffix of the topic are
+// trimmed, e.g. the key of `persistent://public/default/t-partition-1` is `t`.
+func topicKey(topic string) string {
+	topic = topic[strings.LastIndex(topic, "/")+1:]
+	if i := strings.LastIndex(topic, "-partition-"); i >= 0 {
+		if _, err := strconv.Atoi(topic[i+len("-partition-"):]); err == nil {
+			topic = topic[:i]
+		}
+	}
+	return topic
+}
+
+// assignVirtualPartitions maps each topic to a virtual partition by a stable
+// hash of the topic, conflicts are resolved by linear probing, so that
+// the same topics are always mapped to the same partitions.
+func assignVirtualPartitions(topics []string) map[string]int {
+	keys := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		keys = append(keys, topicKey(topic))
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]int, len(keys))
+	used := make([]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := result[key]; ok {
+			continue
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		partition := int(h.Sum32() % uint32(len(keys)))
+		for used[partition] {
+			partition = (partition + 1) % len(keys)
+		}
+		used[partition] = true
+		result[key] = partition
+	}
+	return result
 }
 
 // Consumer represents a local pulsar consumer
 type Consumer struct {
-	eventGroups          map[int64]*eventsGroup
+	// eventGroupsMu protects the eventGroups of all partitionSinks, which are
+	// resolved by both the resolved ts events and the forced flush in Run.
+	eventGroupsMu        sync.Mutex
 	ddlList              []*model.DDLEvent
 	ddlListMu            sync.Mutex
 	lastReceivedDDL      *model.DDLEvent
@@ -334,6 +487,14 @@ type Consumer struct {
 	sinkFactory *eventsinkfactory.SinkFactory
 	sinks       []*partitionSinks
 	sinksMu     sync.Mutex
+	// closed is protected by sinksMu, the sinks are cleared once it's set.
+	closed bool
+	// cancel cancels the context of the sinks.
+	cancel context.CancelFunc
+	// topicPartitions maps the key of each topic to its index in sinks.
+	topicPartitions map[string]int
+	// unknownTopics records the topics not subscribed at startup.
+	unknownTopics map[string]struct{}
 
 	// initialize to 0 by default
 	globalResolvedTs uint64
@@ -342,6 +503,15 @@ type Consumer struct {
 
 	codecConfig *common.Config
 
+	// schemaFetcher is nil if the upstream TiDB is not specified.
+	schemaFetcher *upstreamSchemaFetcher
+	// codecDetector is nil if the codec auto detection is disabled.
+	codecDetector *CodecAutoDetector
+	// verifier is nil unless the tables to verify are specified.
+	verifier *tableVerifier
+	// topicRule is nil if the topic rule is not specified.
+	topicRule *topicRuleChecker
+
 	option *ConsumerOption
 }
 
@@ -369,12 +539,41 @@ func NewConsumer(ctx context.Context, o *ConsumerOption) (*Consumer, error) {
 		c.codecConfig.AvroEnableWatermark = true
 	}
 
+	if o.codecAutoDetect {
+		c.codecDetector = NewCodecAutoDetector()
+	}
+
+	if o.topicRule != "" {
+		c.topicRule, err = newTopicRuleChecker(o.topicRule)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid topic-rule")
+		}
+	}
+
+	if o.upstreamTiDBDSN != "" {
+		db, err := openDB(ctx, o.upstreamTiDBDSN)
+		if err != nil {
+			return nil, err
+		}
+		c.schemaFetcher = newUpstreamSchemaFetcher(db)
+		if len(o.verifyTables) > 0 {
+			c.verifier, err = newConsumerVerifier(ctx, db, o)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if len(o.verifyTables) > 0 {
+		return nil, errors.New("upstream-tidb-dsn is required to verify the tables")
+	}
+
 	c.sinks = make([]*partitionSinks, o.partitionNum)
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error, 1)
 	for i := 0; i < o.partitionNum; i++ {
-		c.sinks[i] = &partitionSinks{}
+		c.sinks[i] = newPartitionSinks()
 	}
+	c.topicPartitions = assignVirtualPartitions(o.topics)
+	c.unknownTopics = make(map[string]struct{})
 
 	changefeedID := model.DefaultChangeFeedID("pulsar-consumer")
 	f, err := eventsinkfactory.New(ctx, changefeedID, o.downstreamURI, config.GetDefaultReplicaConfig(), errChan, nil)
@@ -383,29 +582,68 @@ func NewConsumer(ctx context.Context, o *ConsumerOption) (*Consumer, error) {
 		return nil, errors.Trace(err)
 	}
 	c.sinkFactory = f
+	c.cancel = cancel
 
 	go func() {
-		err := <-errChan
-		if errors.Cause(err) != context.Canceled {
-			log.Error("error on running consumer", zap.Error(err))
-		} else {
+		select {
+		case <-ctx.Done():
 			log.Info("consumer exited")
+			return
+		case err := <-errChan:
+			if errors.Cause(err) != context.Canceled {
+				log.Error("error on running consumer", zap.Error(err))
+			} else {
+				log.Info("consumer exited")
+			}
 		}
 		cancel()
 	}()
 
 	ddlSink, err := ddlsinkfactory.New(ctx, changefeedID, o.downstreamURI, config.GetDefaultReplicaConfig())
 	if err != nil {
+		f.Close()
 		cancel()
 		return nil, errors.Trace(err)
 	}
 	c.ddlSink = ddlSink
-	c.eventGroups = make(map[int64]*eventsGroup)
 	return c, nil
 }
 
+// Close closes the DDL sink and the event sink factory of the consumer,
+// the messages handled after it returns ErrSinkClosed. It's safe to call
+// Close more than once.
+func (c *Consumer) Close() error {
+	c.sinksMu.Lock()
+	defer c.sinksMu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.sinks = nil
+
+	if c.ddlSink != nil {
+		c.ddlSink.Close()
+	}
+	if c.sinkFactory != nil {
+		c.sinkFactory.Close()
+	}
+	if c.cancel != nil {
+		c.cancel()
+	}
+	log.Info("pulsar consumer closed")
+	return nil
+}
+
 type eventsGroup struct {
 	events []*model.RowChangedEvent
+	// arrivals records when the unresolved events are appended, in the order
+	// of appending.
+	arrivals []eventArrival
+}
+
+type eventArrival struct {
+	commitTs   uint64
+	appendedAt time.Time
 }
 
 func newEventsGroup() *eventsGroup {
@@ -416,6 +654,7 @@ func newEventsGroup() *eventsGroup {
 
 func (g *eventsGroup) Append(e *model.RowChangedEvent) {
 	g.events = append(g.events, e)
+	g.arrivals = append(g.arrivals, eventArrival{commitTs: e.CommitTs, appendedAt: time.Now()})
 }
 
 func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
@@ -429,33 +668,75 @@ func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
 	result := g.events[:i]
 	g.events = g.events[i:]
 
+	arrivals := g.arrivals[:0]
+	for _, a := range g.arrivals {
+		if a.commitTs > resolveTs {
+			arrivals = append(arrivals, a)
+		}
+	}
+	g.arrivals = arrivals
+
 	return result
 }
 
+// oldestAppendedAt returns the time when the oldest unresolved event is appended.
+func (g *eventsGroup) oldestAppendedAt() (time.Time, bool) {
+	if len(g.arrivals) == 0 {
+		return time.Time{}, false
+	}
+	return g.arrivals[0].appendedAt, true
+}
+
+// minCommitTs returns the minimum commit ts of the buffered events.
+func (g *eventsGroup) minCommitTs() (uint64, bool) {
+	if len(g.events) == 0 {
+		return 0, false
+	}
+	result := g.events[0].CommitTs
+	for _, e := range g.events[1:] {
+		if e.CommitTs < result {
+			result = e.CommitTs
+		}
+	}
+	return result, true
+}
+
 // HandleMsg handles the message received from the pulsar consumer
 func (c *Consumer) HandleMsg(msg pulsar.Message) error {
+	partition, ok := c.topicPartitions[topicKey(msg.Topic())]
+	if !ok {
+		// The topics are only resolved at startup, the consumer must be
+		// restarted with the new topic list to consume the topic.
+		if _, warned := c.unknownTopics[msg.Topic()]; !warned {
+			log.Warn("message received from an unknown topic, the topic list may be "+
+				"changed, restart the consumer to consume it",
+				zap.String("topic", msg.Topic()),
+				zap.Strings("topics", c.option.topics))
+			c.unknownTopics[msg.Topic()] = struct{}{}
+		}
+		return nil
+	}
 	c.sinksMu.Lock()
-	sink := c.sinks[0]
+	if c.closed {
+		c.sinksMu.Unlock()
+		return ErrSinkClosed
+	}
+	sink := c.sinks[partition]
 	c.sinksMu.Unlock()
 	if sink == nil {
 		panic("sink should initialized")
 	}
+	c.eventGroupsMu.Lock()
+	defer c.eventGroupsMu.Unlock()
 
 	ctx := context.Background()
-	var (
-		decoder codec.RowEventDecoder
-		err     error
-	)
-
-	switch c.codecConfig.Protocol {
-	case config.ProtocolCanalJSON:
-		decoder, err = canal.NewBatchDecoder(ctx, c.codecConfig, nil)
-		if err != nil {
-			return err
+	protocol := c.codecConfig.Protocol
+	if c.codecDetector != nil {
+		if detected, ok := c.codecDetector.Detect([]byte(msg.Key()), msg.Payload()); ok {
+			protocol = detected
 		}
-	default:
-		log.Panic("Protocol not supported", zap.Any("Protocol", c.codecConfig.Protocol))
 	}
+	decoder, err := c.newDecoder(ctx, protocol)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -491,6 +772,9 @@ func (c *Consumer) HandleMsg(msg pulsar.Message) error {
 					zap.Error(err))
 			}
 			log.Info("DDL event received", zap.Any("DDL", ddl))
+			if c.schemaFetcher != nil && ddl.TableInfo != nil {
+				c.schemaFetcher.invalidate(ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table)
+			}
 			c.appendDDL(ddl)
 		case model.MessageTypeRow:
 			row, err := decoder.NextRowChangedEvent()
@@ -511,6 +795,16 @@ func (c *Consumer) HandleMsg(msg pulsar.Message) error {
 				// todo: mark the offset after the DDL is fully synced to the downstream mysql.
 				continue
 			}
+			if c.schemaFetcher != nil {
+				if err := c.schemaFetcher.enrich(ctx, row); err != nil {
+					log.Error("enrich the row changed event by the upstream schema failed",
+						zap.Any("row", row), zap.Error(err))
+					return errors.Trace(err)
+				}
+			}
+			if c.topicRule != nil {
+				c.topicRule.check(msg.Topic(), row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName())
+			}
 			var partitionID int64
 			if row.TableInfo.IsPartitionTable() {
 				partitionID = row.PhysicalTableID
@@ -520,10 +814,10 @@ func (c *Consumer) HandleMsg(msg pulsar.Message) error {
 				generateFakeTableID(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName(), partitionID)
 			row.TableInfo.TableName.TableID = tableID
 
-			group, ok := c.eventGroups[tableID]
+			group, ok := sink.eventGroups[tableID]
 			if !ok {
 				group = newEventsGroup()
-				c.eventGroups[tableID] = group
+				sink.eventGroups[tableID] = group
 			}
 			group.Append(row)
 		case model.MessageTypeResolved:
@@ -545,38 +839,126 @@ func (c *Consumer) HandleMsg(msg pulsar.Message) error {
 				continue
 			}
 
-			for tableID, group := range c.eventGroups {
+			for tableID, group := range sink.eventGroups {
 				events := group.Resolve(ts)
 				if len(events) == 0 {
 					continue
 				}
-				if _, ok := sink.tableSinksMap.Load(tableID); !ok {
-					log.Info("create table sink for consumer", zap.Any("tableID", tableID))
-					tableSink := c.sinkFactory.CreateTableSinkForConsumer(
-						model.DefaultChangeFeedID("pulsar-consumer"),
-						spanz.TableIDToComparableSpan(tableID),
-						events[0].CommitTs)
-
-					log.Info("table sink created", zap.Any("tableID", tableID),
-						zap.Any("tableSink", tableSink.GetCheckpointTs()))
-
-					sink.tableSinksMap.Store(tableID, tableSink)
-				}
-				s, _ := sink.tableSinksMap.Load(tableID)
-				s.(tablesink.TableSink).AppendRowChangedEvents(events...)
-				commitTs := events[len(events)-1].CommitTs
-				lastCommitTs, ok := sink.tablesCommitTsMap.Load(tableID)
-				if !ok || lastCommitTs.(uint64) < commitTs {
-					sink.tablesCommitTsMap.Store(tableID, commitTs)
-				}
+				c.appendRowChangedEvents(sink, tableID, events)
 			}
 			atomic.StoreUint64(&sink.resolvedTs, ts)
+		case model.MessageTypeHeartbeat:
+			ts, err := decoder.NextResolvedEvent()
+			if err != nil {
+				log.Panic("decode message value failed",
+					zap.ByteString("value", msg.Payload()),
+					zap.Error(err))
+			}
+			// Heartbeats do not flush the buffered events, so the resolved ts
+			// never exceeds the commit ts of them.
+			for _, group := range sink.eventGroups {
+				if commitTs, ok := group.minCommitTs(); ok && commitTs <= ts {
+					ts = commitTs - 1
+				}
+			}
+			if ts > atomic.LoadUint64(&sink.resolvedTs) {
+				atomic.StoreUint64(&sink.resolvedTs, ts)
+			}
 		}
 
 	}
 	return nil
 }
 
+// newDecoder creates a decoder of the protocol, the codec config of the
+// consumer is reused by all protocols.
+func (c *Consumer) newDecoder(
+	ctx context.Context, protocol config.Protocol,
+) (codec.RowEventDecoder, error) {
+	codecConfig := *c.codecConfig
+	codecConfig.Protocol = protocol
+	switch protocol {
+	case config.ProtocolCanalJSON:
+		return canal.NewBatchDecoder(ctx, &codecConfig, nil)
+	case config.ProtocolOpen:
+		return open.NewBatchDecoder(ctx, &codecConfig, nil)
+	case config.ProtocolOCDF:
+		return ocdf.NewDecoder(), nil
+	default:
+		log.Panic("Protocol not supported", zap.Any("Protocol", protocol))
+	}
+	return nil, nil
+}
+
+// appendRowChangedEvents appends the resolved events to the table sink,
+// the table sink is created if it does not exist.
+func (c *Consumer) appendRowChangedEvents(
+	sink *partitionSinks, tableID int64, events []*model.RowChangedEvent,
+) tablesink.TableSink {
+	if _, ok := sink.tableSinksMap.Load(tableID); !ok {
+		log.Info("create table sink for consumer", zap.Any("tableID", tableID))
+		tableSink := c.sinkFactory.CreateTableSinkForConsumer(
+			model.DefaultChangeFeedID("pulsar-consumer"),
+			spanz.TableIDToComparableSpan(tableID),
+			events[0].CommitTs)
+
+		log.Info("table sink created", zap.Any("tableID", tableID),
+			zap.Any("tableSink", tableSink.GetCheckpointTs()))
+
+		sink.tableSinksMap.Store(tableID, tableSink)
+	}
+	s, _ := sink.tableSinksMap.Load(tableID)
+	tableSink := s.(tablesink.TableSink)
+	tableSink.AppendRowChangedEvents(events...)
+	commitTs := events[len(events)-1].CommitTs
+	lastCommitTs, ok := sink.tablesCommitTsMap.Load(tableID)
+	if !ok || lastCommitTs.(uint64) < commitTs {
+		sink.tablesCommitTsMap.Store(tableID, commitTs)
+	}
+	return tableSink
+}
+
+// forceFlushStaleEvents flushes the events of the tables whose oldest
+// unresolved event is buffered longer than maxDMLBatchWaitMs, it's used by the
+// protocols which do not emit resolved ts events frequently. The events are
+// flushed as if a resolved ts event with the commit ts of the oldest unresolved
+// event is received by the table, unless a DDL before them is not executed yet.
+func (c *Consumer) forceFlushStaleEvents() error {
+	if c.option.maxDMLBatchWaitMs <= 0 {
+		return nil
+	}
+	maxWait := time.Duration(c.option.maxDMLBatchWaitMs) * time.Millisecond
+	nextDDL := c.getFrontDDL()
+
+	c.eventGroupsMu.Lock()
+	defer c.eventGroupsMu.Unlock()
+	now := time.Now()
+	return c.forEachSink(func(sink *partitionSinks) error {
+		for tableID, group := range sink.eventGroups {
+			appendedAt, ok := group.oldestAppendedAt()
+			if !ok || now.Sub(appendedAt) < maxWait {
+				continue
+			}
+			ts, _ := group.minCommitTs()
+			if nextDDL != nil && nextDDL.CommitTs <= ts {
+				continue
+			}
+			events := group.Resolve(ts)
+			tableSink := c.appendRowChangedEvents(sink, tableID, events)
+			if err := tableSink.UpdateResolvedTs(model.NewResolvedTs(ts)); err != nil {
+				return errors.Trace(err)
+			}
+			dmlBatchForcedFlushCounter.Inc()
+			log.Warn("DML batch is flushed without resolved ts event",
+				zap.Int64("tableID", tableID),
+				zap.Uint64("resolvedTs", ts),
+				zap.Int("events", len(events)),
+				zap.Duration("waited", now.Sub(appendedAt)))
+		}
+		return nil
+	})
+}
+
 // append DDL wait to be handled, only consider the constraint among DDLs.
 // for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
 func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
@@ -627,6 +1009,9 @@ func (c *Consumer) popDDL() *model.DDLEvent {
 func (c *Consumer) forEachSink(fn func(sink *partitionSinks) error) error {
 	c.sinksMu.Lock()
 	defer c.sinksMu.Unlock()
+	if c.closed {
+		return ErrSinkClosed
+	}
 	for _, sink := range c.sinks {
 		if err := fn(sink); err != nil {
 			return errors.Trace(err)
@@ -657,6 +1042,11 @@ func (c *Consumer) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
+			// 0. Flush the DMLs which wait for the resolved ts event too long
+			if err := c.forceFlushStaleEvents(); err != nil {
+				return errors.Trace(err)
+			}
+
 			// 1. Get the minimum resolvedTs of all the partitionSinks
 			minResolvedTs, err := c.getMinResolvedTs()
 			if err != nil {
@@ -704,6 +1094,13 @@ func (c *Consumer) Run(ctx context.Context) error {
 			}); err != nil {
 				return errors.Trace(err)
 			}
+
+			// 5. verify the downstream against the upstream at globalResolvedTs
+			if c.verifier != nil {
+				if err := c.verifier.maybeVerify(ctx, c.globalResolvedTs); err != nil {
+					return errors.Trace(err)
+				}
+			}
 		}
 	}
 }
@@ -760,3 +1157,24 @@ func (g *fakeTableIDGenerator) generateFakeTableID(schema, table string, partiti
 	g.tableIDs[key] = g.currentTableID
 	return g.currentTableID
 }
+
+func openDB(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		log.Error("open db failed", zap.Error(err))
+		return nil, errors.Trace(err)
+	}
+
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(10)
+	db.SetConnMaxLifetime(10 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		log.Error("ping db failed", zap.Error(err))
+		return nil, errors.Trace(err)
+	}
+	log.Info("open db success")
+	return db, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	eventsinkfactory "github.com/pingcap/tiflow/cdc/sink/dmlsink/factory"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTableSink writes the events up to the resolved ts immediately.
+type mockTableSink struct {
+	mu         sync.Mutex
+	buffered   []*model.RowChangedEvent
+	written    []*model.RowChangedEvent
+	resolvedTs model.ResolvedTs
+}
+
+func (s *mockTableSink) AppendRowChangedEvents(rows ...*model.RowChangedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffered = append(s.buffered, rows...)
+}
+
+func (s *mockTableSink) UpdateResolvedTs(resolvedTs model.ResolvedTs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resolvedTs.EqualOrGreater(resolvedTs) {
+		return nil
+	}
+	s.resolvedTs = resolvedTs
+	i := 0
+	for ; i < len(s.buffered) && s.buffered[i].CommitTs <= resolvedTs.Ts; i++ {
+	}
+	s.written = append(s.written, s.buffered[:i]...)
+	s.buffered = s.buffered[i:]
+	return nil
+}
+
+func (s *mockTableSink) GetCheckpointTs() model.ResolvedTs {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolvedTs
+}
+
+func (s *mockTableSink) writtenCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.written)
+}
+
+func (s *mockTableSink) GetLastSyncedTs() model.Ts { return 0 }
+func (s *mockTableSink) Close()                    {}
+func (s *mockTableSink) AsyncClose() bool          { return true }
+func (s *mockTableSink) CheckHealth() error        { return nil }
+
+func TestForceFlushStaleEvents(t *testing.T) {
+	c := &Consumer{
+		option: &ConsumerOption{maxDMLBatchWaitMs: 100},
+		sinks:  []*partitionSinks{newPartitionSinks()},
+	}
+	tableSink := &mockTableSink{}
+	c.sinks[0].tableSinksMap.Store(int64(1), tableSink)
+	eventGroups := c.sinks[0].eventGroups
+
+	// The resolved ts event is withheld, so the events are only flushed
+	// by the timeout.
+	eventGroups[1] = newEventsGroup()
+	eventGroups[1].Append(&model.RowChangedEvent{CommitTs: 20})
+	eventGroups[1].Append(&model.RowChangedEvent{CommitTs: 10})
+	eventGroups[1].Append(&model.RowChangedEvent{CommitTs: 10})
+	before := testutil.ToFloat64(dmlBatchForcedFlushCounter)
+
+	// The events are not flushed before the timeout.
+	require.NoError(t, c.forceFlushStaleEvents())
+	require.Equal(t, 0, tableSink.writtenCount())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return tableSink.writtenCount() == 3
+	}, 5*time.Second, 50*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// The events are flushed in two batches, one for each commit ts.
+	require.Equal(t, before+2, testutil.ToFloat64(dmlBatchForcedFlushCounter))
+	require.Equal(t, uint64(20), tableSink.GetCheckpointTs().Ts)
+	_, ok := eventGroups[1].oldestAppendedAt()
+	require.False(t, ok)
+
+	// The events after a pending DDL wait for the DDL to be executed.
+	c.ddlList = []*model.DDLEvent{{CommitTs: 30}}
+	eventGroups[1].Append(&model.RowChangedEvent{CommitTs: 40})
+	eventGroups[1].arrivals[0].appendedAt = time.Now().Add(-time.Second)
+	require.NoError(t, c.forceFlushStaleEvents())
+	require.Equal(t, 3, tableSink.writtenCount())
+	require.Len(t, eventGroups[1].events, 1)
+}
+
+func TestAssignVirtualPartitions(t *testing.T) {
+	require.Equal(t, "t1", topicKey("t1"))
+	require.Equal(t, "t1", topicKey("persistent://public/default/t1"))
+	require.Equal(t, "t1", topicKey("persistent://public/default/t1-partition-2"))
+	require.Equal(t, "t1-partition-x", topicKey("t1-partition-x"))
+
+	topics := []string{"t1", "t2", "persistent://public/default/t3", "t4", "t5"}
+	partitions := assignVirtualPartitions(topics)
+	require.Len(t, partitions, len(topics))
+	used := make(map[int]struct{})
+	for _, topic := range topics {
+		partition, ok := partitions[topicKey(topic)]
+		require.True(t, ok)
+		require.GreaterOrEqual(t, partition, 0)
+		require.Less(t, partition, len(topics))
+		used[partition] = struct{}{}
+	}
+	require.Len(t, used, len(topics))
+
+	// The mapping is stable regardless of the order of the topics.
+	require.Equal(t, partitions,
+		assignVirtualPartitions([]string{"t5", "t4", "t3", "t2", "t1"}))
+}
+
+func TestHandleCanalJSONResolvedEvent(t *testing.T) {
+	ctx := context.Background()
+	// The resolved ts of canal-json is carried by the TiDB extension.
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	c := &Consumer{
+		option:               &ConsumerOption{topics: []string{"t1"}},
+		sinks:                []*partitionSinks{newPartitionSinks()},
+		topicPartitions:      assignVirtualPartitions([]string{"t1"}),
+		unknownTopics:        make(map[string]struct{}),
+		codecConfig:          codecConfig,
+		fakeTableIDGenerator: &fakeTableIDGenerator{tableIDs: make(map[string]int64)},
+	}
+
+	builder, err := canal.NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	for _, ts := range []uint64{100, 200} {
+		watermark, err := encoder.EncodeCheckpointEvent(ts)
+		require.NoError(t, err)
+		require.NotNil(t, watermark)
+		require.NoError(t, c.HandleMsg(&mockMessage{
+			topic: "t1", key: string(watermark.Key), payload: watermark.Value,
+		}))
+		require.Equal(t, ts, c.sinks[0].resolvedTs)
+	}
+
+	// The fallback resolved ts is skipped.
+	watermark, err := encoder.EncodeCheckpointEvent(150)
+	require.NoError(t, err)
+	require.NoError(t, c.HandleMsg(&mockMessage{
+		topic: "t1", key: string(watermark.Key), payload: watermark.Value,
+	}))
+	require.Equal(t, uint64(200), c.sinks[0].resolvedTs)
+}
+
+type mockDDLSink struct {
+	ddlsink.Sink
+	closed bool
+}
+
+func (s *mockDDLSink) Close() { s.closed = true }
+
+func TestConsumerClose(t *testing.T) {
+	ddlSink := &mockDDLSink{}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Consumer{
+		option:          &ConsumerOption{topics: []string{"t1"}},
+		sinks:           []*partitionSinks{newPartitionSinks()},
+		topicPartitions: assignVirtualPartitions([]string{"t1"}),
+		unknownTopics:   make(map[string]struct{}),
+		codecConfig:     common.NewConfig(config.ProtocolCanalJSON),
+		ddlSink:         ddlSink,
+		sinkFactory:     &eventsinkfactory.SinkFactory{},
+		cancel:          cancel,
+	}
+	require.NoError(t, c.Close())
+	require.True(t, ddlSink.closed)
+	require.Nil(t, c.sinks)
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	// Close is idempotent.
+	require.NoError(t, c.Close())
+
+	// The messages are rejected instead of panicking after the consumer is
+	// closed.
+	err := c.HandleMsg(&mockMessage{topic: "t1", payload: []byte("{}")})
+	require.ErrorIs(t, err, ErrSinkClosed)
+	_, err = c.getMinResolvedTs()
+	require.ErrorIs(t, err, ErrSinkClosed)
+}
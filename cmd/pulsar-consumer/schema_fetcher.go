@@ -0,0 +1,175 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// schemaCacheKey identifies a version of the schema of a table.
+type schemaCacheKey struct {
+	schema  string
+	table   string
+	version uint64
+}
+
+// upstreamColumn is the column metadata fetched from the upstream TiDB.
+type upstreamColumn struct {
+	name         string
+	isPrimaryKey bool
+}
+
+// upstreamSchemaFetcher fetches the column metadata of the tables from the
+// upstream TiDB, it's used to enrich the row changed events whose columns are
+// only identified by their indices.
+type upstreamSchemaFetcher struct {
+	db *sql.DB
+
+	mu sync.Mutex
+	// cache stores the columns of each table, ordered by the ordinal position.
+	cache map[schemaCacheKey][]upstreamColumn
+}
+
+func newUpstreamSchemaFetcher(db *sql.DB) *upstreamSchemaFetcher {
+	return &upstreamSchemaFetcher{
+		db:    db,
+		cache: make(map[schemaCacheKey][]upstreamColumn),
+	}
+}
+
+// isSchemaIncomplete returns true if any column of the table info has no name.
+func isSchemaIncomplete(tableInfo *model.TableInfo) bool {
+	for _, col := range tableInfo.Columns {
+		if col.Name.O == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch returns the columns of the table, the upstream is only queried when
+// the version of the table is encountered for the first time.
+func (f *upstreamSchemaFetcher) fetch(
+	ctx context.Context, key schemaCacheKey,
+) ([]upstreamColumn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if columns, ok := f.cache[key]; ok {
+		return columns, nil
+	}
+
+	rows, err := f.db.QueryContext(ctx, "SELECT COLUMN_NAME, COLUMN_KEY FROM "+
+		"INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? "+
+		"ORDER BY ORDINAL_POSITION", key.schema, key.table)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer rows.Close()
+
+	var columns []upstreamColumn
+	for rows.Next() {
+		var name, columnKey string
+		if err := rows.Scan(&name, &columnKey); err != nil {
+			return nil, errors.Trace(err)
+		}
+		columns = append(columns, upstreamColumn{name: name, isPrimaryKey: columnKey == "PRI"})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(columns) == 0 {
+		return nil, errors.Errorf("table %s.%s not found in the upstream", key.schema, key.table)
+	}
+	f.cache[key] = columns
+	log.Info("schema fetched from the upstream",
+		zap.String("schema", key.schema),
+		zap.String("table", key.table),
+		zap.Uint64("version", key.version),
+		zap.Int("columns", len(columns)))
+	return columns, nil
+}
+
+// invalidate drops all cached versions of the table, it's called when a DDL
+// of the table is received since the schema may be changed by it.
+func (f *upstreamSchemaFetcher) invalidate(schema, table string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key := range f.cache {
+		if key.schema == schema && key.table == table {
+			delete(f.cache, key)
+		}
+	}
+}
+
+// enrich fills the names of the columns of the row changed event by the
+// upstream schema, the index of a column is its ordinal position in the
+// upstream table. The event is left untouched if its schema is complete.
+func (f *upstreamSchemaFetcher) enrich(ctx context.Context, row *model.RowChangedEvent) error {
+	if !isSchemaIncomplete(row.TableInfo) {
+		return nil
+	}
+	schema, table := row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName()
+	columns, err := f.fetch(ctx, schemaCacheKey{
+		schema:  schema,
+		table:   table,
+		version: row.TableInfo.Version,
+	})
+	if err != nil {
+		return err
+	}
+
+	pkNames := make(map[string]struct{})
+	nameColumns := func(datas []*model.ColumnData, cols []*model.Column) ([]*model.Column, error) {
+		for i, data := range datas {
+			offset := row.TableInfo.ForceGetColumnInfo(data.ColumnID).Offset
+			if offset >= len(columns) {
+				return nil, errors.Errorf("column index %d out of range of table %s.%s, "+
+					"the upstream table has %d columns", offset, schema, table, len(columns))
+			}
+			cols[i].Name = columns[offset].name
+			if columns[offset].isPrimaryKey {
+				pkNames[cols[i].Name] = struct{}{}
+			}
+		}
+		return cols, nil
+	}
+	cols, err := nameColumns(row.Columns, row.GetColumns())
+	if err != nil {
+		return err
+	}
+	preCols, err := nameColumns(row.PreColumns, row.GetPreColumns())
+	if err != nil {
+		return err
+	}
+
+	// The table info is rebuilt from the columns carried by the event, so only
+	// the primary key columns present in the event are kept.
+	source := cols
+	if source == nil {
+		source = preCols
+	}
+	tableInfo := model.BuildTableInfoWithPKNames4Test(schema, table, source, pkNames)
+	tableInfo.Version = row.TableInfo.Version
+	row.TableInfo = tableInfo
+	row.Columns = model.Columns2ColumnDatas(cols, tableInfo)
+	row.PreColumns = model.Columns2ColumnDatas(preCols, tableInfo)
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+// newIncompleteRow builds a row changed event of `test`.`t` whose columns are
+// only identified by their indices.
+func newIncompleteRow(commitTs uint64, values, preValues []interface{}) *model.RowChangedEvent {
+	source := values
+	if source == nil {
+		source = preValues
+	}
+	columns := make([]*model.Column, 0, len(source))
+	for range source {
+		columns = append(columns, &model.Column{Type: mysql.TypeLong})
+	}
+	tableInfo := model.BuildTableInfo("test", "t", columns, nil)
+	toColumnDatas := func(values []interface{}) []*model.ColumnData {
+		if values == nil {
+			return nil
+		}
+		datas := make([]*model.ColumnData, 0, len(values))
+		for i, value := range values {
+			datas = append(datas, &model.ColumnData{
+				ColumnID: tableInfo.Columns[i].ID,
+				Value:    value,
+			})
+		}
+		return datas
+	}
+	return &model.RowChangedEvent{
+		CommitTs:   commitTs,
+		TableInfo:  tableInfo,
+		Columns:    toColumnDatas(values),
+		PreColumns: toColumnDatas(preValues),
+	}
+}
+
+func TestUpstreamSchemaFetcher(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	f := newUpstreamSchemaFetcher(db)
+	ctx := context.Background()
+
+	// The columns of `CREATE TABLE test.t (id INT PRIMARY KEY, age INT, score INT)`
+	// in the INFORMATION_SCHEMA of TiDB.
+	mock.ExpectQuery("SELECT COLUMN_NAME, COLUMN_KEY FROM INFORMATION_SCHEMA.COLUMNS").
+		WithArgs("test", "t").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_KEY"}).
+			AddRow("id", "PRI").
+			AddRow("age", "").
+			AddRow("score", "MUL"))
+
+	row := newIncompleteRow(10, []interface{}{1, 20, 30}, []interface{}{1, 19, 30})
+	require.True(t, isSchemaIncomplete(row.TableInfo))
+	require.NoError(t, f.enrich(ctx, row))
+	require.False(t, isSchemaIncomplete(row.TableInfo))
+	require.Equal(t, "test", row.TableInfo.GetSchemaName())
+	require.Equal(t, "t", row.TableInfo.GetTableName())
+	require.Equal(t, []string{"id"}, row.PrimaryKeyColumnNames())
+	cols := row.GetColumns()
+	require.Len(t, cols, 3)
+	for i, name := range []string{"id", "age", "score"} {
+		require.Equal(t, name, cols[i].Name)
+	}
+	require.Equal(t, 20, cols[1].Value)
+	require.True(t, cols[0].Flag.IsPrimaryKey())
+	require.Equal(t, 19, row.GetPreColumns()[1].Value)
+
+	// The schema is cached, a delete event only carries the pre columns.
+	row = newIncompleteRow(20, nil, []interface{}{1, 20, 30})
+	require.NoError(t, f.enrich(ctx, row))
+	require.Nil(t, row.Columns)
+	require.Equal(t, "score", row.GetPreColumns()[2].Name)
+
+	// An event with complete schema is not enriched.
+	complete := &model.RowChangedEvent{
+		TableInfo: model.BuildTableInfo("test", "t", []*model.Column{
+			{Name: "id", Type: mysql.TypeLong},
+		}, nil),
+	}
+	tableInfo := complete.TableInfo
+	require.NoError(t, f.enrich(ctx, complete))
+	require.Same(t, tableInfo, complete.TableInfo)
+
+	// The schema is fetched again after a DDL of the table.
+	f.invalidate("test", "t")
+	mock.ExpectQuery("SELECT COLUMN_NAME, COLUMN_KEY FROM INFORMATION_SCHEMA.COLUMNS").
+		WithArgs("test", "t").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_KEY"}).
+			AddRow("id", "PRI"))
+	row = newIncompleteRow(30, []interface{}{1, 20}, nil)
+	require.Error(t, f.enrich(ctx, row))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher/topic"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// topicRuleMismatchCounter counts the row changed events received from a
+// topic other than the one the topic rule dispatches them to.
+var topicRuleMismatchCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "consumer",
+		Name:      "topic_rule_mismatch_total",
+		Help:      "The number of row changed events received from an unexpected topic",
+	})
+
+func init() {
+	prometheus.MustRegister(topicRuleMismatchCounter)
+}
+
+// topicRuleChecker checks the row changed events are received from the
+// topics they are dispatched to by the topic rule of the changefeed, it's the
+// inverse of the topic dispatcher of the pulsar sink.
+type topicRuleChecker struct {
+	expr topic.Expression
+	// warned records the tables warned with the topics, so that the
+	// mismatch of a table is only logged once for each topic.
+	warned map[string]map[string]struct{}
+}
+
+// newTopicRuleChecker creates a topicRuleChecker of the topic rule, such as
+// `{schema}_{table}` or `persistent://public/default/{schema}.{table}.events`.
+func newTopicRuleChecker(rule string) (*topicRuleChecker, error) {
+	expr := topic.Expression(rule)
+	if err := expr.PulsarValidate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &topicRuleChecker{
+		expr:   expr,
+		warned: make(map[string]map[string]struct{}),
+	}, nil
+}
+
+// expectedTopic returns the key of the topic which the events of the table
+// are dispatched to.
+func (c *topicRuleChecker) expectedTopic(schema, table string) string {
+	return topicKey(c.expr.Substitute(schema, table))
+}
+
+// check returns false if the events of the table are not expected to be
+// received from the topic. The event is still consumed, the mismatch is
+// only reported, since the topic rule of the changefeed may be changed.
+func (c *topicRuleChecker) check(actualTopic, schema, table string) bool {
+	expected := c.expectedTopic(schema, table)
+	if topicKey(actualTopic) == expected {
+		return true
+	}
+	topicRuleMismatchCounter.Inc()
+
+	name := schema + "." + table
+	topics, ok := c.warned[name]
+	if !ok {
+		topics = make(map[string]struct{})
+		c.warned[name] = topics
+	}
+	if _, ok := topics[actualTopic]; !ok {
+		log.Warn("row changed event received from an unexpected topic, "+
+			"the topic rule may not match the changefeed",
+			zap.String("schema", schema), zap.String("table", table),
+			zap.String("topic", actualTopic), zap.String("expectedTopic", expected),
+			zap.String("topicRule", string(c.expr)))
+		topics[actualTopic] = struct{}{}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicRuleChecker(t *testing.T) {
+	_, err := newTopicRuleChecker("")
+	require.Error(t, err)
+	_, err = newTopicRuleChecker("a/b")
+	require.Error(t, err)
+
+	c, err := newTopicRuleChecker("{schema}_{table}")
+	require.NoError(t, err)
+	require.Equal(t, "test_t1", c.expectedTopic("test", "t1"))
+	before := testutil.ToFloat64(topicRuleMismatchCounter)
+	require.True(t, c.check("test_t1", "test", "t1"))
+	require.True(t, c.check("persistent://public/default/test_t1-partition-0", "test", "t1"))
+	require.False(t, c.check("test_t2", "test", "t1"))
+	require.False(t, c.check("test_t2", "test", "t1"))
+	require.Equal(t, before+2, testutil.ToFloat64(topicRuleMismatchCounter))
+	require.Len(t, c.warned["test.t1"], 1)
+
+	c, err = newTopicRuleChecker("persistent://public/default/{schema}.{table}.events")
+	require.NoError(t, err)
+	require.Equal(t, "test.t1.events", c.expectedTopic("test", "t1"))
+	require.True(t, c.check("persistent://public/default/test.t1.events", "test", "t1"))
+}
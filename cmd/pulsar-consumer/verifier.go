@@ -0,0 +1,224 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+// verifyInterval is the minimum interval between two verifications, since
+// each of them reads the whole tables from both the upstream and downstream.
+const verifyInterval = time.Minute
+
+// Row is a row read from a table, the NULL values are invalid NullStrings.
+type Row []sql.NullString
+
+func (r Row) String() string {
+	values := make([]string, 0, len(r))
+	for _, value := range r {
+		if !value.Valid {
+			values = append(values, "NULL")
+			continue
+		}
+		values = append(values, strconv.Quote(value.String))
+	}
+	return "(" + strings.Join(values, ",") + ")"
+}
+
+// runStaleRead reads all the rows of the table at ts by the stale read of
+// TiDB, ts is an expression such as `TIDB_PARSE_TSO(<tso>)`.
+func runStaleRead(ctx context.Context, db *sql.DB, table, ts string) ([]Row, error) {
+	rows, err := readRows(ctx, db, fmt.Sprintf("SELECT * FROM %s AS OF TIMESTAMP %s", table, ts))
+	if err != nil {
+		if isGCTooEarlyError(err) {
+			return nil, errors.Annotatef(err,
+				"can not read %s at %s, the timestamp is outside the GC window of the upstream, "+
+					"increase tidb_gc_life_time to verify it", table, ts)
+		}
+		return nil, errors.Trace(err)
+	}
+	return rows, nil
+}
+
+func isGCTooEarlyError(err error) bool {
+	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
+	return ok && mysqlErr.Number == errno.ErrGCTooEarly
+}
+
+func readRows(ctx context.Context, db *sql.DB, query string) ([]Row, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var result []Row
+	for rows.Next() {
+		row := make(Row, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, row)
+	}
+	return result, errors.Trace(rows.Err())
+}
+
+// tableVerifier verifies that the tables in the downstream are consistent
+// with the upstream, by comparing them with the stale read of the upstream
+// at the global resolved ts.
+type tableVerifier struct {
+	upstream   *sql.DB
+	downstream *sql.DB
+	// tables are the quoted names of the verified tables.
+	tables []string
+
+	lastVerifiedAt time.Time
+	lastVerifiedTs uint64
+}
+
+// newTableVerifier creates a tableVerifier of the tables in the form of
+// `schema.table`.
+func newTableVerifier(upstream, downstream *sql.DB, tables []string) (*tableVerifier, error) {
+	quoted := make([]string, 0, len(tables))
+	for _, table := range tables {
+		parts := strings.Split(table, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid table %s to verify, it must be `schema.table`", table)
+		}
+		quoted = append(quoted, quotes.QuoteSchema(parts[0], parts[1]))
+	}
+	return &tableVerifier{
+		upstream:   upstream,
+		downstream: downstream,
+		tables:     quoted,
+	}, nil
+}
+
+// newConsumerVerifier creates the tableVerifier of the consumer, the
+// downstream must be MySQL compatible.
+func newConsumerVerifier(
+	ctx context.Context, upstream *sql.DB, o *ConsumerOption,
+) (*tableVerifier, error) {
+	// The forced flush writes the DMLs beyond the global resolved ts.
+	if o.maxDMLBatchWaitMs > 0 {
+		return nil, errors.New("verify-table can not be used with max-dml-batch-wait-ms")
+	}
+	sinkURI, err := url.Parse(o.downstreamURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !sink.IsMySQLCompatibleScheme(strings.ToLower(sinkURI.Scheme)) {
+		return nil, errors.Errorf("verify-table requires a MySQL compatible downstream, got %s",
+			sinkURI.Scheme)
+	}
+	dsn, err := pmysql.GenBasicDSN(sinkURI, pmysql.NewConfig())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	downstream, err := openDB(ctx, dsn.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	return newTableVerifier(upstream, downstream, o.verifyTables)
+}
+
+// maybeVerify verifies the tables at ts if they haven't been verified in
+// the last verifyInterval. It must be called when all the DMLs with commitTs
+// <= ts have been flushed and no other DMLs have, so that the downstream is
+// a snapshot of the upstream at ts.
+func (v *tableVerifier) maybeVerify(ctx context.Context, ts uint64) error {
+	if ts <= v.lastVerifiedTs || time.Since(v.lastVerifiedAt) < verifyInterval {
+		return nil
+	}
+	for _, table := range v.tables {
+		if err := v.verify(ctx, table, ts); err != nil {
+			return err
+		}
+	}
+	v.lastVerifiedAt = time.Now()
+	v.lastVerifiedTs = ts
+	return nil
+}
+
+func (v *tableVerifier) verify(ctx context.Context, table string, ts uint64) error {
+	expected, err := runStaleRead(ctx, v.upstream, table, fmt.Sprintf("TIDB_PARSE_TSO(%d)", ts))
+	if err != nil {
+		return err
+	}
+	actual, err := readRows(ctx, v.downstream, "SELECT * FROM "+table)
+	if err != nil {
+		return err
+	}
+
+	missing, unexpected := diffRows(expected, actual)
+	if len(missing) > 0 || len(unexpected) > 0 {
+		log.Error("the downstream table is inconsistent with the upstream",
+			zap.String("table", table), zap.Uint64("ts", ts),
+			zap.Int("upstreamRows", len(expected)), zap.Int("downstreamRows", len(actual)),
+			zap.Strings("missingRows", missing), zap.Strings("unexpectedRows", unexpected))
+		return errors.Errorf("the downstream table %s is inconsistent with the upstream at %d, "+
+			"%d rows are missing, %d rows are unexpected", table, ts, len(missing), len(unexpected))
+	}
+	log.Info("the downstream table is verified",
+		zap.String("table", table), zap.Uint64("ts", ts), zap.Int("rows", len(actual)))
+	return nil
+}
+
+// diffRows returns the rows only in expected and the rows only in actual,
+// the order of the rows is ignored.
+func diffRows(expected, actual []Row) (missing, unexpected []string) {
+	counts := make(map[string]int, len(expected))
+	for _, row := range expected {
+		counts[row.String()]++
+	}
+	for _, row := range actual {
+		key := row.String()
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		unexpected = append(unexpected, key)
+	}
+	for key, count := range counts {
+		for i := 0; i < count; i++ {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing, unexpected
+}
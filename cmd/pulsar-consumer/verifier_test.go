@@ -0,0 +1,100 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStaleRead(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT * FROM `test`.`t` AS OF TIMESTAMP TIDB_PARSE_TSO(100)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "a").
+			AddRow(2, nil))
+	rows, err := runStaleRead(context.Background(), db, "`test`.`t`", "TIDB_PARSE_TSO(100)")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, `("1","a")`, rows[0].String())
+	require.Equal(t, `("2",NULL)`, rows[1].String())
+
+	// The timestamp is older than the GC safe point.
+	mock.ExpectQuery("SELECT * FROM `test`.`t` AS OF TIMESTAMP TIDB_PARSE_TSO(1)").
+		WillReturnError(&dmysql.MySQLError{
+			Number:  errno.ErrGCTooEarly,
+			Message: "GC life time is shorter than transaction duration",
+		})
+	_, err = runStaleRead(context.Background(), db, "`test`.`t`", "TIDB_PARSE_TSO(1)")
+	require.ErrorContains(t, err, "outside the GC window")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTableVerifier(t *testing.T) {
+	upstream, upstreamMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer upstream.Close()
+	downstream, downstreamMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer downstream.Close()
+
+	_, err = newTableVerifier(upstream, downstream, []string{"t"})
+	require.Error(t, err)
+	v, err := newTableVerifier(upstream, downstream, []string{"test.t"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The order of the rows is ignored.
+	upstreamMock.ExpectQuery("SELECT * FROM `test`.`t` AS OF TIMESTAMP TIDB_PARSE_TSO(100)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	downstreamMock.ExpectQuery("SELECT * FROM `test`.`t`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "b").AddRow(1, "a"))
+	require.NoError(t, v.maybeVerify(ctx, 100))
+	// The tables are not verified again within the interval.
+	require.NoError(t, v.maybeVerify(ctx, 200))
+
+	v.lastVerifiedAt = v.lastVerifiedAt.Add(-verifyInterval)
+	upstreamMock.ExpectQuery("SELECT * FROM `test`.`t` AS OF TIMESTAMP TIDB_PARSE_TSO(300)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "c"))
+	downstreamMock.ExpectQuery("SELECT * FROM `test`.`t`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	require.ErrorContains(t, v.maybeVerify(ctx, 300), "1 rows are missing, 1 rows are unexpected")
+
+	require.NoError(t, upstreamMock.ExpectationsWereMet())
+	require.NoError(t, downstreamMock.ExpectationsWereMet())
+}
+
+func TestDiffRows(t *testing.T) {
+	row := func(values ...string) Row {
+		r := make(Row, 0, len(values))
+		for _, value := range values {
+			r = append(r, sql.NullString{String: value, Valid: true})
+		}
+		return r
+	}
+	expected := []Row{row("1", "a"), row("1", "a"), row("2", "b")}
+	actual := []Row{row("1", "a"), row("3", "c")}
+	missing, unexpected := diffRows(expected, actual)
+	require.Equal(t, []string{`("1","a")`, `("2","b")`}, missing)
+	require.Equal(t, []string{`("3","c")`}, unexpected)
+}
@@ -19,84 +19,23 @@ import (
 	"strings"
 	"time"
 
-	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-const metadataCauseKey = "cause"
-
 // ToGRPCError converts an error to a gRPC error.
 func ToGRPCError(errIn error) error {
-	if errIn == nil {
-		return nil
-	}
-	if _, ok := status.FromError(errIn); ok {
-		return errIn
-	}
-
-	var (
-		normalizedErr *perrors.Error
-		metadata      map[string]string
-		rfcCode       perrors.RFCErrorCode
-		errMsg        string
-	)
-	if errors.As(errIn, &normalizedErr) {
-		rfcCode = normalizedErr.RFCCode()
-		if cause := normalizedErr.Cause(); cause != nil {
-			metadata = map[string]string{
-				metadataCauseKey: cause.Error(),
-			}
-		}
-		errMsg = normalizedErr.GetMsg()
-	} else {
-		rfcCode = errors.ErrUnknown.RFCCode()
-		errMsg = errIn.Error()
-	}
-
-	code := errors.GRPCStatusCode(errIn)
-	st, err := status.New(code, errMsg).
-		WithDetails(&errdetails.ErrorInfo{
-			Reason:   string(rfcCode),
-			Metadata: metadata,
-		})
-	if err != nil {
-		return status.New(code, errMsg).Err()
-	}
-	return st.Err()
+	return errors.ToGRPCError(errIn)
 }
 
 // FromGRPCError converts a gRPC error to a normalized error.
 func FromGRPCError(errIn error) error {
-	if errIn == nil {
-		return nil
-	}
-	st, ok := status.FromError(errIn)
-	if !ok {
-		return errIn
-	}
-	var errInfo *errdetails.ErrorInfo
-	for _, detail := range st.Details() {
-		if ei, ok := detail.(*errdetails.ErrorInfo); ok {
-			errInfo = ei
-			break
-		}
-	}
-	if errInfo == nil || errInfo.Reason == "" {
-		return errors.ErrUnknown.GenWithStack(st.Message())
-	}
-
-	normalizedErr := perrors.Normalize(st.Message(), perrors.RFCCodeText(errInfo.Reason))
-	if causeMsg := errInfo.Metadata[metadataCauseKey]; causeMsg != "" {
-		return normalizedErr.Wrap(perrors.New(causeMsg)).GenWithStackByArgs()
-	}
-	return normalizedErr.GenWithStackByArgs()
+	return errors.FromGRPCError(errIn)
 }
 
 // ForwardToLeader is a gRPC middleware that forwards the request to the leader if the current node is not the leader.
@@ -47,6 +47,8 @@ type ChangefeedInterface interface {
 	Get(ctx context.Context, namespace string, name string) (*v2.ChangeFeedInfo, error)
 	// List lists all changefeeds
 	List(ctx context.Context, namespace string, state string) ([]v2.ChangefeedCommonInfo, error)
+	// DDLHistory lists the DDLs executed by a changefeed
+	DDLHistory(ctx context.Context, namespace string, name string) ([]v2.DDLHistoryEntry, error)
 }
 
 // changefeeds implements ChangefeedInterface
@@ -157,3 +159,16 @@ func (c *changefeeds) List(ctx context.Context,
 		Into(result)
 	return result.Items, err
 }
+
+// DDLHistory lists the DDLs executed by a changefeed
+func (c *changefeeds) DDLHistory(ctx context.Context,
+	namespace string, name string,
+) ([]v2.DDLHistoryEntry, error) {
+	result := &v2.ListResponse[v2.DDLHistoryEntry]{}
+	u := fmt.Sprintf("changefeeds/%s/ddl_history?namespace=%s", name, namespace)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result.Items, err
+}
@@ -88,6 +88,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Create(ctx, cfg interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockChangefeedInterface)(nil).Create), ctx, cfg)
 }
 
+// DDLHistory mocks base method.
+func (m *MockChangefeedInterface) DDLHistory(ctx context.Context, namespace, name string) ([]v2.DDLHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DDLHistory", ctx, namespace, name)
+	ret0, _ := ret[0].([]v2.DDLHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DDLHistory indicates an expected call of DDLHistory.
+func (mr *MockChangefeedInterfaceMockRecorder) DDLHistory(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DDLHistory", reflect.TypeOf((*MockChangefeedInterface)(nil).DDLHistory), ctx, namespace, name)
+}
+
 // Delete mocks base method.
 func (m *MockChangefeedInterface) Delete(ctx context.Context, namespace, name string) error {
 	m.ctrl.T.Helper()
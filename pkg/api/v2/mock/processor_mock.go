@@ -102,3 +102,18 @@ func (mr *MockProcessorInterfaceMockRecorder) List(ctx interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProcessorInterface)(nil).List), ctx)
 }
+
+// TableStats mocks base method.
+func (m *MockProcessorInterface) TableStats(ctx context.Context, namespace, changefeedID, captureID string) ([]v2.TableStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TableStats", ctx, namespace, changefeedID, captureID)
+	ret0, _ := ret[0].([]v2.TableStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TableStats indicates an expected call of TableStats.
+func (mr *MockProcessorInterfaceMockRecorder) TableStats(ctx, namespace, changefeedID, captureID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TableStats", reflect.TypeOf((*MockProcessorInterface)(nil).TableStats), ctx, namespace, changefeedID, captureID)
+}
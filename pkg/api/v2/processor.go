@@ -31,8 +31,12 @@ type ProcessorsGetter interface {
 type ProcessorInterface interface {
 	Get(ctx context.Context, namespace string, changefeedID, captureID string) (*v2.ProcessorDetail, error)
 	List(ctx context.Context) ([]v2.ProcessorCommonInfo, error)
+	TableStats(ctx context.Context, namespace string, changefeedID, captureID string) ([]v2.TableStats, error)
 }
 
+// tableStatsPageSize is the max page size of the table statistics.
+const tableStatsPageSize = 1000
+
 // processors implements ProcessorInterface.
 type processors struct {
 	client rest.CDCRESTInterface
@@ -70,3 +74,30 @@ func (p *processors) Get(
 		Into(result)
 	return result, err
 }
+
+// TableStats returns the replication statistics of all the tables of the
+// processor with given `changefeedID` and `captureID`.
+func (p *processors) TableStats(
+	ctx context.Context,
+	namespace,
+	changefeedID,
+	captureID string,
+) ([]v2.TableStats, error) {
+	var tables []v2.TableStats
+	for page := 1; ; page++ {
+		result := &v2.GetTableStatsResponse{}
+		u := fmt.Sprintf("processors/%s/%s/tables/stats?namespace=%s&page=%d&page_size=%d",
+			changefeedID, captureID, namespace, page, tableStatsPageSize)
+		err := p.client.Get().
+			WithURI(u).
+			Do(ctx).
+			Into(result)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, result.Items...)
+		if len(result.Items) == 0 || len(tables) >= result.Total {
+			return tables, nil
+		}
+	}
+}
@@ -58,6 +58,9 @@ type RedoApplierConfig struct {
 	SinkURI string
 	Storage string
 	Dir     string
+	// OnProgress is called with the commit ts of the events sent to the
+	// downstream, it's optional.
+	OnProgress func(appliedTs model.Ts)
 }
 
 // RedoApplier implements a redo log applier
@@ -79,6 +82,11 @@ type RedoApplier struct {
 	tableResolvedTsMap map[model.TableID]*memquota.MemConsumeRecord
 	appliedLogCount    uint64
 
+	// targetTs is the commit ts up to which the redo logs are applied in the
+	// point-in-time recovery mode, 0 means all the redo logs are applied.
+	targetTs   model.Ts
+	progressTs model.Ts
+
 	errCh chan error
 
 	// changefeedID is used to identify the changefeed that this applier belongs to.
@@ -160,9 +168,19 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if ra.targetTs != 0 {
+		if ra.targetTs < checkpointTs || ra.targetTs > resolvedTs {
+			return errors.ErrRedoTargetTsOutOfRange.GenWithStackByArgs(
+				ra.targetTs, checkpointTs, resolvedTs)
+		}
+		// The events committed after targetTs are not applied, so the
+		// downstream is consistent at targetTs.
+		resolvedTs = ra.targetTs
+	}
 	log.Info("apply redo log starts",
 		zap.Uint64("checkpointTs", checkpointTs),
-		zap.Uint64("resolvedTs", resolvedTs))
+		zap.Uint64("resolvedTs", resolvedTs),
+		zap.Uint64("targetTs", ra.targetTs))
 	if err := ra.initSink(ctx); err != nil {
 		return err
 	}
@@ -180,11 +198,29 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 		return row.CommitTs > ddl.CommitTs
 	}
 
-	row, err := ra.rd.ReadNextRow(ctx)
+	// The redo logs of all segments are merged by the reader in the order of
+	// commit ts, so the events committed after resolvedTs can be discarded as
+	// soon as the first one is read, no matter which segment it comes from.
+	readNextRow := func() (*model.RowChangedEvent, error) {
+		row, err := ra.rd.ReadNextRow(ctx)
+		if err != nil || row == nil || row.CommitTs > resolvedTs {
+			return nil, err
+		}
+		return row, nil
+	}
+	readNextDDL := func() (*model.DDLEvent, error) {
+		ddl, err := ra.rd.ReadNextDDL(ctx)
+		if err != nil || ddl == nil || ddl.CommitTs > resolvedTs {
+			return nil, err
+		}
+		return ddl, nil
+	}
+
+	row, err := readNextRow()
 	if err != nil {
 		return err
 	}
-	ddl, err := ra.rd.ReadNextDDL(ctx)
+	ddl, err := readNextDDL()
 	if err != nil {
 		return err
 	}
@@ -196,14 +232,16 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 			if err := ra.applyDDL(ctx, ddl, checkpointTs); err != nil {
 				return err
 			}
-			if ddl, err = ra.rd.ReadNextDDL(ctx); err != nil {
+			ra.reportProgress(ddl.CommitTs)
+			if ddl, err = readNextDDL(); err != nil {
 				return err
 			}
 		} else {
 			if err := ra.applyRow(row, checkpointTs); err != nil {
 				return err
 			}
-			if row, err = ra.rd.ReadNextRow(ctx); err != nil {
+			ra.reportProgress(row.CommitTs)
+			if row, err = readNextRow(); err != nil {
 				return err
 			}
 		}
@@ -215,6 +253,7 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 		}
 		ra.tableSinks[tableID].Close()
 	}
+	ra.reportProgress(resolvedTs)
 
 	log.Info("apply redo log finishes",
 		zap.Uint64("appliedLogCount", ra.appliedLogCount),
@@ -223,6 +262,15 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	return errApplyFinished
 }
 
+// reportProgress calls the OnProgress callback if the applied ts advances.
+func (ra *RedoApplier) reportProgress(appliedTs model.Ts) {
+	if ra.cfg.OnProgress == nil || appliedTs <= ra.progressTs {
+		return
+	}
+	ra.progressTs = appliedTs
+	ra.cfg.OnProgress(appliedTs)
+}
+
 func (ra *RedoApplier) resetQuota(rowSize uint64) error {
 	if rowSize >= config.DefaultChangefeedMemoryQuota || rowSize < ra.pendingQuota {
 		log.Panic("row size exceeds memory quota",
@@ -459,3 +507,18 @@ func (ra *RedoApplier) Apply(egCtx context.Context) (err error) {
 	}
 	return nil
 }
+
+// PITRReplay applies the DDL and DML events with commit ts less than or equal
+// to targetTs to the downstream, which restores the downstream to the state of
+// the upstream at targetTs. targetTs must be in the range of
+// [checkpointTs, resolvedTs] of the redo logs.
+func (ra *RedoApplier) PITRReplay(ctx context.Context, targetTs model.Ts, downstreamURI string) error {
+	if targetTs == 0 {
+		return errors.ErrRedoConfigInvalid.GenWithStack("target ts must be specified")
+	}
+	cfg := *ra.cfg
+	cfg.SinkURI = downstreamURI
+	ra.cfg = &cfg
+	ra.targetTs = targetTs
+	return ra.Apply(ctx)
+}
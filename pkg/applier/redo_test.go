@@ -286,3 +286,180 @@ func getMockDB(t *testing.T) *sql.DB {
 	mock.ExpectClose()
 	return db
 }
+
+func TestPITRReplay(t *testing.T) {
+	checkpointTs := uint64(1000)
+	tableInfo := model.BuildTableInfo("test", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysqlParser.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		}, {
+			Name: "b",
+			Type: mysqlParser.TypeString,
+			Flag: 0,
+		},
+	}, [][]int{{0}})
+	newRow := func(a int, b string) []*model.ColumnData {
+		return model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: a},
+			{Name: "b", Value: b},
+		}, tableInfo)
+	}
+	// The i-th step is committed at checkpointTs+i.
+	steps := []struct {
+		insert bool
+		a      int
+		b      string
+	}{
+		{true, 1, "a"},
+		{true, 2, "b"},
+		{false, 1, "a"},
+		{true, 3, "c"},
+		{true, 1, "d"},
+		{false, 2, "b"},
+		{true, 4, "e"},
+		{false, 3, "c"},
+		{false, 4, "e"},
+		{true, 5, "f"},
+	}
+	// expected[i] is the state of the downstream at checkpointTs+i.
+	expected := []map[int]string{
+		{},
+		{1: "a"},
+		{1: "a", 2: "b"},
+		{2: "b"},
+		{2: "b", 3: "c"},
+		{1: "d", 2: "b", 3: "c"},
+		{1: "d", 3: "c"},
+		{1: "d", 3: "c", 4: "e"},
+		{1: "d", 4: "e"},
+		{1: "d"},
+		{1: "d", 5: "f"},
+	}
+	resolvedTs := checkpointTs + uint64(len(steps))
+
+	for i := range expected {
+		targetTs := checkpointTs + uint64(i)
+		redoLogCh := make(chan *model.RowChangedEvent, len(steps))
+		for j, step := range steps {
+			row := &model.RowChangedEvent{
+				StartTs:   checkpointTs + uint64(j),
+				CommitTs:  checkpointTs + uint64(j) + 1,
+				TableInfo: tableInfo,
+			}
+			if step.insert {
+				row.Columns = newRow(step.a, step.b)
+			} else {
+				row.PreColumns = newRow(step.a, step.b)
+			}
+			redoLogCh <- row
+		}
+		close(redoLogCh)
+		ddlEventCh := make(chan *model.DDLEvent)
+		close(ddlEventCh)
+
+		// Only the steps committed before or at targetTs are expected to be
+		// applied, and they make up the state at targetTs.
+		db, mock := getMockDBWithoutEvents(t)
+		state := make(map[int]string)
+		for _, step := range steps[:i] {
+			mock.ExpectBegin()
+			if step.insert {
+				mock.ExpectExec("REPLACE INTO `test`.`t1` (`a`,`b`) VALUES (?,?)").
+					WithArgs(step.a, step.b).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				state[step.a] = step.b
+			} else {
+				mock.ExpectExec("DELETE FROM `test`.`t1` WHERE (`a` = ?)").
+					WithArgs(step.a).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				delete(state, step.a)
+			}
+			mock.ExpectCommit()
+		}
+		mock.ExpectClose()
+		require.Equal(t, expected[i], state, "targetTs %d", targetTs)
+
+		var progress []uint64
+		cfg := &RedoApplierConfig{
+			OnProgress: func(appliedTs uint64) {
+				progress = append(progress, appliedTs)
+			},
+		}
+		err := replay(t, cfg, db, NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh),
+			func(ap *RedoApplier) error {
+				return ap.PITRReplay(context.Background(), targetTs,
+					"mysql://127.0.0.1:4000/?worker-count=1&max-txn-row=1"+
+						"&tidb_placement_mode=ignore&safe-mode=true&cache-prep-stmts=false"+
+						"&multi-stmt-enable=false")
+			})
+		require.NoError(t, err, "targetTs %d", targetTs)
+		require.NoError(t, mock.ExpectationsWereMet(), "targetTs %d", targetTs)
+		if i > 0 {
+			require.Len(t, progress, i)
+			require.Equal(t, targetTs, progress[len(progress)-1])
+		}
+	}
+
+	// The target ts must be in the range of the redo logs.
+	for _, targetTs := range []uint64{checkpointTs - 1, resolvedTs + 1} {
+		redoLogCh := make(chan *model.RowChangedEvent)
+		ddlEventCh := make(chan *model.DDLEvent)
+		db, _ := getMockDBWithoutEvents(t)
+		err := replay(t, &RedoApplierConfig{}, db,
+			NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh),
+			func(ap *RedoApplier) error {
+				return ap.PITRReplay(context.Background(), targetTs, "mysql://127.0.0.1:4000/")
+			})
+		require.Regexp(t, "CDC:ErrRedoTargetTsOutOfRange", err)
+	}
+}
+
+// replay runs fn with the redo applier reading logs from rd and writing to db.
+func replay(
+	t *testing.T, cfg *RedoApplierConfig, db *sql.DB, rd reader.RedoLogReader,
+	fn func(ap *RedoApplier) error,
+) error {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() {
+			dbIndex++
+		}()
+		if dbIndex%2 == 0 {
+			testDB, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return testDB, nil
+		}
+		return db, nil
+	}
+
+	getDMLDBConnBak := txn.GetDBConnImpl
+	txn.GetDBConnImpl = mockGetDBConn
+	getDDLDBConnBak := mysqlDDL.GetDBConnImpl
+	mysqlDDL.GetDBConnImpl = mockGetDBConn
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return rd, nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+		txn.GetDBConnImpl = getDMLDBConnBak
+		mysqlDDL.GetDBConnImpl = getDDLDBConnBak
+	}()
+	return fn(NewRedoApplier(cfg))
+}
+
+// getMockDBWithoutEvents returns a mock db expecting the queries run when
+// the sinks are created.
+func getMockDBWithoutEvents(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.Nil(t, err)
+	for i := 0; i < 4; i++ {
+		mock.ExpectQuery("select tidb_version()").WillReturnError(&mysql.MySQLError{
+			Number:  1305,
+			Message: "FUNCTION test.tidb_version does not exist",
+		})
+	}
+	return db, mock
+}
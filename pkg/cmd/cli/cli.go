@@ -22,16 +22,33 @@ import (
 
 // NewCmdCli creates the `cli` command.
 func NewCmdCli() *cobra.Command {
-	// Bind the certificate and log options.
-	cf := factory.NewClientFlags()
-
 	cmds := &cobra.Command{
 		Use:   "cli",
 		Short: "Manage replication task and TiCDC cluster",
 		Args:  cobra.NoArgs,
 	}
 
-	// Binding the `cli` command flags.
+	// Construct the client construction factory.
+	f := newClientFactory(cmds)
+
+	// Add subcommands.
+	cmds.AddCommand(newCmdCapture(f))
+	cmds.AddCommand(newCmdChangefeed(f))
+	cmds.AddCommand(newCmdProcessor(f))
+	cmds.AddCommand(newCmdTso(f))
+	cmds.AddCommand(newCmdUnsafe(f))
+	cmds.AddCommand(newConfigureCredentials())
+
+	return cmds
+}
+
+// newClientFactory binds the certificate and log options to cmds, and
+// returns the factory of the clients connecting to the TiCDC cluster.
+func newClientFactory(cmds *cobra.Command) factory.Factory {
+	// Bind the certificate and log options.
+	cf := factory.NewClientFlags()
+
+	// Binding the command flags.
 	cf.AddFlags(cmds)
 	cmds.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		// Here we will initialize the logging configuration and set the current default context.
@@ -48,16 +65,5 @@ func NewCmdCli() *cobra.Command {
 		util.CheckErr(cf.CompleteClientAuthParameters(cmd))
 	}
 
-	// Construct the client construction factory.
-	f := factory.NewFactory(cf)
-
-	// Add subcommands.
-	cmds.AddCommand(newCmdCapture(f))
-	cmds.AddCommand(newCmdChangefeed(f))
-	cmds.AddCommand(newCmdProcessor(f))
-	cmds.AddCommand(newCmdTso(f))
-	cmds.AddCommand(newCmdUnsafe(f))
-	cmds.AddCommand(newConfigureCredentials())
-
-	return cmds
+	return factory.NewFactory(cf)
 }
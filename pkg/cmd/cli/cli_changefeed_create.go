@@ -103,11 +103,13 @@ type createChangefeedOptions struct {
 	commonChangefeedOptions *changefeedCommonOptions
 	apiClient               apiv2client.APIV2Interface
 
-	changefeedID            string
-	namespace               string
-	disableGCSafePointCheck bool
-	startTs                 uint64
-	timezone                string
+	changefeedID             string
+	namespace                string
+	disableGCSafePointCheck  bool
+	startTs                  uint64
+	timezone                 string
+	upstreamDSN              string
+	validateDownstreamSchema bool
 
 	cfg *config.ReplicaConfig
 }
@@ -128,6 +130,10 @@ func (o *createChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.disableGCSafePointCheck, "disable-gc-check", "", false, "Disable GC safe point check")
 	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "Start ts of changefeed")
 	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
+	cmd.PersistentFlags().StringVar(&o.upstreamDSN, "upstream-dsn", "",
+		"DSN of the upstream TiDB, the tables matched by the filter rules are validated against it if it's set")
+	cmd.PersistentFlags().BoolVar(&o.validateDownstreamSchema, "validate-downstream-schema", false,
+		"Validate the tables on the downstream as well, requires --upstream-dsn and a MySQL compatible sink")
 	// we don't support specify these flags below when cdc version >= 6.2.0
 	_ = cmd.PersistentFlags().MarkHidden("tz")
 }
@@ -222,6 +228,9 @@ func (o *createChangefeedOptions) getChangefeedConfig() *v2.ChangefeedConfig {
 		SinkURI:       o.commonChangefeedOptions.sinkURI,
 		ReplicaConfig: replicaConfig,
 		PDConfig:      upstreamConfig.PDConfig,
+
+		UpstreamDSN:              o.upstreamDSN,
+		ValidateDownstreamSchema: o.validateDownstreamSchema,
 	}
 }
 
@@ -333,8 +342,15 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 			cmd.Println(fmt.Sprintf("Invalid input, 'ignore-event' parameters can only accept [%s]",
 				strings.Join(eventTypesStr, ", ")))
 		}
+		if strings.Contains(err.Error(), "ErrSchemaValidationFailed") {
+			cmd.Println("[ERROR] Schema validation failed, " +
+				"please fix all the problems listed below and create the changefeed again")
+		}
 		return err
 	}
+	if o.upstreamDSN != "" {
+		cmd.Println("Schema validation passed")
+	}
 	infoStr, err := info.Marshal()
 	if err != nil {
 		return err
@@ -14,6 +14,7 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -169,3 +171,35 @@ func TestChangefeedCreateCli(t *testing.T) {
 	require.NoError(t, o.complete(f))
 	require.Contains(t, o.validate(cmd).Error(), "creating changefeed with `--sort-dir`")
 }
+
+func TestChangefeedCreateValidateSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	cmd := newCmdCreateChangefeed(f)
+	os.Args = []string{
+		"create",
+		"--no-confirm=true",
+		"--sink-uri=mysql://root@127.0.0.1:3306/",
+		"--changefeed-id=abc",
+		"--upstream-dsn=root@tcp(127.0.0.1:4000)/",
+		"--validate-downstream-schema",
+	}
+	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(&v2.Tso{
+		Timestamp: time.Now().Unix() * 1000,
+	}, nil)
+	f.changefeeds.EXPECT().VerifyTable(gomock.Any(), gomock.Any()).Return(&v2.Tables{}, nil)
+	f.changefeeds.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error) {
+			require.Equal(t, "root@tcp(127.0.0.1:4000)/", cfg.UpstreamDSN)
+			require.True(t, cfg.ValidateDownstreamSchema)
+			return nil, errors.New("[CDC:ErrSchemaValidationFailed]schema validation failed, " +
+				"1 problem(s) found:\n`test`.`t1`: table doesn't exist on the downstream")
+		})
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "`test`.`t1`: table doesn't exist on the downstream")
+	require.Contains(t, b.String(), "[ERROR] Schema validation failed")
+}
@@ -45,6 +45,10 @@ type cfMeta struct {
 	ErrorHis       []int64                   `json:"error_history,omitempty"`
 	CreatorVersion string                    `json:"creator_version"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+	ScanProgress   *v2.ScanProgress          `json:"scan_progress,omitempty"`
+
+	ReadAmplification *v2.ReadAmplification `json:"read_amplification,omitempty"`
+	Queued            bool                  `json:"queued,omitempty"`
 }
 
 // queryChangefeedOptions defines flags for the `cli changefeed query` command.
@@ -115,6 +119,10 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		RunningError:   detail.Error,
 		CreatorVersion: detail.CreatorVersion,
 		TaskStatus:     detail.TaskStatus,
+		ScanProgress:   detail.ScanProgress,
+
+		ReadAmplification: detail.ReadAmplification,
+		Queued:            detail.Queued,
 	}
 	return util.JSONPrint(cmd, meta)
 }
@@ -0,0 +1,292 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	_ "github.com/go-sql-driver/mysql" // mysql driver
+	"github.com/pingcap/errors"
+	tfilter "github.com/pingcap/tidb/pkg/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/spf13/cobra"
+)
+
+// tableChecksum is the row count and checksum of a table.
+type tableChecksum struct {
+	Count    int64  `json:"count"`
+	Checksum uint64 `json:"checksum"`
+}
+
+// tableValidation is the validation result of a table.
+type tableValidation struct {
+	Table      string        `json:"table"`
+	Upstream   tableChecksum `json:"upstream"`
+	Downstream tableChecksum `json:"downstream"`
+	Consistent bool          `json:"consistent"`
+}
+
+// validationReport is the validation result of a changefeed.
+type validationReport struct {
+	Namespace          string             `json:"namespace"`
+	ID                 string             `json:"id"`
+	CheckpointTs       uint64             `json:"checkpoint_ts"`
+	Tables             []*tableValidation `json:"tables"`
+	InconsistentTables []string           `json:"inconsistent_tables"`
+}
+
+// validateChangefeedOptions defines flags for the `cli changefeed validate` command.
+type validateChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace       string
+	changefeedID    string
+	upstreamTiDBDSN string
+	downstreamDSN   string
+	tableFilter     []string
+
+	// openDB opens a database by the dsn, it's replaced in tests.
+	openDB func(dsn string) (*sql.DB, error)
+}
+
+// newValidateChangefeedOptions creates new options for the `cli changefeed validate` command.
+func newValidateChangefeedOptions() *validateChangefeedOptions {
+	return &validateChangefeedOptions{
+		openDB: func(dsn string) (*sql.DB, error) {
+			return sql.Open("mysql", dsn)
+		},
+	}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *validateChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.upstreamTiDBDSN, "upstream-tidb-dsn", "", "DSN of the upstream TiDB")
+	cmd.PersistentFlags().StringVar(&o.downstreamDSN, "downstream-dsn", "", "DSN of the downstream MySQL or TiDB")
+	cmd.PersistentFlags().StringSliceVar(&o.tableFilter, "table-filter", []string{"*.*"}, "Filter rules of the tables to validate")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("upstream-tidb-dsn")
+	_ = cmd.MarkPersistentFlagRequired("downstream-dsn")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *validateChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed validate` command.
+func (o *validateChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.Background()
+
+	tableFilter, err := tfilter.Parse(o.tableFilter)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrFilterRuleInvalid, err, o.tableFilter)
+	}
+	detail, err := o.apiClient.Changefeeds().Get(ctx, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	replicaConfig := detail.Config.ToInternalReplicaConfig()
+	changefeedFilter, err := filter.NewFilter(replicaConfig, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !replicaConfig.CaseSensitive {
+		tableFilter = tfilter.CaseInsensitive(tableFilter)
+	}
+	if detail.State == model.StateNormal {
+		cmd.Printf(color.HiYellowString("[WARN] changefeed %s is running, the downstream "+
+			"may be ahead of the checkpoint, please pause it for an accurate result.\n", o.changefeedID))
+	}
+
+	upstream, err := o.openDB(o.upstreamTiDBDSN)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer upstream.Close()
+	downstream, err := o.openDB(o.downstreamDSN)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer downstream.Close()
+
+	tables, err := listTables(ctx, upstream, func(schema, table string) bool {
+		return tableFilter.MatchTable(schema, table) &&
+			!changefeedFilter.ShouldIgnoreTable(schema, table)
+	})
+	if err != nil {
+		return err
+	}
+
+	report := &validationReport{
+		Namespace:          o.namespace,
+		ID:                 o.changefeedID,
+		CheckpointTs:       detail.CheckpointTs,
+		Tables:             make([]*tableValidation, 0, len(tables)),
+		InconsistentTables: make([]string, 0),
+	}
+	for _, table := range tables {
+		result, err := validateTable(ctx, upstream, downstream, table, detail.CheckpointTs)
+		if err != nil {
+			return err
+		}
+		report.Tables = append(report.Tables, result)
+		if !result.Consistent {
+			report.InconsistentTables = append(report.InconsistentTables, result.Table)
+		}
+	}
+	if err := util.JSONPrint(cmd, report); err != nil {
+		return err
+	}
+	if len(report.InconsistentTables) > 0 {
+		return errors.Errorf("%d tables are inconsistent: %s",
+			len(report.InconsistentTables), strings.Join(report.InconsistentTables, ", "))
+	}
+	return nil
+}
+
+// listTables lists the tables in the upstream that match the filter.
+func listTables(
+	ctx context.Context, db *sql.DB, match func(schema, table string) bool,
+) ([]model.TableName, error) {
+	rows, err := db.QueryContext(ctx, "SELECT TABLE_SCHEMA, TABLE_NAME FROM "+
+		"information_schema.tables WHERE TABLE_TYPE = 'BASE TABLE' "+
+		"ORDER BY TABLE_SCHEMA, TABLE_NAME")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer rows.Close()
+
+	var tables []model.TableName
+	for rows.Next() {
+		var schema, table string
+		if err := rows.Scan(&schema, &table); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if match(schema, table) {
+			tables = append(tables, model.TableName{Schema: schema, Table: table})
+		}
+	}
+	return tables, errors.Trace(rows.Err())
+}
+
+// validateTable compares the row count and checksum of the table between
+// the upstream at checkpointTs and the downstream.
+func validateTable(
+	ctx context.Context, upstream, downstream *sql.DB,
+	table model.TableName, checkpointTs uint64,
+) (*tableValidation, error) {
+	columns, err := listColumns(ctx, upstream, table)
+	if err != nil {
+		return nil, err
+	}
+	result := &tableValidation{Table: table.String()}
+	result.Upstream, err = queryChecksum(ctx, upstream,
+		buildChecksumQuery(table, columns, checkpointTs))
+	if err != nil {
+		return nil, err
+	}
+	result.Downstream, err = queryChecksum(ctx, downstream,
+		buildChecksumQuery(table, columns, 0))
+	if err != nil {
+		return nil, err
+	}
+	result.Consistent = result.Upstream == result.Downstream
+	return result, nil
+}
+
+func listColumns(ctx context.Context, db *sql.DB, table model.TableName) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT COLUMN_NAME FROM information_schema.columns "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+		table.Schema, table.Table)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, errors.Trace(err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, errors.Trace(rows.Err())
+}
+
+// buildChecksumQuery builds the query to calculate the row count and checksum
+// of the table, the table is read at asOfTs if it's not 0.
+func buildChecksumQuery(table model.TableName, columns []string, asOfTs uint64) string {
+	quoted := make([]string, 0, len(columns))
+	isNulls := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, quotes.QuoteName(column))
+		isNulls = append(isNulls, fmt.Sprintf("ISNULL(%s)", quotes.QuoteName(column)))
+	}
+	// CONCAT_WS skips NULL values, so the NULL flags of all columns are
+	// appended to tell NULL from an empty string.
+	query := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(CRC32(CONCAT_WS(',', %s, CONCAT(%s)))), 0) FROM %s",
+		strings.Join(quoted, ", "), strings.Join(isNulls, ", "),
+		quotes.QuoteSchema(table.Schema, table.Table))
+	if asOfTs != 0 {
+		query += fmt.Sprintf(" AS OF TIMESTAMP TIDB_PARSE_TSO(%d)", asOfTs)
+	}
+	return query
+}
+
+func queryChecksum(ctx context.Context, db *sql.DB, query string) (tableChecksum, error) {
+	var result tableChecksum
+	err := db.QueryRowContext(ctx, query).Scan(&result.Count, &result.Checksum)
+	return result, errors.Trace(err)
+}
+
+// newCmdValidateChangefeed creates the `cli changefeed validate` command.
+func newCmdValidateChangefeed(f factory.Factory) *cobra.Command {
+	o := newValidateChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate data between the upstream and the downstream of a replication task (changefeed)",
+		Long: "Compare the row count and checksum of each replicated table between the " +
+			"upstream TiDB at the checkpoint of the changefeed and the downstream, " +
+			"the tables whose row count or checksum differ are reported.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
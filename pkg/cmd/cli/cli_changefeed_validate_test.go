@@ -0,0 +1,107 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChecksumQuery(t *testing.T) {
+	table := model.TableName{Schema: "test", Table: "t1"}
+	require.Equal(t, "SELECT COUNT(*), COALESCE(SUM(CRC32(CONCAT_WS(',', `a`, `b`, "+
+		"CONCAT(ISNULL(`a`), ISNULL(`b`))))), 0) FROM `test`.`t1` AS OF TIMESTAMP TIDB_PARSE_TSO(100)",
+		buildChecksumQuery(table, []string{"a", "b"}, 100))
+	require.Equal(t, "SELECT COUNT(*), COALESCE(SUM(CRC32(CONCAT_WS(',', `a`, "+
+		"CONCAT(ISNULL(`a`))))), 0) FROM `test`.`t1`",
+		buildChecksumQuery(table, []string{"a"}, 0))
+}
+
+func TestChangefeedValidateCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeeds: cfV2}
+	cmd := newCmdValidateChangefeed(f)
+
+	upstream, upMock, err := sqlmock.New()
+	require.Nil(t, err)
+	downstream, downMock, err := sqlmock.New()
+	require.Nil(t, err)
+
+	o := newValidateChangefeedOptions()
+	require.Nil(t, o.complete(f))
+	o.changefeedID = "abc"
+	o.upstreamTiDBDSN = "upstream"
+	o.downstreamDSN = "downstream"
+	o.tableFilter = []string{"test.*", "!test.ignored"}
+	o.openDB = func(dsn string) (*sql.DB, error) {
+		if dsn == "upstream" {
+			return upstream, nil
+		}
+		return downstream, nil
+	}
+
+	cfV2.EXPECT().Get(gomock.Any(), gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+		ID:           "abc",
+		CheckpointTs: 100,
+		State:        model.StateStopped,
+		Config:       v2.GetDefaultReplicaConfig(),
+	}, nil)
+	upMock.ExpectQuery("SELECT TABLE_SCHEMA, TABLE_NAME FROM information_schema.tables").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME"}).
+			AddRow("mysql", "user").
+			AddRow("test", "ignored").
+			AddRow("test", "t1").
+			AddRow("test", "t2"))
+	for _, table := range []string{"t1", "t2"} {
+		upMock.ExpectQuery("SELECT COLUMN_NAME FROM information_schema.columns").
+			WithArgs("test", table).
+			WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}).AddRow("id"))
+		upMock.ExpectQuery(regexp.QuoteMeta(
+			"FROM `test`.`" + table + "` AS OF TIMESTAMP TIDB_PARSE_TSO(100)")).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "checksum"}).AddRow(2, 12345))
+	}
+	downMock.ExpectQuery(regexp.QuoteMeta("FROM `test`.`t1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "checksum"}).AddRow(2, 12345))
+	downMock.ExpectQuery(regexp.QuoteMeta("FROM `test`.`t2`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "checksum"}).AddRow(1, 345))
+	upMock.ExpectClose()
+	downMock.ExpectClose()
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Regexp(t, "1 tables are inconsistent: test.t2", o.run(cmd))
+	require.Nil(t, upMock.ExpectationsWereMet())
+	require.Nil(t, downMock.ExpectationsWereMet())
+
+	report := &validationReport{}
+	require.Nil(t, json.Unmarshal(b.Bytes(), report))
+	require.Equal(t, uint64(100), report.CheckpointTs)
+	require.Len(t, report.Tables, 2)
+	require.True(t, report.Tables[0].Consistent)
+	require.Equal(t, tableChecksum{Count: 2, Checksum: 12345}, report.Tables[0].Downstream)
+	require.False(t, report.Tables[1].Consistent)
+	require.Equal(t, []string{"test.t2"}, report.InconsistentTables)
+}
@@ -0,0 +1,410 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diagnoseSectionChangefeeds   = "changefeeds"
+	diagnoseSectionOwner         = "owner"
+	diagnoseSectionProcessors    = "processors"
+	diagnoseSectionLaggingTables = "lagging-tables"
+	diagnoseSectionDDLHistory    = "ddl-history"
+	diagnoseSectionRuntime       = "runtime"
+	diagnoseSectionVersion       = "version"
+
+	// diagnoseLaggingTables is the number of the most lagging tables collected.
+	diagnoseLaggingTables = 10
+	// diagnoseDDLEvents is the number of the latest DDL events collected.
+	diagnoseDDLEvents = 20
+)
+
+// diagnoseSections are all the sections of the diagnostic dump in order.
+var diagnoseSections = []string{
+	diagnoseSectionChangefeeds,
+	diagnoseSectionOwner,
+	diagnoseSectionProcessors,
+	diagnoseSectionLaggingTables,
+	diagnoseSectionDDLHistory,
+	diagnoseSectionRuntime,
+	diagnoseSectionVersion,
+}
+
+// nodeTables is the number of tables replicated by a node.
+type nodeTables struct {
+	CaptureID  string `json:"capture_id"`
+	Address    string `json:"address,omitempty"`
+	Processors int    `json:"processors"`
+	Tables     int    `json:"tables"`
+}
+
+// laggingTable is the replication statistics of a table with its processor.
+type laggingTable struct {
+	Namespace    string `json:"namespace"`
+	ChangefeedID string `json:"changefeed_id"`
+	CaptureID    string `json:"capture_id"`
+	v2.TableStats
+}
+
+// changefeedDDL is a DDL event executed by a changefeed.
+type changefeedDDL struct {
+	Namespace    string `json:"namespace"`
+	ChangefeedID string `json:"changefeed_id"`
+	v2.DDLHistoryEntry
+}
+
+// runtimeInfo is the runtime statistics of the server the command connects to.
+type runtimeInfo struct {
+	CaptureID  string `json:"capture_id"`
+	Goroutines int    `json:"goroutines"`
+	HeapInUse  uint64 `json:"heap_inuse"`
+}
+
+// versionInfo is the versions of the server and the command.
+type versionInfo struct {
+	Server        string `json:"server"`
+	ServerGitHash string `json:"server_git_hash"`
+	Client        string `json:"client"`
+	ClientGitHash string `json:"client_git_hash"`
+}
+
+// diagnoseReport is the diagnostic dump of a TiCDC cluster, the sections
+// which are not collected are omitted. The sections failed to collect are
+// recorded in Errors, so that the others are still dumped.
+type diagnoseReport struct {
+	CollectedAt   time.Time                 `json:"collected_at"`
+	Changefeeds   []v2.ChangefeedCommonInfo `json:"changefeeds,omitempty"`
+	Owner         *model.Capture            `json:"owner,omitempty"`
+	Processors    []nodeTables              `json:"processors,omitempty"`
+	LaggingTables []laggingTable            `json:"lagging_tables,omitempty"`
+	DDLHistory    []changefeedDDL           `json:"ddl_history,omitempty"`
+	Runtime       *runtimeInfo              `json:"runtime,omitempty"`
+	Version       *versionInfo              `json:"version,omitempty"`
+	Errors        map[string]string         `json:"errors,omitempty"`
+}
+
+// diagnoseOptions defines flags for the `diagnose` command.
+type diagnoseOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace string
+	sections  []string
+	output    string
+	timeout   time.Duration
+}
+
+// newDiagnoseOptions creates new options for the `diagnose` command.
+func newDiagnoseOptions() *diagnoseOptions {
+	return &diagnoseOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *diagnoseOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringSliceVar(&o.sections, "section", diagnoseSections,
+		"Sections to collect, can be "+strings.Join(diagnoseSections, ", "))
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "",
+		"Write the gzip compressed JSON to the file instead of stdout")
+	cmd.PersistentFlags().DurationVar(&o.timeout, "timeout", 30*time.Second,
+		"Timeout of collecting all the sections")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *diagnoseOptions) complete(f factory.Factory) error {
+	for _, section := range o.sections {
+		if !isDiagnoseSection(section) {
+			return errors.Errorf("invalid section %s, which must be one of %s",
+				section, strings.Join(diagnoseSections, ", "))
+		}
+	}
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func isDiagnoseSection(section string) bool {
+	for _, s := range diagnoseSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// run the `diagnose` command.
+func (o *diagnoseOptions) run(cmd *cobra.Command) error {
+	ctx, cancel := context.WithTimeout(cmdcontext.GetDefaultContext(), o.timeout)
+	defer cancel()
+
+	report := o.collect(ctx)
+	if o.output == "" {
+		return util.JSONPrint(cmd, report)
+	}
+	if err := writeDiagnoseReport(o.output, report); err != nil {
+		return err
+	}
+	cmd.Printf("Diagnostic dump is written to %s\n", o.output)
+	return nil
+}
+
+// collect collects the sections of the report. A failed section doesn't
+// stop collecting the others, since the dump is most useful when the
+// cluster is partially unavailable.
+func (o *diagnoseOptions) collect(ctx context.Context) *diagnoseReport {
+	report := &diagnoseReport{CollectedAt: time.Now()}
+	for _, section := range diagnoseSections {
+		if !o.hasSection(section) {
+			continue
+		}
+		var err error
+		switch section {
+		case diagnoseSectionChangefeeds:
+			report.Changefeeds, err = o.apiClient.Changefeeds().List(ctx, o.namespace, "all")
+		case diagnoseSectionOwner:
+			report.Owner, err = o.collectOwner(ctx)
+		case diagnoseSectionProcessors:
+			report.Processors, err = o.collectProcessors(ctx)
+		case diagnoseSectionLaggingTables:
+			report.LaggingTables, err = o.collectLaggingTables(ctx)
+		case diagnoseSectionDDLHistory:
+			report.DDLHistory, err = o.collectDDLHistory(ctx)
+		case diagnoseSectionRuntime, diagnoseSectionVersion:
+			err = o.collectStatus(ctx, section, report)
+		}
+		if err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[string]string)
+			}
+			report.Errors[section] = err.Error()
+		}
+	}
+	return report
+}
+
+func (o *diagnoseOptions) hasSection(section string) bool {
+	for _, s := range o.sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *diagnoseOptions) collectOwner(ctx context.Context) (*model.Capture, error) {
+	captures, err := o.apiClient.Captures().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range captures {
+		if captures[i].IsOwner {
+			return &captures[i], nil
+		}
+	}
+	return nil, errors.New("owner not found")
+}
+
+// listProcessors lists the processors of the changefeeds in the namespace.
+func (o *diagnoseOptions) listProcessors(ctx context.Context) ([]v2.ProcessorCommonInfo, error) {
+	processors, err := o.apiClient.Processors().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]v2.ProcessorCommonInfo, 0, len(processors))
+	for _, p := range processors {
+		if p.Namespace == o.namespace {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// collectProcessors collects the number of tables replicated by each node.
+func (o *diagnoseOptions) collectProcessors(ctx context.Context) ([]nodeTables, error) {
+	captures, err := o.apiClient.Captures().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	processors, err := o.listProcessors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*nodeTables, len(captures))
+	for _, capture := range captures {
+		nodes[capture.ID] = &nodeTables{CaptureID: capture.ID, Address: capture.AdvertiseAddr}
+	}
+	for _, p := range processors {
+		detail, err := o.apiClient.Processors().Get(ctx, p.Namespace, p.ChangeFeedID, p.CaptureID)
+		if err != nil {
+			return nil, err
+		}
+		node, ok := nodes[p.CaptureID]
+		if !ok {
+			node = &nodeTables{CaptureID: p.CaptureID}
+			nodes[p.CaptureID] = node
+		}
+		node.Processors++
+		node.Tables += len(detail.Tables)
+	}
+
+	result := make([]nodeTables, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, *node)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CaptureID < result[j].CaptureID })
+	return result, nil
+}
+
+// collectLaggingTables collects the most lagging tables of all processors.
+func (o *diagnoseOptions) collectLaggingTables(ctx context.Context) ([]laggingTable, error) {
+	processors, err := o.listProcessors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tables []laggingTable
+	for _, p := range processors {
+		stats, err := o.apiClient.Processors().TableStats(ctx, p.Namespace, p.ChangeFeedID, p.CaptureID)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range stats {
+			tables = append(tables, laggingTable{
+				Namespace:    p.Namespace,
+				ChangefeedID: p.ChangeFeedID,
+				CaptureID:    p.CaptureID,
+				TableStats:   table,
+			})
+		}
+	}
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].LagMs > tables[j].LagMs })
+	if len(tables) > diagnoseLaggingTables {
+		tables = tables[:diagnoseLaggingTables]
+	}
+	return tables, nil
+}
+
+// collectDDLHistory collects the latest DDL events of all changefeeds.
+func (o *diagnoseOptions) collectDDLHistory(ctx context.Context) ([]changefeedDDL, error) {
+	changefeeds, err := o.apiClient.Changefeeds().List(ctx, o.namespace, "all")
+	if err != nil {
+		return nil, err
+	}
+	var ddls []changefeedDDL
+	for _, cf := range changefeeds {
+		history, err := o.apiClient.Changefeeds().DDLHistory(ctx, cf.Namespace, cf.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range history {
+			ddls = append(ddls, changefeedDDL{
+				Namespace:       cf.Namespace,
+				ChangefeedID:    cf.ID,
+				DDLHistoryEntry: entry,
+			})
+		}
+	}
+	sort.SliceStable(ddls, func(i, j int) bool { return ddls[i].ExecutedAt.After(ddls[j].ExecutedAt) })
+	if len(ddls) > diagnoseDDLEvents {
+		ddls = ddls[:diagnoseDDLEvents]
+	}
+	return ddls, nil
+}
+
+// collectStatus collects the runtime or version section from the status of
+// the server the command connects to.
+func (o *diagnoseOptions) collectStatus(
+	ctx context.Context, section string, report *diagnoseReport,
+) error {
+	status, err := o.apiClient.Status().Get(ctx)
+	if err != nil {
+		return err
+	}
+	if section == diagnoseSectionRuntime {
+		report.Runtime = &runtimeInfo{
+			CaptureID:  status.ID,
+			Goroutines: status.Goroutines,
+			HeapInUse:  status.HeapInUse,
+		}
+		return nil
+	}
+	report.Version = &versionInfo{
+		Server:        status.Version,
+		ServerGitHash: status.GitHash,
+		Client:        version.ReleaseVersion,
+		ClientGitHash: version.GitHash,
+	}
+	return nil
+}
+
+// writeDiagnoseReport writes the report to the file as gzip compressed JSON.
+func writeDiagnoseReport(path string, report *diagnoseReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	writer := gzip.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		_ = file.Close()
+		return errors.Trace(err)
+	}
+	if err = writer.Close(); err != nil {
+		_ = file.Close()
+		return errors.Trace(err)
+	}
+	return errors.Trace(file.Close())
+}
+
+// NewCmdDiagnose creates the `diagnose` command.
+func NewCmdDiagnose() *cobra.Command {
+	o := newDiagnoseOptions()
+
+	command := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Collect the diagnostic information of the TiCDC cluster for bug reports",
+		Args:  cobra.NoArgs,
+	}
+	f := newClientFactory(command)
+	command.Run = func(cmd *cobra.Command, args []string) {
+		util.CheckErr(o.complete(f))
+		util.CheckErr(o.run(cmd))
+	}
+
+	o.addFlags(command)
+
+	return command
+}
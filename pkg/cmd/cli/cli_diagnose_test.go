@@ -0,0 +1,130 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseCollect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	o := newDiagnoseOptions()
+	o.namespace = "default"
+	o.sections = diagnoseSections
+	require.Nil(t, o.complete(f))
+
+	f.changefeeds.EXPECT().List(gomock.Any(), "default", "all").
+		Return([]v2.ChangefeedCommonInfo{{Namespace: "default", ID: "cf1"}}, nil).Times(2)
+	f.captures.EXPECT().List(gomock.Any()).Return([]model.Capture{
+		{ID: "c1", IsOwner: true, AdvertiseAddr: "127.0.0.1:8300"},
+		{ID: "c2", AdvertiseAddr: "127.0.0.1:8301"},
+	}, nil).Times(2)
+	f.processors.EXPECT().List(gomock.Any()).Return([]v2.ProcessorCommonInfo{
+		{Namespace: "default", ChangeFeedID: "cf1", CaptureID: "c1"},
+		{Namespace: "default", ChangeFeedID: "cf1", CaptureID: "c2"},
+		{Namespace: "other", ChangeFeedID: "cf2", CaptureID: "c2"},
+	}, nil).Times(2)
+	f.processors.EXPECT().Get(gomock.Any(), "default", "cf1", "c1").
+		Return(&v2.ProcessorDetail{Tables: []int64{1, 2}}, nil)
+	f.processors.EXPECT().Get(gomock.Any(), "default", "cf1", "c2").
+		Return(&v2.ProcessorDetail{Tables: []int64{3}}, nil)
+
+	var stats []v2.TableStats
+	for i := 0; i < 15; i++ {
+		stats = append(stats, v2.TableStats{TableID: int64(i), LagMs: int64(i * 100)})
+	}
+	f.processors.EXPECT().TableStats(gomock.Any(), "default", "cf1", "c1").Return(stats[:8], nil)
+	f.processors.EXPECT().TableStats(gomock.Any(), "default", "cf1", "c2").Return(stats[8:], nil)
+
+	now := time.Now()
+	var history []v2.DDLHistoryEntry
+	for i := 0; i < 25; i++ {
+		history = append(history, v2.DDLHistoryEntry{
+			ExecutedAt: now.Add(time.Duration(i) * time.Second),
+			Query:      fmt.Sprintf("CREATE TABLE t%d (a INT)", i),
+		})
+	}
+	f.changefeeds.EXPECT().DDLHistory(gomock.Any(), "default", "cf1").Return(history, nil)
+	// The status of the server is unavailable.
+	f.status.EXPECT().Get(gomock.Any()).Return(nil, errors.New("test")).Times(2)
+
+	report := o.collect(context.Background())
+	require.Len(t, report.Changefeeds, 1)
+	require.Equal(t, "c1", report.Owner.ID)
+	require.Equal(t, []nodeTables{
+		{CaptureID: "c1", Address: "127.0.0.1:8300", Processors: 1, Tables: 2},
+		{CaptureID: "c2", Address: "127.0.0.1:8301", Processors: 1, Tables: 1},
+	}, report.Processors)
+	require.Len(t, report.LaggingTables, diagnoseLaggingTables)
+	require.Equal(t, int64(14), report.LaggingTables[0].TableID)
+	require.Equal(t, "c2", report.LaggingTables[0].CaptureID)
+	require.Len(t, report.DDLHistory, diagnoseDDLEvents)
+	require.Equal(t, "CREATE TABLE t24 (a INT)", report.DDLHistory[0].Query)
+	require.Nil(t, report.Runtime)
+	require.Nil(t, report.Version)
+	require.Equal(t, map[string]string{
+		diagnoseSectionRuntime: "test",
+		diagnoseSectionVersion: "test",
+	}, report.Errors)
+}
+
+func TestDiagnoseSection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	o := newDiagnoseOptions()
+	o.sections = []string{"goroutines"}
+	require.Regexp(t, "invalid section goroutines", o.complete(f))
+
+	o.sections = []string{diagnoseSectionRuntime}
+	require.Nil(t, o.complete(f))
+	f.status.EXPECT().Get(gomock.Any()).Return(&model.ServerStatus{
+		ID:         "c1",
+		Goroutines: 100,
+		HeapInUse:  1024,
+	}, nil)
+	report := o.collect(context.Background())
+	require.Equal(t, &runtimeInfo{CaptureID: "c1", Goroutines: 100, HeapInUse: 1024}, report.Runtime)
+	require.Nil(t, report.Changefeeds)
+	require.Nil(t, report.Errors)
+
+	// The report is written as gzip compressed JSON.
+	path := filepath.Join(t.TempDir(), "diagnose.json.gz")
+	require.Nil(t, writeDiagnoseReport(path, report))
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	require.Nil(t, err)
+	decoded := &diagnoseReport{}
+	require.Nil(t, json.NewDecoder(reader).Decode(decoded))
+	require.Equal(t, report.Runtime, decoded.Runtime)
+}
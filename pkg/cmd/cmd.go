@@ -44,6 +44,7 @@ func Run() {
 
 	cmd.AddCommand(server.NewCmdServer())
 	cmd.AddCommand(cli.NewCmdCli())
+	cmd.AddCommand(cli.NewCmdDiagnose())
 	cmd.AddCommand(version.NewCmdVersion())
 	cmd.AddCommand(redo.NewCmdRedo())
 
@@ -25,7 +25,8 @@ import (
 // applyRedoOptions defines flags for the `redo apply` command.
 type applyRedoOptions struct {
 	options
-	sinkURI string
+	sinkURI  string
+	targetTs uint64
 }
 
 // newapplyRedoOptions creates new applyRedoOptions for the `redo apply` command.
@@ -37,6 +38,9 @@ func newapplyRedoOptions() *applyRedoOptions {
 // flags related to template printing to it.
 func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "target database sink-uri")
+	cmd.Flags().Uint64Var(&o.targetTs, "target-ts", 0,
+		"only apply the redo logs committed before or at this ts, "+
+			"which restores the target database to the state at this ts")
 	// the possible error returned from MarkFlagRequired is `no such flag`
 	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
 }
@@ -68,7 +72,15 @@ func (o *applyRedoOptions) run(cmd *cobra.Command) error {
 		Dir:     o.dir,
 	}
 	ap := applier.NewRedoApplier(cfg)
-	err := ap.Apply(ctx)
+	var err error
+	if o.targetTs != 0 {
+		cfg.OnProgress = func(appliedTs uint64) {
+			cmd.Printf("Applied redo log to ts %d, target ts %d\n", appliedTs, o.targetTs)
+		}
+		err = ap.PITRReplay(ctx, o.targetTs, o.sinkURI)
+	} else {
+		err = ap.Apply(ctx)
+	}
 	if err != nil {
 		return err
 	}
@@ -42,6 +42,9 @@ type options struct {
 	serverConfig         *config.ServerConfig
 	serverPdAddr         string
 	serverConfigFilePath string
+	// envOverrides are the environment variables which override the config,
+	// the sensitive values are masked.
+	envOverrides map[string]string
 
 	// TODO(hi-rustin): Consider using a client construction factory here.
 	caPath        string
@@ -61,8 +64,11 @@ func newOptions() *options {
 // flags related to template printing to it.
 func (o *options) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.serverConfig.ClusterID, "cluster-id", "default", "Set cdc cluster id")
+	cmd.Flags().StringVar(&o.serverConfig.Region, "region", o.serverConfig.Region, "Set the region label of the cdc server")
 	cmd.Flags().StringVar(&o.serverConfig.Addr, "addr", o.serverConfig.Addr, "Set the listening address")
 	cmd.Flags().StringVar(&o.serverConfig.AdvertiseAddr, "advertise-addr", o.serverConfig.AdvertiseAddr, "Set the advertise listening address for client communication")
+	cmd.Flags().BoolVar(&o.serverConfig.EnablePprof, "enable-pprof", o.serverConfig.EnablePprof, "Enable the pprof server listening on pprof-addr")
+	cmd.Flags().StringVar(&o.serverConfig.PprofAddr, "pprof-addr", o.serverConfig.PprofAddr, "Set the listening address of the pprof server, its port must differ from addr")
 
 	cmd.Flags().StringVar(&o.serverConfig.TZ, "tz", o.serverConfig.TZ, "Specify time zone of TiCDC cluster")
 	cmd.Flags().Int64Var(&o.serverConfig.GcTTL, "gc-ttl", o.serverConfig.GcTTL, "CDC GC safepoint TTL duration, specified in seconds")
@@ -104,6 +110,11 @@ func (o *options) run(cmd *cobra.Command) error {
 	})
 	defer cancel()
 
+	if len(o.envOverrides) > 0 {
+		log.Info("TiCDC server config is overridden by environment variables",
+			zap.Any("variables", o.envOverrides))
+	}
+
 	_, err := ticdcutil.GetTimezone(o.serverConfig.TZ)
 	if err != nil {
 		return errors.Annotate(err, "can not load timezone, Please specify the time zone through environment variable `TZ` or command line parameters `--tz`")
@@ -155,23 +166,35 @@ func (o *options) complete(cmd *cobra.Command) error {
 		if err := util.StrictDecodeFile(o.serverConfigFilePath, "TiCDC server", cfg, config.DebugConfigurationItem); err != nil {
 			return err
 		}
+	}
 
-		// User specified sort-dir should not take effect, it's always `/tmp/sorter`
-		// if user try to set sort-dir by config file, warn it.
-		if cfg.Sorter.SortDir != config.DefaultSortDir {
-			cmd.Printf(color.HiYellowString("[WARN] --sort-dir is deprecated in server settings. " +
-				"sort-dir will be set to `{data-dir}/tmp/sorter`. The sort-dir here will be no-op\n"))
+	// Environment variables take precedence over the config file.
+	envOverrides, err := util.OverrideConfigFromEnv(util.ServerConfigEnvPrefix, cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.envOverrides = envOverrides
 
-			cfg.Sorter.SortDir = config.DefaultSortDir
-		}
+	// User specified sort-dir should not take effect, it's always `/tmp/sorter`
+	// if user try to set sort-dir by config file or environment variables, warn it.
+	if cfg.Sorter.SortDir != config.DefaultSortDir {
+		cmd.Printf(color.HiYellowString("[WARN] --sort-dir is deprecated in server settings. " +
+			"sort-dir will be set to `{data-dir}/tmp/sorter`. The sort-dir here will be no-op\n"))
+
+		cfg.Sorter.SortDir = config.DefaultSortDir
 	}
 
+	pdChanged := false
 	cmd.Flags().Visit(func(flag *pflag.Flag) {
 		switch flag.Name {
 		case "addr":
 			cfg.Addr = o.serverConfig.Addr
 		case "advertise-addr":
 			cfg.AdvertiseAddr = o.serverConfig.AdvertiseAddr
+		case "enable-pprof":
+			cfg.EnablePprof = o.serverConfig.EnablePprof
+		case "pprof-addr":
+			cfg.PprofAddr = o.serverConfig.PprofAddr
 		case "tz":
 			cfg.TZ = o.serverConfig.TZ
 		case "gc-ttl":
@@ -204,13 +227,22 @@ func (o *options) complete(cmd *cobra.Command) error {
 			cfg.Sorter.SortDir = config.DefaultSortDir
 		case "cluster-id":
 			cfg.ClusterID = o.serverConfig.ClusterID
-		case "pd", "config":
+		case "region":
+			cfg.Region = o.serverConfig.Region
+		case "pd":
+			pdChanged = true
+		case "config":
 			// do nothing
 		default:
 			log.Panic("unknown flag, please report a bug", zap.String("flagName", flag.Name))
 		}
 	})
 
+	// The PD endpoints in the config file are used unless `--pd` is specified.
+	if !pdChanged && cfg.PDEndpoints != "" {
+		o.serverPdAddr = cfg.PDEndpoints
+	}
+
 	if err := cfg.ValidateAndAdjust(); err != nil {
 		return errors.Trace(err)
 	}
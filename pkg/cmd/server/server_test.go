@@ -126,6 +126,8 @@ func TestParseCfg(t *testing.T) {
 		"--key", "cc",
 		"--cert-allowed-cn", "dd,ee",
 		"--sort-dir", "/tmp/just_a_test",
+		"--enable-pprof",
+		"--pprof-addr", "127.5.5.1:6060",
 	}))
 
 	err := o.complete(cmd)
@@ -209,7 +211,13 @@ func TestParseCfg(t *testing.T) {
 				ResolvedTsStuckInterval:        config.TomlDuration(5 * time.Minute),
 			},
 		},
-		ClusterID: "default",
+		ClusterID:                        "default",
+		EnablePprof:                      true,
+		PprofAddr:                        "127.5.5.1:6060",
+		PprofRetentionSecs:               60,
+		GRPCKeepaliveInterval:            config.TomlDuration(30 * time.Second),
+		GRPCKeepaliveTimeout:             config.TomlDuration(10 * time.Second),
+		GRPCKeepalivePermitWithoutStream: true,
 	}, o.serverConfig)
 }
 
@@ -356,7 +364,12 @@ check-balance-interval = "10s"
 				ResolvedTsStuckInterval:        config.TomlDuration(5 * time.Minute),
 			},
 		},
-		ClusterID: "default",
+		ClusterID:                        "default",
+		PprofAddr:                        "127.0.0.1:6060",
+		PprofRetentionSecs:               60,
+		GRPCKeepaliveInterval:            config.TomlDuration(30 * time.Second),
+		GRPCKeepaliveTimeout:             config.TomlDuration(10 * time.Second),
+		GRPCKeepalivePermitWithoutStream: true,
 	}, o.serverConfig)
 }
 
@@ -495,10 +508,79 @@ cert-allowed-cn = ["dd","ee"]
 				ResolvedTsStuckInterval:        config.TomlDuration(5 * time.Minute),
 			},
 		},
-		ClusterID: "default",
+		ClusterID:                        "default",
+		PprofAddr:                        "127.0.0.1:6060",
+		PprofRetentionSecs:               60,
+		GRPCKeepaliveInterval:            config.TomlDuration(30 * time.Second),
+		GRPCKeepaliveTimeout:             config.TomlDuration(10 * time.Second),
+		GRPCKeepalivePermitWithoutStream: true,
 	}, o.serverConfig)
 }
 
+func TestDecodeCfgWithEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ticdc.toml")
+	configContent := `
+addr = "128.0.0.1:1234"
+log-level = "warn"
+[kv-client]
+worker-concurrent = 4
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0o644)
+	require.Nil(t, err)
+	t.Setenv("TICDC_LOG_LEVEL", "debug")
+	t.Setenv("TICDC_KV_CLIENT_WORKER_CONCURRENT", "16")
+	t.Setenv("TICDC_SECURITY_CERT_ALLOWED_CN", "client1,client2")
+	t.Setenv("TICDC_GC_TTL", "10")
+
+	cmd := new(cobra.Command)
+	o := newOptions()
+	o.addFlags(cmd)
+
+	// the command line flags take precedence over environment variables.
+	require.Nil(t, cmd.ParseFlags([]string{"--config", configPath, "--gc-ttl", "20"}))
+	require.Nil(t, o.complete(cmd))
+	require.Equal(t, "128.0.0.1:1234", o.serverConfig.Addr)
+	require.Equal(t, "debug", o.serverConfig.LogLevel)
+	require.Equal(t, uint(16), o.serverConfig.KVClient.WorkerConcurrent)
+	require.Equal(t, []string{"client1", "client2"}, o.serverConfig.Security.CertAllowedCN)
+	require.Equal(t, int64(20), o.serverConfig.GcTTL)
+	require.Equal(t, map[string]string{
+		"TICDC_LOG_LEVEL":                   "debug",
+		"TICDC_KV_CLIENT_WORKER_CONCURRENT": "16",
+		"TICDC_SECURITY_CERT_ALLOWED_CN":    "client1,client2",
+		"TICDC_GC_TTL":                      "10",
+	}, o.envOverrides)
+
+	t.Setenv("TICDC_KV_CLIENT_WORKER_CONCURRENT", "-1")
+	o = newOptions()
+	require.ErrorContains(t, o.complete(cmd), "TICDC_KV_CLIENT_WORKER_CONCURRENT")
+}
+
+func TestDecodeCfgWithPDEndpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ticdc.toml")
+	configContent := `pd-endpoints = "http://127.0.0.1:2379,http://127.0.0.1:2479"`
+	err := os.WriteFile(configPath, []byte(configContent), 0o644)
+	require.Nil(t, err)
+
+	cmd := new(cobra.Command)
+	o := newOptions()
+	o.addFlags(cmd)
+	require.Nil(t, cmd.ParseFlags([]string{"--config", configPath}))
+	require.Nil(t, o.complete(cmd))
+	require.Nil(t, o.validate())
+	require.Equal(t, "http://127.0.0.1:2379,http://127.0.0.1:2479", o.serverPdAddr)
+
+	// The `--pd` flag takes precedence over the config file.
+	cmd = new(cobra.Command)
+	o = newOptions()
+	o.addFlags(cmd)
+	require.Nil(t, cmd.ParseFlags([]string{"--config", configPath, "--pd", "http://127.0.0.1:2579"}))
+	require.Nil(t, o.complete(cmd))
+	require.Equal(t, "http://127.0.0.1:2579", o.serverPdAddr)
+}
+
 func TestDecodeUnknownDebugCfg(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "ticdc.toml")
@@ -0,0 +1,199 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// ServerConfigEnvPrefix is the prefix of environment variables which override
+// the configuration of TiCDC server.
+const ServerConfigEnvPrefix = "TICDC"
+
+// sensitiveEnvNames are the substrings of environment variables whose values
+// should be masked in logs.
+var sensitiveEnvNames = []string{
+	"PASSWORD",
+	"PASSWD",
+	"TOKEN",
+	"SECRET",
+	"KEY",
+	"CREDENTIAL",
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// OverrideConfigFromEnv overrides the fields of cfg, which must be a pointer to
+// a struct, by environment variables. A field is overridden by the variable
+// named by the prefix and the toml keys from the root to the field in upper
+// snake case, e.g. `TICDC_KV_CLIENT_WORKER_CONCURRENT` for the field whose
+// toml path is `kv-client.worker-concurrent`. The values of slices are comma
+// separated. It returns the applied variables, whose values are masked if
+// they are sensitive, so that they can be logged.
+func OverrideConfigFromEnv(prefix string, cfg interface{}) (map[string]string, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.Errorf("config must be a pointer to struct, got %T", cfg)
+	}
+	applied := make(map[string]string)
+	if err := overrideStructFromEnv(prefix, v.Elem(), applied); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func overrideStructFromEnv(prefix string, v reflect.Value, applied map[string]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if err := overrideValueFromEnv(name, v.Field(i), applied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func overrideValueFromEnv(name string, v reflect.Value, applied map[string]string) error {
+	if !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		switch v.Kind() {
+		case reflect.Struct:
+			return overrideStructFromEnv(name, v, applied)
+		case reflect.Ptr:
+			if v.Type().Elem().Kind() == reflect.Struct {
+				// Only allocate the struct if any of its fields is overridden.
+				if v.IsNil() {
+					if !hasEnvWithPrefix(name + "_") {
+						return nil
+					}
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				return overrideStructFromEnv(name, v.Elem(), applied)
+			}
+		case reflect.Map:
+			// Maps are keyed by the user, they can't be mapped to names.
+			return nil
+		}
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	target := v
+	if v.Kind() == reflect.Ptr {
+		target = reflect.New(v.Type().Elem()).Elem()
+	}
+	if err := setValueFromString(target, value); err != nil {
+		return errors.Annotatef(err, "invalid value of environment variable %s", name)
+	}
+	if v.Kind() == reflect.Ptr {
+		v.Set(target.Addr())
+	}
+	applied[name] = maskEnvValue(name, value)
+	return nil
+}
+
+func setValueFromString(v reflect.Value, value string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return errors.Trace(u.UnmarshalText([]byte(value)))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		if value != "" {
+			items = strings.Split(value, ",")
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValueFromString(s.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	default:
+		return errors.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func hasEnvWithPrefix(prefix string) bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskEnvValue(name, value string) string {
+	for _, sensitive := range sensitiveEnvNames {
+		if strings.Contains(name, sensitive) {
+			return "xxxxx"
+		}
+	}
+	if strings.HasSuffix(name, "_URI") {
+		return util.MaskSensitiveDataInURI(value)
+	}
+	return value
+}
@@ -23,6 +23,9 @@ const (
   "enable-sync-point": false,
   "enable-table-monitor": false,
   "bdr-mode": false,
+  "schema-only-mode": false,
+  "validate-initial-consistency": false,
+  "initial-consistency-sample-rate": 0.01,
   "sync-point-interval": 600000000000,
   "sync-point-retention": 86400000000000,
   "filter": {
@@ -64,7 +67,9 @@ const (
     "large-message-handle": {
       "large-message-handle-option": "none",
       "large-message-handle-compression": "",
-      "claim-check-storage-uri": ""
+      "claim-check-storage-uri": "",
+      "large-value-external-storage": "",
+      "large-value-threshold-bytes": 0
     },
     "advance-timeout-in-sec": 150,
     "send-bootstrap-interval-in-sec": 120,
@@ -104,6 +109,7 @@ const (
 	testCfgTestServerConfigMarshal = `{
   "addr": "192.155.22.33:8887",
   "advertise-addr": "",
+  "pd-endpoints": "",
   "log-file": "",
   "log-level": "info",
   "log": {
@@ -123,6 +129,7 @@ const (
   "sorter": {
     "sort-dir": "/tmp/sorter",
     "cache-size-in-mb": 128,
+    "adaptive-memory-threshold-in-mb": 0,
     "max-memory-percentage": 0,
     "max-memory-consumption": 0,
     "num-workerpool-goroutine": 0,
@@ -175,7 +182,8 @@ const (
       "collect-stats-tick": 200,
       "max-task-concurrency": 10,
       "check-balance-interval": 60000000000,
-      "add-table-batch-size": 50
+      "add-table-batch-size": 50,
+      "rebalance-threshold": 0
     },
     "cdc-v2": {
       "enable": false,
@@ -194,6 +202,15 @@ const (
   },
   "cluster-id": "default",
   "gc-tuner-memory-threshold": 0,
+  "region": "",
+  "namespace-quota": null,
+  "enable-pprof": false,
+  "pprof-addr": "127.0.0.1:6060",
+  "pprof-retention-secs": 60,
+  "max-concurrent-changefeeds": 0,
+  "grpc-keepalive-interval": 30000000000,
+  "grpc-keepalive-timeout": 10000000000,
+  "grpc-keepalive-permit-without-stream": true,
   "per-table-memory-quota": 0,
   "max-memory-percentage": 0
 }`
@@ -207,6 +224,9 @@ const (
   "enable-sync-point": false,
   "enable-table-monitor": false,
   "bdr-mode": false,
+  "schema-only-mode": false,
+  "validate-initial-consistency": false,
+  "initial-consistency-sample-rate": 0.01,
   "sync-point-interval": 600000000000,
   "sync-point-retention": 86400000000000,
   "filter": {
@@ -289,7 +309,9 @@ const (
       "large-message-handle": {
         "large-message-handle-option": "handle-key-only",
         "large-message-handle-compression": "",
-        "claim-check-storage-uri": ""
+        "claim-check-storage-uri": "",
+        "large-value-external-storage": "",
+        "large-value-threshold-bytes": 0
       },
       "glue-schema-registry-config": {
         "region":"region",
@@ -375,6 +397,9 @@ const (
   "enable-sync-point": false,
   "enable-table-monitor": false,
   "bdr-mode": false,
+  "schema-only-mode": false,
+  "validate-initial-consistency": false,
+  "initial-consistency-sample-rate": 0.01,
   "sync-point-interval": 600000000000,
   "sync-point-retention": 86400000000000,
   "filter": {
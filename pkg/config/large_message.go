@@ -25,6 +25,10 @@ const (
 	LargeMessageHandleOptionClaimCheck string = "claim-check"
 	// LargeMessageHandleOptionHandleKeyOnly means handling large message by sending only handle key columns.
 	LargeMessageHandleOptionHandleKeyOnly string = "handle-key-only"
+
+	// DefaultLargeValueThresholdBytes is the default threshold of the column value
+	// to be sent to the large value external storage.
+	DefaultLargeValueThresholdBytes = 1024 * 1024
 )
 
 // LargeMessageHandleConfig is the configuration for handling large message.
@@ -32,6 +36,13 @@ type LargeMessageHandleConfig struct {
 	LargeMessageHandleOption      string `toml:"large-message-handle-option" json:"large-message-handle-option"`
 	LargeMessageHandleCompression string `toml:"large-message-handle-compression" json:"large-message-handle-compression"`
 	ClaimCheckStorageURI          string `toml:"claim-check-storage-uri" json:"claim-check-storage-uri"`
+
+	// LargeValueExternalStorage is the URI of the external storage, column values
+	// larger than LargeValueThresholdBytes are sent to it, and replaced by their
+	// locations in the message. It works independently of the large message
+	// handle option, and only the simple protocol in json format supports it.
+	LargeValueExternalStorage string `toml:"large-value-external-storage" json:"large-value-external-storage"`
+	LargeValueThresholdBytes  int    `toml:"large-value-threshold-bytes" json:"large-value-threshold-bytes"`
 }
 
 // NewDefaultLargeMessageHandleConfig return the default Config.
@@ -57,6 +68,22 @@ func (c *LargeMessageHandleConfig) AdjustAndValidate(protocol Protocol, enableTi
 		return cerror.ErrInvalidReplicaConfig.GenWithStack(
 			"large message handle compression is not supported, got %s", c.LargeMessageHandleCompression)
 	}
+
+	if c.EnableLargeValueExternalStorage() {
+		if protocol != ProtocolSimple {
+			return cerror.ErrInvalidReplicaConfig.GenWithStack(
+				"large value external storage is set, protocol is %s, it's not supported",
+				protocol.String())
+		}
+		if c.LargeValueThresholdBytes < 0 {
+			return cerror.ErrInvalidReplicaConfig.GenWithStack(
+				"invalid large-value-threshold-bytes %d", c.LargeValueThresholdBytes)
+		}
+		if c.LargeValueThresholdBytes == 0 {
+			c.LargeValueThresholdBytes = DefaultLargeValueThresholdBytes
+		}
+	}
+
 	if c.LargeMessageHandleOption == LargeMessageHandleOptionNone {
 		return nil
 	}
@@ -101,6 +128,15 @@ func (c *LargeMessageHandleConfig) EnableClaimCheck() bool {
 	return c.LargeMessageHandleOption == LargeMessageHandleOptionClaimCheck
 }
 
+// EnableLargeValueExternalStorage returns true if large column values are sent
+// to the external storage.
+func (c *LargeMessageHandleConfig) EnableLargeValueExternalStorage() bool {
+	if c == nil {
+		return false
+	}
+	return c.LargeValueExternalStorage != ""
+}
+
 // Disabled returns true if disable large message handle.
 func (c *LargeMessageHandleConfig) Disabled() bool {
 	if c == nil {
@@ -118,3 +118,42 @@ func TestLargeMessageHandle4OpenProtocol(t *testing.T) {
 
 	}
 }
+
+func TestLargeValueExternalStorage(t *testing.T) {
+	t.Parallel()
+
+	largeMessageHandle := NewDefaultLargeMessageHandleConfig()
+	require.False(t, largeMessageHandle.EnableLargeValueExternalStorage())
+
+	largeMessageHandle.LargeValueExternalStorage = "file:///tmp/large-value"
+	require.True(t, largeMessageHandle.EnableLargeValueExternalStorage())
+
+	// only the simple protocol is supported
+	for _, protocol := range []Protocol{
+		ProtocolOpen, ProtocolCanalJSON, ProtocolAvro, ProtocolDebezium,
+	} {
+		err := largeMessageHandle.AdjustAndValidate(protocol, true)
+		require.ErrorIs(t, err, cerror.ErrInvalidReplicaConfig)
+	}
+
+	// the threshold is set to the default value if not set
+	err := largeMessageHandle.AdjustAndValidate(ProtocolSimple, false)
+	require.NoError(t, err)
+	require.Equal(t, DefaultLargeValueThresholdBytes, largeMessageHandle.LargeValueThresholdBytes)
+
+	largeMessageHandle.LargeValueThresholdBytes = 1024
+	err = largeMessageHandle.AdjustAndValidate(ProtocolSimple, false)
+	require.NoError(t, err)
+	require.Equal(t, 1024, largeMessageHandle.LargeValueThresholdBytes)
+
+	largeMessageHandle.LargeValueThresholdBytes = -1
+	err = largeMessageHandle.AdjustAndValidate(ProtocolSimple, false)
+	require.ErrorIs(t, err, cerror.ErrInvalidReplicaConfig)
+
+	// it works together with the large message handle option
+	largeMessageHandle.LargeValueThresholdBytes = 1024
+	largeMessageHandle.LargeMessageHandleOption = LargeMessageHandleOptionClaimCheck
+	largeMessageHandle.ClaimCheckStorageURI = "file:///tmp/claim-check"
+	err = largeMessageHandle.AdjustAndValidate(ProtocolSimple, false)
+	require.NoError(t, err)
+}
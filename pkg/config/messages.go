@@ -38,10 +38,14 @@ type MessagesConfig struct {
 
 	// After a duration of this time if the server doesn't see any activity it
 	// pings the client to see if the transport is still alive.
+	//
+	// Deprecated: it's overridden by `grpc-keepalive-interval` of the server.
 	KeepAliveTime TomlDuration `toml:"keep-alive-time" json:"keep-alive-time"`
 	// After having pinged for keepalive check, the server waits for a duration
 	// of Timeout and if no activity is seen even after that the connection is
 	// closed.
+	//
+	// Deprecated: it's overridden by `grpc-keepalive-timeout` of the server.
 	KeepAliveTimeout TomlDuration `toml:"keep-alive-timeout" json:"keep-alive-timeout"`
 }
 
@@ -0,0 +1,45 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/pingcap/tiflow/pkg/errors"
+
+// ChangefeedQuota represents the resource quota of changefeeds in a namespace.
+// A zero value means the dimension is not limited.
+type ChangefeedQuota struct {
+	// the max number of changefeeds in the namespace
+	MaxChangefeeds int `toml:"max-changefeeds" json:"max-changefeeds"`
+	// the max number of tables replicated by a single changefeed
+	MaxTablesPerChangefeed int `toml:"max-tables-per-changefeed" json:"max-tables-per-changefeed"`
+	// the max number of sink tasks handled at the same time by the changefeeds
+	// of the namespace on each capture
+	MaxWorkers int `toml:"max-workers" json:"max-workers"`
+}
+
+// ValidateAndAdjust validates the changefeed quota
+func (q *ChangefeedQuota) ValidateAndAdjust() error {
+	if q.MaxChangefeeds < 0 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"max-changefeeds should not be negative")
+	}
+	if q.MaxTablesPerChangefeed < 0 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"max-tables-per-changefeed should not be negative")
+	}
+	if q.MaxWorkers < 0 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"max-workers should not be negative")
+	}
+	return nil
+}
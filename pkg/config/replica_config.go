@@ -38,6 +38,12 @@ const (
 	// minSyncPointRetention is the minimum of SyncPointRetention can be set.
 	minSyncPointRetention           = time.Hour * 1
 	minChangeFeedErrorStuckDuration = time.Minute * 30
+	// minResolvedTsBroadcastIntervalMs and maxResolvedTsBroadcastIntervalMs
+	// are the range of ResolvedTsBroadcastIntervalMs can be set.
+	minResolvedTsBroadcastIntervalMs = 50
+	maxResolvedTsBroadcastIntervalMs = 60000
+	// defaultInitialConsistencySampleRate samples 1% rows of each table.
+	defaultInitialConsistencySampleRate = 0.01
 )
 
 var defaultReplicaConfig = &ReplicaConfig{
@@ -49,6 +55,10 @@ var defaultReplicaConfig = &ReplicaConfig{
 	SyncPointInterval:  util.AddressOf(10 * time.Minute),
 	SyncPointRetention: util.AddressOf(24 * time.Hour),
 	BDRMode:            util.AddressOf(false),
+	SchemaOnlyMode:     util.AddressOf(false),
+
+	ValidateInitialConsistency:   util.AddressOf(false),
+	InitialConsistencySampleRate: util.AddressOf(defaultInitialConsistencySampleRate),
 	Filter: &FilterConfig{
 		Rules: []string{"*.*"},
 	},
@@ -140,6 +150,18 @@ type replicaConfig struct {
 	// replicate data of same tables from TiDB-1 to TiDB-2 and vice versa.
 	// This feature is only available for TiDB.
 	BDRMode *bool `toml:"bdr-mode" json:"bdr-mode,omitempty"`
+	// SchemaOnlyMode replicates DDL events only, row changed events are
+	// dropped after they are sorted, so the checkpoint keeps advancing without
+	// writing any rows to the downstream.
+	SchemaOnlyMode *bool `toml:"schema-only-mode" json:"schema-only-mode,omitempty"`
+	// ValidateInitialConsistency samples the rows of the replicated tables at
+	// the start-ts of a new changefeed, and compares them with the downstream
+	// before any row is replicated. The mismatches are reported as warnings.
+	// This feature is only available when the downstream is MySQL or TiDB.
+	ValidateInitialConsistency *bool `toml:"validate-initial-consistency" json:"validate-initial-consistency,omitempty"`
+	// InitialConsistencySampleRate is the ratio of rows sampled in each table
+	// by the initial consistency validation, which is in (0, 1].
+	InitialConsistencySampleRate *float64 `toml:"initial-consistency-sample-rate" json:"initial-consistency-sample-rate,omitempty"`
 	// SyncPointInterval is only available when the downstream is DB.
 	SyncPointInterval *time.Duration `toml:"sync-point-interval" json:"sync-point-interval,omitempty"`
 	// SyncPointRetention is only available when the downstream is DB.
@@ -155,6 +177,16 @@ type replicaConfig struct {
 	Integrity                    *integrity.Config   `toml:"integrity" json:"integrity"`
 	ChangefeedErrorStuckDuration *time.Duration      `toml:"changefeed-error-stuck-duration" json:"changefeed-error-stuck-duration,omitempty"`
 	SyncedStatus                 *SyncedStatusConfig `toml:"synced-status" json:"synced-status,omitempty"`
+	// SamplingMode writes a sampled fraction of the row changed events to a
+	// file for debugging.
+	SamplingMode *SamplingModeConfig `toml:"sampling-mode" json:"sampling-mode,omitempty"`
+	// ResolvedTsBroadcastIntervalMs is the interval in millisecond that the owner
+	// broadcasts the resolved ts of the changefeed to processors. Processors flush
+	// the data to the sink only up to the broadcast resolved ts, so a larger value
+	// reduces the flush frequency but increases the replication latency, up to
+	// the interval. The checkpoints of tables are still collected every heartbeat
+	// tick. If it's not set, the resolved ts is broadcast every heartbeat tick.
+	ResolvedTsBroadcastIntervalMs *uint64 `toml:"resolved-ts-broadcast-interval-ms" json:"resolved-ts-broadcast-interval-ms,omitempty"`
 
 	// Deprecated: we don't use this field since v8.0.0.
 	SQLMode string `toml:"sql-mode" json:"sql-mode"`
@@ -271,6 +303,19 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 						minSyncPointRetention.String()))
 		}
 	}
+	if c.InitialConsistencySampleRate != nil {
+		rate := *c.InitialConsistencySampleRate
+		if rate <= 0 || rate > 1 {
+			return cerror.ErrInvalidReplicaConfig.
+				FastGenByArgs(
+					fmt.Sprintf("The InitialConsistencySampleRate:%v must be in (0, 1]", rate))
+		}
+	}
+	if c.SamplingMode != nil {
+		if err := c.SamplingMode.Validate(); err != nil {
+			return err
+		}
+	}
 	if c.MemoryQuota == uint64(0) {
 		c.FixMemoryQuota()
 	}
@@ -318,6 +363,17 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 					minChangeFeedErrorStuckDuration.Seconds()))
 	}
 
+	if c.ResolvedTsBroadcastIntervalMs != nil &&
+		(*c.ResolvedTsBroadcastIntervalMs < minResolvedTsBroadcastIntervalMs ||
+			*c.ResolvedTsBroadcastIntervalMs > maxResolvedTsBroadcastIntervalMs) {
+		return cerror.ErrInvalidReplicaConfig.
+			FastGenByArgs(
+				fmt.Sprintf("The ResolvedTsBroadcastIntervalMs:%d must be in [%d, %d]",
+					*c.ResolvedTsBroadcastIntervalMs,
+					minResolvedTsBroadcastIntervalMs,
+					maxResolvedTsBroadcastIntervalMs))
+	}
+
 	return nil
 }
 
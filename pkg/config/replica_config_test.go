@@ -298,6 +298,17 @@ func TestValidateAndAdjust(t *testing.T) {
 	duration = minChangeFeedErrorStuckDuration
 	cfg.ChangefeedErrorStuckDuration = &duration
 	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+
+	// resolved ts broadcast interval is out of range
+	cfg = GetDefaultReplicaConfig()
+	for _, interval := range []uint64{0, minResolvedTsBroadcastIntervalMs - 1, maxResolvedTsBroadcastIntervalMs + 1} {
+		cfg.ResolvedTsBroadcastIntervalMs = util.AddressOf(interval)
+		require.ErrorContains(t, cfg.ValidateAndAdjust(sinkURL), "The ResolvedTsBroadcastIntervalMs")
+	}
+	for _, interval := range []uint64{minResolvedTsBroadcastIntervalMs, maxResolvedTsBroadcastIntervalMs} {
+		cfg.ResolvedTsBroadcastIntervalMs = util.AddressOf(interval)
+		require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	}
 }
 
 func TestIsSinkCompatibleWithSpanReplication(t *testing.T) {
@@ -377,6 +388,20 @@ func TestValidateAndAdjustLargeMessageHandle(t *testing.T) {
 	require.Equal(t, compression.None, cfg.Sink.KafkaConfig.LargeMessageHandle.LargeMessageHandleCompression)
 }
 
+func TestValidateAndAdjustSamplingMode(t *testing.T) {
+	cfg := GetDefaultReplicaConfig()
+	sinkURL, err := url.Parse("blackhole://")
+	require.NoError(t, err)
+
+	cfg.SamplingMode = &SamplingModeConfig{Enabled: true, SampleRate: 0.1}
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+
+	cfg.SamplingMode.SampleRate = 1.5
+	require.ErrorContains(t, cfg.ValidateAndAdjust(sinkURL), "must be in [0, 1]")
+	cfg.SamplingMode.SampleRate = -0.1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(sinkURL), "must be in [0, 1]")
+}
+
 func TestMaskSensitiveData(t *testing.T) {
 	config := ReplicaConfig{
 		Sink:       nil,
@@ -399,11 +424,18 @@ func TestMaskSensitiveData(t *testing.T) {
 		},
 	}
 	config.Sink.SchemaRegistry = aws.String("http://abc.com?password=bacd")
+	config.Sink.SchemaRegistryPassword = aws.String("bacd")
+	config.Sink.SchemaRegistryAPIKey = aws.String("bacd")
+	config.Sink.MySQLConfig = &MySQLConfig{
+		UpstreamTiDBDSN: aws.String("root:bacd@tcp(127.0.0.1:4000)/"),
+	}
 	config.Consistent = &ConsistentConfig{
 		Storage: "http://abc.com?password=bacd",
 	}
 	config.MaskSensitiveData()
 	require.Equal(t, "http://abc.com?password=xxxxx", *config.Sink.SchemaRegistry)
+	require.Equal(t, "******", *config.Sink.SchemaRegistryPassword)
+	require.Equal(t, "******", *config.Sink.SchemaRegistryAPIKey)
 	require.Equal(t, "http://abc.com?password=xxxxx", config.Consistent.Storage)
 	require.Equal(t, "http://abc.com?password=xxxxx", *config.Sink.KafkaConfig.SASLOAuthTokenURL)
 	require.Equal(t, "******", *config.Sink.KafkaConfig.SASLOAuthClientSecret)
@@ -413,4 +445,5 @@ func TestMaskSensitiveData(t *testing.T) {
 	require.Equal(t, "******", config.Sink.KafkaConfig.GlueSchemaRegistryConfig.SecretAccessKey)
 	require.Equal(t, "******", config.Sink.KafkaConfig.GlueSchemaRegistryConfig.Token)
 	require.Equal(t, "******", config.Sink.KafkaConfig.GlueSchemaRegistryConfig.AccessKey)
+	require.Equal(t, "******", *config.Sink.MySQLConfig.UpstreamTiDBDSN)
 }
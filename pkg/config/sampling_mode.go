@@ -0,0 +1,44 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// SamplingModeConfig represents the event sampling config for a changefeed,
+// which helps to debug a changefeed without a full downstream setup.
+type SamplingModeConfig struct {
+	// Enabled writes the sampled row changed events in canal-json format to
+	// OutputFile in addition to the sink. Use the blackhole sink to sample
+	// the events only.
+	Enabled bool `toml:"enabled" json:"enabled"`
+	// SampleRate is the fraction of the rows sampled, which is in [0, 1].
+	// The sampling decision is stable per primary key.
+	SampleRate float64 `toml:"sample-rate" json:"sample-rate"`
+	// OutputFile is the file which the sampled events are appended to, they
+	// are written to stdout if it's empty.
+	OutputFile string `toml:"output-file" json:"output-file"`
+}
+
+// Validate validates the sampling mode config.
+func (c *SamplingModeConfig) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The SampleRate:%v of the sampling mode must be in [0, 1]", c.SampleRate))
+	}
+	return nil
+}
@@ -65,9 +65,18 @@ type SchedulerConfig struct {
 	// When there are only 2 captures, and a large number of tables, this can be helpful to prevent
 	// oom caused by all tables dispatched to only one capture.
 	AddTableBatchSize int `toml:"add-table-batch-size" json:"add-table-batch-size"`
+	// RebalanceThreshold is the threshold of the coefficient of variation of
+	// table counts across captures. The balance scheduler only moves tables
+	// when the coefficient of variation exceeds the threshold.
+	// 0 means always try to balance tables.
+	RebalanceThreshold float64 `toml:"rebalance-threshold" json:"rebalance-threshold"`
 
 	// ChangefeedSettings is setting by changefeed.
 	ChangefeedSettings *ChangefeedSchedulerConfig `toml:"-" json:"-"`
+	// ResolvedTsBroadcastInterval is setting by changefeed, it's the min interval
+	// of sending the barrier to captures in heartbeats. Heartbeats are still sent
+	// every HeartbeatTick. 0 means the barrier is sent in every heartbeat.
+	ResolvedTsBroadcastInterval time.Duration `toml:"-" json:"-"`
 }
 
 // NewDefaultSchedulerConfig return the default scheduler configuration.
@@ -106,5 +115,9 @@ func (c *SchedulerConfig) ValidateAndAdjust() error {
 		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
 			"add-table-batch-size must be large than 0")
 	}
+	if c.RebalanceThreshold < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"rebalance-threshold must not be less than 0")
+	}
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/security"
 	"go.uber.org/zap"
 )
@@ -143,12 +144,23 @@ var defaultServerConfig = &ServerConfig{
 	},
 	ClusterID:              "default",
 	GcTunerMemoryThreshold: DisableMemoryLimit,
+	EnablePprof:            false,
+	PprofAddr:              "127.0.0.1:6060",
+	PprofRetentionSecs:     60,
+	// Ping the idle connections more often than the idle timeout of the
+	// load balancers and NAT gateways on the typical cloud networks.
+	GRPCKeepaliveInterval:            TomlDuration(30 * time.Second),
+	GRPCKeepaliveTimeout:             TomlDuration(10 * time.Second),
+	GRPCKeepalivePermitWithoutStream: true,
 }
 
 // ServerConfig represents a config for server
 type ServerConfig struct {
 	Addr          string `toml:"addr" json:"addr"`
 	AdvertiseAddr string `toml:"advertise-addr" json:"advertise-addr"`
+	// PDEndpoints is the comma-separated PD endpoints of the upstream, it's
+	// overridden by the `--pd` flag.
+	PDEndpoints string `toml:"pd-endpoints" json:"pd-endpoints"`
 
 	LogFile  string     `toml:"log-file" json:"log-file"`
 	LogLevel string     `toml:"log-level" json:"log-level"`
@@ -170,6 +182,33 @@ type ServerConfig struct {
 	Debug                  *DebugConfig         `toml:"debug" json:"debug"`
 	ClusterID              string               `toml:"cluster-id" json:"cluster-id"`
 	GcTunerMemoryThreshold uint64               `toml:"gc-tuner-memory-threshold" json:"gc-tuner-memory-threshold"`
+	// Region is the region label of the TiCDC server in a multi-region
+	// deployment, tables are preferentially scheduled to servers in the same
+	// region as the leaders of the table data.
+	Region string `toml:"region" json:"region"`
+	// NamespaceQuota limits the resources used by changefeeds of each namespace,
+	// namespaces without a quota are not limited.
+	NamespaceQuota map[string]*ChangefeedQuota `toml:"namespace-quota" json:"namespace-quota"`
+	// EnablePprof enables the pprof HTTP server listening on PprofAddr, which
+	// must use a different port from Addr.
+	EnablePprof bool   `toml:"enable-pprof" json:"enable-pprof"`
+	PprofAddr   string `toml:"pprof-addr" json:"pprof-addr"`
+	// PprofRetentionSecs limits the duration of delta profiles, e.g.
+	// `/debug/pprof/heap?seconds=30`, as the base profile is kept in memory
+	// until the duration elapses.
+	PprofRetentionSecs int `toml:"pprof-retention-secs" json:"pprof-retention-secs"`
+	// MaxConcurrentChangefeeds limits the number of changefeeds run by the
+	// owner, the others are queued until some of the running changefeeds are
+	// stopped or finished. 0 means no limit.
+	MaxConcurrentChangefeeds int `toml:"max-concurrent-changefeeds" json:"max-concurrent-changefeeds"`
+	// GRPCKeepaliveInterval is the interval of the keepalive pings sent on
+	// the idle gRPC connections between TiCDC nodes, and GRPCKeepaliveTimeout
+	// is how long to wait for the ack of a ping before closing the connection.
+	GRPCKeepaliveInterval TomlDuration `toml:"grpc-keepalive-interval" json:"grpc-keepalive-interval"`
+	GRPCKeepaliveTimeout  TomlDuration `toml:"grpc-keepalive-timeout" json:"grpc-keepalive-timeout"`
+	// GRPCKeepalivePermitWithoutStream allows the keepalive pings to be sent
+	// on the connections without any active stream.
+	GRPCKeepalivePermitWithoutStream bool `toml:"grpc-keepalive-permit-without-stream" json:"grpc-keepalive-permit-without-stream"`
 
 	// Deprecated: we don't use this field anymore.
 	PerTableMemoryQuota uint64 `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
@@ -298,9 +337,91 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	if err = c.Debug.ValidateAndAdjust(); err != nil {
 		return errors.Trace(err)
 	}
+
+	if c.EnablePprof {
+		if err = c.validatePprofAddr(); err != nil {
+			return errors.Trace(err)
+		}
+		if c.PprofRetentionSecs <= 0 {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"pprof-retention-secs must be greater than 0")
+		}
+	}
+
+	if c.MaxConcurrentChangefeeds < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"max-concurrent-changefeeds must not be negative")
+	}
+
+	if c.GRPCKeepaliveInterval == 0 {
+		c.GRPCKeepaliveInterval = defaultCfg.GRPCKeepaliveInterval
+	}
+	if c.GRPCKeepaliveTimeout == 0 {
+		c.GRPCKeepaliveTimeout = defaultCfg.GRPCKeepaliveTimeout
+	}
+	if c.GRPCKeepaliveInterval < 0 || c.GRPCKeepaliveTimeout < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"grpc-keepalive-interval and grpc-keepalive-timeout must not be negative")
+	}
+
+	for namespace, quota := range c.NamespaceQuota {
+		if quota == nil {
+			continue
+		}
+		if err = quota.ValidateAndAdjust(); err != nil {
+			return errors.Annotatef(err, "invalid quota of namespace %s", namespace)
+		}
+	}
 	return nil
 }
 
+// MessageServerConfig returns the config of the peer-to-peer message server
+// with the gRPC keepalive parameters of the server.
+func (c *ServerConfig) MessageServerConfig() *p2p.MessageServerConfig {
+	cfg := c.Debug.Messages.ToMessageServerConfig()
+	cfg.KeepAliveTime = time.Duration(c.GRPCKeepaliveInterval)
+	cfg.KeepAliveTimeout = time.Duration(c.GRPCKeepaliveTimeout)
+	cfg.KeepAlivePermitWithoutStream = c.GRPCKeepalivePermitWithoutStream
+	return cfg
+}
+
+// MessageClientConfig returns the config of the peer-to-peer message client
+// with the gRPC keepalive parameters of the server.
+func (c *ServerConfig) MessageClientConfig() *p2p.MessageClientConfig {
+	cfg := c.Debug.Messages.ToMessageClientConfig()
+	cfg.KeepAliveTime = time.Duration(c.GRPCKeepaliveInterval)
+	cfg.KeepAliveTimeout = time.Duration(c.GRPCKeepaliveTimeout)
+	cfg.KeepAlivePermitWithoutStream = c.GRPCKeepalivePermitWithoutStream
+	return cfg
+}
+
+// validatePprofAddr checks that the pprof server does not share the port of
+// the management API.
+func (c *ServerConfig) validatePprofAddr() error {
+	if c.PprofAddr == "" {
+		return cerror.ErrInvalidServerOption.GenWithStack("empty pprof address")
+	}
+	_, pprofPort, err := net.SplitHostPort(c.PprofAddr)
+	if err != nil {
+		return cerror.ErrInvalidServerOption.Wrap(err).GenWithStackByArgs()
+	}
+	_, port, err := net.SplitHostPort(c.Addr)
+	if err != nil {
+		return cerror.ErrInvalidServerOption.Wrap(err).GenWithStackByArgs()
+	}
+	if pprofPort == port {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"pprof address %s must use a different port from address %s", c.PprofAddr, c.Addr)
+	}
+	return nil
+}
+
+// GetNamespaceQuota returns the quota of the namespace, or nil if the
+// namespace is not limited.
+func (c *ServerConfig) GetNamespaceQuota(namespace string) *ChangefeedQuota {
+	return c.NamespaceQuota[namespace]
+}
+
 // GetDefaultServerConfig returns the default server config
 func GetDefaultServerConfig() *ServerConfig {
 	return defaultServerConfig.Clone()
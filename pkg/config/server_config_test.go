@@ -77,6 +77,50 @@ func TestServerConfigValidateAndAdjust(t *testing.T) {
 	require.EqualValues(t, GetDefaultServerConfig().Debug.Messages.ServerWorkerPoolSize, conf.Debug.Messages.ServerWorkerPoolSize)
 }
 
+func TestPprofConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig()
+	conf.PprofAddr = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+
+	conf.EnablePprof = true
+	require.Regexp(t, ".*empty pprof address.*", conf.ValidateAndAdjust())
+	conf.PprofAddr = "0.0.0.0:8300"
+	require.Regexp(t, ".*must use a different port.*", conf.ValidateAndAdjust())
+	conf.PprofAddr = "127.0.0.1:6060"
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.PprofRetentionSecs = 0
+	require.Regexp(t, ".*pprof-retention-secs must be greater than 0.*", conf.ValidateAndAdjust())
+	conf.PprofRetentionSecs = 60
+	conf.MaxConcurrentChangefeeds = -1
+	require.Regexp(t, ".*max-concurrent-changefeeds must not be negative.*", conf.ValidateAndAdjust())
+	conf.MaxConcurrentChangefeeds = 0
+	conf.GRPCKeepaliveInterval = 0
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, TomlDuration(30*time.Second), conf.GRPCKeepaliveInterval)
+	conf.GRPCKeepaliveTimeout = TomlDuration(-time.Second)
+	require.Regexp(t, ".*grpc-keepalive-timeout must not be negative.*", conf.ValidateAndAdjust())
+}
+
+func TestServerConfigGRPCKeepalive(t *testing.T) {
+	t.Parallel()
+
+	conf := GetDefaultServerConfig()
+	conf.GRPCKeepaliveInterval = TomlDuration(time.Minute)
+	conf.GRPCKeepaliveTimeout = TomlDuration(20 * time.Second)
+	conf.GRPCKeepalivePermitWithoutStream = false
+
+	serverConfig := conf.MessageServerConfig()
+	require.Equal(t, time.Minute, serverConfig.KeepAliveTime)
+	require.Equal(t, 20*time.Second, serverConfig.KeepAliveTimeout)
+	require.False(t, serverConfig.KeepAlivePermitWithoutStream)
+
+	clientConfig := conf.MessageClientConfig()
+	require.Equal(t, time.Minute, clientConfig.KeepAliveTime)
+	require.Equal(t, 20*time.Second, clientConfig.KeepAliveTimeout)
+	require.False(t, clientConfig.KeepAlivePermitWithoutStream)
+}
+
 func TestDBConfigValidateAndAdjust(t *testing.T) {
 	t.Parallel()
 	conf := GetDefaultServerConfig().Clone().Debug.DB
@@ -133,6 +177,12 @@ func TestSchedulerConfigValidateAndAdjust(t *testing.T) {
 	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
 	conf.AddTableBatchSize = 0
 	require.Error(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
+	conf.RebalanceThreshold = -0.1
+	require.Error(t, conf.ValidateAndAdjust())
+	conf.RebalanceThreshold = 0.2
+	require.NoError(t, conf.ValidateAndAdjust())
 }
 
 func TestIsValidClusterID(t *testing.T) {
@@ -35,6 +35,8 @@ const (
 	DefaultMaxMessageBytes = 10 * 1024 * 1024 // 10M
 	// DefaultAdvanceTimeoutInSec sets the default value for advance-timeout-in-sec.
 	DefaultAdvanceTimeoutInSec = uint(150)
+	// DefaultSinkCreationTimeoutInSec sets the default value for sink-creation-timeout-in-sec.
+	DefaultSinkCreationTimeoutInSec = uint(30)
 
 	// TxnAtomicityKey specifies the key of the transaction-atomicity in the SinkURI.
 	TxnAtomicityKey = "transaction-atomicity"
@@ -134,6 +136,21 @@ type SinkConfig struct {
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors,omitempty"`
 	// SchemaRegistry is only available when the downstream is MQ using avro protocol.
 	SchemaRegistry *string `toml:"schema-registry" json:"schema-registry,omitempty"`
+	// SchemaRegistryCA, SchemaRegistryCert and SchemaRegistryKey are the paths
+	// of the TLS files used to connect to the schema registry, only the CA is
+	// required for one-way TLS.
+	SchemaRegistryCA   *string `toml:"schema-registry-ca" json:"schema-registry-ca,omitempty"`
+	SchemaRegistryCert *string `toml:"schema-registry-cert" json:"schema-registry-cert,omitempty"`
+	SchemaRegistryKey  *string `toml:"schema-registry-key" json:"schema-registry-key,omitempty"`
+	// SchemaRegistryUsername and SchemaRegistryPassword are used for the basic
+	// authentication of the schema registry, and SchemaRegistryAPIKey is used
+	// as a bearer token, they can't be specified at the same time.
+	SchemaRegistryUsername *string `toml:"schema-registry-username" json:"schema-registry-username,omitempty"`
+	SchemaRegistryPassword *string `toml:"schema-registry-password" json:"schema-registry-password,omitempty"`
+	SchemaRegistryAPIKey   *string `toml:"schema-registry-api-key" json:"schema-registry-api-key,omitempty"`
+	// SchemaRegistryType is the type of schemas registered to the schema registry,
+	// can be `confluent` or `json-schema`, default to `confluent`.
+	SchemaRegistryType *string `toml:"schema-registry-type" json:"schema-registry-type,omitempty"`
 	// EncoderConcurrency is only available when the downstream is MQ.
 	EncoderConcurrency *int `toml:"encoder-concurrency" json:"encoder-concurrency,omitempty"`
 	// Terminator is NOT available when the downstream is DB.
@@ -158,6 +175,24 @@ type SinkConfig struct {
 	// ContentCompatible is only available when the downstream is MQ.
 	ContentCompatible *bool `toml:"content-compatible" json:"content-compatible,omitempty"`
 
+	// JSONColumnAsObject is only available when the downstream is MQ and
+	// the protocol is canal-json.
+	JSONColumnAsObject *bool `toml:"json-column-as-object" json:"json-column-as-object,omitempty"`
+
+	// SpatialEncoding is the encoding of values of spatial columns,
+	// it can be `wkt` or `wkb`. It's only available when the downstream is MQ
+	// and the protocol is canal-json or avro.
+	SpatialEncoding *string `toml:"spatial-encoding" json:"spatial-encoding,omitempty"`
+
+	// CharsetConversionMode is how to convert the values of GBK and GB18030
+	// columns, it can be `auto`, `raw` or `error`. It's only available when
+	// the downstream is MQ and the protocol is canal or canal-json.
+	CharsetConversionMode *string `toml:"charset-conversion-mode" json:"charset-conversion-mode,omitempty"`
+
+	// ExcludeInvisibleColumns indicates whether to strip invisible columns
+	// from row changed events. Primary key columns are never stripped.
+	ExcludeInvisibleColumns *bool `toml:"exclude-invisible-columns" json:"exclude-invisible-columns,omitempty"`
+
 	// TiDBSourceID is the source ID of the upstream TiDB,
 	// which is used to set the `tidb_cdc_write_source` session variable.
 	// Note: This field is only used internally and only used in the MySQL sink.
@@ -170,10 +205,41 @@ type SinkConfig struct {
 	MySQLConfig        *MySQLConfig        `toml:"mysql-config" json:"mysql-config,omitempty"`
 	CloudStorageConfig *CloudStorageConfig `toml:"cloud-storage-config" json:"cloud-storage-config,omitempty"`
 
+	// PulsarProducerConfig is only available when the downstream is Pulsar.
+	PulsarProducerConfig *PulsarProducerConfig `toml:"pulsar-producer-config" json:"pulsar-producer-config,omitempty"`
+
+	// ElasticsearchConfig is only available when the downstream is Elasticsearch.
+	ElasticsearchConfig *ElasticsearchConfig `toml:"elasticsearch-config" json:"elasticsearch-config,omitempty"`
+
+	// IcebergPartitionBy is the partition spec of the Iceberg tables, it's
+	// only available when the downstream is Iceberg.
+	IcebergPartitionBy []*IcebergPartitionRule `toml:"iceberg-partition-by" json:"iceberg-partition-by,omitempty"`
+
+	// TableNameMappings renames the tables written to the downstream, it's
+	// only available when the downstream is MySQL or TiDB.
+	TableNameMappings []*TableNameMapping `toml:"table-name-mappings" json:"table-name-mappings,omitempty"`
+
 	// AdvanceTimeoutInSec is a duration in second. If a table sink progress hasn't been
 	// advanced for this given duration, the sink will be canceled and re-established.
 	AdvanceTimeoutInSec *uint `toml:"advance-timeout-in-sec" json:"advance-timeout-in-sec,omitempty"`
 
+	// BackpressureThresholdMs is a duration in millisecond. If the lag between the
+	// sorter resolved ts and the checkpoint ts of a table exceeds this value, pullers
+	// will stop pushing events of the table into the sorter until the sink catches up.
+	// 0 means backpressure is disabled.
+	BackpressureThresholdMs *uint64 `toml:"backpressure-threshold-ms" json:"backpressure-threshold-ms,omitempty"`
+
+	// HeartbeatIntervalMs is a duration in millisecond. If it's set, MQ sinks emit a
+	// heartbeat event carrying the resolved ts periodically, even if there are no
+	// DML or DDL events, so that the downstream can tell if the changefeed is alive.
+	// 0 means heartbeat is disabled.
+	HeartbeatIntervalMs *uint64 `toml:"heartbeat-interval-ms" json:"heartbeat-interval-ms,omitempty"`
+
+	// SinkCreationTimeoutInSec is a duration in second. Table sinks are created
+	// without blocking the sink manager, if a table sink can't be created within
+	// this given duration, it's reported as a timeout. 0 means the default value is used.
+	SinkCreationTimeoutInSec *uint `toml:"sink-creation-timeout-in-sec" json:"sink-creation-timeout-in-sec,omitempty"`
+
 	// Simple Protocol only config, use to control the behavior of sending bootstrap message.
 	// Note: When one of the following conditions is set to negative value,
 	// bootstrap sending function will be disabled.
@@ -195,12 +261,21 @@ func (s *SinkConfig) MaskSensitiveData() {
 	if s.SchemaRegistry != nil {
 		s.SchemaRegistry = aws.String(util.MaskSensitiveDataInURI(*s.SchemaRegistry))
 	}
+	if s.SchemaRegistryPassword != nil {
+		s.SchemaRegistryPassword = aws.String("******")
+	}
+	if s.SchemaRegistryAPIKey != nil {
+		s.SchemaRegistryAPIKey = aws.String("******")
+	}
 	if s.KafkaConfig != nil {
 		s.KafkaConfig.MaskSensitiveData()
 	}
 	if s.PulsarConfig != nil {
 		s.PulsarConfig.MaskSensitiveData()
 	}
+	if s.MySQLConfig != nil && s.MySQLConfig.UpstreamTiDBDSN != nil {
+		s.MySQLConfig.UpstreamTiDBDSN = aws.String("******")
+	}
 }
 
 // ShouldSendBootstrapMsg returns whether the sink should send bootstrap message.
@@ -375,6 +450,25 @@ type ColumnSelector struct {
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+// IcebergPartitionRule represents the Iceberg partition spec of the tables
+// matched by Matcher.
+type IcebergPartitionRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	// Fields are the partition fields of the table, each field is a column
+	// name or a transform of a column, e.g. `day(created_at)`,
+	// `bucket(16, id)` and `truncate(4, name)`.
+	Fields []string `toml:"fields" json:"fields"`
+}
+
+// TableNameMapping maps a table of the upstream to a table of the downstream,
+// the rows of the source table are written to the target table.
+type TableNameMapping struct {
+	SourceSchema string `toml:"source-schema" json:"source-schema"`
+	SourceTable  string `toml:"source-table" json:"source-table"`
+	TargetSchema string `toml:"target-schema" json:"target-schema"`
+	TargetTable  string `toml:"target-table" json:"target-table"`
+}
+
 // CodecConfig represents a MQ codec configuration
 type CodecConfig struct {
 	EnableTiDBExtension            *bool   `toml:"enable-tidb-extension" json:"enable-tidb-extension,omitempty"`
@@ -382,6 +476,9 @@ type CodecConfig struct {
 	AvroEnableWatermark            *bool   `toml:"avro-enable-watermark" json:"avro-enable-watermark"`
 	AvroDecimalHandlingMode        *string `toml:"avro-decimal-handling-mode" json:"avro-decimal-handling-mode,omitempty"`
 	AvroBigintUnsignedHandlingMode *string `toml:"avro-bigint-unsigned-handling-mode" json:"avro-bigint-unsigned-handling-mode,omitempty"`
+	AvroEnumSetHandlingMode        *string `toml:"avro-enum-set-handling-mode" json:"avro-enum-set-handling-mode,omitempty"`
+	AvroSchemaNamespace            *string `toml:"avro-schema-namespace" json:"avro-schema-namespace,omitempty"`
+	AvroSchemaCompatibility        *string `toml:"avro-schema-compatibility" json:"avro-schema-compatibility,omitempty"`
 	EncodingFormat                 *string `toml:"encoding-format" json:"encoding-format,omitempty"`
 }
 
@@ -392,6 +489,7 @@ type KafkaConfig struct {
 	KafkaVersion                 *string                   `toml:"kafka-version" json:"kafka-version,omitempty"`
 	MaxMessageBytes              *int                      `toml:"max-message-bytes" json:"max-message-bytes,omitempty"`
 	Compression                  *string                   `toml:"compression" json:"compression,omitempty"`
+	CompressionLevel             *int                      `toml:"compression-level" json:"compression-level,omitempty"`
 	KafkaClientID                *string                   `toml:"kafka-client-id" json:"kafka-client-id,omitempty"`
 	AutoCreateTopic              *bool                     `toml:"auto-create-topic" json:"auto-create-topic,omitempty"`
 	DialTimeout                  *string                   `toml:"dial-timeout" json:"dial-timeout,omitempty"`
@@ -423,6 +521,12 @@ type KafkaConfig struct {
 	CodecConfig                  *CodecConfig              `toml:"codec-config" json:"codec-config,omitempty"`
 	LargeMessageHandle           *LargeMessageHandleConfig `toml:"large-message-handle" json:"large-message-handle,omitempty"`
 	GlueSchemaRegistryConfig     *GlueSchemaRegistryConfig `toml:"glue-schema-registry-config" json:"glue-schema-registry-config"`
+
+	// KafkaStreamsChangelog sends row changes as Kafka Streams changelog
+	// records to the topic named `<appId>-<storeName>-changelog`, the appId is
+	// the topic in the sink URI, and the storeName is `<schema>.<table>`.
+	// It only works with the kafka-streams protocol.
+	KafkaStreamsChangelog *bool `toml:"kafka-streams-changelog" json:"kafka-streams-changelog,omitempty"`
 }
 
 // MaskSensitiveData masks sensitive data in KafkaConfig
@@ -613,12 +717,66 @@ func (c *PulsarConfig) GetDefaultTopicName() string {
 	return topicName[1:]
 }
 
+// PulsarProducerConfig is the configuration of pulsar producers.
+// It takes precedence over the producer related fields in PulsarConfig.
+type PulsarProducerConfig struct {
+	// CompressionType is the compression type of messages, lz4, zlib and zstd are supported.
+	CompressionType *PulsarCompressionType `toml:"compression-type" json:"compression-type,omitempty"`
+	// BatchingEnabled controls whether messages are batched by the producer (default: true).
+	BatchingEnabled *bool `toml:"batching-enabled" json:"batching-enabled,omitempty"`
+	// BatchingMaxMessages is the maximum number of messages permitted in a batch (default: 1000).
+	BatchingMaxMessages *int `toml:"batching-max-messages" json:"batching-max-messages,omitempty"`
+	// BatchingMaxPublishDelayMs is the time period in milliseconds within which
+	// the messages sent will be batched (default: 10ms).
+	BatchingMaxPublishDelayMs *int `toml:"batching-max-publish-delay-ms" json:"batching-max-publish-delay-ms,omitempty"`
+	// MaxPendingMessages is the max size of the queue holding the messages
+	// pending to receive an acknowledgment from the broker.
+	MaxPendingMessages *int `toml:"max-pending-messages" json:"max-pending-messages,omitempty"`
+}
+
+func (c *PulsarProducerConfig) validate(
+	protocol Protocol, enableTiDBExtension bool,
+) error {
+	if c.CompressionType != nil {
+		switch strings.ToLower(string(*c.CompressionType)) {
+		case "", "none", "lz4", "zlib", "zstd":
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"unsupported pulsar compression type %s, "+
+					"only none, lz4, zlib and zstd are supported", *c.CompressionType)
+		}
+	}
+	if util.GetOrZero(c.BatchingMaxMessages) < 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"batching-max-messages should not be negative, but got %d", *c.BatchingMaxMessages)
+	}
+	if util.GetOrZero(c.BatchingMaxPublishDelayMs) < 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"batching-max-publish-delay-ms should not be negative, but got %d",
+			*c.BatchingMaxPublishDelayMs)
+	}
+	if util.GetOrZero(c.MaxPendingMessages) < 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"max-pending-messages should not be negative, but got %d", *c.MaxPendingMessages)
+	}
+	// Watermark messages of canal-json with TiDB extension must be ordered,
+	// which can not be guaranteed if messages are batched.
+	if protocol == ProtocolCanalJSON && enableTiDBExtension &&
+		(c.BatchingEnabled == nil || *c.BatchingEnabled) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"batching-enabled must be false when the protocol is canal-json " +
+				"and enable-tidb-extension is true")
+	}
+	return nil
+}
+
 // MySQLConfig represents a MySQL sink configuration
 type MySQLConfig struct {
 	WorkerCount                  *int    `toml:"worker-count" json:"worker-count,omitempty"`
 	MaxTxnRow                    *int    `toml:"max-txn-row" json:"max-txn-row,omitempty"`
 	MaxMultiUpdateRowSize        *int    `toml:"max-multi-update-row-size" json:"max-multi-update-row-size,omitempty"`
 	MaxMultiUpdateRowCount       *int    `toml:"max-multi-update-row" json:"max-multi-update-row,omitempty"`
+	MaxBatchWaitMs               *int    `toml:"max-batch-wait-ms" json:"max-batch-wait-ms,omitempty"`
 	TiDBTxnMode                  *string `toml:"tidb-txn-mode" json:"tidb-txn-mode,omitempty"`
 	SSLCa                        *string `toml:"ssl-ca" json:"ssl-ca,omitempty"`
 	SSLCert                      *string `toml:"ssl-cert" json:"ssl-cert,omitempty"`
@@ -630,6 +788,91 @@ type MySQLConfig struct {
 	EnableBatchDML               *bool   `toml:"enable-batch-dml" json:"enable-batch-dml,omitempty"`
 	EnableMultiStatement         *bool   `toml:"enable-multi-statement" json:"enable-multi-statement,omitempty"`
 	EnableCachePreparedStatement *bool   `toml:"enable-cache-prepared-statement" json:"enable-cache-prepared-statement,omitempty"`
+	// SessionVariables are set on every new connection to the downstream,
+	// for example `innodb_lock_wait_timeout = "10"`.
+	SessionVariables map[string]string `toml:"session-variables" json:"session-variables,omitempty"`
+	// VerifyOrdering is a debug option for integration tests, it checks that
+	// txns of each table are written in CommitTs order. It requires the
+	// worker-count of the sink URI to be 1. Do not enable it in production
+	// since it introduces extra overhead.
+	VerifyOrdering *bool `toml:"verify-ordering" json:"verify-ordering,omitempty"`
+	// DebugSQLPreview is a debug option, it logs the EXPLAIN result of the
+	// first statement of each DML batch at DEBUG level, which helps to find
+	// missing indexes of downstream tables.
+	DebugSQLPreview *bool `toml:"debug-sql-preview" json:"debug-sql-preview,omitempty"`
+	// SchemaCompatibilityCheck compares the column types and the charset of
+	// downstream tables with the upstream when the sink starts and after each
+	// DDL, mismatches that may truncate values or fail the replication are
+	// logged.
+	SchemaCompatibilityCheck *bool `toml:"schema-compatibility-check" json:"schema-compatibility-check,omitempty"`
+	// TrackBinlogPosition records the binlog position of a MySQL downstream
+	// after each DML batch in a side table, which helps to find the binlog
+	// position for point-in-time recovery.
+	TrackBinlogPosition *bool `toml:"track-binlog-position" json:"track-binlog-position,omitempty"`
+	// PartitionDDLCompatibility rewrites the partition DDLs which use TiDB
+	// specific syntax, such as `ALTER TABLE t LAST PARTITION LESS THAN (...)`,
+	// to MySQL syntax when the downstream is not TiDB.
+	PartitionDDLCompatibility *bool `toml:"partition-ddl-compatibility" json:"partition-ddl-compatibility,omitempty"`
+	// ConnectionPoolPerTable shares the connections to the downstream evenly
+	// by tables, so that slow writes of a table can't exhaust the connections
+	// used by other tables. The prepared statement cache is disabled if it's
+	// enabled.
+	ConnectionPoolPerTable *bool `toml:"connection-pool-per-table" json:"connection-pool-per-table,omitempty"`
+	// DDLFallbackPolicy decides how to handle the DDLs which are not supported
+	// by a MySQL downstream, such as `CREATE SEQUENCE`. It can be `error`,
+	// `skip` or `emulate`, the default is `error`.
+	DDLFallbackPolicy *string `toml:"ddl-fallback-policy" json:"ddl-fallback-policy,omitempty"`
+	// MaxLockTimeoutRetries is the max number of retries of a DML batch
+	// failed by lock wait timeout, the changefeed fails after the retries
+	// are exhausted.
+	MaxLockTimeoutRetries *int `toml:"max-lock-timeout-retries" json:"max-lock-timeout-retries,omitempty"`
+	// DownstreamCharset is the charset of the connections to the downstream,
+	// it's utf8mb4 by default regardless of the default charset of the
+	// downstream since TiDB uses utf8mb4.
+	DownstreamCharset *string `toml:"downstream-charset" json:"downstream-charset,omitempty"`
+	// DownstreamCollation is the collation of the connections to the
+	// downstream, it's utf8mb4_bin by default, or the default collation of
+	// DownstreamCharset if only the charset is specified.
+	DownstreamCollation *string `toml:"downstream-collation" json:"downstream-collation,omitempty"`
+	// AuditSideChannel is the downstream table, e.g. `_ticdc_audit_log`, into
+	// which a row is inserted for each replicated row change in the same
+	// transaction. The table is put into the tidb_cdc schema if it isn't
+	// qualified by a schema.
+	AuditSideChannel *string `toml:"audit-side-channel" json:"audit-side-channel,omitempty"`
+	// AuditSideChannelCompression compresses the JSON payload of the audit
+	// rows by `COMPRESS()`, which can be decompressed by `UNCOMPRESS()`.
+	AuditSideChannelCompression *bool `toml:"audit-side-channel-compression" json:"audit-side-channel-compression,omitempty"`
+	// PtOSCCompatibility delays the DDLs of a table while pt-online-schema-change
+	// is running on it in the downstream, which is detected by the shadow
+	// table `_<table>_new`.
+	PtOSCCompatibility *bool `toml:"pt-osc-compatibility" json:"pt-osc-compatibility,omitempty"`
+	// PtOSCWaitTimeout limits how long the DDLs are delayed since the shadow
+	// table is created, an older shadow table is regarded as left by an
+	// aborted migration. The default is 1h.
+	PtOSCWaitTimeout *string `toml:"pt-osc-wait-timeout" json:"pt-osc-wait-timeout,omitempty"`
+	// BatchDDLMode executes the consecutive `CREATE TABLE` DDLs committed at
+	// the same time, e.g. by a `CREATE TABLES` job, in multi-statement
+	// batches to reduce the network round-trips.
+	BatchDDLMode *bool `toml:"batch-ddl-mode" json:"batch-ddl-mode,omitempty"`
+	// CreateTableIfNotExists creates the downstream table and retries the
+	// write if a DML fails since the table doesn't exist, e.g. the table was
+	// created upstream before the changefeed started.
+	CreateTableIfNotExists *bool `toml:"create-table-if-not-exists" json:"create-table-if-not-exists,omitempty"`
+	// UpstreamTiDBDSN is used to fetch the `CREATE TABLE` statement of a
+	// missing table if it can't be built from the table info of the rows.
+	UpstreamTiDBDSN *string `toml:"upstream-tidb-dsn" json:"upstream-tidb-dsn,omitempty"`
+	// AutoIncrementConflictPolicy decides how to handle the inserted rows of
+	// the tables with an AUTO_INCREMENT column, whose IDs may collide with
+	// the IDs generated by the writes to the downstream. It can be `error`,
+	// `reassign` (`REPLACE INTO`) or `skip` (`INSERT IGNORE`), the default
+	// is `error`.
+	AutoIncrementConflictPolicy *string `toml:"auto-increment-conflict-policy" json:"auto-increment-conflict-policy,omitempty"`
+	// ConditionalDDL makes the DDLs idempotent, so that a DDL which has been
+	// applied to the downstream, e.g. before the changefeed restarts, can be
+	// executed again. `IF [NOT] EXISTS` is added to the DDLs if the downstream
+	// supports it, otherwise the DDLs already applied are skipped by checking
+	// INFORMATION_SCHEMA.
+	ConditionalDDL *bool `toml:"conditional-ddl" json:"conditional-ddl,omitempty"`
 }
 
 // CloudStorageConfig represents a cloud storage sink configuration
@@ -644,11 +887,28 @@ type CloudStorageConfig struct {
 	FlushConcurrency    *int    `toml:"flush-concurrency" json:"flush-concurrency,omitempty"`
 }
 
+// ElasticsearchConfig represents an Elasticsearch sink configuration
+type ElasticsearchConfig struct {
+	// IndexNameTemplate is the template of the index names of the tables,
+	// `{schema}` and `{table}` are replaced by the schema and table names,
+	// the default is `{schema}.{table}`.
+	IndexNameTemplate *string `toml:"index-name-template" json:"index-name-template,omitempty"`
+	// BatchSize is the max number of operations in a bulk request.
+	BatchSize *int `toml:"batch-size" json:"batch-size,omitempty"`
+	// FlushInterval is the max duration to wait for more operations before
+	// sending a partial bulk request, e.g. `1s`.
+	FlushInterval *string `toml:"flush-interval" json:"flush-interval,omitempty"`
+}
+
 func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 	if err := s.validateAndAdjustSinkURI(sinkURI); err != nil {
 		return err
 	}
 
+	if err := validateTableNameMappings(s.TableNameMappings); err != nil {
+		return err
+	}
+
 	if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
 		return nil
 	}
@@ -698,6 +958,21 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 			return err
 		}
 	}
+	if s.PulsarProducerConfig != nil {
+		var (
+			enableTiDBExtension bool
+			err                 error
+		)
+		if s := sinkURI.Query().Get("enable-tidb-extension"); s != "" {
+			enableTiDBExtension, err = strconv.ParseBool(s)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if err = s.PulsarProducerConfig.validate(protocol, enableTiDBExtension); err != nil {
+			return err
+		}
+	}
 
 	for _, rule := range s.DispatchRules {
 		if rule.DispatcherRule != "" && rule.PartitionRule != "" {
@@ -763,6 +1038,37 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 	return nil
 }
 
+// validateTableNameMappings checks that all the names of the mappings are
+// specified, and a table is neither mapped twice nor mapped to by two
+// tables. The names are compared case-insensitively, since the downstream
+// may be case-insensitive.
+func validateTableNameMappings(mappings []*TableNameMapping) error {
+	sources := make(map[string]struct{}, len(mappings))
+	targets := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		if m.SourceSchema == "" || m.SourceTable == "" ||
+			m.TargetSchema == "" || m.TargetTable == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"all the schemas and tables of a table name mapping must be specified, "+
+					"but got %s.%s -> %s.%s", m.SourceSchema, m.SourceTable, m.TargetSchema, m.TargetTable)
+		}
+		source := strings.ToLower(m.SourceSchema + "." + m.SourceTable)
+		target := strings.ToLower(m.TargetSchema + "." + m.TargetTable)
+		if _, ok := sources[source]; ok {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"table %s.%s is mapped more than once", m.SourceSchema, m.SourceTable)
+		}
+		sources[source] = struct{}{}
+		if other, ok := targets[target]; ok {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"table %s and %s.%s are both mapped to %s.%s",
+				other, m.SourceSchema, m.SourceTable, m.TargetSchema, m.TargetTable)
+		}
+		targets[target] = m.SourceSchema + "." + m.SourceTable
+	}
+	return nil
+}
+
 // validateAndAdjustSinkURI validate and adjust `Protocol` and `TxnAtomicity` by sinkURI.
 func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 	if sinkURI == nil {
@@ -792,7 +1098,14 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 		if err != nil {
 			return err
 		}
-	} else if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) && s.Protocol != nil {
+	} else if (sink.IsMySQLCompatibleScheme(sinkURI.Scheme) ||
+		sink.IsPostgresScheme(sinkURI.Scheme) ||
+		sink.IsClickHouseScheme(sinkURI.Scheme) ||
+		sink.IsElasticsearchScheme(sinkURI.Scheme) ||
+		sink.IsRedisScheme(sinkURI.Scheme) ||
+		sink.IsIcebergScheme(sinkURI.Scheme) ||
+		sink.IsCSVScheme(sinkURI.Scheme) ||
+		sink.IsMongoDBScheme(sinkURI.Scheme)) && s.Protocol != nil {
 		return cerror.ErrSinkURIInvalid.GenWithStackByArgs(fmt.Sprintf("protocol %s "+
 			"is incompatible with %s scheme", util.GetOrZero(s.Protocol), sinkURI.Scheme))
 	}
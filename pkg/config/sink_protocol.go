@@ -40,6 +40,8 @@ const (
 	ProtocolCsv
 	ProtocolDebezium
 	ProtocolSimple
+	ProtocolKafkaStreams
+	ProtocolOCDF
 )
 
 // IsBatchEncode returns whether the protocol is a batch encoder.
@@ -72,6 +74,10 @@ func ParseSinkProtocolFromString(protocol string) (Protocol, error) {
 		return ProtocolDebezium, nil
 	case "simple":
 		return ProtocolSimple, nil
+	case "kafka-streams":
+		return ProtocolKafkaStreams, nil
+	case "ocdf":
+		return ProtocolOCDF, nil
 	default:
 		return ProtocolUnknown, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(protocol)
 	}
@@ -100,6 +106,10 @@ func (p Protocol) String() string {
 		return "debezium"
 	case ProtocolSimple:
 		return "simple"
+	case ProtocolKafkaStreams:
+		return "kafka-streams"
+	case ProtocolOCDF:
+		return "ocdf"
 	default:
 		panic("unreachable")
 	}
@@ -62,6 +62,14 @@ func TestParseSinkProtocolFromString(t *testing.T) {
 			protocol:             "open-protocol",
 			expectedProtocolEnum: ProtocolOpen,
 		},
+		{
+			protocol:             "kafka-streams",
+			expectedProtocolEnum: ProtocolKafkaStreams,
+		},
+		{
+			protocol:             "ocdf",
+			expectedProtocolEnum: ProtocolOCDF,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -109,6 +117,14 @@ func TestString(t *testing.T) {
 			protocolEnum:     ProtocolOpen,
 			expectedProtocol: "open-protocol",
 		},
+		{
+			protocolEnum:     ProtocolKafkaStreams,
+			expectedProtocol: "kafka-streams",
+		},
+		{
+			protocolEnum:     ProtocolOCDF,
+			expectedProtocol: "ocdf",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -437,3 +437,70 @@ func TestValidateAndAdjustStorageConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 16, util.GetOrZero(s.Sink.FileIndexWidth))
 }
+
+func TestValidateTableNameMappings(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.NoError(t, err)
+	s := GetDefaultReplicaConfig()
+	s.Sink.TableNameMappings = []*TableNameMapping{{
+		SourceSchema: "prod", SourceTable: "users",
+		TargetSchema: "staging", TargetTable: "users_v2",
+	}, {
+		SourceSchema: "prod", SourceTable: "orders",
+		TargetSchema: "staging", TargetTable: "orders",
+	}}
+	require.NoError(t, s.ValidateAndAdjust(sinkURI))
+
+	s.Sink.TableNameMappings = append(s.Sink.TableNameMappings, &TableNameMapping{
+		SourceSchema: "test", SourceTable: "users",
+		TargetSchema: "STAGING", TargetTable: "Users_V2",
+	})
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI),
+		"table prod.users and test.users are both mapped to STAGING.Users_V2")
+
+	s.Sink.TableNameMappings[2] = &TableNameMapping{
+		SourceSchema: "PROD", SourceTable: "Users",
+		TargetSchema: "staging", TargetTable: "users_v3",
+	}
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "table PROD.Users is mapped more than once")
+
+	s.Sink.TableNameMappings[2] = &TableNameMapping{SourceSchema: "prod", SourceTable: "items"}
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI),
+		"all the schemas and tables of a table name mapping must be specified")
+}
+
+func TestValidatePulsarProducerConfig(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:6650/test?protocol=canal-json")
+	require.NoError(t, err)
+	s := GetDefaultReplicaConfig()
+	s.Sink.PulsarProducerConfig = &PulsarProducerConfig{
+		CompressionType:           util.AddressOf(PulsarCompressionType("zstd")),
+		BatchingEnabled:           util.AddressOf(true),
+		BatchingMaxMessages:       util.AddressOf(100),
+		BatchingMaxPublishDelayMs: util.AddressOf(5),
+		MaxPendingMessages:        util.AddressOf(1000),
+	}
+	require.NoError(t, s.ValidateAndAdjust(sinkURI))
+
+	s.Sink.PulsarProducerConfig.CompressionType = util.AddressOf(PulsarCompressionType("snappy"))
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "unsupported pulsar compression type")
+	s.Sink.PulsarProducerConfig.CompressionType = nil
+
+	s.Sink.PulsarProducerConfig.MaxPendingMessages = util.AddressOf(-1)
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "max-pending-messages")
+	s.Sink.PulsarProducerConfig.MaxPendingMessages = nil
+
+	// Batching must be disabled for canal-json with TiDB extension.
+	sinkURI, err = url.Parse(
+		"pulsar://127.0.0.1:6650/test?protocol=canal-json&enable-tidb-extension=true")
+	require.NoError(t, err)
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "batching-enabled must be false")
+	s.Sink.PulsarProducerConfig.BatchingEnabled = nil
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "batching-enabled must be false")
+	s.Sink.PulsarProducerConfig.BatchingEnabled = util.AddressOf(false)
+	require.NoError(t, s.ValidateAndAdjust(sinkURI))
+}
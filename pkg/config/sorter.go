@@ -27,6 +27,11 @@ type SorterConfig struct {
 	// Cache size of sorter in MB.
 	CacheSizeInMB uint64 `toml:"cache-size-in-mb" json:"cache-size-in-mb"`
 
+	// AdaptiveMemoryThresholdInMB enables the adaptive sorter if it's not 0.
+	// The adaptive sorter sorts events in memory, and migrates them to pebble
+	// once the available memory falls below the threshold.
+	AdaptiveMemoryThresholdInMB uint64 `toml:"adaptive-memory-threshold-in-mb" json:"adaptive-memory-threshold-in-mb"`
+
 	// Deprecated: we don't use this field anymore.
 	MaxMemoryPercentage int `toml:"max-memory-percentage" json:"max-memory-percentage"`
 	// Deprecated: we don't use this field anymore.
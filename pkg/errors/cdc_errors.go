@@ -208,6 +208,10 @@ var (
 		"kafka config item not found",
 		errors.RFCCodeText("CDC:ErrKafkaConfigNotFound"),
 	)
+	ErrKafkaMissingACL = errors.Normalize(
+		"kafka principal %s is missing the %s ACL on the %s %s",
+		errors.RFCCodeText("CDC:ErrKafkaMissingACL"),
+	)
 	// for pulsar
 	ErrPulsarSendMessage = errors.Normalize(
 		"pulsar send message failed",
@@ -289,6 +293,10 @@ var (
 		"initialize meta for redo log",
 		errors.RFCCodeText("CDC:ErrRedoMetaInitialize"),
 	)
+	ErrRedoTargetTsOutOfRange = errors.Normalize(
+		"target ts %d is out of the range [%d, %d] of the redo logs",
+		errors.RFCCodeText("CDC:ErrRedoTargetTsOutOfRange"),
+	)
 	ErrFileSizeExceed = errors.Normalize(
 		"rawData size %d exceeds maximum file size %d",
 		errors.RFCCodeText("CDC:ErrFileSizeExceed"),
@@ -328,6 +336,10 @@ var (
 		"MySQL txn error",
 		errors.RFCCodeText("CDC:ErrMySQLTxnError"),
 	)
+	ErrOutOfOrderEvent = errors.Normalize(
+		"txn of table %d is out of order, commitTs %d is less than the last applied commitTs %d",
+		errors.RFCCodeText("CDC:ErrOutOfOrderEvent"),
+	)
 	ErrMySQLQueryError = errors.Normalize(
 		"MySQL query error",
 		errors.RFCCodeText("CDC:ErrMySQLQueryError"),
@@ -340,6 +352,82 @@ var (
 		"MySQL config invalid",
 		errors.RFCCodeText("CDC:ErrMySQLInvalidConfig"),
 	)
+	ErrMySQLLockContention = errors.Normalize(
+		"lock wait timeout exceeded after %d retries, the downstream is under heavy lock contention, please reduce the concurrent writes to the downstream or increase innodb_lock_wait_timeout",
+		errors.RFCCodeText("CDC:ErrMySQLLockContention"),
+	)
+	ErrPtOSCConflict = errors.Normalize(
+		"pt-online-schema-change is running on table %s in the downstream, the DDL is delayed until it finishes",
+		errors.RFCCodeText("CDC:ErrPtOSCConflict"),
+	)
+	ErrPostgresTxnError = errors.Normalize(
+		"PostgreSQL txn error",
+		errors.RFCCodeText("CDC:ErrPostgresTxnError"),
+	)
+	ErrPostgresConnectionError = errors.Normalize(
+		"PostgreSQL connection error",
+		errors.RFCCodeText("CDC:ErrPostgresConnectionError"),
+	)
+	ErrPostgresInvalidConfig = errors.Normalize(
+		"PostgreSQL config invalid",
+		errors.RFCCodeText("CDC:ErrPostgresInvalidConfig"),
+	)
+	ErrClickHouseTxnError = errors.Normalize(
+		"ClickHouse txn error",
+		errors.RFCCodeText("CDC:ErrClickHouseTxnError"),
+	)
+	ErrClickHouseConnectionError = errors.Normalize(
+		"ClickHouse connection error",
+		errors.RFCCodeText("CDC:ErrClickHouseConnectionError"),
+	)
+	ErrClickHouseInvalidConfig = errors.Normalize(
+		"ClickHouse config invalid",
+		errors.RFCCodeText("CDC:ErrClickHouseInvalidConfig"),
+	)
+	ErrElasticsearchRequestError = errors.Normalize(
+		"Elasticsearch request error",
+		errors.RFCCodeText("CDC:ErrElasticsearchRequestError"),
+	)
+	ErrElasticsearchConnectionError = errors.Normalize(
+		"Elasticsearch connection error",
+		errors.RFCCodeText("CDC:ErrElasticsearchConnectionError"),
+	)
+	ErrElasticsearchInvalidConfig = errors.Normalize(
+		"Elasticsearch config invalid",
+		errors.RFCCodeText("CDC:ErrElasticsearchInvalidConfig"),
+	)
+	ErrRedisRequestError = errors.Normalize(
+		"Redis request error",
+		errors.RFCCodeText("CDC:ErrRedisRequestError"),
+	)
+	ErrRedisConnectionError = errors.Normalize(
+		"Redis connection error",
+		errors.RFCCodeText("CDC:ErrRedisConnectionError"),
+	)
+	ErrRedisInvalidConfig = errors.Normalize(
+		"Redis config invalid",
+		errors.RFCCodeText("CDC:ErrRedisInvalidConfig"),
+	)
+	ErrIcebergCommitFailed = errors.Normalize(
+		"Iceberg commit failed",
+		errors.RFCCodeText("CDC:ErrIcebergCommitFailed"),
+	)
+	ErrIcebergInvalidConfig = errors.Normalize(
+		"Iceberg config invalid",
+		errors.RFCCodeText("CDC:ErrIcebergInvalidConfig"),
+	)
+	ErrMongoDBRequestError = errors.Normalize(
+		"MongoDB request error",
+		errors.RFCCodeText("CDC:ErrMongoDBRequestError"),
+	)
+	ErrMongoDBConnectionError = errors.Normalize(
+		"MongoDB connection error",
+		errors.RFCCodeText("CDC:ErrMongoDBConnectionError"),
+	)
+	ErrMongoDBInvalidConfig = errors.Normalize(
+		"MongoDB config invalid",
+		errors.RFCCodeText("CDC:ErrMongoDBInvalidConfig"),
+	)
 	ErrMySQLWorkerPanic = errors.Normalize(
 		"MySQL worker panic",
 		errors.RFCCodeText("CDC:ErrMySQLWorkerPanic"),
@@ -416,6 +504,10 @@ var (
 		"csv decode failed",
 		errors.RFCCodeText("CDC:ErrCSVDecodeFailed"),
 	)
+	ErrCSVSinkInvalidConfig = errors.Normalize(
+		"CSV sink config invalid",
+		errors.RFCCodeText("CDC:ErrCSVSinkInvalidConfig"),
+	)
 	ErrDebeziumEncodeFailed = errors.Normalize(
 		"debezium encode failed",
 		errors.RFCCodeText("CDC:ErrDebeziumEncodeFailed"),
@@ -442,6 +534,10 @@ var (
 		"create sem version",
 		errors.RFCCodeText("CDC:ErrNewSemVersion"),
 	)
+	ErrNoHealthyPDEndpoint = errors.Normalize(
+		"no healthy PD endpoint in %v",
+		errors.RFCCodeText("CDC:ErrNoHealthyPDEndpoint"),
+	)
 	ErrCheckDirWritable = errors.Normalize(
 		"check dir writable failed",
 		errors.RFCCodeText("CDC:ErrCheckDirWritable"),
@@ -639,6 +735,10 @@ var (
 		"changefeed update error: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateRefused"),
 	)
+	ErrQuotaExceeded = errors.Normalize(
+		"namespace %s exceeds the quota of %s, limit: %d",
+		errors.RFCCodeText("CDC:ErrQuotaExceeded"),
+	)
 	ErrChangefeedUpdateFailedTransaction = errors.Normalize(
 		"changefeed update failed due to unexpected etcd transaction failure: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateFailed"),
@@ -661,6 +761,11 @@ var (
 			" caused by GC. checkpoint-ts %d is earlier than or equal to GC safepoint at %d",
 		errors.RFCCodeText("CDC:ErrSnapshotLostByGC"),
 	)
+	ErrInitialConsistencyMismatch = errors.Normalize(
+		"the downstream is inconsistent with the upstream at start-ts %d, "+
+			"%d of %d sampled rows mismatch, e.g. %s",
+		errors.RFCCodeText("CDC:ErrInitialConsistencyMismatch"),
+	)
 	ErrGCTTLExceeded = errors.Normalize(
 		"the checkpoint-ts(%d) lag of the changefeed(%s) has exceeded "+
 			"the GC TTL and the changefeed is blocking global GC progression",
@@ -679,6 +784,10 @@ var (
 			"if you want to ignore these tables, please set ignore_ineligible_table to true",
 		errors.RFCCodeText("CDC:ErrTableIneligible"),
 	)
+	ErrSchemaValidationFailed = errors.Normalize(
+		"schema validation failed, %s",
+		errors.RFCCodeText("CDC:ErrSchemaValidationFailed"),
+	)
 	ErrInvalidCheckpointTs = errors.Normalize(
 		"checkpointTs(%v) should not larger than resolvedTs(%v)",
 		errors.RFCCodeText("CDC:ErrInvalidCheckpointTs"),
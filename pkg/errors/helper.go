@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -84,6 +85,7 @@ var changefeedUnRetryableErrors = []*errors.Error{
 	ErrKafkaInvalidConfig,
 	ErrMySQLInvalidConfig,
 	ErrStorageSinkInvalidConfig,
+	ErrMySQLLockContention,
 }
 
 // ShouldFailChangefeed returns true if an error is a changefeed not retry error.
@@ -224,6 +226,19 @@ var gRPCStatusCodeMapping = map[errors.RFCErrorCode]codes.Code{
 	ErrIllegalResourcePath.RFCCode():   codes.InvalidArgument,
 	ErrResourceDoesNotExist.RFCCode():  codes.NotFound,
 	ErrResourceConflict.RFCCode():      codes.FailedPrecondition,
+
+	ErrChangeFeedNotExists.RFCCode():            codes.NotFound,
+	ErrChangeFeedAlreadyExists.RFCCode():        codes.AlreadyExists,
+	ErrCaptureNotExist.RFCCode():                codes.NotFound,
+	ErrAPIInvalidParam.RFCCode():                codes.InvalidArgument,
+	ErrPeerMessageIllegalMeta.RFCCode():         codes.InvalidArgument,
+	ErrPeerMessageReceiverMismatch.RFCCode():    codes.FailedPrecondition,
+	ErrPeerMessageStaleConnection.RFCCode():     codes.FailedPrecondition,
+	ErrPeerMessageDuplicateConnection.RFCCode(): codes.AlreadyExists,
+	ErrPeerMessageToManyPeers.RFCCode():         codes.ResourceExhausted,
+	ErrPeerMessageTaskQueueCongested.RFCCode():  codes.ResourceExhausted,
+	ErrPeerMessageTopicCongested.RFCCode():      codes.ResourceExhausted,
+	ErrPeerMessageServerClosed.RFCCode():        codes.Unavailable,
 }
 
 // GRPCStatusCode returns the gRPC status code for the given error.
@@ -249,3 +264,100 @@ func GRPCStatusCode(err error) codes.Code {
 	}
 	return codes.Internal
 }
+
+const grpcErrorInfoCauseKey = "cause"
+
+// ToGRPCError converts an error to a gRPC error. The status code is decided by
+// GRPCStatusCode, and the RFC error code is attached as the reason of an
+// ErrorInfo detail, so that clients can check the error programmatically
+// instead of matching the message.
+func ToGRPCError(errIn error) error {
+	if errIn == nil {
+		return nil
+	}
+	if _, ok := status.FromError(errIn); ok {
+		return errIn
+	}
+
+	var (
+		normalizedErr *errors.Error
+		metadata      map[string]string
+		rfcCode       errors.RFCErrorCode
+		errMsg        string
+	)
+	if As(errIn, &normalizedErr) {
+		rfcCode = normalizedErr.RFCCode()
+		if cause := normalizedErr.Cause(); cause != nil {
+			metadata = map[string]string{
+				grpcErrorInfoCauseKey: cause.Error(),
+			}
+		}
+		errMsg = normalizedErr.GetMsg()
+	} else {
+		rfcCode = ErrUnknown.RFCCode()
+		errMsg = errIn.Error()
+	}
+
+	code := GRPCStatusCode(errIn)
+	st, err := status.New(code, errMsg).
+		WithDetails(&errdetails.ErrorInfo{
+			Reason:   string(rfcCode),
+			Metadata: metadata,
+		})
+	if err != nil {
+		return status.New(code, errMsg).Err()
+	}
+	return st.Err()
+}
+
+// FromGRPCError converts a gRPC error returned by ToGRPCError to a normalized
+// error.
+func FromGRPCError(errIn error) error {
+	if errIn == nil {
+		return nil
+	}
+	st, ok := status.FromError(errIn)
+	if !ok {
+		return errIn
+	}
+	errInfo := grpcErrorInfo(st)
+	if errInfo == nil || errInfo.Reason == "" {
+		return ErrUnknown.GenWithStack(st.Message())
+	}
+
+	normalizedErr := errors.Normalize(st.Message(), errors.RFCCodeText(errInfo.Reason))
+	if causeMsg := errInfo.Metadata[grpcErrorInfoCauseKey]; causeMsg != "" {
+		return normalizedErr.Wrap(errors.New(causeMsg)).GenWithStackByArgs()
+	}
+	return normalizedErr.GenWithStackByArgs()
+}
+
+// IsChangefeedNotFoundError checks whether the error, which may be returned
+// by a gRPC call, indicates that the changefeed does not exist.
+func IsChangefeedNotFoundError(err error) bool {
+	return isGRPCErrorWithRFCCode(err, ErrChangeFeedNotExists)
+}
+
+func isGRPCErrorWithRFCCode(err error, rfcError *errors.Error) bool {
+	if err == nil {
+		return false
+	}
+	if rfcError.Equal(err) {
+		return true
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	errInfo := grpcErrorInfo(st)
+	return errInfo != nil && errInfo.Reason == string(rfcError.RFCCode())
+}
+
+func grpcErrorInfo(st *status.Status) *errdetails.ErrorInfo {
+	for _, detail := range st.Details() {
+		if errInfo, ok := detail.(*errdetails.ErrorInfo); ok {
+			return errInfo
+		}
+	}
+	return nil
+}
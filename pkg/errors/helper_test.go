@@ -21,7 +21,9 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestWrapError(t *testing.T) {
@@ -223,3 +225,75 @@ func TestGRPCStatusCode(t *testing.T) {
 		require.Equal(t, gRPCCode, GRPCStatusCode(err))
 	}
 }
+
+func TestToGRPCError(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ToGRPCError(nil))
+
+	// already a gRPC error
+	err := status.New(codes.NotFound, "not found").Err()
+	require.Equal(t, err, ToGRPCError(err))
+
+	// unknown error
+	err = errors.New("unknown error")
+	gerr := ToGRPCError(err)
+	require.Equal(t, codes.Unknown, status.Code(gerr))
+	st, ok := status.FromError(gerr)
+	require.True(t, ok)
+	require.Equal(t, err.Error(), st.Message())
+	require.Len(t, st.Details(), 1)
+	errInfo := st.Details()[0].(*errdetails.ErrorInfo)
+	require.Equal(t, string(ErrUnknown.RFCCode()), errInfo.Reason)
+
+	// changefeed not found
+	err = ErrChangeFeedNotExists.GenWithStackByArgs("test")
+	gerr = ToGRPCError(err)
+	require.Equal(t, codes.NotFound, status.Code(gerr))
+	st, ok = status.FromError(gerr)
+	require.True(t, ok)
+	require.Equal(t, "changefeed not exists, test", st.Message())
+	require.Len(t, st.Details(), 1)
+	errInfo = st.Details()[0].(*errdetails.ErrorInfo)
+	require.Equal(t, string(ErrChangeFeedNotExists.RFCCode()), errInfo.Reason)
+
+	// changefeed already exists
+	gerr = ToGRPCError(ErrChangeFeedAlreadyExists.GenWithStackByArgs("test"))
+	require.Equal(t, codes.AlreadyExists, status.Code(gerr))
+}
+
+func TestFromGRPCError(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, FromGRPCError(nil))
+
+	// not a gRPC error
+	err := errors.New("unknown error")
+	require.Equal(t, err, FromGRPCError(err))
+
+	// gRPC error without details
+	err = FromGRPCError(status.New(codes.Internal, "internal").Err())
+	require.True(t, ErrUnknown.Equal(err))
+
+	srvErr := ErrChangeFeedNotExists.GenWithStackByArgs("test")
+	clientErr := FromGRPCError(ToGRPCError(srvErr))
+	require.True(t, ErrChangeFeedNotExists.Equal(clientErr))
+	require.Equal(t, srvErr.Error(), clientErr.Error())
+
+	srvErr = WrapError(ErrPeerMessageDecodeError, errors.New("bad data"))
+	clientErr = FromGRPCError(ToGRPCError(srvErr))
+	require.True(t, Is(clientErr, ErrPeerMessageDecodeError))
+	require.Equal(t, "bad data", Cause(clientErr).Error())
+}
+
+func TestIsChangefeedNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	err := ErrChangeFeedNotExists.GenWithStackByArgs("test")
+	require.True(t, IsChangefeedNotFoundError(err))
+	require.True(t, IsChangefeedNotFoundError(ToGRPCError(err)))
+	require.False(t, IsChangefeedNotFoundError(nil))
+	require.False(t, IsChangefeedNotFoundError(ErrCaptureNotExist.GenWithStackByArgs("test")))
+	require.False(t, IsChangefeedNotFoundError(ToGRPCError(ErrCaptureNotExist.GenWithStackByArgs("test"))))
+	require.False(t, IsChangefeedNotFoundError(status.New(codes.NotFound, "not found").Err()))
+}
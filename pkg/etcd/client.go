@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	cbackoff "github.com/cenkalti/backoff/v4"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -59,7 +60,11 @@ const (
 	backoffMaxDelayInMs = 60 * 1000
 	// If no msg comes from an etcd watchCh for etcdWatchChTimeoutDuration long,
 	// we should cancel the watchCh and request a new watchCh from etcd client
-	etcdWatchChTimeoutDuration = 10 * time.Second
+	etcdWatchChTimeoutDuration = 30 * time.Second
+	// etcdWatchReconnectInitialBackoff and etcdWatchReconnectMaxBackoff
+	// bound the exponential backoff between reconnection attempts of a watchCh.
+	etcdWatchReconnectInitialBackoff = 100 * time.Millisecond
+	etcdWatchReconnectMaxBackoff     = 30 * time.Second
 	// If no msg comes from an etcd watchCh for etcdRequestProgressDuration long,
 	// we should call RequestProgress of etcd client
 	etcdRequestProgressDuration = 1 * time.Second
@@ -239,11 +244,31 @@ func (c *Client) WatchWithChan(
 	ticker := c.clock.Ticker(etcdRequestProgressDuration)
 	lastReceivedResponseTime := c.clock.Now()
 
+	reconnectBackoff := newWatchReconnectBackoff()
+	// reconnectCh is not nil if a reconnection is scheduled.
+	var reconnectCh <-chan time.Time
+	reconnectCounter := etcdWatchReconnectCounter.WithLabelValues(role)
+	watchLagGauge := etcdWatchLagGauge.WithLabelValues(role)
+
+	reconnect := func() {
+		// cancel the last cancel func to reset it
+		cancel()
+		watchCtx, cancel = context.WithCancel(ctx)
+		// to avoid possible context leak warning from govet
+		_ = cancel
+		watchCh = c.cli.Watch(watchCtx, key,
+			clientV3.WithPrefix(), clientV3.WithRev(lastRevision))
+		// we need to reset lastReceivedResponseTime after reset Watch
+		lastReceivedResponseTime = c.clock.Now()
+		reconnectCounter.Inc()
+	}
+
 	defer func() {
 		// Using closures to handle changes to the cancel function
 		ticker.Stop()
 		cancel()
 		close(outCh)
+		etcdWatchLagGauge.DeleteLabelValues(role)
 
 		log.Info("WatchWithChan exited", zap.String("role", role))
 	}()
@@ -252,8 +277,22 @@ func (c *Client) WatchWithChan(
 		select {
 		case <-ctx.Done():
 			return
-		case response := <-watchCh:
+		case response, ok := <-watchCh:
+			if !ok {
+				// The watchCh is closed by the etcd client, e.g. the
+				// connection is dropped, recreate it after a backoff.
+				delay := reconnectBackoff.NextBackOff()
+				log.Warn("etcd client watchCh is closed, reconnect it later",
+					zap.Duration("backoff", delay),
+					zap.Int64("revision", lastRevision),
+					zap.String("role", role))
+				watchCh = nil
+				reconnectCh = c.clock.After(delay)
+				continue
+			}
 			lastReceivedResponseTime = c.clock.Now()
+			watchLagGauge.Set(0)
+			reconnectBackoff.Reset()
 			if response.Err() == nil && !response.IsProgressNotify() {
 				lastRevision = response.Header.Revision
 			}
@@ -277,29 +316,46 @@ func (c *Client) WatchWithChan(
 			}
 
 			ticker.Reset(etcdRequestProgressDuration)
+		case <-reconnectCh:
+			reconnectCh = nil
+			log.Info("etcd client reconnect watchCh",
+				zap.Int64("revision", lastRevision),
+				zap.String("role", role))
+			reconnect()
 		case <-ticker.C:
+			watchLagGauge.Set(c.clock.Since(lastReceivedResponseTime).Seconds())
+			if reconnectCh != nil {
+				// A reconnection is already scheduled.
+				continue
+			}
 			if err := c.RequestProgress(ctx); err != nil {
 				log.Warn("failed to request progress for etcd watcher", zap.Error(err))
 			}
 			if c.clock.Since(lastReceivedResponseTime) >= etcdWatchChTimeoutDuration {
-				// cancel the last cancel func to reset it
 				log.Warn("etcd client watchCh blocking too long, reset the watchCh",
 					zap.Duration("duration", c.clock.Since(lastReceivedResponseTime)),
 					zap.Stack("stack"),
 					zap.String("role", role))
-				cancel()
-				watchCtx, cancel = context.WithCancel(ctx)
-				// to avoid possible context leak warning from govet
-				_ = cancel
-				watchCh = c.cli.Watch(watchCtx, key,
-					clientV3.WithPrefix(), clientV3.WithRev(lastRevision))
-				// we need to reset lastReceivedResponseTime after reset Watch
-				lastReceivedResponseTime = c.clock.Now()
+				// The timeout already throttles reconnections, so reconnect
+				// immediately and only advance the backoff.
+				reconnectBackoff.NextBackOff()
+				reconnect()
 			}
 		}
 	}
 }
 
+// newWatchReconnectBackoff returns an exponential backoff for reconnecting
+// a watchCh, it never stops.
+func newWatchReconnectBackoff() *cbackoff.ExponentialBackOff {
+	b := cbackoff.NewExponentialBackOff()
+	b.InitialInterval = etcdWatchReconnectInitialBackoff
+	b.MaxInterval = etcdWatchReconnectMaxBackoff
+	b.MaxElapsedTime = 0
+	b.Reset()
+	return b
+}
+
 // RequestProgress requests a progress notify response be sent in all watch channels.
 func (c *Client) RequestProgress(ctx context.Context) error {
 	return c.cli.RequestProgress(ctx)
@@ -15,12 +15,14 @@ package etcd
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -195,7 +197,7 @@ func TestWatchChBlocked(t *testing.T) {
 	r := <-outCh
 	receivedRes = append(receivedRes, r)
 	// move time forward
-	mockClock.Add(time.Second * 30)
+	mockClock.Add(etcdWatchChTimeoutDuration * 3)
 
 	for r := range outCh {
 		receivedRes = append(receivedRes, r)
@@ -255,7 +257,7 @@ func TestOutChBlocked(t *testing.T) {
 	r := <-outCh
 	receivedRes = append(receivedRes, r)
 	// move time forward
-	mockClock.Add(time.Second * 30)
+	mockClock.Add(etcdWatchChTimeoutDuration * 3)
 
 	for r := range outCh {
 		receivedRes = append(receivedRes, r)
@@ -304,7 +306,7 @@ func TestRevisionNotFallBack(t *testing.T) {
 	// wait for WatchWithChan set up
 	<-outCh
 	// move time forward
-	mockClock.Add(time.Second * 30)
+	mockClock.Add(etcdWatchChTimeoutDuration * 3)
 	// make sure watchCh has been reset since timeout
 	require.True(t, atomic.LoadInt32(watcher.resetCount) > 1)
 	// make sure revision in WatchWitchChan does not fall back
@@ -313,6 +315,84 @@ func TestRevisionNotFallBack(t *testing.T) {
 	require.Equal(t, atomic.LoadInt64(watcher.rev), revision)
 }
 
+// mockDroppingWatcher drops the watchCh after sending n responses.
+type mockDroppingWatcher struct {
+	clientv3.Watcher
+	n int
+
+	mu        sync.Mutex
+	revisions []int64
+}
+
+func (m *mockDroppingWatcher) Watch(
+	ctx context.Context, key string, opts ...clientv3.OpOption,
+) clientv3.WatchChan {
+	op := &clientv3.Op{}
+	for _, opt := range opts {
+		opt(op)
+	}
+	m.mu.Lock()
+	m.revisions = append(m.revisions, op.Rev())
+	m.mu.Unlock()
+
+	ch := make(chan clientv3.WatchResponse)
+	go func() {
+		defer close(ch)
+		for i := 0; i < m.n; i++ {
+			resp := clientv3.WatchResponse{Events: []*clientv3.Event{{}}}
+			resp.Header.Revision = op.Rev() + int64(i)
+			select {
+			case ch <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (m *mockDroppingWatcher) RequestProgress(ctx context.Context) error {
+	return nil
+}
+
+func TestWatchReconnectAfterDropped(t *testing.T) {
+	t.Parallel()
+
+	cli := clientv3.NewCtxClient(context.TODO())
+	watcher := &mockDroppingWatcher{n: 3}
+	cli.Watcher = watcher
+	watchCli := Wrap(cli, nil)
+
+	role := "testWatchReconnectAfterDropped"
+	outCh := make(chan clientv3.WatchResponse, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	go func() {
+		watchCli.WatchWithChan(ctx, outCh, "key", role,
+			clientv3.WithPrefix(), clientv3.WithRev(1))
+	}()
+
+	// Receive events across 3 watchCh.
+	revisions := make([]int64, 0, 9)
+	for r := range outCh {
+		revisions = append(revisions, r.Header.Revision)
+		if len(revisions) == 9 {
+			cancel()
+		}
+	}
+	require.Equal(t, []int64{1, 2, 3, 3, 4, 5, 5, 6, 7}, revisions)
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	// The watchCh is recreated from the last received revision.
+	require.GreaterOrEqual(t, len(watcher.revisions), 3)
+	require.Equal(t, []int64{1, 3, 5}, watcher.revisions[:3])
+
+	metric := &dto.Metric{}
+	require.Nil(t, etcdWatchReconnectCounter.WithLabelValues(role).Write(metric))
+	require.GreaterOrEqual(t, metric.GetCounter().GetValue(), float64(2))
+}
+
 type mockTxn struct {
 	ctx  context.Context
 	mode int
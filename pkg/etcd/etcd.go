@@ -145,6 +145,7 @@ type CDCEtcdClient interface {
 	CreateChangefeedInfo(context.Context,
 		*model.UpstreamInfo,
 		*model.ChangeFeedInfo,
+		int,
 	) error
 
 	UpdateChangefeedAndUpstream(ctx context.Context,
@@ -421,23 +422,27 @@ func (c *CDCEtcdClientImpl) RevokeAllLeases(ctx context.Context, leases map[stri
 }
 
 // CreateChangefeedInfo creates a change feed info into etcd and fails if it is already exists.
+// If maxChangefeeds is not 0, it also fails if the namespace of the changefeed
+// already has maxChangefeeds changefeeds.
 func (c *CDCEtcdClientImpl) CreateChangefeedInfo(
 	ctx context.Context, upstreamInfo *model.UpstreamInfo, info *model.ChangeFeedInfo,
+	maxChangefeeds int,
 ) error {
-	return c.saveChangefeedAndUpstreamInfo(ctx, "Create", upstreamInfo, info)
+	return c.saveChangefeedAndUpstreamInfo(ctx, "Create", upstreamInfo, info, maxChangefeeds)
 }
 
 // UpdateChangefeedAndUpstream updates the changefeed's info and its upstream info into etcd
 func (c *CDCEtcdClientImpl) UpdateChangefeedAndUpstream(
 	ctx context.Context, upstreamInfo *model.UpstreamInfo, changeFeedInfo *model.ChangeFeedInfo,
 ) error {
-	return c.saveChangefeedAndUpstreamInfo(ctx, "Update", upstreamInfo, changeFeedInfo)
+	return c.saveChangefeedAndUpstreamInfo(ctx, "Update", upstreamInfo, changeFeedInfo, 0)
 }
 
 // saveChangefeedAndUpstreamInfo stores changefeed info and its upstream info into etcd
 func (c *CDCEtcdClientImpl) saveChangefeedAndUpstreamInfo(
 	ctx context.Context, operation string,
 	upstreamInfo *model.UpstreamInfo, info *model.ChangeFeedInfo,
+	maxChangefeeds int,
 ) error {
 	cmps := []clientv3.Cmp{}
 	opsThen := []clientv3.Op{}
@@ -508,6 +513,23 @@ func (c *CDCEtcdClientImpl) saveChangefeedAndUpstreamInfo(
 		}
 	}
 
+	if maxChangefeeds > 0 {
+		// Count the changefeeds of the namespace and make sure that none is
+		// created before the transaction is committed, so that concurrent
+		// creations can't exceed the quota.
+		listKey := GetEtcdKeyChangeFeedList(c.ClusterID, info.Namespace) + "/"
+		listResp, err := c.Client.Get(ctx, listKey, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return errors.WrapError(errors.ErrPDEtcdAPIError, err)
+		}
+		if listResp.Count >= int64(maxChangefeeds) {
+			return errors.ErrQuotaExceeded.GenWithStackByArgs(
+				info.Namespace, "max-changefeeds", maxChangefeeds)
+		}
+		cmps = append(cmps, clientv3.Compare(
+			clientv3.ModRevision(listKey), "<", listResp.Header.Revision+1).WithPrefix())
+	}
+
 	cmps = append(cmps,
 		clientv3.Compare(clientv3.ModRevision(infoKey), "=", infoModRevsion),
 		clientv3.Compare(clientv3.ModRevision(jobKey), "=", jobModRevision),
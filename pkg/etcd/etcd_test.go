@@ -229,15 +229,50 @@ func TestCreateChangefeed(t *testing.T) {
 
 	upstreamInfo := &model.UpstreamInfo{ID: 1}
 	err := s.client.CreateChangefeedInfo(ctx,
-		upstreamInfo, detail)
+		upstreamInfo, detail, 0)
 	require.NoError(t, err)
 
 	err = s.client.CreateChangefeedInfo(ctx,
-		upstreamInfo, detail)
+		upstreamInfo, detail, 0)
 	require.True(t, cerror.ErrMetaOpFailed.Equal(err))
 	require.Equal(t, "[DFLOW:ErrMetaOpFailed]unexpected meta operation failure: Create changefeed test/create-changefeed", err.Error())
 }
 
+func TestCreateChangefeedWithQuota(t *testing.T) {
+	t.Parallel()
+
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	upstreamInfo := &model.UpstreamInfo{ID: 1}
+	newInfo := func(namespace, id string) *model.ChangeFeedInfo {
+		return &model.ChangeFeedInfo{
+			UpstreamID: 1,
+			Namespace:  namespace,
+			ID:         id,
+			SinkURI:    "blackhole://",
+		}
+	}
+
+	err := s.client.CreateChangefeedInfo(ctx, upstreamInfo, newInfo("ns", "cf1"), 2)
+	require.NoError(t, err)
+	err = s.client.CreateChangefeedInfo(ctx, upstreamInfo, newInfo("ns", "cf2"), 2)
+	require.NoError(t, err)
+	err = s.client.CreateChangefeedInfo(ctx, upstreamInfo, newInfo("ns", "cf3"), 2)
+	require.True(t, cerror.ErrQuotaExceeded.Equal(err))
+	// Other namespaces are not affected.
+	err = s.client.CreateChangefeedInfo(ctx, upstreamInfo, newInfo("ns2", "cf3"), 2)
+	require.NoError(t, err)
+
+	// The quota is released after a changefeed is removed.
+	err = s.client.DeleteChangeFeedInfo(ctx, model.ChangeFeedID{Namespace: "ns", ID: "cf1"})
+	require.NoError(t, err)
+	err = s.client.CreateChangefeedInfo(ctx, upstreamInfo, newInfo("ns", "cf3"), 2)
+	require.NoError(t, err)
+}
+
 func TestUpdateChangefeedAndUpstream(t *testing.T) {
 	t.Parallel()
 
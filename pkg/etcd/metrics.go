@@ -31,8 +31,26 @@ var etcdStateGauge = prometheus.NewGaugeVec(
 		Help:      "Etcd client states.",
 	}, []string{"type"})
 
+var etcdWatchReconnectCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "owner",
+		Name:      "etcd_reconnect_total",
+		Help:      "The total number of etcd watch reconnections.",
+	}, []string{"role"})
+
+var etcdWatchLagGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "owner",
+		Name:      "etcd_watch_lag_seconds",
+		Help:      "The time since the last response received from etcd watch.",
+	}, []string{"role"})
+
 // InitMetrics registers the etcd request counter.
 func InitMetrics(registry *prometheus.Registry) {
 	prometheus.MustRegister(etcdStateGauge)
 	registry.MustRegister(etcdRequestCounter)
+	registry.MustRegister(etcdWatchReconnectCounter)
+	registry.MustRegister(etcdWatchLagGauge)
 }
@@ -24,6 +24,12 @@ import (
 const (
 	// SyncPointTable is the tale name use to write ts-map when sync-point is enable.
 	SyncPointTable = "syncpoint_v1"
+	// BinlogPositionTable is the table name use to write the binlog positions
+	// of a MySQL downstream when track-binlog-position is enabled.
+	BinlogPositionTable = "_ticdc_binlog_positions"
+	// SkippedDDLTable is the table name use to record the DDLs skipped by
+	// a MySQL downstream when ddl-fallback-policy is skip.
+	SkippedDDLTable = "_ticdc_skipped_ddls"
 	// TiCDCSystemSchema is the schema only use by TiCDC.
 	TiCDCSystemSchema = "tidb_cdc"
 )
@@ -0,0 +1,128 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// SinkURIAnalyzer reports sink URIs that are passed to logging or error
+// functions without being masked, because they may contain passwords.
+var SinkURIAnalyzer = &analysis.Analyzer{
+	Name:     "sinkuri",
+	Doc:      "check that sink URIs are masked before being logged or put in errors",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSinkURI,
+}
+
+// errorsGenFuncs are the methods of *errors.Error which format their arguments.
+var errorsGenFuncs = map[string]struct{}{
+	"GenWithStack":       {},
+	"GenWithStackByArgs": {},
+	"FastGen":            {},
+	"FastGenByArgs":      {},
+}
+
+func runSinkURI(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if !isOutputFunc(pass, call) {
+			return
+		}
+		for _, arg := range call.Args {
+			if name, ok := unmaskedSinkURI(pass, arg); ok {
+				pass.Reportf(arg.Pos(),
+					"sink URI %s may contain password, mask it with util.MaskSinkURI first", name)
+			}
+		}
+	})
+	return nil, nil
+}
+
+// isOutputFunc returns true if the call writes its arguments to logs or errors.
+func isOutputFunc(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+	switch fn.Pkg().Path() {
+	case "github.com/pingcap/log":
+		return true
+	case "go.uber.org/zap":
+		return fn.Name() == "String" || fn.Name() == "Any" || fn.Name() == "Stringer"
+	case "fmt":
+		return fn.Name() == "Errorf"
+	case "github.com/pingcap/errors":
+		_, ok := errorsGenFuncs[fn.Name()]
+		return ok
+	}
+	return false
+}
+
+// unmaskedSinkURI returns the name of the expression if it looks like
+// a raw sink URI, e.g. `sinkURI`, `info.SinkURI` or `sinkURI.String()`.
+func unmaskedSinkURI(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "String" || len(call.Args) != 0 {
+			return "", false
+		}
+		expr = sel.X
+	}
+	var name string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	default:
+		return "", false
+	}
+	lower := strings.ToLower(name)
+	if !strings.Contains(lower, "sinkuri") || strings.Contains(lower, "mask") {
+		return "", false
+	}
+	if !isURIType(pass.TypesInfo.TypeOf(expr)) {
+		return "", false
+	}
+	return name, true
+}
+
+func isURIType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		return basic.Kind() == types.String
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "net/url" && named.Obj().Name() == "URL"
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+type stubImporter map[string]*types.Package
+
+func (m stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := m[path]; ok {
+		return pkg, nil
+	}
+	return importer.Default().Import(path)
+}
+
+func checkPackage(
+	t *testing.T, fset *token.FileSet, imp types.Importer, path, file string,
+) (*ast.File, *types.Package, *types.Info) {
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	pkg, err := (&types.Config{Importer: imp}).Check(path, fset, []*ast.File{f}, info)
+	require.NoError(t, err)
+	return f, pkg, info
+}
+
+func TestSinkURIAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+	imp := stubImporter{}
+	_, logPkg, _ := checkPackage(t, fset, imp, "github.com/pingcap/log",
+		filepath.Join("testdata", "src", "github.com", "pingcap", "log", "log.go"))
+	imp[logPkg.Path()] = logPkg
+	f, pkg, info := checkPackage(t, fset, imp, "a",
+		filepath.Join("testdata", "src", "a", "a.go"))
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer:  SinkURIAnalyzer,
+		Fset:      fset,
+		Files:     []*ast.File{f},
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf: map[*analysis.Analyzer]interface{}{
+			inspect.Analyzer: inspector.New([]*ast.File{f}),
+		},
+		Report: func(d analysis.Diagnostic) { diagnostics = append(diagnostics, d) },
+	}
+	_, err := SinkURIAnalyzer.Run(pass)
+	require.NoError(t, err)
+
+	// Every line with a `want` comment must be reported, and nothing else.
+	wantLines := make(map[int]struct{})
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "// want") {
+				wantLines[fset.Position(c.Pos()).Line] = struct{}{}
+			}
+		}
+	}
+	gotLines := make(map[int]struct{})
+	for _, d := range diagnostics {
+		gotLines[fset.Position(d.Pos).Line] = struct{}{}
+	}
+	require.Len(t, wantLines, 3)
+	require.Equal(t, wantLines, gotLines)
+}
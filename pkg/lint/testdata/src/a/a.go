@@ -0,0 +1,25 @@
+package a
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pingcap/log"
+)
+
+func mask(uri string) string { return uri }
+
+type info struct {
+	SinkURI string
+}
+
+func f(sinkURI *url.URL, cfg info, sinkURIUpdated bool) {
+	log.Info("sink uri", sinkURI)             // want "sink URI sinkURI may contain password"
+	log.Info("sink uri", sinkURI.String())    // want "sink URI sinkURI may contain password"
+	_ = fmt.Errorf("bad uri %s", cfg.SinkURI) // want "sink URI SinkURI may contain password"
+
+	maskedSinkURI := mask(cfg.SinkURI)
+	log.Info("sink uri", maskedSinkURI)
+	log.Info("sink uri", mask(sinkURI.String()))
+	log.Info("sink uri updated", sinkURIUpdated)
+}
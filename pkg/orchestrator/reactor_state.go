@@ -190,6 +190,9 @@ type ChangefeedReactorState struct {
 
 	pendingPatches        []DataPatch
 	skipPatchesInThisTick bool
+	// configHash is the hash of Info.Config, it is used to skip reloading
+	// the config if nothing changed.
+	configHash string
 }
 
 // NewChangefeedReactorState creates a new changefeed reactor state
@@ -373,6 +376,42 @@ func (s *ChangefeedReactorState) CleanUpTaskPositions() {
 	}
 }
 
+// ResetChangefeed restarts the changefeed from the start ts, the info and the
+// status are patched together so they are written in one etcd transaction.
+// The epoch is updated so that the processors drop all the table states.
+func (s *ChangefeedReactorState) ResetChangefeed(startTs uint64, epoch uint64) {
+	s.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		info.StartTs = startTs
+		info.State = model.StateNormal
+		info.AdminJobType = model.AdminNone
+		info.Error = nil
+		if epoch > 0 {
+			info.Epoch = epoch
+		}
+		return info, true, nil
+	})
+	s.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		var oldCheckpointTs uint64
+		if status != nil {
+			oldCheckpointTs = status.CheckpointTs
+		}
+		log.Info("resetting the changefeed",
+			zap.String("namespace", s.ID.Namespace),
+			zap.String("changefeed", s.ID.ID),
+			zap.Uint64("oldCheckpointTs", oldCheckpointTs),
+			zap.Uint64("startTs", startTs),
+			zap.Uint64("epoch", epoch))
+		return &model.ChangeFeedStatus{
+			CheckpointTs:      startTs,
+			MinTableBarrierTs: startTs,
+			AdminJobType:      model.AdminNone,
+		}, true, nil
+	})
+}
+
 // UpdateChangefeedState returns the task status of the changefeed.
 func (s *ChangefeedReactorState) UpdateChangefeedState(feedState model.FeedState,
 	adminJobType model.AdminJobType,
@@ -434,7 +473,10 @@ func (s *ChangefeedReactorState) Update(key util.EtcdKey, value []byte, _ bool)
 
 // UpdateCDCKey updates the state by a parsed etcd key
 func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) error {
-	var e interface{}
+	var (
+		e       interface{}
+		oldInfo *model.ChangeFeedInfo
+	)
 	switch key.Tp {
 	case etcd.CDCKeyTypeChangefeedInfo:
 		if key.ChangefeedID != s.ID {
@@ -442,8 +484,10 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 		}
 		if value == nil {
 			s.Info = nil
+			s.configHash = ""
 			return nil
 		}
+		oldInfo = s.Info
 		s.Info = new(model.ChangeFeedInfo)
 		e = s.Info
 	case etcd.CDCKeyTypeChangeFeedStatus:
@@ -474,6 +518,23 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 		return errors.Trace(err)
 	}
 	if key.Tp == etcd.CDCKeyTypeChangefeedInfo {
+		hash, err := s.Info.ComputeConfigHash()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Only reload the config when it is really changed, so that the owner
+		// and processors keep using the same config object for other updates,
+		// such as state or error changes.
+		if oldInfo != nil && oldInfo.Config != nil && s.configHash == hash {
+			s.Info.Config = oldInfo.Config
+		} else if oldInfo != nil {
+			log.Info("changefeed config changed",
+				zap.String("namespace", s.ID.Namespace),
+				zap.String("changefeed", s.ID.ID),
+				zap.String("oldConfigHash", s.configHash),
+				zap.String("newConfigHash", hash))
+		}
+		s.configHash = hash
 		s.Info.VerifyAndComplete()
 	}
 	return nil
@@ -351,6 +351,28 @@ func TestChangefeedStateUpdate(t *testing.T) {
 	}
 }
 
+func TestChangefeedStateReuseUnchangedConfig(t *testing.T) {
+	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID("test1"))
+	key := util.NewEtcdKey(etcd.DefaultClusterAndNamespacePrefix + "/changefeed/info/test1")
+
+	err := state.Update(key, []byte(`{"sink-uri":"blackhole://","state":"normal","config":{}}`), false)
+	require.Nil(t, err)
+	cfg := state.Info.Config
+
+	// Only the state is changed, the config object is reused.
+	err = state.Update(key, []byte(`{"sink-uri":"blackhole://","state":"stopped","config":{}}`), false)
+	require.Nil(t, err)
+	require.Equal(t, model.StateStopped, state.Info.State)
+	require.Same(t, cfg, state.Info.Config)
+
+	// The config is changed, it must be reloaded.
+	err = state.Update(key, []byte(`{"sink-uri":"blackhole://","state":"stopped","config":{"case-sensitive":true}}`), false)
+	require.Nil(t, err)
+	require.NotSame(t, cfg, state.Info.Config)
+	require.True(t, state.Info.Config.CaseSensitive)
+}
+
 func TestPatchInfo(t *testing.T) {
 	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
 		model.DefaultChangeFeedID("test1"))
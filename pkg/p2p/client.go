@@ -62,6 +62,17 @@ type MessageClientConfig struct {
 	ClientVersion string
 	// MaxRecvMsgSize is the maximum message size in bytes TiCDC can receive.
 	MaxRecvMsgSize int
+
+	// After a duration of this time if the client doesn't see any activity it
+	// pings the server to see if the transport is still alive.
+	KeepAliveTime time.Duration
+	// After having pinged for keepalive check, the client waits for a duration
+	// of Timeout and if no activity is seen even after that the connection is
+	// closed.
+	KeepAliveTimeout time.Duration
+	// KeepAlivePermitWithoutStream allows the client to send keepalive pings
+	// even if there are no active streams.
+	KeepAlivePermitWithoutStream bool
 }
 
 type localMessageClient struct {
@@ -24,6 +24,7 @@ import (
 	proto "github.com/pingcap/tiflow/proto/p2p"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 type clientConnectOptions struct {
@@ -37,6 +38,8 @@ type clientConnectOptions struct {
 	// timeout specifies the DialTimeout of the connection.
 	timeout        time.Duration
 	maxRecvMsgSize int
+	// keepalive is not set if its Time is zero.
+	keepalive keepalive.ClientParameters
 }
 
 type cancelFn = func()
@@ -61,15 +64,19 @@ func (c *clientConnectorImpl) Connect(opts clientConnectOptions) (proto.CDCPeerT
 		return nil, nil, errors.Trace(err)
 	}
 
-	conn, err := grpc.Dial(
-		opts.addr,
+	dialOptions := []grpc.DialOption{
 		securityOption,
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(opts.maxRecvMsgSize)),
 		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
 			return net.DialTimeout(opts.network, s, opts.timeout)
 		}),
 		// We do not need a unary interceptor since we are not making any unary calls.
-		grpc.WithStreamInterceptor(grpcClientMetrics.StreamClientInterceptor()))
+		grpc.WithStreamInterceptor(grpcClientMetrics.StreamClientInterceptor()),
+	}
+	if opts.keepalive.Time > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(opts.keepalive))
+	}
+	conn, err := grpc.Dial(opts.addr, dialOptions...)
 	if err != nil {
 		log.Warn("gRPC dial error", zap.Error(err))
 		return nil, nil, errors.Trace(err)
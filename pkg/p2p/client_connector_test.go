@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/proto/p2p"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 type mockService struct {
@@ -79,3 +80,84 @@ func TestClientConnector(t *testing.T) {
 	grpcServer.Stop()
 	wg.Wait()
 }
+
+type countingListener struct {
+	net.Listener
+	accepted int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		atomic.AddInt32(&l.accepted, 1)
+	}
+	return conn, err
+}
+
+func TestClientConnectorKeepalive(t *testing.T) {
+	// Simulates a low-traffic changefeed, the idle connection must not be
+	// closed by the keepalive enforcement of the server.
+	idle := 60 * time.Second
+	if testing.Short() {
+		idle = 15 * time.Second
+	}
+	// 10s is the minimum keepalive interval of the gRPC clients.
+	const interval, timeout = 10 * time.Second, 5 * time.Second
+
+	wrapper := NewServerWrapper(&MessageServerConfig{
+		MaxRecvMsgSize:               4 * 1024 * 1024,
+		KeepAliveTime:                interval,
+		KeepAliveTimeout:             timeout,
+		KeepAlivePermitWithoutStream: true,
+	})
+	mockService := &mockService{}
+	grpcServer := grpc.NewServer(wrapper.ServerOptions()...)
+	p2p.RegisterCDCPeerToPeerServer(grpcServer, mockService)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	counting := &countingListener{Listener: lis}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = grpcServer.Serve(counting)
+	}()
+	defer func() {
+		grpcServer.Stop()
+		wg.Wait()
+	}()
+
+	client, release, err := newClientConnector().Connect(clientConnectOptions{
+		network:        "tcp",
+		addr:           lis.Addr().String(),
+		credential:     &security.Credential{},
+		timeout:        time.Second,
+		maxRecvMsgSize: 4 * 1024 * 1024,
+		keepalive: keepalive.ClientParameters{
+			Time:                interval,
+			Timeout:             timeout,
+			PermitWithoutStream: true,
+		},
+	})
+	require.NoError(t, err)
+	defer release()
+
+	sendMessage := func(expected int32) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		stream, err := client.SendMessage(ctx)
+		require.NoError(t, err)
+		_, _ = stream.Recv()
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&mockService.callCount) == expected
+		}, time.Second*5, time.Millisecond*100)
+	}
+
+	sendMessage(1)
+	time.Sleep(idle)
+	sendMessage(2)
+	// The connection is reused after being idle.
+	require.EqualValues(t, 1, atomic.LoadInt32(&counting.accepted))
+}
@@ -31,6 +31,7 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc/keepalive"
 	gRPCPeer "google.golang.org/grpc/peer"
 )
 
@@ -118,6 +119,11 @@ func (c *grpcMessageClient) Run(
 			credential:     credential,
 			timeout:        c.config.DialTimeout,
 			maxRecvMsgSize: c.config.MaxRecvMsgSize,
+			keepalive: keepalive.ClientParameters{
+				Time:                c.config.KeepAliveTime,
+				Timeout:             c.config.KeepAliveTimeout,
+				PermitWithoutStream: c.config.KeepAlivePermitWithoutStream,
+			},
 		})
 		if err != nil {
 			log.Warn("peer-message client: failed to connect to server",
@@ -69,6 +69,10 @@ type MessageServerConfig struct {
 	// closed.
 	KeepAliveTimeout time.Duration
 
+	// KeepAlivePermitWithoutStream allows the clients to send keepalive pings
+	// even if there are no active streams.
+	KeepAlivePermitWithoutStream bool
+
 	// The maximum time duration to wait before forcefully removing a handler.
 	//
 	// waitUnregisterHandleTimeout specifies how long to wait for
@@ -701,7 +705,7 @@ func (m *MessageServer) SendMessage(stream p2p.CDCPeerToPeer_SendMessageServer)
 	if err := m.verifyStreamMeta(packet.Meta); err != nil {
 		msg := errorToRPCResponse(err)
 		_ = stream.Send(&msg)
-		return errors.Trace(err)
+		return cerror.ToGRPCError(err)
 	}
 
 	metricsServerStreamCount := serverStreamCount.With(prometheus.Labels{
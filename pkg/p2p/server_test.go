@@ -26,9 +26,12 @@ import (
 	"time"
 
 	"github.com/phayes/freeport"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/proto/p2p"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -859,6 +862,11 @@ func TestReceiverIDMismatch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, p2p.ExitReason_CAPTURE_ID_MISMATCH, resp.ExitReason)
 
+	// The stream is closed with a structured gRPC error.
+	_, err = stream.Recv()
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.True(t, cerror.ErrPeerMessageReceiverMismatch.Equal(cerror.FromGRPCError(err)))
+
 	cancel()
 	wg.Wait()
 }
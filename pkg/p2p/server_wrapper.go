@@ -74,9 +74,17 @@ func (s *ServerWrapper) ServerOptions() []grpc.ServerOption {
 		Time:    s.cfg.KeepAliveTime,
 		Timeout: s.cfg.KeepAliveTimeout,
 	}
+	// The server closes the connections of the clients pinging more often than
+	// MinTime, so it's set to half of our interval to tolerate the clients
+	// configured with a shorter one.
+	enforcementPolicy := keepalive.EnforcementPolicy{
+		MinTime:             s.cfg.KeepAliveTime / 2,
+		PermitWithoutStream: s.cfg.KeepAlivePermitWithoutStream,
+	}
 	return []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(s.cfg.MaxRecvMsgSize),
 		grpc.KeepaliveParams(keepaliveParams),
+		grpc.KeepaliveEnforcementPolicy(enforcementPolicy),
 	}
 }
 
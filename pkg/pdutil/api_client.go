@@ -43,6 +43,7 @@ const (
 	gcServiceSafePointURL = "/pd/api/v1/gc/safepoint"
 	healthyAPI            = "/pd/api/v1/health"
 	scanRegionAPI         = "/pd/api/v1/regions/key"
+	placementRulesAPI     = "/pd/api/v1/config/rules"
 
 	// Split the default rule by following keys to keep metadata region isolated
 	// from the normal data area.
@@ -101,6 +102,7 @@ type PDAPIClient interface {
 	CollectMemberEndpoints(ctx context.Context) ([]string, error)
 	Healthy(ctx context.Context, endpoint string) error
 	ScanRegions(ctx context.Context, span tablepb.Span) ([]RegionInfo, error)
+	ListPlacementRules(ctx context.Context) ([]*PlacementRule, error)
 	Close()
 }
 
@@ -108,6 +110,9 @@ type PDAPIClient interface {
 type pdAPIClient struct {
 	grpcClient pd.Client
 	httpClient *httputil.Client
+	// endpointPool selects the endpoints to scan regions from, it's nil if
+	// the client is not created with a pool.
+	endpointPool *PDEndpointPool
 }
 
 // NewPDAPIClient create a new pdAPIClient.
@@ -182,7 +187,7 @@ type RegionsInfo struct {
 // ScanRegions is a reentrant function that updates the meta-region label of upstream cluster.
 func (pc *pdAPIClient) ScanRegions(ctx context.Context, span tablepb.Span) ([]RegionInfo, error) {
 	scanLimit := 1024
-	endpoints, err := pc.CollectMemberEndpoints(ctx)
+	endpoints, err := pc.scanEndpoints(ctx)
 	if err != nil {
 		log.Warn("fail to collec pd member endpoints")
 		return nil, errors.Trace(err)
@@ -190,6 +195,26 @@ func (pc *pdAPIClient) ScanRegions(ctx context.Context, span tablepb.Span) ([]Re
 	return pc.scanRegions(ctx, span, endpoints, scanLimit)
 }
 
+// scanEndpoints returns the endpoints to scan regions from. If the client has
+// an endpoint pool, the healthy endpoints are returned, starting from the next
+// one in the round-robin order. Otherwise, all the members are returned.
+func (pc *pdAPIClient) scanEndpoints(ctx context.Context) ([]string, error) {
+	if pc.endpointPool != nil {
+		next, err := pc.endpointPool.Next()
+		if err == nil {
+			endpoints := []string{next}
+			for _, endpoint := range pc.endpointPool.HealthyEndpoints() {
+				if endpoint != next {
+					endpoints = append(endpoints, endpoint)
+				}
+			}
+			return endpoints, nil
+		}
+		log.Warn("no healthy pd endpoint, fallback to all members", zap.Error(err))
+	}
+	return pc.CollectMemberEndpoints(ctx)
+}
+
 func (pc *pdAPIClient) scanRegions(
 	ctx context.Context, span tablepb.Span, endpoints []string, scanLimit int,
 ) ([]RegionInfo, error) {
@@ -230,8 +255,12 @@ func (pc *pdAPIClient) scanRegions(
 	for spanz.EndCompare(startKey, span.EndKey) < 0 || (len(startKey) == 0 && isFirstStartKey) {
 		for i, endpoint := range endpoints {
 			r, err := scan(endpoint, startKey, span.EndKey)
-			if err != nil && i+1 == len(endpoints) {
-				return nil, errors.Trace(err)
+			if err != nil {
+				if i+1 == len(endpoints) {
+					return nil, errors.Trace(err)
+				}
+				// Try the next endpoint.
+				continue
 			}
 
 			if len(r) == 0 {
@@ -313,6 +342,67 @@ func (pc *pdAPIClient) ListGcServiceSafePoint(
 	return resp, err
 }
 
+// LabelConstraint is used to filter stores when trying to place peer of a region.
+// NOTE: This type is a copy of github.com/tikv/pd/pkg/schedule/placement.LabelConstraint.
+// To reduce dependency tree, we do not import the placement package directly.
+type LabelConstraint struct {
+	Key    string   `json:"key"`
+	Op     string   `json:"op"`
+	Values []string `json:"values"`
+}
+
+// PlacementRule is the placement rule that can be checked against a region.
+// NOTE: This type is a copy of github.com/tikv/pd/pkg/schedule/placement.Rule.
+// To reduce dependency tree, we do not import the placement package directly.
+type PlacementRule struct {
+	GroupID          string            `json:"group_id"`
+	ID               string            `json:"id"`
+	Index            int               `json:"index,omitempty"`
+	StartKeyHex      string            `json:"start_key"`
+	EndKeyHex        string            `json:"end_key"`
+	Role             string            `json:"role"`
+	Count            int               `json:"count"`
+	LabelConstraints []LabelConstraint `json:"label_constraints,omitempty"`
+}
+
+// ListPlacementRules lists all placement rules from PD.
+func (pc *pdAPIClient) ListPlacementRules(ctx context.Context) ([]*PlacementRule, error) {
+	var (
+		resp []*PlacementRule
+		err  error
+	)
+	err = retry.Do(ctx, func() error {
+		ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		defer cancel()
+
+		resp, err = pc.listPlacementRules(ctx)
+		return err
+	}, retry.WithMaxTries(defaultMaxRetry), retry.WithIsRetryableErr(func(err error) bool {
+		switch errors.Cause(err) {
+		case context.Canceled:
+			return false
+		}
+		return true
+	}))
+	return resp, err
+}
+
+func (pc *pdAPIClient) listPlacementRules(ctx context.Context) ([]*PlacementRule, error) {
+	url := pc.grpcClient.GetLeaderAddr() + placementRulesAPI
+
+	respData, err := pc.httpClient.DoRequest(ctx, url, http.MethodGet,
+		nil, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var rules []*PlacementRule
+	err = json.Unmarshal(respData, &rules)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rules, nil
+}
+
 func (pc *pdAPIClient) patchMetaLabel(ctx context.Context) error {
 	url := pc.grpcClient.GetLeaderAddr() + regionLabelPrefix
 	header := http.Header{"Content-Type": {"application/json"}}
@@ -116,6 +116,46 @@ func TestListGcServiceSafePoint(t *testing.T) {
 	mockClient.testServer.Close()
 }
 
+func TestListPlacementRules(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &mockPDClient{}
+	mockClient.testServer = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, placementRulesAPI, r.URL.Path)
+			_, _ = w.Write([]byte(`[{
+				"group_id": "pd", "id": "default", "start_key": "", "end_key": "",
+				"role": "voter", "count": 3
+			}, {
+				"group_id": "TiDB_DDL_100", "id": "table_rule_100_0", "index": 40,
+				"start_key": "7480000000000000ff6400000000000000f8",
+				"end_key": "7480000000000000ff6500000000000000f8",
+				"role": "leader", "count": 1,
+				"label_constraints": [{"key": "region", "op": "in", "values": ["us-east-1"]}]
+			}]`))
+		},
+	))
+	defer mockClient.testServer.Close()
+	mockClient.url = mockClient.testServer.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pc, err := NewPDAPIClient(mockClient, nil)
+	require.NoError(t, err)
+	defer pc.Close()
+	rules, err := pc.ListPlacementRules(ctx)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "voter", rules[0].Role)
+	require.Empty(t, rules[0].LabelConstraints)
+	require.Equal(t, "leader", rules[1].Role)
+	require.Equal(t, 40, rules[1].Index)
+	require.Equal(t, "7480000000000000ff6400000000000000f8", rules[1].StartKeyHex)
+	require.Equal(t, []LabelConstraint{{Key: "region", Op: "in", Values: []string{"us-east-1"}}},
+		rules[1].LabelConstraints)
+}
+
 // LabelRulePatch is the patch to update the label rules.
 // NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
 // Copied from github.com/tikv/pd/server/schedule/labeler
@@ -0,0 +1,165 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdutil
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/prometheus/client_golang/prometheus"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+)
+
+// defaultHealthCheckInterval is the interval to check the health of the PD
+// endpoints.
+const defaultHealthCheckInterval = 30 * time.Second
+
+// PDEndpointPool round-robins across the healthy PD endpoints. The health of
+// the endpoints is checked in the background, the unhealthy endpoints are
+// removed from the pool and restored once they recover.
+type PDEndpointPool struct {
+	upstreamID string
+	endpoints  []string
+	client     PDAPIClient
+	interval   time.Duration
+
+	healthyCountGauge prometheus.Gauge
+
+	mu      sync.Mutex
+	healthy []bool
+	next    int
+}
+
+// NewPDEndpointPool creates a PDEndpointPool of the upstream, all the endpoints
+// are regarded as healthy before they are checked.
+func NewPDEndpointPool(
+	upstreamID uint64, endpoints []string, client PDAPIClient,
+) *PDEndpointPool {
+	healthy := make([]bool, len(endpoints))
+	for i := range healthy {
+		healthy[i] = true
+	}
+	id := strconv.FormatUint(upstreamID, 10)
+	gauge := pdEndpointHealthyCountGauge.WithLabelValues(id)
+	gauge.Set(float64(len(endpoints)))
+	return &PDEndpointPool{
+		upstreamID:        id,
+		endpoints:         endpoints,
+		client:            client,
+		interval:          defaultHealthCheckInterval,
+		healthyCountGauge: gauge,
+		healthy:           healthy,
+	}
+}
+
+// NewPDAPIClientWithEndpointPool creates a PDAPIClient which scans regions
+// from the healthy endpoints of a PDEndpointPool. The pool is returned to be
+// run by the caller.
+func NewPDAPIClientWithEndpointPool(
+	pdClient pd.Client, conf *security.Credential,
+	upstreamID uint64, endpoints []string,
+) (PDAPIClient, *PDEndpointPool, error) {
+	dialClient, err := httputil.NewClient(conf)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	client := &pdAPIClient{
+		grpcClient: pdClient,
+		httpClient: dialClient,
+	}
+	client.endpointPool = NewPDEndpointPool(upstreamID, endpoints, client)
+	return client, client.endpointPool, nil
+}
+
+// Next returns the next healthy endpoint in the round-robin order.
+func (p *PDEndpointPool) Next() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for range p.endpoints {
+		i := p.next
+		p.next = (p.next + 1) % len(p.endpoints)
+		if p.healthy[i] {
+			return p.endpoints[i], nil
+		}
+	}
+	return "", cerror.ErrNoHealthyPDEndpoint.GenWithStackByArgs(p.endpoints)
+}
+
+// HealthyEndpoints returns all the healthy endpoints.
+func (p *PDEndpointPool) HealthyEndpoints() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := make([]string, 0, len(p.endpoints))
+	for i, endpoint := range p.endpoints {
+		if p.healthy[i] {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
+
+// Run checks the health of the endpoints periodically until ctx is canceled.
+func (p *PDEndpointPool) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.checkHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *PDEndpointPool) checkHealth(ctx context.Context) {
+	healthy := make([]bool, len(p.endpoints))
+	for i, endpoint := range p.endpoints {
+		checkCtx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		err := p.client.Healthy(checkCtx, endpoint)
+		cancel()
+		healthy[i] = err == nil
+		if err != nil {
+			log.Warn("PD endpoint is unhealthy",
+				zap.String("endpoint", endpoint), zap.Error(err))
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	count := 0
+	for i, endpoint := range p.endpoints {
+		if healthy[i] {
+			count++
+			if !p.healthy[i] {
+				log.Info("PD endpoint recovers", zap.String("endpoint", endpoint))
+			}
+		}
+	}
+	p.healthy = healthy
+	p.healthyCountGauge.Set(float64(count))
+}
+
+// Close removes the metrics of the pool, it must be called after Run returns.
+func (p *PDEndpointPool) Close() {
+	pdEndpointHealthyCountGauge.DeleteLabelValues(p.upstreamID)
+}
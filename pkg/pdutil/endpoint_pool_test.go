@@ -0,0 +1,190 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdutil
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHealthyClient struct {
+	PDAPIClient
+
+	mu        sync.Mutex
+	unhealthy map[string]bool
+}
+
+func (c *mockHealthyClient) Healthy(_ context.Context, endpoint string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unhealthy[endpoint] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (c *mockHealthyClient) setUnhealthy(endpoint string, unhealthy bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unhealthy[endpoint] = unhealthy
+}
+
+func TestPDEndpointPool(t *testing.T) {
+	client := &mockHealthyClient{unhealthy: make(map[string]bool)}
+	pool := NewPDEndpointPool(1, []string{"pd1", "pd2", "pd3"}, client)
+	ctx := context.Background()
+
+	next := func() string {
+		endpoint, err := pool.Next()
+		require.NoError(t, err)
+		return endpoint
+	}
+	require.Equal(t, "pd1", next())
+	require.Equal(t, "pd2", next())
+
+	// The unhealthy endpoint is skipped.
+	client.setUnhealthy("pd2", true)
+	pool.checkHealth(ctx)
+	require.Equal(t, []string{"pd1", "pd3"}, pool.HealthyEndpoints())
+	require.Equal(t, float64(2), testutil.ToFloat64(pdEndpointHealthyCountGauge.WithLabelValues("1")))
+	for _, expected := range []string{"pd3", "pd1", "pd3", "pd1"} {
+		require.Equal(t, expected, next())
+	}
+
+	// The endpoint is restored once it recovers.
+	client.setUnhealthy("pd2", false)
+	pool.checkHealth(ctx)
+	require.Equal(t, []string{"pd1", "pd2", "pd3"}, pool.HealthyEndpoints())
+	require.Equal(t, float64(3), testutil.ToFloat64(pdEndpointHealthyCountGauge.WithLabelValues("1")))
+	require.Equal(t, "pd2", next())
+
+	for _, endpoint := range []string{"pd1", "pd2", "pd3"} {
+		client.setUnhealthy(endpoint, true)
+	}
+	pool.checkHealth(ctx)
+	_, err := pool.Next()
+	require.True(t, cerror.ErrNoHealthyPDEndpoint.Equal(err))
+
+	// The metric of the upstream is removed after the pool is closed.
+	pool.Close()
+	require.False(t, pdEndpointHealthyCountGauge.DeleteLabelValues("1"))
+}
+
+func TestPDEndpointPoolRun(t *testing.T) {
+	client := &mockHealthyClient{unhealthy: map[string]bool{"pd1": true}}
+	pool := NewPDEndpointPool(2, []string{"pd1", "pd2"}, client)
+	pool.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.Equal(t, context.Canceled, errors.Cause(pool.Run(ctx)))
+	}()
+
+	// The requests keep being served by the healthy endpoint.
+	require.Eventually(t, func() bool {
+		endpoint, err := pool.Next()
+		return err == nil && endpoint == "pd2" && len(pool.HealthyEndpoints()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	client.setUnhealthy("pd1", false)
+	require.Eventually(t, func() bool {
+		return len(pool.HealthyEndpoints()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
+
+func TestScanRegionsWithEndpointPool(t *testing.T) {
+	regions := []RegionInfo{NewTestRegionInfo(2, []byte(""), []byte(""), 0)}
+	var requests []string
+	var mu sync.Mutex
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == scanRegionAPI {
+					mu.Lock()
+					requests = append(requests, name)
+					mu.Unlock()
+				}
+				data, _ := json.Marshal(RegionsInfo{Count: 1, Regions: regions})
+				_, _ = w.Write(data)
+			},
+		))
+	}
+	pd1, pd2 := newServer("pd1"), newServer("pd2")
+	defer pd1.Close()
+	defer pd2.Close()
+
+	pc, pool, err := NewPDAPIClientWithEndpointPool(
+		&mockPDClient{}, nil, 3, []string{pd1.URL, pd2.URL})
+	require.NoError(t, err)
+	defer pc.Close()
+	ctx := context.Background()
+
+	// The requests are round-robined across the endpoints.
+	for i := 0; i < 2; i++ {
+		rs, err := pc.ScanRegions(ctx, tablepb.Span{})
+		require.NoError(t, err)
+		require.Len(t, rs, 1)
+	}
+	require.Equal(t, []string{"pd1", "pd2"}, requests)
+
+	// Disable pd1, the requests are served by pd2 only.
+	pd1.Close()
+	requests = nil
+	pool.checkHealth(ctx)
+	require.Equal(t, []string{pd2.URL}, pool.HealthyEndpoints())
+	for i := 0; i < 2; i++ {
+		rs, err := pc.ScanRegions(ctx, tablepb.Span{})
+		require.NoError(t, err)
+		require.Len(t, rs, 1)
+	}
+	require.Equal(t, []string{"pd2", "pd2"}, requests)
+}
+
+func TestScanRegionsFailover(t *testing.T) {
+	regions := []RegionInfo{NewTestRegionInfo(2, []byte(""), []byte(""), 0)}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			data, _ := json.Marshal(RegionsInfo{Count: 1, Regions: regions})
+			_, _ = w.Write(data)
+		},
+	))
+	defer server.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	httpcli, _ := httputil.NewClient(nil)
+	pc := pdAPIClient{httpClient: httpcli}
+	// The endpoint which is down before being removed from the pool is skipped.
+	rs, err := pc.scanRegions(context.Background(), tablepb.Span{},
+		[]string{down.URL, server.URL}, 1024)
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+}
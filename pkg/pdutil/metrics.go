@@ -0,0 +1,33 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdutil
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pdEndpointHealthyCountGauge records the number of healthy PD endpoints of
+// each upstream.
+var pdEndpointHealthyCountGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "pd",
+		Name:      "endpoint_healthy_count",
+		Help:      "The number of healthy PD endpoints",
+	}, []string{"upstream"})
+
+// InitMetrics registers all metrics used in pdutil
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(pdEndpointHealthyCountGauge)
+}
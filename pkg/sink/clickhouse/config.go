@@ -0,0 +1,168 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	// Register the "clickhouse" driver for database/sql, it talks to
+	// ClickHouse with the native protocol.
+	_ "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxTxnRow is the default max number of rows in a batch.
+	// ClickHouse prefers large and infrequent inserts.
+	DefaultMaxTxnRow = 8192
+	// defaultMaxBatchWait is the default max duration to wait for more
+	// transactions before flushing a partial batch.
+	defaultMaxBatchWait = 100 * time.Millisecond
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max txn rows.
+	maxMaxTxnRow = 65536
+	// The upper limit of max batch wait.
+	maxMaxBatchWait = 10 * time.Second
+)
+
+// tiCDCParams are the parameters of the sink URI consumed by TiCDC, they
+// must not be passed to the ClickHouse driver.
+var tiCDCParams = []string{"worker-count", "max-txn-row", "max-batch-wait-ms"}
+
+type urlConfig struct {
+	WorkerCount    *int `form:"worker-count"`
+	MaxTxnRow      *int `form:"max-txn-row"`
+	MaxBatchWaitMs *int `form:"max-batch-wait-ms"`
+}
+
+// Config is the configs for ClickHouse backend.
+type Config struct {
+	WorkerCount  int
+	MaxTxnRow    int
+	MaxBatchWait time.Duration
+}
+
+// NewConfig returns the default ClickHouse backend config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount:  sink.DefaultWorkerCount,
+		MaxTxnRow:    DefaultMaxTxnRow,
+		MaxBatchWait: defaultMaxBatchWait,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL) error {
+	if sinkURI == nil {
+		return cerror.ErrClickHouseInvalidConfig.GenWithStack("fail to open ClickHouse sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsClickHouseScheme(scheme) {
+		return cerror.ErrClickHouseInvalidConfig.GenWithStack(
+			"can't create ClickHouse sink with unsupported scheme: %s", scheme)
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrClickHouseInvalidConfig, err)
+	}
+
+	if urlParameter.WorkerCount != nil {
+		workerCount := *urlParameter.WorkerCount
+		if workerCount <= 0 {
+			return cerror.WrapError(cerror.ErrClickHouseInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", workerCount))
+		}
+		if workerCount > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", workerCount), zap.Int("override", maxWorkerCount))
+			workerCount = maxWorkerCount
+		}
+		c.WorkerCount = workerCount
+	}
+	if urlParameter.MaxTxnRow != nil {
+		maxTxnRow := *urlParameter.MaxTxnRow
+		if maxTxnRow <= 0 {
+			return cerror.WrapError(cerror.ErrClickHouseInvalidConfig,
+				fmt.Errorf("invalid max-txn-row %d, which must be greater than 0", maxTxnRow))
+		}
+		if maxTxnRow > maxMaxTxnRow {
+			log.Warn("max-txn-row too large",
+				zap.Int("original", maxTxnRow), zap.Int("override", maxMaxTxnRow))
+			maxTxnRow = maxMaxTxnRow
+		}
+		c.MaxTxnRow = maxTxnRow
+	}
+	if urlParameter.MaxBatchWaitMs != nil {
+		wait := time.Duration(*urlParameter.MaxBatchWaitMs) * time.Millisecond
+		if wait <= 0 {
+			return cerror.WrapError(cerror.ErrClickHouseInvalidConfig,
+				fmt.Errorf("invalid max-batch-wait-ms %d, which must be greater than 0",
+					*urlParameter.MaxBatchWaitMs))
+		}
+		if wait > maxMaxBatchWait {
+			log.Warn("max-batch-wait-ms too large",
+				zap.Duration("original", wait), zap.Duration("override", maxMaxBatchWait))
+			wait = maxMaxBatchWait
+		}
+		c.MaxBatchWait = wait
+	}
+	return nil
+}
+
+// GenerateDSN generates the DSN of clickhouse-go from the sink URI, the
+// parameters consumed by TiCDC are removed.
+func GenerateDSN(sinkURI *url.URL) string {
+	dsn := *sinkURI
+	dsn.Scheme = sink.ClickHouseScheme
+	query := dsn.Query()
+	for _, param := range tiCDCParams {
+		query.Del(param)
+	}
+	dsn.RawQuery = query.Encode()
+	return dsn.String()
+}
+
+// Factory is the factory for creating db connection.
+type Factory func(ctx context.Context, dsnStr string) (*sql.DB, error)
+
+// CreateClickHouseDBConn creates a ClickHouse database connection with the given dsn.
+func CreateClickHouseDBConn(ctx context.Context, dsnStr string) (*sql.DB, error) {
+	db, err := sql.Open("clickhouse", dsnStr)
+	if err != nil {
+		return nil, cerror.ErrClickHouseConnectionError.Wrap(err).GenWithStack("fail to open ClickHouse connection")
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		// close db to recycle resources
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn("close db failed", zap.Error(err))
+		}
+		return nil, cerror.ErrClickHouseConnectionError.Wrap(err).GenWithStack("fail to open ClickHouse connection")
+	}
+
+	return db, nil
+}
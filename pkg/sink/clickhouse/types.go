@@ -0,0 +1,191 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// CommitTsColumn is the version column of the ReplacingMergeTree tables,
+	// the row with the largest commit ts of a key is kept after merging.
+	CommitTsColumn = "_tidb_commit_ts"
+	// IsDeletedColumn marks the rows deleted in the upstream, they are
+	// removed when the parts are merged.
+	IsDeletedColumn = "_tidb_is_deleted"
+
+	maxDecimal128Precision = 38
+)
+
+// ColumnType returns the ClickHouse column type of a TiDB column type.
+func ColumnType(ft *types.FieldType) string {
+	tp := baseColumnType(ft)
+	if !mysql.HasNotNullFlag(ft.GetFlag()) {
+		return "Nullable(" + tp + ")"
+	}
+	return tp
+}
+
+func baseColumnType(ft *types.FieldType) string {
+	unsigned := mysql.HasUnsignedFlag(ft.GetFlag())
+	prefix := ""
+	if unsigned {
+		prefix = "U"
+	}
+	switch ft.GetType() {
+	case mysql.TypeTiny:
+		return prefix + "Int8"
+	case mysql.TypeShort:
+		return prefix + "Int16"
+	case mysql.TypeInt24, mysql.TypeLong:
+		return prefix + "Int32"
+	case mysql.TypeLonglong:
+		return prefix + "Int64"
+	case mysql.TypeYear:
+		return "UInt16"
+	case mysql.TypeFloat:
+		return "Float32"
+	case mysql.TypeDouble:
+		return "Float64"
+	case mysql.TypeNewDecimal:
+		if ft.GetFlen() > maxDecimal128Precision {
+			return fmt.Sprintf("Decimal256(%d)", ft.GetDecimal())
+		}
+		return fmt.Sprintf("Decimal128(%d)", ft.GetDecimal())
+	case mysql.TypeDate:
+		return "Date32"
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		fsp := ft.GetDecimal()
+		if fsp < 0 {
+			fsp = 0
+		}
+		// The values are written in UTC without conversion, so that they are
+		// displayed as they are in TiDB.
+		return fmt.Sprintf("DateTime64(%d, 'UTC')", fsp)
+	case mysql.TypeBit:
+		return "UInt64"
+	default:
+		// TIME, JSON, ENUM, SET and all the string types are stored as String.
+		return "String"
+	}
+}
+
+// ConvertValue converts a column value of a RowChangedEvent to the Go type
+// expected by clickhouse-go for the column type returned by ColumnType.
+func ConvertValue(ft *types.FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		return convertInteger(ft, value)
+	case mysql.TypeYear:
+		if v, ok := value.(int64); ok {
+			return uint16(v), nil
+		}
+	case mysql.TypeFloat:
+		switch v := value.(type) {
+		case float32:
+			return v, nil
+		case float64:
+			return float32(v), nil
+		}
+	case mysql.TypeNewDecimal:
+		if v, ok := value.(string); ok {
+			d, err := decimal.NewFromString(v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return d, nil
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		if v, ok := value.(string); ok {
+			return convertTime(ft, v)
+		}
+	case mysql.TypeEnum:
+		if v, ok := value.(uint64); ok {
+			enum, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return enum.Name, nil
+		}
+	case mysql.TypeSet:
+		if v, ok := value.(uint64); ok {
+			set, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return set.Name, nil
+		}
+	}
+	if v, ok := value.([]byte); ok {
+		return string(v), nil
+	}
+	return value, nil
+}
+
+func convertInteger(ft *types.FieldType, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64:
+		switch ft.GetType() {
+		case mysql.TypeTiny:
+			return int8(v), nil
+		case mysql.TypeShort:
+			return int16(v), nil
+		case mysql.TypeInt24, mysql.TypeLong:
+			return int32(v), nil
+		default:
+			return v, nil
+		}
+	case uint64:
+		switch ft.GetType() {
+		case mysql.TypeTiny:
+			return uint8(v), nil
+		case mysql.TypeShort:
+			return uint16(v), nil
+		case mysql.TypeInt24, mysql.TypeLong:
+			return uint32(v), nil
+		default:
+			return v, nil
+		}
+	}
+	return nil, errors.Errorf("unexpected value %v of type %T for integer column", value, value)
+}
+
+func convertTime(ft *types.FieldType, value string) (interface{}, error) {
+	// ClickHouse rejects the zero dates of MySQL.
+	if strings.HasPrefix(value, "0000-00-00") {
+		if !mysql.HasNotNullFlag(ft.GetFlag()) {
+			return nil, nil
+		}
+		return time.Unix(0, 0).UTC(), nil
+	}
+	layout := "2006-01-02 15:04:05.999999"
+	if ft.GetType() == mysql.TypeDate {
+		layout = "2006-01-02"
+	}
+	t, err := time.ParseInLocation(layout, value, time.UTC)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return t, nil
+}
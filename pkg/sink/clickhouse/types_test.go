@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func newFieldType(tp byte, flag uint, flen, decimal int) *types.FieldType {
+	ft := types.NewFieldType(tp)
+	ft.SetFlag(flag)
+	ft.SetFlen(flen)
+	ft.SetDecimal(decimal)
+	return ft
+}
+
+func TestColumnType(t *testing.T) {
+	t.Parallel()
+
+	notNull := mysql.NotNullFlag
+	cases := []struct {
+		ft       *types.FieldType
+		expected string
+	}{
+		{newFieldType(mysql.TypeTiny, notNull, 4, 0), "Int8"},
+		{newFieldType(mysql.TypeShort, notNull|mysql.UnsignedFlag, 6, 0), "UInt16"},
+		{newFieldType(mysql.TypeLong, 0, 11, 0), "Nullable(Int32)"},
+		{newFieldType(mysql.TypeLonglong, notNull|mysql.UnsignedFlag, 20, 0), "UInt64"},
+		{newFieldType(mysql.TypeDouble, notNull, 22, -1), "Float64"},
+		{newFieldType(mysql.TypeNewDecimal, notNull, 10, 2), "Decimal128(2)"},
+		{newFieldType(mysql.TypeNewDecimal, notNull, 65, 30), "Decimal256(30)"},
+		{newFieldType(mysql.TypeDate, notNull, 10, 0), "Date32"},
+		{newFieldType(mysql.TypeDatetime, 0, 23, 3), "Nullable(DateTime64(3, 'UTC'))"},
+		{newFieldType(mysql.TypeVarchar, notNull, 32, 0), "String"},
+		{newFieldType(mysql.TypeJSON, 0, 0, 0), "Nullable(String)"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, ColumnType(c.ft), c.ft.String())
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	t.Parallel()
+
+	value, err := ConvertValue(newFieldType(mysql.TypeLong, 0, 11, 0), int64(1))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeTiny, mysql.UnsignedFlag, 3, 0), uint64(255))
+	require.NoError(t, err)
+	require.Equal(t, uint8(255), value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeNewDecimal, 0, 10, 2), "123.45")
+	require.NoError(t, err)
+	require.True(t, decimal.RequireFromString("123.45").Equal(value.(decimal.Decimal)))
+
+	value, err = ConvertValue(newFieldType(mysql.TypeDatetime, 0, 26, 6), "2024-01-02 03:04:05.123456")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC), value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeDate, 0, 10, 0), "0000-00-00")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	enum := newFieldType(mysql.TypeEnum, 0, 1, 0)
+	enum.SetElems([]string{"a", "b"})
+	value, err = ConvertValue(enum, uint64(1))
+	require.NoError(t, err)
+	require.Equal(t, "a", value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeBlob, 0, 65535, 0), []byte{0x1, 0x2})
+	require.NoError(t, err)
+	require.Equal(t, "\x01\x02", value)
+}
@@ -18,7 +18,10 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"math"
 	"math/big"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -35,6 +38,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/spatial"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -91,7 +95,7 @@ func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *model.Row
 		return nil, errors.Trace(err)
 	}
 
-	native, err := a.columns2AvroData(keyColumns)
+	native, err := a.columns2AvroData(&e.TableInfo.TableName, keyColumns)
 	if err != nil {
 		log.Error("avro: key converting to native failed", zap.Error(err))
 		return nil, errors.Trace(err)
@@ -114,7 +118,13 @@ func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *model.Row
 	return data, nil
 }
 
-func topicName2SchemaSubjects(topicName, subjectSuffix string) string {
+// topicName2SchemaSubjects returns the schema registry subject of the topic,
+// it's prefixed with the schema namespace if it's not empty, so changefeeds
+// with different namespaces don't share subjects.
+func topicName2SchemaSubjects(schemaNamespace, topicName, subjectSuffix string) string {
+	if schemaNamespace != "" {
+		return schemaNamespace + "." + topicName + subjectSuffix
+	}
 	return topicName + subjectSuffix
 }
 
@@ -130,7 +140,7 @@ func (a *BatchEncoder) getValueSchemaCodec(
 		return schema, nil
 	}
 
-	subject := topicName2SchemaSubjects(topic, valueSchemaSuffix)
+	subject := topicName2SchemaSubjects(a.config.AvroSchemaNamespace, topic, valueSchemaSuffix)
 	avroCodec, header, err := a.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -150,7 +160,7 @@ func (a *BatchEncoder) getKeySchemaCodec(
 		return schema, nil
 	}
 
-	subject := topicName2SchemaSubjects(topic, keySchemaSuffix)
+	subject := topicName2SchemaSubjects(a.config.AvroSchemaNamespace, topic, keySchemaSuffix)
 	avroCodec, header, err := a.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -176,7 +186,7 @@ func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *model.R
 		return nil, errors.Trace(err)
 	}
 
-	native, err := a.columns2AvroData(input)
+	native, err := a.columns2AvroData(&e.TableInfo.TableName, input)
 	if err != nil {
 		log.Error("avro: converting value to native failed", zap.Error(err))
 		return nil, errors.Trace(err)
@@ -383,6 +393,7 @@ var type2TiDBType = map[byte]string{
 	mysql.TypeTimestamp:  "TIMESTAMP",
 	mysql.TypeDuration:   "TIME",
 	mysql.TypeYear:       "YEAR",
+	mysql.TypeGeometry:   "GEOMETRY",
 }
 
 func getTiDBTypeFromColumn(col *model.Column) string {
@@ -439,6 +450,8 @@ func mysqlTypeFromTiDBType(tidbType string) byte {
 		result = mysql.TypeDuration
 	case "YEAR":
 		result = mysql.TypeYear
+	case "GEOMETRY":
+		result = mysql.TypeGeometry
 	default:
 		log.Panic("this should not happen, unknown TiDB type", zap.String("type", tidbType))
 	}
@@ -498,16 +511,53 @@ func escapeEnumAndSetOptions(option string) string {
 	return option
 }
 
+const avroEnumType = "enum"
+
+// avroEnumSymbolRegexp matches valid avro enum symbols, which share the rule of names.
+var avroEnumSymbolRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// encodeEnumAsAvroEnum returns whether the enum column should be encoded as an avro enum.
+// Elements which are not valid avro enum symbols can't be encoded as an avro enum,
+// so fall back to the string in this case.
+func (a *BatchEncoder) encodeEnumAsAvroEnum(ft *types.FieldType) bool {
+	if a.config.AvroEnumSetHandlingMode != common.EnumSetHandlingModeNative {
+		return false
+	}
+	for _, e := range ft.GetElems() {
+		if !avroEnumSymbolRegexp.MatchString(e) {
+			return false
+		}
+	}
+	return true
+}
+
 func getAvroNamespace(namespace string, schema string) string {
 	return sanitizeName(namespace) + "." + sanitizeName(schema)
 }
 
 type avroSchema struct {
 	Type string `json:"type"`
+	Doc  string `json:"doc,omitempty"`
 	// connect.parameters is designated field extracted by schema registry
 	Parameters map[string]string `json:"connect.parameters"`
 }
 
+// avroEnumSchema is the schema of enum columns if the enum-set handling mode is native.
+type avroEnumSchema struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Symbols    []string          `json:"symbols"`
+	Parameters map[string]string `json:"connect.parameters"`
+}
+
+// avroArraySchema is the schema of set columns if the enum-set handling mode is native.
+type avroArraySchema struct {
+	Type       string            `json:"type"`
+	Items      string            `json:"items"`
+	Parameters map[string]string `json:"connect.parameters"`
+}
+
 type avroLogicalTypeSchema struct {
 	avroSchema
 	LogicalType string      `json:"logicalType"`
@@ -576,6 +626,12 @@ func (a *BatchEncoder) columns2AvroSchema(
 		if err != nil {
 			return nil, err
 		}
+		// enum is a named type, put it into the namespace of the record
+		// to avoid conflicting with other named types.
+		if enumType, ok := avroType.(avroEnumSchema); ok {
+			enumType.Namespace = top.Namespace + "." + top.Name
+			avroType = enumType
+		}
 		field := make(map[string]interface{})
 		field["name"] = sanitizeName(col.Name)
 
@@ -662,6 +718,7 @@ func (a *BatchEncoder) key2AvroSchema(
 }
 
 func (a *BatchEncoder) columns2AvroData(
+	tableName *model.TableName,
 	input *avroEncodeInput,
 ) (map[string]interface{}, error) {
 	ret := make(map[string]interface{}, len(input.columns))
@@ -676,6 +733,11 @@ func (a *BatchEncoder) columns2AvroData(
 
 		// https: //pkg.go.dev/github.com/linkedin/goavro/v2#Union
 		if col.Flag.IsNullable() {
+			// the union branch of a named type is its full name.
+			if str == avroEnumType {
+				str = getAvroNamespace(a.namespace, tableName.Schema) + "." +
+					sanitizeName(tableName.Table) + "." + sanitizeName(col.Name)
+			}
 			ret[sanitizeName(col.Name)] = goavro.Union(str, data)
 		} else {
 			ret[sanitizeName(col.Name)] = data
@@ -694,10 +756,23 @@ func (a *BatchEncoder) columnToAvroSchema(
 	switch col.Type {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24:
 		// BOOL/TINYINT/SMALLINT/MEDIUMINT
-		return avroSchema{
+		schema := avroSchema{
 			Type:       "int",
 			Parameters: map[string]string{tidbType: tt},
-		}, nil
+		}
+		// The original type is lost since all of them are INT in tidb_type,
+		// so document the range of unsigned values.
+		if col.Flag.IsUnsigned() {
+			upperBound := uint64(math.MaxUint8)
+			switch col.Type {
+			case mysql.TypeShort:
+				upperBound = math.MaxUint16
+			case mysql.TypeInt24:
+				upperBound = mysql.MaxUint24
+			}
+			schema.Doc = fmt.Sprintf("unsigned value in range [0, %d]", upperBound)
+		}
+		return schema, nil
 	case mysql.TypeLong: // INT
 		if col.Flag.IsUnsigned() {
 			return avroSchema{
@@ -711,9 +786,13 @@ func (a *BatchEncoder) columnToAvroSchema(
 		}, nil
 	case mysql.TypeLonglong: // BIGINT
 		t := "long"
-		if col.Flag.IsUnsigned() &&
-			a.config.AvroBigintUnsignedHandlingMode == common.BigintUnsignedHandlingModeString {
-			t = "string"
+		if col.Flag.IsUnsigned() {
+			switch a.config.AvroBigintUnsignedHandlingMode {
+			case common.BigintUnsignedHandlingModeString:
+				t = "string"
+			case common.BigintUnsignedHandlingModeBytes:
+				t = "bytes"
+			}
 		}
 		return avroSchema{
 			Type:       t,
@@ -790,12 +869,28 @@ func (a *BatchEncoder) columnToAvroSchema(
 			e = escapeEnumAndSetOptions(e)
 			es = append(es, e)
 		}
+		parameters := map[string]string{
+			tidbType:  tt,
+			"allowed": strings.Join(es, ","),
+		}
+		if col.Type == mysql.TypeEnum && a.encodeEnumAsAvroEnum(ft) {
+			return avroEnumSchema{
+				Type:       avroEnumType,
+				Name:       sanitizeName(col.Name),
+				Symbols:    ft.GetElems(),
+				Parameters: parameters,
+			}, nil
+		}
+		if col.Type == mysql.TypeSet && a.config.AvroEnumSetHandlingMode == common.EnumSetHandlingModeNative {
+			return avroArraySchema{
+				Type:       "array",
+				Items:      "string",
+				Parameters: parameters,
+			}, nil
+		}
 		return avroSchema{
-			Type: "string",
-			Parameters: map[string]string{
-				tidbType:  tt,
-				"allowed": strings.Join(es, ","),
-			},
+			Type:       "string",
+			Parameters: parameters,
 		}, nil
 	case mysql.TypeJSON:
 		return avroSchema{
@@ -812,6 +907,15 @@ func (a *BatchEncoder) columnToAvroSchema(
 			Type:       "int",
 			Parameters: map[string]string{tidbType: tt},
 		}, nil
+	case mysql.TypeGeometry:
+		t := "bytes"
+		if a.config.SpatialEncoding == common.SpatialEncodingWKT {
+			t = "string"
+		}
+		return avroSchema{
+			Type:       t,
+			Parameters: map[string]string{tidbType: tt},
+		}, nil
 	default:
 		log.Error("unknown mysql type", zap.Any("mysqlType", col.Type))
 		return nil, cerror.ErrAvroEncodeFailed.GenWithStack("unknown mysql type")
@@ -864,6 +968,9 @@ func (a *BatchEncoder) columnToAvroData(
 				if err != nil {
 					return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
 				}
+				if a.config.AvroBigintUnsignedHandlingMode == common.BigintUnsignedHandlingModeBytes {
+					return encodeUnsignedBigint(n), "bytes", nil
+				}
 				return int64(n), "long", nil
 			}
 			n, err := strconv.ParseInt(v, 10, 64)
@@ -873,8 +980,11 @@ func (a *BatchEncoder) columnToAvroData(
 			return n, "long", nil
 		}
 		if col.Flag.IsUnsigned() {
-			if a.config.AvroBigintUnsignedHandlingMode == common.BigintUnsignedHandlingModeLong {
+			switch a.config.AvroBigintUnsignedHandlingMode {
+			case common.BigintUnsignedHandlingModeLong:
 				return int64(col.Value.(uint64)), "long", nil
+			case common.BigintUnsignedHandlingModeBytes:
+				return encodeUnsignedBigint(col.Value.(uint64)), "bytes", nil
 			}
 			// bigintUnsignedHandlingMode == "string"
 			return strconv.FormatUint(col.Value.(uint64), 10), "string", nil
@@ -933,8 +1043,12 @@ func (a *BatchEncoder) columnToAvroData(
 		}
 		return string(col.Value.([]byte)), "string", nil
 	case mysql.TypeEnum:
+		t := "string"
+		if a.encodeEnumAsAvroEnum(ft) {
+			t = avroEnumType
+		}
 		if v, ok := col.Value.(string); ok {
-			return v, "string", nil
+			return v, t, nil
 		}
 		elements := ft.GetElems()
 		number := col.Value.(uint64)
@@ -943,20 +1057,30 @@ func (a *BatchEncoder) columnToAvroData(
 			log.Info("avro encoder parse enum value failed", zap.Strings("elements", elements), zap.Uint64("number", number))
 			return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
 		}
-		return enumVar.Name, "string", nil
+		return enumVar.Name, t, nil
 	case mysql.TypeSet:
-		if v, ok := col.Value.(string); ok {
-			return v, "string", nil
+		name, ok := col.Value.(string)
+		if !ok {
+			elements := ft.GetElems()
+			number := col.Value.(uint64)
+			setVar, err := types.ParseSetValue(elements, number)
+			if err != nil {
+				log.Info("avro encoder parse set value failed",
+					zap.Strings("elements", elements), zap.Uint64("number", number), zap.Error(err))
+				return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
+			}
+			name = setVar.Name
 		}
-		elements := ft.GetElems()
-		number := col.Value.(uint64)
-		setVar, err := types.ParseSetValue(elements, number)
-		if err != nil {
-			log.Info("avro encoder parse set value failed",
-				zap.Strings("elements", elements), zap.Uint64("number", number), zap.Error(err))
-			return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
+		if a.config.AvroEnumSetHandlingMode == common.EnumSetHandlingModeNative {
+			items := make([]interface{}, 0)
+			if name != "" {
+				for _, item := range strings.Split(name, ",") {
+					items = append(items, item)
+				}
+			}
+			return items, "array", nil
 		}
-		return setVar.Name, "string", nil
+		return name, "string", nil
 	case mysql.TypeJSON:
 		return col.Value.(string), "string", nil
 	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
@@ -971,12 +1095,57 @@ func (a *BatchEncoder) columnToAvroData(
 			return int32(n), "int", nil
 		}
 		return int32(col.Value.(int64)), "int", nil
+	case mysql.TypeGeometry:
+		var value []byte
+		switch v := col.Value.(type) {
+		case []byte:
+			value = v
+		case string:
+			value = []byte(v)
+		}
+		if a.config.SpatialEncoding == common.SpatialEncodingWKT {
+			wkt, err := spatial.ToWKT(value)
+			if err != nil {
+				return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
+			}
+			return wkt, "string", nil
+		}
+		wkb, err := spatial.ToWKB(value)
+		if err != nil {
+			return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
+		}
+		return wkb, "bytes", nil
 	default:
 		log.Error("unknown mysql type", zap.Any("value", col.Value), zap.Any("mysqlType", col.Type))
 		return nil, "", cerror.ErrAvroEncodeFailed.GenWithStack("unknown mysql type")
 	}
 }
 
+// unsignedBigintBytesLen is the length of the bytes encoded from unsigned bigint,
+// avro long is a signed 64-bit integer, so the value is encoded as a 16-byte
+// big-endian integer.
+const unsignedBigintBytesLen = 16
+
+func encodeUnsignedBigint(v uint64) []byte {
+	b := make([]byte, unsignedBigintBytesLen)
+	binary.BigEndian.PutUint64(b[unsignedBigintBytesLen-8:], v)
+	return b
+}
+
+func decodeUnsignedBigint(b []byte) (uint64, error) {
+	if len(b) != unsignedBigintBytesLen {
+		return 0, cerror.ErrDecodeFailed.GenWithStackByArgs(
+			fmt.Sprintf("invalid unsigned bigint length %d", len(b)))
+	}
+	for _, v := range b[:unsignedBigintBytesLen-8] {
+		if v != 0 {
+			return 0, cerror.ErrDecodeFailed.GenWithStackByArgs(
+				"unsigned bigint overflows uint64")
+		}
+	}
+	return binary.BigEndian.Uint64(b[unsignedBigintBytesLen-8:]), nil
+}
+
 const (
 	// avro does not send ddl and checkpoint message, the following 2 field is used to distinguish
 	// TiCDC DDL event and checkpoint event, only used for testing purpose, not for production
@@ -1017,10 +1186,21 @@ func NewBatchEncoderBuilder(
 	schemaRegistryType := config.SchemaRegistryType()
 	switch schemaRegistryType {
 	case common.SchemaRegistryTypeConfluent:
-		schemaM, err = NewConfluentSchemaManager(ctx, config.AvroConfluentSchemaRegistry, nil)
+		m, err := newConfluentSchemaManager(ctx, config.AvroConfluentSchemaRegistry,
+			config.SchemaRegistryCredential(), config.SchemaRegistryAuthorization())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m.compatibility = config.AvroSchemaCompatibility
+		schemaM = m
+	case common.SchemaRegistryTypeJSONSchema:
+		m, err := NewJSONSchemaRegistry(ctx, config.AvroConfluentSchemaRegistry,
+			config.SchemaRegistryCredential(), config.SchemaRegistryAuthorization())
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		m.compatibility = config.AvroSchemaCompatibility
+		schemaM = m
 	case common.SchemaRegistryTypeGlue:
 		schemaM, err = NewGlueSchemaManager(ctx, config.AvroGlueSchemaRegistry)
 		if err != nil {
@@ -19,7 +19,9 @@ import (
 	"encoding/json"
 	"math/big"
 	"math/rand"
+	"net/http"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -112,7 +114,11 @@ var avroTestColumns = []*avroTestColumnTuple{
 			VirtualGenCol: false,
 			Ft:            utils.SetUnsigned(types.NewFieldType(mysql.TypeTiny)),
 		},
-		avroSchema{Type: "int", Parameters: map[string]string{"tidb_type": "INT UNSIGNED"}},
+		avroSchema{
+			Type:       "int",
+			Doc:        "unsigned value in range [0, 255]",
+			Parameters: map[string]string{"tidb_type": "INT UNSIGNED"},
+		},
 		int32(1), "int",
 	},
 	{
@@ -128,7 +134,11 @@ var avroTestColumns = []*avroTestColumnTuple{
 			VirtualGenCol: false,
 			Ft:            utils.SetUnsigned(types.NewFieldType(mysql.TypeShort)),
 		},
-		avroSchema{Type: "int", Parameters: map[string]string{"tidb_type": "INT UNSIGNED"}},
+		avroSchema{
+			Type:       "int",
+			Doc:        "unsigned value in range [0, 65535]",
+			Parameters: map[string]string{"tidb_type": "INT UNSIGNED"},
+		},
 		int32(1), "int",
 	},
 	{
@@ -144,7 +154,11 @@ var avroTestColumns = []*avroTestColumnTuple{
 			VirtualGenCol: false,
 			Ft:            utils.SetUnsigned(types.NewFieldType(mysql.TypeInt24)),
 		},
-		avroSchema{Type: "int", Parameters: map[string]string{"tidb_type": "INT UNSIGNED"}},
+		avroSchema{
+			Type:       "int",
+			Doc:        "unsigned value in range [0, 16777215]",
+			Parameters: map[string]string{"tidb_type": "INT UNSIGNED"},
+		},
 		int32(1), "int",
 	},
 	{
@@ -592,6 +606,63 @@ func TestColumnToAvroData(t *testing.T) {
 	}
 }
 
+func TestEnumAndSetToAvroNative(t *testing.T) {
+	t.Parallel()
+
+	encoder := NewAvroEncoder("namespace", nil, &common.Config{
+		AvroDecimalHandlingMode:        "precise",
+		AvroBigintUnsignedHandlingMode: "long",
+		AvroEnumSetHandlingMode:        common.EnumSetHandlingModeNative,
+	}).(*BatchEncoder)
+
+	enumCol := &model.Column{Name: "enum", Value: uint64(2), Type: mysql.TypeEnum}
+	enumFt := utils.SetElems(types.NewFieldType(mysql.TypeEnum), []string{"a", "b"})
+	schema, err := encoder.columnToAvroSchema(enumCol, enumFt)
+	require.NoError(t, err)
+	require.Equal(t, avroEnumSchema{
+		Type:       "enum",
+		Name:       "enum",
+		Symbols:    []string{"a", "b"},
+		Parameters: map[string]string{"tidb_type": "ENUM", "allowed": "a,b"},
+	}, schema)
+	data, str, err := encoder.columnToAvroData(enumCol, enumFt)
+	require.NoError(t, err)
+	require.Equal(t, "b", data)
+	require.Equal(t, "enum", str)
+
+	// elements which are not valid avro enum symbols fall back to string.
+	invalidFt := utils.SetElems(types.NewFieldType(mysql.TypeEnum), []string{"a b", "1"})
+	schema, err = encoder.columnToAvroSchema(enumCol, invalidFt)
+	require.NoError(t, err)
+	require.Equal(t, avroSchema{
+		Type:       "string",
+		Parameters: map[string]string{"tidb_type": "ENUM", "allowed": "a b,1"},
+	}, schema)
+	data, str, err = encoder.columnToAvroData(enumCol, invalidFt)
+	require.NoError(t, err)
+	require.Equal(t, "1", data)
+	require.Equal(t, "string", str)
+
+	setCol := &model.Column{Name: "set", Value: uint64(3), Type: mysql.TypeSet}
+	setFt := utils.SetElems(types.NewFieldType(mysql.TypeSet), []string{"a", "b"})
+	schema, err = encoder.columnToAvroSchema(setCol, setFt)
+	require.NoError(t, err)
+	require.Equal(t, avroArraySchema{
+		Type:       "array",
+		Items:      "string",
+		Parameters: map[string]string{"tidb_type": "SET", "allowed": "a,b"},
+	}, schema)
+	data, str, err = encoder.columnToAvroData(setCol, setFt)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"a", "b"}, data)
+	require.Equal(t, "array", str)
+
+	setCol.Value = uint64(0)
+	data, _, err = encoder.columnToAvroData(setCol, setFt)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{}, data)
+}
+
 func indentJSON(j string) string {
 	var buf bytes.Buffer
 	_ = json.Indent(&buf, []byte(j), "", "  ")
@@ -732,7 +803,7 @@ func TestRowToAvroData(t *testing.T) {
 	codecConfig := common.NewConfig(config.ProtocolAvro)
 	encoder := NewAvroEncoder(model.DefaultNamespace, nil, codecConfig)
 
-	data, err := encoder.(*BatchEncoder).columns2AvroData(input)
+	data, err := encoder.(*BatchEncoder).columns2AvroData(&model.TableName{Schema: "test", Table: "t"}, input)
 	require.NoError(t, err)
 
 	for _, col := range input.columns {
@@ -935,6 +1006,41 @@ func TestGetAvroNamespace(t *testing.T) {
 	)
 }
 
+func TestAvroSchemaNamespace(t *testing.T) {
+	startHTTPInterceptForTestingRegistry()
+	defer stopHTTPInterceptForTestingRegistry()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two changefeeds replicate the same table to the same topic,
+	// and share the schema registry.
+	topic := "default"
+	ids := make(map[int]struct{})
+	for _, namespace := range []string{"cf1", "cf.2"} {
+		codecConfig := common.NewConfig(config.ProtocolAvro)
+		codecConfig.EnableTiDBExtension = true
+		codecConfig.AvroSchemaNamespace = namespace
+		schemaM, err := NewConfluentSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+		require.NoError(t, err)
+		encoder := NewAvroEncoder(model.DefaultNamespace, schemaM, codecConfig).(*BatchEncoder)
+
+		bin, err := encoder.encodeValue(ctx, topic, newLargeEvent())
+		require.NoError(t, err)
+		cid, _, err := extractConfluentSchemaIDAndBinaryData(bin)
+		require.NoError(t, err)
+		ids[cid] = struct{}{}
+
+		resp, err := http.Get("http://127.0.0.1:8081/schemas/ids/" + strconv.Itoa(cid))
+		require.NoError(t, err)
+		var lookup lookupResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&lookup))
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, namespace+"."+topic+valueSchemaSuffix, lookup.Name)
+	}
+	require.Len(t, ids, 2)
+}
+
 func TestArvoAppendRowChangedEventWithCallback(t *testing.T) {
 	codecConfig := common.NewConfig(config.ProtocolAvro)
 	codecConfig.EnableTiDBExtension = true
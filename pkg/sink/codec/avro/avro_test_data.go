@@ -141,6 +141,7 @@ var expectedSchemaWithoutExtension = `{
       "name": "tinyunsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 255]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -153,6 +154,7 @@ var expectedSchemaWithoutExtension = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 255]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -163,6 +165,7 @@ var expectedSchemaWithoutExtension = `{
       "name": "shortunsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 65535]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -175,6 +178,7 @@ var expectedSchemaWithoutExtension = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 65535]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -185,6 +189,7 @@ var expectedSchemaWithoutExtension = `{
       "name": "int24unsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 16777215]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -197,6 +202,7 @@ var expectedSchemaWithoutExtension = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 16777215]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -962,6 +968,7 @@ var expectedSchemaWithExtension = `{
       "name": "tinyunsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 255]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -974,6 +981,7 @@ var expectedSchemaWithExtension = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 255]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -984,6 +992,7 @@ var expectedSchemaWithExtension = `{
       "name": "shortunsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 65535]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -996,6 +1005,7 @@ var expectedSchemaWithExtension = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 65535]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -1006,6 +1016,7 @@ var expectedSchemaWithExtension = `{
       "name": "int24unsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 16777215]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -1018,6 +1029,7 @@ var expectedSchemaWithExtension = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 16777215]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -1724,6 +1736,7 @@ var expectedSchemaWithExtensionEnableChecksum = `{
       "name": "tinyunsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 255]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -1733,6 +1746,7 @@ var expectedSchemaWithExtensionEnableChecksum = `{
       "name": "shortunsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 65535]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -1742,6 +1756,7 @@ var expectedSchemaWithExtensionEnableChecksum = `{
       "name": "int24unsigned",
       "type": {
         "type": "int",
+        "doc": "unsigned value in range [0, 16777215]",
         "connect.parameters": {
           "tidb_type": "INT UNSIGNED"
         }
@@ -2074,6 +2089,7 @@ var expectedSchemaWithExtensionEnableChecksum = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 255]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -2087,6 +2103,7 @@ var expectedSchemaWithExtensionEnableChecksum = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 65535]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -2100,6 +2117,7 @@ var expectedSchemaWithExtensionEnableChecksum = `{
         "null",
         {
           "type": "int",
+          "doc": "unsigned value in range [0, 16777215]",
           "connect.parameters": {
             "tidb_type": "INT UNSIGNED"
           }
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
 
@@ -47,7 +48,14 @@ const magicByte = uint8(0)
 type confluentSchemaManager struct {
 	registryURL string
 
-	credential *security.Credential // placeholder, currently always nil
+	// credential is nil unless TLS is configured for the schema registry.
+	credential *security.Credential
+	// authorization is the value of the Authorization header of requests,
+	// it's empty unless authentication is configured.
+	authorization string
+	// compatibility is the compatibility level set to the subjects before
+	// registering schemas, the global default of the registry is used if empty.
+	compatibility string
 
 	cacheRWLock  sync.RWMutex
 	cache        map[string]*schemaCacheEntry
@@ -56,14 +64,18 @@ type confluentSchemaManager struct {
 
 type registerRequest struct {
 	Schema string `json:"schema"`
-	// Commented out for compatibility with Confluent 5.4.x
-	// SchemaType string `json:"schemaType"`
+	// SchemaType is omitted for Avro schemas for compatibility with Confluent 5.4.x
+	SchemaType string `json:"schemaType,omitempty"`
 }
 
 type registerResponse struct {
 	SchemaID int `json:"id"`
 }
 
+type compatibilityRequest struct {
+	Compatibility string `json:"compatibility"`
+}
+
 type lookupResponse struct {
 	Name     string `json:"name"`
 	SchemaID int    `json:"id"`
@@ -77,12 +89,34 @@ func NewConfluentSchemaManager(
 	registryURL string,
 	credential *security.Credential,
 ) (SchemaManager, error) {
+	m, err := newConfluentSchemaManager(ctx, registryURL, credential, "")
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// newConfluentSchemaManager is like NewConfluentSchemaManager, and all requests
+// carry the given Authorization header if it's not empty.
+func newConfluentSchemaManager(
+	ctx context.Context,
+	registryURL string,
+	credential *security.Credential,
+	authorization string,
+) (*confluentSchemaManager, error) {
 	registryURL = strings.TrimRight(registryURL, "/")
 	httpCli, err := httputil.NewClient(credential)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	resp, err := httpCli.Get(ctx, registryURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL, nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := httpCli.Do(req)
 	if err != nil {
 		log.Error("Test connection to Schema Registry failed", zap.Error(err))
 		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
@@ -95,6 +129,13 @@ func NewConfluentSchemaManager(
 		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		log.Error("Authentication to Schema Registry failed", zap.Int("status", resp.StatusCode))
+		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Authentication to Schema Registry failed, HTTP status %d", resp.StatusCode,
+		)
+	}
+
 	if string(text[:]) != "{}" {
 		log.Error("Unexpected response from Schema Registry", zap.ByteString("response", text))
 		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
@@ -108,9 +149,11 @@ func NewConfluentSchemaManager(
 	)
 
 	return &confluentSchemaManager{
-		registryURL:  registryURL,
-		cache:        make(map[string]*schemaCacheEntry, 1),
-		registryType: common.SchemaRegistryTypeConfluent,
+		registryURL:   registryURL,
+		credential:    credential,
+		authorization: authorization,
+		cache:         make(map[string]*schemaCacheEntry, 1),
+		registryType:  common.SchemaRegistryTypeConfluent,
 	}, nil
 }
 
@@ -124,6 +167,10 @@ func (m *confluentSchemaManager) Register(
 	id := schemaID{}
 	log.Info("confluentSchemaManager", zap.String("schemaDefinition", schemaDefinition), zap.String("schemaName", schemaName))
 
+	if err := m.setCompatibility(ctx, schemaName); err != nil {
+		return id, errors.Trace(err)
+	}
+
 	buffer := new(bytes.Buffer)
 	err := json.Compact(buffer, []byte(schemaDefinition))
 	if err != nil {
@@ -139,7 +186,7 @@ func (m *confluentSchemaManager) Register(
 		return id, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 	uri := m.registryURL + "/subjects/" + url.QueryEscape(schemaName) + "/versions"
-	log.Info("Registering schema", zap.String("uri", uri), zap.ByteString("payload", payload))
+	log.Info("Registering schema", zap.String("uri", util.MaskSensitiveDataInURI(uri)), zap.ByteString("payload", payload))
 
 	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(payload))
 	if err != nil {
@@ -152,7 +199,7 @@ func (m *confluentSchemaManager) Register(
 			"application/json",
 	)
 	req.Header.Add("Content-Type", "application/vnd.schemaregistry.v1+json")
-	resp, err := httpRetry(ctx, m.credential, req)
+	resp, err := m.doRequest(ctx, req)
 	if err != nil {
 		return id, err
 	}
@@ -171,10 +218,14 @@ func (m *confluentSchemaManager) Register(
 		log.Error(
 			"Failed to register schema to the Registry, HTTP error",
 			zap.Int("status", resp.StatusCode),
-			zap.String("uri", uri),
+			zap.String("uri", util.MaskSensitiveDataInURI(uri)),
 			zap.ByteString("requestBody", payload),
 			zap.ByteString("responseBody", body),
 		)
+		if resp.StatusCode == http.StatusConflict {
+			return id, cerror.ErrAvroSchemaAPIError.GenWithStack(
+				"schema of subject %s is incompatible with the registered schemas", schemaName)
+		}
 		return id, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs()
 	}
 
@@ -195,7 +246,7 @@ func (m *confluentSchemaManager) Register(
 
 	log.Info("Registered schema successfully",
 		zap.Int("schemaID", jsonResp.SchemaID),
-		zap.String("uri", uri),
+		zap.String("uri", util.MaskSensitiveDataInURI(uri)),
 		zap.ByteString("body", body))
 
 	id.confluentSchemaID = jsonResp.SchemaID
@@ -229,7 +280,7 @@ func (m *confluentSchemaManager) Lookup(
 			"application/json",
 	)
 
-	resp, err := httpRetry(ctx, m.credential, req)
+	resp, err := m.doRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +295,7 @@ func (m *confluentSchemaManager) Lookup(
 	if resp.StatusCode != 200 && resp.StatusCode != 404 {
 		log.Error("Failed to query schema from the Registry, HTTP error",
 			zap.Int("status", resp.StatusCode),
-			zap.String("uri", uri),
+			zap.String("uri", util.MaskSensitiveDataInURI(uri)),
 			zap.ByteString("responseBody", body))
 		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
 			"Failed to query schema from the Registry, HTTP error",
@@ -364,7 +415,7 @@ func (m *confluentSchemaManager) ClearRegistry(ctx context.Context, schemaSubjec
 		"application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, "+
 			"application/json",
 	)
-	resp, err := httpRetry(ctx, m.credential, req)
+	resp, err := m.doRequest(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -410,6 +461,61 @@ func (m *confluentSchemaManager) getMsgHeader(schemaID int) ([]byte, error) {
 	return head.Bytes(), nil
 }
 
+// setCompatibility sets the compatibility level of the subject, so that
+// incompatible schemas are rejected by the registry when registering.
+func (m *confluentSchemaManager) setCompatibility(ctx context.Context, subject string) error {
+	if m.compatibility == "" {
+		return nil
+	}
+	payload, err := json.Marshal(&compatibilityRequest{Compatibility: m.compatibility})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	uri := m.registryURL + "/config/" + url.QueryEscape(subject)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, bytes.NewReader(payload))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add(
+		"Accept",
+		"application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, "+
+			"application/json",
+	)
+	req.Header.Add("Content-Type", "application/vnd.schemaregistry.v1+json")
+	resp, err := m.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if resp.StatusCode != 200 {
+		log.Error("Failed to set the compatibility level of the subject, HTTP error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("uri", util.MaskSensitiveDataInURI(uri)),
+			zap.String("compatibility", m.compatibility),
+			zap.ByteString("responseBody", body))
+		return cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Failed to set the compatibility level %s of subject %s, HTTP status %d",
+			m.compatibility, subject, resp.StatusCode,
+		)
+	}
+	return nil
+}
+
+// doRequest sends the request to the schema registry with retries.
+func (m *confluentSchemaManager) doRequest(
+	ctx context.Context, r *http.Request,
+) (*http.Response, error) {
+	if m.authorization != "" {
+		r.Header.Set("Authorization", m.authorization)
+	}
+	return httpRetry(ctx, m.credential, r)
+}
+
 func httpRetry(
 	ctx context.Context,
 	credential *security.Credential,
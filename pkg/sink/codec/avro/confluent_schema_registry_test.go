@@ -16,12 +16,22 @@ package avro
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -268,3 +278,205 @@ func TestHTTPRetry(t *testing.T) {
 	require.Equal(t, 200, resp.StatusCode)
 	_ = resp.Body.Close()
 }
+
+func newTLSTestingRegistryHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/subjects/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+	return mux
+}
+
+func TestSchemaRegistryTLS(t *testing.T) {
+	schema := `{"type":"record","name":"test","fields":[{"type":"string","name":"field1"}]}`
+	newManager := func(registryURL, ca, cert, key string) (*confluentSchemaManager, error) {
+		cfg := common.NewConfig(config.ProtocolAvro)
+		cfg.AvroConfluentSchemaRegistry = registryURL
+		cfg.SchemaRegistryCA = ca
+		cfg.SchemaRegistryCert = cert
+		cfg.SchemaRegistryKey = key
+		builder, err := NewBatchEncoderBuilder(getTestingContext(), cfg)
+		if err != nil {
+			return nil, err
+		}
+		return builder.(*batchEncoderBuilder).schemaM.(*confluentSchemaManager), nil
+	}
+
+	// one-way TLS, only the CA is specified.
+	server := httptest.NewTLSServer(newTLSTestingRegistryHandler())
+	defer server.Close()
+	caPath, err := security.WriteFile("ticdc-test-registry-ca", pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	require.NoError(t, err)
+	defer os.Remove(caPath)
+
+	_, err = newManager(server.URL, "", "", "")
+	require.Error(t, err)
+	manager, err := newManager(server.URL, caPath, "", "")
+	require.NoError(t, err)
+	id, err := manager.Register(getTestingContext(), "cdctest-value", schema)
+	require.NoError(t, err)
+	require.Equal(t, 1, id.confluentSchemaID)
+
+	// mutual TLS, the registry requires client certificates.
+	_, credential, err := security.NewServerCredential4Test("")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.Remove(credential.CAPath)
+		_ = os.Remove(credential.CertPath)
+		_ = os.Remove(credential.KeyPath)
+	}()
+	credential.MTLS = true
+	tlsConfig, err := credential.ToTLSConfig()
+	require.NoError(t, err)
+	// httptest serves its own certificate if none is set in Certificates.
+	cert, err := tls.LoadX509KeyPair(credential.CertPath, credential.KeyPath)
+	require.NoError(t, err)
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	mTLSServer := httptest.NewUnstartedServer(newTLSTestingRegistryHandler())
+	mTLSServer.TLS = tlsConfig
+	mTLSServer.StartTLS()
+	defer mTLSServer.Close()
+
+	_, err = newManager(mTLSServer.URL, credential.CAPath, "", "")
+	require.Error(t, err)
+	manager, err = newManager(mTLSServer.URL,
+		credential.CAPath, credential.CertPath, credential.KeyPath)
+	require.NoError(t, err)
+	id, err = manager.Register(getTestingContext(), "cdctest-value", schema)
+	require.NoError(t, err)
+	require.Equal(t, 1, id.confluentSchemaID)
+}
+
+func TestSchemaRegistryAuthorization(t *testing.T) {
+	newServer := func(authorization string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != authorization {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Method == http.MethodPost {
+				_, _ = w.Write([]byte(`{"id":1}`))
+				return
+			}
+			_, _ = w.Write([]byte("{}"))
+		}))
+	}
+
+	schema := `{"type":"record","name":"test","fields":[{"type":"string","name":"field1"}]}`
+	for _, tc := range []struct {
+		username string
+		password string
+		apiKey   string
+		header   string
+	}{
+		// base64 of "user:pass"
+		{username: "user", password: "pass", header: "Basic dXNlcjpwYXNz"},
+		{apiKey: "secret-key", header: "Bearer secret-key"},
+	} {
+		server := newServer(tc.header)
+		cfg := common.NewConfig(config.ProtocolAvro)
+		cfg.AvroConfluentSchemaRegistry = server.URL
+		builder, err := NewBatchEncoderBuilder(getTestingContext(), cfg)
+		require.ErrorContains(t, err, "Authentication to Schema Registry failed")
+		require.Nil(t, builder)
+
+		cfg.SchemaRegistryUsername = tc.username
+		cfg.SchemaRegistryPassword = tc.password
+		cfg.SchemaRegistryAPIKey = tc.apiKey
+		builder, err = NewBatchEncoderBuilder(getTestingContext(), cfg)
+		require.NoError(t, err)
+		manager := builder.(*batchEncoderBuilder).schemaM
+		id, err := manager.Register(getTestingContext(), "cdctest-value", schema)
+		require.NoError(t, err)
+		require.Equal(t, 1, id.confluentSchemaID)
+		server.Close()
+	}
+}
+
+func TestSchemaRegistryCompatibility(t *testing.T) {
+	type field struct {
+		Name    string      `json:"name"`
+		Default interface{} `json:"default"`
+	}
+	var (
+		mu            sync.Mutex
+		compatibility = make(map[string]string)
+		registered    = make(map[string][]field)
+	)
+	// The mock only checks the BACKWARD compatibility: fields added
+	// by the new schema must have default values.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/":
+			_, _ = w.Write([]byte("{}"))
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/config/"):
+			var req compatibilityRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			compatibility[strings.TrimPrefix(r.URL.Path, "/config/")] = req.Compatibility
+			_ = json.NewEncoder(w).Encode(&req)
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/subjects/"):
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			var req registerRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			var schema struct {
+				Fields []field `json:"fields"`
+			}
+			_ = json.Unmarshal([]byte(req.Schema), &schema)
+			if old, ok := registered[subject]; ok && compatibility[subject] == "BACKWARD" {
+				for _, f := range schema.Fields {
+					added := true
+					for _, o := range old {
+						added = added && o.Name != f.Name
+					}
+					if added && f.Default == nil {
+						w.WriteHeader(http.StatusConflict)
+						return
+					}
+				}
+			}
+			registered[subject] = schema.Fields
+			_, _ = w.Write([]byte(`{"id":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := common.NewConfig(config.ProtocolAvro)
+	cfg.AvroConfluentSchemaRegistry = server.URL
+	cfg.AvroSchemaCompatibility = common.AvroSchemaCompatibilityBackward
+	builder, err := NewBatchEncoderBuilder(getTestingContext(), cfg)
+	require.NoError(t, err)
+	manager := builder.(*batchEncoderBuilder).schemaM
+
+	_, err = manager.Register(getTestingContext(), "cdctest-value",
+		`{"type":"record","name":"test","fields":[{"type":"string","name":"field1"}]}`)
+	require.NoError(t, err)
+	mu.Lock()
+	require.Equal(t, "BACKWARD", compatibility["cdctest-value"])
+	mu.Unlock()
+
+	// adding a field without default value breaks the backward compatibility.
+	_, _, err = manager.GetCachedOrRegister(getTestingContext(), "cdctest-value", 2,
+		func() (string, error) {
+			return `{"type":"record","name":"test","fields":[` +
+				`{"type":"string","name":"field1"},{"type":"string","name":"field2"}]}`, nil
+		})
+	require.ErrorIs(t, err, cerror.ErrAvroSchemaAPIError)
+	require.Regexp(t, "incompatible", err)
+
+	// adding a field with default value is allowed.
+	_, _, err = manager.GetCachedOrRegister(getTestingContext(), "cdctest-value", 3,
+		func() (string, error) {
+			return `{"type":"record","name":"test","fields":[{"type":"string","name":"field1"},` +
+				`{"type":"string","name":"field2","default":""}]}`, nil
+		})
+	require.NoError(t, err)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/spatial"
 	"go.uber.org/zap"
 )
 
@@ -303,8 +304,30 @@ func getColumnValue(
 	}
 
 	switch mysqlType {
+	case mysql.TypeLonglong:
+		// unsigned bigint is encoded as bytes if the handling mode is bytes.
+		if b, ok := value.([]byte); ok {
+			v, err := decodeUnsignedBigint(b)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			value = v
+		}
+	case mysql.TypeGeometry:
+		// spatial type is encoded as WKB, or WKT if the spatial encoding is wkt,
+		// the SRID is not encoded, so it's always 0 after decoding.
+		var err error
+		switch v := value.(type) {
+		case []byte:
+			value, err = spatial.FromWKB(v, 0)
+		case string:
+			value, err = spatial.FromWKT(v, 0)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	case mysql.TypeEnum:
-		// enum type is encoded as string,
+		// enum type is encoded as string or avro enum, both are decoded as string,
 		// we need to convert it to int by the order of the enum values definition.
 		allowed := strings.Split(holder["allowed"].(string), ",")
 		enum, err := types.ParseEnum(allowed, value.(string), "")
@@ -313,8 +336,16 @@ func getColumnValue(
 		}
 		value = enum.Value
 	case mysql.TypeSet:
-		// set type is encoded as string,
+		// set type is encoded as string, or an array of strings if the
+		// enum-set handling mode is native,
 		// we need to convert it to int by the order of the set values definition.
+		if items, ok := value.([]interface{}); ok {
+			names := make([]string, 0, len(items))
+			for _, item := range items {
+				names = append(names, item.(string))
+			}
+			value = strings.Join(names, ",")
+		}
 		elems := strings.Split(holder["allowed"].(string), ",")
 		s, err := types.ParseSet(elems, value.(string), "")
 		if err != nil {
@@ -414,7 +445,7 @@ func decodeRawBytes(
 	var gid string
 
 	switch schemaM.RegistryType() {
-	case common.SchemaRegistryTypeConfluent:
+	case common.SchemaRegistryTypeConfluent, common.SchemaRegistryTypeJSONSchema:
 		cid, binary, err = extractConfluentSchemaIDAndBinaryData(data)
 		if err != nil {
 			return nil, nil, err
@@ -15,14 +15,18 @@ package avro
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"testing"
 	"time"
 
 	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/spatial"
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,3 +160,212 @@ func TestDecodeResolvedEvent(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, resolvedTs, obtained)
 }
+
+func TestDecodeUnsignedBoundaryValues(t *testing.T) {
+	codecConfig := common.NewConfig(config.ProtocolAvro)
+	codecConfig.EnableTiDBExtension = true
+	codecConfig.AvroBigintUnsignedHandlingMode = common.BigintUnsignedHandlingModeBytes
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, codecConfig)
+	defer TeardownEncoderAndSchemaRegistry4Testing()
+	require.NoError(t, err)
+
+	schemaM, err := NewConfluentSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+	require.NoError(t, err)
+	topic := "avro-unsigned-topic"
+	decoder := NewDecoder(codecConfig, schemaM, topic)
+
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "tiny", Type: mysql.TypeTiny, Flag: model.UnsignedFlag},
+		{Name: "int", Type: mysql.TypeLong, Flag: model.UnsignedFlag},
+		{Name: "bigint", Type: mysql.TypeLonglong, Flag: model.UnsignedFlag},
+	}
+	tableInfo := model.BuildTableInfo("test", "t", columns, [][]int{{0}})
+	for i, c := range []struct {
+		tiny, int, bigint uint64
+	}{
+		{tiny: 0, int: 0, bigint: 0},
+		{tiny: math.MaxInt8 + 1, int: math.MaxInt32 + 1, bigint: math.MaxInt64 + 1},
+		{tiny: math.MaxUint8, int: math.MaxUint32, bigint: math.MaxUint64},
+	} {
+		event := &model.RowChangedEvent{
+			CommitTs:  uint64(i + 1),
+			TableInfo: tableInfo,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "id", Value: int64(i)},
+				{Name: "tiny", Value: c.tiny},
+				{Name: "int", Value: c.int},
+				{Name: "bigint", Value: c.bigint},
+			}, tableInfo),
+		}
+		err = encoder.AppendRowChangedEvent(ctx, topic, event, func() {})
+		require.NoError(t, err)
+		messages := encoder.Build()
+		require.Len(t, messages, 1)
+
+		err = decoder.AddKeyValue(messages[0].Key, messages[0].Value)
+		require.NoError(t, err)
+		messageType, exist, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, exist)
+		require.Equal(t, model.MessageTypeRow, messageType)
+		decodedEvent, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+
+		decoded := make(map[string]interface{})
+		for _, col := range decodedEvent.GetColumns() {
+			decoded[col.Name] = col.Value
+		}
+		require.EqualValues(t, c.tiny, decoded["tiny"])
+		require.EqualValues(t, c.int, decoded["int"])
+		require.Equal(t, c.bigint, decoded["bigint"])
+	}
+}
+
+func TestDecodeUnsignedBigintBytes(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		b := encodeUnsignedBigint(v)
+		require.Len(t, b, 16)
+		decoded, err := decodeUnsignedBigint(b)
+		require.NoError(t, err)
+		require.Equal(t, v, decoded)
+	}
+
+	_, err := decodeUnsignedBigint([]byte{1, 2, 3})
+	require.Error(t, err)
+	b := encodeUnsignedBigint(1)
+	b[0] = 1
+	_, err = decodeUnsignedBigint(b)
+	require.Error(t, err)
+}
+
+func TestDecodeEnumAndSetNative(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	codecConfig := common.NewConfig(config.ProtocolAvro)
+	codecConfig.EnableTiDBExtension = true
+	codecConfig.AvroEnumSetHandlingMode = common.EnumSetHandlingModeNative
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, codecConfig)
+	defer TeardownEncoderAndSchemaRegistry4Testing()
+	require.NoError(t, err)
+
+	schemaM, err := NewConfluentSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+	require.NoError(t, err)
+	topic := "avro-enum-set-topic"
+	decoder := NewDecoder(codecConfig, schemaM, topic)
+
+	roundTrip := func(event *model.RowChangedEvent) {
+		err := encoder.AppendRowChangedEvent(ctx, topic, event, func() {})
+		require.NoError(t, err)
+		messages := encoder.Build()
+		require.Len(t, messages, 1)
+
+		err = decoder.AddKeyValue(messages[0].Key, messages[0].Value)
+		require.NoError(t, err)
+		messageType, exist, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, exist)
+		require.Equal(t, model.MessageTypeRow, messageType)
+		decodedEvent, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+
+		decoded := make(map[string]interface{})
+		for _, col := range decodedEvent.GetColumns() {
+			decoded[col.Name] = col.Value
+		}
+		for _, col := range event.GetColumns() {
+			require.EqualValues(t, col.Value, decoded[col.Name], col.Name)
+		}
+	}
+
+	_ = helper.DDL2Event(`create table test.t(
+		id int primary key, e enum('a', 'b') null, s set('x', 'y') not null default 'x')`)
+	roundTrip(helper.DML2Event(`insert into test.t values (1, 'b', 'x,y')`, "test", "t"))
+	roundTrip(helper.DML2Event(`insert into test.t values (2, null, '')`, "test", "t"))
+
+	// the new enum symbol and set element are encoded with the new schema.
+	_ = helper.DDL2Event(`alter table test.t modify column e enum('a', 'b', 'c') null`)
+	_ = helper.DDL2Event(`alter table test.t modify column s set('x', 'y', 'z') not null default 'x'`)
+	event := helper.DML2Event(`insert into test.t values (3, 'c', 'x,z')`, "test", "t")
+	schema, err := encoder.value2AvroSchema(&event.TableInfo.TableName, &avroEncodeInput{
+		columns:  event.GetColumns(),
+		colInfos: event.TableInfo.GetColInfosForRowChangedEvent(),
+	})
+	require.NoError(t, err)
+	require.Contains(t, schema, `"type":"enum","name":"e","namespace":"default.test.t","symbols":["a","b","c"]`)
+	require.Contains(t, schema, `"type":"array","items":"string"`)
+	roundTrip(event)
+	roundTrip(helper.DML2Event(`insert into test.t values (4, null, 'z')`, "test", "t"))
+}
+
+func TestDecodeSpatialValues(t *testing.T) {
+	for _, encoding := range []string{common.SpatialEncodingWKB, common.SpatialEncodingWKT} {
+		codecConfig := common.NewConfig(config.ProtocolAvro)
+		codecConfig.EnableTiDBExtension = true
+		codecConfig.SpatialEncoding = encoding
+		ctx, cancel := context.WithCancel(context.Background())
+
+		encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, codecConfig)
+		require.NoError(t, err)
+
+		schemaM, err := NewConfluentSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+		require.NoError(t, err)
+		topic := "avro-spatial-topic"
+		decoder := NewDecoder(codecConfig, schemaM, topic)
+
+		columns := []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "geo", Type: mysql.TypeGeometry, Flag: model.NullableFlag},
+		}
+		tableInfo := model.BuildTableInfo("test", "t", columns, [][]int{{0}})
+		for i, wkt := range []string{
+			"POINT(1 2)",
+			"LINESTRING(0 0,1 1,2.5 -3)",
+			"POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,4 2,4 4,2 2))",
+			"",
+		} {
+			var value interface{}
+			if wkt != "" {
+				value, err = spatial.FromWKT(wkt, 0)
+				require.NoError(t, err)
+			}
+			event := &model.RowChangedEvent{
+				CommitTs:  uint64(i + 1),
+				TableInfo: tableInfo,
+				Columns: model.Columns2ColumnDatas([]*model.Column{
+					{Name: "id", Value: int64(i)},
+					{Name: "geo", Value: value},
+				}, tableInfo),
+			}
+			err = encoder.AppendRowChangedEvent(ctx, topic, event, func() {})
+			require.NoError(t, err)
+			messages := encoder.Build()
+			require.Len(t, messages, 1)
+
+			err = decoder.AddKeyValue(messages[0].Key, messages[0].Value)
+			require.NoError(t, err)
+			_, exist, err := decoder.HasNext()
+			require.NoError(t, err)
+			require.True(t, exist)
+			decodedEvent, err := decoder.NextRowChangedEvent()
+			require.NoError(t, err)
+
+			decoded := make(map[string]interface{})
+			for _, col := range decodedEvent.GetColumns() {
+				decoded[col.Name] = col.Value
+			}
+			require.Equal(t, value, decoded["geo"])
+		}
+		TeardownEncoderAndSchemaRegistry4Testing()
+		cancel()
+	}
+}
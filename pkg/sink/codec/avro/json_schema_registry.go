@@ -0,0 +1,419 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	jsonSchemaType  = "JSON"
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+	// jsonSchemaAvroKeyword is the custom keyword which keeps the Avro schema
+	// of the message in the JSON Schema, so the Avro codec can be rebuilt
+	// from the schema registry when decoding.
+	jsonSchemaAvroKeyword = "x-avro-schema"
+)
+
+// JSONSchemaRegistry is used to register JSON Schemas to a schema registry
+// which supports JSON Schema, such as Karapace. The JSON Schema is generated
+// from the Avro schema of the table, and the messages are still encoded in
+// the confluent avro wire format.
+type JSONSchemaRegistry struct {
+	*confluentSchemaManager
+}
+
+type jsonSchemaLookupResponse struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+// NewJSONSchemaRegistry creates a JSON Schema registry client,
+// and test connectivity to the schema registry.
+func NewJSONSchemaRegistry(
+	ctx context.Context,
+	registryURL string,
+	credential *security.Credential,
+	authorization string,
+) (*JSONSchemaRegistry, error) {
+	m, err := newConfluentSchemaManager(ctx, registryURL, credential, authorization)
+	if err != nil {
+		return nil, err
+	}
+	m.registryType = common.SchemaRegistryTypeJSONSchema
+	return &JSONSchemaRegistry{confluentSchemaManager: m}, nil
+}
+
+// Register converts the Avro schema to a JSON Schema and registers it
+// in the schema registry, no cache.
+func (m *JSONSchemaRegistry) Register(
+	ctx context.Context,
+	schemaName string,
+	schemaDefinition string,
+) (schemaID, error) {
+	id := schemaID{}
+	if err := m.setCompatibility(ctx, schemaName); err != nil {
+		return id, errors.Trace(err)
+	}
+	jsonSchema, err := avroSchema2JSONSchema(schemaDefinition)
+	if err != nil {
+		return id, errors.Trace(err)
+	}
+	payload, err := json.Marshal(&registerRequest{
+		Schema:     jsonSchema,
+		SchemaType: jsonSchemaType,
+	})
+	if err != nil {
+		log.Error("Could not marshal request to the Registry", zap.Error(err))
+		return id, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	uri := m.registryURL + "/subjects/" + url.QueryEscape(schemaName) + "/versions"
+	log.Info("Registering JSON schema",
+		zap.String("uri", util.MaskSensitiveDataInURI(uri)), zap.ByteString("payload", payload))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(payload))
+	if err != nil {
+		return id, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add(
+		"Accept",
+		"application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, "+
+			"application/json",
+	)
+	req.Header.Add("Content-Type", "application/vnd.schemaregistry.v1+json")
+	resp, err := m.doRequest(ctx, req)
+	if err != nil {
+		return id, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return id, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if resp.StatusCode != 200 {
+		log.Error(
+			"Failed to register JSON schema to the Registry, HTTP error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("uri", util.MaskSensitiveDataInURI(uri)),
+			zap.ByteString("requestBody", payload),
+			zap.ByteString("responseBody", body),
+		)
+		return id, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs()
+	}
+
+	var jsonResp registerResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return id, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if jsonResp.SchemaID == 0 {
+		return id, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Illegal schema ID returned from Registry %d",
+			jsonResp.SchemaID,
+		)
+	}
+	log.Info("Registered JSON schema successfully",
+		zap.Int("schemaID", jsonResp.SchemaID),
+		zap.String("uri", util.MaskSensitiveDataInURI(uri)))
+
+	id.confluentSchemaID = jsonResp.SchemaID
+	return id, nil
+}
+
+// Lookup the cached schema entry first, if not found, fetch the JSON Schema
+// from the Registry server and rebuild the Avro codec from it.
+func (m *JSONSchemaRegistry) Lookup(
+	ctx context.Context,
+	schemaName string,
+	schemaID schemaID,
+) (*goavro.Codec, error) {
+	m.cacheRWLock.RLock()
+	entry, exists := m.cache[schemaName]
+	if exists && entry.schemaID.confluentSchemaID == schemaID.confluentSchemaID {
+		m.cacheRWLock.RUnlock()
+		return entry.codec, nil
+	}
+	m.cacheRWLock.RUnlock()
+
+	uri := m.registryURL + "/schemas/ids/" + strconv.Itoa(schemaID.confluentSchemaID)
+	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add(
+		"Accept",
+		"application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, "+
+			"application/json",
+	)
+	resp, err := m.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if resp.StatusCode == 404 {
+		log.Warn("Specified schema not found in Registry",
+			zap.String("key", schemaName),
+			zap.Int("schemaID", schemaID.confluentSchemaID))
+		return nil, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs(
+			"Schema not found in Registry",
+		)
+	}
+	if resp.StatusCode != 200 {
+		log.Error("Failed to query JSON schema from the Registry, HTTP error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("uri", util.MaskSensitiveDataInURI(uri)),
+			zap.ByteString("responseBody", body))
+		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Failed to query schema from the Registry, HTTP error",
+		)
+	}
+
+	var jsonResp jsonSchemaLookupResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if jsonResp.SchemaType != jsonSchemaType {
+		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Unexpected schema type %s of schema %d",
+			jsonResp.SchemaType, schemaID.confluentSchemaID,
+		)
+	}
+	avroSchema, err := avroSchemaFromJSONSchema(jsonResp.Schema)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cacheEntry := new(schemaCacheEntry)
+	cacheEntry.codec, err = goavro.NewCodec(avroSchema)
+	if err != nil {
+		log.Error("Creating Avro codec failed", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	cacheEntry.schemaID.confluentSchemaID = schemaID.confluentSchemaID
+	cacheEntry.header, err = m.getMsgHeader(schemaID.confluentSchemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.cacheRWLock.Lock()
+	m.cache[schemaName] = cacheEntry
+	m.cacheRWLock.Unlock()
+	return cacheEntry.codec, nil
+}
+
+// GetCachedOrRegister checks if the suitable schema has been cached.
+// If not, a new schema is generated, registered and cached.
+func (m *JSONSchemaRegistry) GetCachedOrRegister(
+	ctx context.Context,
+	schemaSubject string,
+	tableVersion uint64,
+	schemaGen SchemaGenerator,
+) (*goavro.Codec, []byte, error) {
+	m.cacheRWLock.RLock()
+	if entry, exists := m.cache[schemaSubject]; exists && entry.tableVersion == tableVersion {
+		m.cacheRWLock.RUnlock()
+		return entry.codec, entry.header, nil
+	}
+	m.cacheRWLock.RUnlock()
+
+	log.Info("JSON schema lookup cache miss",
+		zap.String("key", schemaSubject),
+		zap.Uint64("tableVersion", tableVersion))
+
+	schema, err := schemaGen()
+	if err != nil {
+		return nil, nil, err
+	}
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		log.Error("GetCachedOrRegister: Could not make goavro codec", zap.Error(err))
+		return nil, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	id, err := m.Register(ctx, schemaSubject, schema)
+	if err != nil {
+		log.Error("GetCachedOrRegister: Could not register schema", zap.Error(err))
+		return nil, nil, errors.Trace(err)
+	}
+	header, err := m.getMsgHeader(id.confluentSchemaID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.cacheRWLock.Lock()
+	m.cache[schemaSubject] = &schemaCacheEntry{
+		tableVersion: tableVersion,
+		schemaID:     id,
+		codec:        codec,
+		header:       header,
+	}
+	m.cacheRWLock.Unlock()
+	return codec, header, nil
+}
+
+// avroSchema2JSONSchema generates a JSON Schema from the Avro record schema
+// of a table, each column of the table becomes a property of the object.
+// The Avro schema is kept in the JSON Schema as well.
+func avroSchema2JSONSchema(avroSchema string) (string, error) {
+	var top avroSchemaTop
+	if err := json.Unmarshal([]byte(avroSchema), &top); err != nil {
+		return "", cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	// The Schema Registry expects the JSON to be without newline characters
+	compacted := new(bytes.Buffer)
+	if err := json.Compact(compacted, []byte(avroSchema)); err != nil {
+		return "", cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+
+	properties := make(map[string]interface{}, len(top.Fields))
+	required := make([]string, 0, len(top.Fields))
+	for _, field := range top.Fields {
+		name, _ := field["name"].(string)
+		property, nullable, err := avroType2JSONSchema(field["type"])
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		properties[name] = property
+		if !nullable {
+			required = append(required, name)
+		}
+	}
+	title := top.Name
+	if top.Namespace != "" {
+		title = top.Namespace + "." + top.Name
+	}
+	result, err := json.Marshal(map[string]interface{}{
+		"$schema":             jsonSchemaDraft,
+		"title":               title,
+		"type":                "object",
+		"properties":          properties,
+		"required":            required,
+		jsonSchemaAvroKeyword: compacted.String(),
+	})
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrAvroMarshalFailed, err)
+	}
+	return string(result), nil
+}
+
+// avroType2JSONSchema returns the JSON Schema of an Avro field type,
+// and whether the field is nullable.
+func avroType2JSONSchema(avroType interface{}) (map[string]interface{}, bool, error) {
+	switch tp := avroType.(type) {
+	case string:
+		switch tp {
+		case "null":
+			return map[string]interface{}{"type": "null"}, true, nil
+		case "boolean":
+			return map[string]interface{}{"type": "boolean"}, false, nil
+		case "int", "long":
+			return map[string]interface{}{"type": "integer"}, false, nil
+		case "float", "double":
+			return map[string]interface{}{"type": "number"}, false, nil
+		case "string":
+			return map[string]interface{}{"type": "string"}, false, nil
+		case "bytes":
+			return map[string]interface{}{
+				"type":            "string",
+				"contentEncoding": "base64",
+			}, false, nil
+		}
+	case []interface{}:
+		// columns which can be null are encoded as union of null and the type.
+		var (
+			result   map[string]interface{}
+			nullable bool
+		)
+		for _, t := range tp {
+			if t == "null" {
+				nullable = true
+				continue
+			}
+			schema, _, err := avroType2JSONSchema(t)
+			if err != nil {
+				return nil, false, err
+			}
+			result = schema
+		}
+		if result == nil {
+			break
+		}
+		if nullable {
+			result["type"] = []interface{}{result["type"], "null"}
+		}
+		return result, nullable, nil
+	case map[string]interface{}:
+		switch tp["type"] {
+		case "enum":
+			return map[string]interface{}{
+				"type": "string",
+				"enum": tp["symbols"],
+			}, false, nil
+		case "array":
+			items, _, err := avroType2JSONSchema(tp["items"])
+			if err != nil {
+				return nil, false, err
+			}
+			return map[string]interface{}{
+				"type":  "array",
+				"items": items,
+			}, false, nil
+		}
+		result, nullable, err := avroType2JSONSchema(tp["type"])
+		if err != nil {
+			return nil, false, err
+		}
+		if params, ok := tp["connect.parameters"].(map[string]interface{}); ok {
+			if t, ok := params[tidbType].(string); ok {
+				result["description"] = t
+			}
+		}
+		return result, nullable, nil
+	}
+	return nil, false, cerror.ErrAvroSchemaAPIError.GenWithStack(
+		"unsupported avro type %v", avroType)
+}
+
+// avroSchemaFromJSONSchema extracts the Avro schema kept in the JSON Schema.
+func avroSchemaFromJSONSchema(jsonSchema string) (string, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonSchema), &schema); err != nil {
+		return "", cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	avroSchema, ok := schema[jsonSchemaAvroKeyword].(string)
+	if !ok {
+		return "", cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"JSON Schema doesn't contain the avro schema in %s", jsonSchemaAvroKeyword)
+	}
+	return avroSchema, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+// newKarapaceMock starts a mock of Karapace which only accepts JSON Schemas.
+func newKarapaceMock() *httptest.Server {
+	var (
+		mu      sync.Mutex
+		schemas []string
+	)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/":
+			_, _ = w.Write([]byte("{}"))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/subjects/"):
+			var req registerRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil ||
+				req.SchemaType != jsonSchemaType {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				return
+			}
+			schemas = append(schemas, req.Schema)
+			_ = json.NewEncoder(w).Encode(&registerResponse{SchemaID: len(schemas)})
+		case r.Method == http.MethodGet && r.URL.Path == "/schemas/ids/1" && len(schemas) > 0:
+			_ = json.NewEncoder(w).Encode(&jsonSchemaLookupResponse{
+				Schema:     schemas[0],
+				SchemaType: jsonSchemaType,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestJSONSchemaRegistry(t *testing.T) {
+	server := newKarapaceMock()
+	defer server.Close()
+
+	cfg := common.NewConfig(config.ProtocolAvro)
+	cfg.AvroConfluentSchemaRegistry = server.URL
+	cfg.AvroSchemaRegistryType = common.SchemaRegistryTypeJSONSchema
+	builder, err := NewBatchEncoderBuilder(getTestingContext(), cfg)
+	require.NoError(t, err)
+	manager := builder.(*batchEncoderBuilder).schemaM
+	require.Equal(t, common.SchemaRegistryTypeJSONSchema, manager.RegistryType())
+
+	// the avro schema of table `test`.`t` (id bigint primary key, name varchar(32), price decimal(10,2))
+	avroSchema := `{"type":"record","name":"t","namespace":"default.test","fields":[` +
+		`{"name":"id","type":{"type":"long","connect.parameters":{"tidb_type":"BIGINT"}}},` +
+		`{"name":"name","type":["null",{"type":"string","connect.parameters":{"tidb_type":"TEXT"}}],` +
+		`"default":null},` +
+		`{"name":"price","type":["null",{"type":"bytes","connect.parameters":{"tidb_type":"DECIMAL"},` +
+		`"logicalType":"decimal","precision":10,"scale":2}],"default":null}]}`
+	codec, header, err := manager.GetCachedOrRegister(getTestingContext(), "test.t-value", 1,
+		func() (string, error) { return avroSchema, nil })
+	require.NoError(t, err)
+	require.Equal(t, []byte{magicByte, 0, 0, 0, 1}, header)
+
+	// the registered schema is a JSON Schema generated from the columns.
+	resp, err := http.Get(server.URL + "/schemas/ids/1")
+	require.NoError(t, err)
+	var lookup jsonSchemaLookupResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&lookup))
+	require.NoError(t, resp.Body.Close())
+	var jsonSchema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lookup.Schema), &jsonSchema))
+	require.Equal(t, "object", jsonSchema["type"])
+	require.Equal(t, "default.test.t", jsonSchema["title"])
+	require.Equal(t, []interface{}{"id"}, jsonSchema["required"])
+	require.Equal(t, map[string]interface{}{
+		"id":   map[string]interface{}{"type": "integer", "description": "BIGINT"},
+		"name": map[string]interface{}{"type": []interface{}{"string", "null"}, "description": "TEXT"},
+		"price": map[string]interface{}{
+			"type":            []interface{}{"string", "null"},
+			"contentEncoding": "base64",
+			"description":     "DECIMAL",
+		},
+	}, jsonSchema["properties"])
+
+	// a new registry client retrieves the schema without the cache.
+	registry, err := NewJSONSchemaRegistry(getTestingContext(), server.URL, nil, "")
+	require.NoError(t, err)
+	lookupCodec, err := registry.Lookup(getTestingContext(), "test.t-value",
+		schemaID{confluentSchemaID: 1})
+	require.NoError(t, err)
+	expected, err := goavro.NewCodec(avroSchema)
+	require.NoError(t, err)
+	require.Equal(t, expected.CanonicalSchema(), lookupCodec.CanonicalSchema())
+	require.Equal(t, codec.CanonicalSchema(), lookupCodec.CanonicalSchema())
+
+	_, err = registry.Lookup(getTestingContext(), "test.t-value", schemaID{confluentSchemaID: 2})
+	require.Regexp(t, `.*not\sfound.*`, err)
+}
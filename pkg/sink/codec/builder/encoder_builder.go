@@ -25,7 +25,9 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/craft"
 	"github.com/pingcap/tiflow/pkg/sink/codec/csv"
 	"github.com/pingcap/tiflow/pkg/sink/codec/debezium"
+	"github.com/pingcap/tiflow/pkg/sink/codec/kafkastreams"
 	"github.com/pingcap/tiflow/pkg/sink/codec/maxwell"
+	"github.com/pingcap/tiflow/pkg/sink/codec/ocdf"
 	"github.com/pingcap/tiflow/pkg/sink/codec/open"
 	"github.com/pingcap/tiflow/pkg/sink/codec/simple"
 )
@@ -52,6 +54,10 @@ func NewRowEventEncoderBuilder(
 		return debezium.NewBatchEncoderBuilder(cfg, config.GetGlobalServerConfig().ClusterID), nil
 	case config.ProtocolSimple:
 		return simple.NewBuilder(ctx, cfg)
+	case config.ProtocolKafkaStreams:
+		return kafkastreams.NewBatchEncoderBuilder(cfg), nil
+	case config.ProtocolOCDF:
+		return ocdf.NewBatchEncoderBuilder(cfg, config.GetGlobalServerConfig().ClusterID), nil
 	default:
 		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(cfg.Protocol)
 	}
@@ -124,7 +124,11 @@ func (b *canalEntryBuilder) buildColumn(c *model.Column, columnInfo *timodel.Col
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
 
-	value, err := b.formatValue(c.Value, c.Flag.IsBinary())
+	colValue, err := common.ConvertCharset(c.Value, c.Charset, b.config.CharsetConversionMode)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+	}
+	value, err := b.formatValue(colValue, c.Flag.IsBinary())
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
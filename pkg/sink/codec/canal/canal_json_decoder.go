@@ -127,7 +127,7 @@ func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
 		return model.MessageTypeUnknown, false, nil
 	}
 
-	if err := json.Unmarshal(encodedData, msg); err != nil {
+	if err := unmarshalJSONMessage(encodedData, msg); err != nil {
 		log.Error("canal-json decoder unmarshal data failed",
 			zap.Error(err), zap.ByteString("data", encodedData))
 		return model.MessageTypeUnknown, false, err
@@ -152,7 +152,7 @@ func (b *batchDecoder) assembleClaimCheckRowChangedEvent(ctx context.Context, cl
 		return nil, err
 	}
 	message := &canalJSONMessageWithTiDBExtension{}
-	err = json.Unmarshal(value, message)
+	err = unmarshalJSONMessage(value, message)
 	if err != nil {
 		return nil, err
 	}
@@ -312,8 +312,10 @@ func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
 
 // NextResolvedEvent implements the RowEventDecoder interface
 // `HasNext` should be called before this.
+// It also returns the resolved ts carried by the heartbeat event.
 func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
-	if b.msg == nil || b.msg.messageType() != model.MessageTypeResolved {
+	if b.msg == nil || (b.msg.messageType() != model.MessageTypeResolved &&
+		b.msg.messageType() != model.MessageTypeHeartbeat) {
 		return 0, cerror.ErrCanalDecodeFailed.
 			GenWithStack("not found resolved event message")
 	}
@@ -328,3 +330,11 @@ func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
 	b.msg = nil
 	return withExtensionEvent.Extensions.WatermarkTs, nil
 }
+
+// unmarshalJSONMessage decodes numbers as json.Number, so that values of JSON
+// columns embedded as objects keep their precision.
+func unmarshalJSONMessage(data []byte, msg canalJSONMessageInterface) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(msg)
+}
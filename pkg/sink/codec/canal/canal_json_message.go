@@ -18,18 +18,23 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/goccy/go-json"
 	"github.com/pingcap/log"
 	timodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/spatial"
 	"github.com/pingcap/tiflow/pkg/sink/codec/utils"
 	canal "github.com/pingcap/tiflow/proto/canal"
 	"go.uber.org/zap"
 	"golang.org/x/text/encoding/charmap"
 )
 
-const tidbWaterMarkType = "TIDB_WATERMARK"
+const (
+	tidbWaterMarkType = "TIDB_WATERMARK"
+	tidbHeartbeatType = "TIDB_HEARTBEAT"
+)
 
 // The TiCDC Canal-JSON implementation extend the official format with a TiDB extension field.
 // canalJSONMessageInterface is used to support this without affect the original format.
@@ -41,6 +46,7 @@ type canalJSONMessageInterface interface {
 	getOld() map[string]interface{}
 	getData() map[string]interface{}
 	getMySQLType() map[string]string
+	getCharset() map[string]string
 	getJavaSQLType() map[string]int32
 	messageType() model.MessageType
 	eventType() canal.EventType
@@ -66,6 +72,9 @@ type JSONMessage struct {
 	SQLType map[string]int32 `json:"sqlType"`
 	// only works for INSERT / UPDATE / DELETE events, records each column's mysql representation type.
 	MySQLType map[string]string `json:"mysqlType"`
+	// Charset is a TiCDC custom field, records the original charset of each
+	// GBK and GB18030 column.
+	Charset map[string]string `json:"_charset,omitempty"`
 	// A Datum should be a string or nil
 	Data []map[string]interface{} `json:"data"`
 	Old  []map[string]interface{} `json:"old"`
@@ -106,6 +115,10 @@ func (c *JSONMessage) getMySQLType() map[string]string {
 	return c.MySQLType
 }
 
+func (c *JSONMessage) getCharset() map[string]string {
+	return c.Charset
+}
+
 func (c *JSONMessage) getJavaSQLType() map[string]int32 {
 	return c.SQLType
 }
@@ -119,6 +132,10 @@ func (c *JSONMessage) messageType() model.MessageType {
 		return model.MessageTypeResolved
 	}
 
+	if c.EventType == tidbHeartbeatType {
+		return model.MessageTypeHeartbeat
+	}
+
 	return model.MessageTypeRow
 }
 
@@ -158,17 +175,18 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 	result := new(model.RowChangedEvent)
 	result.CommitTs = msg.getCommitTs()
 	mysqlType := msg.getMySQLType()
+	charsets := msg.getCharset()
 	var err error
 	if msg.eventType() == canal.EventType_DELETE {
 		// for `DELETE` event, `data` contain the old data, set it as the `PreColumns`
-		preCols, err := canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType)
+		preCols, err := canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType, charsets)
 		result.TableInfo = model.BuildTableInfoWithPKNames4Test(*msg.getSchema(), *msg.getTable(), preCols, msg.pkNameSet())
 		result.PreColumns = model.Columns2ColumnDatas(preCols, result.TableInfo)
 		return result, err
 	}
 
 	// for `INSERT` and `UPDATE`, `data` contain fresh data, set it as the `Columns`
-	cols, err := canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType)
+	cols, err := canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType, charsets)
 	result.TableInfo = model.BuildTableInfoWithPKNames4Test(*msg.getSchema(), *msg.getTable(), cols, msg.pkNameSet())
 	result.Columns = model.Columns2ColumnDatas(cols, result.TableInfo)
 	if err != nil {
@@ -177,7 +195,7 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 
 	// for `UPDATE`, `old` contain old data, set it as the `PreColumns`
 	if msg.eventType() == canal.EventType_UPDATE {
-		preCols, err := canalJSONColumnMap2RowChangeColumns(msg.getOld(), mysqlType)
+		preCols, err := canalJSONColumnMap2RowChangeColumns(msg.getOld(), mysqlType, charsets)
 		if len(preCols) < len(cols) {
 			newPreCols := make([]*model.Column, 0, len(preCols))
 			j := 0
@@ -204,7 +222,9 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 	return result, nil
 }
 
-func canalJSONColumnMap2RowChangeColumns(cols map[string]interface{}, mysqlType map[string]string) ([]*model.Column, error) {
+func canalJSONColumnMap2RowChangeColumns(
+	cols map[string]interface{}, mysqlType map[string]string, charsets map[string]string,
+) ([]*model.Column, error) {
 	result := make([]*model.Column, 0, len(cols))
 	for name, value := range cols {
 		mysqlTypeStr, ok := mysqlType[name]
@@ -214,6 +234,7 @@ func canalJSONColumnMap2RowChangeColumns(cols map[string]interface{}, mysqlType
 				"mysql type does not found, column: %+v, mysqlType: %+v", name, mysqlType)
 		}
 		col := canalJSONFormatColumn(value, name, mysqlTypeStr)
+		col.Charset = charsets[name]
 		result = append(result, col)
 	}
 	if len(result) == 0 {
@@ -236,12 +257,41 @@ func canalJSONFormatColumn(value interface{}, name string, mysqlTypeStr string)
 		return result
 	}
 
+	// the value of a JSON column is embedded as a native JSON value
+	// if `json-column-as-object` is enabled, convert it back to a string.
+	if _, ok := value.(string); !ok && mysqlType == mysql.TypeJSON {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			log.Panic("invalid json column value, please report a bug",
+				zap.Any("value", value), zap.Error(err))
+		}
+		value = string(encoded)
+		result.Value = value
+	}
+
 	data, ok := value.(string)
 	if !ok {
 		log.Panic("canal-json encoded message should have type in `string`")
 	}
 
 	var err error
+	if mysqlType == mysql.TypeGeometry {
+		// spatial values are encoded as WKT, or WKB if `spatial-encoding` is wkb,
+		// the SRID is not encoded, so it's always 0 after decoding.
+		value, err = spatial.FromWKT(data, 0)
+		if err != nil {
+			var wkb string
+			wkb, err = charmap.ISO8859_1.NewEncoder().String(data)
+			if err == nil {
+				value, err = spatial.FromWKB([]byte(wkb), 0)
+			}
+		}
+		if err != nil {
+			log.Panic("invalid spatial column value, please report a bug", zap.Any("col", result), zap.Error(err))
+		}
+		result.Value = value
+		return result
+	}
 	if utils.IsBinaryMySQLType(mysqlTypeStr) {
 		// when encoding the `JavaSQLTypeBLOB`, use `ISO8859_1` decoder, now reverse it back.
 		encoder := charmap.ISO8859_1.NewEncoder()
@@ -14,6 +14,7 @@
 package canal
 
 import (
+	"bytes"
 	"context"
 	"time"
 
@@ -21,11 +22,13 @@ import (
 	"github.com/mailru/easyjson/jwriter"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/spatial"
 	"github.com/pingcap/tiflow/pkg/sink/codec/utils"
 	"github.com/pingcap/tiflow/pkg/sink/kafka/claimcheck"
 	"go.uber.org/zap"
@@ -36,6 +39,7 @@ func fillColumns(
 	onlyOutputUpdatedColumn bool,
 	onlyHandleKeyColumn bool,
 	newColumnMap map[string]*model.Column,
+	config *common.Config,
 	out *jwriter.Writer,
 	builder *canalEntryBuilder,
 ) error {
@@ -60,7 +64,18 @@ func fillColumns(
 			} else {
 				out.RawByte(',')
 			}
-			value, err := builder.formatValue(col.Value, col.Flag.IsBinary())
+			colValue, err := common.ConvertCharset(col.Value, col.Charset, config.CharsetConversionMode)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+			}
+			isBinary := col.Flag.IsBinary()
+			if col.Type == mysql.TypeGeometry && colValue != nil {
+				colValue, isBinary, err = formatSpatialValue(colValue, config.SpatialEncoding)
+				if err != nil {
+					return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+				}
+			}
+			value, err := builder.formatValue(colValue, isBinary)
 			if err != nil {
 				return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 			}
@@ -68,6 +83,8 @@ func fillColumns(
 			out.RawByte(':')
 			if col.Value == nil {
 				out.RawString("null")
+			} else if config.JSONColumnAsObject && col.Type == mysql.TypeJSON {
+				writeJSONColumnValue(col.Name, value, out)
 			} else {
 				out.String(value)
 			}
@@ -78,6 +95,39 @@ func fillColumns(
 	return nil
 }
 
+// formatSpatialValue converts the value of a spatial column to WKT or WKB,
+// WKB is output as a binary value.
+func formatSpatialValue(value interface{}, encoding string) (interface{}, bool, error) {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, false, errors.Errorf("unexpected spatial value type %T", value)
+	}
+	if encoding == common.SpatialEncodingWKB {
+		wkb, err := spatial.ToWKB(data)
+		return wkb, true, err
+	}
+	wkt, err := spatial.ToWKT(data)
+	return wkt, false, err
+}
+
+// writeJSONColumnValue embeds the value of a JSON column as a native JSON value.
+// An invalid JSON value is written as a string, just like the default behavior.
+func writeJSONColumnValue(name, value string, out *jwriter.Writer) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(value)); err != nil {
+		log.Warn("invalid value of json column, output it as a string",
+			zap.String("column", name), zap.Error(err))
+		out.String(value)
+		return
+	}
+	out.Raw(buf.Bytes(), nil)
+}
+
 func newJSONMessageForDML(
 	builder *canalEntryBuilder,
 	e *model.RowChangedEvent,
@@ -93,6 +143,8 @@ func newJSONMessageForDML(
 	}
 
 	mysqlTypeMap := make(map[string]string, len(e.Columns))
+	// charsetMap is the original charsets of the GBK and GB18030 columns.
+	var charsetMap map[string]string
 	out := &jwriter.Writer{}
 	out.RawByte('{')
 	{
@@ -184,6 +236,12 @@ func newJSONMessageForDML(
 				out.RawByte(':')
 				out.Int32(int32(javaType))
 				mysqlTypeMap[colName] = utils.GetMySQLType(columnInfo, config.ContentCompatible)
+				if common.IsConvertibleCharset(columnInfo.GetCharset()) {
+					if charsetMap == nil {
+						charsetMap = make(map[string]string)
+					}
+					charsetMap[colName] = columnInfo.GetCharset()
+				}
 			}
 		}
 		if emptyColumn {
@@ -213,13 +271,30 @@ func newJSONMessageForDML(
 			out.RawByte('}')
 		}
 	}
+	if charsetMap != nil {
+		const prefix string = ",\"_charset\":"
+		out.RawString(prefix)
+		out.RawByte('{')
+		isFirst := true
+		for name, charset := range charsetMap {
+			if isFirst {
+				isFirst = false
+			} else {
+				out.RawByte(',')
+			}
+			out.String(name)
+			out.RawByte(':')
+			out.String(charset)
+		}
+		out.RawByte('}')
+	}
 
 	if e.IsDelete() {
 		out.RawString(",\"old\":null")
 		out.RawString(",\"data\":")
 		if err := fillColumns(
 			e.GetPreColumns(),
-			false, onlyHandleKey, nil, out, builder,
+			false, onlyHandleKey, nil, config, out, builder,
 		); err != nil {
 			return nil, err
 		}
@@ -228,7 +303,7 @@ func newJSONMessageForDML(
 		out.RawString(",\"data\":")
 		if err := fillColumns(
 			e.GetColumns(),
-			false, onlyHandleKey, nil, out, builder,
+			false, onlyHandleKey, nil, config, out, builder,
 		); err != nil {
 			return nil, err
 		}
@@ -243,14 +318,14 @@ func newJSONMessageForDML(
 		out.RawString(",\"old\":")
 		if err := fillColumns(
 			e.GetPreColumns(),
-			config.OnlyOutputUpdatedColumns, onlyHandleKey, newColsMap, out, builder,
+			config.OnlyOutputUpdatedColumns, onlyHandleKey, newColsMap, config, out, builder,
 		); err != nil {
 			return nil, err
 		}
 		out.RawString(",\"data\":")
 		if err := fillColumns(
 			e.GetColumns(),
-			false, onlyHandleKey, nil, out, builder,
+			false, onlyHandleKey, nil, config, out, builder,
 		); err != nil {
 			return nil, err
 		}
@@ -344,14 +419,13 @@ func (c *JSONRowEventEncoder) newJSONMessageForDDL(e *model.DDLEvent) canalJSONM
 	}
 }
 
-func (c *JSONRowEventEncoder) newJSONMessage4CheckpointEvent(
-	ts uint64,
-) *canalJSONMessageWithTiDBExtension {
+// newJSONMessage4TsEvent creates the message of the checkpoint or heartbeat event.
+func newJSONMessage4TsEvent(eventType string, ts uint64) *canalJSONMessageWithTiDBExtension {
 	return &canalJSONMessageWithTiDBExtension{
 		JSONMessage: &JSONMessage{
 			ID:            0,
 			IsDDL:         false,
-			EventType:     tidbWaterMarkType,
+			EventType:     eventType,
 			ExecutionTime: convertToCanalTs(ts),
 			BuildTime:     time.Now().UnixNano() / int64(time.Millisecond), // converts to milliseconds
 		},
@@ -365,7 +439,27 @@ func (c *JSONRowEventEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message,
 		return nil, nil
 	}
 
-	msg := c.newJSONMessage4CheckpointEvent(ts)
+	value, err := c.encodeTsEvent(newJSONMessage4TsEvent(tidbWaterMarkType, ts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewResolvedMsg(config.ProtocolCanalJSON, nil, value, ts), nil
+}
+
+// EncodeHeartbeatEvent implements the HeartbeatEventEncoder interface
+func (c *JSONRowEventEncoder) EncodeHeartbeatEvent(ts uint64) (*common.Message, error) {
+	if !c.config.EnableTiDBExtension {
+		return nil, nil
+	}
+
+	value, err := c.encodeTsEvent(newJSONMessage4TsEvent(tidbHeartbeatType, ts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewHeartbeatMsg(config.ProtocolCanalJSON, nil, value, ts), nil
+}
+
+func (c *JSONRowEventEncoder) encodeTsEvent(msg *canalJSONMessageWithTiDBExtension) ([]byte, error) {
 	value, err := json.Marshal(msg)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
@@ -377,8 +471,7 @@ func (c *JSONRowEventEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-
-	return common.NewResolvedMsg(config.ProtocolCanalJSON, nil, value, ts), nil
+	return value, nil
 }
 
 // AppendRowChangedEvent implements the interface EventJSONBatchEncoder
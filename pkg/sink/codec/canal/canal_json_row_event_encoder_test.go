@@ -19,14 +19,19 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/mailru/easyjson/jwriter"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/spatial"
 	"github.com/pingcap/tiflow/pkg/sink/codec/utils"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
 )
 
 func TestBuildCanalJSONRowEventEncoder(t *testing.T) {
@@ -431,6 +436,42 @@ func TestEncodeCheckpointEvent(t *testing.T) {
 	}
 }
 
+func TestEncodeHeartbeatEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var ts uint64 = 2333
+	for _, enable := range []bool{false, true} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = enable
+
+		builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+		require.NoError(t, err)
+
+		encoder := builder.Build().(*JSONRowEventEncoder)
+		msg, err := encoder.EncodeHeartbeatEvent(ts)
+		require.NoError(t, err)
+		if !enable {
+			require.Nil(t, msg)
+			continue
+		}
+		require.Equal(t, model.MessageTypeHeartbeat, msg.Type)
+
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(msg.Key, msg.Value)
+		require.NoError(t, err)
+
+		ty, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeHeartbeat, ty)
+		consumed, err := decoder.NextResolvedEvent()
+		require.NoError(t, err)
+		require.Equal(t, ts, consumed)
+	}
+}
+
 func TestCheckpointEventValueMarshal(t *testing.T) {
 	t.Parallel()
 
@@ -817,3 +858,259 @@ func TestCanalJSONBatchDecoderWithTerminator(t *testing.T) {
 	}
 	require.Equal(t, 3, cnt)
 }
+
+func TestCanalJSONColumnAsObjectE2E(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	_ = helper.DDL2Event(`create table test.t(a int primary key, b json)`)
+	insertEvent := helper.DML2Event(`insert into test.t values (1, '{"key": [1, 2.5, "str", null, true], "big": 18446744073709551615, "nested": {"k": "v"}}')`, "test", "t")
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	codecConfig.JSONColumnAsObject = true
+
+	builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder := builder.Build()
+
+	err = encoder.AppendRowChangedEvent(ctx, "", insertEvent, func() {})
+	require.NoError(t, err)
+	message := encoder.Build()[0]
+
+	var encoded struct {
+		Data []map[string]json.RawMessage `json:"data"`
+	}
+	err = json.Unmarshal(message.Value, &encoded)
+	require.NoError(t, err)
+	require.Len(t, encoded.Data, 1)
+	// the json column is embedded as an object instead of a string
+	require.Equal(t, byte('{'), encoded.Data[0]["b"][0])
+	require.Equal(t, `"1"`, string(encoded.Data[0]["a"]))
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(message.Key, message.Value)
+	require.NoError(t, err)
+	messageType, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, messageType)
+
+	decodedEvent, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+
+	// re-encode the decoded event, the embedded object must be identical.
+	err = encoder.AppendRowChangedEvent(ctx, "", decodedEvent, func() {})
+	require.NoError(t, err)
+	message = encoder.Build()[0]
+
+	var reEncoded struct {
+		Data []map[string]json.RawMessage `json:"data"`
+	}
+	err = json.Unmarshal(message.Value, &reEncoded)
+	require.NoError(t, err)
+	require.Len(t, reEncoded.Data, 1)
+	require.JSONEq(t, string(encoded.Data[0]["b"]), string(reEncoded.Data[0]["b"]))
+	require.Contains(t, string(reEncoded.Data[0]["b"]), "18446744073709551615")
+}
+
+func TestCanalJSONColumnAsObjectInvalidValue(t *testing.T) {
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	builder := newCanalEntryBuilder(codecConfig)
+
+	columns := []*model.Column{
+		{Name: "a", Type: mysql.TypeJSON, Value: `{"k": "v"}`},
+		{Name: "b", Type: mysql.TypeJSON, Value: `{"k": invalid}`},
+		{Name: "c", Type: mysql.TypeVarchar, Value: `{"k": "v"}`},
+		{Name: "d", Type: mysql.TypeJSON, Value: nil},
+	}
+
+	out := &jwriter.Writer{}
+	err := fillColumns(columns, false, false, nil, &common.Config{JSONColumnAsObject: true}, out, builder)
+	require.NoError(t, err)
+	require.Equal(t,
+		`[{"a":{"k":"v"},"b":"{\"k\": invalid}","c":"{\"k\": \"v\"}","d":null}]`,
+		string(out.Buffer.BuildBytes()))
+
+	// keep the string representation if disabled.
+	out = &jwriter.Writer{}
+	err = fillColumns(columns, false, false, nil, codecConfig, out, builder)
+	require.NoError(t, err)
+	require.Equal(t,
+		`[{"a":"{\"k\": \"v\"}","b":"{\"k\": invalid}","c":"{\"k\": \"v\"}","d":null}]`,
+		string(out.Buffer.BuildBytes()))
+}
+
+func TestCanalJSONSpatialColumnE2E(t *testing.T) {
+	ctx := context.Background()
+
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "point", Type: mysql.TypeGeometry},
+		{Name: "line", Type: mysql.TypeGeometry},
+		{Name: "polygon", Type: mysql.TypeGeometry, Flag: model.NullableFlag},
+	}
+	tableInfo := model.BuildTableInfo("test", "t", columns, [][]int{{0}})
+
+	wkts := []string{
+		"POINT(1 2)",
+		"LINESTRING(0 0,1 1,2.5 -3)",
+		"POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,4 2,4 4,2 2))",
+	}
+	values := make([]interface{}, 0, len(wkts))
+	for _, wkt := range wkts {
+		value, err := spatial.FromWKT(wkt, 0)
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+	event := &model.RowChangedEvent{
+		CommitTs:  1,
+		TableInfo: tableInfo,
+		Columns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: int64(1)},
+			{Name: "point", Value: values[0]},
+			{Name: "line", Value: values[1]},
+			{Name: "polygon", Value: values[2]},
+		}, tableInfo),
+	}
+
+	for _, encoding := range []string{"", common.SpatialEncodingWKT, common.SpatialEncodingWKB} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = true
+		codecConfig.SpatialEncoding = encoding
+
+		builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+		require.NoError(t, err)
+		encoder := builder.Build()
+		err = encoder.AppendRowChangedEvent(ctx, "", event, func() {})
+		require.NoError(t, err)
+		message := encoder.Build()[0]
+
+		var encoded JSONMessage
+		err = json.Unmarshal(message.Value, &encoded)
+		require.NoError(t, err)
+		if encoding != common.SpatialEncodingWKB {
+			require.Equal(t, wkts[0], encoded.Data[0]["point"])
+			require.Equal(t, wkts[1], encoded.Data[0]["line"])
+			require.Equal(t, wkts[2], encoded.Data[0]["polygon"])
+		}
+
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(message.Key, message.Value)
+		require.NoError(t, err)
+		_, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		decodedEvent, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+
+		decoded := make(map[string]interface{})
+		for _, col := range decodedEvent.GetColumns() {
+			decoded[col.Name] = col.Value
+		}
+		require.Equal(t, values[0], decoded["point"])
+		require.Equal(t, values[1], decoded["line"])
+		require.Equal(t, values[2], decoded["polygon"])
+	}
+}
+
+func TestCanalJSONCharsetConversionE2E(t *testing.T) {
+	ctx := context.Background()
+
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "gbk", Type: mysql.TypeVarchar, Charset: charset.CharsetGBK, Flag: model.NullableFlag},
+		{Name: "gb18030", Type: mysql.TypeVarchar, Charset: charset.CharsetGB18030, Flag: model.NullableFlag},
+		{Name: "utf8", Type: mysql.TypeVarchar, Flag: model.NullableFlag},
+	}
+	tableInfo := model.BuildTableInfo("test", "t", columns, [][]int{{0}})
+
+	gbkValue, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte("中文"))
+	require.NoError(t, err)
+	event := &model.RowChangedEvent{
+		CommitTs:  1,
+		TableInfo: tableInfo,
+		Columns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: int64(1)},
+			{Name: "gbk", Value: gbkValue},
+			// the values of GBK columns are stored in UTF-8 by TiDB.
+			{Name: "gb18030", Value: []byte("中文")},
+			{Name: "utf8", Value: []byte("中文")},
+		}, tableInfo),
+	}
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	require.Equal(t, common.CharsetConversionModeAuto, codecConfig.CharsetConversionMode)
+
+	builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	err = encoder.AppendRowChangedEvent(ctx, "", event, func() {})
+	require.NoError(t, err)
+	message := encoder.Build()[0]
+
+	var encoded JSONMessage
+	err = json.Unmarshal(message.Value, &encoded)
+	require.NoError(t, err)
+	require.Equal(t, "中文", encoded.Data[0]["gbk"])
+	require.Equal(t, "中文", encoded.Data[0]["gb18030"])
+	require.Equal(t, "中文", encoded.Data[0]["utf8"])
+	require.Equal(t, map[string]string{
+		"gbk":     charset.CharsetGBK,
+		"gb18030": charset.CharsetGB18030,
+	}, encoded.Charset)
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(message.Key, message.Value)
+	require.NoError(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	decodedEvent, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+
+	for _, col := range decodedEvent.GetColumns() {
+		switch col.Name {
+		case "gbk", "gb18030", "utf8":
+			require.Equal(t, "中文", col.Value)
+		}
+		require.Equal(t, encoded.Charset[col.Name] != "", common.IsConvertibleCharset(col.Charset))
+	}
+
+	// re-encode the decoded event, the message must be identical.
+	err = encoder.AppendRowChangedEvent(ctx, "", decodedEvent, func() {})
+	require.NoError(t, err)
+	message = encoder.Build()[0]
+	var reEncoded JSONMessage
+	err = json.Unmarshal(message.Value, &reEncoded)
+	require.NoError(t, err)
+	require.Equal(t, encoded.Data, reEncoded.Data)
+	require.Equal(t, encoded.Charset, reEncoded.Charset)
+
+	// the raw bytes are output as they are.
+	codecConfig.CharsetConversionMode = common.CharsetConversionModeRaw
+	builder, err = NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder = builder.Build()
+	err = encoder.AppendRowChangedEvent(ctx, "", event, func() {})
+	require.NoError(t, err)
+	message = encoder.Build()[0]
+	var rawEncoded JSONMessage
+	err = json.Unmarshal(message.Value, &rawEncoded)
+	require.NoError(t, err)
+	require.NotEqual(t, "中文", rawEncoded.Data[0]["gbk"])
+	require.Equal(t, "中文", rawEncoded.Data[0]["gb18030"])
+
+	// the value which is not valid UTF-8 fails the encoding.
+	codecConfig.CharsetConversionMode = common.CharsetConversionModeError
+	builder, err = NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder = builder.Build()
+	err = encoder.AppendRowChangedEvent(ctx, "", event, func() {})
+	require.ErrorIs(t, err, cerror.ErrCanalEncodeFailed)
+}
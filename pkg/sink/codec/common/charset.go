@@ -0,0 +1,75 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+const (
+	// CharsetConversionModeAuto transcodes the values of GBK and GB18030
+	// columns to UTF-8 if they are not valid UTF-8.
+	CharsetConversionModeAuto = "auto"
+	// CharsetConversionModeRaw outputs the values of GBK and GB18030 columns
+	// as they are.
+	CharsetConversionModeRaw = "raw"
+	// CharsetConversionModeError fails the encoding if the value of a GBK or
+	// GB18030 column is not valid UTF-8.
+	CharsetConversionModeError = "error"
+)
+
+// IsConvertibleCharset returns true if the values of columns of the charset
+// may need to be transcoded to UTF-8.
+func IsConvertibleCharset(charsetName string) bool {
+	return getCharsetEncoding(charsetName) != nil
+}
+
+func getCharsetEncoding(charsetName string) encoding.Encoding {
+	switch strings.ToLower(charsetName) {
+	case charset.CharsetGBK:
+		return simplifiedchinese.GBK
+	case charset.CharsetGB18030:
+		return simplifiedchinese.GB18030
+	}
+	return nil
+}
+
+// ConvertCharset converts the value of a GBK or GB18030 column to UTF-8 by
+// the mode, the values of other columns are returned as they are.
+// TiDB stores the values of GBK columns in UTF-8, so only the values which
+// are not valid UTF-8 are transcoded.
+func ConvertCharset(value interface{}, charsetName string, mode string) (interface{}, error) {
+	v, ok := value.([]byte)
+	if !ok || mode == CharsetConversionModeRaw || utf8.Valid(v) {
+		return value, nil
+	}
+	enc := getCharsetEncoding(charsetName)
+	if enc == nil {
+		return value, nil
+	}
+	if mode == CharsetConversionModeError {
+		return nil, errors.Errorf("the value of %s column is not valid UTF-8", charsetName)
+	}
+	decoded, err := enc.NewDecoder().Bytes(v)
+	if err != nil {
+		return nil, errors.Annotatef(err, "transcode the value of %s column failed", charsetName)
+	}
+	return decoded, nil
+}
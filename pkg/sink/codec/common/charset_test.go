@@ -0,0 +1,69 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestConvertCharset(t *testing.T) {
+	t.Parallel()
+
+	gbkValue, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte("中文"))
+	require.NoError(t, err)
+	// the 4-byte sequences are only available in GB18030.
+	gb18030Value, err := simplifiedchinese.GB18030.NewEncoder().Bytes([]byte("中文𠀀"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		value    interface{}
+		charset  string
+		mode     string
+		expected interface{}
+		hasError bool
+	}{
+		{gbkValue, charset.CharsetGBK, CharsetConversionModeAuto, []byte("中文"), false},
+		{gbkValue, "GBK", CharsetConversionModeAuto, []byte("中文"), false},
+		{gb18030Value, charset.CharsetGB18030, CharsetConversionModeAuto, []byte("中文𠀀"), false},
+		{[]byte("中文"), charset.CharsetGBK, CharsetConversionModeAuto, []byte("中文"), false},
+		{"中文", charset.CharsetGBK, CharsetConversionModeAuto, "中文", false},
+		{nil, charset.CharsetGBK, CharsetConversionModeAuto, nil, false},
+		{gbkValue, charset.CharsetUTF8MB4, CharsetConversionModeAuto, gbkValue, false},
+		{gbkValue, charset.CharsetBin, CharsetConversionModeAuto, gbkValue, false},
+		{gbkValue, charset.CharsetGBK, CharsetConversionModeRaw, gbkValue, false},
+		{[]byte("中文"), charset.CharsetGBK, CharsetConversionModeError, []byte("中文"), false},
+		{gbkValue, charset.CharsetGBK, CharsetConversionModeError, nil, true},
+		{gbkValue, charset.CharsetUTF8MB4, CharsetConversionModeError, gbkValue, false},
+	}
+	for _, tc := range testCases {
+		converted, err := ConvertCharset(tc.value, tc.charset, tc.mode)
+		if tc.hasError {
+			require.ErrorContains(t, err, "not valid UTF-8")
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, converted)
+	}
+
+	// the transcoded value can be encoded back to the original charset.
+	converted, err := ConvertCharset(gb18030Value, charset.CharsetGB18030, CharsetConversionModeAuto)
+	require.NoError(t, err)
+	encoded, err := simplifiedchinese.GB18030.NewEncoder().Bytes(converted.([]byte))
+	require.NoError(t, err)
+	require.Equal(t, gb18030Value, encoded)
+}
@@ -14,8 +14,12 @@
 package common
 
 import (
+	"encoding/base64"
 	"net/http"
 	"net/url"
+	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin/binding"
@@ -25,6 +29,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
@@ -32,6 +37,9 @@ import (
 // defaultMaxBatchSize sets the default value for max-batch-size
 const defaultMaxBatchSize int = 16
 
+// avroSchemaNamespaceRe matches the valid avro-schema-namespace values.
+var avroSchemaNamespaceRe = regexp.MustCompile(`^[a-zA-Z0-9.]+$`)
+
 // Config use to create the encoder
 type Config struct {
 	ChangefeedID model.ChangeFeedID
@@ -54,7 +62,27 @@ type Config struct {
 	AvroConfluentSchemaRegistry    string
 	AvroDecimalHandlingMode        string
 	AvroBigintUnsignedHandlingMode string
-	AvroGlueSchemaRegistry         *config.GlueSchemaRegistryConfig
+	AvroEnumSetHandlingMode        string
+	// AvroSchemaNamespace is the prefix of the schema registry subjects,
+	// it's used to isolate the subjects of changefeeds sharing a registry.
+	AvroSchemaNamespace string
+	// AvroSchemaCompatibility is the compatibility level of the schema
+	// registry subjects, the global default of the registry is used if empty.
+	AvroSchemaCompatibility string
+	AvroGlueSchemaRegistry  *config.GlueSchemaRegistryConfig
+	// The paths of the TLS files used to connect to the confluent schema
+	// registry, the CA is required if the cert and key are set.
+	SchemaRegistryCA   string
+	SchemaRegistryCert string
+	SchemaRegistryKey  string
+	// Username and password for the basic authentication of the confluent
+	// schema registry, or the API key used as a bearer token.
+	SchemaRegistryUsername string
+	SchemaRegistryPassword string
+	SchemaRegistryAPIKey   string
+	// AvroSchemaRegistryType is the type of schemas registered to the
+	// confluent compatible schema registry, `confluent` or `json-schema`.
+	AvroSchemaRegistryType string
 	// EnableWatermarkEvent set to true, avro encode DDL and checkpoint event
 	// and send to the downstream kafka, they cannot be consumed by the confluent official consumer
 	// and would cause error, so this is only used for ticdc internal testing purpose, should not be
@@ -63,6 +91,17 @@ type Config struct {
 
 	// canal-json only
 	ContentCompatible bool
+	// JSONColumnAsObject outputs values of JSON columns as JSON objects
+	// instead of strings.
+	JSONColumnAsObject bool
+
+	// canal-json and avro only, the encoding of values of spatial columns,
+	// the default is `wkt` for canal-json and `wkb` for avro.
+	SpatialEncoding string
+
+	// canal and canal-json only, how to convert the values of GBK and
+	// GB18030 columns, can be `auto`, `raw` and `error`.
+	CharsetConversionMode string
 
 	// for sinking to cloud storage
 	Delimiter            string
@@ -111,6 +150,8 @@ func NewConfig(protocol config.Protocol) *Config {
 		AvroConfluentSchemaRegistry:    "",
 		AvroDecimalHandlingMode:        "precise",
 		AvroBigintUnsignedHandlingMode: "long",
+		AvroEnumSetHandlingMode:        "string",
+		AvroSchemaRegistryType:         SchemaRegistryTypeConfluent,
 		AvroEnableWatermark:            false,
 
 		OnlyOutputUpdatedColumns:   false,
@@ -119,6 +160,8 @@ func NewConfig(protocol config.Protocol) *Config {
 
 		EncodingFormat: EncodingFormatJSON,
 
+		CharsetConversionMode: CharsetConversionModeAuto,
+
 		TimeZone: time.Local,
 	}
 }
@@ -127,7 +170,19 @@ const (
 	codecOPTEnableTiDBExtension            = "enable-tidb-extension"
 	codecOPTAvroDecimalHandlingMode        = "avro-decimal-handling-mode"
 	codecOPTAvroBigintUnsignedHandlingMode = "avro-bigint-unsigned-handling-mode"
+	codecOPTAvroEnumSetHandlingMode        = "avro-enum-set-handling-mode"
+	codecOPTAvroSchemaNamespace            = "avro-schema-namespace"
+	codecOPTAvroSchemaCompatibility        = "avro-schema-compatibility"
+	codecOPTSpatialEncoding                = "spatial-encoding"
+	codecOPTCharsetConversionMode          = "charset-conversion-mode"
 	codecOPTAvroSchemaRegistry             = "schema-registry"
+	codecOPTSchemaRegistryCA               = "schema-registry-ca"
+	codecOPTSchemaRegistryCert             = "schema-registry-cert"
+	codecOPTSchemaRegistryKey              = "schema-registry-key"
+	codecOPTSchemaRegistryUsername         = "schema-registry-username"
+	codecOPTSchemaRegistryPassword         = "schema-registry-password"
+	codecOPTSchemaRegistryAPIKey           = "schema-registry-api-key"
+	codecOPTSchemaRegistryType             = "schema-registry-type"
 	coderOPTAvroGlueSchemaRegistry         = "glue-schema-registry"
 )
 
@@ -140,6 +195,28 @@ const (
 	BigintUnsignedHandlingModeString = "string"
 	// BigintUnsignedHandlingModeLong is the long mode for unsigned bigint handling
 	BigintUnsignedHandlingModeLong = "long"
+	// BigintUnsignedHandlingModeBytes is the bytes mode for unsigned bigint handling,
+	// values are encoded as 16-byte big-endian unsigned integers.
+	BigintUnsignedHandlingModeBytes = "bytes"
+	// EnumSetHandlingModeString is the string mode for enum and set handling
+	EnumSetHandlingModeString = "string"
+	// EnumSetHandlingModeNative is the native mode for enum and set handling,
+	// enum values are encoded as avro enums and set values as avro string arrays.
+	EnumSetHandlingModeNative = "native"
+	// SpatialEncodingWKT encodes values of spatial columns as Well-Known Text
+	SpatialEncodingWKT = "wkt"
+	// SpatialEncodingWKB encodes values of spatial columns as Well-Known Binary
+	SpatialEncodingWKB = "wkb"
+	// AvroSchemaCompatibilityBackward allows consumers using the new schema
+	// to read data written with the last schema
+	AvroSchemaCompatibilityBackward = "BACKWARD"
+	// AvroSchemaCompatibilityForward allows consumers using the last schema
+	// to read data written with the new schema
+	AvroSchemaCompatibilityForward = "FORWARD"
+	// AvroSchemaCompatibilityFull requires both backward and forward compatibility
+	AvroSchemaCompatibilityFull = "FULL"
+	// AvroSchemaCompatibilityNone disables the compatibility checks
+	AvroSchemaCompatibilityNone = "NONE"
 )
 
 type urlConfig struct {
@@ -148,15 +225,28 @@ type urlConfig struct {
 	MaxMessageBytes                *int    `form:"max-message-bytes"`
 	AvroDecimalHandlingMode        *string `form:"avro-decimal-handling-mode"`
 	AvroBigintUnsignedHandlingMode *string `form:"avro-bigint-unsigned-handling-mode"`
+	AvroEnumSetHandlingMode        *string `form:"avro-enum-set-handling-mode"`
+	AvroSchemaNamespace            *string `form:"avro-schema-namespace"`
+	AvroSchemaCompatibility        *string `form:"avro-schema-compatibility"`
 
 	// AvroEnableWatermark is the option for enabling watermark in avro protocol
 	// only used for internal testing, do not set this in the production environment since the
 	// confluent official consumer cannot handle watermark.
 	AvroEnableWatermark *bool `form:"avro-enable-watermark"`
 
-	AvroSchemaRegistry       string `form:"schema-registry"`
-	OnlyOutputUpdatedColumns *bool  `form:"only-output-updated-columns"`
-	ContentCompatible        *bool  `form:"content-compatible"`
+	AvroSchemaRegistry       string  `form:"schema-registry"`
+	SchemaRegistryCA         *string `form:"schema-registry-ca"`
+	SchemaRegistryCert       *string `form:"schema-registry-cert"`
+	SchemaRegistryKey        *string `form:"schema-registry-key"`
+	SchemaRegistryUsername   *string `form:"schema-registry-username"`
+	SchemaRegistryPassword   *string `form:"schema-registry-password"`
+	SchemaRegistryAPIKey     *string `form:"schema-registry-api-key"`
+	SchemaRegistryType       *string `form:"schema-registry-type"`
+	OnlyOutputUpdatedColumns *bool   `form:"only-output-updated-columns"`
+	ContentCompatible        *bool   `form:"content-compatible"`
+	JSONColumnAsObject       *bool   `form:"json-column-as-object"`
+	SpatialEncoding          *string `form:"spatial-encoding"`
+	CharsetConversionMode    *string `form:"charset-conversion-mode"`
 
 	DebeziumDisableSchema *bool `form:"debezium-disable-schema"`
 	// EncodingFormatType is only works for the simple protocol,
@@ -197,6 +287,12 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		*urlParameter.AvroBigintUnsignedHandlingMode != "" {
 		c.AvroBigintUnsignedHandlingMode = *urlParameter.AvroBigintUnsignedHandlingMode
 	}
+	if urlParameter.AvroEnumSetHandlingMode != nil &&
+		*urlParameter.AvroEnumSetHandlingMode != "" {
+		c.AvroEnumSetHandlingMode = *urlParameter.AvroEnumSetHandlingMode
+	}
+	c.AvroSchemaNamespace = util.GetOrZero(urlParameter.AvroSchemaNamespace)
+	c.AvroSchemaCompatibility = strings.ToUpper(util.GetOrZero(urlParameter.AvroSchemaCompatibility))
 	if urlParameter.AvroEnableWatermark != nil {
 		if c.EnableTiDBExtension && c.Protocol == config.ProtocolAvro {
 			c.AvroEnableWatermark = *urlParameter.AvroEnableWatermark
@@ -205,6 +301,15 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 	if urlParameter.AvroSchemaRegistry != "" {
 		c.AvroConfluentSchemaRegistry = urlParameter.AvroSchemaRegistry
 	}
+	c.SchemaRegistryCA = util.GetOrZero(urlParameter.SchemaRegistryCA)
+	c.SchemaRegistryCert = util.GetOrZero(urlParameter.SchemaRegistryCert)
+	c.SchemaRegistryKey = util.GetOrZero(urlParameter.SchemaRegistryKey)
+	c.SchemaRegistryUsername = util.GetOrZero(urlParameter.SchemaRegistryUsername)
+	c.SchemaRegistryPassword = util.GetOrZero(urlParameter.SchemaRegistryPassword)
+	c.SchemaRegistryAPIKey = util.GetOrZero(urlParameter.SchemaRegistryAPIKey)
+	if urlParameter.SchemaRegistryType != nil {
+		c.AvroSchemaRegistryType = *urlParameter.SchemaRegistryType
+	}
 	if replicaConfig.Sink.KafkaConfig != nil &&
 		replicaConfig.Sink.KafkaConfig.GlueSchemaRegistryConfig != nil {
 		c.AvroGlueSchemaRegistry = replicaConfig.Sink.KafkaConfig.GlueSchemaRegistryConfig
@@ -213,6 +318,11 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		return cerror.ErrCodecInvalidConfig.GenWithStack(
 			`force-replicate must be disabled, when using avro protocol`)
 	}
+	// The key of kafka streams changelog records is the handle key.
+	if c.Protocol == config.ProtocolKafkaStreams && replicaConfig.ForceReplicate {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`force-replicate must be disabled, when using kafka-streams protocol`)
+	}
 
 	if replicaConfig.Sink != nil {
 		c.Terminator = util.GetOrZero(replicaConfig.Sink.Terminator)
@@ -253,6 +363,7 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		if c.ContentCompatible {
 			c.OnlyOutputUpdatedColumns = true
 		}
+		c.JSONColumnAsObject = util.GetOrZero(urlParameter.JSONColumnAsObject)
 	}
 
 	if c.Protocol == config.ProtocolSimple {
@@ -271,6 +382,11 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 	if urlParameter.DebeziumDisableSchema != nil {
 		c.DebeziumDisableSchema = *urlParameter.DebeziumDisableSchema
 	}
+	c.SpatialEncoding = util.GetOrZero(urlParameter.SpatialEncoding)
+	if urlParameter.CharsetConversionMode != nil &&
+		*urlParameter.CharsetConversionMode != "" {
+		c.CharsetConversionMode = *urlParameter.CharsetConversionMode
+	}
 
 	return nil
 }
@@ -282,8 +398,18 @@ func mergeConfig(
 	dest := &urlConfig{}
 	if replicaConfig.Sink != nil {
 		dest.AvroSchemaRegistry = util.GetOrZero(replicaConfig.Sink.SchemaRegistry)
+		dest.SchemaRegistryCA = replicaConfig.Sink.SchemaRegistryCA
+		dest.SchemaRegistryCert = replicaConfig.Sink.SchemaRegistryCert
+		dest.SchemaRegistryKey = replicaConfig.Sink.SchemaRegistryKey
+		dest.SchemaRegistryUsername = replicaConfig.Sink.SchemaRegistryUsername
+		dest.SchemaRegistryPassword = replicaConfig.Sink.SchemaRegistryPassword
+		dest.SchemaRegistryAPIKey = replicaConfig.Sink.SchemaRegistryAPIKey
+		dest.SchemaRegistryType = replicaConfig.Sink.SchemaRegistryType
 		dest.OnlyOutputUpdatedColumns = replicaConfig.Sink.OnlyOutputUpdatedColumns
 		dest.ContentCompatible = replicaConfig.Sink.ContentCompatible
+		dest.JSONColumnAsObject = replicaConfig.Sink.JSONColumnAsObject
+		dest.SpatialEncoding = replicaConfig.Sink.SpatialEncoding
+		dest.CharsetConversionMode = replicaConfig.Sink.CharsetConversionMode
 		if util.GetOrZero(dest.ContentCompatible) {
 			dest.OnlyOutputUpdatedColumns = util.AddressOf(true)
 		}
@@ -296,6 +422,9 @@ func mergeConfig(
 				dest.AvroEnableWatermark = codecConfig.AvroEnableWatermark
 				dest.AvroDecimalHandlingMode = codecConfig.AvroDecimalHandlingMode
 				dest.AvroBigintUnsignedHandlingMode = codecConfig.AvroBigintUnsignedHandlingMode
+				dest.AvroEnumSetHandlingMode = codecConfig.AvroEnumSetHandlingMode
+				dest.AvroSchemaNamespace = codecConfig.AvroSchemaNamespace
+				dest.AvroSchemaCompatibility = codecConfig.AvroSchemaCompatibility
 				dest.EncodingFormatType = codecConfig.EncodingFormat
 			}
 		}
@@ -348,6 +477,16 @@ func (c *Config) Validate() error {
 			)
 		}
 
+		if c.AvroSchemaRegistryType != SchemaRegistryTypeConfluent &&
+			c.AvroSchemaRegistryType != SchemaRegistryTypeJSONSchema {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only be "%s" or "%s"`,
+				codecOPTSchemaRegistryType,
+				SchemaRegistryTypeConfluent,
+				SchemaRegistryTypeJSONSchema,
+			)
+		}
+
 		if c.AvroDecimalHandlingMode != DecimalHandlingModePrecise &&
 			c.AvroDecimalHandlingMode != DecimalHandlingModeString {
 			return cerror.ErrCodecInvalidConfig.GenWithStack(
@@ -359,12 +498,45 @@ func (c *Config) Validate() error {
 		}
 
 		if c.AvroBigintUnsignedHandlingMode != BigintUnsignedHandlingModeLong &&
-			c.AvroBigintUnsignedHandlingMode != BigintUnsignedHandlingModeString {
+			c.AvroBigintUnsignedHandlingMode != BigintUnsignedHandlingModeString &&
+			c.AvroBigintUnsignedHandlingMode != BigintUnsignedHandlingModeBytes {
 			return cerror.ErrCodecInvalidConfig.GenWithStack(
-				`%s value could only be "%s" or "%s"`,
+				`%s value could only be "%s", "%s" or "%s"`,
 				codecOPTAvroBigintUnsignedHandlingMode,
 				BigintUnsignedHandlingModeLong,
 				BigintUnsignedHandlingModeString,
+				BigintUnsignedHandlingModeBytes,
+			)
+		}
+
+		if c.AvroEnumSetHandlingMode != EnumSetHandlingModeString &&
+			c.AvroEnumSetHandlingMode != EnumSetHandlingModeNative {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only be "%s" or "%s"`,
+				codecOPTAvroEnumSetHandlingMode,
+				EnumSetHandlingModeString,
+				EnumSetHandlingModeNative,
+			)
+		}
+
+		if c.AvroSchemaNamespace != "" && !avroSchemaNamespaceRe.MatchString(c.AvroSchemaNamespace) {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only contain alphanumeric characters and dots`,
+				codecOPTAvroSchemaNamespace,
+			)
+		}
+
+		switch c.AvroSchemaCompatibility {
+		case "", AvroSchemaCompatibilityBackward, AvroSchemaCompatibilityForward,
+			AvroSchemaCompatibilityFull, AvroSchemaCompatibilityNone:
+		default:
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only be "%s", "%s", "%s" or "%s"`,
+				codecOPTAvroSchemaCompatibility,
+				AvroSchemaCompatibilityBackward,
+				AvroSchemaCompatibilityForward,
+				AvroSchemaCompatibilityFull,
+				AvroSchemaCompatibilityNone,
 			)
 		}
 
@@ -381,6 +553,43 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if err := c.validateSchemaRegistryTLS(); err != nil {
+		return err
+	}
+	if c.SchemaRegistryAPIKey != "" && c.SchemaRegistryUsername != "" {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`"%s" and "%s" can't be specified at the same time`,
+			codecOPTSchemaRegistryAPIKey, codecOPTSchemaRegistryUsername)
+	}
+	if c.SchemaRegistryPassword != "" && c.SchemaRegistryUsername == "" {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`"%s" is required when "%s" is specified`,
+			codecOPTSchemaRegistryUsername, codecOPTSchemaRegistryPassword)
+	}
+
+	if c.SpatialEncoding != "" &&
+		c.SpatialEncoding != SpatialEncodingWKT &&
+		c.SpatialEncoding != SpatialEncodingWKB {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`%s value could only be "%s" or "%s"`,
+			codecOPTSpatialEncoding,
+			SpatialEncodingWKT,
+			SpatialEncodingWKB,
+		)
+	}
+
+	switch c.CharsetConversionMode {
+	case "", CharsetConversionModeAuto, CharsetConversionModeRaw, CharsetConversionModeError:
+	default:
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`%s value could only be "%s", "%s" or "%s"`,
+			codecOPTCharsetConversionMode,
+			CharsetConversionModeAuto,
+			CharsetConversionModeRaw,
+			CharsetConversionModeError,
+		)
+	}
+
 	if c.MaxMessageBytes <= 0 {
 		return cerror.ErrCodecInvalidConfig.Wrap(
 			errors.Errorf("invalid max-message-bytes %d", c.MaxMessageBytes),
@@ -408,11 +617,17 @@ const (
 	SchemaRegistryTypeConfluent = "confluent"
 	// SchemaRegistryTypeGlue is the type of AWS Glue Schema Registry
 	SchemaRegistryTypeGlue = "glue"
+	// SchemaRegistryTypeJSONSchema is the type of schema registry which
+	// stores JSON Schemas, such as Karapace.
+	SchemaRegistryTypeJSONSchema = "json-schema"
 )
 
 // SchemaRegistryType returns the type of schema registry
 func (c *Config) SchemaRegistryType() string {
 	if c.AvroConfluentSchemaRegistry != "" {
+		if c.AvroSchemaRegistryType == SchemaRegistryTypeJSONSchema {
+			return SchemaRegistryTypeJSONSchema
+		}
 		return SchemaRegistryTypeConfluent
 	}
 	if c.AvroGlueSchemaRegistry != nil {
@@ -420,3 +635,62 @@ func (c *Config) SchemaRegistryType() string {
 	}
 	return "unknown"
 }
+
+// SchemaRegistryCredential returns the credential used to connect to the
+// schema registry, it's nil if TLS isn't configured.
+func (c *Config) SchemaRegistryCredential() *security.Credential {
+	if c.SchemaRegistryCA == "" {
+		return nil
+	}
+	return &security.Credential{
+		CAPath:   c.SchemaRegistryCA,
+		CertPath: c.SchemaRegistryCert,
+		KeyPath:  c.SchemaRegistryKey,
+	}
+}
+
+// SchemaRegistryAuthorization returns the value of the Authorization header
+// of requests to the schema registry, it's empty if no authentication is
+// configured.
+func (c *Config) SchemaRegistryAuthorization() string {
+	if c.SchemaRegistryAPIKey != "" {
+		return "Bearer " + c.SchemaRegistryAPIKey
+	}
+	if c.SchemaRegistryUsername != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString(
+			[]byte(c.SchemaRegistryUsername+":"+c.SchemaRegistryPassword))
+	}
+	return ""
+}
+
+func (c *Config) validateSchemaRegistryTLS() error {
+	if (c.SchemaRegistryCert == "") != (c.SchemaRegistryKey == "") {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`"%s" and "%s" must be specified together for mutual TLS`,
+			codecOPTSchemaRegistryCert, codecOPTSchemaRegistryKey)
+	}
+	if c.SchemaRegistryCert != "" && c.SchemaRegistryCA == "" {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`"%s" is required when "%s" and "%s" are specified`,
+			codecOPTSchemaRegistryCA, codecOPTSchemaRegistryCert, codecOPTSchemaRegistryKey)
+	}
+	for _, file := range []struct {
+		option string
+		path   string
+	}{
+		{codecOPTSchemaRegistryCA, c.SchemaRegistryCA},
+		{codecOPTSchemaRegistryCert, c.SchemaRegistryCert},
+		{codecOPTSchemaRegistryKey, c.SchemaRegistryKey},
+	} {
+		if file.path == "" {
+			continue
+		}
+		f, err := os.Open(file.path)
+		if err != nil {
+			return cerror.ErrCodecInvalidConfig.Wrap(
+				errors.Annotatef(err, "%s %s is not readable", file.option, file.path))
+		}
+		_ = f.Close()
+	}
+	return nil
+}
@@ -15,12 +15,15 @@ package common
 
 import (
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/integrity"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -295,6 +298,17 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.NoError(t, err)
 
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&avro-bigint-unsigned-handling-mode=bytes"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, "bytes", c.AvroBigintUnsignedHandlingMode)
+
+	err = c.Validate()
+	require.NoError(t, err)
+
 	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&avro-bigint-unsigned-handling-mode=invalid"
 	sinkURI, err = url.Parse(uri)
 	require.NoError(t, err)
@@ -307,9 +321,83 @@ func TestConfigApplyValidate(t *testing.T) {
 	require.ErrorContains(
 		t,
 		err,
-		`bigint-unsigned-handling-mode value could only be "long" or "string"`,
+		`bigint-unsigned-handling-mode value could only be "long", "string" or "bytes"`,
+	)
+
+	// avro-enum-set-handling-mode
+	c = NewConfig(config.ProtocolAvro)
+	require.Equal(t, "string", c.AvroEnumSetHandlingMode)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&avro-enum-set-handling-mode=native"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, "native", c.AvroEnumSetHandlingMode)
+
+	err = c.Validate()
+	require.NoError(t, err)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&avro-enum-set-handling-mode=invalid"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	err = c.Validate()
+	require.ErrorContains(
+		t,
+		err,
+		`avro-enum-set-handling-mode value could only be "string" or "native"`,
 	)
 
+	// spatial-encoding
+	c = NewConfig(config.ProtocolAvro)
+	require.Equal(t, "", c.SpatialEncoding)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&spatial-encoding=wkt"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, "wkt", c.SpatialEncoding)
+	err = c.Validate()
+	require.NoError(t, err)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&spatial-encoding=geojson"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	err = c.Validate()
+	require.ErrorContains(t, err, `spatial-encoding value could only be "wkt" or "wkb"`)
+
+	// charset-conversion-mode
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.Equal(t, CharsetConversionModeAuto, c.CharsetConversionMode)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=canal-json&charset-conversion-mode=raw"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, CharsetConversionModeRaw, c.CharsetConversionMode)
+	err = c.Validate()
+	require.NoError(t, err)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=canal-json&charset-conversion-mode=latin1"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	err = c.Validate()
+	require.ErrorContains(t, err, `charset-conversion-mode value could only be "auto", "raw" or "error"`)
+
 	// Illegal max-message-bytes.
 	uri = "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&max-message-bytes=a"
 	sinkURI, err = url.Parse(uri)
@@ -513,3 +601,147 @@ func TestConfig4Simple(t *testing.T) {
 	err = codecConfig.Apply(sinkURL, config.GetDefaultReplicaConfig())
 	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
 }
+
+func TestConfigSchemaRegistryTLS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca, cert, key := filepath.Join(dir, "ca.pem"),
+		filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	for _, path := range []string{ca, cert, key} {
+		require.NoError(t, os.WriteFile(path, []byte("pem"), 0o600))
+	}
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.SchemaRegistryCA = util.AddressOf(ca)
+	uri := "kafka://127.0.0.1:9092/abc?protocol=avro&schema-registry=https://127.0.0.1:8081" +
+		"&schema-registry-cert=" + cert + "&schema-registry-key=" + key
+	sinkURL, err := url.Parse(uri)
+	require.NoError(t, err)
+	codecConfig := NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, &security.Credential{
+		CAPath:   ca,
+		CertPath: cert,
+		KeyPath:  key,
+	}, codecConfig.SchemaRegistryCredential())
+
+	// one-way TLS
+	codecConfig.SchemaRegistryCert, codecConfig.SchemaRegistryKey = "", ""
+	require.NoError(t, codecConfig.Validate())
+
+	codecConfig.SchemaRegistryCA = filepath.Join(dir, "not-exist.pem")
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, "schema-registry-ca .*not-exist.pem is not readable", err)
+
+	codecConfig.SchemaRegistryCA, codecConfig.SchemaRegistryCert = ca, cert
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, "must be specified together", err)
+
+	codecConfig.SchemaRegistryCA, codecConfig.SchemaRegistryKey = "", key
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, `"schema-registry-ca" is required`, err)
+
+	codecConfig.SchemaRegistryCert, codecConfig.SchemaRegistryKey = "", ""
+	require.Nil(t, codecConfig.SchemaRegistryCredential())
+}
+
+func TestConfigSchemaRegistryAuthorization(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.SchemaRegistryUsername = util.AddressOf("user")
+	replicaConfig.Sink.SchemaRegistryPassword = util.AddressOf("pass")
+	sinkURL, err := url.Parse(
+		"kafka://127.0.0.1:9092/abc?protocol=avro&schema-registry=http://127.0.0.1:8081")
+	require.NoError(t, err)
+	codecConfig := NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, "Basic dXNlcjpwYXNz", codecConfig.SchemaRegistryAuthorization())
+
+	codecConfig.SchemaRegistryAPIKey = "key"
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, "can't be specified at the same time", err)
+
+	codecConfig.SchemaRegistryUsername = ""
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, `"schema-registry-username" is required`, err)
+
+	codecConfig.SchemaRegistryPassword = ""
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, "Bearer key", codecConfig.SchemaRegistryAuthorization())
+
+	codecConfig.SchemaRegistryAPIKey = ""
+	require.Empty(t, codecConfig.SchemaRegistryAuthorization())
+}
+
+func TestConfigSchemaRegistryType(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURL, err := url.Parse(
+		"kafka://127.0.0.1:9092/abc?protocol=avro&schema-registry=http://127.0.0.1:8081")
+	require.NoError(t, err)
+	codecConfig := NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, SchemaRegistryTypeConfluent, codecConfig.SchemaRegistryType())
+
+	replicaConfig.Sink.SchemaRegistryType = util.AddressOf(SchemaRegistryTypeJSONSchema)
+	codecConfig = NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, SchemaRegistryTypeJSONSchema, codecConfig.SchemaRegistryType())
+
+	sinkURL, err = url.Parse("kafka://127.0.0.1:9092/abc?protocol=avro" +
+		"&schema-registry=http://127.0.0.1:8081&schema-registry-type=protobuf")
+	require.NoError(t, err)
+	codecConfig = NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, "schema-registry-type", err)
+}
+
+func TestConfigAvroSchemaNamespace(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURL, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=avro" +
+		"&schema-registry=http://127.0.0.1:8081&avro-schema-namespace=cf1.prod")
+	require.NoError(t, err)
+	codecConfig := NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, "cf1.prod", codecConfig.AvroSchemaNamespace)
+
+	codecConfig.AvroSchemaNamespace = "cf1-prod"
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, "avro-schema-namespace", err)
+}
+
+func TestConfigAvroSchemaCompatibility(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURL, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=avro" +
+		"&schema-registry=http://127.0.0.1:8081&avro-schema-compatibility=full")
+	require.NoError(t, err)
+	codecConfig := NewConfig(config.ProtocolAvro)
+	require.NoError(t, codecConfig.Apply(sinkURL, replicaConfig))
+	require.NoError(t, codecConfig.Validate())
+	require.Equal(t, AvroSchemaCompatibilityFull, codecConfig.AvroSchemaCompatibility)
+
+	codecConfig.AvroSchemaCompatibility = "FULL_TRANSITIVE"
+	err = codecConfig.Validate()
+	require.ErrorIs(t, err, cerror.ErrCodecInvalidConfig)
+	require.Regexp(t, "avro-schema-compatibility", err)
+}
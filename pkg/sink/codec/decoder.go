@@ -28,7 +28,8 @@ type RowEventDecoder interface {
 	//     2. a bool if the next event is exist
 	//     3. error
 	HasNext() (model.MessageType, bool, error)
-	// NextResolvedEvent returns the next resolved event if exists
+	// NextResolvedEvent returns the next resolved event if exists,
+	// it's also used to decode the resolved ts of the heartbeat event.
 	NextResolvedEvent() (uint64, error)
 	// NextRowChangedEvent returns the next row changed event if exists
 	NextRowChangedEvent() (*model.RowChangedEvent, error)
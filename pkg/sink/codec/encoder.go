@@ -38,6 +38,15 @@ type DDLEventBatchEncoder interface {
 	EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error)
 }
 
+// HeartbeatEventEncoder is implemented by encoders which can encode heartbeat
+// events distinguishable from checkpoint events. For other encoders, heartbeats
+// are encoded as checkpoint events.
+type HeartbeatEventEncoder interface {
+	// EncodeHeartbeatEvent encodes a heartbeat event carrying the resolved ts.
+	// This event will be broadcast to all partitions.
+	EncodeHeartbeatEvent(ts uint64) (*common.Message, error)
+}
+
 // MessageBuilder is an abstraction to build message.
 type MessageBuilder interface {
 	// Build builds the batch and returns the bytes of key and value.
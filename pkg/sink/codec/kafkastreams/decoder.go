@@ -0,0 +1,148 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkastreams
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+)
+
+// ParseTopic returns the schema and table of a changelog topic named by
+// TopicExpression. The storeName is the part after the last '-', so the
+// schema must not contain '-'.
+func ParseTopic(topic string) (schema, table string, err error) {
+	storeName, ok := strings.CutSuffix(topic, changelogSuffix)
+	if ok {
+		storeName = storeName[strings.LastIndex(storeName, "-")+1:]
+		schema, table, ok = strings.Cut(storeName, ".")
+	}
+	if !ok {
+		return "", "", cerror.ErrDecodeFailed.GenWithStackByArgs(
+			"invalid kafka streams changelog topic " + topic)
+	}
+	return schema, table, nil
+}
+
+// Decoder decodes Kafka Streams changelog records of a table.
+// The commit ts is not carried by changelog records, so it's always 0.
+type Decoder struct {
+	config *common.Config
+	schema string
+	table  string
+
+	key   []byte
+	value []byte
+}
+
+// NewDecoder creates a Decoder for the changelog topic of the given table.
+func NewDecoder(config *common.Config, schema, table string) *Decoder {
+	return &Decoder{
+		config: config,
+		schema: schema,
+		table:  table,
+	}
+}
+
+// AddKeyValue implements the RowEventDecoder interface
+func (d *Decoder) AddKeyValue(key, value []byte) error {
+	if d.key != nil {
+		return cerror.ErrDecodeFailed.GenWithStackByArgs(
+			"decoder key already exists, not consumed yet")
+	}
+	if len(key) == 0 {
+		return cerror.ErrDecodeFailed.GenWithStackByArgs("empty key")
+	}
+	d.key = key
+	d.value = value
+	return nil
+}
+
+// HasNext implements the RowEventDecoder interface
+func (d *Decoder) HasNext() (model.MessageType, bool, error) {
+	if d.key == nil {
+		return model.MessageTypeUnknown, false, nil
+	}
+	return model.MessageTypeRow, true, nil
+}
+
+// NextResolvedEvent implements the RowEventDecoder interface
+func (d *Decoder) NextResolvedEvent() (uint64, error) {
+	return 0, cerror.ErrDecodeFailed.GenWithStackByArgs(
+		"resolved event is not supported by kafka streams changelog")
+}
+
+// NextDDLEvent implements the RowEventDecoder interface
+func (d *Decoder) NextDDLEvent() (*model.DDLEvent, error) {
+	return nil, cerror.ErrDecodeFailed.GenWithStackByArgs(
+		"ddl event is not supported by kafka streams changelog")
+}
+
+// NextRowChangedEvent implements the RowEventDecoder interface.
+// A record with null value is decoded as a delete event which only contains
+// the handle key columns, others are decoded as insert events.
+func (d *Decoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if d.key == nil {
+		return nil, cerror.ErrDecodeFailed.GenWithStackByArgs("no row changed event")
+	}
+	key, value := d.key, d.value
+	d.key, d.value = nil, nil
+
+	if value == nil {
+		preCols, err := decodeColumns(key)
+		if err != nil {
+			return nil, err
+		}
+		e := &model.RowChangedEvent{}
+		e.TableInfo = model.BuildTableInfo(d.schema, d.table, preCols,
+			model.GetHandleAndUniqueIndexOffsets4Test(preCols))
+		e.PreColumns = model.Columns2ColumnDatas(preCols, e.TableInfo)
+		return e, nil
+	}
+
+	value, err := common.Decompress(d.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := decodeColumns(value)
+	if err != nil {
+		return nil, err
+	}
+	e := &model.RowChangedEvent{}
+	e.TableInfo = model.BuildTableInfo(d.schema, d.table, cols,
+		model.GetHandleAndUniqueIndexOffsets4Test(cols))
+	e.Columns = model.Columns2ColumnDatas(cols, e.TableInfo)
+	return e, nil
+}
+
+func decodeColumns(data []byte) ([]*model.Column, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var columns map[string]internal.Column
+	if err := decoder.Decode(&columns); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	result := make([]*model.Column, 0, len(columns))
+	for name, column := range columns {
+		column = internal.FormatColumn(column)
+		result = append(result, column.ToRowChangeColumn(name))
+	}
+	internal.SortColumnArrays(result)
+	return result, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkastreams
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+)
+
+// changelogSuffix is the suffix of Kafka Streams changelog topics.
+const changelogSuffix = "-changelog"
+
+// TopicExpression returns the topic expression of the changelog topics
+// following the Kafka Streams convention `<appId>-<storeName>-changelog`,
+// the storeName of each table is `<schema>.<table>`.
+func TopicExpression(appID string) string {
+	return appID + "-{schema}.{table}" + changelogSuffix
+}
+
+// BatchEncoder encodes row changed events into Kafka Streams changelog records.
+// The key is the handle key columns of the row, and the value is the columns
+// of the new row, or null for delete events, which is a tombstone in Kafka.
+type BatchEncoder struct {
+	messages []*common.Message
+	config   *common.Config
+}
+
+// AppendRowChangedEvent implements the RowEventEncoder interface
+func (d *BatchEncoder) AppendRowChangedEvent(
+	_ context.Context,
+	_ string,
+	e *model.RowChangedEvent,
+	callback func(),
+) error {
+	if e.IsDelete() {
+		m, err := d.newMessage(e, e.GetPreColumns(), false, callback)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d.messages = append(d.messages, m)
+		return nil
+	}
+
+	// The old key must be deleted from the state store if the handle key is updated.
+	if e.IsUpdate() {
+		oldKey := encodeColumns(e.GetPreColumns(), true)
+		newKey := encodeColumns(e.GetColumns(), true)
+		if !reflect.DeepEqual(oldKey, newKey) {
+			m, err := d.newMessage(e, e.GetPreColumns(), false, nil)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d.messages = append(d.messages, m)
+		}
+	}
+	m, err := d.newMessage(e, e.GetColumns(), true, callback)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	d.messages = append(d.messages, m)
+	return nil
+}
+
+func (d *BatchEncoder) newMessage(
+	e *model.RowChangedEvent, cols []*model.Column, withValue bool, callback func(),
+) (*common.Message, error) {
+	keyColumns := encodeColumns(cols, true)
+	if len(keyColumns) == 0 {
+		return nil, cerror.ErrEncodeFailed.GenWithStack(
+			"handle key columns not found in table %s", e.TableInfo.TableName)
+	}
+	key, err := json.Marshal(keyColumns)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	var value []byte
+	if withValue {
+		value, err = json.Marshal(encodeColumns(cols, false))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+		}
+		value, err = common.Compress(
+			d.config.ChangefeedID,
+			d.config.LargeMessageHandle.LargeMessageHandleCompression,
+			value,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	m := &common.Message{
+		Key:      key,
+		Value:    value,
+		Ts:       e.CommitTs,
+		Schema:   e.TableInfo.GetSchemaNamePtr(),
+		Table:    e.TableInfo.GetTableNamePtr(),
+		Type:     model.MessageTypeRow,
+		Protocol: config.ProtocolKafkaStreams,
+		Callback: callback,
+	}
+	m.IncRowsCount()
+	return m, nil
+}
+
+// EncodeCheckpointEvent implements the RowEventEncoder interface.
+// Changelog topics only contain records of the state store.
+func (d *BatchEncoder) EncodeCheckpointEvent(_ uint64) (*common.Message, error) {
+	return nil, nil
+}
+
+// EncodeDDLEvent implements the RowEventEncoder interface.
+// Changelog topics only contain records of the state store.
+func (d *BatchEncoder) EncodeDDLEvent(_ *model.DDLEvent) (*common.Message, error) {
+	return nil, nil
+}
+
+// Build implements the RowEventEncoder interface
+func (d *BatchEncoder) Build() []*common.Message {
+	if len(d.messages) == 0 {
+		return nil
+	}
+
+	result := d.messages
+	d.messages = nil
+	return result
+}
+
+func encodeColumns(cols []*model.Column, onlyHandleKeyColumns bool) map[string]internal.Column {
+	result := make(map[string]internal.Column, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		if onlyHandleKeyColumns && !col.Flag.IsHandleKey() {
+			continue
+		}
+		c := internal.Column{}
+		c.FromRowChangeColumn(col)
+		result[col.Name] = c
+	}
+	return result
+}
+
+type batchEncoderBuilder struct {
+	config *common.Config
+}
+
+// NewBatchEncoderBuilder creates a Kafka Streams changelog batchEncoderBuilder.
+func NewBatchEncoderBuilder(config *common.Config) codec.RowEventEncoderBuilder {
+	return &batchEncoderBuilder{config: config}
+}
+
+// Build a `BatchEncoder`
+func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
+	return &BatchEncoder{config: b.config}
+}
+
+// CleanMetrics do nothing
+func (b *batchEncoderBuilder) CleanMetrics() {}
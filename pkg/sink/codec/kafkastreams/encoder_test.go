@@ -0,0 +1,139 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkastreams
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicExpression(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "app-{schema}.{table}-changelog", TopicExpression("app"))
+
+	schema, table, err := ParseTopic("my-app-test.t1-changelog")
+	require.NoError(t, err)
+	require.Equal(t, "test", schema)
+	require.Equal(t, "t1", table)
+
+	_, _, err = ParseTopic("my-app-test.t1")
+	require.Error(t, err)
+	_, _, err = ParseTopic("my-app-t1-changelog")
+	require.Error(t, err)
+}
+
+func TestEncodeDecodeChangelog(t *testing.T) {
+	t.Parallel()
+
+	cfg := common.NewConfig(config.ProtocolKafkaStreams)
+	encoder := NewBatchEncoderBuilder(cfg).Build()
+	decoder := NewDecoder(cfg, "test", "t1")
+
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "name", Type: mysql.TypeVarchar},
+	}
+	tableInfo := model.BuildTableInfo("test", "t1", columns, [][]int{{0}})
+	newRow := func(id int64, name string) []*model.ColumnData {
+		return model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: id},
+			{Name: "name", Value: []byte(name)},
+		}, tableInfo)
+	}
+	events := []*model.RowChangedEvent{
+		// insert
+		{CommitTs: 1, TableInfo: tableInfo, Columns: newRow(1, "a")},
+		// update without changing the handle key
+		{CommitTs: 2, TableInfo: tableInfo, PreColumns: newRow(1, "a"), Columns: newRow(1, "b")},
+		// update the handle key
+		{CommitTs: 3, TableInfo: tableInfo, PreColumns: newRow(1, "b"), Columns: newRow(2, "b")},
+		// delete
+		{CommitTs: 4, TableInfo: tableInfo, PreColumns: newRow(2, "b")},
+	}
+	for _, e := range events {
+		require.NoError(t, encoder.AppendRowChangedEvent(context.Background(), "", e, nil))
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 5)
+
+	expected := []struct {
+		id   int64
+		name string
+		// tombstone is true if the value is null.
+		tombstone bool
+	}{
+		{id: 1, name: "a"},
+		{id: 1, name: "b"},
+		// the tombstone of the old key
+		{id: 1, tombstone: true},
+		{id: 2, name: "b"},
+		{id: 2, tombstone: true},
+	}
+	for i, m := range messages {
+		require.Equal(t, config.ProtocolKafkaStreams, m.Protocol)
+		require.Equal(t, expected[i].tombstone, m.Value == nil)
+
+		require.NoError(t, decoder.AddKeyValue(m.Key, m.Value))
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		decoded, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+		require.Equal(t, "test", decoded.TableInfo.GetSchemaName())
+		require.Equal(t, "t1", decoded.TableInfo.GetTableName())
+		_, hasNext, err = decoder.HasNext()
+		require.NoError(t, err)
+		require.False(t, hasNext)
+
+		if expected[i].tombstone {
+			// The key only contains the handle key columns.
+			require.True(t, decoded.IsDelete())
+			preCols := decoded.GetPreColumns()
+			require.Len(t, preCols, 1)
+			require.Equal(t, "id", preCols[0].Name)
+			require.Equal(t, expected[i].id, preCols[0].Value)
+			continue
+		}
+		require.True(t, decoded.IsInsert())
+		cols := decoded.GetColumns()
+		require.Len(t, cols, 2)
+		require.Equal(t, "id", cols[0].Name)
+		require.True(t, cols[0].Flag.IsHandleKey())
+		require.Equal(t, expected[i].id, cols[0].Value)
+		require.Equal(t, "name", cols[1].Name)
+		require.Equal(t, []byte(expected[i].name), cols[1].Value)
+	}
+}
+
+func TestEncodeWithoutHandleKey(t *testing.T) {
+	t.Parallel()
+
+	encoder := NewBatchEncoderBuilder(common.NewConfig(config.ProtocolKafkaStreams)).Build()
+	columns := []*model.Column{{Name: "a", Type: mysql.TypeLong}}
+	tableInfo := model.BuildTableInfo("test", "t1", columns, nil)
+	e := &model.RowChangedEvent{
+		CommitTs:  1,
+		TableInfo: tableInfo,
+		Columns:   model.Columns2ColumnDatas([]*model.Column{{Name: "a", Value: 1}}, tableInfo),
+	}
+	require.Error(t, encoder.AppendRowChangedEvent(context.Background(), "", e, nil))
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocdf
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+)
+
+// Decoder decodes the messages encoded by BatchEncoder.
+type Decoder struct {
+	envelope *Envelope
+}
+
+// NewDecoder creates a new Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// AddKeyValue implements the RowEventDecoder interface
+func (d *Decoder) AddKeyValue(_, value []byte) error {
+	if d.envelope != nil {
+		return cerror.ErrDecodeFailed.GenWithStackByArgs(
+			"decoder value already exists, not consumed yet")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	envelope := &Envelope{}
+	if err := decoder.Decode(envelope); err != nil {
+		return cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	if envelope.Version != Version {
+		return cerror.ErrDecodeFailed.GenWithStackByArgs(
+			"unsupported ocdf version " + envelope.Version)
+	}
+	if envelope.Source == nil {
+		return cerror.ErrDecodeFailed.GenWithStackByArgs("ocdf source is missing")
+	}
+	if envelope.Op != OpHeartbeat && envelope.Transaction == nil {
+		return cerror.ErrDecodeFailed.GenWithStackByArgs("ocdf transaction is missing")
+	}
+	d.envelope = envelope
+	return nil
+}
+
+// HasNext implements the RowEventDecoder interface
+func (d *Decoder) HasNext() (model.MessageType, bool, error) {
+	if d.envelope == nil {
+		return model.MessageTypeUnknown, false, nil
+	}
+	switch d.envelope.Op {
+	case OpInsert, OpUpdate, OpDelete:
+		return model.MessageTypeRow, true, nil
+	case OpDDL:
+		return model.MessageTypeDDL, true, nil
+	case OpHeartbeat:
+		return model.MessageTypeResolved, true, nil
+	}
+	return model.MessageTypeUnknown, false, cerror.ErrDecodeFailed.GenWithStackByArgs(
+		"unknown ocdf op " + string(d.envelope.Op))
+}
+
+// NextResolvedEvent implements the RowEventDecoder interface
+func (d *Decoder) NextResolvedEvent() (uint64, error) {
+	if d.envelope == nil || d.envelope.Op != OpHeartbeat {
+		return 0, cerror.ErrDecodeFailed.GenWithStackByArgs("no heartbeat event")
+	}
+	envelope := d.envelope
+	d.envelope = nil
+	return envelope.ResolvedTs, nil
+}
+
+// NextDDLEvent implements the RowEventDecoder interface
+func (d *Decoder) NextDDLEvent() (*model.DDLEvent, error) {
+	if d.envelope == nil || d.envelope.Op != OpDDL || d.envelope.DDL == nil {
+		return nil, cerror.ErrDecodeFailed.GenWithStackByArgs("no ddl event")
+	}
+	envelope := d.envelope
+	d.envelope = nil
+
+	e := new(model.DDLEvent)
+	e.TableInfo = new(model.TableInfo)
+	e.StartTs = envelope.Transaction.StartTs
+	e.CommitTs = envelope.Transaction.CommitTs
+	e.TableInfo.TableName = model.TableName{
+		Schema:  envelope.Source.Schema,
+		Table:   envelope.Source.Table,
+		TableID: envelope.Source.TableID,
+	}
+	e.Query = envelope.DDL.Query
+	e.Type = envelope.DDL.Type
+	return e, nil
+}
+
+// NextRowChangedEvent implements the RowEventDecoder interface
+func (d *Decoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if d.envelope == nil {
+		return nil, cerror.ErrDecodeFailed.GenWithStackByArgs("no row changed event")
+	}
+	envelope := d.envelope
+	d.envelope = nil
+	switch envelope.Op {
+	case OpInsert, OpUpdate, OpDelete:
+	default:
+		return nil, cerror.ErrDecodeFailed.GenWithStackByArgs("no row changed event")
+	}
+
+	cols := decodeColumns(envelope.After)
+	preCols := decodeColumns(envelope.Before)
+	tableCols := cols
+	if len(tableCols) == 0 {
+		tableCols = preCols
+	}
+	e := &model.RowChangedEvent{
+		StartTs:         envelope.Transaction.StartTs,
+		CommitTs:        envelope.Transaction.CommitTs,
+		PhysicalTableID: envelope.Source.TableID,
+	}
+	e.TableInfo = model.BuildTableInfo(envelope.Source.Schema, envelope.Source.Table, tableCols,
+		model.GetHandleAndUniqueIndexOffsets4Test(tableCols))
+	e.TableInfo.TableName.TableID = envelope.Source.TableID
+	if len(cols) != 0 {
+		e.Columns = model.Columns2ColumnDatas(cols, e.TableInfo)
+	}
+	if len(preCols) != 0 {
+		e.PreColumns = model.Columns2ColumnDatas(preCols, e.TableInfo)
+	}
+	return e, nil
+}
+
+func decodeColumns(columns map[string]internal.Column) []*model.Column {
+	if len(columns) == 0 {
+		return nil
+	}
+	result := make([]*model.Column, 0, len(columns))
+	for name, column := range columns {
+		column = internal.FormatColumn(column)
+		result = append(result, column.ToRowChangeColumn(name))
+	}
+	internal.SortColumnArrays(result)
+	return result
+}
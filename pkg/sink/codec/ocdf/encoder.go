@@ -0,0 +1,186 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocdf
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// BatchEncoder encodes the events into OCDF envelopes, each message
+// contains one envelope.
+type BatchEncoder struct {
+	messages  []*common.Message
+	config    *common.Config
+	clusterID string
+}
+
+// AppendRowChangedEvent implements the RowEventEncoder interface
+func (d *BatchEncoder) AppendRowChangedEvent(
+	_ context.Context,
+	_ string,
+	e *model.RowChangedEvent,
+	callback func(),
+) error {
+	envelope := d.newEnvelope(e.CommitTs)
+	envelope.Source.Schema = e.TableInfo.GetSchemaName()
+	envelope.Source.Table = e.TableInfo.GetTableName()
+	envelope.Source.TableID = e.PhysicalTableID
+	envelope.Transaction = &Transaction{
+		ID:       strconv.FormatUint(e.CommitTs, 10),
+		StartTs:  e.StartTs,
+		CommitTs: e.CommitTs,
+	}
+	switch {
+	case e.IsInsert():
+		envelope.Op = OpInsert
+		envelope.After = encodeColumns(e.GetColumns())
+	case e.IsUpdate():
+		envelope.Op = OpUpdate
+		envelope.Before = encodeColumns(e.GetPreColumns())
+		envelope.After = encodeColumns(e.GetColumns())
+	default:
+		envelope.Op = OpDelete
+		envelope.Before = encodeColumns(e.GetPreColumns())
+	}
+
+	value, err := d.encode(envelope)
+	if err != nil {
+		return err
+	}
+	m := common.NewMsg(config.ProtocolOCDF, nil, value, e.CommitTs,
+		model.MessageTypeRow, e.TableInfo.GetSchemaNamePtr(), e.TableInfo.GetTableNamePtr())
+	m.Callback = callback
+	m.IncRowsCount()
+	if length := m.Length(); length > d.config.MaxMessageBytes {
+		log.Warn("Single message is too large for ocdf",
+			zap.Int("maxMessageBytes", d.config.MaxMessageBytes),
+			zap.Int("length", length),
+			zap.Any("table", e.TableInfo.TableName))
+		return cerror.ErrMessageTooLarge.GenWithStackByArgs(length)
+	}
+	d.messages = append(d.messages, m)
+	return nil
+}
+
+// EncodeCheckpointEvent implements the RowEventEncoder interface.
+// The checkpoint ts is sent as a heartbeat envelope.
+func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
+	envelope := d.newEnvelope(ts)
+	envelope.Op = OpHeartbeat
+	envelope.ResolvedTs = ts
+	value, err := d.encode(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return common.NewResolvedMsg(config.ProtocolOCDF, nil, value, ts), nil
+}
+
+// EncodeDDLEvent implements the RowEventEncoder interface
+func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error) {
+	envelope := d.newEnvelope(e.CommitTs)
+	envelope.Source.Schema = e.TableInfo.TableName.Schema
+	envelope.Source.Table = e.TableInfo.TableName.Table
+	envelope.Source.TableID = e.TableInfo.TableName.TableID
+	envelope.Transaction = &Transaction{
+		ID:       strconv.FormatUint(e.CommitTs, 10),
+		StartTs:  e.StartTs,
+		CommitTs: e.CommitTs,
+	}
+	envelope.Op = OpDDL
+	envelope.DDL = &DDL{Query: e.Query, Type: e.Type}
+	value, err := d.encode(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return common.NewDDLMsg(config.ProtocolOCDF, nil, value, e), nil
+}
+
+// Build implements the RowEventEncoder interface
+func (d *BatchEncoder) Build() []*common.Message {
+	if len(d.messages) == 0 {
+		return nil
+	}
+
+	result := d.messages
+	d.messages = nil
+	return result
+}
+
+func (d *BatchEncoder) newEnvelope(ts uint64) *Envelope {
+	return &Envelope{
+		Version: Version,
+		Source: &Source{
+			Connector: connector,
+			ClusterID: d.clusterID,
+		},
+		TsMs: oracle.ExtractPhysical(ts),
+	}
+}
+
+func (d *BatchEncoder) encode(envelope *Envelope) ([]byte, error) {
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	return value, nil
+}
+
+func encodeColumns(cols []*model.Column) map[string]internal.Column {
+	result := make(map[string]internal.Column, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		c := internal.Column{}
+		c.FromRowChangeColumn(col)
+		result[col.Name] = c
+	}
+	return result
+}
+
+type batchEncoderBuilder struct {
+	config    *common.Config
+	clusterID string
+}
+
+// NewBatchEncoderBuilder creates an OCDF batchEncoderBuilder.
+func NewBatchEncoderBuilder(config *common.Config, clusterID string) codec.RowEventEncoderBuilder {
+	return &batchEncoderBuilder{
+		config:    config,
+		clusterID: clusterID,
+	}
+}
+
+// Build a `BatchEncoder`
+func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
+	return &BatchEncoder{
+		config:    b.config,
+		clusterID: b.clusterID,
+	}
+}
+
+// CleanMetrics do nothing
+func (b *batchEncoderBuilder) CleanMetrics() {}
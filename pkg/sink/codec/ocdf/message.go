@@ -0,0 +1,78 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocdf
+
+import (
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+)
+
+// Version is the version of the OCDF envelope.
+const Version = "1.0"
+
+// Op is the operation type of an OCDF envelope.
+type Op string
+
+// The operation types defined by OCDF.
+const (
+	OpInsert    Op = "INSERT"
+	OpUpdate    Op = "UPDATE"
+	OpDelete    Op = "DELETE"
+	OpDDL       Op = "DDL"
+	OpHeartbeat Op = "HEARTBEAT"
+)
+
+// connector is the name of the upstream connector in the source.
+const connector = "ticdc"
+
+// Source is the upstream metadata of an event. The schema and table are
+// empty for heartbeat events.
+type Source struct {
+	Connector string `json:"connector"`
+	ClusterID string `json:"cluster_id"`
+	Schema    string `json:"schema,omitempty"`
+	Table     string `json:"table,omitempty"`
+	TableID   int64  `json:"table_id,omitempty"`
+}
+
+// Transaction groups the events committed by the same upstream transaction,
+// the id is the decimal string of the commit ts.
+type Transaction struct {
+	ID       string `json:"id"`
+	StartTs  uint64 `json:"start_ts"`
+	CommitTs uint64 `json:"commit_ts"`
+}
+
+// DDL is the statement of a DDL event.
+type DDL struct {
+	Query string             `json:"query"`
+	Type  timodel.ActionType `json:"type"`
+}
+
+// Envelope is an OCDF change event. The before and after row images are
+// null if they don't exist, e.g. the before image of an insert event.
+// The transaction is null for heartbeat events.
+type Envelope struct {
+	Version     string                     `json:"version"`
+	Source      *Source                    `json:"source"`
+	Transaction *Transaction               `json:"transaction"`
+	Op          Op                         `json:"op"`
+	Before      map[string]internal.Column `json:"before"`
+	After       map[string]internal.Column `json:"after"`
+	DDL         *DDL                       `json:"ddl,omitempty"`
+	// ResolvedTs is the checkpoint ts carried by heartbeat events.
+	ResolvedTs uint64 `json:"resolved_ts,omitempty"`
+	// TsMs is the physical time of the commit ts in milliseconds.
+	TsMs int64 `json:"ts_ms"`
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocdf
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// requireConformance checks the required fields of an OCDF envelope are
+// present and have the expected JSON types.
+func requireConformance(t *testing.T, value []byte, op Op) map[string]interface{} {
+	envelope := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(value, &envelope))
+
+	require.Equal(t, Version, envelope["version"])
+	require.Equal(t, string(op), envelope["op"])
+	require.IsType(t, float64(0), envelope["ts_ms"])
+
+	source, ok := envelope["source"].(map[string]interface{})
+	require.True(t, ok, "source must be an object")
+	require.Equal(t, connector, source["connector"])
+	require.IsType(t, "", source["cluster_id"])
+
+	for _, key := range []string{"transaction", "before", "after"} {
+		require.Contains(t, envelope, key)
+	}
+	if op == OpHeartbeat {
+		require.Nil(t, envelope["transaction"])
+		require.IsType(t, float64(0), envelope["resolved_ts"])
+		return envelope
+	}
+
+	require.IsType(t, "", source["schema"])
+	require.IsType(t, "", source["table"])
+	transaction, ok := envelope["transaction"].(map[string]interface{})
+	require.True(t, ok, "transaction must be an object")
+	require.IsType(t, "", transaction["id"])
+	require.IsType(t, float64(0), transaction["start_ts"])
+	require.IsType(t, float64(0), transaction["commit_ts"])
+
+	switch op {
+	case OpInsert:
+		require.Nil(t, envelope["before"])
+		require.IsType(t, map[string]interface{}{}, envelope["after"])
+	case OpUpdate:
+		require.IsType(t, map[string]interface{}{}, envelope["before"])
+		require.IsType(t, map[string]interface{}{}, envelope["after"])
+	case OpDelete:
+		require.IsType(t, map[string]interface{}{}, envelope["before"])
+		require.Nil(t, envelope["after"])
+	case OpDDL:
+		ddl, ok := envelope["ddl"].(map[string]interface{})
+		require.True(t, ok, "ddl must be an object")
+		require.IsType(t, "", ddl["query"])
+		require.IsType(t, float64(0), ddl["type"])
+	}
+	return envelope
+}
+
+func TestRowChangedEventConformance(t *testing.T) {
+	t.Parallel()
+
+	cfg := common.NewConfig(config.ProtocolOCDF)
+	encoder := NewBatchEncoderBuilder(cfg, "cluster").Build()
+
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "name", Type: mysql.TypeVarchar},
+	}
+	tableInfo := model.BuildTableInfo("test", "t1", columns, [][]int{{0}})
+	newRow := func(id int64, name string) []*model.ColumnData {
+		return model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: id},
+			{Name: "name", Value: []byte(name)},
+		}, tableInfo)
+	}
+	commitTs := oracle.ComposeTS(1700000000000, 0)
+	events := []struct {
+		op    Op
+		event *model.RowChangedEvent
+	}{
+		{OpInsert, &model.RowChangedEvent{
+			StartTs: commitTs - 1, CommitTs: commitTs, TableInfo: tableInfo, Columns: newRow(1, "a"),
+		}},
+		{OpUpdate, &model.RowChangedEvent{
+			StartTs: commitTs - 1, CommitTs: commitTs, TableInfo: tableInfo,
+			PreColumns: newRow(1, "a"), Columns: newRow(1, "b"),
+		}},
+		{OpDelete, &model.RowChangedEvent{
+			StartTs: commitTs - 1, CommitTs: commitTs, TableInfo: tableInfo, PreColumns: newRow(1, "b"),
+		}},
+	}
+	for _, e := range events {
+		require.NoError(t, encoder.AppendRowChangedEvent(context.Background(), "", e.event, nil))
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, len(events))
+
+	decoder := NewDecoder()
+	for i, m := range messages {
+		envelope := requireConformance(t, m.Value, events[i].op)
+		require.Equal(t, float64(1700000000000), envelope["ts_ms"])
+		source := envelope["source"].(map[string]interface{})
+		require.Equal(t, "cluster", source["cluster_id"])
+		require.Equal(t, "test", source["schema"])
+		require.Equal(t, "t1", source["table"])
+
+		require.NoError(t, decoder.AddKeyValue(m.Key, m.Value))
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		decoded, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+		require.Equal(t, commitTs, decoded.CommitTs)
+		require.Equal(t, commitTs-1, decoded.StartTs)
+		require.Equal(t, "test", decoded.TableInfo.GetSchemaName())
+		require.Equal(t, "t1", decoded.TableInfo.GetTableName())
+		require.Equal(t, events[i].event.IsInsert(), decoded.IsInsert())
+		require.Equal(t, events[i].event.IsUpdate(), decoded.IsUpdate())
+		require.Equal(t, events[i].event.IsDelete(), decoded.IsDelete())
+		require.Len(t, decoded.Columns, len(events[i].event.Columns))
+		require.Len(t, decoded.PreColumns, len(events[i].event.PreColumns))
+	}
+}
+
+func TestDDLEventConformance(t *testing.T) {
+	t.Parallel()
+
+	cfg := common.NewConfig(config.ProtocolOCDF)
+	encoder := NewBatchEncoderBuilder(cfg, "cluster").Build()
+
+	ddl := &model.DDLEvent{
+		StartTs:  1,
+		CommitTs: 2,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t1", TableID: 100},
+		},
+		Query: "CREATE TABLE test.t1 (id INT PRIMARY KEY)",
+		Type:  timodel.ActionCreateTable,
+	}
+	m, err := encoder.EncodeDDLEvent(ddl)
+	require.NoError(t, err)
+	requireConformance(t, m.Value, OpDDL)
+
+	decoder := NewDecoder()
+	require.NoError(t, decoder.AddKeyValue(m.Key, m.Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, tp)
+	decoded, err := decoder.NextDDLEvent()
+	require.NoError(t, err)
+	require.Equal(t, ddl.StartTs, decoded.StartTs)
+	require.Equal(t, ddl.CommitTs, decoded.CommitTs)
+	require.Equal(t, ddl.Query, decoded.Query)
+	require.Equal(t, ddl.Type, decoded.Type)
+	require.Equal(t, ddl.TableInfo.TableName, decoded.TableInfo.TableName)
+}
+
+func TestHeartbeatConformance(t *testing.T) {
+	t.Parallel()
+
+	cfg := common.NewConfig(config.ProtocolOCDF)
+	encoder := NewBatchEncoderBuilder(cfg, "cluster").Build()
+
+	m, err := encoder.EncodeCheckpointEvent(123)
+	require.NoError(t, err)
+	requireConformance(t, m.Value, OpHeartbeat)
+
+	decoder := NewDecoder()
+	require.NoError(t, decoder.AddKeyValue(m.Key, m.Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, tp)
+	ts, err := decoder.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, uint64(123), ts)
+}
+
+func TestDecodeInvalidEnvelope(t *testing.T) {
+	t.Parallel()
+
+	decoder := NewDecoder()
+	require.ErrorContains(t, decoder.AddKeyValue(nil, []byte(`{"version":"2.0"}`)),
+		"unsupported ocdf version")
+	require.ErrorContains(t, decoder.AddKeyValue(nil, []byte(`{"version":"1.0"}`)),
+		"ocdf source is missing")
+	require.ErrorContains(t, decoder.AddKeyValue(nil,
+		[]byte(`{"version":"1.0","source":{"connector":"ticdc"},"op":"INSERT"}`)),
+		"ocdf transaction is missing")
+}
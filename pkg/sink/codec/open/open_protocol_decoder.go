@@ -156,8 +156,9 @@ func (b *BatchDecoder) HasNext() (model.MessageType, bool, error) {
 }
 
 // NextResolvedEvent implements the RowEventDecoder interface
+// It also returns the resolved ts carried by the heartbeat event.
 func (b *BatchDecoder) NextResolvedEvent() (uint64, error) {
-	if b.nextKey.Type != model.MessageTypeResolved {
+	if b.nextKey.Type != model.MessageTypeResolved && b.nextKey.Type != model.MessageTypeHeartbeat {
 		return 0, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found resolved event message")
 	}
 	resolvedTs := b.nextKey.Ts
@@ -25,6 +25,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/sink/kafka/claimcheck"
 	"go.uber.org/zap"
 )
@@ -241,11 +242,29 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 
 // EncodeCheckpointEvent implements the RowEventEncoder interface
 func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
-	keyMsg := newResolvedMessage(ts)
-	key, err := keyMsg.Encode()
+	key, value, err := encodeTsMessage(newResolvedMessage(ts))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	return common.NewResolvedMsg(config.ProtocolOpen, key, value, ts), nil
+}
+
+// EncodeHeartbeatEvent implements the HeartbeatEventEncoder interface
+func (d *BatchEncoder) EncodeHeartbeatEvent(ts uint64) (*common.Message, error) {
+	key, value, err := encodeTsMessage(newHeartbeatMessage(ts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewHeartbeatMsg(config.ProtocolOpen, key, value, ts), nil
+}
+
+// encodeTsMessage encodes the message key which only carries a ts,
+// the value part is empty.
+func encodeTsMessage(keyMsg *internal.MessageKey) ([]byte, []byte, error) {
+	key, err := keyMsg.Encode()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 
 	var keyLenByte [8]byte
 	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
@@ -262,8 +281,7 @@ func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 	valueBuf := new(bytes.Buffer)
 	valueBuf.Write(valueLenByte[:])
 
-	ret := common.NewResolvedMsg(config.ProtocolOpen, keyBuf.Bytes(), valueBuf.Bytes(), ts)
-	return ret, nil
+	return keyBuf.Bytes(), valueBuf.Bytes(), nil
 }
 
 // Build implements the RowEventEncoder interface
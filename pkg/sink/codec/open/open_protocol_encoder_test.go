@@ -467,6 +467,39 @@ func TestE2EDDLCompression(t *testing.T) {
 	require.Equal(t, decodedWatermark, waterMark)
 }
 
+func TestE2EHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolOpen)
+	builder, err := NewBatchEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder, ok := builder.Build().(codec.HeartbeatEventEncoder)
+	require.True(t, ok)
+
+	message, err := encoder.EncodeHeartbeatEvent(2333)
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeHeartbeat, message.Type)
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(message.Key, message.Value)
+	require.NoError(t, err)
+
+	messageType, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeHeartbeat, messageType)
+
+	ts, err := decoder.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2333), ts)
+
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
+
 func TestE2EHandleKeyOnlyEvent(t *testing.T) {
 	t.Parallel()
 
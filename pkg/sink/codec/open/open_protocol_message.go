@@ -96,6 +96,13 @@ func newResolvedMessage(ts uint64) *internal.MessageKey {
 	}
 }
 
+func newHeartbeatMessage(ts uint64) *internal.MessageKey {
+	return &internal.MessageKey{
+		Ts:   ts,
+		Type: model.MessageTypeHeartbeat,
+	}
+}
+
 func rowChangeToMsg(
 	e *model.RowChangedEvent,
 	config *common.Config,
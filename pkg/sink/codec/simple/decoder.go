@@ -38,6 +38,8 @@ type Decoder struct {
 
 	upstreamTiDB *sql.DB
 	storage      storage.ExternalStorage
+	// largeValueStorage is used to read the large column values.
+	largeValueStorage storage.ExternalStorage
 
 	value []byte
 	msg   *message
@@ -52,8 +54,9 @@ type Decoder struct {
 // NewDecoder returns a new Decoder
 func NewDecoder(ctx context.Context, config *common.Config, db *sql.DB) (*Decoder, error) {
 	var (
-		externalStorage storage.ExternalStorage
-		err             error
+		externalStorage   storage.ExternalStorage
+		largeValueStorage storage.ExternalStorage
+		err               error
 	)
 	if config.LargeMessageHandle.EnableClaimCheck() {
 		storageURI := config.LargeMessageHandle.ClaimCheckStorageURI
@@ -62,13 +65,20 @@ func NewDecoder(ctx context.Context, config *common.Config, db *sql.DB) (*Decode
 			return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 		}
 	}
+	if config.LargeMessageHandle.EnableLargeValueExternalStorage() {
+		storageURI := config.LargeMessageHandle.LargeValueExternalStorage
+		largeValueStorage, err = util.GetExternalStorageFromURI(ctx, storageURI)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+	}
 
 	if config.LargeMessageHandle.HandleKeyOnly() && db == nil {
 		return nil, cerror.ErrCodecDecode.
 			GenWithStack("handle-key-only is enabled, but upstream TiDB is not provided")
 	}
 
-	m, err := newMarshaller(config)
+	m, err := newMarshaller(config, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -77,8 +87,9 @@ func NewDecoder(ctx context.Context, config *common.Config, db *sql.DB) (*Decode
 		config:     config,
 		marshaller: m,
 
-		storage:      externalStorage,
-		upstreamTiDB: db,
+		storage:           externalStorage,
+		largeValueStorage: largeValueStorage,
+		upstreamTiDB:      db,
 
 		memo:           newMemoryTableInfoProvider(),
 		cachedMessages: list.New(),
@@ -152,6 +163,10 @@ func (d *Decoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 		return d.assembleHandleKeyOnlyRowChangedEvent(d.msg)
 	}
 
+	if err := d.readLargeValues(d.msg); err != nil {
+		return nil, err
+	}
+
 	tableInfo := d.memo.Read(d.msg.Schema, d.msg.Table, d.msg.SchemaVersion)
 	if tableInfo == nil {
 		log.Debug("table info not found for the event, "+
@@ -39,7 +39,7 @@ type encoder struct {
 func (e *encoder) AppendRowChangedEvent(
 	ctx context.Context, _ string, event *model.RowChangedEvent, callback func(),
 ) error {
-	value, err := e.marshaller.MarshalRowChangedEvent(event, false, "")
+	value, err := e.marshaller.MarshalRowChangedEvent(ctx, event, false, "")
 	if err != nil {
 		return err
 	}
@@ -83,7 +83,7 @@ func (e *encoder) AppendRowChangedEvent(
 		}
 	}
 
-	value, err = e.marshaller.MarshalRowChangedEvent(event, true, claimCheckLocation)
+	value, err = e.marshaller.MarshalRowChangedEvent(ctx, event, true, claimCheckLocation)
 	if err != nil {
 		return err
 	}
@@ -161,16 +161,18 @@ func (e *encoder) EncodeDDLEvent(event *model.DDLEvent) (*common.Message, error)
 }
 
 type builder struct {
-	config     *common.Config
-	claimCheck *claimcheck.ClaimCheck
-	marshaller marshaller
+	config            *common.Config
+	claimCheck        *claimcheck.ClaimCheck
+	largeValueStorage *claimcheck.ClaimCheck
+	marshaller        marshaller
 }
 
 // NewBuilder returns a new builder
 func NewBuilder(ctx context.Context, config *common.Config) (*builder, error) {
 	var (
-		claimCheck *claimcheck.ClaimCheck
-		err        error
+		claimCheck        *claimcheck.ClaimCheck
+		largeValueStorage *claimcheck.ClaimCheck
+		err               error
 	)
 	if config.LargeMessageHandle.EnableClaimCheck() {
 		claimCheck, err = claimcheck.New(ctx,
@@ -179,16 +181,24 @@ func NewBuilder(ctx context.Context, config *common.Config) (*builder, error) {
 			return nil, errors.Trace(err)
 		}
 	}
+	if config.LargeMessageHandle.EnableLargeValueExternalStorage() {
+		largeValueStorage, err = claimcheck.New(ctx,
+			config.LargeMessageHandle.LargeValueExternalStorage, config.ChangefeedID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 
-	m, err := newMarshaller(config)
+	m, err := newMarshaller(config, largeValueStorage)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	return &builder{
-		config:     config,
-		claimCheck: claimCheck,
-		marshaller: m,
+		config:            config,
+		claimCheck:        claimCheck,
+		largeValueStorage: largeValueStorage,
+		marshaller:        m,
 	}, nil
 }
 
@@ -207,4 +217,7 @@ func (b *builder) CleanMetrics() {
 	if b.claimCheck != nil {
 		b.claimCheck.CleanMetrics()
 	}
+	if b.largeValueStorage != nil {
+		b.largeValueStorage.CleanMetrics()
+	}
 }
@@ -1386,3 +1386,91 @@ func TestLargeMessageHandleKeyOnly(t *testing.T) {
 		}
 	}
 }
+
+func TestLargeValueExternalStorage(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	sql := `create table test.t(a int primary key, b longblob, c longtext)`
+	ddlEvent := helper.DDL2Event(sql)
+
+	sql = `insert into test.t values (1, 'b', 'c')`
+	row := helper.DML2Event(sql, "test", "t")
+	// replace the value by a 10MB BLOB, which exceeds the max message bytes.
+	largeValue := make([]byte, 10*1024*1024)
+	_, err := rand.Read(largeValue)
+	require.NoError(t, err)
+	for _, col := range row.Columns {
+		if row.TableInfo.ForceGetColumnName(col.ColumnID) == "b" {
+			col.Value = largeValue
+		}
+	}
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolSimple)
+	codecConfig.LargeMessageHandle.LargeValueExternalStorage = "file://" + t.TempDir()
+	codecConfig.LargeMessageHandle.LargeValueThresholdBytes = config.DefaultLargeValueThresholdBytes
+
+	codecConfig.EncodingFormat = common.EncodingFormatAvro
+	_, err = NewBuilder(ctx, codecConfig)
+	require.ErrorIs(t, err, errors.ErrCodecInvalidConfig)
+
+	codecConfig.EncodingFormat = common.EncodingFormatJSON
+	b, err := NewBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	enc := b.Build()
+
+	m, err := enc.EncodeDDLEvent(ddlEvent)
+	require.NoError(t, err)
+
+	dec, err := NewDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+
+	err = dec.AddKeyValue(m.Key, m.Value)
+	require.NoError(t, err)
+	_, _, err = dec.HasNext()
+	require.NoError(t, err)
+	_, err = dec.NextDDLEvent()
+	require.NoError(t, err)
+
+	err = enc.AppendRowChangedEvent(ctx, "", row, func() {})
+	require.NoError(t, err)
+	messages := enc.Build()
+	require.Len(t, messages, 1)
+	require.Less(t, messages[0].Length(), codecConfig.LargeMessageHandle.LargeValueThresholdBytes)
+
+	err = dec.AddKeyValue(messages[0].Key, messages[0].Value)
+	require.NoError(t, err)
+	messageType, hasNext, err := dec.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, messageType)
+	require.Len(t, dec.msg.ExternalData, 1)
+	require.Contains(t, dec.msg.ExternalData, "b")
+	require.Nil(t, dec.msg.Data["b"])
+
+	decodedRow, err := dec.NextRowChangedEvent()
+	require.NoError(t, err)
+	decodedColumns := make(map[string]*model.ColumnData, len(decodedRow.Columns))
+	for _, column := range decodedRow.Columns {
+		colName := decodedRow.TableInfo.ForceGetColumnName(column.ColumnID)
+		decodedColumns[colName] = column
+	}
+	for _, expected := range row.Columns {
+		colName := row.TableInfo.ForceGetColumnName(expected.ColumnID)
+		decoded, ok := decodedColumns[colName]
+		require.True(t, ok)
+		require.EqualValues(t, expected.Value, decoded.Value)
+	}
+
+	// the consumer can't decode the message without the external storage.
+	codecConfig.LargeMessageHandle.LargeValueExternalStorage = ""
+	dec, err = NewDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = dec.AddKeyValue(messages[0].Key, messages[0].Value)
+	require.NoError(t, err)
+	_, _, err = dec.HasNext()
+	require.NoError(t, err)
+	_, err = dec.NextRowChangedEvent()
+	require.ErrorIs(t, err, errors.ErrCodecDecode)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/kafka/claimcheck"
+)
+
+// writeLargeValues sends the column values larger than the threshold to the
+// large value external storage, and replaces them by their locations.
+func (m *jsonMarshaller) writeLargeValues(ctx context.Context, msg *message) error {
+	var err error
+	msg.ExternalData, err = m.writeLargeColumns(ctx, msg.Data)
+	if err != nil {
+		return err
+	}
+	msg.ExternalOld, err = m.writeLargeColumns(ctx, msg.Old)
+	return err
+}
+
+func (m *jsonMarshaller) writeLargeColumns(
+	ctx context.Context, columns map[string]interface{},
+) (map[string]string, error) {
+	var result map[string]string
+	threshold := m.config.LargeMessageHandle.LargeValueThresholdBytes
+	for name, value := range columns {
+		// the large BLOB and TEXT values are all encoded as string.
+		v, ok := value.(string)
+		if !ok || len(v) <= threshold {
+			continue
+		}
+		fileName := claimcheck.NewValueFileName()
+		if err := m.largeValueStorage.WriteValue(ctx, []byte(v), fileName); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[name] = m.largeValueStorage.FileNameWithPrefix(fileName)
+		// keep the column in the map, so that the consumer can tell the
+		// row changed event from others even if all columns are large.
+		columns[name] = nil
+	}
+	return result, nil
+}
+
+// readLargeValues reads the column values sent to the large value external
+// storage back to the message.
+func (d *Decoder) readLargeValues(msg *message) error {
+	if len(msg.ExternalData) == 0 && len(msg.ExternalOld) == 0 {
+		return nil
+	}
+	if d.largeValueStorage == nil {
+		return cerror.ErrCodecDecode.GenWithStack(
+			"the message contains large column values, but large value external storage is not set")
+	}
+	if err := d.readLargeColumns(msg.Data, msg.ExternalData); err != nil {
+		return err
+	}
+	if err := d.readLargeColumns(msg.Old, msg.ExternalOld); err != nil {
+		return err
+	}
+	msg.ExternalData = nil
+	msg.ExternalOld = nil
+	return nil
+}
+
+func (d *Decoder) readLargeColumns(columns map[string]interface{}, locations map[string]string) error {
+	for name, location := range locations {
+		_, fileName := filepath.Split(location)
+		data, err := d.largeValueStorage.ReadFile(context.Background(), fileName)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrCodecDecode, err)
+		}
+		columns[name] = string(data)
+	}
+	return nil
+}
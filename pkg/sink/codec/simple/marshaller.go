@@ -14,6 +14,7 @@
 package simple
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/kafka/claimcheck"
 )
 
 //go:embed message.json
@@ -34,22 +36,33 @@ type marshaller interface {
 	MarshalDDLEvent(event *model.DDLEvent) ([]byte, error)
 
 	// MarshalRowChangedEvent marshals the row changed event into bytes.
-	MarshalRowChangedEvent(event *model.RowChangedEvent,
+	MarshalRowChangedEvent(ctx context.Context, event *model.RowChangedEvent,
 		handleKeyOnly bool, claimCheckFileName string) ([]byte, error)
 
 	// Unmarshal the bytes into the given value.
 	Unmarshal(data []byte, v any) error
 }
 
-func newMarshaller(config *common.Config) (marshaller, error) {
+// newMarshaller creates the marshaller by the encoding format. largeValueStorage
+// is used to send the large column values to the external storage, it's only
+// supported by the json format, and can be nil if not enabled.
+func newMarshaller(
+	config *common.Config, largeValueStorage *claimcheck.ClaimCheck,
+) (marshaller, error) {
 	var (
 		result marshaller
 		err    error
 	)
 	switch config.EncodingFormat {
 	case common.EncodingFormatJSON:
-		result = newJSONMarshaller(config)
+		m := newJSONMarshaller(config)
+		m.largeValueStorage = largeValueStorage
+		result = m
 	case common.EncodingFormatAvro:
+		if largeValueStorage != nil {
+			return nil, errors.ErrCodecInvalidConfig.GenWithStack(
+				"large value external storage is not supported by the avro encoding format")
+		}
 		result, err = newAvroMarshaller(config, string(avroSchemaBytes))
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -62,6 +75,8 @@ func newMarshaller(config *common.Config) (marshaller, error) {
 
 type jsonMarshaller struct {
 	config *common.Config
+
+	largeValueStorage *claimcheck.ClaimCheck
 }
 
 func newJSONMarshaller(config *common.Config) *jsonMarshaller {
@@ -97,10 +112,15 @@ func (m *jsonMarshaller) MarshalDDLEvent(event *model.DDLEvent) ([]byte, error)
 
 // MarshalRowChangedEvent implement the marshaller interface
 func (m *jsonMarshaller) MarshalRowChangedEvent(
-	event *model.RowChangedEvent,
+	ctx context.Context, event *model.RowChangedEvent,
 	handleKeyOnly bool, claimCheckFileName string,
 ) ([]byte, error) {
 	msg := m.newDMLMessage(event, handleKeyOnly, claimCheckFileName)
+	if m.largeValueStorage != nil {
+		if err := m.writeLargeValues(ctx, msg); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	value, err := json.Marshal(msg)
 	if err != nil {
 		return nil, errors.WrapError(errors.ErrEncodeFailed, err)
@@ -161,7 +181,7 @@ func (m *avroMarshaller) MarshalDDLEvent(event *model.DDLEvent) ([]byte, error)
 
 // MarshalRowChangedEvent implement the marshaller interface
 func (m *avroMarshaller) MarshalRowChangedEvent(
-	event *model.RowChangedEvent,
+	_ context.Context, event *model.RowChangedEvent,
 	handleKeyOnly bool, claimCheckFileName string,
 ) ([]byte, error) {
 	msg := m.newDMLMessageMap(event, handleKeyOnly, claimCheckFileName)
@@ -539,6 +539,11 @@ type message struct {
 	ClaimCheckLocation string `json:"claimCheckLocation,omitempty"`
 	// HandleKeyOnly is only for the DML event.
 	HandleKeyOnly bool `json:"handleKeyOnly,omitempty"`
+	// ExternalData and ExternalOld are the locations of the column values sent
+	// to the large value external storage, keyed by the column name. Values of
+	// these columns are null in the Data and Old. They are only for the DML event.
+	ExternalData map[string]string `json:"externalData,omitempty"`
+	ExternalOld  map[string]string `json:"externalOld,omitempty"`
 
 	// E2E checksum related fields, only set when enable checksum functionality.
 	Checksum *checksum `json:"checksum,omitempty"`
@@ -0,0 +1,338 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spatial
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Values of spatial columns are stored in the MySQL internal format,
+// which is a 4-byte little-endian SRID followed by the WKB of the geometry.
+// Only POINT, LINESTRING and POLYGON are supported at the moment.
+
+const sridLength = 4
+
+const (
+	wkbPoint      uint32 = 1
+	wkbLineString uint32 = 2
+	wkbPolygon    uint32 = 3
+)
+
+const (
+	wkbBigEndian    byte = 0
+	wkbLittleEndian byte = 1
+)
+
+var wkbType2Name = map[uint32]string{
+	wkbPoint:      "POINT",
+	wkbLineString: "LINESTRING",
+	wkbPolygon:    "POLYGON",
+}
+
+type point struct {
+	x, y float64
+}
+
+// geometry is a POINT, LINESTRING or POLYGON.
+// A POINT has one ring with one point, a LINESTRING has one ring,
+// and a POLYGON has several rings.
+type geometry struct {
+	tp    uint32
+	rings [][]point
+}
+
+// ToWKT converts the value of a spatial column to WKT.
+func ToWKT(value []byte) (string, error) {
+	_, wkb, err := splitSRID(value)
+	if err != nil {
+		return "", err
+	}
+	g, err := parseWKB(wkb)
+	if err != nil {
+		return "", err
+	}
+	return g.wkt(), nil
+}
+
+// ToWKB converts the value of a spatial column to WKB, the SRID is dropped.
+func ToWKB(value []byte) ([]byte, error) {
+	_, wkb, err := splitSRID(value)
+	if err != nil {
+		return nil, err
+	}
+	// make sure the WKB is valid.
+	if _, err := parseWKB(wkb); err != nil {
+		return nil, err
+	}
+	return wkb, nil
+}
+
+// FromWKT converts WKT to the value of a spatial column with the given SRID.
+func FromWKT(wkt string, srid uint32) ([]byte, error) {
+	g, err := parseWKT(wkt)
+	if err != nil {
+		return nil, err
+	}
+	return g.value(srid), nil
+}
+
+// FromWKB converts WKB to the value of a spatial column with the given SRID.
+func FromWKB(wkb []byte, srid uint32) ([]byte, error) {
+	g, err := parseWKB(wkb)
+	if err != nil {
+		return nil, err
+	}
+	return g.value(srid), nil
+}
+
+func splitSRID(value []byte) (uint32, []byte, error) {
+	if len(value) < sridLength {
+		return 0, nil, cerror.ErrDecodeFailed.GenWithStack(
+			"spatial value is too short, length: %d", len(value))
+	}
+	return binary.LittleEndian.Uint32(value), value[sridLength:], nil
+}
+
+type wkbReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, cerror.ErrDecodeFailed.GenWithStack("unexpected end of wkb")
+	}
+	v := r.order.Uint32(r.data)
+	r.data = r.data[4:]
+	return v, nil
+}
+
+func (r *wkbReader) points() ([]point, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.data)) < uint64(n)*16 {
+		return nil, cerror.ErrDecodeFailed.GenWithStack("unexpected end of wkb")
+	}
+	result := make([]point, 0, n)
+	for i := uint32(0); i < n; i++ {
+		result = append(result, r.point())
+	}
+	return result, nil
+}
+
+func (r *wkbReader) point() point {
+	x := math.Float64frombits(r.order.Uint64(r.data))
+	y := math.Float64frombits(r.order.Uint64(r.data[8:]))
+	r.data = r.data[16:]
+	return point{x: x, y: y}
+}
+
+func parseWKB(wkb []byte) (*geometry, error) {
+	if len(wkb) < 1 {
+		return nil, cerror.ErrDecodeFailed.GenWithStack("unexpected end of wkb")
+	}
+	r := &wkbReader{data: wkb[1:]}
+	switch wkb[0] {
+	case wkbBigEndian:
+		r.order = binary.BigEndian
+	case wkbLittleEndian:
+		r.order = binary.LittleEndian
+	default:
+		return nil, cerror.ErrDecodeFailed.GenWithStack("invalid wkb byte order %d", wkb[0])
+	}
+	tp, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &geometry{tp: tp}
+	switch tp {
+	case wkbPoint:
+		if len(r.data) < 16 {
+			return nil, cerror.ErrDecodeFailed.GenWithStack("unexpected end of wkb")
+		}
+		g.rings = [][]point{{r.point()}}
+	case wkbLineString:
+		points, err := r.points()
+		if err != nil {
+			return nil, err
+		}
+		g.rings = [][]point{points}
+	case wkbPolygon:
+		n, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint32(0); i < n; i++ {
+			points, err := r.points()
+			if err != nil {
+				return nil, err
+			}
+			g.rings = append(g.rings, points)
+		}
+	default:
+		return nil, cerror.ErrDecodeFailed.GenWithStack("unsupported wkb geometry type %d", tp)
+	}
+	if len(r.data) != 0 {
+		return nil, cerror.ErrDecodeFailed.GenWithStack(
+			"unexpected %d bytes at the end of wkb", len(r.data))
+	}
+	return g, nil
+}
+
+func (g *geometry) wkb() []byte {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, wkbLittleEndian)
+	buf = binary.LittleEndian.AppendUint32(buf, g.tp)
+	appendPoints := func(points []point) {
+		for _, p := range points {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.x))
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.y))
+		}
+	}
+	switch g.tp {
+	case wkbPoint:
+		appendPoints(g.rings[0])
+	case wkbLineString:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.rings[0])))
+		appendPoints(g.rings[0])
+	case wkbPolygon:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.rings)))
+		for _, ring := range g.rings {
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(ring)))
+			appendPoints(ring)
+		}
+	}
+	return buf
+}
+
+func (g *geometry) value(srid uint32) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, srid), g.wkb()...)
+}
+
+// wkt returns the WKT of the geometry in the format of `ST_AsText` in MySQL,
+// such as `POLYGON((0 0,1 0,1 1,0 0))`.
+func (g *geometry) wkt() string {
+	var sb strings.Builder
+	sb.WriteString(wkbType2Name[g.tp])
+	writePoints := func(points []point) {
+		for i, p := range points {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(strconv.FormatFloat(p.x, 'g', -1, 64))
+			sb.WriteByte(' ')
+			sb.WriteString(strconv.FormatFloat(p.y, 'g', -1, 64))
+		}
+	}
+	sb.WriteByte('(')
+	switch g.tp {
+	case wkbPoint, wkbLineString:
+		writePoints(g.rings[0])
+	case wkbPolygon:
+		for i, ring := range g.rings {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte('(')
+			writePoints(ring)
+			sb.WriteByte(')')
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+func parseWKT(wkt string) (*geometry, error) {
+	wkt = strings.TrimSpace(wkt)
+	idx := strings.IndexByte(wkt, '(')
+	if idx < 0 || wkt[len(wkt)-1] != ')' {
+		return nil, cerror.ErrDecodeFailed.GenWithStack("invalid wkt %s", wkt)
+	}
+	name := strings.ToUpper(strings.TrimSpace(wkt[:idx]))
+	body := wkt[idx+1 : len(wkt)-1]
+
+	g := &geometry{}
+	switch name {
+	case "POINT":
+		points, err := parseWKTPoints(body)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) != 1 {
+			return nil, cerror.ErrDecodeFailed.GenWithStack("invalid wkt %s", wkt)
+		}
+		g.tp, g.rings = wkbPoint, [][]point{points}
+	case "LINESTRING":
+		points, err := parseWKTPoints(body)
+		if err != nil {
+			return nil, err
+		}
+		g.tp, g.rings = wkbLineString, [][]point{points}
+	case "POLYGON":
+		g.tp = wkbPolygon
+		for len(strings.TrimSpace(body)) > 0 {
+			body = strings.TrimSpace(body)
+			end := strings.IndexByte(body, ')')
+			if body[0] != '(' || end < 0 {
+				return nil, cerror.ErrDecodeFailed.GenWithStack("invalid wkt %s", wkt)
+			}
+			points, err := parseWKTPoints(body[1:end])
+			if err != nil {
+				return nil, err
+			}
+			g.rings = append(g.rings, points)
+			body = strings.TrimSpace(body[end+1:])
+			if len(body) > 0 {
+				if body[0] != ',' {
+					return nil, cerror.ErrDecodeFailed.GenWithStack("invalid wkt %s", wkt)
+				}
+				body = body[1:]
+			}
+		}
+	default:
+		return nil, cerror.ErrDecodeFailed.GenWithStack("unsupported wkt geometry type %s", name)
+	}
+	return g, nil
+}
+
+// parseWKTPoints parses points like `0 0, 1 1`.
+func parseWKTPoints(s string) ([]point, error) {
+	items := strings.Split(s, ",")
+	result := make([]point, 0, len(items))
+	for _, item := range items {
+		fields := strings.Fields(item)
+		if len(fields) != 2 {
+			return nil, cerror.ErrDecodeFailed.GenWithStack("invalid wkt point %s", item)
+		}
+		x, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecodeFailed, errors.Trace(err))
+		}
+		y, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecodeFailed, errors.Trace(err))
+		}
+		result = append(result, point{x: x, y: y})
+	}
+	return result, nil
+}
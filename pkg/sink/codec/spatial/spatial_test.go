@@ -0,0 +1,108 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spatial
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, wkt := range []string{
+		"POINT(1 2)",
+		"POINT(-1.5 1e+30)",
+		"LINESTRING(0 0,1 1,2.25 -3)",
+		"POLYGON((0 0,10 0,10 10,0 10,0 0))",
+		"POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,4 2,4 4,2 2))",
+	} {
+		value, err := FromWKT(wkt, 4326)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0xe6, 0x10, 0, 0}, value[:4])
+
+		actual, err := ToWKT(value)
+		require.NoError(t, err)
+		require.Equal(t, wkt, actual)
+
+		wkb, err := ToWKB(value)
+		require.NoError(t, err)
+		require.Equal(t, value[4:], wkb)
+		decoded, err := FromWKB(wkb, 4326)
+		require.NoError(t, err)
+		require.Equal(t, value, decoded)
+	}
+}
+
+func TestParseWKT(t *testing.T) {
+	t.Parallel()
+
+	value, err := FromWKT(" point ( 1  2 ) ", 0)
+	require.NoError(t, err)
+	wkt, err := ToWKT(value)
+	require.NoError(t, err)
+	require.Equal(t, "POINT(1 2)", wkt)
+
+	value, err = FromWKT("POLYGON ((0 0, 1 0, 1 1, 0 0), (0 0, 1 0, 1 1, 0 0))", 0)
+	require.NoError(t, err)
+	wkt, err = ToWKT(value)
+	require.NoError(t, err)
+	require.Equal(t, "POLYGON((0 0,1 0,1 1,0 0),(0 0,1 0,1 1,0 0))", wkt)
+
+	for _, invalid := range []string{
+		"",
+		"POINT",
+		"POINT(1)",
+		"POINT(1 2,3 4)",
+		"POINT(a b)",
+		"MULTIPOINT(1 2)",
+		"POLYGON(0 0,1 1)",
+		"POLYGON((0 0,1 1) (0 0,1 1))",
+	} {
+		_, err = FromWKT(invalid, 0)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestParseWKB(t *testing.T) {
+	t.Parallel()
+
+	// POINT(1 2) in big-endian WKB, with SRID 0.
+	value, err := hex.DecodeString("00000000" + "00" + "00000001" +
+		"3ff0000000000000" + "4000000000000000")
+	require.NoError(t, err)
+	wkt, err := ToWKT(value)
+	require.NoError(t, err)
+	require.Equal(t, "POINT(1 2)", wkt)
+
+	for _, invalid := range []string{
+		"",
+		"000000",
+		"00000000",
+		"0000000002",
+		"00000000" + "01" + "07000000",
+		"00000000" + "01" + "01000000" + "000000000000f03f",
+		"00000000" + "01" + "02000000" + "02000000" + "000000000000f03f000000000000f03f",
+		"00000000" + "01" + "01000000" + "000000000000f03f000000000000f03f" + "00",
+	} {
+		value, err := hex.DecodeString(invalid)
+		require.NoError(t, err)
+		_, err = ToWKT(value)
+		require.Error(t, err, invalid)
+		_, err = ToWKB(value)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/imdario/mergo"
+	"github.com/olivere/elastic/v7"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// SchemaPlaceholder is replaced by the schema name in the index name template.
+	SchemaPlaceholder = "{schema}"
+	// TablePlaceholder is replaced by the table name in the index name template.
+	TablePlaceholder = "{table}"
+
+	// DefaultIndexNameTemplate is the default index name template.
+	DefaultIndexNameTemplate = SchemaPlaceholder + "." + TablePlaceholder
+	// DefaultBatchSize is the default max number of operations in a bulk request.
+	DefaultBatchSize = 1000
+	// defaultFlushInterval is the default max duration to wait for more
+	// operations before sending a partial bulk request.
+	defaultFlushInterval = time.Second
+	// The upper limit of max worker counts.
+	maxWorkerCount = 256
+	// The upper limit of the batch size. Elasticsearch recommends to keep
+	// bulk requests in a few megabytes.
+	maxBatchSize = 10000
+	// The upper limit of the flush interval.
+	maxFlushInterval = time.Minute
+)
+
+type urlConfig struct {
+	WorkerCount   *int    `form:"worker-count"`
+	BatchSize     *int    `form:"batch-size"`
+	FlushInterval *string `form:"flush-interval"`
+	EnableTLS     *bool   `form:"enable-tls"`
+}
+
+// Config is the configs for Elasticsearch sink.
+type Config struct {
+	// Endpoints are the URLs of the Elasticsearch nodes.
+	Endpoints []string
+	Username  string
+	Password  string
+
+	WorkerCount       int
+	BatchSize         int
+	FlushInterval     time.Duration
+	IndexNameTemplate string
+}
+
+// NewConfig returns the default Elasticsearch sink config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount:       sink.DefaultWorkerCount,
+		BatchSize:         DefaultBatchSize,
+		FlushInterval:     defaultFlushInterval,
+		IndexNameTemplate: DefaultIndexNameTemplate,
+	}
+}
+
+// Apply applies the sink URI parameters and the replica config to the
+// config, the parameters in the sink URI take precedence.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) (err error) {
+	if sinkURI == nil {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"fail to open Elasticsearch sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsElasticsearchScheme(scheme) {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"can't create Elasticsearch sink with unsupported scheme: %s", scheme)
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig, err)
+	}
+	if urlParameter, err = mergeConfig(replicaConfig, urlParameter); err != nil {
+		return err
+	}
+
+	httpScheme := "http"
+	if urlParameter.EnableTLS != nil && *urlParameter.EnableTLS {
+		httpScheme = "https"
+	}
+	c.Endpoints = c.Endpoints[:0]
+	for _, host := range strings.Split(sinkURI.Host, ",") {
+		c.Endpoints = append(c.Endpoints, httpScheme+"://"+host)
+	}
+	if sinkURI.User != nil {
+		c.Username = sinkURI.User.Username()
+		c.Password, _ = sinkURI.User.Password()
+	}
+
+	if urlParameter.WorkerCount != nil {
+		workerCount := *urlParameter.WorkerCount
+		if workerCount <= 0 {
+			return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", workerCount))
+		}
+		if workerCount > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", workerCount), zap.Int("override", maxWorkerCount))
+			workerCount = maxWorkerCount
+		}
+		c.WorkerCount = workerCount
+	}
+	if urlParameter.BatchSize != nil {
+		batchSize := *urlParameter.BatchSize
+		if batchSize <= 0 {
+			return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig,
+				fmt.Errorf("invalid batch-size %d, which must be greater than 0", batchSize))
+		}
+		if batchSize > maxBatchSize {
+			log.Warn("batch-size too large",
+				zap.Int("original", batchSize), zap.Int("override", maxBatchSize))
+			batchSize = maxBatchSize
+		}
+		c.BatchSize = batchSize
+	}
+	if urlParameter.FlushInterval != nil && len(*urlParameter.FlushInterval) > 0 {
+		interval, err := time.ParseDuration(*urlParameter.FlushInterval)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig, err)
+		}
+		if interval <= 0 {
+			return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig,
+				fmt.Errorf("invalid flush-interval %s, which must be greater than 0", interval))
+		}
+		if interval > maxFlushInterval {
+			log.Warn("flush-interval too large",
+				zap.Duration("original", interval), zap.Duration("override", maxFlushInterval))
+			interval = maxFlushInterval
+		}
+		c.FlushInterval = interval
+	}
+
+	if replicaConfig.Sink != nil && replicaConfig.Sink.ElasticsearchConfig != nil &&
+		replicaConfig.Sink.ElasticsearchConfig.IndexNameTemplate != nil {
+		template := strings.TrimSpace(*replicaConfig.Sink.ElasticsearchConfig.IndexNameTemplate)
+		// The documents of different tables must not be put into the same
+		// index, or they may overwrite each other.
+		if !strings.Contains(template, TablePlaceholder) {
+			return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+				"index-name-template %s must contain %s", template, TablePlaceholder)
+		}
+		c.IndexNameTemplate = template
+	}
+	return nil
+}
+
+func mergeConfig(
+	replicaConfig *config.ReplicaConfig,
+	urlParameters *urlConfig,
+) (*urlConfig, error) {
+	dest := &urlConfig{}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.ElasticsearchConfig != nil {
+		dest.BatchSize = replicaConfig.Sink.ElasticsearchConfig.BatchSize
+		dest.FlushInterval = replicaConfig.Sink.ElasticsearchConfig.FlushInterval
+	}
+	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
+		return nil, cerror.WrapError(cerror.ErrElasticsearchInvalidConfig, err)
+	}
+	return dest, nil
+}
+
+// IndexName returns the name of the index which the documents of a table
+// are written to. Index names of Elasticsearch must be lowercase.
+func (c *Config) IndexName(schema, table string) string {
+	name := strings.ReplaceAll(c.IndexNameTemplate, SchemaPlaceholder, schema)
+	name = strings.ReplaceAll(name, TablePlaceholder, table)
+	return strings.ToLower(name)
+}
+
+// Factory is the factory for creating Elasticsearch clients.
+type Factory func(ctx context.Context, cfg *Config) (*elastic.Client, error)
+
+// CreateClient creates an Elasticsearch client with the given config.
+func CreateClient(ctx context.Context, cfg *Config) (*elastic.Client, error) {
+	options := []elastic.ClientOptionFunc{
+		elastic.SetURL(cfg.Endpoints...),
+		// The nodes may be behind a load balancer or in a container network,
+		// whose published addresses are unreachable from TiCDC.
+		elastic.SetSniff(false),
+	}
+	if cfg.Username != "" {
+		options = append(options, elastic.SetBasicAuth(cfg.Username, cfg.Password))
+	}
+	client, err := elastic.DialContext(ctx, options...)
+	if err != nil {
+		return nil, cerror.ErrElasticsearchConnectionError.Wrap(err).GenWithStack(
+			"fail to connect to Elasticsearch")
+	}
+	return client, nil
+}
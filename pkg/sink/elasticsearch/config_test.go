@@ -0,0 +1,65 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.ElasticsearchConfig = &config.ElasticsearchConfig{
+		IndexNameTemplate: util.AddressOf("tidb-{schema}-{table}"),
+		BatchSize:         util.AddressOf(500),
+		FlushInterval:     util.AddressOf("2s"),
+	}
+
+	sinkURI, err := url.Parse("es://elastic:pass@127.0.0.1:9200,127.0.0.2:9200/?batch-size=200&enable-tls=true")
+	require.NoError(t, err)
+	cfg := NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI, replicaConfig))
+	require.Equal(t, []string{"https://127.0.0.1:9200", "https://127.0.0.2:9200"}, cfg.Endpoints)
+	require.Equal(t, "elastic", cfg.Username)
+	require.Equal(t, "pass", cfg.Password)
+	// The sink URI takes precedence over the replica config.
+	require.Equal(t, 200, cfg.BatchSize)
+	require.Equal(t, 2*time.Second, cfg.FlushInterval)
+	require.Equal(t, sink.DefaultWorkerCount, cfg.WorkerCount)
+	require.Equal(t, "tidb-test-orders", cfg.IndexName("Test", "Orders"))
+
+	sinkURI, err = url.Parse("elasticsearch://127.0.0.1:9200")
+	require.NoError(t, err)
+	cfg = NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI, config.GetDefaultReplicaConfig()))
+	require.Equal(t, []string{"http://127.0.0.1:9200"}, cfg.Endpoints)
+	require.Equal(t, DefaultBatchSize, cfg.BatchSize)
+	require.Equal(t, "test.t", cfg.IndexName("test", "t"))
+
+	replicaConfig.Sink.ElasticsearchConfig.IndexNameTemplate = util.AddressOf("{schema}")
+	require.ErrorContains(t, NewConfig().Apply(sinkURI, replicaConfig), "must contain {table}")
+
+	sinkURI, err = url.Parse("es://127.0.0.1:9200/?batch-size=0")
+	require.NoError(t, err)
+	require.ErrorContains(t, NewConfig().Apply(sinkURI, config.GetDefaultReplicaConfig()),
+		"invalid batch-size 0")
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// DocumentID returns the ID of the document of a row, which is the hash of
+// the values of its key columns. The values must be in the same order for
+// the same table.
+func DocumentID(keyValues []string) string {
+	// Encode the values as a JSON array so that different values can't be
+	// concatenated to the same bytes.
+	data, _ := json.Marshal(keyValues)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ConvertValue converts a column value of a RowChangedEvent to the value of
+// a document field, which is mapped to a proper field type by the dynamic
+// mapping of Elasticsearch.
+func ConvertValue(ft *types.FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch ft.GetType() {
+	case mysql.TypeNewDecimal:
+		if v, ok := value.(string); ok {
+			// Keep the decimal as a JSON number without losing precision
+			// in the document source.
+			return json.Number(v), nil
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		if v, ok := value.(string); ok {
+			// Elasticsearch rejects the zero dates of MySQL.
+			if strings.HasPrefix(v, "0000-00-00") {
+				return nil, nil
+			}
+			// Use the ISO 8601 format, which is detected as a date field.
+			return strings.Replace(v, " ", "T", 1), nil
+		}
+	case mysql.TypeJSON:
+		if v, ok := value.(string); ok {
+			return json.RawMessage(v), nil
+		}
+	case mysql.TypeEnum:
+		if v, ok := value.(uint64); ok {
+			enum, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return enum.Name, nil
+		}
+	case mysql.TypeSet:
+		if v, ok := value.(uint64); ok {
+			set, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return set.Name, nil
+		}
+	}
+	// Binary values are encoded in base64 by encoding/json, as expected by
+	// the binary field type.
+	if v, ok := value.([]byte); ok && ft.GetCharset() != charset.CharsetBin {
+		return string(v), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentID(t *testing.T) {
+	t.Parallel()
+
+	id := DocumentID([]string{"1", "a"})
+	require.Len(t, id, 64)
+	require.Equal(t, id, DocumentID([]string{"1", "a"}))
+	require.NotEqual(t, id, DocumentID([]string{"1a"}))
+	require.NotEqual(t, id, DocumentID([]string{"1", "b"}))
+}
+
+func TestConvertValue(t *testing.T) {
+	t.Parallel()
+
+	value, err := ConvertValue(types.NewFieldType(mysql.TypeNewDecimal), "123.45")
+	require.NoError(t, err)
+	require.Equal(t, json.Number("123.45"), value)
+
+	value, err = ConvertValue(types.NewFieldType(mysql.TypeDatetime), "2024-01-02 03:04:05.123")
+	require.NoError(t, err)
+	require.Equal(t, "2024-01-02T03:04:05.123", value)
+
+	value, err = ConvertValue(types.NewFieldType(mysql.TypeDate), "0000-00-00")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	value, err = ConvertValue(types.NewFieldType(mysql.TypeJSON), `{"a": 1}`)
+	require.NoError(t, err)
+	doc, err := json.Marshal(map[string]interface{}{"j": value})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"j": {"a": 1}}`, string(doc))
+
+	enum := types.NewFieldType(mysql.TypeEnum)
+	enum.SetElems([]string{"a", "b"})
+	value, err = ConvertValue(enum, uint64(2))
+	require.NoError(t, err)
+	require.Equal(t, "b", value)
+
+	text := types.NewFieldType(mysql.TypeBlob)
+	text.SetCharset(charset.CharsetUTF8MB4)
+	value, err = ConvertValue(text, []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", value)
+
+	blob := types.NewFieldType(mysql.TypeBlob)
+	blob.SetCharset(charset.CharsetBin)
+	value, err = ConvertValue(blob, []byte{0x1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1}, value)
+}
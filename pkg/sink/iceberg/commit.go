@@ -0,0 +1,144 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	gcs "cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// errFileExists is returned by createFile if the file already exists.
+var errFileExists = cerror.New("file already exists")
+
+// createFileFunc writes the file only if it doesn't exist, errFileExists is
+// returned otherwise. It's the atomic operation to commit a new version of
+// the metadata of a table.
+type createFileFunc func(ctx context.Context, name string, data []byte) error
+
+// newCreateFileFunc returns the createFileFunc of the external storage, an
+// error is returned if the storage doesn't support to create files atomically.
+func newCreateFileFunc(s storage.ExternalStorage) (createFileFunc, error) {
+	switch s := s.(type) {
+	case *storage.LocalStorage:
+		base := strings.TrimPrefix(s.URI(), storage.LocalURIPrefix)
+		return func(_ context.Context, name string, data []byte) error {
+			return createLocalFile(filepath.Join(base, name), data)
+		}, nil
+	case *storage.S3Storage:
+		return func(ctx context.Context, name string, data []byte) error {
+			return createS3File(ctx, s, name, data)
+		}, nil
+	case *storage.GCSStorage:
+		return func(ctx context.Context, name string, data []byte) error {
+			return createGCSFile(ctx, s, name, data)
+		}, nil
+	default:
+		return nil, cerror.ErrIcebergInvalidConfig.GenWithStack(
+			"storage %s doesn't support conditional writes, which are required "+
+				"to commit the Iceberg tables atomically", s.URI())
+	}
+}
+
+// createLocalFile writes the data to a temp file and then links it to the
+// path, the link fails if the path already exists.
+func createLocalFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return cerror.Trace(err)
+	}
+	tmpPath := name + ".tmp." + uuid.NewString()
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return cerror.Trace(err)
+	}
+	defer os.Remove(tmpPath)
+	if err := os.Link(tmpPath, name); err != nil {
+		if os.IsExist(err) {
+			return errFileExists
+		}
+		return cerror.Trace(err)
+	}
+	return nil
+}
+
+// createS3File puts the object with `If-None-Match: *`, so the put fails if
+// the object exists. The S3 compatible storages which ignore the header can
+// not prevent the concurrent commits.
+func createS3File(ctx context.Context, s *storage.S3Storage, name string, data []byte) error {
+	options := s.GetOptions()
+	input := &s3.PutObjectInput{
+		Body:   aws.ReadSeekCloser(bytes.NewReader(data)),
+		Bucket: aws.String(options.Bucket),
+		Key:    aws.String(options.Prefix + name),
+	}
+	if options.Acl != "" {
+		input = input.SetACL(options.Acl)
+	}
+	if options.Sse != "" {
+		input = input.SetServerSideEncryption(options.Sse)
+	}
+	if options.SseKmsKeyId != "" {
+		input = input.SetSSEKMSKeyId(options.SseKmsKeyId)
+	}
+	if options.StorageClass != "" {
+		input = input.SetStorageClass(options.StorageClass)
+	}
+	_, err := s.GetS3APIHandle().PutObjectWithContext(ctx, input,
+		request.WithSetRequestHeaders(map[string]string{"If-None-Match": "*"}))
+	if err != nil {
+		var reqErr awserr.RequestFailure
+		if errors.As(err, &reqErr) && (reqErr.StatusCode() == http.StatusPreconditionFailed ||
+			reqErr.StatusCode() == http.StatusConflict) {
+			return errFileExists
+		}
+		return cerror.Trace(err)
+	}
+	return nil
+}
+
+// createGCSFile writes the object with the precondition that it doesn't exist.
+func createGCSFile(ctx context.Context, s *storage.GCSStorage, name string, data []byte) error {
+	options := s.GetOptions()
+	object := s.GetBucketHandle().Object(path.Join(options.Prefix, name)).
+		If(gcs.Conditions{DoesNotExist: true})
+	wc := object.NewWriter(ctx)
+	wc.StorageClass = options.StorageClass
+	wc.PredefinedACL = options.PredefinedAcl
+	_, err := wc.Write(data)
+	if err == nil {
+		err = wc.Close()
+	}
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			return errFileExists
+		}
+		return cerror.Trace(err)
+	}
+	return nil
+}
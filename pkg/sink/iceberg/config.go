@@ -0,0 +1,154 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	filter "github.com/pingcap/tidb/pkg/util/table-filter"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultStorage is the default scheme of the external storage of the
+	// warehouse.
+	DefaultStorage = sink.S3Scheme
+	// The upper limit of max worker counts.
+	maxWorkerCount = 64
+)
+
+type urlConfig struct {
+	WorkerCount *int    `form:"worker-count"`
+	Storage     *string `form:"storage"`
+}
+
+// Config is the configs for Iceberg sink.
+type Config struct {
+	// StorageURI is the URI of the external storage where the warehouse is
+	// located, e.g. `s3://bucket/prefix?endpoint=...`.
+	StorageURI  string
+	WorkerCount int
+
+	partitionRules []*partitionRule
+}
+
+type partitionRule struct {
+	matcher filter.Filter
+	fields  []string
+}
+
+// NewConfig returns the default Iceberg sink config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount: sink.DefaultWorkerCount,
+	}
+}
+
+// Apply applies the sink URI parameters and the replica config to the config.
+// The sink URI is like `iceberg://bucket/prefix?storage=s3&endpoint=...`,
+// the parameters except the ones of the Iceberg sink are passed to the
+// external storage.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrIcebergInvalidConfig.GenWithStack("fail to open Iceberg sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsIcebergScheme(scheme) {
+		return cerror.ErrIcebergInvalidConfig.GenWithStack(
+			"can't create Iceberg sink with unsupported scheme: %s", scheme)
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrIcebergInvalidConfig, err)
+	}
+
+	storage := DefaultStorage
+	if urlParameter.Storage != nil {
+		storage = strings.ToLower(*urlParameter.Storage)
+	}
+	if !sink.IsStorageScheme(storage) || storage == sink.CloudStorageNoopScheme {
+		return cerror.ErrIcebergInvalidConfig.GenWithStack(
+			"unsupported storage %s of the warehouse", storage)
+	}
+	query := sinkURI.Query()
+	query.Del("worker-count")
+	query.Del("storage")
+	storageURI := url.URL{
+		Scheme:   storage,
+		User:     sinkURI.User,
+		Host:     sinkURI.Host,
+		Path:     sinkURI.Path,
+		RawQuery: query.Encode(),
+	}
+	c.StorageURI = storageURI.String()
+
+	if urlParameter.WorkerCount != nil {
+		workerCount := *urlParameter.WorkerCount
+		if workerCount <= 0 {
+			return cerror.WrapError(cerror.ErrIcebergInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", workerCount))
+		}
+		if workerCount > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", workerCount), zap.Int("override", maxWorkerCount))
+			workerCount = maxWorkerCount
+		}
+		c.WorkerCount = workerCount
+	}
+
+	if replicaConfig == nil || replicaConfig.Sink == nil {
+		return nil
+	}
+	for _, rule := range replicaConfig.Sink.IcebergPartitionBy {
+		matcher, err := filter.Parse(rule.Matcher)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrIcebergInvalidConfig, err)
+		}
+		if !replicaConfig.CaseSensitive {
+			matcher = filter.CaseInsensitive(matcher)
+		}
+		for _, field := range rule.Fields {
+			if _, _, err := parsePartitionField(field); err != nil {
+				return err
+			}
+		}
+		c.partitionRules = append(c.partitionRules, &partitionRule{
+			matcher: matcher,
+			fields:  rule.Fields,
+		})
+	}
+	return nil
+}
+
+// PartitionFields returns the partition fields of a table, which are from
+// the first matched rule of `iceberg-partition-by`. Nil is returned if the
+// table is not partitioned.
+func (c *Config) PartitionFields(schema, table string) []string {
+	for _, rule := range c.partitionRules {
+		if rule.matcher.MatchTable(schema, table) {
+			return rule.fields
+		}
+	}
+	return nil
+}
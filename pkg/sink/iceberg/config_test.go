@@ -0,0 +1,67 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("iceberg://bucket/warehouse?endpoint=http://127.0.0.1:9000&worker-count=8")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.IcebergPartitionBy = []*config.IcebergPartitionRule{
+		{Matcher: []string{"test.orders*"}, Fields: []string{"day(created_at)", "bucket(16, id)"}},
+		{Matcher: []string{"test.*"}, Fields: []string{"id"}},
+	}
+	cfg := NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI, replicaConfig))
+	require.Equal(t, "s3://bucket/warehouse?endpoint=http%3A%2F%2F127.0.0.1%3A9000", cfg.StorageURI)
+	require.Equal(t, 8, cfg.WorkerCount)
+	require.Equal(t, []string{"day(created_at)", "bucket(16, id)"}, cfg.PartitionFields("test", "Orders_1"))
+	require.Equal(t, []string{"id"}, cfg.PartitionFields("test", "t"))
+	require.Nil(t, cfg.PartitionFields("test2", "t"))
+
+	sinkURI, err = url.Parse("iceberg:///tmp/warehouse?storage=file&worker-count=1000")
+	require.NoError(t, err)
+	cfg = NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI, nil))
+	require.Equal(t, "file:///tmp/warehouse", cfg.StorageURI)
+	require.Equal(t, maxWorkerCount, cfg.WorkerCount)
+
+	cases := map[string]string{
+		"s3://bucket/warehouse":                     "unsupported scheme",
+		"iceberg://bucket/warehouse?storage=noop":   "unsupported storage",
+		"iceberg://bucket/warehouse?storage=kafka":  "unsupported storage",
+		"iceberg://bucket/warehouse?worker-count=0": "invalid worker-count",
+	}
+	for uri, expected := range cases {
+		sinkURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.ErrorContains(t, NewConfig().Apply(sinkURI, nil), expected, uri)
+	}
+
+	replicaConfig.Sink.IcebergPartitionBy = []*config.IcebergPartitionRule{
+		{Matcher: []string{"test.*"}, Fields: []string{"bucket(id)"}},
+	}
+	sinkURI, err = url.Parse("iceberg://bucket/warehouse")
+	require.NoError(t, err)
+	require.ErrorContains(t, NewConfig().Apply(sinkURI, replicaConfig), "invalid partition field")
+}
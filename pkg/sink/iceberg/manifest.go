@@ -0,0 +1,439 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/errors"
+)
+
+// The content types of the data files.
+const (
+	ContentData            = 0
+	ContentPositionDeletes = 1
+	ContentEqualityDeletes = 2
+)
+
+// The content types of the manifests.
+const (
+	manifestContentData    = 0
+	manifestContentDeletes = 1
+)
+
+// The status of the manifest entries.
+const (
+	entryStatusExisting = 0
+	entryStatusAdded    = 1
+)
+
+const manifestListSchema = `{
+  "type": "record",
+  "name": "manifest_file",
+  "fields": [
+    {"name": "manifest_path", "type": "string", "field-id": 500},
+    {"name": "manifest_length", "type": "long", "field-id": 501},
+    {"name": "partition_spec_id", "type": "int", "field-id": 502},
+    {"name": "content", "type": "int", "field-id": 517},
+    {"name": "sequence_number", "type": "long", "field-id": 515},
+    {"name": "min_sequence_number", "type": "long", "field-id": 516},
+    {"name": "added_snapshot_id", "type": "long", "field-id": 503},
+    {"name": "added_files_count", "type": "int", "field-id": 504},
+    {"name": "existing_files_count", "type": "int", "field-id": 505},
+    {"name": "deleted_files_count", "type": "int", "field-id": 506},
+    {"name": "added_rows_count", "type": "long", "field-id": 512},
+    {"name": "existing_rows_count", "type": "long", "field-id": 513},
+    {"name": "deleted_rows_count", "type": "long", "field-id": 514}
+  ]
+}`
+
+// DataFile is a data file or a delete file of a table.
+type DataFile struct {
+	Content     int
+	Path        string
+	RecordCount int64
+	FileSize    int64
+	// Partition are the partition values in the order of the partition spec.
+	Partition []interface{}
+	// EqualityIDs are the field IDs of the equality delete files.
+	EqualityIDs []int
+	// SequenceNumber is the data sequence number of the file, it's assigned
+	// when the file is committed.
+	SequenceNumber int64
+}
+
+// ManifestFile is an entry of a manifest list.
+type ManifestFile struct {
+	Path               string
+	Length             int64
+	SpecID             int
+	Content            int
+	SequenceNumber     int64
+	MinSequenceNumber  int64
+	AddedSnapshotID    int64
+	AddedFilesCount    int32
+	ExistingFilesCount int32
+	DeletedFilesCount  int32
+	AddedRowsCount     int64
+	ExistingRowsCount  int64
+	DeletedRowsCount   int64
+}
+
+// manifestEntrySchema returns the Avro schema of the manifest entries of
+// the partition spec.
+func manifestEntrySchema(spec *PartitionSpec, schema *Schema) (string, error) {
+	partitionFields := make([]interface{}, 0, len(spec.Fields))
+	for _, field := range spec.Fields {
+		source, ok := schema.Field(field.SourceID)
+		if !ok {
+			return "", errors.Errorf("source field %d of partition field %s not found",
+				field.SourceID, field.Name)
+		}
+		tp, err := resultType(field.Transform, source.Type)
+		if err != nil {
+			return "", err
+		}
+		partitionFields = append(partitionFields, map[string]interface{}{
+			"name":     avroName(field.Name),
+			"type":     []interface{}{"null", avroType(tp)},
+			"default":  nil,
+			"field-id": field.FieldID,
+		})
+	}
+	optional := func(name string, tp interface{}, id int) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name, "type": []interface{}{"null", tp}, "default": nil, "field-id": id,
+		}
+	}
+	required := func(name string, tp interface{}, id int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "type": tp, "field-id": id}
+	}
+	dataFile := map[string]interface{}{
+		"type": "record",
+		"name": "r2",
+		"fields": []interface{}{
+			required("content", "int", 134),
+			required("file_path", "string", 100),
+			required("file_format", "string", 101),
+			required("partition", map[string]interface{}{
+				"type": "record", "name": "r102", "fields": partitionFields,
+			}, 102),
+			required("record_count", "long", 103),
+			required("file_size_in_bytes", "long", 104),
+			optional("equality_ids", map[string]interface{}{
+				"type": "array", "items": "int", "element-id": 136,
+			}, 135),
+		},
+	}
+	entry := map[string]interface{}{
+		"type": "record",
+		"name": "manifest_entry",
+		"fields": []interface{}{
+			required("status", "int", 0),
+			optional("snapshot_id", "long", 1),
+			optional("sequence_number", "long", 3),
+			optional("file_sequence_number", "long", 4),
+			required("data_file", dataFile, 2),
+		},
+	}
+	data, err := json.Marshal(entry)
+	return string(data), errors.Trace(err)
+}
+
+// avroType returns the Avro type of an Iceberg type of partition values.
+func avroType(tp string) string {
+	switch tp {
+	case TypeInt, TypeDate:
+		return "int"
+	case TypeLong, TypeTimestamp:
+		return "long"
+	case TypeFloat, TypeDouble, TypeString:
+		return tp
+	default:
+		return "bytes"
+	}
+}
+
+// avroName makes a name compatible with Avro in the same way as Iceberg.
+func avroName(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		valid := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(i > 0 && r >= '0' && r <= '9')
+		switch {
+		case valid:
+			builder.WriteRune(r)
+		case i == 0 && r >= '0' && r <= '9':
+			builder.WriteString("_")
+			builder.WriteRune(r)
+		default:
+			builder.WriteString("_x")
+			builder.WriteString(strings.ToUpper(strconv.FormatInt(int64(r), 16)))
+		}
+	}
+	return builder.String()
+}
+
+// encodeManifest encodes the data files to a manifest file, all of them
+// must have the same content type of the manifest.
+func encodeManifest(
+	snapshotID int64, schema *Schema, spec *PartitionSpec, content int, files []*DataFile,
+) ([]byte, error) {
+	avroSchema, err := manifestEntrySchema(spec, schema)
+	if err != nil {
+		return nil, err
+	}
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	specJSON, err := json.Marshal(spec.Fields)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	partitionTypes := make([]string, 0, len(spec.Fields))
+	for _, field := range spec.Fields {
+		// the source field is checked by manifestEntrySchema.
+		source, _ := schema.Field(field.SourceID)
+		tp, _ := resultType(field.Transform, source.Type)
+		partitionTypes = append(partitionTypes, avroType(tp))
+	}
+	contentName := "data"
+	if content == manifestContentDeletes {
+		contentName = "deletes"
+	}
+	buf := &bytes.Buffer{}
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               buf,
+		Schema:          avroSchema,
+		CompressionName: goavro.CompressionDeflateLabel,
+		MetaData: map[string][]byte{
+			"schema":            schemaJSON,
+			"schema-id":         []byte(strconv.Itoa(schema.SchemaID)),
+			"partition-spec":    specJSON,
+			"partition-spec-id": []byte(strconv.Itoa(spec.SpecID)),
+			"format-version":    []byte(strconv.Itoa(formatVersion)),
+			"content":           []byte(contentName),
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	entries := make([]interface{}, 0, len(files))
+	for _, file := range files {
+		partition := make(map[string]interface{}, len(spec.Fields))
+		for i, field := range spec.Fields {
+			value := file.Partition[i]
+			// the binary values are kept as strings like the Parquet values.
+			if v, ok := value.(string); ok && partitionTypes[i] == "bytes" {
+				value = []byte(v)
+			}
+			partition[avroName(field.Name)] = avroUnion(value)
+		}
+		dataFile := map[string]interface{}{
+			"content":            int32(file.Content),
+			"file_path":          file.Path,
+			"file_format":        "PARQUET",
+			"partition":          partition,
+			"record_count":       file.RecordCount,
+			"file_size_in_bytes": file.FileSize,
+			"equality_ids":       nil,
+		}
+		if len(file.EqualityIDs) > 0 {
+			ids := make([]interface{}, 0, len(file.EqualityIDs))
+			for _, id := range file.EqualityIDs {
+				ids = append(ids, int32(id))
+			}
+			dataFile["equality_ids"] = goavro.Union("array", ids)
+		}
+		entries = append(entries, map[string]interface{}{
+			"status":               int32(entryStatusAdded),
+			"snapshot_id":          goavro.Union("long", snapshotID),
+			"sequence_number":      goavro.Union("long", file.SequenceNumber),
+			"file_sequence_number": goavro.Union("long", file.SequenceNumber),
+			"data_file":            dataFile,
+		})
+	}
+	// An empty block can't be decoded, so nothing is appended if there are
+	// no entries.
+	if len(entries) > 0 {
+		if err := writer.Append(entries); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// avroUnion wraps a partition value to the native form of the nullable
+// union of goavro.
+func avroUnion(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int32:
+		return goavro.Union("int", v)
+	case int64:
+		return goavro.Union("long", v)
+	case float32:
+		return goavro.Union("float", v)
+	case float64:
+		return goavro.Union("double", v)
+	case string:
+		return goavro.Union("string", v)
+	case []byte:
+		return goavro.Union("bytes", v)
+	}
+	return value
+}
+
+// decodeManifest decodes the data files of a manifest file.
+func decodeManifest(data []byte, spec *PartitionSpec, manifest *ManifestFile) ([]*DataFile, error) {
+	reader, err := goavro.NewOCFReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var files []*DataFile
+	for reader.Scan() {
+		datum, err := reader.Read()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		entry := datum.(map[string]interface{})
+		dataFile := entry["data_file"].(map[string]interface{})
+		file := &DataFile{
+			Content:        int(dataFile["content"].(int32)),
+			Path:           dataFile["file_path"].(string),
+			RecordCount:    dataFile["record_count"].(int64),
+			FileSize:       dataFile["file_size_in_bytes"].(int64),
+			SequenceNumber: manifest.SequenceNumber,
+		}
+		// The sequence number is inherited from the manifest if it's null.
+		if seq, ok := unwrapUnion(entry["sequence_number"]).(int64); ok {
+			file.SequenceNumber = seq
+		}
+		partition := dataFile["partition"].(map[string]interface{})
+		for _, field := range spec.Fields {
+			value := unwrapUnion(partition[avroName(field.Name)])
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			file.Partition = append(file.Partition, value)
+		}
+		if ids, ok := unwrapUnion(dataFile["equality_ids"]).([]interface{}); ok {
+			for _, id := range ids {
+				file.EqualityIDs = append(file.EqualityIDs, int(id.(int32)))
+			}
+		}
+		files = append(files, file)
+	}
+	return files, errors.Trace(reader.Err())
+}
+
+func unwrapUnion(value interface{}) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		for _, v := range m {
+			return v
+		}
+	}
+	return value
+}
+
+// encodeManifestList encodes the manifests of a snapshot to a manifest list.
+func encodeManifestList(snapshot *Snapshot, manifests []*ManifestFile) ([]byte, error) {
+	parentID := "null"
+	if snapshot.ParentSnapshotID != nil {
+		parentID = strconv.FormatInt(*snapshot.ParentSnapshotID, 10)
+	}
+	buf := &bytes.Buffer{}
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               buf,
+		Schema:          manifestListSchema,
+		CompressionName: goavro.CompressionDeflateLabel,
+		MetaData: map[string][]byte{
+			"snapshot-id":        []byte(strconv.FormatInt(snapshot.SnapshotID, 10)),
+			"parent-snapshot-id": []byte(parentID),
+			"sequence-number":    []byte(strconv.FormatInt(snapshot.SequenceNumber, 10)),
+			"format-version":     []byte(strconv.Itoa(formatVersion)),
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	records := make([]interface{}, 0, len(manifests))
+	for _, m := range manifests {
+		records = append(records, map[string]interface{}{
+			"manifest_path":        m.Path,
+			"manifest_length":      m.Length,
+			"partition_spec_id":    int32(m.SpecID),
+			"content":              int32(m.Content),
+			"sequence_number":      m.SequenceNumber,
+			"min_sequence_number":  m.MinSequenceNumber,
+			"added_snapshot_id":    m.AddedSnapshotID,
+			"added_files_count":    m.AddedFilesCount,
+			"existing_files_count": m.ExistingFilesCount,
+			"deleted_files_count":  m.DeletedFilesCount,
+			"added_rows_count":     m.AddedRowsCount,
+			"existing_rows_count":  m.ExistingRowsCount,
+			"deleted_rows_count":   m.DeletedRowsCount,
+		})
+	}
+	// The manifest list of a truncated table has no manifests, nothing is
+	// appended in that case since an empty block can't be decoded.
+	if len(records) > 0 {
+		if err := writer.Append(records); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeManifestList decodes the manifests of a manifest list.
+func decodeManifestList(data []byte) ([]*ManifestFile, error) {
+	reader, err := goavro.NewOCFReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var manifests []*ManifestFile
+	for reader.Scan() {
+		datum, err := reader.Read()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		record, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("unexpected manifest list record %v", datum)
+		}
+		manifests = append(manifests, &ManifestFile{
+			Path:               record["manifest_path"].(string),
+			Length:             record["manifest_length"].(int64),
+			SpecID:             int(record["partition_spec_id"].(int32)),
+			Content:            int(record["content"].(int32)),
+			SequenceNumber:     record["sequence_number"].(int64),
+			MinSequenceNumber:  record["min_sequence_number"].(int64),
+			AddedSnapshotID:    record["added_snapshot_id"].(int64),
+			AddedFilesCount:    record["added_files_count"].(int32),
+			ExistingFilesCount: record["existing_files_count"].(int32),
+			DeletedFilesCount:  record["deleted_files_count"].(int32),
+			AddedRowsCount:     record["added_rows_count"].(int64),
+			ExistingRowsCount:  record["existing_rows_count"].(int64),
+			DeletedRowsCount:   record["deleted_rows_count"].(int64),
+		})
+	}
+	return manifests, errors.Trace(reader.Err())
+}
@@ -0,0 +1,324 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/spaolacci/murmur3"
+)
+
+// The transforms of the partition fields.
+const (
+	TransformIdentity = "identity"
+	TransformBucket   = "bucket"
+	TransformTruncate = "truncate"
+	TransformYear     = "year"
+	TransformMonth    = "month"
+	TransformDay      = "day"
+	TransformHour     = "hour"
+
+	// the field IDs of partition fields start from 1000 in Iceberg.
+	firstPartitionFieldID = 1000
+
+	microsPerHour = int64(time.Hour / time.Microsecond)
+	microsPerDay  = 24 * microsPerHour
+)
+
+// partitionFieldRegexp matches `transform(column)` and `transform(n, column)`.
+var partitionFieldRegexp = regexp.MustCompile(`^(\w+)\s*\(\s*(?:(\d+)\s*,\s*)?([^()]+?)\s*\)$`)
+
+// PartitionField is a field of an Iceberg partition spec.
+type PartitionField struct {
+	SourceID int    `json:"source-id"`
+	FieldID  int    `json:"field-id"`
+	Name     string `json:"name"`
+	// Transform is like `identity`, `day`, `bucket[16]` and `truncate[4]`.
+	Transform string `json:"transform"`
+}
+
+// PartitionSpec is an Iceberg partition spec.
+type PartitionSpec struct {
+	SpecID int               `json:"spec-id"`
+	Fields []*PartitionField `json:"fields"`
+}
+
+// parsePartitionField parses a partition field of `iceberg-partition-by`,
+// which is a column name, or a transform of a column like `day(ts)`,
+// `bucket(16, id)` and `truncate(4, name)`. The column and the transform
+// in the form of Iceberg are returned.
+func parsePartitionField(field string) (column string, transform string, err error) {
+	field = strings.TrimSpace(field)
+	matches := partitionFieldRegexp.FindStringSubmatch(field)
+	if matches == nil {
+		if field == "" || strings.ContainsAny(field, "(),") {
+			return "", "", cerror.ErrIcebergInvalidConfig.GenWithStack(
+				"invalid partition field %q", field)
+		}
+		return field, TransformIdentity, nil
+	}
+	name, param, column := strings.ToLower(matches[1]), matches[2], matches[3]
+	switch name {
+	case TransformYear, TransformMonth, TransformDay, TransformHour, TransformIdentity:
+		if param != "" {
+			return "", "", cerror.ErrIcebergInvalidConfig.GenWithStack(
+				"invalid partition field %q, transform %s has no parameter", field, name)
+		}
+		return column, name, nil
+	case TransformBucket, TransformTruncate:
+		n, err := strconv.Atoi(param)
+		if err != nil || n <= 0 {
+			return "", "", cerror.ErrIcebergInvalidConfig.GenWithStack(
+				"invalid partition field %q, transform %s requires a positive parameter", field, name)
+		}
+		return column, fmt.Sprintf("%s[%d]", name, n), nil
+	default:
+		return "", "", cerror.ErrIcebergInvalidConfig.GenWithStack(
+			"invalid partition field %q, unknown transform %s", field, name)
+	}
+}
+
+// NewPartitionSpec creates the partition spec of the partition fields of
+// `iceberg-partition-by` on the schema.
+func NewPartitionSpec(schema *Schema, fields []string) (*PartitionSpec, error) {
+	spec := &PartitionSpec{Fields: []*PartitionField{}}
+	for i, field := range fields {
+		column, transform, err := parsePartitionField(field)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := schema.FieldByName(column)
+		if !ok {
+			return nil, cerror.ErrIcebergInvalidConfig.GenWithStack(
+				"partition column %s not found", column)
+		}
+		name, _ := splitTransform(transform)
+		if _, err := resultType(transform, source.Type); err != nil {
+			return nil, err
+		}
+		partitionName := source.Name
+		if name != TransformIdentity {
+			suffix := name
+			if name == TransformTruncate {
+				suffix = "trunc"
+			}
+			partitionName += "_" + suffix
+		}
+		spec.Fields = append(spec.Fields, &PartitionField{
+			SourceID:  source.ID,
+			FieldID:   firstPartitionFieldID + i,
+			Name:      partitionName,
+			Transform: transform,
+		})
+	}
+	return spec, nil
+}
+
+// LastPartitionID returns the largest partition field ID.
+func (s *PartitionSpec) LastPartitionID() int {
+	// Iceberg uses 999 for the unpartitioned tables.
+	lastID := firstPartitionFieldID - 1
+	for _, field := range s.Fields {
+		if field.FieldID > lastID {
+			lastID = field.FieldID
+		}
+	}
+	return lastID
+}
+
+// splitTransform splits `bucket[16]` to `bucket` and 16.
+func splitTransform(transform string) (string, int) {
+	i := strings.IndexByte(transform, '[')
+	if i < 0 {
+		return transform, 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSuffix(transform[i+1:], "]"))
+	return transform[:i], n
+}
+
+// resultType returns the Iceberg type of the partition values of a
+// transform on the source type.
+func resultType(transform string, sourceType string) (string, error) {
+	name, _ := splitTransform(transform)
+	_, _, isDecimal := parseDecimalType(sourceType)
+	supported := false
+	result := sourceType
+	switch name {
+	case TransformIdentity:
+		supported = !isDecimal
+	case TransformBucket:
+		supported = sourceType != TypeFloat && sourceType != TypeDouble
+		result = TypeInt
+	case TransformTruncate:
+		supported = sourceType == TypeInt || sourceType == TypeLong ||
+			sourceType == TypeString || sourceType == TypeBinary
+	case TransformYear, TransformMonth:
+		supported = sourceType == TypeDate || sourceType == TypeTimestamp
+		result = TypeInt
+	case TransformDay:
+		supported = sourceType == TypeDate || sourceType == TypeTimestamp
+		result = TypeDate
+	case TransformHour:
+		supported = sourceType == TypeTimestamp
+		result = TypeInt
+	}
+	if !supported {
+		return "", cerror.ErrIcebergInvalidConfig.GenWithStack(
+			"transform %s is not supported on %s columns", transform, sourceType)
+	}
+	return result, nil
+}
+
+// applyTransform applies the transform to the Parquet value of the source
+// field returned by ConvertValue.
+func applyTransform(transform string, sourceType string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	name, n := splitTransform(transform)
+	switch name {
+	case TransformIdentity:
+		return value, nil
+	case TransformBucket:
+		hash, err := bucketHash(sourceType, value)
+		if err != nil {
+			return nil, err
+		}
+		return int32((hash & math.MaxInt32) % uint32(n)), nil
+	case TransformTruncate:
+		switch v := value.(type) {
+		case int32:
+			w := int32(n)
+			return v - (((v % w) + w) % w), nil
+		case int64:
+			w := int64(n)
+			return v - (((v % w) + w) % w), nil
+		case string:
+			if sourceType == TypeBinary {
+				if len(v) > n {
+					return v[:n], nil
+				}
+				return v, nil
+			}
+			// strings are truncated by code points.
+			runes := []rune(v)
+			if len(runes) > n {
+				return string(runes[:n]), nil
+			}
+			return v, nil
+		}
+	case TransformYear, TransformMonth, TransformDay, TransformHour:
+		var micros int64
+		switch v := value.(type) {
+		case int32:
+			micros = int64(v) * microsPerDay
+		case int64:
+			micros = v
+		default:
+			return nil, errors.Errorf("unexpected value %v of type %T for transform %s", value, value, transform)
+		}
+		t := time.UnixMicro(micros).UTC()
+		switch name {
+		case TransformYear:
+			return int32(t.Year() - 1970), nil
+		case TransformMonth:
+			return int32((t.Year()-1970)*12 + int(t.Month()) - 1), nil
+		case TransformDay:
+			return int32(floorDiv(micros, microsPerDay)), nil
+		default:
+			return int32(floorDiv(micros, microsPerHour)), nil
+		}
+	}
+	return nil, errors.Errorf("unexpected value %v of type %T for transform %s", value, value, transform)
+}
+
+// bucketHash returns the 32-bit murmur3 hash of a value as the Iceberg spec.
+func bucketHash(sourceType string, value interface{}) (uint32, error) {
+	switch v := value.(type) {
+	case int32:
+		return hashLong(int64(v)), nil
+	case int64:
+		return hashLong(v), nil
+	case string:
+		if _, _, ok := parseDecimalType(sourceType); ok {
+			return murmur3.Sum32(minimalBytes(unscaledValue([]byte(v)))), nil
+		}
+		return murmur3.Sum32([]byte(v)), nil
+	}
+	return 0, errors.Errorf("unexpected value %v of type %T for bucket transform", value, value)
+}
+
+func hashLong(v int64) uint32 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(v))
+	return murmur3.Sum32(buf[:])
+}
+
+// partitionPath returns the human-readable path of a partition, which is
+// the same as the one of Iceberg, e.g. `ts_day=2024-01-02/id_bucket=3`.
+func partitionPath(spec *PartitionSpec, schema *Schema, values []interface{}) string {
+	parts := make([]string, 0, len(spec.Fields))
+	for i, field := range spec.Fields {
+		parts = append(parts, url.QueryEscape(field.Name)+"="+
+			url.QueryEscape(partitionValueString(field, schema, values[i])))
+	}
+	return strings.Join(parts, "/")
+}
+
+func partitionValueString(field *PartitionField, schema *Schema, value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	name, _ := splitTransform(field.Transform)
+	sourceType := ""
+	if source, ok := schema.Field(field.SourceID); ok {
+		sourceType = source.Type
+	}
+	switch name {
+	case TransformYear:
+		return strconv.Itoa(1970 + int(value.(int32)))
+	case TransformMonth:
+		months := int(value.(int32))
+		year, month := 1970+months/12, months%12
+		if month < 0 {
+			year, month = year-1, month+12
+		}
+		return fmt.Sprintf("%04d-%02d", year, month+1)
+	case TransformDay:
+		return time.UnixMicro(int64(value.(int32)) * microsPerDay).UTC().Format("2006-01-02")
+	case TransformHour:
+		return time.UnixMicro(int64(value.(int32)) * microsPerHour).UTC().Format("2006-01-02-15")
+	case TransformBucket:
+		return fmt.Sprintf("%v", value)
+	}
+	switch sourceType {
+	case TypeDate:
+		return time.UnixMicro(int64(value.(int32)) * microsPerDay).UTC().Format("2006-01-02")
+	case TypeTimestamp:
+		return time.UnixMicro(value.(int64)).UTC().Format("2006-01-02T15:04:05.999999")
+	case TypeBinary:
+		return base64.StdEncoding.EncodeToString([]byte(value.(string)))
+	}
+	return fmt.Sprintf("%v", value)
+}
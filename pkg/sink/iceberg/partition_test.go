@@ -0,0 +1,149 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePartitionField(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		field     string
+		column    string
+		transform string
+	}{
+		{"id", "id", TransformIdentity},
+		{"day(created_at)", "created_at", TransformDay},
+		{"HOUR( created_at )", "created_at", TransformHour},
+		{"bucket(16, id)", "id", "bucket[16]"},
+		{"truncate(4,name)", "name", "truncate[4]"},
+	}
+	for _, c := range cases {
+		column, transform, err := parsePartitionField(c.field)
+		require.NoError(t, err, c.field)
+		require.Equal(t, c.column, column, c.field)
+		require.Equal(t, c.transform, transform, c.field)
+	}
+
+	for _, field := range []string{"", "day(1, ts)", "bucket(id)", "bucket(0, id)", "unknown(id)", "a,b"} {
+		_, _, err := parsePartitionField(field)
+		require.Error(t, err, field)
+	}
+}
+
+func TestNewPartitionSpec(t *testing.T) {
+	t.Parallel()
+
+	schema := &Schema{Fields: []*Field{
+		{ID: 1, Name: "id", Type: TypeLong},
+		{ID: 2, Name: "name", Type: TypeString},
+		{ID: 3, Name: "created_at", Type: TypeTimestamp},
+		{ID: 4, Name: "price", Type: "decimal(10, 2)"},
+	}}
+	spec, err := NewPartitionSpec(schema, []string{"day(created_at)", "bucket(16, id)", "truncate(2, name)"})
+	require.NoError(t, err)
+	require.Equal(t, []*PartitionField{
+		{SourceID: 3, FieldID: 1000, Name: "created_at_day", Transform: TransformDay},
+		{SourceID: 1, FieldID: 1001, Name: "id_bucket", Transform: "bucket[16]"},
+		{SourceID: 2, FieldID: 1002, Name: "name_trunc", Transform: "truncate[2]"},
+	}, spec.Fields)
+	require.Equal(t, 1002, spec.LastPartitionID())
+
+	spec, err = NewPartitionSpec(schema, nil)
+	require.NoError(t, err)
+	require.Empty(t, spec.Fields)
+	require.Equal(t, 999, spec.LastPartitionID())
+
+	_, err = NewPartitionSpec(schema, []string{"day(name)"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = NewPartitionSpec(schema, []string{"price"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = NewPartitionSpec(schema, []string{"missing"})
+	require.ErrorContains(t, err, "not found")
+}
+
+// TestBucketHash checks the hashes with the test vectors of the Iceberg spec.
+func TestBucketHash(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		sourceType string
+		value      interface{}
+		expected   int32
+	}{
+		{TypeInt, int32(34), 2017239379},
+		{TypeLong, int64(34), 2017239379},
+		{"decimal(4, 2)", "\x05\x8c", -500754589},
+		{TypeDate, int32(17486), -653330422},
+		{TypeTimestamp, int64(1510871468000000), -2047944441},
+		{TypeString, "iceberg", 1210000089},
+		{TypeBinary, "\x00\x01\x02\x03", -188683207},
+	}
+	for _, c := range cases {
+		hash, err := bucketHash(c.sourceType, c.value)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, int32(hash), c.sourceType)
+	}
+}
+
+func TestApplyTransform(t *testing.T) {
+	t.Parallel()
+
+	// 2017-11-16 22:31:08
+	ts := int64(1510871468000000)
+	cases := []struct {
+		transform  string
+		sourceType string
+		value      interface{}
+		expected   interface{}
+	}{
+		{TransformIdentity, TypeString, "a", "a"},
+		{TransformYear, TypeTimestamp, ts, int32(47)},
+		{TransformMonth, TypeTimestamp, ts, int32(574)},
+		{TransformDay, TypeTimestamp, ts, int32(17486)},
+		{TransformDay, TypeDate, int32(17486), int32(17486)},
+		{TransformHour, TypeTimestamp, ts, int32(419686)},
+		{TransformDay, TypeTimestamp, int64(-1), int32(-1)},
+		{"truncate[10]", TypeInt, int32(-1), int32(-10)},
+		{"truncate[10]", TypeLong, int64(15), int64(10)},
+		{"truncate[3]", TypeString, "冰山iceberg", "冰山i"},
+		{"bucket[16]", TypeLong, int64(34), int32(2017239379 % 16)},
+		{"bucket[16]", TypeLong, nil, nil},
+	}
+	for _, c := range cases {
+		value, err := applyTransform(c.transform, c.sourceType, c.value)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, value, c.transform)
+	}
+}
+
+func TestPartitionPath(t *testing.T) {
+	t.Parallel()
+
+	schema := &Schema{Fields: []*Field{
+		{ID: 1, Name: "id", Type: TypeLong},
+		{ID: 2, Name: "created_at", Type: TypeTimestamp},
+		{ID: 3, Name: "name", Type: TypeString},
+	}}
+	spec, err := NewPartitionSpec(schema, []string{"day(created_at)", "bucket(16, id)", "name"})
+	require.NoError(t, err)
+	require.Equal(t, "created_at_day=2017-11-16/id_bucket=3/name=a+b",
+		partitionPath(spec, schema, []interface{}{int32(17486), int32(3), "a b"}))
+	require.Equal(t, "created_at_day=null/id_bucket=null/name=null",
+		partitionPath(spec, schema, []interface{}{nil, nil, nil}))
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// Key returns the key of the values, which is used to match the rows with
+// the equality delete files.
+func Key(values []interface{}) string {
+	var builder strings.Builder
+	for _, value := range values {
+		if value == nil {
+			builder.WriteString("null,")
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%T:", value))
+		builder.WriteString(strconv.Quote(fmt.Sprintf("%v", value)))
+		builder.WriteString(",")
+	}
+	return builder.String()
+}
+
+// Scan reads the rows of the snapshot as of the commit ts, the rows are
+// returned as the maps from the field names to the values in the same
+// types as ConvertValue. It's used to verify the time travel queries of
+// the tables written by the sink.
+func (t *Table) Scan(ctx context.Context, commitTs uint64) ([]map[string]interface{}, error) {
+	snapshot := t.Metadata.SnapshotAsOf(commitTs)
+	if snapshot == nil {
+		return nil, nil
+	}
+	schema := t.Metadata.Schema(snapshot.SchemaID)
+	data, err := t.catalog.storage.ReadFile(ctx, t.storagePath(snapshot.ManifestList))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	manifests, err := decodeManifestList(data)
+	if err != nil {
+		return nil, err
+	}
+	var dataFiles, deleteFiles []*DataFile
+	for _, manifest := range manifests {
+		data, err := t.catalog.storage.ReadFile(ctx, t.storagePath(manifest.Path))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		files, err := decodeManifest(data, t.Metadata.Spec(manifest.SpecID), manifest)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.Content == ContentData {
+				dataFiles = append(dataFiles, file)
+			} else {
+				deleteFiles = append(deleteFiles, file)
+			}
+		}
+	}
+
+	// the keys of the rows deleted by each delete file.
+	deletedKeys := make([]map[string]struct{}, 0, len(deleteFiles))
+	for _, file := range deleteFiles {
+		rows, err := t.readDataFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		keys := make(map[string]struct{}, len(rows))
+		for _, row := range rows {
+			keys[Key(valuesOf(row, file.EqualityIDs))] = struct{}{}
+		}
+		deletedKeys = append(deletedKeys, keys)
+	}
+
+	var result []map[string]interface{}
+	for _, file := range dataFiles {
+		rows, err := t.readDataFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		partition := Key(file.Partition)
+		for _, row := range rows {
+			deleted := false
+			for i, deleteFile := range deleteFiles {
+				// An equality delete file applies to the data files in the same
+				// partition with smaller sequence numbers.
+				if deleteFile.SequenceNumber <= file.SequenceNumber ||
+					Key(deleteFile.Partition) != partition {
+					continue
+				}
+				if _, ok := deletedKeys[i][Key(valuesOf(row, deleteFile.EqualityIDs))]; ok {
+					deleted = true
+					break
+				}
+			}
+			if deleted {
+				continue
+			}
+			record := make(map[string]interface{}, len(schema.Fields))
+			for _, field := range schema.Fields {
+				record[field.Name] = row[field.ID]
+			}
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func valuesOf(row map[int]interface{}, ids []int) []interface{} {
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, row[id])
+	}
+	return values
+}
+
+// readDataFile reads the rows of a Parquet file as the maps from the field
+// IDs to the values.
+func (t *Table) readDataFile(ctx context.Context, file *DataFile) ([]map[int]interface{}, error) {
+	data, err := t.catalog.storage.ReadFile(ctx, t.storagePath(file.Path))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pf, err := buffer.NewBufferFile(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pr, err := reader.NewParquetColumnReader(pf, 1)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer pr.ReadStop()
+
+	numRows := pr.GetNumRows()
+	rows := make([]map[int]interface{}, numRows)
+	for i := range rows {
+		rows[i] = make(map[int]interface{})
+	}
+	// the first element is the root of the schema.
+	for i, element := range pr.SchemaHandler.SchemaElements[1:] {
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), numRows)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if int64(len(values)) != numRows {
+			return nil, errors.Errorf("unexpected %d values of column %s in file %s with %d rows",
+				len(values), element.GetName(), file.Path, numRows)
+		}
+		for j, value := range values {
+			rows[j][int(element.GetFieldID())] = value
+		}
+	}
+	return rows, nil
+}
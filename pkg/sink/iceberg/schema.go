@@ -0,0 +1,110 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// Field is a field of an Iceberg schema.
+type Field struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Type     string `json:"type"`
+}
+
+// Schema is an Iceberg schema, which only contains primitive fields.
+type Schema struct {
+	Type     string `json:"type"`
+	SchemaID int    `json:"schema-id"`
+	// IdentifierFieldIDs are the IDs of the handle key columns, which are
+	// used as the equality fields of the delete files.
+	IdentifierFieldIDs []int    `json:"identifier-field-ids,omitempty"`
+	Fields             []*Field `json:"fields"`
+}
+
+// NewSchema creates the Iceberg schema of a table. The field IDs are the
+// column IDs, which are never reused by TiDB, so that the renamed and
+// dropped columns can be tracked by Iceberg.
+func NewSchema(tableInfo *model.TableInfo) *Schema {
+	schema := &Schema{Type: "struct"}
+	for _, col := range tableInfo.Columns {
+		if col.IsGenerated() && !col.GeneratedStored {
+			continue
+		}
+		schema.Fields = append(schema.Fields, &Field{
+			ID:       int(col.ID),
+			Name:     col.Name.O,
+			Required: mysql.HasNotNullFlag(col.GetFlag()),
+			Type:     ColumnType(&col.FieldType),
+		})
+		if tableInfo.ForceGetColumnFlagType(col.ID).IsHandleKey() {
+			schema.IdentifierFieldIDs = append(schema.IdentifierFieldIDs, int(col.ID))
+		}
+	}
+	return schema
+}
+
+// Field returns the field with the ID.
+func (s *Schema) Field(id int) (*Field, bool) {
+	for _, field := range s.Fields {
+		if field.ID == id {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// FieldByName returns the field with the name.
+func (s *Schema) FieldByName(name string) (*Field, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// LastColumnID returns the largest field ID.
+func (s *Schema) LastColumnID() int {
+	lastID := 0
+	for _, field := range s.Fields {
+		if field.ID > lastID {
+			lastID = field.ID
+		}
+	}
+	return lastID
+}
+
+// SameAs returns true if the two schemas have the same fields, the schema
+// IDs are ignored.
+func (s *Schema) SameAs(other *Schema) bool {
+	if len(s.Fields) != len(other.Fields) ||
+		len(s.IdentifierFieldIDs) != len(other.IdentifierFieldIDs) {
+		return false
+	}
+	for i, field := range s.Fields {
+		if *field != *other.Fields[i] {
+			return false
+		}
+	}
+	for i, id := range s.IdentifierFieldIDs {
+		if id != other.IdentifierFieldIDs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,625 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/xitongsys/parquet-go/writer"
+	"go.uber.org/zap"
+)
+
+const (
+	formatVersion   = 2
+	metadataDir     = "metadata"
+	dataDir         = "data"
+	versionHintFile = "version-hint.text"
+	mainBranch      = "main"
+	noSnapshotID    = int64(-1)
+
+	// CommitTsProperty is the property of the snapshot summary, which is the
+	// max commit ts of the rows committed by the snapshot.
+	CommitTsProperty = "tidb.commit-ts"
+
+	// maxCommitAttempts is the max attempts to commit a snapshot if the
+	// metadata is committed by other writers concurrently.
+	maxCommitAttempts = 10
+)
+
+// Snapshot is a snapshot of an Iceberg table.
+type Snapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	SequenceNumber   int64             `json:"sequence-number"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	ManifestList     string            `json:"manifest-list"`
+	Summary          map[string]string `json:"summary"`
+	SchemaID         int               `json:"schema-id"`
+}
+
+// CommitTs returns the max commit ts of the rows committed by the snapshot.
+func (s *Snapshot) CommitTs() uint64 {
+	ts, _ := strconv.ParseUint(s.Summary[CommitTsProperty], 10, 64)
+	return ts
+}
+
+// SnapshotLogEntry is an entry of the snapshot log.
+type SnapshotLogEntry struct {
+	SnapshotID  int64 `json:"snapshot-id"`
+	TimestampMs int64 `json:"timestamp-ms"`
+}
+
+// MetadataLogEntry is an entry of the metadata log.
+type MetadataLogEntry struct {
+	MetadataFile string `json:"metadata-file"`
+	TimestampMs  int64  `json:"timestamp-ms"`
+}
+
+// SnapshotRef is a branch or a tag of a table.
+type SnapshotRef struct {
+	SnapshotID int64  `json:"snapshot-id"`
+	Type       string `json:"type"`
+}
+
+// SortOrder is a sort order of a table, the tables are always unsorted.
+type SortOrder struct {
+	OrderID int           `json:"order-id"`
+	Fields  []interface{} `json:"fields"`
+}
+
+// TableMetadata is the metadata of an Iceberg table in format version 2.
+type TableMetadata struct {
+	FormatVersion      int                     `json:"format-version"`
+	TableUUID          string                  `json:"table-uuid"`
+	Location           string                  `json:"location"`
+	LastSequenceNumber int64                   `json:"last-sequence-number"`
+	LastUpdatedMs      int64                   `json:"last-updated-ms"`
+	LastColumnID       int                     `json:"last-column-id"`
+	Schemas            []*Schema               `json:"schemas"`
+	CurrentSchemaID    int                     `json:"current-schema-id"`
+	PartitionSpecs     []*PartitionSpec        `json:"partition-specs"`
+	DefaultSpecID      int                     `json:"default-spec-id"`
+	LastPartitionID    int                     `json:"last-partition-id"`
+	Properties         map[string]string       `json:"properties"`
+	CurrentSnapshotID  int64                   `json:"current-snapshot-id"`
+	Refs               map[string]*SnapshotRef `json:"refs"`
+	Snapshots          []*Snapshot             `json:"snapshots"`
+	SnapshotLog        []*SnapshotLogEntry     `json:"snapshot-log"`
+	MetadataLog        []*MetadataLogEntry     `json:"metadata-log"`
+	SortOrders         []*SortOrder            `json:"sort-orders"`
+	DefaultSortOrderID int                     `json:"default-sort-order-id"`
+}
+
+// Schema returns the schema with the ID.
+func (m *TableMetadata) Schema(id int) *Schema {
+	for _, schema := range m.Schemas {
+		if schema.SchemaID == id {
+			return schema
+		}
+	}
+	return nil
+}
+
+// CurrentSchema returns the current schema of the table.
+func (m *TableMetadata) CurrentSchema() *Schema {
+	return m.Schema(m.CurrentSchemaID)
+}
+
+// Spec returns the partition spec with the ID.
+func (m *TableMetadata) Spec(id int) *PartitionSpec {
+	for _, spec := range m.PartitionSpecs {
+		if spec.SpecID == id {
+			return spec
+		}
+	}
+	return nil
+}
+
+// Snapshot returns the snapshot with the ID.
+func (m *TableMetadata) Snapshot(id int64) *Snapshot {
+	for _, snapshot := range m.Snapshots {
+		if snapshot.SnapshotID == id {
+			return snapshot
+		}
+	}
+	return nil
+}
+
+// CurrentSnapshot returns the current snapshot, nil is returned if the
+// table has no snapshot.
+func (m *TableMetadata) CurrentSnapshot() *Snapshot {
+	if m.CurrentSnapshotID == noSnapshotID {
+		return nil
+	}
+	return m.Snapshot(m.CurrentSnapshotID)
+}
+
+// SnapshotAsOf returns the latest snapshot which contains no rows committed
+// after the commit ts. The partitions of a table may be committed out of
+// order, so a snapshot is skipped if any of its previous snapshots commits
+// rows after the commit ts.
+func (m *TableMetadata) SnapshotAsOf(commitTs uint64) *Snapshot {
+	var result *Snapshot
+	for _, entry := range m.SnapshotLog {
+		snapshot := m.Snapshot(entry.SnapshotID)
+		if snapshot == nil {
+			continue
+		}
+		if snapshot.CommitTs() > commitTs {
+			break
+		}
+		result = snapshot
+	}
+	return result
+}
+
+// Catalog is a catalog of the Iceberg tables in a warehouse on an external
+// storage. The tables are located at `<warehouse>/<schema>/<table>`, and
+// committed in the same way as the Hadoop catalog of Iceberg: the metadata
+// of version N is written to `metadata/vN.metadata.json`, and the latest
+// version is recorded in `metadata/version-hint.text`.
+// The metadata file is created with a conditional write which fails if the
+// version exists, so only one of the concurrent writers commits the version,
+// and the others retry on the latest version.
+type Catalog struct {
+	storage    storage.ExternalStorage
+	createFile createFileFunc
+	location   string
+}
+
+// NewCatalog creates a catalog of the warehouse on the external storage, an
+// error is returned if the storage doesn't support conditional writes.
+func NewCatalog(storage storage.ExternalStorage) (*Catalog, error) {
+	createFile, err := newCreateFileFunc(storage)
+	if err != nil {
+		return nil, err
+	}
+	return &Catalog{
+		storage:    storage,
+		createFile: createFile,
+		location:   strings.TrimSuffix(storage.URI(), "/"),
+	}, nil
+}
+
+// Table is an Iceberg table of a catalog.
+type Table struct {
+	catalog *Catalog
+	// path is the path of the table relative to the warehouse.
+	path    string
+	version int
+
+	Metadata *TableMetadata
+}
+
+func tablePath(schema, table string) string {
+	return path.Join(schema, table)
+}
+
+// LoadTable loads the latest metadata of a table, nil is returned if the
+// table doesn't exist.
+func (c *Catalog) LoadTable(ctx context.Context, schema, table string) (*Table, error) {
+	t := &Table{catalog: c, path: tablePath(schema, table)}
+	hintPath := path.Join(t.path, metadataDir, versionHintFile)
+	exists, err := c.storage.FileExists(ctx, hintPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	hint, err := c.storage.ReadFile(ctx, hintPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(hint)))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrIcebergCommitFailed, err)
+	}
+	// The version hint is written after the metadata, so there may be a
+	// newer version if the last commit failed after writing the metadata.
+	for {
+		exists, err := c.storage.FileExists(ctx, t.metadataPath(version+1))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !exists {
+			break
+		}
+		version++
+	}
+	data, err := c.storage.ReadFile(ctx, t.metadataPath(version))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	metadata := &TableMetadata{}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, cerror.WrapError(cerror.ErrIcebergCommitFailed, err)
+	}
+	t.version = version
+	t.Metadata = metadata
+	return t, nil
+}
+
+// CreateTable creates a table with the schema and the partition fields of
+// `iceberg-partition-by`, the existing table is loaded if it exists.
+func (c *Catalog) CreateTable(
+	ctx context.Context, schema, table string, icebergSchema *Schema, partitionFields []string,
+) (*Table, error) {
+	t, err := c.LoadTable(ctx, schema, table)
+	if err != nil || t != nil {
+		return t, err
+	}
+	spec, err := NewPartitionSpec(icebergSchema, partitionFields)
+	if err != nil {
+		return nil, err
+	}
+	icebergSchema.SchemaID = 0
+	t = &Table{catalog: c, path: tablePath(schema, table)}
+	t.Metadata = &TableMetadata{
+		FormatVersion:      formatVersion,
+		TableUUID:          uuid.NewString(),
+		Location:           c.location + "/" + t.path,
+		LastUpdatedMs:      time.Now().UnixMilli(),
+		LastColumnID:       icebergSchema.LastColumnID(),
+		Schemas:            []*Schema{icebergSchema},
+		PartitionSpecs:     []*PartitionSpec{spec},
+		LastPartitionID:    spec.LastPartitionID(),
+		Properties:         map[string]string{"write.format.default": "parquet"},
+		CurrentSnapshotID:  noSnapshotID,
+		Refs:               map[string]*SnapshotRef{},
+		Snapshots:          []*Snapshot{},
+		SnapshotLog:        []*SnapshotLogEntry{},
+		MetadataLog:        []*MetadataLogEntry{},
+		SortOrders:         []*SortOrder{{OrderID: 0, Fields: []interface{}{}}},
+		DefaultSortOrderID: 0,
+	}
+	if err := t.writeMetadata(ctx, 1); err != nil {
+		if cerror.Cause(err) == errFileExists {
+			// The table is created by another writer concurrently.
+			return c.LoadTable(ctx, schema, table)
+		}
+		return nil, err
+	}
+	log.Info("Iceberg table is created",
+		zap.String("table", t.Metadata.Location),
+		zap.Strings("partitionFields", partitionFields))
+	return t, nil
+}
+
+func (t *Table) metadataPath(version int) string {
+	return path.Join(t.path, metadataDir, fmt.Sprintf("v%d.metadata.json", version))
+}
+
+// location returns the absolute location of a file of the table.
+func (t *Table) location(relPath string) string {
+	return t.Metadata.Location + "/" + relPath
+}
+
+// storagePath returns the path in the external storage of a file location.
+func (t *Table) storagePath(location string) string {
+	return strings.TrimPrefix(location, t.catalog.location+"/")
+}
+
+// writeMetadata creates the metadata file of the version, errFileExists is
+// returned if the version is committed by another writer. The version hint
+// is updated after the metadata is committed.
+func (t *Table) writeMetadata(ctx context.Context, version int) error {
+	data, err := json.Marshal(t.Metadata)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := t.catalog.createFile(ctx, t.metadataPath(version), data); err != nil {
+		return err
+	}
+	t.version = version
+	// The version hint is only a hint, LoadTable always looks for the newer
+	// versions, so the committed version is not rolled back if it fails.
+	hintPath := path.Join(t.path, metadataDir, versionHintFile)
+	if err := t.catalog.storage.WriteFile(ctx, hintPath, []byte(strconv.Itoa(version))); err != nil {
+		log.Warn("failed to update the version hint of the Iceberg table",
+			zap.String("table", t.Metadata.Location),
+			zap.Int("version", version),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// reload loads the latest metadata of the table.
+func (t *Table) reload(ctx context.Context) error {
+	schema, table := path.Split(t.path)
+	latest, err := t.catalog.LoadTable(ctx, path.Clean(schema), table)
+	if err != nil {
+		return err
+	}
+	if latest == nil {
+		return cerror.ErrIcebergCommitFailed.GenWithStack(
+			"table %s is not found", t.path)
+	}
+	t.version = latest.version
+	t.Metadata = latest.Metadata
+	return nil
+}
+
+// Spec returns the default partition spec of the table.
+func (t *Table) Spec() *PartitionSpec {
+	return t.Metadata.Spec(t.Metadata.DefaultSpecID)
+}
+
+// PartitionValues returns the partition values of a row in the default
+// partition spec, the row contains the values of the fields of the schema.
+func (t *Table) PartitionValues(schema *Schema, row []interface{}) ([]interface{}, error) {
+	spec := t.Spec()
+	values := make([]interface{}, 0, len(spec.Fields))
+	for _, field := range spec.Fields {
+		var value interface{}
+		sourceType := ""
+		for i, f := range schema.Fields {
+			if f.ID == field.SourceID {
+				value, sourceType = row[i], f.Type
+				break
+			}
+		}
+		partitionValue, err := applyTransform(field.Transform, sourceType, value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, partitionValue)
+	}
+	return values, nil
+}
+
+// WriteDataFile writes the rows to a Parquet file in the partition. The
+// rows contain the values of the fields returned by ConvertValue. The
+// equality IDs are required if the file is an equality delete file.
+func (t *Table) WriteDataFile(
+	ctx context.Context, fields []*Field, rows [][]interface{},
+	partition []interface{}, content int, equalityIDs []int,
+) (*DataFile, error) {
+	tags := make([]string, 0, len(fields))
+	for _, field := range fields {
+		tags = append(tags, parquetTag(field))
+	}
+	buf := &bytes.Buffer{}
+	pw, err := writer.NewCSVWriterFromWriter(tags, buf, 1)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	name := uuid.NewString() + ".parquet"
+	if content == ContentEqualityDeletes {
+		name = uuid.NewString() + "-deletes.parquet"
+	}
+	relPath := path.Join(dataDir, name)
+	if len(partition) > 0 {
+		relPath = path.Join(dataDir, partitionPath(t.Spec(), t.Metadata.CurrentSchema(), partition), name)
+	}
+	location := t.location(relPath)
+	if err := t.catalog.storage.WriteFile(ctx, t.storagePath(location), buf.Bytes()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &DataFile{
+		Content:     content,
+		Path:        location,
+		RecordCount: int64(len(rows)),
+		FileSize:    int64(buf.Len()),
+		Partition:   partition,
+		EqualityIDs: equalityIDs,
+	}, nil
+}
+
+// Commit commits a snapshot with the data files and the delete files. The
+// schema is added to the table if it's changed. If overwrite is true, the
+// files of the previous snapshots are removed from the new snapshot. If the
+// table is committed by other writers concurrently, the snapshot is committed
+// again on the latest metadata.
+func (t *Table) Commit(
+	ctx context.Context, schema *Schema, files []*DataFile, commitTs uint64, overwrite bool,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := t.commit(ctx, schema, files, commitTs, overwrite)
+		if cerror.Cause(err) != errFileExists {
+			return err
+		}
+		if attempt >= maxCommitAttempts {
+			return cerror.ErrIcebergCommitFailed.GenWithStack(
+				"table %s is committed by other writers concurrently, "+
+					"gave up after %d attempts", t.path, attempt)
+		}
+		log.Info("Iceberg table is committed by another writer, commit again",
+			zap.String("table", t.Metadata.Location),
+			zap.Int("version", t.version+1),
+			zap.Int("attempt", attempt))
+		if err := t.reload(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (t *Table) commit(
+	ctx context.Context, schema *Schema, files []*DataFile, commitTs uint64, overwrite bool,
+) error {
+	metadata := *t.Metadata
+	current := metadata.CurrentSchema()
+	if current.SameAs(schema) {
+		schema = current
+	} else {
+		newSchema := *schema
+		for _, s := range metadata.Schemas {
+			if s.SchemaID >= newSchema.SchemaID {
+				newSchema.SchemaID = s.SchemaID + 1
+			}
+		}
+		schema = &newSchema
+		metadata.Schemas = append(append([]*Schema{}, metadata.Schemas...), schema)
+		metadata.CurrentSchemaID = schema.SchemaID
+		if lastID := schema.LastColumnID(); lastID > metadata.LastColumnID {
+			metadata.LastColumnID = lastID
+		}
+	}
+
+	sequenceNumber := metadata.LastSequenceNumber + 1
+	now := time.Now().UnixMilli()
+	// The timestamp is the time when the snapshot is committed, the commit
+	// ts of the rows is recorded in the summary.
+	snapshot := &Snapshot{
+		SnapshotID:     newSnapshotID(),
+		SequenceNumber: sequenceNumber,
+		TimestampMs:    now,
+		SchemaID:       schema.SchemaID,
+	}
+	parent := metadata.CurrentSnapshot()
+	if parent != nil {
+		snapshot.ParentSnapshotID = &parent.SnapshotID
+	}
+
+	var manifests []*ManifestFile
+	if parent != nil && !overwrite {
+		data, err := t.catalog.storage.ReadFile(ctx, t.storagePath(parent.ManifestList))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		manifests, err = decodeManifestList(data)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrIcebergCommitFailed, err)
+		}
+	}
+	var dataFiles, deleteFiles []*DataFile
+	var addedRows, deletedRows int64
+	for _, file := range files {
+		file.SequenceNumber = sequenceNumber
+		if file.Content == ContentData {
+			dataFiles = append(dataFiles, file)
+			addedRows += file.RecordCount
+		} else {
+			deleteFiles = append(deleteFiles, file)
+			deletedRows += file.RecordCount
+		}
+	}
+	for content, contentFiles := range [][]*DataFile{dataFiles, deleteFiles} {
+		if len(contentFiles) == 0 {
+			continue
+		}
+		manifest, err := t.writeManifest(ctx, snapshot, schema, content, contentFiles)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, manifest)
+	}
+	data, err := encodeManifestList(snapshot, manifests)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrIcebergCommitFailed, err)
+	}
+	snapshot.ManifestList = t.location(path.Join(metadataDir,
+		fmt.Sprintf("snap-%d-1-%s.avro", snapshot.SnapshotID, uuid.NewString())))
+	if err := t.catalog.storage.WriteFile(ctx, t.storagePath(snapshot.ManifestList), data); err != nil {
+		return errors.Trace(err)
+	}
+
+	operation := "append"
+	if overwrite || len(deleteFiles) > 0 {
+		operation = "overwrite"
+	}
+	snapshot.Summary = map[string]string{
+		"operation":              operation,
+		"added-data-files":       strconv.Itoa(len(dataFiles)),
+		"added-delete-files":     strconv.Itoa(len(deleteFiles)),
+		"added-records":          strconv.FormatInt(addedRows, 10),
+		"added-equality-deletes": strconv.FormatInt(deletedRows, 10),
+		CommitTsProperty:         strconv.FormatUint(commitTs, 10),
+	}
+
+	metadata.LastSequenceNumber = sequenceNumber
+	metadata.LastUpdatedMs = now
+	metadata.CurrentSnapshotID = snapshot.SnapshotID
+	metadata.Snapshots = append(append([]*Snapshot{}, metadata.Snapshots...), snapshot)
+	metadata.SnapshotLog = append(append([]*SnapshotLogEntry{}, metadata.SnapshotLog...),
+		&SnapshotLogEntry{SnapshotID: snapshot.SnapshotID, TimestampMs: snapshot.TimestampMs})
+	metadata.MetadataLog = append(append([]*MetadataLogEntry{}, metadata.MetadataLog...),
+		&MetadataLogEntry{
+			MetadataFile: t.location(path.Join(metadataDir, fmt.Sprintf("v%d.metadata.json", t.version))),
+			TimestampMs:  t.Metadata.LastUpdatedMs,
+		})
+	metadata.Refs = map[string]*SnapshotRef{
+		mainBranch: {SnapshotID: snapshot.SnapshotID, Type: "branch"},
+	}
+
+	previous := t.Metadata
+	t.Metadata = &metadata
+	if err := t.writeMetadata(ctx, t.version+1); err != nil {
+		t.Metadata = previous
+		return err
+	}
+	return nil
+}
+
+func (t *Table) writeManifest(
+	ctx context.Context, snapshot *Snapshot, schema *Schema, content int, files []*DataFile,
+) (*ManifestFile, error) {
+	manifestContent := manifestContentData
+	if content != ContentData {
+		manifestContent = manifestContentDeletes
+	}
+	spec := t.Spec()
+	data, err := encodeManifest(snapshot.SnapshotID, schema, spec, manifestContent, files)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrIcebergCommitFailed, err)
+	}
+	location := t.location(path.Join(metadataDir,
+		fmt.Sprintf("%s-m%d.avro", uuid.NewString(), manifestContent)))
+	if err := t.catalog.storage.WriteFile(ctx, t.storagePath(location), data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	manifest := &ManifestFile{
+		Path:              location,
+		Length:            int64(len(data)),
+		SpecID:            spec.SpecID,
+		Content:           manifestContent,
+		SequenceNumber:    snapshot.SequenceNumber,
+		MinSequenceNumber: snapshot.SequenceNumber,
+		AddedSnapshotID:   snapshot.SnapshotID,
+		AddedFilesCount:   int32(len(files)),
+	}
+	for _, file := range files {
+		manifest.AddedRowsCount += file.RecordCount
+	}
+	return manifest, nil
+}
+
+// newSnapshotID returns a random positive snapshot ID.
+func newSnapshotID() int64 {
+	id := uuid.New()
+	return int64(binary.BigEndian.Uint64(id[:8]) & math.MaxInt64)
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitAndScan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+t.TempDir())
+	require.NoError(t, err)
+	catalog, err := NewCatalog(storage)
+	require.NoError(t, err)
+
+	table, err := catalog.LoadTable(ctx, "test", "t")
+	require.NoError(t, err)
+	require.Nil(t, table)
+
+	schema := &Schema{
+		Type:               "struct",
+		IdentifierFieldIDs: []int{1},
+		Fields: []*Field{
+			{ID: 1, Name: "id", Required: true, Type: TypeLong},
+			{ID: 2, Name: "v", Type: TypeString},
+		},
+	}
+	table, err = catalog.CreateTable(ctx, "test", "t", schema, nil)
+	require.NoError(t, err)
+	keyFields := schema.Fields[:1]
+
+	// insert (1, a), (2, b) at 100.
+	data, err := table.WriteDataFile(ctx, schema.Fields,
+		[][]interface{}{{int64(1), "a"}, {int64(2), "b"}}, nil, ContentData, nil)
+	require.NoError(t, err)
+	require.NoError(t, table.Commit(ctx, schema, []*DataFile{data}, 100, false))
+
+	// update (1, a) to (1, c) at 200.
+	deletes, err := table.WriteDataFile(ctx, keyFields,
+		[][]interface{}{{int64(1)}}, nil, ContentEqualityDeletes, []int{1})
+	require.NoError(t, err)
+	data, err = table.WriteDataFile(ctx, schema.Fields,
+		[][]interface{}{{int64(1), "c"}}, nil, ContentData, nil)
+	require.NoError(t, err)
+	require.NoError(t, table.Commit(ctx, schema, []*DataFile{deletes, data}, 200, false))
+
+	// the table is loaded from the storage.
+	table, err = catalog.LoadTable(ctx, "test", "t")
+	require.NoError(t, err)
+	require.Len(t, table.Metadata.Snapshots, 2)
+
+	rows, err := table.Scan(ctx, 50)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+	rows, err = table.Scan(ctx, 150)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int64(1), "v": "a"},
+		{"id": int64(2), "v": "b"},
+	}, rows)
+	rows, err = table.Scan(ctx, 200)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int64(1), "v": "c"},
+		{"id": int64(2), "v": "b"},
+	}, rows)
+
+	// add a column and insert (3, d, 1) at 300.
+	newSchema := &Schema{
+		Type:               "struct",
+		IdentifierFieldIDs: []int{1},
+		Fields: []*Field{
+			{ID: 1, Name: "id", Required: true, Type: TypeLong},
+			{ID: 2, Name: "v", Type: TypeString},
+			{ID: 3, Name: "w", Type: TypeInt},
+		},
+	}
+	data, err = table.WriteDataFile(ctx, newSchema.Fields,
+		[][]interface{}{{int64(3), "d", int32(1)}}, nil, ContentData, nil)
+	require.NoError(t, err)
+	require.NoError(t, table.Commit(ctx, newSchema, []*DataFile{data}, 300, false))
+	require.Len(t, table.Metadata.Schemas, 2)
+	require.Equal(t, 1, table.Metadata.CurrentSchemaID)
+	require.Equal(t, 3, table.Metadata.LastColumnID)
+	rows, err = table.Scan(ctx, 300)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"id": int64(1), "v": "c", "w": nil},
+		{"id": int64(2), "v": "b", "w": nil},
+		{"id": int64(3), "v": "d", "w": int32(1)},
+	}, rows)
+
+	// truncate the table at 400.
+	require.NoError(t, table.Commit(ctx, newSchema, nil, 400, true))
+	rows, err = table.Scan(ctx, 400)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+	rows, err = table.Scan(ctx, 399)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	// the commit ts is recorded as is if the snapshots are committed out of
+	// order, the snapshots after it are not visible before the truncate.
+	require.NoError(t, table.Commit(ctx, newSchema, nil, 350, false))
+	require.Equal(t, uint64(350), table.Metadata.CurrentSnapshot().CommitTs())
+	require.Len(t, table.Metadata.Schemas, 2)
+	require.Equal(t, uint64(300), table.Metadata.SnapshotAsOf(360).CommitTs())
+	require.Equal(t, uint64(350), table.Metadata.SnapshotAsOf(400).CommitTs())
+	// the empty manifest list written by the truncate can be read.
+	rows, err = table.Scan(ctx, 400)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func TestConcurrentCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+t.TempDir())
+	require.NoError(t, err)
+	catalog, err := NewCatalog(storage)
+	require.NoError(t, err)
+
+	schema := &Schema{
+		Type:               "struct",
+		IdentifierFieldIDs: []int{1},
+		Fields: []*Field{
+			{ID: 1, Name: "id", Required: true, Type: TypeLong},
+		},
+	}
+	table1, err := catalog.CreateTable(ctx, "test", "t", schema, nil)
+	require.NoError(t, err)
+	// the table created by another writer is loaded.
+	table2, err := catalog.CreateTable(ctx, "test", "t", schema, nil)
+	require.NoError(t, err)
+	require.Equal(t, table1.Metadata.TableUUID, table2.Metadata.TableUUID)
+
+	data1, err := table1.WriteDataFile(ctx, schema.Fields,
+		[][]interface{}{{int64(1)}}, nil, ContentData, nil)
+	require.NoError(t, err)
+	require.NoError(t, table1.Commit(ctx, schema, []*DataFile{data1}, 100, false))
+
+	// table2 is stale, the commit conflicts with table1 and is retried on
+	// the latest metadata, so the files of both commits are kept.
+	data2, err := table2.WriteDataFile(ctx, schema.Fields,
+		[][]interface{}{{int64(2)}}, nil, ContentData, nil)
+	require.NoError(t, err)
+	require.NoError(t, table2.Commit(ctx, schema, []*DataFile{data2}, 200, false))
+	require.Equal(t, 3, table2.version)
+
+	table, err := catalog.LoadTable(ctx, "test", "t")
+	require.NoError(t, err)
+	require.Len(t, table.Metadata.Snapshots, 2)
+	rows, err := table.Scan(ctx, 200)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []map[string]interface{}{{"id": int64(1)}, {"id": int64(2)}}, rows)
+}
+
+func TestPartitionedTable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+t.TempDir())
+	require.NoError(t, err)
+	catalog, err := NewCatalog(storage)
+	require.NoError(t, err)
+
+	schema := &Schema{
+		Type:               "struct",
+		IdentifierFieldIDs: []int{1},
+		Fields: []*Field{
+			{ID: 1, Name: "id", Required: true, Type: TypeLong},
+			{ID: 2, Name: "d", Type: TypeDate},
+		},
+	}
+	table, err := catalog.CreateTable(ctx, "test", "t", schema, []string{"month(d)"})
+	require.NoError(t, err)
+
+	var files []*DataFile
+	for _, row := range [][]interface{}{{int64(1), int32(0)}, {int64(2), int32(40)}} {
+		partition, err := table.PartitionValues(schema, row)
+		require.NoError(t, err)
+		file, err := table.WriteDataFile(ctx, schema.Fields, [][]interface{}{row}, partition, ContentData, nil)
+		require.NoError(t, err)
+		require.Contains(t, file.Path, "/data/d_month=")
+		files = append(files, file)
+	}
+	require.NoError(t, table.Commit(ctx, schema, files, 100, false))
+
+	// the delete file only applies to the data files in the same partition.
+	deletes, err := table.WriteDataFile(ctx, schema.Fields[:1],
+		[][]interface{}{{int64(1)}, {int64(2)}}, []interface{}{int32(1)}, ContentEqualityDeletes, []int{1})
+	require.NoError(t, err)
+	require.NoError(t, table.Commit(ctx, schema, []*DataFile{deletes}, 200, false))
+
+	rows, err := table.Scan(ctx, 200)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{{"id": int64(1), "d": int32(0)}}, rows)
+}
@@ -0,0 +1,337 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// The primitive types of Iceberg used by the sink.
+const (
+	TypeInt       = "int"
+	TypeLong      = "long"
+	TypeFloat     = "float"
+	TypeDouble    = "double"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+	TypeString    = "string"
+	TypeBinary    = "binary"
+
+	maxDecimalPrecision = 38
+	// the precision of BIGINT UNSIGNED and BIT(64).
+	uint64Precision = 20
+)
+
+var unixEpoch = time.Unix(0, 0).UTC()
+
+// ColumnType returns the Iceberg type of a TiDB column type.
+func ColumnType(ft *types.FieldType) string {
+	unsigned := mysql.HasUnsignedFlag(ft.GetFlag())
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeYear:
+		return TypeInt
+	case mysql.TypeLong:
+		if unsigned {
+			return TypeLong
+		}
+		return TypeInt
+	case mysql.TypeLonglong:
+		if unsigned {
+			return decimalType(uint64Precision, 0)
+		}
+		return TypeLong
+	case mysql.TypeBit:
+		if ft.GetFlen() >= 64 {
+			return decimalType(uint64Precision, 0)
+		}
+		return TypeLong
+	case mysql.TypeFloat:
+		return TypeFloat
+	case mysql.TypeDouble:
+		return TypeDouble
+	case mysql.TypeNewDecimal:
+		// The decimals which can't be represented by Iceberg are stored as
+		// strings.
+		if ft.GetFlen() <= 0 || ft.GetFlen() > maxDecimalPrecision {
+			return TypeString
+		}
+		return decimalType(ft.GetFlen(), ft.GetDecimal())
+	case mysql.TypeDate:
+		return TypeDate
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		// The values are written without time zone conversion, so that they
+		// are the same as they are displayed in TiDB.
+		return TypeTimestamp
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob,
+		mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString:
+		if ft.GetCharset() == charset.CharsetBin {
+			return TypeBinary
+		}
+		return TypeString
+	default:
+		// TIME, JSON, ENUM, SET are stored as strings.
+		return TypeString
+	}
+}
+
+func decimalType(precision, scale int) string {
+	if scale < 0 {
+		scale = 0
+	}
+	return fmt.Sprintf("decimal(%d, %d)", precision, scale)
+}
+
+// parseDecimalType returns the precision and scale of a decimal type.
+func parseDecimalType(tp string) (precision, scale int, ok bool) {
+	if !strings.HasPrefix(tp, "decimal(") {
+		return 0, 0, false
+	}
+	_, err := fmt.Sscanf(tp, "decimal(%d, %d)", &precision, &scale)
+	return precision, scale, err == nil
+}
+
+// ConvertValue converts a column value of a RowChangedEvent to the physical
+// value of the Parquet column of the Iceberg type returned by ColumnType.
+func ConvertValue(ft *types.FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	tp := ColumnType(ft)
+	if precision, scale, ok := parseDecimalType(tp); ok {
+		return convertDecimal(value, precision, scale)
+	}
+	switch tp {
+	case TypeInt:
+		switch v := value.(type) {
+		case int64:
+			return int32(v), nil
+		case uint64:
+			return int32(v), nil
+		}
+	case TypeLong:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case uint64:
+			return int64(v), nil
+		}
+	case TypeFloat:
+		switch v := value.(type) {
+		case float32:
+			return v, nil
+		case float64:
+			return float32(v), nil
+		}
+	case TypeDouble:
+		switch v := value.(type) {
+		case float32:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case TypeDate, TypeTimestamp:
+		if v, ok := value.(string); ok {
+			return convertTime(ft, tp, v)
+		}
+	case TypeString:
+		switch ft.GetType() {
+		case mysql.TypeEnum:
+			if v, ok := value.(uint64); ok {
+				enum, err := types.ParseEnumValue(ft.GetElems(), v)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				return enum.Name, nil
+			}
+		case mysql.TypeSet:
+			if v, ok := value.(uint64); ok {
+				set, err := types.ParseSetValue(ft.GetElems(), v)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				return set.Name, nil
+			}
+		}
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		}
+	case TypeBinary:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		}
+	}
+	return nil, errors.Errorf("unexpected value %v of type %T for %s column", value, value, tp)
+}
+
+// convertDecimal converts a decimal to the fixed-length big-endian two's
+// complement bytes of its unscaled value.
+func convertDecimal(value interface{}, precision, scale int) (interface{}, error) {
+	var unscaled *big.Int
+	switch v := value.(type) {
+	case string:
+		d, err := decimal.NewFromString(v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unscaled = d.Shift(int32(scale)).BigInt()
+	case uint64:
+		unscaled = new(big.Int).SetUint64(v)
+	case int64:
+		unscaled = big.NewInt(v)
+	default:
+		return nil, errors.Errorf("unexpected value %v of type %T for decimal column", value, value)
+	}
+	return string(fixedBytes(unscaled, decimalLength(precision))), nil
+}
+
+// decimalLength returns the minimal number of bytes to store the unscaled
+// value of a decimal with the precision.
+func decimalLength(precision int) int {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	// one more bit for the sign.
+	return (limit.BitLen() + 1 + 7) / 8
+}
+
+// fixedBytes returns the big-endian two's complement bytes of n with the
+// length.
+func fixedBytes(n *big.Int, length int) []byte {
+	buf := make([]byte, length)
+	if n.Sign() >= 0 {
+		n.FillBytes(buf)
+		return buf
+	}
+	// the two's complement of a negative number is 2^(8*length) + n.
+	complement := new(big.Int).Lsh(big.NewInt(1), uint(8*length))
+	complement.Add(complement, n)
+	complement.FillBytes(buf)
+	return buf
+}
+
+// minimalBytes returns the minimal big-endian two's complement bytes of n,
+// which is the same as BigInteger.toByteArray of Java.
+func minimalBytes(n *big.Int) []byte {
+	bitLen := n.BitLen()
+	if n.Sign() < 0 {
+		// the bit length of a negative number excludes the sign bit in Java,
+		// e.g. -128 is stored in 1 byte.
+		bitLen = new(big.Int).Not(n).BitLen()
+	}
+	return fixedBytes(n, bitLen/8+1)
+}
+
+// unscaledValue parses the bytes returned by fixedBytes.
+func unscaledValue(b []byte) *big.Int {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return n
+}
+
+func convertTime(ft *types.FieldType, tp string, value string) (interface{}, error) {
+	// Iceberg has no zero dates of MySQL.
+	if strings.HasPrefix(value, "0000-00-00") {
+		if !mysql.HasNotNullFlag(ft.GetFlag()) {
+			return nil, nil
+		}
+		value = ""
+	}
+	t := unixEpoch
+	if value != "" {
+		layout := "2006-01-02 15:04:05.999999"
+		if tp == TypeDate {
+			layout = "2006-01-02"
+		}
+		var err error
+		t, err = time.ParseInLocation(layout, value, time.UTC)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if tp == TypeDate {
+		return int32(floorDiv(t.Unix(), 24*3600)), nil
+	}
+	return t.UnixMicro(), nil
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// parquetTag returns the tag of a Parquet column of the parquet-go writer.
+// All the columns are optional, which is required by the CSV writer of
+// parquet-go, and the required fields of Iceberg have no null values.
+func parquetTag(field *Field) string {
+	var tag string
+	if precision, scale, ok := parseDecimalType(field.Type); ok {
+		tag = fmt.Sprintf("type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, length=%d, "+
+			"precision=%d, scale=%d", decimalLength(precision), precision, scale)
+	} else {
+		switch field.Type {
+		case TypeInt:
+			tag = "type=INT32"
+		case TypeLong:
+			tag = "type=INT64"
+		case TypeFloat:
+			tag = "type=FLOAT"
+		case TypeDouble:
+			tag = "type=DOUBLE"
+		case TypeDate:
+			tag = "type=INT32, convertedtype=DATE"
+		case TypeTimestamp:
+			tag = "type=INT64, convertedtype=TIMESTAMP_MICROS"
+		case TypeString:
+			tag = "type=BYTE_ARRAY, convertedtype=UTF8"
+		default:
+			tag = "type=BYTE_ARRAY"
+		}
+	}
+	return fmt.Sprintf("name=%s, %s, fieldid=%d, repetitiontype=OPTIONAL",
+		parquetName(field), tag, field.ID)
+}
+
+// parquetName returns the name of the Parquet column of a field. The
+// columns are matched by the field IDs by Iceberg, so the names which can't
+// be used in the tags of parquet-go are replaced.
+func parquetName(field *Field) string {
+	for i, r := range field.Name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return fmt.Sprintf("_col%d", field.ID)
+	}
+	if field.Name == "" {
+		return fmt.Sprintf("_col%d", field.ID)
+	}
+	return field.Name
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newFieldType(tp byte, flag uint) *types.FieldType {
+	ft := types.NewFieldType(tp)
+	ft.AddFlag(flag)
+	return ft
+}
+
+func TestColumnType(t *testing.T) {
+	t.Parallel()
+
+	decimalType := newFieldType(mysql.TypeNewDecimal, 0)
+	decimalType.SetFlen(10)
+	decimalType.SetDecimal(2)
+	largeDecimalType := newFieldType(mysql.TypeNewDecimal, 0)
+	largeDecimalType.SetFlen(65)
+	binaryType := newFieldType(mysql.TypeVarchar, mysql.BinaryFlag)
+	binaryType.SetCharset(charset.CharsetBin)
+
+	cases := []struct {
+		ft       *types.FieldType
+		expected string
+	}{
+		{newFieldType(mysql.TypeTiny, 0), TypeInt},
+		{newFieldType(mysql.TypeLong, 0), TypeInt},
+		{newFieldType(mysql.TypeLong, mysql.UnsignedFlag), TypeLong},
+		{newFieldType(mysql.TypeLonglong, 0), TypeLong},
+		{newFieldType(mysql.TypeLonglong, mysql.UnsignedFlag), "decimal(20, 0)"},
+		{newFieldType(mysql.TypeDouble, 0), TypeDouble},
+		{decimalType, "decimal(10, 2)"},
+		{largeDecimalType, TypeString},
+		{newFieldType(mysql.TypeDate, 0), TypeDate},
+		{newFieldType(mysql.TypeDatetime, 0), TypeTimestamp},
+		{newFieldType(mysql.TypeVarchar, 0), TypeString},
+		{binaryType, TypeBinary},
+		{newFieldType(mysql.TypeJSON, 0), TypeString},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, ColumnType(c.ft), c.ft.String())
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	t.Parallel()
+
+	decimalType := newFieldType(mysql.TypeNewDecimal, 0)
+	decimalType.SetFlen(10)
+	decimalType.SetDecimal(2)
+	enumType := newFieldType(mysql.TypeEnum, 0)
+	enumType.SetElems([]string{"a", "b"})
+
+	cases := []struct {
+		ft       *types.FieldType
+		value    interface{}
+		expected interface{}
+	}{
+		{newFieldType(mysql.TypeLong, 0), int64(-1), int32(-1)},
+		{newFieldType(mysql.TypeLonglong, 0), int64(1), int64(1)},
+		{newFieldType(mysql.TypeFloat, 0), float64(1.5), float32(1.5)},
+		{decimalType, "-1.5", "\xff\xff\xff\xff\x6a"},
+		{decimalType, "14.2", "\x00\x00\x00\x05\x8c"},
+		{newFieldType(mysql.TypeLonglong, mysql.UnsignedFlag), uint64(1), "\x00\x00\x00\x00\x00\x00\x00\x00\x01"},
+		{newFieldType(mysql.TypeDate, 0), "2017-11-16", int32(17486)},
+		{newFieldType(mysql.TypeDatetime, 0), "2017-11-16 22:31:08", int64(1510871468000000)},
+		{newFieldType(mysql.TypeDatetime, 0), "0000-00-00 00:00:00", nil},
+		{newFieldType(mysql.TypeDatetime, mysql.NotNullFlag), "0000-00-00 00:00:00", int64(0)},
+		{newFieldType(mysql.TypeVarchar, 0), []byte("iceberg"), "iceberg"},
+		{enumType, uint64(2), "b"},
+		{newFieldType(mysql.TypeLong, 0), nil, nil},
+	}
+	for _, c := range cases {
+		value, err := ConvertValue(c.ft, c.value)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, value, c.ft.String())
+	}
+
+	_, err := ConvertValue(newFieldType(mysql.TypeLong, 0), "1")
+	require.ErrorContains(t, err, "unexpected value")
+}
+
+func TestMinimalBytes(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []byte{0x00}, minimalBytes(big.NewInt(0)))
+	require.Equal(t, []byte{0x80}, minimalBytes(big.NewInt(-128)))
+	require.Equal(t, []byte{0x00, 0x80}, minimalBytes(big.NewInt(128)))
+	require.Equal(t, []byte{0xff, 0x7f}, minimalBytes(big.NewInt(-129)))
+	require.Equal(t, []byte{0x05, 0x8c}, minimalBytes(big.NewInt(1420)))
+	require.Equal(t, big.NewInt(-150), unscaledValue([]byte{0xff, 0xff, 0xff, 0xff, 0x6a}))
+}
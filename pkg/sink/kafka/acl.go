@@ -0,0 +1,127 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"go.uber.org/zap"
+)
+
+const (
+	// clusterResourceName is the name of the only cluster resource in Kafka.
+	clusterResourceName = "kafka-cluster"
+	// wildcardPrincipal matches all principals in an ACL.
+	wildcardPrincipal = "User:*"
+)
+
+// aclPrincipal returns the Kafka principal the changefeed authenticates as,
+// it's empty if the principal can't be known from the sink options.
+func aclPrincipal(o *Options) string {
+	if o.SASL == nil || o.SASL.SASLUser == "" {
+		return ""
+	}
+	switch o.SASL.SASLMechanism {
+	case security.PlainMechanism, security.SCRAM256Mechanism, security.SCRAM512Mechanism:
+		return "User:" + o.SASL.SASLUser
+	}
+	return ""
+}
+
+// validateKafkaACLs checks the principal has the `WRITE` ACL on the topic
+// and the `DESCRIBE` ACL on the cluster, which are required by the sink.
+func validateKafkaACLs(
+	_ context.Context, adminClient sarama.ClusterAdmin, topic, principal string,
+) error {
+	checks := []struct {
+		resourceType sarama.AclResourceType
+		resourceName string
+		operation    sarama.AclOperation
+	}{
+		{sarama.AclResourceTopic, topic, sarama.AclOperationWrite},
+		{sarama.AclResourceCluster, clusterResourceName, sarama.AclOperationDescribe},
+	}
+	for _, check := range checks {
+		resourceName := check.resourceName
+		resourceAcls, err := adminClient.ListAcls(sarama.AclFilter{
+			ResourceType:              check.resourceType,
+			ResourceName:              &resourceName,
+			ResourcePatternTypeFilter: sarama.AclPatternMatch,
+			Operation:                 sarama.AclOperationAny,
+			PermissionType:            sarama.AclPermissionAny,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// The response is empty if the authorizer is not enabled in the
+		// Kafka cluster, in which case the ACLs can't be validated.
+		if len(resourceAcls) == 0 {
+			log.Warn("no kafka ACL found, skip validating ACLs",
+				zap.String("resource", resourceName),
+				zap.String("principal", principal))
+			continue
+		}
+		if !aclAllowed(resourceAcls, principal, check.operation) {
+			return cerror.ErrKafkaMissingACL.GenWithStackByArgs(
+				principal, check.operation.String(), check.resourceType.String(), resourceName)
+		}
+	}
+	return nil
+}
+
+// aclAllowed returns true if the operation is allowed for the principal,
+// a matched `DENY` ACL takes precedence over the `ALLOW` ones.
+func aclAllowed(
+	resourceAcls []sarama.ResourceAcls, principal string, operation sarama.AclOperation,
+) bool {
+	allowed := false
+	for _, resource := range resourceAcls {
+		for _, acl := range resource.Acls {
+			if acl.Principal != principal && acl.Principal != wildcardPrincipal {
+				continue
+			}
+			if !aclImplies(acl.Operation, operation) {
+				continue
+			}
+			switch acl.PermissionType {
+			case sarama.AclPermissionDeny:
+				return false
+			case sarama.AclPermissionAllow:
+				allowed = true
+			}
+		}
+	}
+	return allowed
+}
+
+// aclImplies returns true if an ACL on the granted operation also grants
+// the wanted operation, e.g. `WRITE` implies `DESCRIBE` in Kafka.
+func aclImplies(granted, wanted sarama.AclOperation) bool {
+	if granted == wanted || granted == sarama.AclOperationAll {
+		return true
+	}
+	if wanted == sarama.AclOperationDescribe {
+		switch granted {
+		case sarama.AclOperationRead, sarama.AclOperationWrite,
+			sarama.AclOperationDelete, sarama.AclOperationAlter:
+			return true
+		}
+	}
+	return false
+}
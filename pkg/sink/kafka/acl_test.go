@@ -0,0 +1,152 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+)
+
+// mockACLAdmin returns the configured ACLs of each resource type.
+type mockACLAdmin struct {
+	sarama.ClusterAdmin
+	acls map[sarama.AclResourceType][]sarama.ResourceAcls
+}
+
+func (m *mockACLAdmin) ListAcls(filter sarama.AclFilter) ([]sarama.ResourceAcls, error) {
+	return m.acls[filter.ResourceType], nil
+}
+
+func newResourceAcls(
+	resourceType sarama.AclResourceType, name string, acls ...*sarama.Acl,
+) []sarama.ResourceAcls {
+	return []sarama.ResourceAcls{{
+		Resource: sarama.Resource{
+			ResourceType:        resourceType,
+			ResourceName:        name,
+			ResourcePatternType: sarama.AclPatternLiteral,
+		},
+		Acls: acls,
+	}}
+}
+
+func TestValidateKafkaACLs(t *testing.T) {
+	t.Parallel()
+
+	const (
+		topic     = "test"
+		principal = "User:ticdc"
+	)
+	allow := func(principal string, op sarama.AclOperation) *sarama.Acl {
+		return &sarama.Acl{
+			Principal: principal, Host: "*",
+			Operation: op, PermissionType: sarama.AclPermissionAllow,
+		}
+	}
+	deny := func(principal string, op sarama.AclOperation) *sarama.Acl {
+		return &sarama.Acl{
+			Principal: principal, Host: "*",
+			Operation: op, PermissionType: sarama.AclPermissionDeny,
+		}
+	}
+	cluster := newResourceAcls(sarama.AclResourceCluster, clusterResourceName,
+		allow(principal, sarama.AclOperationDescribe))
+
+	cases := []struct {
+		name    string
+		acls    map[sarama.AclResourceType][]sarama.ResourceAcls
+		missing string
+	}{
+		{
+			name: "allowed",
+			acls: map[sarama.AclResourceType][]sarama.ResourceAcls{
+				sarama.AclResourceTopic: newResourceAcls(sarama.AclResourceTopic, topic,
+					allow(principal, sarama.AclOperationWrite)),
+				sarama.AclResourceCluster: cluster,
+			},
+		},
+		{
+			name: "allowed by wildcard principal and implied operation",
+			acls: map[sarama.AclResourceType][]sarama.ResourceAcls{
+				sarama.AclResourceTopic: newResourceAcls(sarama.AclResourceTopic, topic,
+					allow(wildcardPrincipal, sarama.AclOperationAll)),
+				sarama.AclResourceCluster: newResourceAcls(sarama.AclResourceCluster, clusterResourceName,
+					allow(principal, sarama.AclOperationAlter)),
+			},
+		},
+		{
+			name: "no acl defined",
+		},
+		{
+			name: "missing write on topic",
+			acls: map[sarama.AclResourceType][]sarama.ResourceAcls{
+				sarama.AclResourceTopic: newResourceAcls(sarama.AclResourceTopic, topic,
+					allow(principal, sarama.AclOperationRead),
+					allow("User:other", sarama.AclOperationWrite)),
+				sarama.AclResourceCluster: cluster,
+			},
+			missing: "missing the Write ACL on the Topic test",
+		},
+		{
+			name: "write on topic is denied",
+			acls: map[sarama.AclResourceType][]sarama.ResourceAcls{
+				sarama.AclResourceTopic: newResourceAcls(sarama.AclResourceTopic, topic,
+					allow(principal, sarama.AclOperationWrite),
+					deny(wildcardPrincipal, sarama.AclOperationWrite)),
+				sarama.AclResourceCluster: cluster,
+			},
+			missing: "missing the Write ACL on the Topic test",
+		},
+		{
+			name: "missing describe on cluster",
+			acls: map[sarama.AclResourceType][]sarama.ResourceAcls{
+				sarama.AclResourceTopic: newResourceAcls(sarama.AclResourceTopic, topic,
+					allow(principal, sarama.AclOperationWrite)),
+				sarama.AclResourceCluster: newResourceAcls(sarama.AclResourceCluster, clusterResourceName,
+					allow(principal, sarama.AclOperationCreate)),
+			},
+			missing: "missing the Describe ACL on the Cluster kafka-cluster",
+		},
+	}
+	for _, c := range cases {
+		admin := &mockACLAdmin{acls: c.acls}
+		err := validateKafkaACLs(context.Background(), admin, topic, principal)
+		if c.missing == "" {
+			require.NoError(t, err, c.name)
+			continue
+		}
+		require.True(t, cerror.ErrKafkaMissingACL.Equal(err), c.name)
+		require.ErrorContains(t, err, c.missing, c.name)
+	}
+}
+
+func TestACLPrincipal(t *testing.T) {
+	t.Parallel()
+
+	options := NewOptions()
+	require.Empty(t, aclPrincipal(options))
+
+	options.SASL.SASLUser = "ticdc"
+	options.SASL.SASLMechanism = security.SCRAM512Mechanism
+	require.Equal(t, "User:ticdc", aclPrincipal(options))
+
+	// The principal of the other mechanisms depends on the broker.
+	options.SASL.SASLMechanism = security.GSSAPIMechanism
+	require.Empty(t, aclPrincipal(options))
+}
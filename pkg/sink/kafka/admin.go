@@ -175,6 +175,12 @@ func (a *saramaAdminClient) CreateTopic(
 	return nil
 }
 
+func (a *saramaAdminClient) ValidateACLs(
+	ctx context.Context, topic string, principal string,
+) error {
+	return validateKafkaACLs(ctx, a.admin, topic, principal)
+}
+
 func (a *saramaAdminClient) Close() {
 	if err := a.admin.Close(); err != nil {
 		log.Warn("close admin client meet error",
@@ -89,9 +89,13 @@ func (c *ClaimCheck) WriteMessage(ctx context.Context, key, value []byte, fileNa
 	if err != nil {
 		return errors.Trace(err)
 	}
+	return c.WriteValue(ctx, data, fileName)
+}
 
+// WriteValue write the raw value to the claim check external storage.
+func (c *ClaimCheck) WriteValue(ctx context.Context, value []byte, fileName string) error {
 	start := time.Now()
-	err = c.storage.WriteFile(ctx, fileName, data)
+	err := c.storage.WriteFile(ctx, fileName, value)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -118,3 +122,9 @@ func (c *ClaimCheck) CleanMetrics() {
 func NewFileName() string {
 	return uuid.NewString() + ".json"
 }
+
+// NewValueFileName return the file name for the large column value which is
+// delivered to the external storage system.
+func NewValueFileName() string {
+	return uuid.NewString() + ".value"
+}
@@ -52,6 +52,9 @@ type ClusterAdminClient interface {
 	// CreateTopic creates a new topic.
 	CreateTopic(ctx context.Context, detail *TopicDetail, validateOnly bool) error
 
+	// ValidateACLs checks the principal has the ACLs required to write the topic.
+	ValidateACLs(ctx context.Context, topic string, principal string) error
+
 	// Close shuts down the admin client.
 	Close()
 }
@@ -201,6 +201,11 @@ func (c *ClusterAdminClientMockImpl) DeleteTopic(topicName string) {
 	delete(c.topics, topicName)
 }
 
+// ValidateACLs do nothing.
+func (c *ClusterAdminClientMockImpl) ValidateACLs(context.Context, string, string) error {
+	return nil
+}
+
 // Close do nothing.
 func (c *ClusterAdminClientMockImpl) Close() {}
 
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -168,6 +169,8 @@ type saramaAsyncProducer struct {
 	producer     sarama.AsyncProducer
 	changefeedID model.ChangeFeedID
 	failpointCh  chan error
+
+	uncompressedBytes prometheus.Counter
 }
 
 func (p *saramaAsyncProducer) Close() {
@@ -278,5 +281,6 @@ func (p *saramaAsyncProducer) AsyncSend(ctx context.Context,
 		return errors.Trace(ctx.Err())
 	case p.producer.Input() <- msg:
 	}
+	p.uncompressedBytes.Add(float64(len(key) + len(value)))
 	return nil
 }
@@ -59,6 +59,29 @@ var (
 			Help:      "The compression ratio times 100 of record batches for all topics.",
 		}, []string{"namespace", "changefeed"})
 
+	// uncompressedBytesCounter counts the bytes of key and value of the
+	// messages sent to the producer, before compression.
+	uncompressedBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "kafka_producer_uncompressed_bytes",
+			Help:      "The total bytes of messages before compression.",
+		}, []string{"namespace", "changefeed"})
+
+	// updated by the count of `outgoing-byte-rate`. It counts the bytes of all
+	// requests written to the brokers, so besides the compressed record
+	// batches, it also includes the headers of produce requests and the bytes
+	// of metadata requests.
+	compressedBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "kafka_producer_compressed_bytes",
+			Help: "The total bytes of requests written to all brokers, " +
+				"including the compressed record batches and the request headers.",
+		}, []string{"namespace", "changefeed"})
+
 	// updated by `records-per-request`.
 	recordsPerRequestGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -127,6 +150,8 @@ var (
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(compressionRatioGauge)
 	registry.MustRegister(recordsPerRequestGauge)
+	registry.MustRegister(uncompressedBytesCounter)
+	registry.MustRegister(compressedBytesCounter)
 	registry.MustRegister(OutgoingByteRateGauge)
 	registry.MustRegister(RequestRateGauge)
 	registry.MustRegister(RequestLatencyGauge)
@@ -44,6 +44,7 @@ const (
 	// Producer level.
 	compressionRatioMetricName  = "compression-ratio"
 	recordsPerRequestMetricName = "records-per-request"
+	outgoingByteRateMetricName  = "outgoing-byte-rate"
 
 	// Broker level.
 	outgoingByteRateMetricNamePrefix   = "outgoing-byte-rate-for-broker-"
@@ -60,6 +61,9 @@ type saramaMetricsCollector struct {
 	adminClient ClusterAdminClient
 	brokers     map[int32]struct{}
 	registry    metrics.Registry
+	// lastOutgoingBytes is the count of `outgoing-byte-rate` at the last
+	// collection, used to increase the compressed bytes counter.
+	lastOutgoingBytes int64
 }
 
 // NewSaramaMetricsCollector return a kafka metrics collector based on sarama library.
@@ -142,6 +146,17 @@ func (m *saramaMetricsCollector) collectProducerMetrics() {
 			WithLabelValues(namespace, changefeedID).
 			Set(histogram.Snapshot().Mean())
 	}
+
+	outgoingByteRateMetric := m.registry.Get(outgoingByteRateMetricName)
+	if meter, ok := outgoingByteRateMetric.(metrics.Meter); ok {
+		count := meter.Snapshot().Count()
+		if count > m.lastOutgoingBytes {
+			compressedBytesCounter.
+				WithLabelValues(namespace, changefeedID).
+				Add(float64(count - m.lastOutgoingBytes))
+		}
+		m.lastOutgoingBytes = count
+	}
 }
 
 func (m *saramaMetricsCollector) collectBrokerMetrics() {
@@ -201,6 +216,10 @@ func (m *saramaMetricsCollector) cleanupProducerMetrics() {
 		DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
 	recordsPerRequestGauge.
 		DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	uncompressedBytesCounter.
+		DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	compressedBytesCounter.
+		DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
 }
 
 func (m *saramaMetricsCollector) cleanupBrokerMetrics() {
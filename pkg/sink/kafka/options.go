@@ -116,6 +116,7 @@ type urlConfig struct {
 	KafkaVersion                 *string `form:"kafka-version"`
 	MaxMessageBytes              *int    `form:"max-message-bytes"`
 	Compression                  *string `form:"compression"`
+	CompressionLevel             *int    `form:"compression-level"`
 	KafkaClientID                *string `form:"kafka-client-id"`
 	AutoCreateTopic              *bool   `form:"auto-create-topic"`
 	DialTimeout                  *string `form:"dial-timeout"`
@@ -152,6 +153,7 @@ type Options struct {
 	Version           string
 	MaxMessageBytes   int
 	Compression       string
+	CompressionLevel  int
 	ClientID          string
 	RequiredAcks      RequiredAcks
 	// Only for test. User can not set this value.
@@ -256,6 +258,13 @@ func (o *Options) Apply(changefeedID model.ChangeFeedID,
 		o.Compression = *urlParameter.Compression
 	}
 
+	if urlParameter.CompressionLevel != nil {
+		o.CompressionLevel = *urlParameter.CompressionLevel
+		if err := validateCompressionLevel(o.Compression, o.CompressionLevel); err != nil {
+			return err
+		}
+	}
+
 	var kafkaClientID string
 	if urlParameter.KafkaClientID != nil {
 		kafkaClientID = *urlParameter.KafkaClientID
@@ -330,6 +339,7 @@ func mergeConfig(
 		dest.KafkaVersion = fileConifg.KafkaVersion
 		dest.MaxMessageBytes = fileConifg.MaxMessageBytes
 		dest.Compression = fileConifg.Compression
+		dest.CompressionLevel = fileConifg.CompressionLevel
 		dest.KafkaClientID = fileConifg.KafkaClientID
 		dest.AutoCreateTopic = fileConifg.AutoCreateTopic
 		dest.DialTimeout = fileConifg.DialTimeout
@@ -359,6 +369,30 @@ func mergeConfig(
 	return dest, nil
 }
 
+// validateCompressionLevel checks the compression level, which is only
+// supported by gzip and zstd. 0 means the default level of the codec.
+func validateCompressionLevel(compression string, level int) error {
+	if level == 0 {
+		return nil
+	}
+	var minLevel, maxLevel int
+	switch strings.ToLower(strings.TrimSpace(compression)) {
+	case "gzip":
+		minLevel, maxLevel = 1, 9
+	case "zstd":
+		minLevel, maxLevel = 1, 22
+	default:
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"compression-level is not supported by the compression %s", compression)
+	}
+	if level < minLevel || level > maxLevel {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"compression-level of %s should be in [%d, %d], got %d",
+			compression, minLevel, maxLevel, level)
+	}
+	return nil
+}
+
 func (o *Options) applyTLS(params *urlConfig) error {
 	if params.CA != nil && *params.CA != "" {
 		o.Credential.CAPath = *params.CA
@@ -570,6 +604,12 @@ func AdjustOptions(
 	options *Options,
 	topic string,
 ) error {
+	if principal := aclPrincipal(options); principal != "" {
+		if err := admin.ValidateACLs(ctx, topic, principal); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	topics, err := admin.GetTopicsMeta(ctx, []string{topic}, true)
 	if err != nil {
 		return errors.Trace(err)
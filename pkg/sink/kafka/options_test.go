@@ -191,6 +191,38 @@ func TestTimeout(t *testing.T) {
 	require.Equal(t, 2*time.Minute, options.WriteTimeout)
 }
 
+func TestCompressionLevel(t *testing.T) {
+	cases := []struct {
+		uri      string
+		expected int
+		err      string
+	}{
+		{"kafka://127.0.0.1:9092/abc?compression=zstd", 0, ""},
+		{"kafka://127.0.0.1:9092/abc?compression=zstd&compression-level=1", 1, ""},
+		{"kafka://127.0.0.1:9092/abc?compression=zstd&compression-level=22", 22, ""},
+		{"kafka://127.0.0.1:9092/abc?compression=gzip&compression-level=9", 9, ""},
+		{"kafka://127.0.0.1:9092/abc?compression=zstd&compression-level=23", 0, ".*should be in \\[1, 22\\].*"},
+		{"kafka://127.0.0.1:9092/abc?compression=gzip&compression-level=-1", 0, ".*should be in \\[1, 9\\].*"},
+		// 0 means the default level of the codec.
+		{"kafka://127.0.0.1:9092/abc?compression=gzip&compression-level=0", 0, ""},
+		{"kafka://127.0.0.1:9092/abc?compression=lz4&compression-level=0", 0, ""},
+		{"kafka://127.0.0.1:9092/abc?compression=lz4&compression-level=3", 0, ".*not supported by the compression lz4.*"},
+		{"kafka://127.0.0.1:9092/abc?compression-level=3", 0, ".*not supported by the compression none.*"},
+	}
+	for _, c := range cases {
+		sinkURI, err := url.Parse(c.uri)
+		require.NoError(t, err)
+		options := NewOptions()
+		err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+		if c.err != "" {
+			require.Regexp(t, c.err, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, c.expected, options.CompressionLevel)
+	}
+}
+
 func TestAdjustConfigTopicNotExist(t *testing.T) {
 	// When the topic does not exist, use the broker's configuration to create the topic.
 	adminClient := NewClusterAdminClientMockImpl()
@@ -655,6 +687,7 @@ func TestMerge(t *testing.T) {
 		KafkaVersion:              aws.String("3.1.2"),
 		MaxMessageBytes:           aws.Int(1024 * 1024),
 		Compression:               aws.String("gzip"),
+		CompressionLevel:          aws.Int(6),
 		KafkaClientID:             aws.String("test-id"),
 		AutoCreateTopic:           aws.Bool(true),
 		DialTimeout:               aws.String("1m1s"),
@@ -683,6 +716,7 @@ func TestMerge(t *testing.T) {
 	require.Equal(t, "3.1.2", c.Version)
 	require.Equal(t, 1024*1024, c.MaxMessageBytes)
 	require.Equal(t, "gzip", c.Compression)
+	require.Equal(t, 6, c.CompressionLevel)
 	require.Equal(t, "test-id", c.ClientID)
 	require.Equal(t, true, c.AutoCreate)
 	require.Equal(t, time.Minute+time.Second, c.DialTimeout)
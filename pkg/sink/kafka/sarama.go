@@ -95,6 +95,10 @@ func NewSaramaConfig(ctx context.Context, o *Options) (*sarama.Config, error) {
 	if config.Producer.Compression != sarama.CompressionNone {
 		log.Info("Kafka producer uses " + compression + " compression algorithm")
 	}
+	if o.CompressionLevel != 0 {
+		config.Producer.CompressionLevel = o.CompressionLevel
+		log.Info("Kafka producer uses compression level", zap.Int("level", o.CompressionLevel))
+	}
 
 	if o.EnableTLS {
 		// for SSL encryption with a trust CA certificate, we must populate the
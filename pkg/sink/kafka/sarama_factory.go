@@ -114,6 +114,8 @@ func (f *saramaFactory) AsyncProducer(
 		producer:     p,
 		changefeedID: f.changefeedID,
 		failpointCh:  failpointCh,
+		uncompressedBytes: uncompressedBytesCounter.
+			WithLabelValues(f.changefeedID.Namespace, f.changefeedID.ID),
 	}, nil
 }
 
@@ -230,6 +230,11 @@ func (a *admin) CreateTopic(
 	return nil
 }
 
+// ValidateACLs is not supported by the kafka-go client yet.
+func (a *admin) ValidateACLs(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
 func (a *admin) Close() {
 	log.Info("admin client start closing",
 		zap.String("namespace", a.changefeedID.Namespace),
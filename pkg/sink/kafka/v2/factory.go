@@ -197,6 +197,12 @@ func (f *factory) newWriter(async bool) *kafka.Writer {
 	log.Info("Kafka producer uses "+f.options.Compression+" compression algorithm",
 		zap.String("namespace", f.changefeedID.Namespace),
 		zap.String("changefeed", f.changefeedID.ID))
+	if f.options.CompressionLevel != 0 {
+		log.Warn("Kafka sink v2 does not support compression level, use the default level",
+			zap.String("namespace", f.changefeedID.Namespace),
+			zap.String("changefeed", f.changefeedID.ID),
+			zap.Int("compressionLevel", f.options.CompressionLevel))
+	}
 	return w
 }
 
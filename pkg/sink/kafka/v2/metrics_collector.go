@@ -85,6 +85,8 @@ func (m *MetricsCollector) collectMetrics() {
 		Set(statistics.WriteTime.Avg.Seconds())
 	pkafka.OutgoingByteRateGauge.WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID, "v2").
 		Set(float64(statistics.Bytes / 5))
+	// kafka-go does not expose the compressed size of messages, so the
+	// compressed and uncompressed bytes are only collected by sarama.
 
 	pkafka.ClientRetryGauge.WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID).
 		Set(float64(statistics.Retries))
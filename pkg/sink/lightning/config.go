@@ -0,0 +1,130 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lightning
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultStorage is the default scheme of the external storage of the
+	// CSV files.
+	DefaultStorage = sink.S3Scheme
+	// The upper limit of max worker counts.
+	maxWorkerCount = 64
+)
+
+type urlConfig struct {
+	WorkerCount *int    `form:"worker-count"`
+	Storage     *string `form:"storage"`
+}
+
+// Config is the configs for the CSV sink, which writes the files can be
+// imported by TiDB Lightning.
+type Config struct {
+	// StorageURI is the URI of the external storage where the files are
+	// written, e.g. `s3://bucket/prefix?endpoint=...`.
+	StorageURI  string
+	WorkerCount int
+}
+
+// NewConfig returns the default CSV sink config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount: sink.DefaultWorkerCount,
+	}
+}
+
+// Apply applies the sink URI parameters to the config. The sink URI is like
+// `csv://bucket/prefix?storage=s3&endpoint=...`, the parameters except the
+// ones of the CSV sink are passed to the external storage.
+func (c *Config) Apply(sinkURI *url.URL) error {
+	if sinkURI == nil {
+		return cerror.ErrCSVSinkInvalidConfig.GenWithStack("fail to open CSV sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsCSVScheme(scheme) {
+		return cerror.ErrCSVSinkInvalidConfig.GenWithStack(
+			"can't create CSV sink with unsupported scheme: %s", scheme)
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrCSVSinkInvalidConfig, err)
+	}
+
+	storage := DefaultStorage
+	if urlParameter.Storage != nil {
+		storage = strings.ToLower(*urlParameter.Storage)
+	}
+	if !sink.IsStorageScheme(storage) || storage == sink.CloudStorageNoopScheme {
+		return cerror.ErrCSVSinkInvalidConfig.GenWithStack(
+			"unsupported storage %s of the CSV files", storage)
+	}
+	query := sinkURI.Query()
+	query.Del("worker-count")
+	query.Del("storage")
+	storageURI := url.URL{
+		Scheme:   storage,
+		User:     sinkURI.User,
+		Host:     sinkURI.Host,
+		Path:     sinkURI.Path,
+		RawQuery: query.Encode(),
+	}
+	c.StorageURI = storageURI.String()
+
+	if urlParameter.WorkerCount != nil {
+		workerCount := *urlParameter.WorkerCount
+		if workerCount <= 0 {
+			return cerror.WrapError(cerror.ErrCSVSinkInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", workerCount))
+		}
+		if workerCount > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", workerCount), zap.Int("override", maxWorkerCount))
+			workerCount = maxWorkerCount
+		}
+		c.WorkerCount = workerCount
+	}
+	return nil
+}
+
+// DataFileName returns the name of the CSV file holding the rows of the
+// table committed at commitTs, it's `<db>.<table>.<commitTs>.csv`, which is
+// routed to the table by the default file routing rules of Lightning.
+func DataFileName(schema, table string, commitTs uint64) string {
+	return fmt.Sprintf("%s.%s.%d.csv", schema, table, commitTs)
+}
+
+// SchemaCreateFileName returns the name of the file holding the
+// CREATE DATABASE statement of the schema.
+func SchemaCreateFileName(schema string) string {
+	return fmt.Sprintf("%s-schema-create.sql", schema)
+}
+
+// TableSchemaFileName returns the name of the file holding the
+// CREATE TABLE statement of the table.
+func TableSchemaFileName(schema, table string) string {
+	return fmt.Sprintf("%s.%s-schema.sql", schema, table)
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lightning
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("csv://bucket/prefix?endpoint=http://127.0.0.1:9000&worker-count=8")
+	require.NoError(t, err)
+	cfg := NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI))
+	require.Equal(t, "s3://bucket/prefix?endpoint=http%3A%2F%2F127.0.0.1%3A9000", cfg.StorageURI)
+	require.Equal(t, 8, cfg.WorkerCount)
+
+	sinkURI, err = url.Parse("csv:///tmp/csv?storage=file&worker-count=1000")
+	require.NoError(t, err)
+	cfg = NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI))
+	require.Equal(t, "file:///tmp/csv", cfg.StorageURI)
+	require.Equal(t, maxWorkerCount, cfg.WorkerCount)
+
+	cases := map[string]string{
+		"s3://bucket/prefix":                   "unsupported scheme",
+		"csv://bucket/prefix?storage=noop":     "unsupported storage",
+		"csv://bucket/prefix?storage=kafka":    "unsupported storage",
+		"csv://bucket/prefix?worker-count=0":   "invalid worker-count",
+		"csv://bucket/prefix?worker-count=abc": "CSV sink config invalid",
+	}
+	for uri, expected := range cases {
+		sinkURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.ErrorContains(t, NewConfig().Apply(sinkURI), expected, uri)
+	}
+}
+
+func TestFileNames(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "test.t1.100.csv", DataFileName("test", "t1", 100))
+	require.Equal(t, "test-schema-create.sql", SchemaCreateFileName("test"))
+	require.Equal(t, "test.t1-schema.sql", TableSchemaFileName("test", "t1"))
+}
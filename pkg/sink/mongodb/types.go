@@ -0,0 +1,147 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ConvertValue converts a column value of a RowChangedEvent to the value of
+// a BSON document.
+func ConvertValue(ft *types.FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch ft.GetType() {
+	case mysql.TypeNewDecimal:
+		if v, ok := value.(string); ok {
+			// Decimal128 keeps the precision of decimals up to 34 digits, the
+			// larger ones are stored as strings.
+			if d, err := primitive.ParseDecimal128(v); err == nil {
+				return d, nil
+			}
+			return v, nil
+		}
+	case mysql.TypeJSON:
+		if v, ok := value.(string); ok {
+			return convertJSON(v)
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		if v, ok := value.(string); ok {
+			return convertTime(ft, v)
+		}
+	case mysql.TypeEnum:
+		if v, ok := value.(uint64); ok {
+			enum, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return enum.Name, nil
+		}
+	case mysql.TypeSet:
+		if v, ok := value.(uint64); ok {
+			set, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return set.Name, nil
+		}
+	}
+	switch v := value.(type) {
+	case uint64:
+		// BSON has no unsigned integers.
+		if v <= math.MaxInt64 {
+			return int64(v), nil
+		}
+		d, err := primitive.ParseDecimal128(strconv.FormatUint(v, 10))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return d, nil
+	case []byte:
+		// Binary values are stored as BSON binary data.
+		if ft.GetCharset() == charset.CharsetBin {
+			return v, nil
+		}
+		return string(v), nil
+	}
+	return value, nil
+}
+
+// convertTime converts a date or a datetime to a BSON date, which is in
+// milliseconds. The values are converted without time zone conversion, so
+// that they are the same as they are displayed in TiDB.
+func convertTime(ft *types.FieldType, value string) (interface{}, error) {
+	// BSON has no zero dates of MySQL.
+	if strings.HasPrefix(value, "0000-00-00") {
+		return nil, nil
+	}
+	layout := "2006-01-02 15:04:05.999999"
+	if ft.GetType() == mysql.TypeDate {
+		layout = "2006-01-02"
+	}
+	t, err := time.ParseInLocation(layout, value, time.UTC)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return primitive.NewDateTimeFromTime(t), nil
+}
+
+// convertJSON converts a JSON value to a BSON value, the integers are kept
+// as int64 if possible.
+func convertJSON(value string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(value))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return convertJSONValue(v), nil
+}
+
+func convertJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		doc := make(bson.M, len(v))
+		for key, elem := range v {
+			doc[key] = convertJSONValue(elem)
+		}
+		return doc
+	case []interface{}:
+		arr := make(bson.A, 0, len(v))
+		for _, elem := range v {
+			arr = append(arr, convertJSONValue(elem))
+		}
+		return arr
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestConvertValue(t *testing.T) {
+	t.Parallel()
+
+	v, err := ConvertValue(types.NewFieldType(mysql.TypeLong), nil)
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeNewDecimal), "-12.3400")
+	require.NoError(t, err)
+	require.IsType(t, primitive.Decimal128{}, v)
+	require.Equal(t, "-12.3400", v.(primitive.Decimal128).String())
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeJSON), `{"a":1,"b":[1.5,"x",true],"c":null}`)
+	require.NoError(t, err)
+	require.Equal(t, bson.M{"a": int64(1), "b": bson.A{1.5, "x", true}, "c": nil}, v)
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeDatetime), "2024-01-02 03:04:05.123456")
+	require.NoError(t, err)
+	require.Equal(t, primitive.NewDateTimeFromTime(
+		time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.UTC)), v)
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeDate), "2024-01-02")
+	require.NoError(t, err)
+	require.Equal(t, primitive.NewDateTimeFromTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), v)
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeTimestamp), "0000-00-00 00:00:00")
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	ft := types.NewFieldType(mysql.TypeEnum)
+	ft.SetElems([]string{"a", "b"})
+	v, err = ConvertValue(ft, uint64(2))
+	require.NoError(t, err)
+	require.Equal(t, "b", v)
+
+	ft = types.NewFieldType(mysql.TypeSet)
+	ft.SetElems([]string{"a", "b"})
+	v, err = ConvertValue(ft, uint64(3))
+	require.NoError(t, err)
+	require.Equal(t, "a,b", v)
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeLonglong), uint64(1))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), v)
+
+	v, err = ConvertValue(types.NewFieldType(mysql.TypeLonglong), uint64(math.MaxUint64))
+	require.NoError(t, err)
+	require.Equal(t, "18446744073709551615", v.(primitive.Decimal128).String())
+
+	ft = types.NewFieldType(mysql.TypeBlob)
+	ft.SetCharset(charset.CharsetBin)
+	v, err = ConvertValue(ft, []byte{0x1, 0x2})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2}, v)
+
+	ft = types.NewFieldType(mysql.TypeVarchar)
+	ft.SetCharset(charset.CharsetUTF8MB4)
+	v, err = ConvertValue(ft, []byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, "abc", v)
+}
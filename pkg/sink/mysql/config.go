@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/imdario/mergo"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser/charset"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -57,6 +59,11 @@ const (
 	maxMaxMultiUpdateRowCount = 256
 	// The upper limit of max multi update row size(8KB).
 	maxMaxMultiUpdateRowSize = 8192
+	// defaultMaxBatchWait is the default max duration to wait for more
+	// transactions before flushing a partial batch.
+	defaultMaxBatchWait = 10 * time.Millisecond
+	// The upper limit of max batch wait.
+	maxMaxBatchWait = 10 * time.Second
 
 	defaultTiDBTxnMode  = txnModeOptimistic
 	defaultReadTimeout  = "2m"
@@ -66,6 +73,7 @@ const (
 	defaultSafeMode       = false
 	defaultTxnIsolationRC = "READ-COMMITTED"
 	defaultCharacterSet   = "utf8mb4"
+	defaultCollation      = "utf8mb4_bin"
 
 	// BackoffBaseDelay indicates the base delay time for retrying.
 	BackoffBaseDelay = 500 * time.Millisecond
@@ -77,13 +85,58 @@ const (
 
 	// defaultcachePrepStmts is the default value of cachePrepStmts
 	defaultCachePrepStmts = true
+
+	// defaultMaxLockTimeoutRetries is the default max number of retries of a
+	// DML batch failed by lock wait timeout.
+	defaultMaxLockTimeoutRetries = 10
+
+	// DDLFallbackPolicyError fails the changefeed if a DDL is not supported
+	// by the downstream.
+	DDLFallbackPolicyError = "error"
+	// DDLFallbackPolicySkip skips the DDLs not supported by the downstream,
+	// the skipped DDLs are recorded in the downstream.
+	DDLFallbackPolicySkip = "skip"
+	// DDLFallbackPolicyEmulate tries to execute the DDLs not supported by the
+	// downstream, they are skipped if the downstream rejects them.
+	DDLFallbackPolicyEmulate = "emulate"
+
+	// AutoIncrementConflictPolicyError fails the changefeed if an inserted
+	// row collides with an existing row in the downstream.
+	AutoIncrementConflictPolicyError = "error"
+	// AutoIncrementConflictPolicyReassign overwrites the existing row by
+	// `REPLACE INTO`.
+	AutoIncrementConflictPolicyReassign = "reassign"
+	// AutoIncrementConflictPolicySkip keeps the existing row by `INSERT IGNORE`.
+	AutoIncrementConflictPolicySkip = "skip"
+
+	// defaultPtOSCWaitTimeout is the default max duration to delay the DDLs
+	// of a table since pt-online-schema-change starts on it.
+	defaultPtOSCWaitTimeout = time.Hour
 )
 
+// sessionVariableNameRegexp is used to validate names of session variables,
+// which are spliced into `SET` statements directly.
+var sessionVariableNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// driverDSNParams are the lower-cased DSN params recognized by the MySQL
+// driver. Session variables are passed as DSN params, so these names are
+// rejected, otherwise they change the behavior of the driver instead.
+var driverDSNParams = map[string]struct{}{
+	"allowallfiles": {}, "allowcleartextpasswords": {}, "allowfallbacktoplaintext": {},
+	"allownativepasswords": {}, "allowoldpasswords": {}, "charset": {},
+	"checkconnliveness": {}, "clientfoundrows": {}, "collation": {},
+	"columnswithalias": {}, "compress": {}, "interpolateparams": {}, "loc": {},
+	"maxallowedpacket": {}, "multistatements": {}, "parsetime": {},
+	"readtimeout": {}, "rejectreadonly": {}, "serverpubkey": {}, "strict": {},
+	"timeout": {}, "tls": {}, "writetimeout": {},
+}
+
 type urlConfig struct {
 	WorkerCount                  *int    `form:"worker-count"`
 	MaxTxnRow                    *int    `form:"max-txn-row"`
 	MaxMultiUpdateRowSize        *int    `form:"max-multi-update-row-size"`
 	MaxMultiUpdateRowCount       *int    `form:"max-multi-update-row"`
+	MaxBatchWaitMs               *int    `form:"max-batch-wait-ms"`
 	TiDBTxnMode                  *string `form:"tidb-txn-mode"`
 	SSLCa                        *string `form:"ssl-ca"`
 	SSLCert                      *string `form:"ssl-cert"`
@@ -104,6 +157,7 @@ type Config struct {
 	MaxTxnRow              int
 	MaxMultiUpdateRowCount int
 	MaxMultiUpdateRowSize  int
+	MaxBatchWait           time.Duration
 	tidbTxnMode            string
 	ReadTimeout            string
 	WriteTimeout           string
@@ -122,6 +176,59 @@ type Config struct {
 	BatchDMLEnable  bool
 	MultiStmtEnable bool
 	CachePrepStmts  bool
+	// SessionVariables are set on every new connection to the downstream.
+	SessionVariables map[string]string
+	// VerifyOrdering checks the CommitTs order of txns written to each table,
+	// it's only used in integration tests and requires WorkerCount to be 1.
+	VerifyOrdering bool
+	// DebugSQLPreview logs the EXPLAIN result of each DML batch for debugging.
+	DebugSQLPreview bool
+	// SchemaCompatibilityCheck compares the column types and the charset of
+	// downstream tables with the upstream and logs the mismatches.
+	SchemaCompatibilityCheck bool
+	// TrackBinlogPosition records the binlog position of a MySQL downstream
+	// after each DML batch.
+	TrackBinlogPosition bool
+	// PartitionDDLCompatibility rewrites partition DDLs to MySQL syntax
+	// when the downstream is not TiDB.
+	PartitionDDLCompatibility bool
+	// ConnectionPoolPerTable gives each table its own connection pool.
+	ConnectionPoolPerTable bool
+	// DDLFallbackPolicy decides how to handle the DDLs not supported by a
+	// MySQL downstream.
+	DDLFallbackPolicy string
+	// MaxLockTimeoutRetries is the max number of retries of a DML batch
+	// failed by lock wait timeout.
+	MaxLockTimeoutRetries int
+	// Charset and Collation are used by all connections to the downstream.
+	Charset   string
+	Collation string
+	// AuditSideChannel is the table recording each replicated row change,
+	// it's empty if the audit side channel is disabled.
+	AuditSideChannel string
+	// AuditSideChannelCompression compresses the payload of audit rows.
+	AuditSideChannelCompression bool
+	// TableNameMappings maps the lower case `schema.table` of the upstream
+	// tables to the downstream tables.
+	TableNameMappings map[string]*model.TableName
+	// PtOSCCompatibility delays the DDLs of a table while
+	// pt-online-schema-change is running on it in the downstream.
+	PtOSCCompatibility bool
+	// PtOSCWaitTimeout limits how long the DDLs are delayed.
+	PtOSCWaitTimeout time.Duration
+	// BatchDDLMode executes the `CREATE TABLE` DDLs in multi-statement batches.
+	BatchDDLMode bool
+	// CreateTableIfNotExists creates the missing downstream tables of the
+	// failed DMLs and retries them.
+	CreateTableIfNotExists bool
+	// UpstreamTiDBDSN is used to fetch the schema of the missing tables.
+	UpstreamTiDBDSN string
+	// AutoIncrementConflictPolicy decides how to insert the rows of the
+	// tables with an AUTO_INCREMENT column.
+	AutoIncrementConflictPolicy string
+	// ConditionalDDL makes the DDLs idempotent by adding `IF [NOT] EXISTS`
+	// or skipping the DDLs already applied to the downstream.
+	ConditionalDDL bool
 }
 
 // NewConfig returns the default mysql backend config.
@@ -131,6 +238,7 @@ func NewConfig() *Config {
 		MaxTxnRow:              DefaultMaxTxnRow,
 		MaxMultiUpdateRowCount: defaultMaxMultiUpdateRowCount,
 		MaxMultiUpdateRowSize:  defaultMaxMultiUpdateRowSize,
+		MaxBatchWait:           defaultMaxBatchWait,
 		tidbTxnMode:            defaultTiDBTxnMode,
 		ReadTimeout:            defaultReadTimeout,
 		WriteTimeout:           defaultWriteTimeout,
@@ -139,6 +247,13 @@ func NewConfig() *Config {
 		BatchDMLEnable:         defaultBatchDMLEnable,
 		MultiStmtEnable:        defaultMultiStmtEnable,
 		CachePrepStmts:         defaultCachePrepStmts,
+		DDLFallbackPolicy:      DDLFallbackPolicyError,
+		MaxLockTimeoutRetries:  defaultMaxLockTimeoutRetries,
+		Charset:                defaultCharacterSet,
+		Collation:              defaultCollation,
+		PtOSCWaitTimeout:       defaultPtOSCWaitTimeout,
+
+		AutoIncrementConflictPolicy: AutoIncrementConflictPolicyError,
 	}
 }
 
@@ -177,6 +292,9 @@ func (c *Config) Apply(
 	if err = getMaxMultiUpdateRowSize(urlParameter, &c.MaxMultiUpdateRowSize); err != nil {
 		return err
 	}
+	if err = getMaxBatchWait(urlParameter, &c.MaxBatchWait); err != nil {
+		return err
+	}
 	getTiDBTxnMode(urlParameter, &c.tidbTxnMode)
 	if err = getSSLCA(urlParameter, changefeedID, &c.TLS); err != nil {
 		return err
@@ -198,6 +316,41 @@ func (c *Config) Apply(
 	getBatchDMLEnable(urlParameter, &c.BatchDMLEnable)
 	getMultiStmtEnable(urlParameter, &c.MultiStmtEnable)
 	getCachePrepStmts(urlParameter, &c.CachePrepStmts)
+	if err = getSessionVariables(replicaConfig, &c.SessionVariables); err != nil {
+		return err
+	}
+	if err = getVerifyOrdering(replicaConfig, c.WorkerCount, &c.VerifyOrdering); err != nil {
+		return err
+	}
+	getDebugSQLPreview(replicaConfig, &c.DebugSQLPreview)
+	getSchemaCompatibilityCheck(replicaConfig, &c.SchemaCompatibilityCheck)
+	getTrackBinlogPosition(replicaConfig, &c.TrackBinlogPosition)
+	getPartitionDDLCompatibility(replicaConfig, &c.PartitionDDLCompatibility)
+	getConnectionPoolPerTable(replicaConfig, &c.ConnectionPoolPerTable)
+	if err = getDDLFallbackPolicy(replicaConfig, &c.DDLFallbackPolicy); err != nil {
+		return err
+	}
+	if err = getMaxLockTimeoutRetries(replicaConfig, &c.MaxLockTimeoutRetries); err != nil {
+		return err
+	}
+	if err = getCharsetAndCollation(replicaConfig, &c.Charset, &c.Collation); err != nil {
+		return err
+	}
+	if err = getAuditSideChannel(replicaConfig, &c.AuditSideChannel); err != nil {
+		return err
+	}
+	getAuditSideChannelCompression(replicaConfig, &c.AuditSideChannelCompression)
+	getTableNameMappings(replicaConfig, &c.TableNameMappings)
+	getPtOSCCompatibility(replicaConfig, &c.PtOSCCompatibility)
+	if err = getPtOSCWaitTimeout(replicaConfig, &c.PtOSCWaitTimeout); err != nil {
+		return err
+	}
+	getBatchDDLMode(replicaConfig, &c.BatchDDLMode)
+	getCreateTableIfNotExists(replicaConfig, &c.CreateTableIfNotExists, &c.UpstreamTiDBDSN)
+	if err = getAutoIncrementConflictPolicy(replicaConfig, &c.AutoIncrementConflictPolicy); err != nil {
+		return err
+	}
+	getConditionalDDL(replicaConfig, &c.ConditionalDDL)
 	c.ForceReplicate = replicaConfig.ForceReplicate
 	c.SourceID = replicaConfig.Sink.TiDBSourceID
 
@@ -216,6 +369,7 @@ func mergeConfig(
 		dest.MaxTxnRow = mConfig.MaxTxnRow
 		dest.MaxMultiUpdateRowCount = mConfig.MaxMultiUpdateRowCount
 		dest.MaxMultiUpdateRowSize = mConfig.MaxMultiUpdateRowSize
+		dest.MaxBatchWaitMs = mConfig.MaxBatchWaitMs
 		dest.TiDBTxnMode = mConfig.TiDBTxnMode
 		dest.SSLCa = mConfig.SSLCa
 		dest.SSLCert = mConfig.SSLCert
@@ -311,6 +465,26 @@ func getMaxMultiUpdateRowSize(values *urlConfig, maxMultiUpdateRowSize *int) err
 	return nil
 }
 
+func getMaxBatchWait(values *urlConfig, maxBatchWait *time.Duration) error {
+	if values.MaxBatchWaitMs == nil {
+		return nil
+	}
+
+	c := time.Duration(*values.MaxBatchWaitMs) * time.Millisecond
+	if c <= 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid max-batch-wait-ms %d, which must be greater than 0",
+				*values.MaxBatchWaitMs))
+	}
+	if c > maxMaxBatchWait {
+		log.Warn("max-batch-wait-ms too large",
+			zap.Duration("original", c), zap.Duration("override", maxMaxBatchWait))
+		c = maxMaxBatchWait
+	}
+	*maxBatchWait = c
+	return nil
+}
+
 func getTiDBTxnMode(values *urlConfig, mode *string) {
 	if values.TiDBTxnMode == nil || len(*values.TiDBTxnMode) == 0 {
 		return
@@ -443,3 +617,267 @@ func getCachePrepStmts(values *urlConfig, cachePrepStmts *bool) {
 		*cachePrepStmts = *values.EnableCachePreparedStatement
 	}
 }
+
+func getSessionVariables(replicaConfig *config.ReplicaConfig, sessionVariables *map[string]string) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil {
+		return nil
+	}
+	vars := replicaConfig.Sink.MySQLConfig.SessionVariables
+	for name := range vars {
+		if !sessionVariableNameRegexp.MatchString(name) {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid session variable name %s, which must match %s",
+					name, sessionVariableNameRegexp.String()))
+		}
+		if _, ok := driverDSNParams[strings.ToLower(name)]; ok {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid session variable name %s, which is a param of the MySQL driver", name))
+		}
+	}
+	*sessionVariables = vars
+	return nil
+}
+
+func getVerifyOrdering(
+	replicaConfig *config.ReplicaConfig, workerCount int, verifyOrdering *bool,
+) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.VerifyOrdering == nil {
+		return nil
+	}
+	// Txns which don't conflict with each other can be written out of order by
+	// different workers, so the ordering can only be verified with one worker.
+	if *replicaConfig.Sink.MySQLConfig.VerifyOrdering && workerCount != 1 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("verify-ordering requires worker-count to be 1, but got %d", workerCount))
+	}
+	*verifyOrdering = *replicaConfig.Sink.MySQLConfig.VerifyOrdering
+	return nil
+}
+
+func getDebugSQLPreview(replicaConfig *config.ReplicaConfig, debugSQLPreview *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.DebugSQLPreview == nil {
+		return
+	}
+	*debugSQLPreview = *replicaConfig.Sink.MySQLConfig.DebugSQLPreview
+}
+
+func getSchemaCompatibilityCheck(replicaConfig *config.ReplicaConfig, check *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.SchemaCompatibilityCheck == nil {
+		return
+	}
+	*check = *replicaConfig.Sink.MySQLConfig.SchemaCompatibilityCheck
+}
+
+func getTrackBinlogPosition(replicaConfig *config.ReplicaConfig, track *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.TrackBinlogPosition == nil {
+		return
+	}
+	*track = *replicaConfig.Sink.MySQLConfig.TrackBinlogPosition
+}
+
+func getPartitionDDLCompatibility(replicaConfig *config.ReplicaConfig, compatibility *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.PartitionDDLCompatibility == nil {
+		return
+	}
+	*compatibility = *replicaConfig.Sink.MySQLConfig.PartitionDDLCompatibility
+}
+
+func getConnectionPoolPerTable(replicaConfig *config.ReplicaConfig, perTable *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.ConnectionPoolPerTable == nil {
+		return
+	}
+	*perTable = *replicaConfig.Sink.MySQLConfig.ConnectionPoolPerTable
+}
+
+func getDDLFallbackPolicy(replicaConfig *config.ReplicaConfig, policy *string) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.DDLFallbackPolicy == nil {
+		return nil
+	}
+	s := strings.ToLower(*replicaConfig.Sink.MySQLConfig.DDLFallbackPolicy)
+	switch s {
+	case DDLFallbackPolicyError, DDLFallbackPolicySkip, DDLFallbackPolicyEmulate:
+		*policy = s
+		return nil
+	}
+	return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+		fmt.Errorf("invalid ddl-fallback-policy %s, which must be one of error, skip and emulate", s))
+}
+
+func getMaxLockTimeoutRetries(replicaConfig *config.ReplicaConfig, retries *int) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.MaxLockTimeoutRetries == nil {
+		return nil
+	}
+	c := *replicaConfig.Sink.MySQLConfig.MaxLockTimeoutRetries
+	if c < 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid max-lock-timeout-retries %d, which must not be negative", c))
+	}
+	*retries = c
+	return nil
+}
+
+func getCharsetAndCollation(replicaConfig *config.ReplicaConfig, cs, collation *string) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil {
+		return nil
+	}
+	mysqlConfig := replicaConfig.Sink.MySQLConfig
+	if mysqlConfig.DownstreamCharset == nil && mysqlConfig.DownstreamCollation == nil {
+		return nil
+	}
+	newCharset, newCollation := *cs, *collation
+	if mysqlConfig.DownstreamCharset != nil {
+		info, err := charset.GetCharsetInfo(*mysqlConfig.DownstreamCharset)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		newCharset, newCollation = info.Name, info.DefaultCollation
+	}
+	if mysqlConfig.DownstreamCollation != nil {
+		info, err := charset.GetCollationByName(*mysqlConfig.DownstreamCollation)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if mysqlConfig.DownstreamCharset == nil {
+			newCharset = info.CharsetName
+		}
+		newCollation = info.Name
+	}
+	if c, _ := charset.GetCollationByName(newCollation); c.CharsetName != newCharset {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("downstream-collation %s is not valid for downstream-charset %s",
+				newCollation, newCharset))
+	}
+	*cs, *collation = newCharset, newCollation
+	return nil
+}
+
+func getAuditSideChannel(replicaConfig *config.ReplicaConfig, table *string) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.AuditSideChannel == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*replicaConfig.Sink.MySQLConfig.AuditSideChannel)
+	if t == "" {
+		return nil
+	}
+	names := strings.Split(t, ".")
+	if len(names) > 2 || names[0] == "" || names[len(names)-1] == "" {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid audit-side-channel %s, which must be a table name "+
+				"optionally qualified by a schema name", t))
+	}
+	*table = t
+	return nil
+}
+
+func getAuditSideChannelCompression(replicaConfig *config.ReplicaConfig, compression *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.AuditSideChannelCompression == nil {
+		return
+	}
+	*compression = *replicaConfig.Sink.MySQLConfig.AuditSideChannelCompression
+}
+
+func getPtOSCCompatibility(replicaConfig *config.ReplicaConfig, compatibility *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.PtOSCCompatibility == nil {
+		return
+	}
+	*compatibility = *replicaConfig.Sink.MySQLConfig.PtOSCCompatibility
+}
+
+func getPtOSCWaitTimeout(replicaConfig *config.ReplicaConfig, timeout *time.Duration) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.PtOSCWaitTimeout == nil {
+		return nil
+	}
+	s := *replicaConfig.Sink.MySQLConfig.PtOSCWaitTimeout
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+	}
+	if d <= 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid pt-osc-wait-timeout %s, which must be positive", s))
+	}
+	*timeout = d
+	return nil
+}
+
+func getTableNameMappings(replicaConfig *config.ReplicaConfig, mappings *map[string]*model.TableName) {
+	if replicaConfig.Sink == nil || len(replicaConfig.Sink.TableNameMappings) == 0 {
+		return
+	}
+	result := make(map[string]*model.TableName, len(replicaConfig.Sink.TableNameMappings))
+	for _, m := range replicaConfig.Sink.TableNameMappings {
+		result[strings.ToLower(m.SourceSchema+"."+m.SourceTable)] = &model.TableName{
+			Schema: m.TargetSchema,
+			Table:  m.TargetTable,
+		}
+	}
+	*mappings = result
+}
+
+// TargetTableName returns the downstream table which the rows of the
+// upstream table are written to.
+func (c *Config) TargetTableName(table *model.TableName) *model.TableName {
+	if target, ok := c.TableNameMappings[strings.ToLower(table.Schema+"."+table.Table)]; ok {
+		return target
+	}
+	return table
+}
+
+func getBatchDDLMode(replicaConfig *config.ReplicaConfig, batchDDLMode *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.BatchDDLMode == nil {
+		return
+	}
+	*batchDDLMode = *replicaConfig.Sink.MySQLConfig.BatchDDLMode
+}
+
+func getCreateTableIfNotExists(
+	replicaConfig *config.ReplicaConfig, createTableIfNotExists *bool, upstreamTiDBDSN *string,
+) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil {
+		return
+	}
+	if replicaConfig.Sink.MySQLConfig.CreateTableIfNotExists != nil {
+		*createTableIfNotExists = *replicaConfig.Sink.MySQLConfig.CreateTableIfNotExists
+	}
+	if replicaConfig.Sink.MySQLConfig.UpstreamTiDBDSN != nil {
+		*upstreamTiDBDSN = *replicaConfig.Sink.MySQLConfig.UpstreamTiDBDSN
+	}
+}
+
+func getAutoIncrementConflictPolicy(replicaConfig *config.ReplicaConfig, policy *string) error {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.AutoIncrementConflictPolicy == nil {
+		return nil
+	}
+	s := strings.ToLower(*replicaConfig.Sink.MySQLConfig.AutoIncrementConflictPolicy)
+	switch s {
+	case AutoIncrementConflictPolicyError, AutoIncrementConflictPolicyReassign,
+		AutoIncrementConflictPolicySkip:
+		*policy = s
+		return nil
+	}
+	return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+		fmt.Errorf("invalid auto-increment-conflict-policy %s, "+
+			"which must be one of error, reassign and skip", s))
+}
+
+func getConditionalDDL(replicaConfig *config.ReplicaConfig, conditional *bool) {
+	if replicaConfig.Sink == nil || replicaConfig.Sink.MySQLConfig == nil ||
+		replicaConfig.Sink.MySQLConfig.ConditionalDDL == nil {
+		return
+	}
+	*conditional = *replicaConfig.Sink.MySQLConfig.ConditionalDDL
+}
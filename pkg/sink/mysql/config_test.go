@@ -49,6 +49,7 @@ func TestGenerateDSNByConfig(t *testing.T) {
 			"allow_auto_random_explicit_insert=1",
 			"transaction_isolation=%22READ-COMMITTED%22",
 			"charset=utf8mb4",
+			"collation_connection=%22utf8mb4_bin%22",
 			"tidb_placement_mode=%22ignore%22",
 		}
 		for _, param := range expectedCfg {
@@ -172,10 +173,465 @@ func TestGenerateDSNByConfig(t *testing.T) {
 		}
 	}
 
+	testSessionVariables := func() {
+		db, err := MockTestDB()
+		require.Nil(t, err)
+		defer db.Close()
+
+		dsn, err := dmysql.ParseDSN("root:123456@tcp(127.0.0.1:4000)/")
+		require.Nil(t, err)
+		cfg := NewConfig()
+		cfg.Timezone = `"UTC"`
+		cfg.SessionVariables = map[string]string{
+			"time_zone":                "+08:00",
+			"innodb_lock_wait_timeout": "10",
+			"tidb_enable_1pc":          "ON",
+			"tidb_session_alias":       "it's \\ é\x00",
+			"max_execution_time":       "-1.5",
+			"tidb_mem_quota_query":     "1e400",
+			"div_precision_increment":  "Inf",
+			"sql_select_limit":         "NaN",
+		}
+		dsnStr, err := generateDSNByConfig(context.TODO(), dsn, cfg, db)
+		require.Nil(t, err)
+		// Session variables are params of the DSN, so they are set on all connections.
+		dsnCfg, err := dmysql.ParseDSN(dsnStr)
+		require.Nil(t, err)
+		require.Equal(t, `'+08:00'`, dsnCfg.Params["time_zone"])
+		require.Equal(t, "10", dsnCfg.Params["innodb_lock_wait_timeout"])
+		require.Equal(t, `'ON'`, dsnCfg.Params["tidb_enable_1pc"])
+		// Values are escaped as MySQL string literals.
+		require.Equal(t, `'it\'s \\ é\0'`, dsnCfg.Params["tidb_session_alias"])
+		// Only decimal literals are not quoted.
+		require.Equal(t, "-1.5", dsnCfg.Params["max_execution_time"])
+		require.Equal(t, `'1e400'`, dsnCfg.Params["tidb_mem_quota_query"])
+		require.Equal(t, `'Inf'`, dsnCfg.Params["div_precision_increment"])
+		require.Equal(t, `'NaN'`, dsnCfg.Params["sql_select_limit"])
+	}
+
 	testDefaultConfig()
 	testTimezoneParam()
 	testTimeoutConfig()
 	testIsolationConfig()
+	testSessionVariables()
+}
+
+func TestApplySessionVariables(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		SessionVariables: map[string]string{"time_zone": "UTC"},
+	}
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"time_zone": "UTC"}, cfg.SessionVariables)
+
+	replicaConfig.Sink.MySQLConfig.SessionVariables = map[string]string{"tidb_enable_1pc": "ON"}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+
+	for _, name := range []string{
+		"time_zone=1;DROP TABLE t;", "`time_zone`", "time-zone", "", "1pc",
+		// the params of the MySQL driver
+		"timeout", "readTimeout", "charset", "Collation",
+	} {
+		replicaConfig.Sink.MySQLConfig.SessionVariables = map[string]string{name: "UTC"}
+		cfg = NewConfig()
+		err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+		require.Regexp(t, ".*invalid session variable name.*", err)
+	}
+}
+
+func TestApplyVerifyOrdering(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.VerifyOrdering)
+
+	// verify-ordering requires only one worker.
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		VerifyOrdering: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Regexp(t, ".*verify-ordering requires worker-count to be 1.*", err)
+
+	uri, err = url.Parse("mysql://127.0.0.1:3306/?worker-count=1")
+	require.Nil(t, err)
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.VerifyOrdering)
+}
+
+func TestApplyDebugSQLPreview(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.DebugSQLPreview)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		DebugSQLPreview: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.DebugSQLPreview)
+}
+
+func TestApplySchemaCompatibilityCheck(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.SchemaCompatibilityCheck)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		SchemaCompatibilityCheck: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.SchemaCompatibilityCheck)
+}
+
+func TestApplyTrackBinlogPosition(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.TrackBinlogPosition)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		TrackBinlogPosition: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.TrackBinlogPosition)
+}
+
+func TestApplyPartitionDDLCompatibility(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.PartitionDDLCompatibility)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		PartitionDDLCompatibility: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.PartitionDDLCompatibility)
+}
+
+func TestApplyConnectionPoolPerTable(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.ConnectionPoolPerTable)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		ConnectionPoolPerTable: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.ConnectionPoolPerTable)
+}
+
+func TestApplyDDLFallbackPolicy(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, DDLFallbackPolicyError, cfg.DDLFallbackPolicy)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		DDLFallbackPolicy: util.AddressOf("Skip"),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, DDLFallbackPolicySkip, cfg.DDLFallbackPolicy)
+
+	replicaConfig.Sink.MySQLConfig.DDLFallbackPolicy = util.AddressOf("ignore")
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Regexp(t, "invalid ddl-fallback-policy ignore", err)
+}
+
+func TestApplyAutoIncrementConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, AutoIncrementConflictPolicyError, cfg.AutoIncrementConflictPolicy)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		AutoIncrementConflictPolicy: util.AddressOf("Reassign"),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, AutoIncrementConflictPolicyReassign, cfg.AutoIncrementConflictPolicy)
+
+	replicaConfig.Sink.MySQLConfig.AutoIncrementConflictPolicy = util.AddressOf("overwrite")
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Regexp(t, "invalid auto-increment-conflict-policy overwrite", err)
+}
+
+func TestApplyCharsetAndCollation(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	apply := func(cs, collation *string) (*Config, error) {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+			DownstreamCharset:   cs,
+			DownstreamCollation: collation,
+		}
+		cfg := NewConfig()
+		err := cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+		return cfg, err
+	}
+
+	cfg, err := apply(nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, "utf8mb4", cfg.Charset)
+	require.Equal(t, "utf8mb4_bin", cfg.Collation)
+
+	cfg, err = apply(util.AddressOf("latin1"), nil)
+	require.Nil(t, err)
+	require.Equal(t, "latin1", cfg.Charset)
+	require.Equal(t, "latin1_bin", cfg.Collation)
+
+	cfg, err = apply(nil, util.AddressOf("utf8mb4_general_ci"))
+	require.Nil(t, err)
+	require.Equal(t, "utf8mb4", cfg.Charset)
+	require.Equal(t, "utf8mb4_general_ci", cfg.Collation)
+
+	_, err = apply(util.AddressOf("latin1"), util.AddressOf("utf8mb4_bin"))
+	require.Regexp(t, "downstream-collation utf8mb4_bin is not valid for downstream-charset latin1", err)
+	_, err = apply(util.AddressOf("unknown"), nil)
+	require.Regexp(t, "Unknown charset unknown", err)
+	_, err = apply(nil, util.AddressOf("unknown"))
+	require.Regexp(t, "Unknown collation", err)
+}
+
+func TestGenerateDSNCharset(t *testing.T) {
+	t.Parallel()
+
+	// The charset and collation are set explicitly for every connection, so
+	// the default charset of the downstream, e.g. latin1, is never used.
+	db, err := MockTestDB()
+	require.Nil(t, err)
+	defer db.Close()
+	dsn, err := dmysql.ParseDSN("root:123456@tcp(127.0.0.1:3306)/")
+	require.Nil(t, err)
+	dsnStr, err := generateDSNByConfig(context.TODO(), dsn, NewConfig(), db)
+	require.Nil(t, err)
+	dsn, err = dmysql.ParseDSN(dsnStr)
+	require.Nil(t, err)
+	require.Equal(t, "utf8mb4", dsn.Params["charset"])
+	require.Equal(t, `"utf8mb4_bin"`, dsn.Params["collation_connection"])
+}
+
+func TestApplyMaxLockTimeoutRetries(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, defaultMaxLockTimeoutRetries, cfg.MaxLockTimeoutRetries)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		MaxLockTimeoutRetries: util.AddressOf(0),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, 0, cfg.MaxLockTimeoutRetries)
+
+	replicaConfig.Sink.MySQLConfig.MaxLockTimeoutRetries = util.AddressOf(-1)
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Regexp(t, "invalid max-lock-timeout-retries -1", err)
+}
+
+func TestApplyPtOSCCompatibility(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.PtOSCCompatibility)
+	require.Equal(t, defaultPtOSCWaitTimeout, cfg.PtOSCWaitTimeout)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		PtOSCCompatibility: util.AddressOf(true),
+		PtOSCWaitTimeout:   util.AddressOf("10m"),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.PtOSCCompatibility)
+	require.Equal(t, 10*time.Minute, cfg.PtOSCWaitTimeout)
+
+	replicaConfig.Sink.MySQLConfig.PtOSCWaitTimeout = util.AddressOf("0s")
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Regexp(t, "invalid pt-osc-wait-timeout 0s", err)
+
+	replicaConfig.Sink.MySQLConfig.PtOSCWaitTimeout = util.AddressOf("forever")
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Regexp(t, "ErrMySQLInvalidConfig.*invalid duration", err)
+}
+
+func TestApplyBatchDDLMode(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.BatchDDLMode)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		BatchDDLMode: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.BatchDDLMode)
+}
+
+func TestApplyCreateTableIfNotExists(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.False(t, cfg.CreateTableIfNotExists)
+	require.Empty(t, cfg.UpstreamTiDBDSN)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		CreateTableIfNotExists: util.AddressOf(true),
+		UpstreamTiDBDSN:        util.AddressOf("root@tcp(127.0.0.1:4000)/"),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.True(t, cfg.CreateTableIfNotExists)
+	require.Equal(t, "root@tcp(127.0.0.1:4000)/", cfg.UpstreamTiDBDSN)
+}
+
+func TestApplyTableNameMappings(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.TableNameMappings = []*config.TableNameMapping{{
+		SourceSchema: "prod",
+		SourceTable:  "Users",
+		TargetSchema: "staging",
+		TargetTable:  "users_v2",
+	}}
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+
+	target := cfg.TargetTableName(&model.TableName{Schema: "PROD", Table: "users", TableID: 1})
+	require.Equal(t, &model.TableName{Schema: "staging", Table: "users_v2"}, target)
+	orders := &model.TableName{Schema: "prod", Table: "orders"}
+	require.Same(t, orders, cfg.TargetTableName(orders))
+}
+
+func TestApplyAuditSideChannel(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Empty(t, cfg.AuditSideChannel)
+	require.False(t, cfg.AuditSideChannelCompression)
+
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		AuditSideChannel:            util.AddressOf("audit._ticdc_audit_log"),
+		AuditSideChannelCompression: util.AddressOf(true),
+	}
+	cfg = NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+	require.Nil(t, err)
+	require.Equal(t, "audit._ticdc_audit_log", cfg.AuditSideChannel)
+	require.True(t, cfg.AuditSideChannelCompression)
+
+	for _, table := range []string{"a.b.c", ".t", "db."} {
+		replicaConfig.Sink.MySQLConfig.AuditSideChannel = util.AddressOf(table)
+		cfg = NewConfig()
+		err = cfg.Apply("UTC", model.DefaultChangeFeedID("123"), uri, replicaConfig)
+		require.Regexp(t, "invalid audit-side-channel", err)
+	}
 }
 
 func TestApplySinkURIParamsToConfig(t *testing.T) {
@@ -186,12 +642,14 @@ func TestApplySinkURIParamsToConfig(t *testing.T) {
 	expected.MaxTxnRow = 20
 	expected.MaxMultiUpdateRowCount = 80
 	expected.MaxMultiUpdateRowSize = 512
+	expected.MaxBatchWait = 100 * time.Millisecond
 	expected.SafeMode = false
 	expected.Timezone = `"UTC"`
 	expected.tidbTxnMode = "pessimistic"
 	expected.CachePrepStmts = true
 	uriStr := "mysql://127.0.0.1:3306/?worker-count=64&max-txn-row=20" +
 		"&max-multi-update-row=80&max-multi-update-row-size=512" +
+		"&max-batch-wait-ms=100" +
 		"&safe-mode=false" +
 		"&tidb-txn-mode=pessimistic" +
 		"&test-some-deprecated-config=true&test-deprecated-size-config=100" +
@@ -230,6 +688,11 @@ func TestParseSinkURIOverride(t *testing.T) {
 		checker: func(sp *Config) {
 			require.EqualValues(t, sp.MaxMultiUpdateRowSize, maxMaxMultiUpdateRowSize)
 		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?max-batch-wait-ms=2147483647", // int32 max
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.MaxBatchWait, maxMaxBatchWait)
+		},
 	}, {
 		uri: "mysql://127.0.0.1:3306/?tidb-txn-mode=badmode",
 		checker: func(sp *Config) {
@@ -271,6 +734,7 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 		"mysql://127.0.0.1:3306/?max-txn-row=not-number",
 		"mysql://127.0.0.1:3306/?max-txn-row=-1",
 		"mysql://127.0.0.1:3306/?max-txn-row=0",
+		"mysql://127.0.0.1:3306/?max-batch-wait-ms=0",
 		"mysql://127.0.0.1:3306/?ssl-ca=only-ca-exists",
 		"mysql://127.0.0.1:3306/?safe-mode=not-bool",
 		"mysql://127.0.0.1:3306/?time-zone=badtz",
@@ -417,6 +881,7 @@ func TestMergeConfig(t *testing.T) {
 		MaxTxnRow:                    aws.Int(100),
 		MaxMultiUpdateRowSize:        aws.Int(102),
 		MaxMultiUpdateRowCount:       aws.Int(103),
+		MaxBatchWaitMs:               aws.Int(104),
 		TiDBTxnMode:                  aws.String("pessimistic"),
 		TimeZone:                     aws.String("Asia/Shanghai"),
 		WriteTimeout:                 aws.String("1m1s"),
@@ -433,6 +898,7 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, 100, c.MaxTxnRow)
 	require.Equal(t, 102, c.MaxMultiUpdateRowSize)
 	require.Equal(t, 103, c.MaxMultiUpdateRowCount)
+	require.Equal(t, 104*time.Millisecond, c.MaxBatchWait)
 	require.Equal(t, "pessimistic", c.tidbTxnMode)
 	require.Equal(t, "\"Asia/Shanghai\"", c.Timezone)
 	require.Equal(t, "1m1s", c.WriteTimeout)
@@ -447,6 +913,7 @@ func TestMergeConfig(t *testing.T) {
 		"max-txn-row=100&" +
 		"max-multi-update-row-size=102&" +
 		"max-multi-update-row=103&" +
+		"max-batch-wait-ms=104&" +
 		"tidb-txn-mode=pessimistic&" +
 		"time-zone=Asia/Shanghai&" +
 		"write-timeout=1m1s&" +
@@ -463,6 +930,7 @@ func TestMergeConfig(t *testing.T) {
 		MaxTxnRow:                    aws.Int(130),
 		MaxMultiUpdateRowSize:        aws.Int(142),
 		MaxMultiUpdateRowCount:       aws.Int(153),
+		MaxBatchWaitMs:               aws.Int(154),
 		TiDBTxnMode:                  aws.String("optimistic"),
 		TimeZone:                     aws.String("utc"),
 		WriteTimeout:                 aws.String("2m1s"),
@@ -479,6 +947,7 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, 100, c.MaxTxnRow)
 	require.Equal(t, 102, c.MaxMultiUpdateRowSize)
 	require.Equal(t, 103, c.MaxMultiUpdateRowCount)
+	require.Equal(t, 104*time.Millisecond, c.MaxBatchWait)
 	require.Equal(t, "pessimistic", c.tidbTxnMode)
 	require.Equal(t, "\"Asia/Shanghai\"", c.Timezone)
 	require.Equal(t, "1m1s", c.WriteTimeout)
@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 
 	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/errors"
@@ -147,8 +149,12 @@ func generateDSNByConfig(
 		dsnCfg.Params["tx_isolation"] = fmt.Sprintf(`"%s"`, defaultTxnIsolationRC)
 	}
 
-	// equals to executing "SET NAMES utf8mb4"
-	dsnCfg.Params["charset"] = defaultCharacterSet
+	// equals to executing "SET NAMES utf8mb4", the charset is always set
+	// explicitly since the default charset of the downstream may differ.
+	dsnCfg.Params["charset"] = cfg.Charset
+	// `SET NAMES` resets the collation of the connection to the default one of
+	// the charset, so the collation is set as a session variable.
+	dsnCfg.Params["collation_connection"] = strconv.Quote(cfg.Collation)
 
 	// disable foreign_key_checks
 	dsnCfg.Params["foreign_key_checks"] = "0"
@@ -168,6 +174,13 @@ func generateDSNByConfig(
 		// set the `tidb_enable_external_ts_read` to `OFF`, so cdc could write to the sink
 		dsnCfg.Params["tidb_enable_external_ts_read"] = fmt.Sprintf(`"%s"`, tidbEnableExternalTSRead)
 	}
+	// The driver executes `SET name=value` for every param it doesn't recognize
+	// after a new connection is established, so user-defined session variables
+	// are applied to all connections in the pool and can override the above ones.
+	for name, value := range cfg.SessionVariables {
+		dsnCfg.Params[name] = quoteSessionVariableValue(value)
+	}
+
 	dsnClone := dsnCfg.Clone()
 	dsnClone.Passwd = "******"
 	log.Info("sink uri is configured", zap.String("dsn", dsnClone.FormatDSN()))
@@ -175,6 +188,40 @@ func generateDSNByConfig(
 	return dsnCfg.FormatDSN(), nil
 }
 
+// numericLiteralRegexp matches the decimal integer and decimal literals of MySQL.
+var numericLiteralRegexp = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// quoteSessionVariableValue quotes the value as a MySQL string literal unless
+// it's a number, because MySQL rejects quoted values for numeric variables.
+func quoteSessionVariableValue(value string) string {
+	if numericLiteralRegexp.MatchString(value) {
+		return value
+	}
+	var sb strings.Builder
+	sb.Grow(len(value) + 2)
+	sb.WriteByte('\'')
+	for i := 0; i < len(value); i++ {
+		// The same characters are escaped as the MySQL driver does.
+		switch c := value[i]; c {
+		case '\x00':
+			sb.WriteString(`\0`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\x1a':
+			sb.WriteString(`\Z`)
+		case '\'', '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
+
 // check whether the target charset is supported
 func checkCharsetSupport(ctx context.Context, db *sql.DB, charsetName string) (bool, error) {
 	// validate charsetName
@@ -334,6 +381,80 @@ func QueryMaxAllowedPacket(ctx context.Context, db *sql.DB) (int64, error) {
 	return maxAllowedPacket.Int64, nil
 }
 
+// QueryGTIDExecuted gets the value of gtid_executed
+func QueryGTIDExecuted(ctx context.Context, db *sql.DB) (string, error) {
+	row := db.QueryRowContext(ctx, "select @@global.gtid_executed;")
+	var gtidExecuted sql.NullString
+	if err := row.Scan(&gtidExecuted); err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return gtidExecuted.String, nil
+}
+
+// QueryBinlogPosition gets the current binlog file, position and
+// executed GTID set by `SHOW MASTER STATUS`.
+func QueryBinlogPosition(
+	ctx context.Context, db *sql.DB,
+) (file string, pos uint64, gtidSet string, err error) {
+	rows, err := db.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		return "", 0, "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", 0, "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return "", 0, "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		return "", 0, "", cerror.ErrMySQLQueryError.GenWithStack(
+			"binlog is not enabled in the downstream")
+	}
+	// The columns of `SHOW MASTER STATUS` differ between versions,
+	// so they are fetched by name.
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err = rows.Scan(dest...); err != nil {
+		return "", 0, "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	for i, column := range columns {
+		switch column {
+		case "File":
+			file = values[i].String
+		case "Position":
+			pos, err = strconv.ParseUint(values[i].String, 10, 64)
+			if err != nil {
+				return "", 0, "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+			}
+		case "Executed_Gtid_Set":
+			gtidSet = values[i].String
+		}
+	}
+	return file, pos, gtidSet, nil
+}
+
+// CheckPartitioningSupported checks if the downstream supports partitioned
+// tables by `have_partitioning`. The variable is removed in MySQL 8.0,
+// whose InnoDB always supports partitioning, so a missing variable is
+// treated as supported.
+func CheckPartitioningSupported(ctx context.Context, db *sql.DB) (bool, error) {
+	var name, value string
+	err := db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'have_partitioning'").Scan(&name, &value)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return strings.EqualFold(value, "YES"), nil
+}
+
 // SetWriteSource sets write source for the transaction.
 func SetWriteSource(ctx context.Context, cfg *Config, txn *sql.Tx) error {
 	// we only set write source when donwstream is TiDB and write source is existed.
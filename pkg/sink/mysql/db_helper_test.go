@@ -14,10 +14,12 @@
 package mysql
 
 import (
+	"context"
 	"encoding/base64"
 	"net/url"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -74,3 +76,30 @@ func TestDecodePassword(t *testing.T) {
 		require.Equal(t, c.want, c.password)
 	}
 }
+
+func TestCheckPartitioningSupported(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "SHOW VARIABLES LIKE 'have_partitioning'"
+	columns := []string{"Variable_name", "Value"}
+	// MySQL 5.7
+	mock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("have_partitioning", "YES"))
+	// MySQL 5.7 built without partitioning
+	mock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("have_partitioning", "DISABLED"))
+	// MySQL 8.0
+	mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns))
+
+	ctx := context.Background()
+	for _, expected := range []bool{true, false, true} {
+		supported, err := CheckPartitioningSupported(ctx, db)
+		require.NoError(t, err)
+		require.Equal(t, expected, supported)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
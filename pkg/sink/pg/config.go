@@ -0,0 +1,158 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	// Register the "postgres" driver for database/sql.
+	_ "github.com/lib/pq"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxTxnRow is the default max number of rows in a transaction.
+	DefaultMaxTxnRow = 256
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max txn rows.
+	maxMaxTxnRow = 2048
+
+	defaultWriteTimeout = 2 * time.Minute
+)
+
+// tiCDCParams are the parameters of the sink URI consumed by TiCDC, they
+// must not be passed to the PostgreSQL driver.
+var tiCDCParams = []string{"worker-count", "max-txn-row", "write-timeout"}
+
+type urlConfig struct {
+	WorkerCount  *int    `form:"worker-count"`
+	MaxTxnRow    *int    `form:"max-txn-row"`
+	WriteTimeout *string `form:"write-timeout"`
+}
+
+// Config is the configs for PostgreSQL backend.
+type Config struct {
+	WorkerCount  int
+	MaxTxnRow    int
+	WriteTimeout time.Duration
+}
+
+// NewConfig returns the default PostgreSQL backend config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount:  sink.DefaultWorkerCount,
+		MaxTxnRow:    DefaultMaxTxnRow,
+		WriteTimeout: defaultWriteTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL) error {
+	if sinkURI == nil {
+		return cerror.ErrPostgresInvalidConfig.GenWithStack("fail to open PostgreSQL sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsPostgresScheme(scheme) {
+		return cerror.ErrPostgresInvalidConfig.GenWithStack(
+			"can't create PostgreSQL sink with unsupported scheme: %s", scheme)
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+	}
+
+	if urlParameter.WorkerCount != nil {
+		workerCount := *urlParameter.WorkerCount
+		if workerCount <= 0 {
+			return cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", workerCount))
+		}
+		if workerCount > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", workerCount), zap.Int("override", maxWorkerCount))
+			workerCount = maxWorkerCount
+		}
+		c.WorkerCount = workerCount
+	}
+	if urlParameter.MaxTxnRow != nil {
+		maxTxnRow := *urlParameter.MaxTxnRow
+		if maxTxnRow <= 0 {
+			return cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid max-txn-row %d, which must be greater than 0", maxTxnRow))
+		}
+		if maxTxnRow > maxMaxTxnRow {
+			log.Warn("max-txn-row too large",
+				zap.Int("original", maxTxnRow), zap.Int("override", maxMaxTxnRow))
+			maxTxnRow = maxMaxTxnRow
+		}
+		c.MaxTxnRow = maxTxnRow
+	}
+	if urlParameter.WriteTimeout != nil {
+		writeTimeout, err := time.ParseDuration(*urlParameter.WriteTimeout)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+		}
+		c.WriteTimeout = writeTimeout
+	}
+	return nil
+}
+
+// GenerateDSN generates the connection string of lib/pq from the sink URI.
+// The parameters consumed by TiCDC are removed, and the client encoding is
+// always UTF8 since all the strings replicated from TiDB are encoded in UTF8.
+func GenerateDSN(sinkURI *url.URL) string {
+	dsn := *sinkURI
+	dsn.Scheme = sink.PostgresScheme
+	query := dsn.Query()
+	for _, param := range tiCDCParams {
+		query.Del(param)
+	}
+	query.Set("client_encoding", "UTF8")
+	dsn.RawQuery = query.Encode()
+	return dsn.String()
+}
+
+// Factory is the factory for creating db connection.
+type Factory func(ctx context.Context, dsnStr string) (*sql.DB, error)
+
+// CreatePGDBConn creates a PostgreSQL database connection with the given dsn.
+func CreatePGDBConn(ctx context.Context, dsnStr string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsnStr)
+	if err != nil {
+		return nil, cerror.ErrPostgresConnectionError.Wrap(err).GenWithStack("fail to open PostgreSQL connection")
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		// close db to recycle resources
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn("close db failed", zap.Error(err))
+		}
+		return nil, cerror.ErrPostgresConnectionError.Wrap(err).GenWithStack("fail to open PostgreSQL connection")
+	}
+
+	return db, nil
+}
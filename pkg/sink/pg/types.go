@@ -0,0 +1,160 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// QuoteName quotes an identifier of PostgreSQL.
+func QuoteName(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteSchema quotes a full table name of PostgreSQL.
+func QuoteSchema(schema string, table string) string {
+	return QuoteName(schema) + "." + QuoteName(table)
+}
+
+// ColumnType returns the PostgreSQL column type of a TiDB column type.
+func ColumnType(ft *types.FieldType) string {
+	unsigned := mysql.HasUnsignedFlag(ft.GetFlag())
+	binary := ft.GetCharset() == charset.CharsetBin
+	switch ft.GetType() {
+	case mysql.TypeTiny:
+		return "SMALLINT"
+	case mysql.TypeShort:
+		if unsigned {
+			return "INTEGER"
+		}
+		return "SMALLINT"
+	case mysql.TypeInt24:
+		return "INTEGER"
+	case mysql.TypeLong:
+		if unsigned {
+			return "BIGINT"
+		}
+		return "INTEGER"
+	case mysql.TypeLonglong:
+		if unsigned {
+			// BIGINT UNSIGNED overflows the BIGINT of PostgreSQL.
+			return "NUMERIC(20,0)"
+		}
+		return "BIGINT"
+	case mysql.TypeFloat:
+		return "REAL"
+	case mysql.TypeDouble:
+		return "DOUBLE PRECISION"
+	case mysql.TypeNewDecimal:
+		if ft.GetFlen() > 0 && ft.GetDecimal() >= 0 {
+			return fmt.Sprintf("NUMERIC(%d,%d)", ft.GetFlen(), ft.GetDecimal())
+		}
+		return "NUMERIC"
+	case mysql.TypeDate:
+		return "DATE"
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		// TiDB converts TIMESTAMP values to the time zone of the changefeed,
+		// so neither of them carries a time zone.
+		if ft.GetDecimal() > 0 {
+			return fmt.Sprintf("TIMESTAMP(%d) WITHOUT TIME ZONE", ft.GetDecimal())
+		}
+		return "TIMESTAMP WITHOUT TIME ZONE"
+	case mysql.TypeDuration:
+		// TIME of MySQL ranges from -838:59:59 to 838:59:59, which doesn't
+		// fit in the TIME of PostgreSQL.
+		return "INTERVAL"
+	case mysql.TypeYear:
+		return "SMALLINT"
+	case mysql.TypeString:
+		if binary {
+			return "BYTEA"
+		}
+		if ft.GetFlen() > 0 {
+			return fmt.Sprintf("CHAR(%d)", ft.GetFlen())
+		}
+		return "TEXT"
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		if binary {
+			return "BYTEA"
+		}
+		if ft.GetFlen() > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", ft.GetFlen())
+		}
+		return "TEXT"
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		if binary {
+			return "BYTEA"
+		}
+		return "TEXT"
+	case mysql.TypeEnum, mysql.TypeSet:
+		return "TEXT"
+	case mysql.TypeBit:
+		return "NUMERIC(20,0)"
+	case mysql.TypeJSON:
+		return "JSONB"
+	case mysql.TypeGeometry:
+		return "BYTEA"
+	default:
+		return "TEXT"
+	}
+}
+
+// ConvertValue converts a column value of a RowChangedEvent to the value
+// accepted by the PostgreSQL driver.
+func ConvertValue(ft *types.FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch ft.GetType() {
+	case mysql.TypeEnum:
+		if v, ok := value.(uint64); ok {
+			enum, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return enum.Name, nil
+		}
+	case mysql.TypeSet:
+		if v, ok := value.(uint64); ok {
+			set, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return set.Name, nil
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		// PostgreSQL rejects the zero dates of MySQL.
+		if v, ok := value.(string); ok && strings.HasPrefix(v, "0000-00-00") {
+			return nil, nil
+		}
+	}
+
+	switch v := value.(type) {
+	case uint64:
+		// database/sql doesn't support uint64 values with the high bit set.
+		return strconv.FormatUint(v, 10), nil
+	case []byte:
+		if ft.GetCharset() != charset.CharsetBin {
+			return string(v), nil
+		}
+	}
+	return value, nil
+}
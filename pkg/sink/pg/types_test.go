@@ -0,0 +1,95 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newFieldType(tp byte, flag uint, flen, decimal int, cs string) *types.FieldType {
+	ft := types.NewFieldType(tp)
+	ft.SetFlag(flag)
+	ft.SetFlen(flen)
+	ft.SetDecimal(decimal)
+	ft.SetCharset(cs)
+	return ft
+}
+
+func TestColumnType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		ft       *types.FieldType
+		expected string
+	}{
+		{newFieldType(mysql.TypeTiny, 0, 4, 0, charset.CharsetBin), "SMALLINT"},
+		{newFieldType(mysql.TypeShort, mysql.UnsignedFlag, 6, 0, charset.CharsetBin), "INTEGER"},
+		{newFieldType(mysql.TypeLong, 0, 11, 0, charset.CharsetBin), "INTEGER"},
+		{newFieldType(mysql.TypeLong, mysql.UnsignedFlag, 10, 0, charset.CharsetBin), "BIGINT"},
+		{newFieldType(mysql.TypeLonglong, 0, 20, 0, charset.CharsetBin), "BIGINT"},
+		{newFieldType(mysql.TypeLonglong, mysql.UnsignedFlag, 20, 0, charset.CharsetBin), "NUMERIC(20,0)"},
+		{newFieldType(mysql.TypeDouble, 0, 22, -1, charset.CharsetBin), "DOUBLE PRECISION"},
+		{newFieldType(mysql.TypeNewDecimal, 0, 10, 2, charset.CharsetBin), "NUMERIC(10,2)"},
+		{newFieldType(mysql.TypeDatetime, 0, 19, 0, charset.CharsetBin), "TIMESTAMP WITHOUT TIME ZONE"},
+		{newFieldType(mysql.TypeTimestamp, 0, 23, 3, charset.CharsetBin), "TIMESTAMP(3) WITHOUT TIME ZONE"},
+		{newFieldType(mysql.TypeDuration, 0, 10, 0, charset.CharsetBin), "INTERVAL"},
+		{newFieldType(mysql.TypeVarchar, 0, 32, 0, charset.CharsetUTF8MB4), "VARCHAR(32)"},
+		{newFieldType(mysql.TypeVarchar, mysql.BinaryFlag, 32, 0, charset.CharsetBin), "BYTEA"},
+		{newFieldType(mysql.TypeBlob, 0, 65535, 0, charset.CharsetUTF8MB4), "TEXT"},
+		{newFieldType(mysql.TypeBlob, mysql.BinaryFlag, 65535, 0, charset.CharsetBin), "BYTEA"},
+		{newFieldType(mysql.TypeEnum, 0, 1, 0, charset.CharsetUTF8MB4), "TEXT"},
+		{newFieldType(mysql.TypeJSON, 0, 0, 0, charset.CharsetBin), "JSONB"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, ColumnType(c.ft), c.ft.String())
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	t.Parallel()
+
+	enum := newFieldType(mysql.TypeEnum, 0, 1, 0, charset.CharsetUTF8MB4)
+	enum.SetElems([]string{"a", "b"})
+	value, err := ConvertValue(enum, uint64(2))
+	require.NoError(t, err)
+	require.Equal(t, "b", value)
+
+	set := newFieldType(mysql.TypeSet, 0, 1, 0, charset.CharsetUTF8MB4)
+	set.SetElems([]string{"a", "b"})
+	value, err = ConvertValue(set, uint64(3))
+	require.NoError(t, err)
+	require.Equal(t, "a,b", value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeLonglong, mysql.UnsignedFlag, 20, 0, charset.CharsetBin),
+		uint64(18446744073709551615))
+	require.NoError(t, err)
+	require.Equal(t, "18446744073709551615", value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeVarchar, 0, 32, 0, charset.CharsetUTF8MB4), []byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, "abc", value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeBlob, mysql.BinaryFlag, 65535, 0, charset.CharsetBin), []byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("abc"), value)
+
+	value, err = ConvertValue(newFieldType(mysql.TypeDatetime, 0, 19, 0, charset.CharsetBin), "0000-00-00 00:00:00")
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
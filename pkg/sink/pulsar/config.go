@@ -16,7 +16,9 @@ package pulsar
 import (
 	"fmt"
 	"net/url"
+	"time"
 
+	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/config"
 	"go.uber.org/zap"
@@ -115,3 +117,49 @@ func toMill(x int) *config.TimeMill {
 func toUint(x uint) *uint {
 	return &x
 }
+
+// NewProducerOptions returns the options to create a pulsar producer for the topic.
+// The producer config takes precedence over the producer related fields in pulsar config.
+func NewProducerOptions(
+	topicName string,
+	pConfig *config.PulsarConfig,
+	producerConfig *config.PulsarProducerConfig,
+) pulsar.ProducerOptions {
+	po := pulsar.ProducerOptions{
+		Topic: topicName,
+	}
+	if pConfig.BatchingMaxMessages != nil {
+		po.BatchingMaxMessages = *pConfig.BatchingMaxMessages
+	}
+	if pConfig.BatchingMaxPublishDelay != nil {
+		po.BatchingMaxPublishDelay = pConfig.BatchingMaxPublishDelay.Duration()
+	}
+	if pConfig.CompressionType != nil {
+		po.CompressionType = pConfig.CompressionType.Value()
+		po.CompressionLevel = pulsar.Default
+	}
+	if pConfig.SendTimeout != nil {
+		po.SendTimeout = pConfig.SendTimeout.Duration()
+	}
+
+	if producerConfig == nil {
+		return po
+	}
+	if producerConfig.CompressionType != nil {
+		po.CompressionType = producerConfig.CompressionType.Value()
+		po.CompressionLevel = pulsar.Default
+	}
+	if producerConfig.BatchingEnabled != nil {
+		po.DisableBatching = !*producerConfig.BatchingEnabled
+	}
+	if producerConfig.BatchingMaxMessages != nil {
+		po.BatchingMaxMessages = uint(*producerConfig.BatchingMaxMessages)
+	}
+	if producerConfig.BatchingMaxPublishDelayMs != nil {
+		po.BatchingMaxPublishDelay = time.Duration(*producerConfig.BatchingMaxPublishDelayMs) * time.Millisecond
+	}
+	if producerConfig.MaxPendingMessages != nil {
+		po.MaxPendingMessages = *producerConfig.MaxPendingMessages
+	}
+	return po
+}
@@ -116,3 +116,37 @@ func TestGetDefaultTopicName(t *testing.T) {
 	config, _ = NewPulsarConfig(sink, replicaConfig.Sink.PulsarConfig)
 	assert.Equal(t, config.GetDefaultTopicName(), "persistent://tenant/namespace/test-topic")
 }
+
+func TestNewProducerOptions(t *testing.T) {
+	t.Parallel()
+
+	pConfig := &config.PulsarConfig{
+		CompressionType:         (*config.PulsarCompressionType)(aws.String("lz4")),
+		BatchingMaxMessages:     aws.Uint(defaultBatchingMaxSize),
+		BatchingMaxPublishDelay: (*config.TimeMill)(aws.Int(defaultBatchingMaxPublishDelay)),
+		SendTimeout:             (*config.TimeSec)(aws.Int(defaultSendTimeout)),
+	}
+	po := NewProducerOptions("test", pConfig, nil)
+	assert.Equal(t, "test", po.Topic)
+	assert.Equal(t, pulsar.LZ4, po.CompressionType)
+	assert.False(t, po.DisableBatching)
+	assert.Equal(t, defaultBatchingMaxSize, po.BatchingMaxMessages)
+	assert.Equal(t, 10*time.Millisecond, po.BatchingMaxPublishDelay)
+	assert.Equal(t, 30*time.Second, po.SendTimeout)
+
+	// The producer config takes precedence over the pulsar config.
+	producerConfig := &config.PulsarProducerConfig{
+		CompressionType:           (*config.PulsarCompressionType)(aws.String("zstd")),
+		BatchingEnabled:           aws.Bool(false),
+		BatchingMaxMessages:       aws.Int(100),
+		BatchingMaxPublishDelayMs: aws.Int(5),
+		MaxPendingMessages:        aws.Int(2000),
+	}
+	po = NewProducerOptions("test", pConfig, producerConfig)
+	assert.Equal(t, pulsar.ZSTD, po.CompressionType)
+	assert.True(t, po.DisableBatching)
+	assert.Equal(t, uint(100), po.BatchingMaxMessages)
+	assert.Equal(t, 5*time.Millisecond, po.BatchingMaxPublishDelay)
+	assert.Equal(t, 2000, po.MaxPendingMessages)
+	assert.Equal(t, 30*time.Second, po.SendTimeout)
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultKeyPrefix is the default prefix of the keys.
+	DefaultKeyPrefix = "tidb"
+	// DefaultPipelineSize is the default max number of commands sent in a pipeline.
+	DefaultPipelineSize = 256
+	// The upper limit of max worker counts.
+	maxWorkerCount = 256
+	// The upper limit of the pipeline size.
+	maxPipelineSize = 10000
+)
+
+type urlConfig struct {
+	WorkerCount  *int    `form:"worker-count"`
+	KeyPrefix    *string `form:"key-prefix"`
+	KeyTTL       *string `form:"key-ttl"`
+	PipelineSize *int    `form:"pipeline-size"`
+	Cluster      *bool   `form:"cluster"`
+}
+
+// Config is the configs for Redis sink.
+type Config struct {
+	Addrs    []string
+	Username string
+	Password string
+	DB       int
+	// Cluster indicates the addresses are the nodes of a Redis Cluster.
+	Cluster bool
+
+	WorkerCount int
+	// KeyPrefix is the prefix of the keys, the key of a row is
+	// `<prefix>:<schema>.<table>:<pk>`.
+	KeyPrefix string
+	// KeyTTL is the expiration of the keys, 0 means the keys never expire.
+	KeyTTL       time.Duration
+	PipelineSize int
+}
+
+// NewConfig returns the default Redis sink config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount:  sink.DefaultWorkerCount,
+		KeyPrefix:    DefaultKeyPrefix,
+		PipelineSize: DefaultPipelineSize,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+// The sink URI is like `redis://[user:password@]host:port[,host:port]/[db]`.
+func (c *Config) Apply(sinkURI *url.URL) error {
+	if sinkURI == nil {
+		return cerror.ErrRedisInvalidConfig.GenWithStack("fail to open Redis sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsRedisScheme(scheme) {
+		return cerror.ErrRedisInvalidConfig.GenWithStack(
+			"can't create Redis sink with unsupported scheme: %s", scheme)
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrRedisInvalidConfig, err)
+	}
+
+	c.Addrs = strings.Split(sinkURI.Host, ",")
+	if sinkURI.User != nil {
+		c.Username = sinkURI.User.Username()
+		c.Password, _ = sinkURI.User.Password()
+	}
+	if db := strings.Trim(sinkURI.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil || n < 0 {
+			return cerror.ErrRedisInvalidConfig.GenWithStack("invalid database %s", db)
+		}
+		c.DB = n
+	}
+	if urlParameter.Cluster != nil {
+		c.Cluster = *urlParameter.Cluster
+	}
+	if !c.Cluster && len(c.Addrs) > 1 {
+		return cerror.ErrRedisInvalidConfig.GenWithStack(
+			"multiple addresses are only supported by Redis Cluster, please set cluster=true")
+	}
+	if c.Cluster && c.DB != 0 {
+		return cerror.ErrRedisInvalidConfig.GenWithStack(
+			"Redis Cluster only supports database 0, but got %d", c.DB)
+	}
+
+	if urlParameter.WorkerCount != nil {
+		workerCount := *urlParameter.WorkerCount
+		if workerCount <= 0 {
+			return cerror.WrapError(cerror.ErrRedisInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", workerCount))
+		}
+		if workerCount > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", workerCount), zap.Int("override", maxWorkerCount))
+			workerCount = maxWorkerCount
+		}
+		c.WorkerCount = workerCount
+	}
+	if urlParameter.KeyPrefix != nil {
+		c.KeyPrefix = *urlParameter.KeyPrefix
+	}
+	if urlParameter.KeyTTL != nil && len(*urlParameter.KeyTTL) > 0 {
+		ttl, err := time.ParseDuration(*urlParameter.KeyTTL)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrRedisInvalidConfig, err)
+		}
+		// Redis rejects the expirations less than 1ms.
+		if ttl < 0 || (ttl > 0 && ttl < time.Millisecond) {
+			return cerror.WrapError(cerror.ErrRedisInvalidConfig,
+				fmt.Errorf("invalid key-ttl %s, which must be 0 or at least 1ms", ttl))
+		}
+		c.KeyTTL = ttl
+	}
+	if urlParameter.PipelineSize != nil {
+		pipelineSize := *urlParameter.PipelineSize
+		if pipelineSize <= 0 {
+			return cerror.WrapError(cerror.ErrRedisInvalidConfig,
+				fmt.Errorf("invalid pipeline-size %d, which must be greater than 0", pipelineSize))
+		}
+		if pipelineSize > maxPipelineSize {
+			log.Warn("pipeline-size too large",
+				zap.Int("original", pipelineSize), zap.Int("override", maxPipelineSize))
+			pipelineSize = maxPipelineSize
+		}
+		c.PipelineSize = pipelineSize
+	}
+	return nil
+}
+
+// TableKeyPrefix returns the prefix of the keys of a table, which ends
+// with the separator.
+func (c *Config) TableKeyPrefix(schema, table string) string {
+	var builder strings.Builder
+	if c.KeyPrefix != "" {
+		builder.WriteString(c.KeyPrefix)
+		builder.WriteString(":")
+	}
+	builder.WriteString(schema)
+	builder.WriteString(".")
+	builder.WriteString(table)
+	builder.WriteString(":")
+	return builder.String()
+}
+
+// Key returns the key of a row, the values of a composite key are joined
+// by colons.
+func (c *Config) Key(schema, table string, keyValues []string) string {
+	return c.TableKeyPrefix(schema, table) + strings.Join(keyValues, ":")
+}
+
+// Factory is the factory for creating Redis clients.
+type Factory func(ctx context.Context, cfg *Config) (redis.UniversalClient, error)
+
+// CreateClient creates a Redis client with the given config, a cluster
+// client is created if the cluster mode is enabled.
+func CreateClient(ctx context.Context, cfg *Config) (redis.UniversalClient, error) {
+	var client redis.UniversalClient
+	if cfg.Cluster {
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    cfg.Addrs,
+			Username: cfg.Username,
+			Password: cfg.Password,
+			PoolSize: cfg.WorkerCount,
+		})
+	} else {
+		client = redis.NewClient(&redis.Options{
+			Addr:     cfg.Addrs[0],
+			Username: cfg.Username,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+			PoolSize: cfg.WorkerCount,
+		})
+	}
+	if err := client.Ping(ctx).Err(); err != nil {
+		// close client to recycle resources
+		if closeErr := client.Close(); closeErr != nil {
+			log.Warn("close redis client failed", zap.Error(closeErr))
+		}
+		return nil, cerror.ErrRedisConnectionError.Wrap(err).GenWithStack("fail to connect to Redis")
+	}
+	return client, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// ConvertValue converts a column value of a RowChangedEvent to the value
+// encoded in the JSON value of a key.
+func ConvertValue(ft *types.FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch ft.GetType() {
+	case mysql.TypeNewDecimal:
+		if v, ok := value.(string); ok {
+			// Keep the precision of decimals.
+			return json.Number(v), nil
+		}
+	case mysql.TypeJSON:
+		if v, ok := value.(string); ok {
+			return json.RawMessage(v), nil
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		if v, ok := value.(string); ok && strings.HasPrefix(v, "0000-00-00") {
+			return nil, nil
+		}
+	case mysql.TypeEnum:
+		if v, ok := value.(uint64); ok {
+			enum, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return enum.Name, nil
+		}
+	case mysql.TypeSet:
+		if v, ok := value.(uint64); ok {
+			set, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return set.Name, nil
+		}
+	}
+	// Binary values are encoded in base64 by encoding/json.
+	if v, ok := value.([]byte); ok && ft.GetCharset() != charset.CharsetBin {
+		return string(v), nil
+	}
+	return value, nil
+}
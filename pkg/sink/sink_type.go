@@ -72,6 +72,27 @@ const (
 	PulsarScheme = "pulsar"
 	// PulsarSSLScheme indicates the scheme is pulsar+ssl
 	PulsarSSLScheme = "pulsar+ssl"
+	// PostgresScheme indicates the scheme is postgres.
+	PostgresScheme = "postgres"
+	// PostgreSQLScheme is an alias for "postgres".
+	PostgreSQLScheme = "postgresql"
+	// ClickHouseScheme indicates the scheme is ClickHouse.
+	ClickHouseScheme = "clickhouse"
+	// ElasticsearchScheme indicates the scheme is Elasticsearch.
+	ElasticsearchScheme = "elasticsearch"
+	// ESScheme is an alias for "elasticsearch".
+	ESScheme = "es"
+	// RedisScheme indicates the scheme is Redis.
+	RedisScheme = "redis"
+	// IcebergScheme indicates the scheme is Iceberg.
+	IcebergScheme = "iceberg"
+	// CSVScheme indicates the scheme is the CSV files of TiDB Lightning.
+	CSVScheme = "csv"
+	// MongoDBScheme indicates the scheme is MongoDB.
+	MongoDBScheme = "mongodb"
+	// MongoDBSRVScheme indicates the scheme is MongoDB with the seed list
+	// of DNS SRV records, which is used by MongoDB Atlas.
+	MongoDBSRVScheme = "mongodb+srv"
 )
 
 // IsMQScheme returns true if the scheme belong to mq scheme.
@@ -97,6 +118,41 @@ func IsPulsarScheme(scheme string) bool {
 	return scheme == PulsarScheme || scheme == PulsarSSLScheme
 }
 
+// IsPostgresScheme returns true if the scheme belong to PostgreSQL scheme.
+func IsPostgresScheme(scheme string) bool {
+	return scheme == PostgresScheme || scheme == PostgreSQLScheme
+}
+
+// IsClickHouseScheme returns true if the scheme belong to ClickHouse scheme.
+func IsClickHouseScheme(scheme string) bool {
+	return scheme == ClickHouseScheme
+}
+
+// IsElasticsearchScheme returns true if the scheme belong to Elasticsearch scheme.
+func IsElasticsearchScheme(scheme string) bool {
+	return scheme == ElasticsearchScheme || scheme == ESScheme
+}
+
+// IsRedisScheme returns true if the scheme belong to Redis scheme.
+func IsRedisScheme(scheme string) bool {
+	return scheme == RedisScheme
+}
+
+// IsIcebergScheme returns true if the scheme belong to Iceberg scheme.
+func IsIcebergScheme(scheme string) bool {
+	return scheme == IcebergScheme
+}
+
+// IsCSVScheme returns true if the scheme belong to CSV scheme.
+func IsCSVScheme(scheme string) bool {
+	return scheme == CSVScheme
+}
+
+// IsMongoDBScheme returns true if the scheme belong to MongoDB scheme.
+func IsMongoDBScheme(scheme string) bool {
+	return scheme == MongoDBScheme || scheme == MongoDBSRVScheme
+}
+
 // IsBlackHoleScheme returns true if the scheme belong to blackhole scheme.
 func IsBlackHoleScheme(scheme string) bool {
 	return scheme == BlackHoleScheme
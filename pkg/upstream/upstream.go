@@ -76,6 +76,12 @@ type Upstream struct {
 	RegionCache *tikv.RegionCache
 	PDClock     pdutil.Clock
 	GCManager   gc.Manager
+	// VersionDetector caches the version of the upstream TiDB, it's nil in tests.
+	VersionDetector *version.TiDBVersionDetector
+	// PDEndpointPool checks the health of the PD endpoints, it's nil in tests.
+	PDEndpointPool *pdutil.PDEndpointPool
+	// PDAPIClient selects the PD endpoints by PDEndpointPool, it's nil in tests.
+	PDAPIClient pdutil.PDAPIClient
 	// Only use in Close().
 	cancel func()
 	mu     sync.Mutex
@@ -184,6 +190,12 @@ func initUpstream(ctx context.Context, up *Upstream, cfg CaptureTopologyCfg) err
 		return errors.Trace(err)
 	}
 
+	up.VersionDetector = version.NewTiDBVersionDetector(up.PdEndpoints, up.SecurityConfig)
+	if _, err := up.VersionDetector.Detect(ctx); err != nil {
+		// Features are not gated by the version if it's unknown.
+		log.Warn("detect upstream TiDB version failed", zap.Error(err))
+	}
+
 	up.KVStorage, err = kv.CreateTiStore(strings.Join(up.PdEndpoints, ","), up.SecurityConfig)
 	if err != nil {
 		up.err.Store(err)
@@ -203,12 +215,14 @@ func initUpstream(ctx context.Context, up *Upstream, cfg CaptureTopologyCfg) err
 	up.GCManager = gc.NewManager(cfg.GCServiceID, up.PDClient, up.PDClock)
 
 	// Update meta-region label to ensure that meta region isolated from data regions.
-	pc, err := pdutil.NewPDAPIClient(up.PDClient, up.SecurityConfig)
+	pc, pool, err := pdutil.NewPDAPIClientWithEndpointPool(
+		up.PDClient, up.SecurityConfig, up.ID, up.PdEndpoints)
 	if err != nil {
 		log.Error("create pd api client failed", zap.Error(err))
 		return errors.Trace(err)
 	}
-	defer pc.Close()
+	up.PDAPIClient = pc
+	up.PDEndpointPool = pool
 
 	err = pc.UpdateMetaLabel(ctx)
 	if err != nil {
@@ -232,6 +246,11 @@ func initUpstream(ctx context.Context, up *Upstream, cfg CaptureTopologyCfg) err
 		defer up.wg.Done()
 		up.GrpcPool.RecycleConn(ctx)
 	}()
+	up.wg.Add(1)
+	go func() {
+		defer up.wg.Done()
+		_ = up.PDEndpointPool.Run(ctx)
+	}()
 
 	log.Info("upstream initialize successfully", zap.Uint64("upstreamID", up.ID))
 	atomic.StoreInt32(&up.status, normal)
@@ -293,6 +312,9 @@ func (up *Upstream) Close() {
 	if up.PDClock != nil {
 		up.PDClock.Stop()
 	}
+	if up.PDAPIClient != nil {
+		up.PDAPIClient.Close()
+	}
 	if up.session != nil {
 		err := up.session.Close()
 		if err != nil {
@@ -301,10 +323,21 @@ func (up *Upstream) Close() {
 	}
 
 	up.wg.Wait()
+	if up.PDEndpointPool != nil {
+		up.PDEndpointPool.Close()
+	}
 	atomic.StoreInt32(&up.status, closed)
 	log.Info("upstream closed", zap.Uint64("upstreamID", up.ID))
 }
 
+// HealthyPDEndpoints returns the healthy PD endpoints of the upstream.
+func (up *Upstream) HealthyPDEndpoints() []string {
+	if up.PDEndpointPool == nil {
+		return up.PdEndpoints
+	}
+	return up.PDEndpointPool.HealthyEndpoints()
+}
+
 // Error returns the error during init this stream
 func (up *Upstream) Error() error {
 	return up.err.Load()
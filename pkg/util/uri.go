@@ -81,6 +81,17 @@ func MaskSinkURI(uri string) (string, error) {
 	return uriParsed.Redacted(), nil
 }
 
+// MustMaskSinkURI is like MaskSinkURI but panics if the uri cannot be parsed.
+// It should only be used for URIs that are known to be valid, such as the
+// ones that have been parsed before.
+func MustMaskSinkURI(uri string) string {
+	maskedURI, err := MaskSinkURI(uri)
+	if err != nil {
+		log.Panic("failed to mask sink URI", zap.Error(err))
+	}
+	return maskedURI
+}
+
 var sensitiveQueryParameterNames = []string{
 	"password",
 	"passwd",
@@ -139,6 +139,34 @@ func CheckTiCDCVersion(versions map[string]struct{}) error {
 
 // checkPDVersion check PD version.
 func checkPDVersion(ctx context.Context, pdAddr string, credential *security.Credential) error {
+	pdVer, err := getPDVersion(ctx, pdAddr, credential)
+	if err != nil {
+		return err
+	}
+
+	ver, err := semver.NewVersion(SanitizeVersion(pdVer))
+	if err != nil {
+		err = errors.Annotate(err, "invalid PD version")
+		return cerror.WrapError(cerror.ErrNewSemVersion, err)
+	}
+
+	minOrd := ver.Compare(*minPDVersion)
+	if minOrd < 0 {
+		arg := fmt.Sprintf("PD %s is not supported, the minimal compatible version is %s",
+			SanitizeVersion(pdVer), minPDVersion)
+		return cerror.ErrVersionIncompatible.GenWithStackByArgs(arg)
+	}
+	maxOrd := ver.Compare(*maxPDVersion)
+	if maxOrd >= 0 {
+		arg := fmt.Sprintf("PD %s is not supported, only support version less than %s",
+			SanitizeVersion(pdVer), maxPDVersion)
+		return cerror.ErrVersionIncompatible.GenWithStackByArgs(arg)
+	}
+	return nil
+}
+
+// getPDVersion gets the version of the given PD.
+func getPDVersion(ctx context.Context, pdAddr string, credential *security.Credential) (string, error) {
 	// See more: https://github.com/pingcap/pd/blob/v4.0.0-rc.1/server/api/version.go
 	pdVer := struct {
 		Version string `json:"version"`
@@ -146,14 +174,14 @@ func checkPDVersion(ctx context.Context, pdAddr string, credential *security.Cre
 
 	httpClient, err := httputil.NewClient(credential)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	resp, err := httpClient.Get(ctx, fmt.Sprintf("%s/pd/api/v1/version", pdAddr))
 	if err != nil {
-		return cerror.ErrCheckClusterVersionFromPD.GenWithStackByArgs(err)
+		return "", cerror.ErrCheckClusterVersionFromPD.GenWithStackByArgs(err)
 	}
 	defer resp.Body.Close()
 
@@ -165,33 +193,14 @@ func checkPDVersion(ctx context.Context, pdAddr string, credential *security.Cre
 		} else {
 			arg = fmt.Sprintf("%s %s", resp.Status, content)
 		}
-		return cerror.ErrCheckClusterVersionFromPD.GenWithStackByArgs(arg)
+		return "", cerror.ErrCheckClusterVersionFromPD.GenWithStackByArgs(arg)
 	}
 
 	err = json.Unmarshal(content, &pdVer)
 	if err != nil {
-		return cerror.ErrCheckClusterVersionFromPD.GenWithStackByArgs(err)
-	}
-
-	ver, err := semver.NewVersion(SanitizeVersion(pdVer.Version))
-	if err != nil {
-		err = errors.Annotate(err, "invalid PD version")
-		return cerror.WrapError(cerror.ErrNewSemVersion, err)
-	}
-
-	minOrd := ver.Compare(*minPDVersion)
-	if minOrd < 0 {
-		arg := fmt.Sprintf("PD %s is not supported, the minimal compatible version is %s",
-			SanitizeVersion(pdVer.Version), minPDVersion)
-		return cerror.ErrVersionIncompatible.GenWithStackByArgs(arg)
-	}
-	maxOrd := ver.Compare(*maxPDVersion)
-	if maxOrd >= 0 {
-		arg := fmt.Sprintf("PD %s is not supported, only support version less than %s",
-			SanitizeVersion(pdVer.Version), maxPDVersion)
-		return cerror.ErrVersionIncompatible.GenWithStackByArgs(arg)
+		return "", cerror.ErrCheckClusterVersionFromPD.GenWithStackByArgs(err)
 	}
-	return nil
+	return pdVer.Version, nil
 }
 
 // CheckStoreVersion checks whether the given TiKV is compatible with this CDC.
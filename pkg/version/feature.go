@@ -0,0 +1,131 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"go.uber.org/zap"
+)
+
+// Feature is a feature whose availability depends on the TiDB version.
+type Feature string
+
+const (
+	// FeatureWatermark is the watermark event.
+	FeatureWatermark Feature = "watermark"
+	// FeatureAvroWatermark is the watermark event of the avro protocol.
+	FeatureAvroWatermark Feature = "avro-watermark"
+	// FeatureBDRMode is the bidirectional replication mode.
+	FeatureBDRMode Feature = "bdr-mode"
+)
+
+// FeatureMatrix is the minimal TiDB version which supports each feature.
+var FeatureMatrix = map[Feature]*semver.Version{
+	FeatureWatermark:     semver.New("5.0.0"),
+	FeatureAvroWatermark: semver.New("6.0.0"),
+	FeatureBDRMode:       semver.New("6.5.0"),
+}
+
+// IsFeatureSupported returns whether the feature is supported by the given
+// TiDB version. Unknown features are not supported.
+func IsFeatureSupported(feature Feature, ver *semver.Version) bool {
+	minVersion, ok := FeatureMatrix[feature]
+	if !ok {
+		return false
+	}
+	return !ver.LessThan(*minVersion)
+}
+
+// CheckFeature returns an error if the feature is not supported
+// by the given TiDB version.
+func CheckFeature(feature Feature, ver *semver.Version) error {
+	if IsFeatureSupported(feature, ver) {
+		return nil
+	}
+	minVersion, ok := FeatureMatrix[feature]
+	if !ok {
+		return cerror.ErrVersionIncompatible.GenWithStackByArgs(
+			fmt.Sprintf("unknown feature %s", feature))
+	}
+	return cerror.ErrVersionIncompatible.GenWithStackByArgs(
+		fmt.Sprintf("%s requires TiDB version >= %s, but got %s", feature, minVersion, ver))
+}
+
+// TiDBVersionDetector detects the version of the upstream TiDB cluster and
+// caches it. TiDB is released together with PD, so the PD version is used.
+type TiDBVersionDetector struct {
+	pdAddrs    []string
+	credential *security.Credential
+
+	mu      sync.RWMutex
+	version *semver.Version
+}
+
+// NewTiDBVersionDetector creates a TiDBVersionDetector.
+func NewTiDBVersionDetector(
+	pdAddrs []string, credential *security.Credential,
+) *TiDBVersionDetector {
+	return &TiDBVersionDetector{
+		pdAddrs:    pdAddrs,
+		credential: credential,
+	}
+}
+
+// Detect queries the version from PD and caches it.
+func (d *TiDBVersionDetector) Detect(ctx context.Context) (*semver.Version, error) {
+	var err error
+	for _, pdAddr := range d.pdAddrs {
+		var ver string
+		ver, err = getPDVersion(ctx, pdAddr, d.credential)
+		if err != nil {
+			continue
+		}
+		var v *semver.Version
+		v, err = semver.NewVersion(SanitizeVersion(ver))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrNewSemVersion, err)
+		}
+		d.mu.Lock()
+		d.version = v
+		d.mu.Unlock()
+		log.Info("upstream TiDB version detected",
+			zap.String("pdAddr", pdAddr), zap.Stringer("version", v))
+		return v, nil
+	}
+	return nil, err
+}
+
+// Version returns the cached version, it's nil if the version is not detected.
+func (d *TiDBVersionDetector) Version() *semver.Version {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.version
+}
+
+// CheckFeature returns an error if the feature is not supported by
+// the upstream TiDB. Features are allowed if the version is not detected.
+func (d *TiDBVersionDetector) CheckFeature(feature Feature) error {
+	ver := d.Version()
+	if ver == nil {
+		return nil
+	}
+	return CheckFeature(feature, ver)
+}
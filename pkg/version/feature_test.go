@@ -0,0 +1,110 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureMatrix(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		version   string
+		supported map[Feature]bool
+	}{
+		{
+			version: "v4.0.16",
+			supported: map[Feature]bool{
+				FeatureWatermark: false, FeatureAvroWatermark: false, FeatureBDRMode: false,
+			},
+		},
+		{
+			version: "v5.0.0-rc",
+			supported: map[Feature]bool{
+				FeatureWatermark: false, FeatureAvroWatermark: false, FeatureBDRMode: false,
+			},
+		},
+		{
+			version: "v5.4.3",
+			supported: map[Feature]bool{
+				FeatureWatermark: true, FeatureAvroWatermark: false, FeatureBDRMode: false,
+			},
+		},
+		{
+			version: "v6.1.0-20-g1234567",
+			supported: map[Feature]bool{
+				FeatureWatermark: true, FeatureAvroWatermark: true, FeatureBDRMode: false,
+			},
+		},
+		{
+			version: "v7.5.0",
+			supported: map[Feature]bool{
+				FeatureWatermark: true, FeatureAvroWatermark: true, FeatureBDRMode: true,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		ver := semver.New(SanitizeVersion(tc.version))
+		for feature, supported := range tc.supported {
+			require.Equal(t, supported, IsFeatureSupported(feature, ver),
+				"feature: %s, version: %s", feature, tc.version)
+			err := CheckFeature(feature, ver)
+			if supported {
+				require.NoError(t, err)
+			} else {
+				require.True(t, cerror.ErrVersionIncompatible.Equal(err))
+			}
+		}
+	}
+
+	require.False(t, IsFeatureSupported(Feature("unknown"), semver.New("7.5.0")))
+}
+
+func TestTiDBVersionDetector(t *testing.T) {
+	t.Parallel()
+
+	var pdVersion string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/pd/api/v1/version", r.URL.Path)
+		_, _ = fmt.Fprintf(w, `{"version":"%s"}`, pdVersion)
+	}))
+	defer ts.Close()
+
+	detector := NewTiDBVersionDetector([]string{ts.URL}, nil)
+	// Features are not gated before the version is detected.
+	require.Nil(t, detector.Version())
+	require.NoError(t, detector.CheckFeature(FeatureAvroWatermark))
+
+	pdVersion = "v5.4.0"
+	ver, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "5.4.0", ver.String())
+	require.Equal(t, ver, detector.Version())
+	require.NoError(t, detector.CheckFeature(FeatureWatermark))
+	require.True(t, cerror.ErrVersionIncompatible.Equal(detector.CheckFeature(FeatureAvroWatermark)))
+
+	pdVersion = "v6.5.0"
+	_, err = detector.Detect(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, detector.CheckFeature(FeatureAvroWatermark))
+}
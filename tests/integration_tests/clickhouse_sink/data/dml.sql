@@ -0,0 +1,8 @@
+use `clickhouse_sink`;
+
+delete from t where id % 10 = 0;
+update t set v = v + 1, price = price * 2 where id % 10 = 1;
+delete from t where id > 99000;
+insert into t (id, v) values (100001, 1);
+
+create table finish_mark (id int primary key);
@@ -0,0 +1,16 @@
+drop database if exists `clickhouse_sink`;
+create database `clickhouse_sink`;
+use `clickhouse_sink`;
+
+create table t (
+    id bigint primary key,
+    v int not null,
+    price decimal(10, 2),
+    doc json,
+    updated_at datetime(3)
+);
+
+set @@cte_max_recursion_depth = 100000;
+insert into t
+    with recursive seq(n) as (select 1 union all select n + 1 from seq where n < 100000)
+    select n, n % 1000, n / 100, json_object('n', n), '2024-01-01 00:00:00.000' from seq;
@@ -0,0 +1,74 @@
+#!/bin/bash
+
+set -eu
+
+CUR=$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)
+source $CUR/../_utils/test_prepare
+WORK_DIR=$OUT_DIR/$TEST_NAME
+CDC_BINARY=cdc.test
+SINK_TYPE=$1
+
+# The downstream is a ClickHouse (>= 23.2) instance listening on the native port.
+CLICKHOUSE_HOST=${CLICKHOUSE_HOST:-127.0.0.1}
+CLICKHOUSE_PORT=${CLICKHOUSE_PORT:-9000}
+
+# check_clickhouse_result checks the result of a query against the downstream.
+function check_clickhouse_result() {
+	query=$1
+	expected=$2
+	actual=$(clickhouse-client --host $CLICKHOUSE_HOST --port $CLICKHOUSE_PORT --query "$query")
+	if [ "$actual" != "$expected" ]; then
+		echo "query: $query, expected: $expected, actual: $actual"
+		return 1
+	fi
+}
+export -f check_clickhouse_result
+export CLICKHOUSE_HOST CLICKHOUSE_PORT
+
+function run() {
+	# test ClickHouse sink only in mysql pipeline
+	if [ "$SINK_TYPE" != "mysql" ]; then
+		return
+	fi
+	if ! command -v clickhouse-client &>/dev/null; then
+		echo "clickhouse-client is not found, skip $TEST_NAME"
+		return
+	fi
+
+	rm -rf $WORK_DIR && mkdir -p $WORK_DIR
+	start_tidb_cluster --workdir $WORK_DIR
+
+	cd $WORK_DIR
+
+	# record tso before we create tables to skip the system table DDLs
+	start_ts=$(cdc cli tso query --pd=http://$UP_PD_HOST_1:$UP_PD_PORT_1)
+
+	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY
+
+	clickhouse-client --host $CLICKHOUSE_HOST --port $CLICKHOUSE_PORT \
+		--query 'DROP DATABASE IF EXISTS `clickhouse_sink`'
+
+	SINK_URI="clickhouse://default@$CLICKHOUSE_HOST:$CLICKHOUSE_PORT/default?worker-count=4"
+	run_cdc_cli changefeed create --start-ts=$start_ts --sink-uri="$SINK_URI" -c "clickhouse-sink"
+
+	run_sql_file $CUR/data/prepare.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 30 check_clickhouse_result "'SELECT count() FROM clickhouse_sink.t FINAL'" 100000
+
+	run_sql_file $CUR/data/dml.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 30 check_clickhouse_result "'EXISTS TABLE clickhouse_sink.finish_mark'" 1
+
+	# The deleted rows must be gone, and the others must be the latest
+	# version, after the rows are collapsed by FINAL.
+	check_clickhouse_result 'SELECT count(), sum(id), sum(v) FROM clickhouse_sink.t FINAL' \
+		"$(printf '89101\t4410550001\t44559901')"
+	check_clickhouse_result 'SELECT count() FROM clickhouse_sink.t FINAL WHERE id % 10 = 0 OR id BETWEEN 99001 AND 100000' 0
+	check_clickhouse_result "SELECT v, doc, updated_at FROM clickhouse_sink.t FINAL WHERE id = 11" \
+		"$(printf '12\t{"n": 11}\t2024-01-01 00:00:00.000')"
+
+	cleanup_process $CDC_BINARY
+}
+
+trap stop_tidb_cluster EXIT
+run $*
+check_logs $WORK_DIR
+echo "[$(date)] <<<<<< run test case $TEST_NAME success! >>>>>>"
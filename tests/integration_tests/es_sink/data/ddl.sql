@@ -0,0 +1,6 @@
+use `es_sink`;
+
+alter table t add column extra varchar(16);
+truncate table t;
+insert into t (id, title, extra) values (10, 'after truncate', 'new column');
+drop table t2;
@@ -0,0 +1,6 @@
+use `es_sink`;
+
+update t set title = 'TiCDC updated', price = 7.89 where id = 2;
+delete from t where id = 3;
+update t2 set a = 3 where a = 2;
+delete from t2 where a = 1;
@@ -0,0 +1,21 @@
+drop database if exists `es_sink`;
+create database `es_sink`;
+use `es_sink`;
+
+create table t (
+    id int primary key,
+    title varchar(64),
+    content text,
+    price decimal(10, 2),
+    tags json,
+    created_at datetime(3)
+);
+
+insert into t values
+    (1, 'TiDB', 'a distributed SQL database', 1.23, '["sql", "htap"]', '2024-01-01 00:00:00.123'),
+    (2, 'TiCDC', 'change data capture of TiDB', 4.56, '["cdc"]', '2024-01-02 00:00:00.000'),
+    (3, 'TiKV', 'a distributed key-value database', null, null, null);
+
+-- table without primary key
+create table t2 (a int, b varchar(8));
+insert into t2 values (1, 'x'), (2, 'y');
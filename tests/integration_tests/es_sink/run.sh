@@ -0,0 +1,86 @@
+#!/bin/bash
+
+set -eu
+
+CUR=$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)
+source $CUR/../_utils/test_prepare
+WORK_DIR=$OUT_DIR/$TEST_NAME
+CDC_BINARY=cdc.test
+SINK_TYPE=$1
+
+# The downstream is an Elasticsearch 8.x instance with security disabled,
+# e.g. started with `-e xpack.security.enabled=false`.
+ES_HOST=${ES_HOST:-127.0.0.1}
+ES_PORT=${ES_PORT:-9200}
+
+# check_es_count checks the number of documents of an index matching an
+# optional query string.
+function check_es_count() {
+	index=$1
+	expected=$2
+	query=${3:-}
+	curl -s -X POST "http://$ES_HOST:$ES_PORT/$index/_refresh" >/dev/null
+	actual=$(curl -s -G "http://$ES_HOST:$ES_PORT/$index/_count" ${query:+--data-urlencode "q=$query"} |
+		grep -o '"count":[0-9]*' | cut -d: -f2)
+	if [ "$actual" != "$expected" ]; then
+		echo "index: $index, query: $query, expected: $expected, actual: $actual"
+		return 1
+	fi
+}
+export -f check_es_count
+export ES_HOST ES_PORT
+
+function run() {
+	# test Elasticsearch sink only in mysql pipeline
+	if [ "$SINK_TYPE" != "mysql" ]; then
+		return
+	fi
+	if ! curl -s "http://$ES_HOST:$ES_PORT" >/dev/null; then
+		echo "Elasticsearch is not available, skip $TEST_NAME"
+		return
+	fi
+
+	rm -rf $WORK_DIR && mkdir -p $WORK_DIR
+	start_tidb_cluster --workdir $WORK_DIR
+
+	cd $WORK_DIR
+
+	# record tso before we create tables to skip the system table DDLs
+	start_ts=$(cdc cli tso query --pd=http://$UP_PD_HOST_1:$UP_PD_PORT_1)
+
+	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY
+
+	curl -s -X DELETE "http://$ES_HOST:$ES_PORT/es_sink.t,es_sink.t2?ignore_unavailable=true"
+
+	SINK_URI="es://$ES_HOST:$ES_PORT/?batch-size=100&flush-interval=500ms"
+	run_cdc_cli changefeed create --start-ts=$start_ts --sink-uri="$SINK_URI" -c "es-sink"
+
+	# insert
+	run_sql_file $CUR/data/prepare.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_es_count es_sink.t 3
+	ensure 20 check_es_count es_sink.t2 2
+	# the documents are searchable by full-text queries
+	check_es_count es_sink.t 2 'content:distributed'
+	check_es_count es_sink.t 1 'tags:htap AND price:1.23'
+	check_es_count es_sink.t 1 'created_at:[2024-01-02 TO *]'
+
+	# update and delete
+	run_sql_file $CUR/data/dml.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_es_count es_sink.t 2
+	ensure 20 check_es_count es_sink.t 1 "'title:updated AND price:7.89'"
+	ensure 20 check_es_count es_sink.t2 1
+	check_es_count es_sink.t2 1 'a:3 AND b:y'
+
+	# the index is deleted by truncate table and drop table
+	run_sql_file $CUR/data/ddl.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_es_count es_sink.t 1 "'extra:column'"
+	check_es_count es_sink.t 1
+	ensure 20 "curl -s -o /dev/null -w '%{http_code}' http://$ES_HOST:$ES_PORT/es_sink.t2 | grep -q 404"
+
+	cleanup_process $CDC_BINARY
+}
+
+trap stop_tidb_cluster EXIT
+run $*
+check_logs $WORK_DIR
+echo "[$(date)] <<<<<< run test case $TEST_NAME success! >>>>>>"
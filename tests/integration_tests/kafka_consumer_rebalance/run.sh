@@ -0,0 +1,79 @@
+#!/bin/bash
+
+set -eu
+
+CUR=$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)
+source $CUR/../_utils/test_prepare
+WORK_DIR=$OUT_DIR/$TEST_NAME
+CDC_BINARY=cdc.test
+SINK_TYPE=$1
+
+# start_consumer starts a kafka consumer in the consumer group of the test,
+# parameter 1 is the log suffix of it.
+function start_consumer() {
+	cdc_kafka_consumer --log-file $WORK_DIR/cdc_kafka_consumer$1.log --log-level info \
+		--upstream-uri $CONSUMER_URI --consumer-group-id $GROUP_ID \
+		--downstream-uri "mysql://root@127.0.0.1:3306/?safe-mode=true&batch-dml-enable=false" \
+		>>$WORK_DIR/cdc_kafka_consumer_stdout$1.log 2>&1 &
+}
+
+function run() {
+	# test kafka sink only in this case
+	if [ "$SINK_TYPE" != "kafka" ]; then
+		return
+	fi
+
+	rm -rf $WORK_DIR && mkdir -p $WORK_DIR
+	start_tidb_cluster --workdir $WORK_DIR
+	cd $WORK_DIR
+
+	pd_addr="http://$UP_PD_HOST_1:$UP_PD_PORT_1"
+	TOPIC_NAME="ticdc-kafka-consumer-rebalance-test-$RANDOM"
+	GROUP_ID="ticdc-kafka-consumer-rebalance-$RANDOM"
+	SINK_URI="kafka://127.0.0.1:9092/$TOPIC_NAME?protocol=open-protocol&partition-num=3&kafka-version=${KAFKA_VERSION}&max-message-bytes=10485760"
+	CONSUMER_URI="kafka://127.0.0.1:9092/$TOPIC_NAME?protocol=open-protocol&partition-num=3&version=${KAFKA_VERSION}&max-message-bytes=10485760"
+
+	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY --addr "127.0.0.1:8300" --pd $pd_addr
+	cdc cli changefeed create --pd=$pd_addr --sink-uri="$SINK_URI"
+
+	run_sql "CREATE DATABASE kafka_consumer_rebalance;" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	for i in $(seq 1 6); do
+		run_sql "CREATE TABLE kafka_consumer_rebalance.t$i(id int primary key auto_increment, val int);" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	done
+	# The duplicated events are written to the table without a primary key
+	# twice, so they are detected by the sync diff.
+	run_sql "CREATE TABLE kafka_consumer_rebalance.t_no_pk(val int);" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+
+	start_consumer ""
+	for i in $(seq 1 6); do
+		check_table_exists "kafka_consumer_rebalance.t$i" ${DOWN_TIDB_HOST} ${DOWN_TIDB_PORT}
+	done
+
+	# Write the tables continuously, and join another consumer to the group
+	# in the middle of it to trigger a rebalance.
+	for round in $(seq 1 60); do
+		for i in $(seq 1 6); do
+			run_sql "INSERT INTO kafka_consumer_rebalance.t$i(val) VALUES ($round),($round);" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+		done
+		run_sql "INSERT INTO kafka_consumer_rebalance.t_no_pk VALUES ($round);" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+		run_sql "UPDATE kafka_consumer_rebalance.t1 SET val = val + 1;" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+		if [ $round -eq 20 ]; then
+			start_consumer "_2"
+		fi
+	done
+	run_sql "CREATE TABLE kafka_consumer_rebalance.finish_mark(id int primary key);" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+
+	check_table_exists "kafka_consumer_rebalance.finish_mark" ${DOWN_TIDB_HOST} ${DOWN_TIDB_PORT} 200
+	check_sync_diff $WORK_DIR $CUR/conf/diff_config.toml
+
+	# Both consumers take part in the group after the rebalance.
+	grep -q "partitions revoked" $WORK_DIR/cdc_kafka_consumer.log
+	grep -q "partitions assigned" $WORK_DIR/cdc_kafka_consumer_2.log
+
+	cleanup_process $CDC_BINARY
+}
+
+trap stop_tidb_cluster EXIT
+run $*
+check_logs $WORK_DIR
+echo "[$(date)] <<<<<< run test case $TEST_NAME success! >>>>>>"
@@ -0,0 +1,19 @@
+use test;
+
+-- a 10MB BLOB value, which is larger than the max-message-bytes.
+insert into large_value values (1, repeat(x'ab', 10 * 1024 * 1024), 'small text', 'insert');
+-- both of the BLOB and TEXT values are large.
+insert into large_value values (2, repeat(x'cd', 2 * 1024 * 1024), repeat('t', 2 * 1024 * 1024), 'insert');
+insert into large_value values (3, x'ef', 'small text', 'insert');
+
+-- the old values are large too.
+update large_value set note = 'update' where id = 1;
+update large_value set b = x'01', t = 'small text' where id = 2;
+update large_value set b = repeat(x'02', 10 * 1024 * 1024) where id = 3;
+
+delete from large_value where id = 1;
+
+create table finish_mark
+(
+    id int primary key
+);
@@ -0,0 +1,11 @@
+drop database if exists test;
+create database test;
+use test;
+
+create table large_value
+(
+    id   int primary key,
+    b    longblob,
+    t    longtext,
+    note varchar(32)
+);
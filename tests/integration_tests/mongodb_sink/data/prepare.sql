@@ -0,0 +1,11 @@
+drop database if exists `mongodb_sink`;
+create database `mongodb_sink`;
+use `mongodb_sink`;
+
+create table t (
+    id int primary key,
+    name varchar(32),
+    price decimal(10, 2)
+);
+
+insert into t values (1, 'apple', 1.50);
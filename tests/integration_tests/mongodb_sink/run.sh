@@ -0,0 +1,77 @@
+#!/bin/bash
+
+set -eu
+
+CUR=$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)
+source $CUR/../_utils/test_prepare
+WORK_DIR=$OUT_DIR/$TEST_NAME
+CDC_BINARY=cdc.test
+SINK_TYPE=$1
+
+# The downstream is a MongoDB 6.x instance, `mongosh` is required to check it.
+MONGODB_HOST=${MONGODB_HOST:-127.0.0.1}
+MONGODB_PORT=${MONGODB_PORT:-27017}
+
+# check_mongodb_value checks the result of a query in the downstream.
+function check_mongodb_value() {
+	query=$1
+	expected=$2
+	actual=$(mongosh --quiet "mongodb://$MONGODB_HOST:$MONGODB_PORT/mongodb_sink" --eval "$query")
+	if [ "$actual" != "$expected" ]; then
+		echo "query: $query, expected: $expected, actual: $actual"
+		return 1
+	fi
+}
+export -f check_mongodb_value
+export MONGODB_HOST MONGODB_PORT
+
+function run() {
+	# test MongoDB sink only in mysql pipeline
+	if [ "$SINK_TYPE" != "mysql" ]; then
+		return
+	fi
+	if ! command -v mongosh &>/dev/null; then
+		echo "mongosh is not found, skip $TEST_NAME"
+		return
+	fi
+
+	rm -rf $WORK_DIR && mkdir -p $WORK_DIR
+	start_tidb_cluster --workdir $WORK_DIR
+
+	cd $WORK_DIR
+
+	# record tso before we create tables to skip the system table DDLs
+	start_ts=$(cdc cli tso query --pd=http://$UP_PD_HOST_1:$UP_PD_PORT_1)
+
+	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY
+
+	mongosh --quiet "mongodb://$MONGODB_HOST:$MONGODB_PORT/mongodb_sink" --eval "db.dropDatabase()"
+
+	SINK_URI="mongodb://$MONGODB_HOST:$MONGODB_PORT/?worker-count=4&batch-size=64"
+	run_cdc_cli changefeed create --start-ts=$start_ts --sink-uri="$SINK_URI" -c "mongodb-sink"
+
+	# insert
+	run_sql_file $CUR/data/prepare.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_mongodb_value "'db.t.findOne({_id: 1}).name'" "'apple'"
+
+	# update
+	run_sql "UPDATE mongodb_sink.t SET name = 'banana', price = 2.00 WHERE id = 1" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_mongodb_value "'db.t.findOne({_id: 1}).name'" "'banana'"
+
+	# delete
+	run_sql "DELETE FROM mongodb_sink.t WHERE id = 1" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_mongodb_value "'db.t.countDocuments({})'" "'0'"
+
+	# truncate
+	run_sql "INSERT INTO mongodb_sink.t VALUES (2, 'cherry', 3.00)" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_mongodb_value "'db.t.countDocuments({})'" "'1'"
+	run_sql "TRUNCATE TABLE mongodb_sink.t" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_mongodb_value "'db.t.countDocuments({})'" "'0'"
+
+	cleanup_process $CDC_BINARY
+}
+
+trap stop_tidb_cluster EXIT
+run $*
+check_logs $WORK_DIR
+echo "[$(date)] <<<<<< run test case $TEST_NAME success! >>>>>>"
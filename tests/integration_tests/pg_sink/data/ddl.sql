@@ -0,0 +1,12 @@
+use `pg_sink`;
+
+alter table t add column c_new varchar(16) not null default 'new';
+alter table t drop column c_json;
+insert into t (id, c_int, c_new) values (4, 4, 'added');
+
+create table t2 (a int primary key, b varchar(8));
+insert into t2 values (1, 'x'), (2, 'y');
+truncate table t2;
+insert into t2 values (3, 'z');
+
+create table finish_mark (id int primary key);
@@ -0,0 +1,5 @@
+use `pg_sink`;
+
+update t set c_varchar = 'updated', c_int = 10 where id = 1;
+update t set id = 3 where id = 2;
+delete from t where id = 18446744073709551615;
@@ -0,0 +1,21 @@
+drop database if exists `pg_sink`;
+create database `pg_sink`;
+use `pg_sink`;
+
+create table t (
+    id bigint unsigned primary key,
+    c_int int,
+    c_decimal decimal(10, 2),
+    c_datetime datetime(3),
+    c_varchar varchar(32),
+    c_text text,
+    c_blob blob,
+    c_enum enum('a', 'b', 'c'),
+    c_set set('a', 'b', 'c'),
+    c_json json
+);
+
+insert into t values
+    (1, 1, 1.23, '2024-01-01 00:00:00.123', 'a', 'text', x'010203', 'a', 'a,b', '{"k": 1}'),
+    (2, 2, 2.34, '2024-01-02 00:00:00.000', 'b', '中文', null, 'b', 'c', null),
+    (18446744073709551615, 3, 3.45, null, 'c', null, null, 'c', '', '[1, 2]');
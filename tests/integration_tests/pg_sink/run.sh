@@ -0,0 +1,83 @@
+#!/bin/bash
+
+set -eu
+
+CUR=$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)
+source $CUR/../_utils/test_prepare
+WORK_DIR=$OUT_DIR/$TEST_NAME
+CDC_BINARY=cdc.test
+SINK_TYPE=$1
+
+# The downstream is a PostgreSQL 14 instance with database `test`.
+PG_HOST=${PG_HOST:-127.0.0.1}
+PG_PORT=${PG_PORT:-5432}
+PG_USER=${PG_USER:-postgres}
+PG_PASSWORD=${PG_PASSWORD:-postgres}
+
+# check_pg_result checks the result of a query against the downstream.
+function check_pg_result() {
+	query=$1
+	expected=$2
+	actual=$(PGPASSWORD=$PG_PASSWORD psql -h $PG_HOST -p $PG_PORT -U $PG_USER -d test -tAc "$query")
+	if [ "$actual" != "$expected" ]; then
+		echo "query: $query, expected: $expected, actual: $actual"
+		return 1
+	fi
+}
+export -f check_pg_result
+export PG_HOST PG_PORT PG_USER PG_PASSWORD
+
+function run() {
+	# test PostgreSQL sink only in mysql pipeline
+	if [ "$SINK_TYPE" != "mysql" ]; then
+		return
+	fi
+	if ! command -v psql &>/dev/null; then
+		echo "psql is not found, skip $TEST_NAME"
+		return
+	fi
+
+	rm -rf $WORK_DIR && mkdir -p $WORK_DIR
+	start_tidb_cluster --workdir $WORK_DIR
+
+	cd $WORK_DIR
+
+	# record tso before we create tables to skip the system table DDLs
+	start_ts=$(cdc cli tso query --pd=http://$UP_PD_HOST_1:$UP_PD_PORT_1)
+
+	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY
+
+	PGPASSWORD=$PG_PASSWORD psql -h $PG_HOST -p $PG_PORT -U $PG_USER -d test \
+		-c 'DROP SCHEMA IF EXISTS "pg_sink" CASCADE'
+
+	SINK_URI="postgres://$PG_USER:$PG_PASSWORD@$PG_HOST:$PG_PORT/test?sslmode=disable&worker-count=4"
+	run_cdc_cli changefeed create --start-ts=$start_ts --sink-uri="$SINK_URI" -c "pg-sink"
+
+	# insert
+	run_sql_file $CUR/data/prepare.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_pg_result "'SELECT count(*) FROM pg_sink.t'" 3
+	check_pg_result 'SELECT c_decimal, c_datetime, c_text, c_enum, c_set, c_json FROM pg_sink.t WHERE id = 1' \
+		'1.23|2024-01-01 00:00:00.123|text|a|a,b|{"k": 1}'
+	check_pg_result 'SELECT c_text, c_blob IS NULL FROM pg_sink.t WHERE id = 2' '中文|t'
+	check_pg_result 'SELECT c_int FROM pg_sink.t WHERE id = 18446744073709551615' 3
+
+	# update and delete
+	run_sql_file $CUR/data/dml.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_pg_result "'SELECT string_agg(id::text, \$\$,\$\$ ORDER BY id) FROM pg_sink.t'" "1,3"
+	check_pg_result 'SELECT c_varchar, c_int FROM pg_sink.t WHERE id = 1' 'updated|10'
+	check_pg_result 'SELECT c_text FROM pg_sink.t WHERE id = 3' '中文'
+
+	# schema changes
+	run_sql_file $CUR/data/ddl.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_pg_result "'SELECT count(*) FROM pg_sink.finish_mark'" 0
+	check_pg_result 'SELECT string_agg(c_new, $$,$$ ORDER BY id) FROM pg_sink.t' 'new,new,added'
+	check_pg_result "SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pg_sink' AND column_name = 'c_json'" 0
+	check_pg_result 'SELECT a, b FROM pg_sink.t2' '3|z'
+
+	cleanup_process $CDC_BINARY
+}
+
+trap stop_tidb_cluster EXIT
+run $*
+check_logs $WORK_DIR
+echo "[$(date)] <<<<<< run test case $TEST_NAME success! >>>>>>"
@@ -0,0 +1,77 @@
+#!/bin/bash
+
+set -eu
+
+CUR=$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)
+source $CUR/../_utils/test_prepare
+WORK_DIR=$OUT_DIR/$TEST_NAME
+CDC_BINARY=cdc.test
+SINK_TYPE=$1
+
+# The downstream is a standalone Redis instance, `redis-cli` is required to
+# check it.
+REDIS_HOST=${REDIS_HOST:-127.0.0.1}
+REDIS_PORT=${REDIS_PORT:-6379}
+
+# check_redis_value checks the value of a key in the downstream.
+function check_redis_value() {
+	key=$1
+	expected=$2
+	actual=$(redis-cli -h $REDIS_HOST -p $REDIS_PORT --raw GET "$key")
+	if [ "$actual" != "$expected" ]; then
+		echo "key: $key, expected: $expected, actual: $actual"
+		return 1
+	fi
+}
+export -f check_redis_value
+export REDIS_HOST REDIS_PORT
+
+function run() {
+	# test Redis sink only in mysql pipeline
+	if [ "$SINK_TYPE" != "mysql" ]; then
+		return
+	fi
+	if ! command -v redis-cli &>/dev/null; then
+		echo "redis-cli is not found, skip $TEST_NAME"
+		return
+	fi
+
+	rm -rf $WORK_DIR && mkdir -p $WORK_DIR
+	start_tidb_cluster --workdir $WORK_DIR
+
+	cd $WORK_DIR
+
+	# record tso before we create tables to skip the system table DDLs
+	start_ts=$(cdc cli tso query --pd=http://$UP_PD_HOST_1:$UP_PD_PORT_1)
+
+	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY
+
+	redis-cli -h $REDIS_HOST -p $REDIS_PORT DEL "cache:redis_sink.t:1"
+
+	SINK_URI="redis://$REDIS_HOST:$REDIS_PORT/0?key-prefix=cache&key-ttl=1h&pipeline-size=64"
+	run_cdc_cli changefeed create --start-ts=$start_ts --sink-uri="$SINK_URI" -c "redis-sink"
+
+	# insert
+	run_sql_file $CUR/data/prepare.sql ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_redis_value "cache:redis_sink.t:1" "'{\"id\":1,\"name\":\"apple\",\"price\":1.50}'"
+	ttl=$(redis-cli -h $REDIS_HOST -p $REDIS_PORT TTL "cache:redis_sink.t:1")
+	if [ "$ttl" -le 0 ] || [ "$ttl" -gt 3600 ]; then
+		echo "unexpected ttl $ttl"
+		exit 1
+	fi
+
+	# update
+	run_sql "UPDATE redis_sink.t SET name = 'banana', price = 2.00 WHERE id = 1" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_redis_value "cache:redis_sink.t:1" "'{\"id\":1,\"name\":\"banana\",\"price\":2.00}'"
+
+	# delete
+	run_sql "DELETE FROM redis_sink.t WHERE id = 1" ${UP_TIDB_HOST} ${UP_TIDB_PORT}
+	ensure 20 check_redis_value "cache:redis_sink.t:1" "''"
+
+	cleanup_process $CDC_BINARY
+}
+
+trap stop_tidb_cluster EXIT
+run $*
+check_logs $WORK_DIR
+echo "[$(date)] <<<<<< run test case $TEST_NAME success! >>>>>>"
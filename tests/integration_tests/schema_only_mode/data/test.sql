@@ -0,0 +1,33 @@
+DROP DATABASE IF EXISTS `schema_only_mode`;
+
+CREATE DATABASE `schema_only_mode`;
+
+USE `schema_only_mode`;
+
+CREATE TABLE t1 (
+  id INT,
+  name varchar(128),
+  PRIMARY KEY (id)
+);
+
+INSERT INTO t1 VALUES (1, 'a'), (2, 'b'), (3, 'c');
+
+ALTER TABLE t1 ADD COLUMN age INT;
+
+UPDATE t1 SET age = 10 WHERE id = 1;
+
+DELETE FROM t1 WHERE id = 2;
+
+CREATE TABLE t2 (
+  id INT,
+  val varchar(64),
+  PRIMARY KEY (id)
+);
+
+INSERT INTO t2 VALUES (1, 'x'), (2, 'y');
+
+CREATE INDEX idx_val ON t2 (val);
+
+CREATE TABLE finish_mark (
+  id INT PRIMARY KEY
+);